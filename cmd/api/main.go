@@ -6,6 +6,7 @@ import (
     "os"
     "os/signal"
     "syscall"
+    "time"
 
     "github.com/gohex/gohex/internal/infrastructure/bootstrap"
 )
@@ -20,7 +21,7 @@ func main() {
     signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
     // 创建应用实例
-    app, err := bootstrap.NewApplication("configs/config.yaml")
+    app, err := bootstrap.NewApplication("config/config.yaml")
     if err != nil {
         log.Fatalf("Failed to create application: %v", err)
     }
@@ -38,7 +39,11 @@ func main() {
     log.Println("Received shutdown signal")
 
     // 优雅关闭
-    shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), app.Config().ShutdownTimeout)
+    shutdownTimeout := app.Config().HTTP.ShutdownTimeout
+    if shutdownTimeout <= 0 {
+        shutdownTimeout = 15 * time.Second
+    }
+    shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), shutdownTimeout)
     defer shutdownCancel()
 
     if err := app.Stop(shutdownCtx); err != nil {
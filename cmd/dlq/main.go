@@ -0,0 +1,142 @@
+package main
+
+import (
+    "context"
+    "flag"
+    "fmt"
+    "log"
+    "os"
+    "os/signal"
+    "syscall"
+
+    "github.com/Shopify/sarama"
+    "github.com/gohex/gohex/internal/infrastructure/adapter/secondary/message/kafka"
+    "github.com/gohex/gohex/internal/infrastructure/config"
+)
+
+const usage = `Usage: dlq [-config path] <command> [flags]
+
+Commands:
+  list     列出死信消息
+  replay   将死信消息重新发送到原始主题
+  purge    清空死信主题
+`
+
+func main() {
+    configPath := flag.String("config", "configs/config.yaml", "Path to config file")
+    flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+    flag.Parse()
+
+    if flag.NArg() == 0 {
+        flag.Usage()
+        os.Exit(2)
+    }
+
+    // 加载配置
+    cfg, err := config.Load(*configPath)
+    if err != nil {
+        log.Fatalf("Failed to load config: %v", err)
+    }
+
+    ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+    defer cancel()
+
+    // 连接 Kafka
+    saramaCfg := sarama.NewConfig()
+    saramaCfg.Producer.Return.Successes = true
+    client, err := sarama.NewClient(cfg.Kafka.Brokers, saramaCfg)
+    if err != nil {
+        log.Fatalf("Failed to connect to kafka: %v", err)
+    }
+    defer client.Close()
+
+    producer, err := sarama.NewSyncProducerFromClient(client)
+    if err != nil {
+        log.Fatalf("Failed to create producer: %v", err)
+    }
+    defer producer.Close()
+
+    clusterAdmin, err := sarama.NewClusterAdminFromClient(client)
+    if err != nil {
+        log.Fatalf("Failed to create cluster admin: %v", err)
+    }
+
+    admin := kafka.NewDeadLetterAdmin(client, producer, clusterAdmin, cfg.Kafka.DeadLetter())
+
+    switch cmd, args := flag.Arg(0), flag.Args()[1:]; cmd {
+    case "list":
+        err = list(ctx, admin, args)
+    case "replay":
+        err = replay(ctx, admin, args)
+    case "purge":
+        err = purge(ctx, admin, cfg.Kafka.DeadLetter(), args)
+    default:
+        flag.Usage()
+        os.Exit(2)
+    }
+    if err != nil {
+        log.Fatalf("%s failed: %v", flag.Arg(0), err)
+    }
+}
+
+func list(ctx context.Context, admin *kafka.DeadLetterAdmin, args []string) error {
+    fs := flag.NewFlagSet("list", flag.ExitOnError)
+    limit := fs.Int("limit", 100, "Maximum number of messages to show, 0 for all")
+    showPayload := fs.Bool("payload", false, "Print message payload")
+    fs.Parse(args)
+
+    letters, err := admin.List(ctx, *limit)
+    if err != nil {
+        return err
+    }
+
+    for _, l := range letters {
+        fmt.Printf("%d/%d\t%s\t%s\tgroup=%s handlers=%s attempts=%d failed_at=%s\n\terror: %s\n",
+            l.Partition, l.Offset, l.EventType, l.OriginalTopic,
+            l.ConsumerGroup, l.FailedHandlers, l.Attempts, l.FailedAt, l.Error)
+        if *showPayload {
+            fmt.Printf("\tpayload: %s\n", l.Payload)
+        }
+    }
+    fmt.Printf("%d message(s)\n", len(letters))
+    return nil
+}
+
+func replay(ctx context.Context, admin *kafka.DeadLetterAdmin, args []string) error {
+    fs := flag.NewFlagSet("replay", flag.ExitOnError)
+    partition := fs.Int("partition", -1, "Only replay messages from this partition")
+    offset := fs.Int64("offset", -1, "Only replay the message at this offset, requires -partition")
+    eventType := fs.String("type", "", "Only replay messages of this event type")
+    fs.Parse(args)
+
+    if *offset >= 0 && *partition < 0 {
+        return fmt.Errorf("-offset requires -partition")
+    }
+
+    n, err := admin.Replay(ctx, func(l kafka.DeadLetter) bool {
+        if *partition >= 0 && l.Partition != int32(*partition) {
+            return false
+        }
+        if *offset >= 0 && l.Offset != *offset {
+            return false
+        }
+        return *eventType == "" || l.EventType == *eventType
+    })
+    fmt.Printf("%d message(s) replayed\n", n)
+    return err
+}
+
+func purge(ctx context.Context, admin *kafka.DeadLetterAdmin, topic string, args []string) error {
+    fs := flag.NewFlagSet("purge", flag.ExitOnError)
+    yes := fs.Bool("yes", false, "Confirm deleting all messages in the dead letter topic")
+    fs.Parse(args)
+
+    if !*yes {
+        return fmt.Errorf("refusing to purge %s without -yes", topic)
+    }
+    if err := admin.Purge(ctx); err != nil {
+        return err
+    }
+    fmt.Printf("%s purged\n", topic)
+    return nil
+}
@@ -3,11 +3,16 @@ package command
 import (
 	"context"
 	"time"
+	"github.com/gohex/gohex/internal/domain/aggregate"
 	"github.com/gohex/gohex/internal/domain/vo"
 	"github.com/gohex/gohex/pkg/errors"
-	"github.com/gohex/gohex/internal/application/port"
+	"github.com/gohex/gohex/internal/application/dto"
+	"github.com/gohex/gohex/internal/application/port/output"
 )
 
+// accountLockDuration 连续登录失败锁定账户后自动解锁的时间
+const accountLockDuration = 30 * time.Minute
+
 // LoginCommand 登录命令
 type LoginCommand struct {
 	Email     string `validate:"required,email"`
@@ -16,18 +21,46 @@ type LoginCommand struct {
 	UserAgent string
 }
 
+// CredentialCommand 登录结果包含令牌，不保存到幂等存储
+func (*LoginCommand) CredentialCommand() {}
+
 type LoginHandler struct {
-	userRepo   port.UserRepository
-	tokenSvc   port.TokenService
-	eventStore port.EventStore
-	cache      port.Cache
-	logger     Logger
-	metrics    MetricsReporter
+	userRepo   output.UserRepository
+	tokenSvc   output.TokenService
+	eventStore output.EventStore
+	eventBus   output.EventBus
+	uow        output.UnitOfWork
+	cache      output.Cache
+	scheduler  output.CommandScheduler
+	logger     output.Logger
+	metrics    output.MetricsReporter
 }
 
-func (h *LoginHandler) Handle(ctx context.Context, cmd interface{}) (interface{}, error) {
-	loginCmd := cmd.(*LoginCommand)
+func NewLoginHandler(
+	userRepo output.UserRepository,
+	tokenSvc output.TokenService,
+	eventStore output.EventStore,
+	eventBus output.EventBus,
+	uow output.UnitOfWork,
+	cache output.Cache,
+	scheduler output.CommandScheduler,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *LoginHandler {
+	return &LoginHandler{
+		userRepo:   userRepo,
+		tokenSvc:   tokenSvc,
+		eventStore: eventStore,
+		eventBus:   eventBus,
+		uow:        uow,
+		cache:      cache,
+		scheduler:  scheduler,
+		logger:     logger,
+		metrics:    metrics,
+	}
+}
 
+func (h *LoginHandler) Handle(ctx context.Context, loginCmd *LoginCommand) (*dto.LoginResponseDTO, error) {
 	// 1. 获取用户
 	email, err := vo.NewEmail(loginCmd.Email)
 	if err != nil {
@@ -42,34 +75,47 @@ func (h *LoginHandler) Handle(ctx context.Context, cmd interface{}) (interface{}
 		return nil, err
 	}
 
-	// 2. 验证密码
+	// 2. 锁定已到期时先解锁，调度器未启用或解锁计划失败时锁定不会变成永久锁定
+	if user.LockExpired(time.Now()) {
+		if user, err = h.unlockExpired(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+
+	// 3. 验证密码
 	if err := user.ValidatePassword(loginCmd.Password); err != nil {
 		// 记录失败次数
 		h.recordLoginFailure(ctx, user.ID())
 		return nil, errors.ErrInvalidCredentials
 	}
 
-	// 3. 检查账户状态
+	// 4. 检查账户状态
 	if !user.Status().IsActive() {
 		return nil, errors.ErrAccountLocked
 	}
 
-	// 4. 生成令牌
+	// 5. 生成令牌
 	token, expiresAt, err := h.tokenSvc.GenerateToken(user)
 	if err != nil {
 		return nil, err
 	}
 
-	// 5. 记录登录事件
+	// 6. 记录登录事件，用户与事件在同一事务中保存，用户版本与事件流保持一致
 	user.RecordLogin(loginCmd.IP, loginCmd.UserAgent)
-	if err := h.eventStore.SaveEvents(ctx, user.ID(), user.Events(), user.Version()); err != nil {
+	err = h.uow.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := h.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		return h.eventStore.SaveEvents(ctx, user.ID(), user.Events(), user.OriginalVersion())
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	// 6. 清除失败计数
+	// 7. 清除失败计数
 	h.clearLoginFailures(ctx, user.ID())
 
-	return &dto.LoginResponse{
+	return &dto.LoginResponseDTO{
 		AccessToken:  token,
 		TokenType:    "Bearer",
 		ExpiresAt:    expiresAt,
@@ -88,6 +134,33 @@ func (h *LoginHandler) recordLoginFailure(ctx context.Context, userID string) {
 	}
 }
 
+// unlockExpired 解除已到期的锁定并重新加载用户，失败计数随之清零，与计划解锁一致
+func (h *LoginHandler) unlockExpired(ctx context.Context, user *aggregate.User) (*aggregate.User, error) {
+	if err := user.Unlock(); err != nil {
+		return nil, err
+	}
+
+	err := h.uow.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := h.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		return h.eventStore.SaveEvents(ctx, user.ID(), user.Events(), user.OriginalVersion())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	h.clearLoginFailures(ctx, user.ID())
+	for _, evt := range user.Events() {
+		if err := h.eventBus.Publish(ctx, evt); err != nil {
+			h.logger.Error("failed to publish account unlocked event", "user_id", user.ID(), "error", err)
+		}
+	}
+
+	h.logger.Info("expired account lock lifted on login", "user_id", user.ID())
+	return h.userRepo.FindByID(ctx, user.ID())
+}
+
 func (h *LoginHandler) clearLoginFailures(ctx context.Context, userID string) {
 	h.cache.Delete(ctx, "login_failures:"+userID)
 }
@@ -99,14 +172,116 @@ func (h *LoginHandler) lockAccount(ctx context.Context, userID string) {
 		return
 	}
 
-	if err := user.Lock(); err != nil {
+	// 只锁定正常账户；已锁定时继续失败不再重复锁定和安排解锁，
+	// 停用或冻结的账户锁定后会被到期解锁恢复为正常
+	if user.Status() != vo.StatusActive {
+		return
+	}
+
+	unlockAt := time.Now().Add(accountLockDuration)
+	if err := user.Lock("too many failed login attempts", unlockAt); err != nil {
 		h.logger.Error("failed to lock account", "error", err)
 		return
 	}
 
-	if err := h.userRepo.Update(ctx, user); err != nil {
-		h.logger.Error("failed to save locked account", "error", err)
+	err = h.uow.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := h.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		return h.eventStore.SaveEvents(ctx, user.ID(), user.Events(), user.OriginalVersion())
+	})
+	if err != nil {
+		h.logger.Error("failed to save locked account", "user_id", userID, "error", err)
+		return
 	}
+
+	// 提交后发布锁定事件，缓存失效和 webhook 等订阅方据此更新
+	for _, evt := range user.Events() {
+		if err := h.eventBus.Publish(ctx, evt); err != nil {
+			h.logger.Error("failed to publish account locked event", "user_id", userID, "error", err)
+		}
+	}
+
+	if _, err := h.scheduler.Schedule(ctx, &UnlockUserCommand{UserID: userID}, unlockAt); err != nil {
+		h.logger.Error("failed to schedule account unlock", "user_id", userID, "error", err)
+	}
+}
+
+// UnlockUserCommand 解锁因登录失败被锁定的账户，锁定时由调度器安排在锁定期满后执行
+type UnlockUserCommand struct {
+	UserID string `validate:"required"`
+}
+
+type UnlockUserHandler struct {
+	userRepo   output.UserRepository
+	eventStore output.EventStore
+	eventBus   output.EventBus
+	uow        output.UnitOfWork
+	cache      output.Cache
+	logger     output.Logger
+	metrics    output.MetricsReporter
+}
+
+func NewUnlockUserHandler(
+	userRepo output.UserRepository,
+	eventStore output.EventStore,
+	eventBus output.EventBus,
+	uow output.UnitOfWork,
+	cache output.Cache,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *UnlockUserHandler {
+	return &UnlockUserHandler{
+		userRepo:   userRepo,
+		eventStore: eventStore,
+		eventBus:   eventBus,
+		uow:        uow,
+		cache:      cache,
+		logger:     logger,
+		metrics:    metrics,
+	}
+}
+
+func (h *UnlockUserHandler) Handle(ctx context.Context, unlockCmd *UnlockUserCommand) (struct{}, error) {
+	var user *aggregate.User
+	unlocked := false
+	err := h.uow.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		user, err = h.userRepo.FindByID(ctx, unlockCmd.UserID)
+		if err != nil {
+			return err
+		}
+
+		// 锁定期间已被管理员解锁、冻结、停用或删除时不做处理，
+		// 管理员冻结会清除锁定期限，避免到期后被自动解除
+		if !user.IsLockedOut() {
+			return nil
+		}
+
+		if err := user.Unlock(); err != nil {
+			return err
+		}
+		if err := h.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+
+		// 保存 UserUnlockedEvent，订阅方据此得知账户已恢复
+		unlocked = true
+		return h.eventStore.SaveEvents(ctx, user.ID(), user.Events(), user.OriginalVersion())
+	})
+	if err != nil || !unlocked {
+		return struct{}{}, err
+	}
+
+	h.cache.Delete(ctx, "login_failures:"+unlockCmd.UserID)
+	for _, evt := range user.Events() {
+		if err := h.eventBus.Publish(ctx, evt); err != nil {
+			h.logger.Error("failed to publish account unlocked event", "user_id", unlockCmd.UserID, "error", err)
+		}
+	}
+	h.logger.Info("account unlocked", "user_id", unlockCmd.UserID)
+	h.metrics.IncrementCounter("account_unlocked")
+	return struct{}{}, nil
 }
 
 // LogoutCommand 登出命令
@@ -116,19 +291,25 @@ type LogoutCommand struct {
 }
 
 type LogoutHandler struct {
-	tokenSvc port.TokenService
-	logger   Logger
-	metrics  MetricsReporter
+	tokenSvc output.TokenService
+	logger   output.Logger
+	metrics  output.MetricsReporter
 }
 
-func (h *LogoutHandler) Handle(ctx context.Context, cmd interface{}) (interface{}, error) {
-	logoutCmd := cmd.(*LogoutCommand)
+func NewLogoutHandler(tokenSvc output.TokenService, logger output.Logger, metrics output.MetricsReporter) *LogoutHandler {
+	return &LogoutHandler{
+		tokenSvc: tokenSvc,
+		logger:   logger,
+		metrics:  metrics,
+	}
+}
 
+func (h *LogoutHandler) Handle(ctx context.Context, logoutCmd *LogoutCommand) (struct{}, error) {
 	// 1. 吊销令牌
 	if err := h.tokenSvc.RevokeToken(ctx, logoutCmd.Token); err != nil {
 		h.logger.Error("failed to revoke token", "error", err)
-		return nil, err
+		return struct{}{}, err
 	}
 
-	return nil, nil
+	return struct{}{}, nil
 } 
\ No newline at end of file
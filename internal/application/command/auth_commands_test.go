@@ -0,0 +1,185 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/aggregate"
+	"github.com/gohex/gohex/internal/domain/event"
+	"github.com/gohex/gohex/internal/domain/vo"
+	"github.com/gohex/gohex/internal/infrastructure/adapter/secondary/cache/memory"
+	"github.com/gohex/gohex/internal/testutil"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+// scheduledCommand 记录一次 Schedule 调用
+type scheduledCommand struct {
+	command interface{}
+	runAt   time.Time
+}
+
+// commandScheduler 记录计划的命令
+type commandScheduler struct {
+	output.CommandScheduler
+	scheduled []scheduledCommand
+}
+
+func (s *commandScheduler) Schedule(ctx context.Context, cmd interface{}, runAt time.Time) (string, error) {
+	s.scheduled = append(s.scheduled, scheduledCommand{command: cmd, runAt: runAt})
+	return "schedule-1", nil
+}
+
+func TestLoginHandler_LockoutSchedulesUnlock(t *testing.T) {
+	users := testutil.NewUserRepository(testutil.NewUser("alice", vo.StatusActive, vo.RoleUser))
+	events := testutil.NewEventStore()
+	bus := &testutil.EventBus{}
+	scheduler := &commandScheduler{}
+	cache := memory.NewCache(time.Minute, testutil.NopLogger{}, testutil.NewMetrics())
+	h := NewLoginHandler(users, testutil.NewTokenService(), events, bus, testutil.UnitOfWork{}, cache, scheduler,
+		testutil.NopLogger{}, testutil.NewMetrics())
+
+	// 第 5 次失败锁定账户，之后的失败不再重复安排解锁
+	for i := 0; i < 6; i++ {
+		_, err := h.Handle(context.Background(), &LoginCommand{Email: "alice@example.com", Password: "wrong"})
+		assert.ErrorIs(t, err, errors.ErrInvalidCredentials)
+	}
+
+	user, err := users.FindByID(context.Background(), "alice")
+	require.NoError(t, err)
+	assert.Equal(t, vo.StatusSuspended, user.Status())
+	assert.Equal(t, []string{event.UserStatusChanged, event.UserLocked}, events.Types("alice"))
+	// 锁定事件发布后缓存失效处理器才能清除旧的用户详情
+	assert.Equal(t, []string{event.UserStatusChanged, event.UserLocked}, typesOf(bus.Published))
+
+	require.Len(t, scheduler.scheduled, 1)
+	assert.Equal(t, &UnlockUserCommand{UserID: "alice"}, scheduler.scheduled[0].command)
+	assert.WithinDuration(t, time.Now().Add(accountLockDuration), scheduler.scheduled[0].runAt, time.Minute)
+}
+
+func TestLoginHandler_LockoutKeepsDeactivatedAccount(t *testing.T) {
+	ctx := context.Background()
+	users := testutil.NewUserRepository(testutil.NewUser("alice", vo.StatusInactive, vo.RoleUser))
+	events := testutil.NewEventStore()
+	scheduler := &commandScheduler{}
+	cache := memory.NewCache(time.Minute, testutil.NopLogger{}, testutil.NewMetrics())
+	h := NewLoginHandler(users, testutil.NewTokenService(), events, &testutil.EventBus{}, testutil.UnitOfWork{}, cache, scheduler,
+		testutil.NopLogger{}, testutil.NewMetrics())
+
+	for i := 0; i < 5; i++ {
+		_, err := h.Handle(ctx, &LoginCommand{Email: "alice@example.com", Password: "wrong"})
+		assert.Error(t, err)
+	}
+	assert.Empty(t, scheduler.scheduled)
+
+	// 即使有遗留的解锁计划，停用的账户也不会被恢复
+	unlock := NewUnlockUserHandler(users, events, &testutil.EventBus{}, testutil.UnitOfWork{}, cache,
+		testutil.NopLogger{}, testutil.NewMetrics())
+	_, err := unlock.Handle(ctx, &UnlockUserCommand{UserID: "alice"})
+	require.NoError(t, err)
+
+	user, err := users.FindByID(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, vo.StatusInactive, user.Status())
+	assert.Empty(t, events.Types("alice"))
+	assert.ErrorIs(t, user.Lock("too many failed login attempts", time.Now().Add(accountLockDuration)),
+		errors.ErrInvalidStatusTransition)
+}
+
+func TestLoginHandler_LiftsExpiredLockout(t *testing.T) {
+	ctx := context.Background()
+	password, err := vo.NewPassword("Secret123!")
+	require.NoError(t, err)
+	alice := testutil.NewUser("alice", vo.StatusActive, vo.RoleUser)
+	// 调度器未启用时解锁计划不会执行，锁定到期后登录时解锁
+	lockedUntil := time.Now().Add(-time.Minute)
+	users := testutil.NewUserRepository(aggregate.ReconstituteUser("alice", alice.Email(), password, alice.Profile(),
+		vo.StatusSuspended, alice.Roles(), alice.CreatedAt(), alice.UpdatedAt(), nil, &lockedUntil, nil, 1))
+	events := testutil.NewEventStore()
+	bus := &testutil.EventBus{}
+	cache := memory.NewCache(time.Minute, testutil.NopLogger{}, testutil.NewMetrics())
+	require.NoError(t, cache.Set(ctx, "login_failures:alice", int64(5), time.Hour))
+	h := NewLoginHandler(users, testutil.NewTokenService(), events, bus, testutil.UnitOfWork{}, cache, &commandScheduler{},
+		testutil.NopLogger{}, testutil.NewMetrics())
+
+	_, err = h.Handle(ctx, &LoginCommand{Email: "alice@example.com", Password: "Secret123!"})
+	require.NoError(t, err)
+
+	user, err := users.FindByID(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, vo.StatusActive, user.Status())
+	assert.Nil(t, user.LockedUntil())
+	assert.Equal(t, []string{event.UserStatusChanged, event.UserUnlocked, event.UserLoggedIn}, events.Types("alice"))
+	assert.Equal(t, []string{event.UserStatusChanged, event.UserUnlocked}, typesOf(bus.Published))
+}
+
+func TestLoginHandler_LoginTwice(t *testing.T) {
+	password, err := vo.NewPassword("Secret123!")
+	require.NoError(t, err)
+	alice := testutil.NewUser("alice", vo.StatusActive, vo.RoleUser)
+	users := testutil.NewUserRepository(aggregate.ReconstituteUser("alice", alice.Email(), password, alice.Profile(),
+		vo.StatusActive, alice.Roles(), alice.CreatedAt(), alice.UpdatedAt(), nil, nil, nil, 1))
+	events := testutil.NewEventStore()
+	cache := memory.NewCache(time.Minute, testutil.NopLogger{}, testutil.NewMetrics())
+	h := NewLoginHandler(users, testutil.NewTokenService(), events, &testutil.EventBus{}, testutil.UnitOfWork{}, cache,
+		&commandScheduler{},
+		testutil.NopLogger{}, testutil.NewMetrics())
+
+	// 每次登录都保存用户，用户版本跟上事件流，下一次登录不会冲突
+	for i := 0; i < 2; i++ {
+		_, err := h.Handle(context.Background(), &LoginCommand{Email: "alice@example.com", Password: "Secret123!"})
+		require.NoError(t, err)
+	}
+
+	user, err := users.FindByID(context.Background(), "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 3, user.Version())
+	assert.Equal(t, []string{event.UserLoggedIn, event.UserLoggedIn}, events.Types("alice"))
+}
+
+func TestUnlockUserHandler(t *testing.T) {
+	lockedUntil := time.Now().Add(accountLockDuration)
+	tests := []struct {
+		name        string
+		status      vo.UserStatus
+		lockedUntil *time.Time
+		wantStatus  vo.UserStatus
+		wantEvents  []string
+	}{
+		{name: "unlocks locked account", status: vo.StatusSuspended, lockedUntil: &lockedUntil, wantStatus: vo.StatusActive,
+			wantEvents: []string{event.UserStatusChanged, event.UserUnlocked}},
+		{name: "keeps account suspended by admin", status: vo.StatusSuspended, wantStatus: vo.StatusSuspended},
+		{name: "keeps account unlocked by admin", status: vo.StatusActive, wantStatus: vo.StatusActive},
+		{name: "keeps deactivated account", status: vo.StatusInactive, wantStatus: vo.StatusInactive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			alice := testutil.NewUser("alice", tt.status, vo.RoleUser)
+			users := testutil.NewUserRepository(aggregate.ReconstituteUser("alice", alice.Email(), alice.Password(), alice.Profile(),
+				tt.status, alice.Roles(), alice.CreatedAt(), alice.UpdatedAt(), nil, tt.lockedUntil, nil, 1))
+			events := testutil.NewEventStore()
+			cache := memory.NewCache(time.Minute, testutil.NopLogger{}, testutil.NewMetrics())
+			require.NoError(t, cache.Set(ctx, "login_failures:alice", int64(5), time.Hour))
+			bus := &testutil.EventBus{}
+			h := NewUnlockUserHandler(users, events, bus, testutil.UnitOfWork{}, cache, testutil.NopLogger{}, testutil.NewMetrics())
+
+			_, err := h.Handle(ctx, &UnlockUserCommand{UserID: "alice"})
+			require.NoError(t, err)
+
+			user, err := users.FindByID(ctx, "alice")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, user.Status())
+			assert.Equal(t, tt.wantEvents, events.Types("alice"))
+			assert.Equal(t, tt.wantEvents, typesOf(bus.Published))
+
+			failures, _ := cache.Get(ctx, "login_failures:alice")
+			assert.Equal(t, tt.wantEvents == nil, failures != nil)
+		})
+	}
+}
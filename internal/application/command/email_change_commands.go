@@ -0,0 +1,320 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/aggregate"
+	"github.com/gohex/gohex/internal/domain/vo"
+	"github.com/gohex/gohex/pkg/actor"
+	"github.com/gohex/gohex/pkg/crypto"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+// emailChangeTokenBytes 确认和取消令牌的随机字节数
+const emailChangeTokenBytes = 32
+
+// RequestEmailChangeCommand 申请变更邮箱命令
+type RequestEmailChangeCommand struct {
+	UserID   string `validate:"required"`
+	NewEmail string `validate:"required,email"`
+}
+
+// RequestEmailChangeResult 待确认变更的过期时间
+type RequestEmailChangeResult struct {
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type RequestEmailChangeHandler struct {
+	userRepo   output.UserRepository
+	changeRepo output.EmailChangeRepository
+	emailSvc   output.EmailService
+	ttl        time.Duration
+	logger     output.Logger
+	metrics    output.MetricsReporter
+}
+
+func NewRequestEmailChangeHandler(
+	userRepo output.UserRepository,
+	changeRepo output.EmailChangeRepository,
+	emailSvc output.EmailService,
+	ttl time.Duration,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *RequestEmailChangeHandler {
+	return &RequestEmailChangeHandler{
+		userRepo:   userRepo,
+		changeRepo: changeRepo,
+		emailSvc:   emailSvc,
+		ttl:        ttl,
+		logger:     logger,
+		metrics:    metrics,
+	}
+}
+
+func (h *RequestEmailChangeHandler) Handle(ctx context.Context, changeCmd *RequestEmailChangeCommand) (*RequestEmailChangeResult, error) {
+	// 模拟会话中禁止变更邮箱
+	if actor.IsImpersonated(ctx) {
+		return nil, errors.ErrImpersonationForbidden
+	}
+
+	// 1. 校验新邮箱
+	newEmail, err := vo.NewEmail(changeCmd.NewEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := h.userRepo.FindByID(ctx, changeCmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsDeleted() {
+		return nil, errors.ErrInactiveUser
+	}
+	if user.Email().String() == newEmail.String() {
+		return nil, errors.ErrEmailUnchanged
+	}
+
+	exists, err := h.userRepo.ExistsByEmail(ctx, newEmail)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.ErrEmailAlreadyExists
+	}
+
+	// 2. 生成一次性令牌，只保存摘要
+	confirmToken, err := crypto.RandomToken(emailChangeTokenBytes)
+	if err != nil {
+		return nil, err
+	}
+	cancelToken, err := crypto.RandomToken(emailChangeTokenBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	change := &output.PendingEmailChange{
+		ID:               uuid.New().String(),
+		UserID:           user.ID(),
+		OldEmail:         user.Email().String(),
+		NewEmail:         newEmail.String(),
+		ConfirmTokenHash: crypto.HashToken(confirmToken),
+		CancelTokenHash:  crypto.HashToken(cancelToken),
+		RequestedAt:      now,
+		ExpiresAt:        now.Add(h.ttl),
+	}
+
+	// 3. 保存请求，覆盖该用户之前未确认的请求
+	if err := h.changeRepo.Save(ctx, change); err != nil {
+		return nil, err
+	}
+
+	// 4. 向新邮箱发送确认链接，向旧邮箱发送带取消链接的通知
+	if err := h.emailSvc.SendEmailChangeConfirmation(change.NewEmail, confirmToken); err != nil {
+		h.logger.Error("failed to send email change confirmation", "user_id", user.ID(), "error", err)
+		return nil, err
+	}
+	if err := h.emailSvc.SendEmailChangeNotice(change.OldEmail, change.NewEmail, cancelToken); err != nil {
+		h.logger.Error("failed to send email change notice", "user_id", user.ID(), "error", err)
+		return nil, err
+	}
+
+	h.metrics.IncrementCounter("email_change_requested")
+	return &RequestEmailChangeResult{ExpiresAt: change.ExpiresAt}, nil
+}
+
+// ConfirmEmailChangeCommand 确认变更邮箱命令
+type ConfirmEmailChangeCommand struct {
+	Token string `validate:"required"`
+}
+
+type ConfirmEmailChangeHandler struct {
+	userRepo   output.UserRepository
+	changeRepo output.EmailChangeRepository
+	eventStore output.EventStore
+	eventBus   output.EventBus
+	tokenSvc   output.TokenService
+	cache      output.Cache
+	uow        output.UnitOfWork
+	logger     output.Logger
+	metrics    output.MetricsReporter
+}
+
+func NewConfirmEmailChangeHandler(
+	userRepo output.UserRepository,
+	changeRepo output.EmailChangeRepository,
+	eventStore output.EventStore,
+	eventBus output.EventBus,
+	tokenSvc output.TokenService,
+	cache output.Cache,
+	uow output.UnitOfWork,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *ConfirmEmailChangeHandler {
+	return &ConfirmEmailChangeHandler{
+		userRepo:   userRepo,
+		changeRepo: changeRepo,
+		eventStore: eventStore,
+		eventBus:   eventBus,
+		tokenSvc:   tokenSvc,
+		cache:      cache,
+		uow:        uow,
+		logger:     logger,
+		metrics:    metrics,
+	}
+}
+
+func (h *ConfirmEmailChangeHandler) Handle(ctx context.Context, confirmCmd *ConfirmEmailChangeCommand) (struct{}, error) {
+	// 1. 查找变更请求
+	change, err := h.changeRepo.FindByConfirmToken(ctx, crypto.HashToken(confirmCmd.Token))
+	if err != nil {
+		return struct{}{}, err
+	}
+	if change.IsExpired(time.Now()) {
+		if err := h.changeRepo.Delete(ctx, change.ID); err != nil {
+			h.logger.Error("failed to delete expired email change", "id", change.ID, "error", err)
+		}
+		return struct{}{}, errors.ErrEmailChangeExpired
+	}
+
+	newEmail, err := vo.NewEmail(change.NewEmail)
+	if err != nil {
+		return struct{}{}, err
+	}
+
+	var user *aggregate.User
+	err = h.uow.WithTransaction(ctx, func(ctx context.Context) error {
+		// 2. 申请后邮箱已再次变更或用户已删除时，请求失效
+		user, err = h.userRepo.FindByID(ctx, change.UserID)
+		if err != nil {
+			return err
+		}
+		if user.Email().String() != change.OldEmail {
+			return errors.ErrEmailChangeStale
+		}
+
+		// 3. 确认时重新检查唯一性，申请后该邮箱可能已被注册
+		exists, err := h.userRepo.ExistsByEmail(ctx, newEmail)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return errors.ErrEmailAlreadyExists
+		}
+
+		// 4. 变更邮箱
+		if err := user.ChangeEmail(newEmail); err != nil {
+			return err
+		}
+
+		// 5. 保存用户
+		if err := h.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+
+		// 6. 保存事件
+		if err := h.eventStore.SaveEvents(ctx, user.ID(), user.Events(), user.OriginalVersion()); err != nil {
+			return err
+		}
+
+		// 7. 删除变更请求，令牌只能使用一次
+		return h.changeRepo.Delete(ctx, change.ID)
+	})
+	if errors.Is(err, errors.ErrEmailChangeStale) || errors.Is(err, errors.ErrInactiveUser) {
+		if err := h.changeRepo.Delete(ctx, change.ID); err != nil {
+			h.logger.Error("failed to delete stale email change", "id", change.ID, "error", err)
+		}
+	}
+	if err != nil {
+		h.metrics.IncrementCounter("email_change_failure")
+		return struct{}{}, err
+	}
+
+	// 8. 清除缓存并吊销旧令牌，令牌中携带旧邮箱
+	clearUserCache(ctx, h.cache, h.logger, user.ID())
+	if err := h.tokenSvc.RevokeUserTokens(ctx, user.ID()); err != nil {
+		h.logger.Error("failed to revoke tokens after email change", "user_id", user.ID(), "error", err)
+	}
+
+	// 9. 发布事件
+	for _, evt := range user.Events() {
+		if err := h.eventBus.Publish(ctx, evt); err != nil {
+			h.logger.Error("failed to publish email changed event", "user_id", user.ID(), "error", err)
+		}
+	}
+
+	h.logger.Info("email changed", "user_id", user.ID())
+	h.metrics.IncrementCounter("email_change_success")
+	return struct{}{}, nil
+}
+
+// CancelEmailChangeCommand 通过旧邮箱中的链接取消变更
+type CancelEmailChangeCommand struct {
+	Token string `validate:"required"`
+}
+
+type CancelEmailChangeHandler struct {
+	changeRepo output.EmailChangeRepository
+	logger     output.Logger
+	metrics    output.MetricsReporter
+}
+
+func NewCancelEmailChangeHandler(
+	changeRepo output.EmailChangeRepository,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *CancelEmailChangeHandler {
+	return &CancelEmailChangeHandler{
+		changeRepo: changeRepo,
+		logger:     logger,
+		metrics:    metrics,
+	}
+}
+
+func (h *CancelEmailChangeHandler) Handle(ctx context.Context, cancelCmd *CancelEmailChangeCommand) (struct{}, error) {
+	change, err := h.changeRepo.FindByCancelToken(ctx, crypto.HashToken(cancelCmd.Token))
+	if err != nil {
+		return struct{}{}, err
+	}
+
+	if err := h.changeRepo.Delete(ctx, change.ID); err != nil {
+		return struct{}{}, err
+	}
+
+	h.logger.Info("email change cancelled", "user_id", change.UserID)
+	h.metrics.IncrementCounter("email_change_cancelled")
+	return struct{}{}, nil
+}
+
+// ExpireEmailChangesCommand 删除已过期的待确认邮箱变更，由调度器周期执行
+type ExpireEmailChangesCommand struct{}
+
+type ExpireEmailChangesHandler struct {
+	changeRepo output.EmailChangeRepository
+	logger     output.Logger
+	metrics    output.MetricsReporter
+}
+
+func NewExpireEmailChangesHandler(changeRepo output.EmailChangeRepository, logger output.Logger, metrics output.MetricsReporter) *ExpireEmailChangesHandler {
+	return &ExpireEmailChangesHandler{
+		changeRepo: changeRepo,
+		logger:     logger,
+		metrics:    metrics,
+	}
+}
+
+func (h *ExpireEmailChangesHandler) Handle(ctx context.Context, _ *ExpireEmailChangesCommand) (int64, error) {
+	n, err := h.changeRepo.DeleteExpired(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	if n > 0 {
+		h.logger.Info("expired email changes deleted", "count", n)
+		h.metrics.IncrementCounter("email_change_expired")
+	}
+	return n, nil
+}
@@ -0,0 +1,116 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/aggregate"
+	"github.com/gohex/gohex/internal/domain/event"
+	"github.com/gohex/gohex/internal/domain/vo"
+	"github.com/gohex/gohex/internal/infrastructure/adapter/secondary/cache/memory"
+	"github.com/gohex/gohex/internal/testutil"
+	"github.com/gohex/gohex/pkg/crypto"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+func TestConfirmEmailChangeHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		oldEmail    string
+		setup       func(t *testing.T, users *testutil.UserRepository)
+		wantErr     error
+		wantEmail   string
+		wantDeleted bool
+	}{
+		{
+			name:        "confirms pending change",
+			oldEmail:    "alice@example.com",
+			wantEmail:   "alice.new@example.com",
+			wantDeleted: true,
+		},
+		{
+			name:        "email changed since request",
+			oldEmail:    "alice.old@example.com",
+			wantErr:     errors.ErrEmailChangeStale,
+			wantEmail:   "alice@example.com",
+			wantDeleted: true,
+		},
+		{
+			name:     "user deleted since request",
+			oldEmail: "alice@example.com",
+			setup: func(t *testing.T, users *testutil.UserRepository) {
+				user, err := users.FindByID(context.Background(), "alice")
+				require.NoError(t, err)
+				require.NoError(t, user.Delete("alice"))
+				require.NoError(t, users.Update(context.Background(), user))
+			},
+			wantErr:     errors.ErrInactiveUser,
+			wantEmail:   "alice@example.com",
+			wantDeleted: true,
+		},
+		{
+			name:     "new email taken since request",
+			oldEmail: "alice@example.com",
+			setup: func(t *testing.T, users *testutil.UserRepository) {
+				email, err := vo.NewEmail("alice.new@example.com")
+				require.NoError(t, err)
+				profile, err := vo.NewUserProfile("Mallory", "")
+				require.NoError(t, err)
+				now := time.Now()
+				require.NoError(t, users.Save(context.Background(), aggregate.ReconstituteUser("mallory", email,
+					vo.NewPasswordFromHash("hash"), profile, vo.StatusActive, []vo.UserRole{vo.RoleUser}, now, now, nil, nil, nil, 1)))
+			},
+			wantErr:   errors.ErrEmailAlreadyExists,
+			wantEmail: "alice@example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			users := testutil.NewUserRepository(testutil.NewUser("alice", vo.StatusActive, vo.RoleUser))
+			if tt.setup != nil {
+				tt.setup(t, users)
+			}
+			changes := &testutil.EmailChangeRepository{Changes: []*output.PendingEmailChange{{
+				ID:               "c1",
+				UserID:           "alice",
+				OldEmail:         tt.oldEmail,
+				NewEmail:         "alice.new@example.com",
+				ConfirmTokenHash: crypto.HashToken("confirm-token"),
+				ExpiresAt:        time.Now().Add(time.Hour),
+			}}}
+			tokens := testutil.NewTokenService()
+			bus := &testutil.EventBus{}
+			h := NewConfirmEmailChangeHandler(users, changes, testutil.NewEventStore(), bus, tokens,
+				memory.NewCache(time.Minute, testutil.NopLogger{}, testutil.NewMetrics()), testutil.UnitOfWork{},
+				testutil.NopLogger{}, testutil.NewMetrics())
+
+			_, err := h.Handle(ctx, &ConfirmEmailChangeCommand{Token: "confirm-token"})
+
+			user, findErr := users.FindByID(ctx, "alice")
+			require.NoError(t, findErr)
+			assert.Equal(t, tt.wantEmail, user.Email().String())
+			if tt.wantDeleted {
+				assert.Equal(t, []string{"c1"}, changes.Deleted)
+			} else {
+				assert.Empty(t, changes.Deleted)
+			}
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, tokens.RevokedUsers)
+				assert.Empty(t, bus.Published)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []string{"alice"}, tokens.RevokedUsers)
+			assert.Equal(t, []string{event.UserEmailChanged}, typesOf(bus.Published))
+		})
+	}
+}
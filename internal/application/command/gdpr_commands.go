@@ -0,0 +1,211 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gohex/gohex/internal/application/dto"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/event"
+)
+
+// RequestDataExportCommand 申请导出个人数据命令
+type RequestDataExportCommand struct {
+	UserID      string `validate:"required"`
+	RequestedBy string `validate:"required"`
+}
+
+type RequestDataExportHandler struct {
+	userRepo   output.UserRepository
+	exportRepo output.DataExportRepository
+	logger     output.Logger
+	metrics    output.MetricsReporter
+}
+
+func NewRequestDataExportHandler(
+	userRepo output.UserRepository,
+	exportRepo output.DataExportRepository,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *RequestDataExportHandler {
+	return &RequestDataExportHandler{
+		userRepo:   userRepo,
+		exportRepo: exportRepo,
+		logger:     logger,
+		metrics:    metrics,
+	}
+}
+
+func (h *RequestDataExportHandler) Handle(ctx context.Context, exportCmd *RequestDataExportCommand) (*dto.DataExportDTO, error) {
+	// 1. 确认用户存在
+	if _, err := h.userRepo.FindByID(ctx, exportCmd.UserID); err != nil {
+		return nil, err
+	}
+
+	// 2. 创建待处理的导出请求，由后台任务生成归档
+	export := &output.DataExport{
+		ID:          uuid.New().String(),
+		UserID:      exportCmd.UserID,
+		RequestedBy: exportCmd.RequestedBy,
+		Status:      output.DataExportPending,
+		RequestedAt: time.Now(),
+	}
+	if err := h.exportRepo.Save(ctx, export); err != nil {
+		return nil, err
+	}
+
+	h.logger.Info("data export requested",
+		"export_id", export.ID,
+		"user_id", export.UserID,
+		"requested_by", export.RequestedBy,
+	)
+	h.metrics.IncrementCounter("data_export_requested")
+
+	return dto.NewDataExportDTO(export), nil
+}
+
+// EraseUserCommand 擦除用户个人信息命令（被遗忘权）
+type EraseUserCommand struct {
+	UserID      string `validate:"required"`
+	RequestedBy string `validate:"required"`
+	Reason      string `validate:"max=500"`
+}
+
+type EraseUserHandler struct {
+	userRepo     output.UserRepository
+	eventStore   output.EventStore
+	eventBus     output.EventBus
+	personalData personalDataEraser
+	cache        output.Cache
+	uow          output.UnitOfWork
+	logger       output.Logger
+	metrics      output.MetricsReporter
+}
+
+func NewEraseUserHandler(
+	userRepo output.UserRepository,
+	eventStore output.EventStore,
+	eventBus output.EventBus,
+	keyStore output.KeyStore,
+	exportRepo output.DataExportRepository,
+	changeRepo output.EmailChangeRepository,
+	auditLog output.AuditLog,
+	webhookRepo output.WebhookRepository,
+	cache output.Cache,
+	uow output.UnitOfWork,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *EraseUserHandler {
+	return &EraseUserHandler{
+		userRepo:   userRepo,
+		eventStore: eventStore,
+		eventBus:   eventBus,
+		personalData: personalDataEraser{
+			eventStore:  eventStore,
+			keyStore:    keyStore,
+			exportRepo:  exportRepo,
+			changeRepo:  changeRepo,
+			auditLog:    auditLog,
+			webhookRepo: webhookRepo,
+		},
+		cache:   cache,
+		uow:     uow,
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+func (h *EraseUserHandler) Handle(ctx context.Context, eraseCmd *EraseUserCommand) (struct{}, error) {
+	var events []event.Event
+	err := h.uow.WithTransaction(ctx, func(ctx context.Context) error {
+		// 1. 获取用户
+		user, err := h.userRepo.FindByID(ctx, eraseCmd.UserID)
+		if err != nil {
+			return err
+		}
+
+		// 2. 匿名化个人信息
+		if err := user.Erase(eraseCmd.RequestedBy); err != nil {
+			return err
+		}
+
+		// 3. 保存用户
+		if err := h.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+
+		// 4. 保存事件，擦除事件不含个人信息，以明文保存
+		if err := h.eventStore.SaveEvents(ctx, user.ID(), user.Events(), user.OriginalVersion()); err != nil {
+			return err
+		}
+
+		// 5. 清除其他存储中的个人数据并粉碎密钥
+		if err := h.personalData.erase(ctx, user.ID()); err != nil {
+			return err
+		}
+
+		events = user.Events()
+		return nil
+	})
+	if err != nil {
+		h.logger.Error("failed to erase user", "user_id", eraseCmd.UserID, "error", err)
+		h.metrics.IncrementCounter("erase_user_failure")
+		return struct{}{}, err
+	}
+
+	// 6. 清除缓存
+	clearUserCache(ctx, h.cache, h.logger, eraseCmd.UserID)
+
+	// 7. 发布事件，通知下游删除各自持有的副本
+	for _, evt := range events {
+		if err := h.eventBus.Publish(ctx, evt); err != nil {
+			h.logger.Error("failed to publish user erased event", "user_id", eraseCmd.UserID, "error", err)
+		}
+	}
+
+	h.logger.Info("user erased",
+		"user_id", eraseCmd.UserID,
+		"requested_by", eraseCmd.RequestedBy,
+		"reason", eraseCmd.Reason,
+	)
+	h.metrics.IncrementCounter("erase_user_success")
+	return struct{}{}, nil
+}
+
+// personalDataEraser 擦除和永久删除用户时清除其散落在各存储中的个人数据
+type personalDataEraser struct {
+	eventStore  output.EventStore
+	keyStore    output.KeyStore
+	exportRepo  output.DataExportRepository
+	changeRepo  output.EmailChangeRepository
+	auditLog    output.AuditLog
+	webhookRepo output.WebhookRepository
+}
+
+// erase 须在工作单元内调用，任一步失败时整体回滚，密钥不会在数据清除前被粉碎
+func (e personalDataEraser) erase(ctx context.Context, userID string) error {
+	// 1. 删除已生成的导出归档和待确认的邮箱变更
+	if err := e.exportRepo.DeleteByUserID(ctx, userID); err != nil {
+		return err
+	}
+	if err := e.changeRepo.DeleteByUserID(ctx, userID); err != nil {
+		return err
+	}
+
+	// 2. 清空启用加密前写入的明文事件
+	if _, err := e.eventStore.RedactEvents(ctx, userID); err != nil {
+		return err
+	}
+
+	// 3. 清空审计记录的变更内容和 webhook 投递数据
+	if _, err := e.auditLog.RedactSubject(ctx, userID); err != nil {
+		return err
+	}
+	if _, err := e.webhookRepo.RedactDeliveries(ctx, userID); err != nil {
+		return err
+	}
+
+	// 4. 删除加密密钥，加密事件中的个人数据随之不可读
+	return e.keyStore.DeleteKey(ctx, userID)
+}
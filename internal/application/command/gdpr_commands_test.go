@@ -0,0 +1,150 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/event"
+	"github.com/gohex/gohex/internal/domain/vo"
+	"github.com/gohex/gohex/internal/infrastructure/adapter/secondary/cache/memory"
+	"github.com/gohex/gohex/internal/testutil"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+type erasureFixture struct {
+	users    *testutil.UserRepository
+	events   *testutil.EventStore
+	bus      *testutil.EventBus
+	keys     *testutil.KeyStore
+	exports  *testutil.DataExportRepository
+	changes  *testutil.EmailChangeRepository
+	audit    *testutil.AuditLog
+	webhooks *testutil.WebhookRepository
+}
+
+// newErasureFixture 为 alice 和 bob 写入带个人信息的事件、审计记录和 webhook 投递
+func newErasureFixture(t *testing.T) erasureFixture {
+	t.Helper()
+	f := erasureFixture{
+		users: testutil.NewUserRepository(
+			testutil.NewUser("alice", vo.StatusActive, vo.RoleUser),
+			testutil.NewUser("bob", vo.StatusActive, vo.RoleUser),
+		),
+		events:   testutil.NewEventStore(),
+		bus:      &testutil.EventBus{},
+		keys:     testutil.NewKeyStore(),
+		exports:  &testutil.DataExportRepository{},
+		changes:  &testutil.EmailChangeRepository{},
+		audit:    &testutil.AuditLog{},
+		webhooks: &testutil.WebhookRepository{},
+	}
+
+	ctx := context.Background()
+	for _, id := range []string{"alice", "bob"} {
+		email, name := id+"@example.com", "Test "+id
+		created := event.NewUserCreatedEvent(id, email, name)
+		require.NoError(t, f.events.SaveEvents(ctx, id, []event.Event{created}, 0))
+		_, err := f.keys.GetOrCreateKey(ctx, id)
+		require.NoError(t, err)
+
+		require.NoError(t, f.audit.Append(ctx, &output.AuditEntry{
+			SubjectID: id,
+			Action:    "UpdateUserProfileCommand",
+			Changes:   map[string]output.AuditChange{"name": {Before: "Old", After: name}},
+		}))
+
+		payload, err := json.Marshal(map[string]interface{}{
+			"id": created.ID(), "type": created.Type(), "aggregate_id": id, "data": created,
+		})
+		require.NoError(t, err)
+		require.NoError(t, f.webhooks.SaveDeliveries(ctx, []*output.WebhookDelivery{
+			{ID: "d-" + id, EventID: created.ID(), EventType: created.Type(), Payload: payload},
+		}))
+	}
+	return f
+}
+
+func (f erasureFixture) handler() *EraseUserHandler {
+	return NewEraseUserHandler(f.users, f.events, f.bus, f.keys, f.exports, f.changes, f.audit, f.webhooks,
+		memory.NewCache(time.Minute, testutil.NopLogger{}, testutil.NewMetrics()), testutil.UnitOfWork{},
+		testutil.NopLogger{}, testutil.NewMetrics())
+}
+
+// stored 序列化主体在各存储中留下的数据
+func (f erasureFixture) stored(t *testing.T, userID string) string {
+	t.Helper()
+	ctx := context.Background()
+
+	events, err := f.events.GetEvents(ctx, userID)
+	require.NoError(t, err)
+	entries, _, err := f.audit.Find(ctx, output.AuditFilter{SubjectID: userID})
+	require.NoError(t, err)
+	var payloads []json.RawMessage
+	for _, d := range f.webhooks.Deliveries {
+		payloads = append(payloads, d.Payload)
+	}
+	user, err := f.users.FindByID(ctx, userID)
+	require.NoError(t, err)
+
+	data, err := json.Marshal([]interface{}{events, entries, payloads, user.Email().String(), user.Profile().Name()})
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestEraseUserHandler_RemovesPersonalData(t *testing.T) {
+	f := newErasureFixture(t)
+	ctx := context.Background()
+
+	_, err := f.handler().Handle(ctx, &EraseUserCommand{UserID: "alice", RequestedBy: "admin"})
+	require.NoError(t, err)
+
+	stored := f.stored(t, "alice")
+	for _, pii := range []string{"alice@example.com", "Test alice"} {
+		assert.NotContains(t, stored, pii)
+	}
+
+	// 密钥已粉碎，关联数据已删除
+	_, err = f.keys.GetKey(ctx, "alice")
+	assert.ErrorIs(t, err, output.ErrKeyShredded)
+	assert.Equal(t, []string{"alice"}, f.exports.DeletedUsers)
+	assert.Equal(t, []string{"alice"}, f.changes.DeletedUsers)
+
+	// 擦除事件本身不含个人信息，密钥粉碎后仍可读取
+	events, err := f.events.GetEvents(ctx, "alice")
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	erased, ok := events[1].(*event.UserErasedEvent)
+	require.True(t, ok)
+	assert.Equal(t, "admin", erased.RequestedBy)
+	assert.Equal(t, []string{event.UserErased}, typesOf(f.bus.Published))
+
+	// 其他用户的数据不受影响
+	assert.Contains(t, f.stored(t, "bob"), "bob@example.com")
+	_, err = f.keys.GetKey(ctx, "bob")
+	assert.NoError(t, err)
+}
+
+func TestEraseUserHandler_AlreadyErased(t *testing.T) {
+	f := newErasureFixture(t)
+	ctx := context.Background()
+
+	_, err := f.handler().Handle(ctx, &EraseUserCommand{UserID: "alice", RequestedBy: "admin"})
+	require.NoError(t, err)
+
+	_, err = f.handler().Handle(ctx, &EraseUserCommand{UserID: "alice", RequestedBy: "admin"})
+	assert.ErrorIs(t, err, errors.ErrUserAlreadyErased)
+}
+
+func typesOf(events []event.Event) []string {
+	var types []string
+	for _, e := range events {
+		types = append(types, e.Type())
+	}
+	return types
+}
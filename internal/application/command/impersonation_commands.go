@@ -0,0 +1,224 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/gohex/gohex/internal/application/dto"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/pkg/actor"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+// ImpersonateUserCommand 以其他用户身份登录命令
+type ImpersonateUserCommand struct {
+	ActorID   string `validate:"required"`
+	SubjectID string `validate:"required"`
+	Reason    string `validate:"required,max=500"`
+	IP        string
+	UserAgent string
+}
+
+// CredentialCommand 模拟结果包含令牌，不保存到幂等存储
+func (*ImpersonateUserCommand) CredentialCommand() {}
+
+// defaultImpersonationTTL 未配置时模拟令牌的有效期
+const defaultImpersonationTTL = 15 * time.Minute
+
+type ImpersonateUserHandler struct {
+	userRepo   output.UserRepository
+	tokenSvc   output.TokenService
+	eventStore output.EventStore
+	uow        output.UnitOfWork
+	enabled    bool
+	ttl        time.Duration
+	logger     output.Logger
+	metrics    output.MetricsReporter
+}
+
+// NewImpersonateUserHandler enabled 为 false 时拒绝所有模拟请求，ttl 为模拟令牌的有效期
+func NewImpersonateUserHandler(
+	userRepo output.UserRepository,
+	tokenSvc output.TokenService,
+	eventStore output.EventStore,
+	uow output.UnitOfWork,
+	enabled bool,
+	ttl time.Duration,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *ImpersonateUserHandler {
+	if ttl <= 0 {
+		ttl = defaultImpersonationTTL
+	}
+	return &ImpersonateUserHandler{
+		userRepo:   userRepo,
+		tokenSvc:   tokenSvc,
+		eventStore: eventStore,
+		uow:        uow,
+		enabled:    enabled,
+		ttl:        ttl,
+		logger:     logger,
+		metrics:    metrics,
+	}
+}
+
+func (h *ImpersonateUserHandler) Handle(ctx context.Context, impCmd *ImpersonateUserCommand) (*dto.ImpersonationResponseDTO, error) {
+	if !h.enabled {
+		return nil, errors.ErrImpersonationDisabled
+	}
+
+	// 1. 不允许在模拟会话中再次发起模拟
+	if actor.IsImpersonated(ctx) {
+		return nil, errors.ErrImpersonationForbidden
+	}
+
+	// 2. 获取操作人和被模拟用户
+	actorUser, err := h.userRepo.FindByID(ctx, impCmd.ActorID)
+	if err != nil {
+		return nil, err
+	}
+
+	subject, err := h.userRepo.FindByID(ctx, impCmd.SubjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 校验权限
+	if err := subject.CanBeImpersonatedBy(actorUser); err != nil {
+		h.metrics.IncrementCounter("impersonation_denied")
+		h.logger.Warn("impersonation denied",
+			"actor_id", actorUser.ID(),
+			"subject_id", subject.ID(),
+			"error", err,
+		)
+		return nil, err
+	}
+
+	// 4. 生成短期模拟令牌
+	token, expiresAt, err := h.tokenSvc.GenerateImpersonationToken(actorUser, subject, h.ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	// 5. 记录开始事件
+	if err := subject.StartImpersonation(actorUser, impCmd.Reason, impCmd.IP, impCmd.UserAgent, expiresAt); err != nil {
+		return nil, err
+	}
+
+	// 用户与事件在同一事务中保存，用户版本与事件流保持一致
+	err = h.uow.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := h.userRepo.Update(ctx, subject); err != nil {
+			return err
+		}
+		return h.eventStore.SaveEvents(ctx, subject.ID(), subject.Events(), subject.OriginalVersion())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	h.logger.Info("impersonation started",
+		"actor_id", actorUser.ID(),
+		"subject_id", subject.ID(),
+		"expires_at", expiresAt,
+	)
+	h.metrics.IncrementCounter("impersonation_started")
+
+	return &dto.ImpersonationResponseDTO{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresAt:   expiresAt,
+		ActorID:     actorUser.ID(),
+		SubjectID:   subject.ID(),
+	}, nil
+}
+
+// EndImpersonationCommand 结束模拟命令
+type EndImpersonationCommand struct {
+	ActorID   string `validate:"required"`
+	SubjectID string `validate:"required"`
+	Token     string `validate:"required"`
+}
+
+type EndImpersonationHandler struct {
+	userRepo   output.UserRepository
+	tokenSvc   output.TokenService
+	eventStore output.EventStore
+	uow        output.UnitOfWork
+	logger     output.Logger
+	metrics    output.MetricsReporter
+}
+
+func NewEndImpersonationHandler(
+	userRepo output.UserRepository,
+	tokenSvc output.TokenService,
+	eventStore output.EventStore,
+	uow output.UnitOfWork,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *EndImpersonationHandler {
+	return &EndImpersonationHandler{
+		userRepo:   userRepo,
+		tokenSvc:   tokenSvc,
+		eventStore: eventStore,
+		uow:        uow,
+		logger:     logger,
+		metrics:    metrics,
+	}
+}
+
+func (h *EndImpersonationHandler) Handle(ctx context.Context, endCmd *EndImpersonationCommand) (struct{}, error) {
+	if endCmd.ActorID == endCmd.SubjectID {
+		return struct{}{}, errors.ErrNotImpersonating
+	}
+
+	// 1. 令牌必须是模拟令牌，且 act 声明中的操作人和主体与调用方一致，
+	// 防止用他人的令牌结束别人的模拟会话或伪造结束事件
+	claims, err := h.tokenSvc.ValidateToken(ctx, endCmd.Token)
+	if err != nil {
+		return struct{}{}, err
+	}
+	if !claims.IsImpersonated() {
+		return struct{}{}, errors.ErrNotImpersonating
+	}
+	if claims.ActorID() != endCmd.ActorID || claims.UserID != endCmd.SubjectID {
+		h.metrics.IncrementCounter("impersonation_end_denied")
+		h.logger.Warn("impersonation token does not belong to caller",
+			"actor_id", endCmd.ActorID,
+			"token_actor_id", claims.ActorID(),
+			"subject_id", endCmd.SubjectID,
+			"token_subject_id", claims.UserID,
+		)
+		return struct{}{}, errors.ErrImpersonationTokenMismatch
+	}
+
+	// 2. 吊销模拟令牌
+	if err := h.tokenSvc.RevokeToken(ctx, endCmd.Token); err != nil {
+		h.logger.Error("failed to revoke impersonation token", "error", err)
+		return struct{}{}, err
+	}
+
+	// 3. 记录结束事件
+	subject, err := h.userRepo.FindByID(ctx, endCmd.SubjectID)
+	if err != nil {
+		return struct{}{}, err
+	}
+
+	subject.EndImpersonation(endCmd.ActorID)
+	err = h.uow.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := h.userRepo.Update(ctx, subject); err != nil {
+			return err
+		}
+		return h.eventStore.SaveEvents(ctx, subject.ID(), subject.Events(), subject.OriginalVersion())
+	})
+	if err != nil {
+		return struct{}{}, err
+	}
+
+	h.logger.Info("impersonation ended",
+		"actor_id", endCmd.ActorID,
+		"subject_id", subject.ID(),
+	)
+	h.metrics.IncrementCounter("impersonation_ended")
+
+	return struct{}{}, nil
+}
@@ -0,0 +1,193 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/event"
+	"github.com/gohex/gohex/internal/domain/vo"
+	"github.com/gohex/gohex/internal/testutil"
+	"github.com/gohex/gohex/pkg/actor"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+type impersonationFixture struct {
+	users  *testutil.UserRepository
+	tokens *testutil.TokenService
+	events *testutil.EventStore
+}
+
+func newImpersonationFixture() impersonationFixture {
+	return impersonationFixture{
+		users: testutil.NewUserRepository(
+			testutil.NewUser("support", vo.StatusActive, vo.RoleSupport),
+			testutil.NewUser("agent", vo.StatusActive, vo.RoleSupport),
+			testutil.NewUser("alice", vo.StatusActive, vo.RoleUser),
+			testutil.NewUser("bob", vo.StatusActive, vo.RoleUser),
+			testutil.NewUser("root", vo.StatusActive, vo.RoleAdmin),
+		),
+		tokens: testutil.NewTokenService(),
+		events: testutil.NewEventStore(),
+	}
+}
+
+func (f impersonationFixture) impersonate(enabled bool, ttl time.Duration) *ImpersonateUserHandler {
+	return NewImpersonateUserHandler(f.users, f.tokens, f.events, testutil.UnitOfWork{}, enabled, ttl, testutil.NopLogger{}, testutil.NewMetrics())
+}
+
+func (f impersonationFixture) end() *EndImpersonationHandler {
+	return NewEndImpersonationHandler(f.users, f.tokens, f.events, testutil.UnitOfWork{}, testutil.NopLogger{}, testutil.NewMetrics())
+}
+
+func TestImpersonateUserHandler(t *testing.T) {
+	tests := []struct {
+		name      string
+		enabled   bool
+		ctx       context.Context
+		actorID   string
+		subjectID string
+		wantErr   error
+	}{
+		{name: "support impersonates user", enabled: true, actorID: "support", subjectID: "alice"},
+		{name: "regular user is denied", enabled: true, actorID: "bob", subjectID: "alice", wantErr: errors.ErrInsufficientPermissions},
+		{name: "admin subject is denied", enabled: true, actorID: "support", subjectID: "root", wantErr: errors.ErrCannotImpersonateAdmin},
+		{name: "disabled by config", enabled: false, actorID: "support", subjectID: "alice", wantErr: errors.ErrImpersonationDisabled},
+		{
+			name:    "nested impersonation is denied",
+			enabled: true,
+			ctx:     actor.WithActor(context.Background(), actor.Actor{UserID: "alice", ImpersonatorID: "support"}),
+			actorID: "support", subjectID: "bob",
+			wantErr: errors.ErrImpersonationForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newImpersonationFixture()
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			result, err := f.impersonate(tt.enabled, 10*time.Minute).Handle(ctx, &ImpersonateUserCommand{
+				ActorID: tt.actorID, SubjectID: tt.subjectID, Reason: "ticket",
+			})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, f.events.Types(tt.subjectID), "denied impersonation must not record events")
+				return
+			}
+			require.NoError(t, err)
+			resp := result
+			assert.Equal(t, tt.actorID, resp.ActorID)
+			assert.Equal(t, tt.subjectID, resp.SubjectID)
+			assert.WithinDuration(t, time.Now().Add(10*time.Minute), resp.ExpiresAt, time.Minute)
+			assert.Equal(t, []string{event.UserImpersonationStarted}, f.events.Types(tt.subjectID))
+		})
+	}
+}
+
+func TestImpersonateUserHandler_DefaultTTL(t *testing.T) {
+	f := newImpersonationFixture()
+
+	result, err := f.impersonate(true, 0).Handle(context.Background(), &ImpersonateUserCommand{
+		ActorID: "support", SubjectID: "alice", Reason: "ticket",
+	})
+
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(defaultImpersonationTTL), result.ExpiresAt, time.Minute)
+}
+
+func TestEndImpersonationHandler(t *testing.T) {
+	tests := []struct {
+		name      string
+		actorID   string
+		subjectID string
+		token     func(f impersonationFixture) string
+		wantErr   error
+	}{
+		{
+			name:    "caller ends own session",
+			actorID: "support", subjectID: "alice",
+			token: func(f impersonationFixture) string { return issue(t, f, "support", "alice") },
+		},
+		{
+			name:    "token issued to another actor",
+			actorID: "agent", subjectID: "alice",
+			token:   func(f impersonationFixture) string { return issue(t, f, "support", "alice") },
+			wantErr: errors.ErrImpersonationTokenMismatch,
+		},
+		{
+			name:    "token for another subject",
+			actorID: "support", subjectID: "bob",
+			token:   func(f impersonationFixture) string { return issue(t, f, "support", "alice") },
+			wantErr: errors.ErrImpersonationTokenMismatch,
+		},
+		{
+			name:    "regular token",
+			actorID: "support", subjectID: "alice",
+			token: func(f impersonationFixture) string {
+				f.tokens.Claims["plain"] = &output.TokenClaims{UserID: "alice"}
+				return "plain"
+			},
+			wantErr: errors.ErrNotImpersonating,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newImpersonationFixture()
+			token := tt.token(f)
+
+			_, err := f.end().Handle(context.Background(), &EndImpersonationCommand{
+				ActorID: tt.actorID, SubjectID: tt.subjectID, Token: token,
+			})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, f.tokens.Revoked, "foreign tokens must not be revoked")
+				assert.NotContains(t, f.events.Types(tt.subjectID), event.UserImpersonationEnded)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []string{token}, f.tokens.Revoked)
+			assert.Contains(t, f.events.Types(tt.subjectID), event.UserImpersonationEnded)
+		})
+	}
+}
+
+func TestImpersonation_SubjectStaysWritable(t *testing.T) {
+	f := newImpersonationFixture()
+	ctx := context.Background()
+
+	// 开始和结束模拟后，被模拟用户的版本与事件流一致，后续修改不会冲突
+	token := issue(t, f, "support", "alice")
+	_, err := f.end().Handle(ctx, &EndImpersonationCommand{ActorID: "support", SubjectID: "alice", Token: token})
+	require.NoError(t, err)
+
+	h := NewChangeUserStatusHandler(f.users, f.events, &testutil.EventBus{}, testutil.UnitOfWork{},
+		testutil.NopLogger{}, testutil.NewMetrics())
+	_, err = h.Handle(ctx, &ChangeUserStatusCommand{UserID: "alice", Status: string(vo.StatusInactive)})
+	require.NoError(t, err)
+
+	user, err := f.users.FindByID(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, vo.StatusInactive, user.Status())
+	assert.Equal(t, 4, user.Version())
+}
+
+// issue 通过处理器签发模拟令牌
+func issue(t *testing.T, f impersonationFixture, actorID, subjectID string) string {
+	t.Helper()
+	result, err := f.impersonate(true, time.Minute).Handle(context.Background(), &ImpersonateUserCommand{
+		ActorID: actorID, SubjectID: subjectID, Reason: "ticket",
+	})
+	require.NoError(t, err)
+	return result.AccessToken
+}
@@ -0,0 +1,47 @@
+package command
+
+import (
+	"context"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+// CancelJobCommand 取消异步任务，运行中的任务在下一次心跳时中断
+type CancelJobCommand struct {
+	JobID       string `validate:"required"`
+	RequestedBy string `validate:"required"`
+	IsAdmin     bool
+}
+
+type CancelJobHandler struct {
+	jobQueue output.JobQueue
+	logger   output.Logger
+	metrics  output.MetricsReporter
+}
+
+func NewCancelJobHandler(jobQueue output.JobQueue, logger output.Logger, metrics output.MetricsReporter) *CancelJobHandler {
+	return &CancelJobHandler{
+		jobQueue: jobQueue,
+		logger:   logger,
+		metrics:  metrics,
+	}
+}
+
+func (h *CancelJobHandler) Handle(ctx context.Context, cancelCmd *CancelJobCommand) (struct{}, error) {
+	job, err := h.jobQueue.FindByID(ctx, cancelCmd.JobID)
+	if err != nil {
+		return struct{}{}, err
+	}
+	if !cancelCmd.IsAdmin && job.RequestedBy != cancelCmd.RequestedBy {
+		return struct{}{}, errors.NewNotFoundError("job")
+	}
+
+	if err := h.jobQueue.Cancel(ctx, job.ID); err != nil {
+		return struct{}{}, err
+	}
+
+	h.logger.Info("job cancellation requested", "job_id", job.ID, "requested_by", cancelCmd.RequestedBy)
+	h.metrics.IncrementCounter("job_cancel_requested")
+	return struct{}{}, nil
+}
@@ -0,0 +1,341 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	cmdbus "github.com/gohex/gohex/internal/application/port/input/command"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/aggregate"
+)
+
+// DeactivateUserCommand 停用用户命令
+type DeactivateUserCommand struct {
+	UserID string `validate:"required"`
+	Reason string `validate:"max=500"`
+}
+
+// ReactivateUserCommand 重新启用用户命令
+type ReactivateUserCommand struct {
+	UserID string `validate:"required"`
+}
+
+// DeleteUserCommand 软删除用户命令
+type DeleteUserCommand struct {
+	UserID      string `validate:"required"`
+	RequestedBy string `validate:"required"`
+}
+
+// RestoreUserCommand 恢复软删除用户命令
+type RestoreUserCommand struct {
+	UserID      string `validate:"required"`
+	RequestedBy string `validate:"required"`
+}
+
+// UserLifecycleHandler 处理停用、启用、删除和恢复命令
+type UserLifecycleHandler struct {
+	userRepo   output.UserRepository
+	eventStore output.EventStore
+	eventBus   output.EventBus
+	cache      output.Cache
+	uow        output.UnitOfWork
+	logger     output.Logger
+	metrics    output.MetricsReporter
+}
+
+func NewUserLifecycleHandler(
+	userRepo output.UserRepository,
+	eventStore output.EventStore,
+	eventBus output.EventBus,
+	cache output.Cache,
+	uow output.UnitOfWork,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *UserLifecycleHandler {
+	return &UserLifecycleHandler{
+		userRepo:   userRepo,
+		eventStore: eventStore,
+		eventBus:   eventBus,
+		cache:      cache,
+		uow:        uow,
+		logger:     logger,
+		metrics:    metrics,
+	}
+}
+
+// Deactivate 停用用户
+func (h *UserLifecycleHandler) Deactivate(ctx context.Context, cmd *DeactivateUserCommand) (struct{}, error) {
+	return h.handle(ctx, cmd.UserID, "deactivate", func(user *aggregate.User) error { return user.Deactivate(cmd.Reason) })
+}
+
+// Reactivate 重新启用用户
+func (h *UserLifecycleHandler) Reactivate(ctx context.Context, cmd *ReactivateUserCommand) (struct{}, error) {
+	return h.handle(ctx, cmd.UserID, "reactivate", func(user *aggregate.User) error { return user.Reactivate() })
+}
+
+// Delete 软删除用户
+func (h *UserLifecycleHandler) Delete(ctx context.Context, cmd *DeleteUserCommand) (struct{}, error) {
+	return h.handle(ctx, cmd.UserID, "delete", func(user *aggregate.User) error { return user.Delete(cmd.RequestedBy) })
+}
+
+// Restore 恢复软删除用户
+func (h *UserLifecycleHandler) Restore(ctx context.Context, cmd *RestoreUserCommand) (struct{}, error) {
+	return h.handle(ctx, cmd.UserID, "restore", func(user *aggregate.User) error { return user.Restore(cmd.RequestedBy) })
+}
+
+func (h *UserLifecycleHandler) handle(ctx context.Context, userID, action string, transition func(*aggregate.User) error) (struct{}, error) {
+	user, err := h.apply(ctx, userID, transition)
+	if err != nil {
+		h.metrics.IncrementCounter("user_lifecycle_failure", "action", action)
+		return struct{}{}, err
+	}
+
+	clearUserCache(ctx, h.cache, h.logger, userID)
+	h.publish(ctx, user)
+
+	h.logger.Info("user lifecycle changed",
+		"user_id", userID,
+		"action", action,
+		"status", user.Status().String(),
+	)
+	h.metrics.IncrementCounter("user_lifecycle_success", "action", action)
+	return struct{}{}, nil
+}
+
+func (h *UserLifecycleHandler) apply(ctx context.Context, userID string, transition func(*aggregate.User) error) (*aggregate.User, error) {
+	var user *aggregate.User
+	err := h.uow.WithTransaction(ctx, func(ctx context.Context) error {
+		// 1. 获取用户（包含已软删除的用户）
+		var err error
+		user, err = h.userRepo.FindByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		// 2. 按状态机迁移
+		if err := transition(user); err != nil {
+			return err
+		}
+
+		// 3. 保存用户
+		if err := h.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+
+		// 4. 保存事件
+		return h.eventStore.SaveEvents(ctx, user.ID(), user.Events(), user.OriginalVersion())
+	})
+	return user, err
+}
+
+func (h *UserLifecycleHandler) publish(ctx context.Context, user *aggregate.User) {
+	for _, evt := range user.Events() {
+		if err := h.eventBus.Publish(ctx, evt); err != nil {
+			h.logger.Error("failed to publish lifecycle event", "type", evt.Type(), "error", err)
+		}
+	}
+}
+
+// PurgeUserCommand 永久删除宽限期已过的用户
+type PurgeUserCommand struct {
+	UserID string `validate:"required"`
+}
+
+// IdempotencyKey 清理任务重试或多个实例重复派发时每个用户只清理一次
+func (c *PurgeUserCommand) IdempotencyKey() string {
+	return c.UserID
+}
+
+var _ cmdbus.IdempotentCommand = (*PurgeUserCommand)(nil)
+
+type PurgeUserHandler struct {
+	userRepo     output.UserRepository
+	eventStore   output.EventStore
+	eventBus     output.EventBus
+	personalData personalDataEraser
+	cache        output.Cache
+	uow          output.UnitOfWork
+	gracePeriod  time.Duration
+	logger       output.Logger
+	metrics      output.MetricsReporter
+}
+
+func NewPurgeUserHandler(
+	userRepo output.UserRepository,
+	eventStore output.EventStore,
+	eventBus output.EventBus,
+	keyStore output.KeyStore,
+	exportRepo output.DataExportRepository,
+	changeRepo output.EmailChangeRepository,
+	auditLog output.AuditLog,
+	webhookRepo output.WebhookRepository,
+	cache output.Cache,
+	uow output.UnitOfWork,
+	gracePeriod time.Duration,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *PurgeUserHandler {
+	return &PurgeUserHandler{
+		userRepo:   userRepo,
+		eventStore: eventStore,
+		eventBus:   eventBus,
+		personalData: personalDataEraser{
+			eventStore:  eventStore,
+			keyStore:    keyStore,
+			exportRepo:  exportRepo,
+			changeRepo:  changeRepo,
+			auditLog:    auditLog,
+			webhookRepo: webhookRepo,
+		},
+		cache:       cache,
+		uow:         uow,
+		gracePeriod: gracePeriod,
+		logger:      logger,
+		metrics:     metrics,
+	}
+}
+
+func (h *PurgeUserHandler) Handle(ctx context.Context, purgeCmd *PurgeUserCommand) (struct{}, error) {
+	var user *aggregate.User
+	err := h.uow.WithTransaction(ctx, func(ctx context.Context) error {
+		// 1. 获取用户
+		var err error
+		user, err = h.userRepo.FindByID(ctx, purgeCmd.UserID)
+		if err != nil {
+			return err
+		}
+
+		// 2. 校验宽限期
+		if err := user.Purge(h.gracePeriod, time.Now()); err != nil {
+			return err
+		}
+
+		// 3. 保存事件，事件不含个人信息，以明文保存
+		if err := h.eventStore.SaveEvents(ctx, user.ID(), user.Events(), user.OriginalVersion()); err != nil {
+			return err
+		}
+
+		// 4. 清除关联数据并粉碎密钥，历史事件随之不可读
+		if err := h.personalData.erase(ctx, user.ID()); err != nil {
+			return err
+		}
+
+		// 5. 永久删除用户记录
+		return h.userRepo.Purge(ctx, user.ID())
+	})
+	if err != nil {
+		h.logger.Error("failed to purge user", "user_id", purgeCmd.UserID, "error", err)
+		h.metrics.IncrementCounter("purge_user_failure")
+		return struct{}{}, err
+	}
+
+	clearUserCache(ctx, h.cache, h.logger, purgeCmd.UserID)
+	for _, evt := range user.Events() {
+		if err := h.eventBus.Publish(ctx, evt); err != nil {
+			h.logger.Error("failed to publish user purged event", "user_id", purgeCmd.UserID, "error", err)
+		}
+	}
+
+	h.logger.Info("user purged", "user_id", purgeCmd.UserID)
+	h.metrics.IncrementCounter("purge_user_success")
+	return struct{}{}, nil
+}
+
+// PurgeDeletedUsersCommand 永久删除所有宽限期已过的用户，由调度器周期执行
+type PurgeDeletedUsersCommand struct{}
+
+type PurgeDeletedUsersHandler struct {
+	userRepo    output.UserRepository
+	commandBus  cmdbus.Bus
+	gracePeriod time.Duration
+	batchSize   int
+	logger      output.Logger
+	metrics     output.MetricsReporter
+}
+
+func NewPurgeDeletedUsersHandler(
+	userRepo output.UserRepository,
+	commandBus cmdbus.Bus,
+	gracePeriod time.Duration,
+	batchSize int,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *PurgeDeletedUsersHandler {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	return &PurgeDeletedUsersHandler{
+		userRepo:    userRepo,
+		commandBus:  commandBus,
+		gracePeriod: gracePeriod,
+		batchSize:   batchSize,
+		logger:      logger,
+		metrics:     metrics,
+	}
+}
+
+func (h *PurgeDeletedUsersHandler) Handle(ctx context.Context, _ *PurgeDeletedUsersCommand) (int64, error) {
+	if h.gracePeriod <= 0 {
+		return 0, nil
+	}
+
+	// 按 ID 分页遍历所有到期用户，失败的用户留到下次执行重试，不会让后续批次饿死
+	before := time.Now().Add(-h.gracePeriod)
+	afterID := ""
+	var purged, failed int64
+	for ctx.Err() == nil {
+		users, err := h.userRepo.FindDeletedBefore(ctx, before, afterID, h.batchSize)
+		if err != nil {
+			return purged, err
+		}
+
+		for _, user := range users {
+			// 每个用户单独分发 PurgeUserCommand，保证每次永久删除都有审计记录
+			if _, err := cmdbus.Dispatch[*PurgeUserCommand, struct{}](ctx, h.commandBus, &PurgeUserCommand{UserID: user.ID()}); err != nil {
+				h.logger.Error("failed to purge deleted user", "user_id", user.ID(), "error", err)
+				failed++
+				continue
+			}
+			purged++
+		}
+
+		if len(users) < h.batchSize {
+			break
+		}
+		afterID = users[len(users)-1].ID()
+	}
+
+	if purged > 0 || failed > 0 {
+		h.logger.Info("deleted users purged", "count", purged, "failed", failed)
+	}
+	if failed > 0 {
+		h.metrics.IncrementCounter("purge_deleted_users_failure")
+	}
+	return purged, ctx.Err()
+}
+
+// clearUserCache 清除用户详情和列表缓存，缓存支持标签时按标签失效
+func clearUserCache(ctx context.Context, cache output.Cache, logger output.Logger, userID string) {
+	if tc, ok := cache.(output.TaggedCache); ok {
+		if err := tc.InvalidateTags(ctx, output.UserCacheTag(userID), output.TagUsersList); err != nil {
+			logger.Error("failed to clear user cache", "user_id", userID, "error", err)
+		}
+		return
+	}
+
+	keys := []string{
+		fmt.Sprintf("user:id:%s", userID),
+		fmt.Sprintf("user:%s", userID),
+	}
+
+	listKeys, err := cache.Keys(ctx, "users:*")
+	if err != nil {
+		logger.Error("failed to list user cache keys", "error", err)
+	}
+	keys = append(keys, listKeys...)
+
+	if err := cache.DeleteMulti(ctx, keys); err != nil {
+		logger.Error("failed to clear user cache", "user_id", userID, "error", err)
+	}
+}
@@ -0,0 +1,70 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cmdbus "github.com/gohex/gohex/internal/application/port/input/command"
+	"github.com/gohex/gohex/internal/domain/aggregate"
+	"github.com/gohex/gohex/internal/domain/vo"
+	"github.com/gohex/gohex/internal/testutil"
+)
+
+// purgeBus 记录分发的永久删除命令，failing 中的用户始终失败
+type purgeBus struct {
+	cmdbus.Bus
+	failing    map[string]bool
+	dispatched []string
+}
+
+func (b *purgeBus) Dispatch(ctx context.Context, cmd interface{}) (interface{}, error) {
+	id := cmd.(*PurgeUserCommand).UserID
+	b.dispatched = append(b.dispatched, id)
+	if b.failing[id] {
+		return nil, errors.New("purge failed")
+	}
+	return nil, nil
+}
+
+func deletedUser(t *testing.T, id string) *aggregate.User {
+	t.Helper()
+	u := testutil.NewUser(id, vo.StatusActive, vo.RoleUser)
+	require.NoError(t, u.Delete("admin"))
+	return u
+}
+
+func TestPurgeDeletedUsersHandler_SkipsFailingUsers(t *testing.T) {
+	users := testutil.NewUserRepository(
+		deletedUser(t, "u1"), deletedUser(t, "u2"), deletedUser(t, "u3"),
+		deletedUser(t, "u4"), deletedUser(t, "u5"),
+		testutil.NewUser("active", vo.StatusActive, vo.RoleUser),
+	)
+	bus := &purgeBus{failing: map[string]bool{"u1": true, "u2": true}}
+	h := NewPurgeDeletedUsersHandler(users, bus, time.Nanosecond, 2, testutil.NopLogger{}, testutil.NewMetrics())
+
+	time.Sleep(time.Millisecond)
+	purged, err := h.Handle(context.Background(), &PurgeDeletedUsersCommand{})
+	require.NoError(t, err)
+
+	// 第一批全部失败时后续批次照常处理，失败的用户留到下次执行
+	assert.Equal(t, []string{"u1", "u2", "u3", "u4", "u5"}, bus.dispatched)
+	assert.Equal(t, int64(3), purged)
+}
+
+func TestPurgeDeletedUsersHandler_KeepsUsersWithinGracePeriod(t *testing.T) {
+	users := testutil.NewUserRepository(deletedUser(t, "u1"))
+	bus := &purgeBus{}
+
+	for _, grace := range []time.Duration{0, time.Hour} {
+		h := NewPurgeDeletedUsersHandler(users, bus, grace, 10, testutil.NopLogger{}, testutil.NewMetrics())
+		purged, err := h.Handle(context.Background(), &PurgeDeletedUsersCommand{})
+		require.NoError(t, err)
+		assert.Zero(t, purged)
+	}
+	assert.Empty(t, bus.dispatched)
+}
@@ -0,0 +1,382 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/aggregate"
+	"github.com/gohex/gohex/pkg/crypto"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+// emailVerificationTokenBytes 验证令牌的随机字节数
+const emailVerificationTokenBytes = 32
+
+// SendVerificationEmailCommand 生成验证令牌并发送验证邮件，由注册流程 saga 分发。
+// 只携带用户 ID，邮箱在处理时读取，saga 数据中不保存个人信息
+type SendVerificationEmailCommand struct {
+	UserID string `validate:"required"`
+}
+
+type SendVerificationEmailHandler struct {
+	userRepo         output.UserRepository
+	verificationRepo output.EmailVerificationRepository
+	emailSvc         output.EmailService
+	scheduler        output.CommandScheduler
+	ttl              time.Duration
+	// reminderAfter 发送后多久仍未验证时发送提醒，不大于 0 或不短于 ttl 时不提醒
+	reminderAfter time.Duration
+	logger        output.Logger
+	metrics       output.MetricsReporter
+}
+
+func NewSendVerificationEmailHandler(
+	userRepo output.UserRepository,
+	verificationRepo output.EmailVerificationRepository,
+	emailSvc output.EmailService,
+	scheduler output.CommandScheduler,
+	ttl time.Duration,
+	reminderAfter time.Duration,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *SendVerificationEmailHandler {
+	return &SendVerificationEmailHandler{
+		userRepo:         userRepo,
+		verificationRepo: verificationRepo,
+		emailSvc:         emailSvc,
+		scheduler:        scheduler,
+		ttl:              ttl,
+		reminderAfter:    reminderAfter,
+		logger:           logger,
+		metrics:          metrics,
+	}
+}
+
+func (h *SendVerificationEmailHandler) Handle(ctx context.Context, sendCmd *SendVerificationEmailCommand) (struct{}, error) {
+	// 1. 读取当前邮箱，已擦除的用户不再发送
+	user, err := h.userRepo.FindByID(ctx, sendCmd.UserID)
+	if err != nil {
+		return struct{}{}, err
+	}
+	if user.IsErased() {
+		h.logger.Info("user erased, verification email skipped", "user_id", sendCmd.UserID)
+		return struct{}{}, nil
+	}
+	email := user.Email().String()
+
+	// 2. 生成一次性令牌，只保存摘要
+	token, err := crypto.RandomToken(emailVerificationTokenBytes)
+	if err != nil {
+		return struct{}{}, err
+	}
+
+	now := time.Now()
+	verification := &output.EmailVerification{
+		ID:        uuid.New().String(),
+		UserID:    sendCmd.UserID,
+		Email:     email,
+		TokenHash: crypto.HashToken(token),
+		CreatedAt: now,
+		ExpiresAt: now.Add(h.ttl),
+	}
+
+	// 3. 保存记录，覆盖该用户之前的验证链接
+	if err := h.verificationRepo.Save(ctx, verification); err != nil {
+		return struct{}{}, err
+	}
+
+	// 4. 发送验证邮件
+	if err := h.emailSvc.SendVerificationEmail(email, token); err != nil {
+		h.logger.Error("failed to send verification email", "user_id", sendCmd.UserID, "error", err)
+		return struct{}{}, err
+	}
+
+	h.metrics.IncrementCounter("email_verification_sent")
+
+	// 5. 安排未验证提醒，提醒只针对本次发送的记录，重新发送或完成验证后自动失效
+	if h.reminderAfter > 0 && h.reminderAfter < h.ttl {
+		reminder := &SendVerificationReminderCommand{UserID: sendCmd.UserID, VerificationID: verification.ID}
+		if _, err := h.scheduler.Schedule(ctx, reminder, now.Add(h.reminderAfter)); err != nil {
+			h.logger.Error("failed to schedule verification reminder", "user_id", sendCmd.UserID, "error", err)
+		}
+	}
+	return struct{}{}, nil
+}
+
+// SendVerificationReminderCommand 提醒尚未验证邮箱的用户，由 SendVerificationEmailHandler 安排
+type SendVerificationReminderCommand struct {
+	UserID         string `validate:"required"`
+	VerificationID string `validate:"required"`
+}
+
+type SendVerificationReminderHandler struct {
+	userRepo         output.UserRepository
+	verificationRepo output.EmailVerificationRepository
+	emailSvc         output.EmailService
+	logger           output.Logger
+	metrics          output.MetricsReporter
+}
+
+func NewSendVerificationReminderHandler(
+	userRepo output.UserRepository,
+	verificationRepo output.EmailVerificationRepository,
+	emailSvc output.EmailService,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *SendVerificationReminderHandler {
+	return &SendVerificationReminderHandler{
+		userRepo:         userRepo,
+		verificationRepo: verificationRepo,
+		emailSvc:         emailSvc,
+		logger:           logger,
+		metrics:          metrics,
+	}
+}
+
+func (h *SendVerificationReminderHandler) Handle(ctx context.Context, remindCmd *SendVerificationReminderCommand) (struct{}, error) {
+	// 1. 已完成验证或已重新发送时记录不存在，不再提醒
+	verification, err := h.verificationRepo.FindByID(ctx, remindCmd.VerificationID)
+	if errors.Is(err, errors.ErrEmailVerificationNotFound) {
+		return struct{}{}, nil
+	}
+	if err != nil {
+		return struct{}{}, err
+	}
+	if verification.IsExpired(time.Now()) {
+		return struct{}{}, nil
+	}
+
+	// 2. 已擦除或邮箱已变更的用户不再提醒
+	user, err := h.userRepo.FindByID(ctx, remindCmd.UserID)
+	if err != nil {
+		return struct{}{}, err
+	}
+	if user.IsErased() || user.Email().String() != verification.Email {
+		h.logger.Info("verification reminder skipped", "user_id", remindCmd.UserID)
+		return struct{}{}, nil
+	}
+
+	// 3. 原令牌只保存了摘要，提醒邮件附带新令牌，有效期不变
+	token, err := crypto.RandomToken(emailVerificationTokenBytes)
+	if err != nil {
+		return struct{}{}, err
+	}
+	verification.TokenHash = crypto.HashToken(token)
+	if err := h.verificationRepo.Save(ctx, verification); err != nil {
+		return struct{}{}, err
+	}
+
+	if err := h.emailSvc.SendVerificationEmail(verification.Email, token); err != nil {
+		h.logger.Error("failed to send verification reminder", "user_id", remindCmd.UserID, "error", err)
+		return struct{}{}, err
+	}
+
+	h.metrics.IncrementCounter("email_verification_reminder_sent")
+	return struct{}{}, nil
+}
+
+// VerifyEmailCommand 通过邮件中的链接验证邮箱
+type VerifyEmailCommand struct {
+	Token string `validate:"required"`
+}
+
+type VerifyEmailHandler struct {
+	userRepo         output.UserRepository
+	verificationRepo output.EmailVerificationRepository
+	eventStore       output.EventStore
+	eventBus         output.EventBus
+	uow              output.UnitOfWork
+	logger           output.Logger
+	metrics          output.MetricsReporter
+}
+
+func NewVerifyEmailHandler(
+	userRepo output.UserRepository,
+	verificationRepo output.EmailVerificationRepository,
+	eventStore output.EventStore,
+	eventBus output.EventBus,
+	uow output.UnitOfWork,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *VerifyEmailHandler {
+	return &VerifyEmailHandler{
+		userRepo:         userRepo,
+		verificationRepo: verificationRepo,
+		eventStore:       eventStore,
+		eventBus:         eventBus,
+		uow:              uow,
+		logger:           logger,
+		metrics:          metrics,
+	}
+}
+
+func (h *VerifyEmailHandler) Handle(ctx context.Context, verifyCmd *VerifyEmailCommand) (struct{}, error) {
+	// 1. 查找验证记录
+	verification, err := h.verificationRepo.FindByToken(ctx, crypto.HashToken(verifyCmd.Token))
+	if err != nil {
+		return struct{}{}, err
+	}
+	if verification.IsExpired(time.Now()) {
+		return struct{}{}, errors.ErrEmailVerificationExpired
+	}
+
+	var user *aggregate.User
+	err = h.uow.WithTransaction(ctx, func(ctx context.Context) error {
+		// 2. 验证邮箱，发送链接后邮箱已变更时拒绝
+		user, err = h.userRepo.FindByID(ctx, verification.UserID)
+		if err != nil {
+			return err
+		}
+		if err := user.VerifyEmail(verification.Email); err != nil {
+			return err
+		}
+
+		// 3. 保存用户和事件，用户版本与事件流保持一致
+		if err := h.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		if err := h.eventStore.SaveEvents(ctx, user.ID(), user.Events(), user.OriginalVersion()); err != nil {
+			return err
+		}
+
+		// 4. 删除验证记录，令牌只能使用一次
+		return h.verificationRepo.Delete(ctx, verification.ID)
+	})
+	if err != nil {
+		h.metrics.IncrementCounter("email_verification_failure")
+		return struct{}{}, err
+	}
+
+	// 5. 发布事件，注册流程 saga 据此进入下一步
+	for _, evt := range user.Events() {
+		if err := h.eventBus.Publish(ctx, evt); err != nil {
+			h.logger.Error("failed to publish email verified event", "user_id", user.ID(), "error", err)
+		}
+	}
+
+	h.logger.Info("email verified", "user_id", user.ID())
+	h.metrics.IncrementCounter("email_verification_success")
+	return struct{}{}, nil
+}
+
+// ProvisionUserCommand 在下游系统中开通用户
+type ProvisionUserCommand struct {
+	UserID string `validate:"required"`
+}
+
+type ProvisionUserHandler struct {
+	userRepo     output.UserRepository
+	provisioning output.ProvisioningService
+	logger       output.Logger
+	metrics      output.MetricsReporter
+}
+
+func NewProvisionUserHandler(
+	userRepo output.UserRepository,
+	provisioning output.ProvisioningService,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *ProvisionUserHandler {
+	return &ProvisionUserHandler{
+		userRepo:     userRepo,
+		provisioning: provisioning,
+		logger:       logger,
+		metrics:      metrics,
+	}
+}
+
+func (h *ProvisionUserHandler) Handle(ctx context.Context, provisionCmd *ProvisionUserCommand) (struct{}, error) {
+	user, err := h.userRepo.FindByID(ctx, provisionCmd.UserID)
+	if err != nil {
+		return struct{}{}, err
+	}
+	if user.IsErased() {
+		h.logger.Info("user erased, provisioning skipped", "user_id", provisionCmd.UserID)
+		return struct{}{}, nil
+	}
+
+	if err := h.provisioning.Provision(ctx, provisionCmd.UserID, user.Email().String()); err != nil {
+		h.logger.Error("failed to provision user", "user_id", provisionCmd.UserID, "error", err)
+		h.metrics.IncrementCounter("user_provision_failure")
+		return struct{}{}, err
+	}
+
+	h.metrics.IncrementCounter("user_provision_success")
+	return struct{}{}, nil
+}
+
+// DeprovisionUserCommand 注销下游系统中的用户，用作开通步骤的补偿
+type DeprovisionUserCommand struct {
+	UserID string `validate:"required"`
+}
+
+type DeprovisionUserHandler struct {
+	provisioning output.ProvisioningService
+	logger       output.Logger
+	metrics      output.MetricsReporter
+}
+
+func NewDeprovisionUserHandler(provisioning output.ProvisioningService, logger output.Logger, metrics output.MetricsReporter) *DeprovisionUserHandler {
+	return &DeprovisionUserHandler{
+		provisioning: provisioning,
+		logger:       logger,
+		metrics:      metrics,
+	}
+}
+
+func (h *DeprovisionUserHandler) Handle(ctx context.Context, deprovisionCmd *DeprovisionUserCommand) (struct{}, error) {
+	if err := h.provisioning.Deprovision(ctx, deprovisionCmd.UserID); err != nil {
+		h.logger.Error("failed to deprovision user", "user_id", deprovisionCmd.UserID, "error", err)
+		return struct{}{}, err
+	}
+
+	h.metrics.IncrementCounter("user_deprovisioned")
+	return struct{}{}, nil
+}
+
+// SendWelcomeEmailCommand 发送欢迎邮件
+type SendWelcomeEmailCommand struct {
+	UserID string `validate:"required"`
+}
+
+type SendWelcomeEmailHandler struct {
+	userRepo output.UserRepository
+	emailSvc output.EmailService
+	logger   output.Logger
+	metrics  output.MetricsReporter
+}
+
+func NewSendWelcomeEmailHandler(
+	userRepo output.UserRepository,
+	emailSvc output.EmailService,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *SendWelcomeEmailHandler {
+	return &SendWelcomeEmailHandler{
+		userRepo: userRepo,
+		emailSvc: emailSvc,
+		logger:   logger,
+		metrics:  metrics,
+	}
+}
+
+func (h *SendWelcomeEmailHandler) Handle(ctx context.Context, welcomeCmd *SendWelcomeEmailCommand) (struct{}, error) {
+	user, err := h.userRepo.FindByID(ctx, welcomeCmd.UserID)
+	if err != nil {
+		return struct{}{}, err
+	}
+	if user.IsErased() {
+		h.logger.Info("user erased, welcome email skipped", "user_id", welcomeCmd.UserID)
+		return struct{}{}, nil
+	}
+
+	if err := h.emailSvc.SendWelcomeEmail(user.Email().String(), user.Profile().Name()); err != nil {
+		h.logger.Error("failed to send welcome email", "user_id", welcomeCmd.UserID, "error", err)
+		return struct{}{}, err
+	}
+
+	h.metrics.IncrementCounter("welcome_email_sent")
+	return struct{}{}, nil
+}
@@ -0,0 +1,241 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/event"
+	"github.com/gohex/gohex/internal/domain/vo"
+	"github.com/gohex/gohex/internal/testutil"
+	"github.com/gohex/gohex/pkg/crypto"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+// verificationRepository 内存邮箱验证仓储，每个用户只保留最新一条
+type verificationRepository struct {
+	byUser map[string]*output.EmailVerification
+}
+
+func (r *verificationRepository) Save(ctx context.Context, v *output.EmailVerification) error {
+	r.byUser[v.UserID] = v
+	return nil
+}
+
+func (r *verificationRepository) FindByID(ctx context.Context, id string) (*output.EmailVerification, error) {
+	for _, v := range r.byUser {
+		if v.ID == id {
+			copied := *v
+			return &copied, nil
+		}
+	}
+	return nil, errors.ErrEmailVerificationNotFound
+}
+
+func (r *verificationRepository) FindByToken(ctx context.Context, tokenHash string) (*output.EmailVerification, error) {
+	for _, v := range r.byUser {
+		if v.TokenHash == tokenHash {
+			return v, nil
+		}
+	}
+	return nil, errors.ErrEmailVerificationNotFound
+}
+
+func (r *verificationRepository) Delete(ctx context.Context, id string) error {
+	for userID, v := range r.byUser {
+		if v.ID == id {
+			delete(r.byUser, userID)
+		}
+	}
+	return nil
+}
+
+// verificationMailer 记录发出的验证令牌
+type verificationMailer struct {
+	output.EmailService
+	tokens []string
+}
+
+func (m *verificationMailer) SendVerificationEmail(email string, token string) error {
+	m.tokens = append(m.tokens, token)
+	return nil
+}
+
+func TestSendVerificationEmailHandler_StoresOnlyHash(t *testing.T) {
+	repo := &verificationRepository{byUser: make(map[string]*output.EmailVerification)}
+	mailer := &verificationMailer{}
+	scheduler := &commandScheduler{}
+	users := testutil.NewUserRepository(testutil.NewUser("alice", vo.StatusActive, vo.RoleUser))
+	h := NewSendVerificationEmailHandler(users, repo, mailer, scheduler, time.Hour, 0, testutil.NopLogger{}, testutil.NewMetrics())
+
+	for i := 0; i < 2; i++ {
+		_, err := h.Handle(context.Background(), &SendVerificationEmailCommand{UserID: "alice"})
+		require.NoError(t, err)
+	}
+
+	require.Len(t, mailer.tokens, 2)
+	require.Len(t, repo.byUser, 1)
+	v := repo.byUser["alice"]
+	// 重新发送后旧链接失效
+	assert.Equal(t, crypto.HashToken(mailer.tokens[1]), v.TokenHash)
+	assert.NotEqual(t, mailer.tokens[1], v.TokenHash)
+	assert.Equal(t, "alice@example.com", v.Email)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), v.ExpiresAt, time.Minute)
+	assert.Empty(t, scheduler.scheduled)
+}
+
+func TestSendVerificationReminderHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		resend     bool
+		verify     bool
+		wantTokens int
+	}{
+		{name: "reminds unverified user", wantTokens: 2},
+		{name: "skips replaced verification", resend: true, wantTokens: 2},
+		{name: "skips verified user", verify: true, wantTokens: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := &verificationRepository{byUser: make(map[string]*output.EmailVerification)}
+			mailer := &verificationMailer{}
+			scheduler := &commandScheduler{}
+			users := testutil.NewUserRepository(testutil.NewUser("alice", vo.StatusActive, vo.RoleUser))
+			send := NewSendVerificationEmailHandler(users, repo, mailer, scheduler, 72*time.Hour, 24*time.Hour,
+				testutil.NopLogger{}, testutil.NewMetrics())
+
+			_, err := send.Handle(ctx, &SendVerificationEmailCommand{UserID: "alice"})
+			require.NoError(t, err)
+			require.Len(t, scheduler.scheduled, 1)
+			reminder := scheduler.scheduled[0].command.(*SendVerificationReminderCommand)
+			assert.Equal(t, repo.byUser["alice"].ID, reminder.VerificationID)
+			assert.WithinDuration(t, time.Now().Add(24*time.Hour), scheduler.scheduled[0].runAt, time.Minute)
+
+			if tt.resend {
+				_, err := send.Handle(ctx, &SendVerificationEmailCommand{UserID: "alice"})
+				require.NoError(t, err)
+			}
+			if tt.verify {
+				require.NoError(t, repo.Delete(ctx, reminder.VerificationID))
+			}
+
+			h := NewSendVerificationReminderHandler(users, repo, mailer, testutil.NopLogger{}, testutil.NewMetrics())
+			_, err = h.Handle(ctx, reminder)
+			require.NoError(t, err)
+
+			require.Len(t, mailer.tokens, tt.wantTokens)
+			if v, ok := repo.byUser["alice"]; ok {
+				// 只有最后发出的链接有效
+				assert.Equal(t, crypto.HashToken(mailer.tokens[len(mailer.tokens)-1]), v.TokenHash)
+			}
+		})
+	}
+}
+
+// welcomeMailer 记录欢迎邮件的收件人和姓名
+type welcomeMailer struct {
+	output.EmailService
+	sent []string
+}
+
+func (m *welcomeMailer) SendWelcomeEmail(email string, name string) error {
+	m.sent = append(m.sent, name+" <"+email+">")
+	return nil
+}
+
+func TestSendWelcomeEmailHandler_ReadsCurrentUser(t *testing.T) {
+	ctx := context.Background()
+	bob := testutil.NewUser("bob", vo.StatusActive, vo.RoleUser)
+	require.NoError(t, bob.Erase("admin"))
+	users := testutil.NewUserRepository(testutil.NewUser("alice", vo.StatusActive, vo.RoleUser), bob)
+	mailer := &welcomeMailer{}
+	h := NewSendWelcomeEmailHandler(users, mailer, testutil.NopLogger{}, testutil.NewMetrics())
+
+	_, err := h.Handle(ctx, &SendWelcomeEmailCommand{UserID: "alice"})
+	require.NoError(t, err)
+	// saga 期间被擦除的用户不再收到邮件
+	_, err = h.Handle(ctx, &SendWelcomeEmailCommand{UserID: "bob"})
+	require.NoError(t, err)
+
+	user, err := users.FindByID(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, []string{user.Profile().Name() + " <alice@example.com>"}, mailer.sent)
+}
+
+func TestVerifyEmailHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		email       string
+		expiresIn   time.Duration
+		wantErr     error
+		wantEvent   bool
+		wantDeleted bool
+	}{
+		{name: "verifies email", email: "alice@example.com", expiresIn: time.Hour, wantEvent: true, wantDeleted: true},
+		{name: "expired link", email: "alice@example.com", expiresIn: -time.Minute, wantErr: errors.ErrEmailVerificationExpired},
+		{name: "email changed since link was sent", email: "alice.old@example.com", expiresIn: time.Hour, wantErr: errors.ErrEmailVerificationStale},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &verificationRepository{byUser: map[string]*output.EmailVerification{
+				"alice": {
+					ID:        "v1",
+					UserID:    "alice",
+					Email:     tt.email,
+					TokenHash: crypto.HashToken("token"),
+					ExpiresAt: time.Now().Add(tt.expiresIn),
+				},
+			}}
+			users := testutil.NewUserRepository(testutil.NewUser("alice", vo.StatusActive, vo.RoleUser))
+			events := testutil.NewEventStore()
+			bus := &testutil.EventBus{}
+			h := NewVerifyEmailHandler(users, repo, events, bus, testutil.UnitOfWork{},
+				testutil.NopLogger{}, testutil.NewMetrics())
+
+			_, err := h.Handle(context.Background(), &VerifyEmailCommand{Token: "token"})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.wantDeleted, repo.byUser["alice"] == nil)
+			user, err := users.FindByID(context.Background(), "alice")
+			require.NoError(t, err)
+			if tt.wantEvent {
+				// 用户与事件一起保存，版本与事件流一致
+				assert.Equal(t, 2, user.Version())
+				assert.Equal(t, []string{event.UserEmailVerified}, events.Types("alice"))
+				require.Len(t, bus.Published, 1)
+				assert.Equal(t, event.UserEmailVerified, bus.Published[0].Type())
+			} else {
+				assert.Equal(t, 1, user.Version())
+				assert.Empty(t, events.Types("alice"))
+				assert.Empty(t, bus.Published)
+			}
+		})
+	}
+}
+
+func TestVerifyEmailHandler_TokenSingleUse(t *testing.T) {
+	repo := &verificationRepository{byUser: map[string]*output.EmailVerification{
+		"alice": {ID: "v1", UserID: "alice", Email: "alice@example.com", TokenHash: crypto.HashToken("token"), ExpiresAt: time.Now().Add(time.Hour)},
+	}}
+	h := NewVerifyEmailHandler(
+		testutil.NewUserRepository(testutil.NewUser("alice", vo.StatusActive, vo.RoleUser)),
+		repo, testutil.NewEventStore(), &testutil.EventBus{}, testutil.UnitOfWork{}, testutil.NopLogger{}, testutil.NewMetrics(),
+	)
+
+	_, err := h.Handle(context.Background(), &VerifyEmailCommand{Token: "token"})
+	require.NoError(t, err)
+
+	_, err = h.Handle(context.Background(), &VerifyEmailCommand{Token: "token"})
+	assert.ErrorIs(t, err, errors.ErrEmailVerificationNotFound)
+}
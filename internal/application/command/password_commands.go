@@ -3,7 +3,9 @@ package command
 import (
 	"context"
 	"github.com/gohex/gohex/internal/domain/vo"
+	"github.com/gohex/gohex/pkg/actor"
 	"github.com/gohex/gohex/pkg/errors"
+	"github.com/gohex/gohex/internal/application/port/output"
 )
 
 // ChangePasswordCommand 修改密码命令
@@ -13,18 +15,40 @@ type ChangePasswordCommand struct {
 	NewPassword     string
 }
 
+// CredentialCommand 密码不参与幂等指纹
+func (*ChangePasswordCommand) CredentialCommand() {}
+
 type ChangePasswordHandler struct {
-	userRepo   port.UserRepository
-	eventStore port.EventStore
-	uow        port.UnitOfWork
-	logger     Logger
-	metrics    MetricsReporter
+	userRepo   output.UserRepository
+	eventStore output.EventStore
+	uow        output.UnitOfWork
+	logger     output.Logger
+	metrics    output.MetricsReporter
+}
+
+func NewChangePasswordHandler(
+	userRepo output.UserRepository,
+	eventStore output.EventStore,
+	uow output.UnitOfWork,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *ChangePasswordHandler {
+	return &ChangePasswordHandler{
+		userRepo:   userRepo,
+		eventStore: eventStore,
+		uow:        uow,
+		logger:     logger,
+		metrics:    metrics,
+	}
 }
 
-func (h *ChangePasswordHandler) Handle(ctx context.Context, cmd interface{}) (interface{}, error) {
-	changeCmd := cmd.(*ChangePasswordCommand)
+func (h *ChangePasswordHandler) Handle(ctx context.Context, changeCmd *ChangePasswordCommand) (struct{}, error) {
+	// 模拟会话中禁止修改密码
+	if actor.IsImpersonated(ctx) {
+		return struct{}{}, errors.ErrImpersonationForbidden
+	}
 
-	return nil, h.uow.WithTransaction(ctx, func(ctx context.Context) error {
+	return struct{}{}, h.uow.WithTransaction(ctx, func(ctx context.Context) error {
 		// 1. 获取用户
 		user, err := h.userRepo.FindByID(ctx, changeCmd.UserID)
 		if err != nil {
@@ -53,7 +77,7 @@ func (h *ChangePasswordHandler) Handle(ctx context.Context, cmd interface{}) (in
 		}
 
 		// 5. 保存事件
-		return h.eventStore.SaveEvents(ctx, user.ID(), user.Events(), user.Version())
+		return h.eventStore.SaveEvents(ctx, user.ID(), user.Events(), user.OriginalVersion())
 	})
 }
 
@@ -63,18 +87,39 @@ type ResetPasswordCommand struct {
 	NewPassword string
 }
 
+// CredentialCommand 密码不参与幂等指纹
+func (*ResetPasswordCommand) CredentialCommand() {}
+
 type ResetPasswordHandler struct {
-	userRepo   port.UserRepository
-	eventStore port.EventStore
-	uow        port.UnitOfWork
-	logger     Logger
-	metrics    MetricsReporter
+	userRepo   output.UserRepository
+	eventStore output.EventStore
+	uow        output.UnitOfWork
+	logger     output.Logger
+	metrics    output.MetricsReporter
 }
 
-func (h *ResetPasswordHandler) Handle(ctx context.Context, cmd interface{}) (interface{}, error) {
-	resetCmd := cmd.(*ResetPasswordCommand)
+func NewResetPasswordHandler(
+	userRepo output.UserRepository,
+	eventStore output.EventStore,
+	uow output.UnitOfWork,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *ResetPasswordHandler {
+	return &ResetPasswordHandler{
+		userRepo:   userRepo,
+		eventStore: eventStore,
+		uow:        uow,
+		logger:     logger,
+		metrics:    metrics,
+	}
+}
+
+func (h *ResetPasswordHandler) Handle(ctx context.Context, resetCmd *ResetPasswordCommand) (struct{}, error) {
+	if actor.IsImpersonated(ctx) {
+		return struct{}{}, errors.ErrImpersonationForbidden
+	}
 
-	return nil, h.uow.WithTransaction(ctx, func(ctx context.Context) error {
+	return struct{}{}, h.uow.WithTransaction(ctx, func(ctx context.Context) error {
 		// 1. 获取用户
 		user, err := h.userRepo.FindByID(ctx, resetCmd.UserID)
 		if err != nil {
@@ -98,7 +143,7 @@ func (h *ResetPasswordHandler) Handle(ctx context.Context, cmd interface{}) (int
 		}
 
 		// 5. 保存事件
-		return h.eventStore.SaveEvents(ctx, user.ID(), user.Events(), user.Version())
+		return h.eventStore.SaveEvents(ctx, user.ID(), user.Events(), user.OriginalVersion())
 	})
 }
 
@@ -108,42 +153,56 @@ type RequestPasswordResetCommand struct {
 }
 
 type RequestPasswordResetHandler struct {
-	userRepo   port.UserRepository
-	tokenSvc   port.TokenService
-	emailSvc   port.EmailService
-	logger     Logger
-	metrics    MetricsReporter
+	userRepo   output.UserRepository
+	tokenSvc   output.TokenService
+	emailSvc   output.EmailService
+	logger     output.Logger
+	metrics    output.MetricsReporter
 }
 
-func (h *RequestPasswordResetHandler) Handle(ctx context.Context, cmd interface{}) (interface{}, error) {
-	resetCmd := cmd.(*RequestPasswordResetCommand)
+func NewRequestPasswordResetHandler(
+	userRepo output.UserRepository,
+	tokenSvc output.TokenService,
+	emailSvc output.EmailService,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *RequestPasswordResetHandler {
+	return &RequestPasswordResetHandler{
+		userRepo: userRepo,
+		tokenSvc: tokenSvc,
+		emailSvc: emailSvc,
+		logger:   logger,
+		metrics:  metrics,
+	}
+}
 
+func (h *RequestPasswordResetHandler) Handle(ctx context.Context, resetCmd *RequestPasswordResetCommand) (struct{}, error) {
 	// 1. 查找用户
 	email, err := vo.NewEmail(resetCmd.Email)
 	if err != nil {
-		return nil, err
+		return struct{}{}, err
 	}
 
 	user, err := h.userRepo.FindByEmail(ctx, email)
 	if err != nil {
 		if err == errors.ErrUserNotFound {
 			// 为了安全，即使用户不存在也返回成功
-			return nil, nil
+			return struct{}{}, nil
 		}
-		return nil, err
+		return struct{}{}, err
 	}
 
 	// 2. 生成重置令牌
 	token, err := h.tokenSvc.GeneratePasswordResetToken(user)
 	if err != nil {
-		return nil, err
+		return struct{}{}, err
 	}
 
 	// 3. 发送重置邮件
 	if err := h.emailSvc.SendPasswordResetEmail(user.Email().String(), token); err != nil {
 		h.logger.Error("failed to send password reset email", "error", err)
-		return nil, err
+		return struct{}{}, err
 	}
 
-	return nil, nil
+	return struct{}{}, nil
 } 
\ No newline at end of file
@@ -4,14 +4,18 @@ import (
 	"context"
 
 	"github.com/gohex/gohex/internal/domain/aggregate"
+	"github.com/gohex/gohex/internal/domain/event"
 	"github.com/gohex/gohex/internal/domain/vo"
-	"github.com/gohex/gohex/internal/application/port"
+	"github.com/gohex/gohex/internal/application/port/output"
+	cmdbus "github.com/gohex/gohex/internal/application/port/input/command"
 	"github.com/gohex/gohex/pkg/errors"
+	"github.com/gohex/gohex/pkg/tracer"
 )
 
 type RegisterUserCommand struct {
-	Email    string
-	Password string
+	Email string
+	// Password 不计入幂等指纹，重试时只按邮箱和资料判断是否为同一请求
+	Password string `idempotency:"-"`
 	Name     string
 	Bio      string
 }
@@ -20,22 +24,25 @@ type RegisterUserResult struct {
 	ID string
 }
 
+// RegisterUserHandler 强类型处理器，通过 command.RegisterHandler 注册
 type RegisterUserHandler struct {
-	userRepo   port.UserRepository
-	eventStore port.EventStore
-	eventBus   port.EventBus
-	uow        port.UnitOfWork
-	logger     Logger
-	metrics    MetricsReporter
+	userRepo   output.UserRepository
+	eventStore output.EventStore
+	eventBus   output.EventBus
+	uow        output.UnitOfWork
+	logger     output.Logger
+	metrics    output.MetricsReporter
 }
 
+var _ cmdbus.TypedHandler[RegisterUserCommand, RegisterUserResult] = (*RegisterUserHandler)(nil)
+
 func NewRegisterUserHandler(
-	userRepo port.UserRepository,
-	eventStore port.EventStore,
-	eventBus port.EventBus,
-	uow port.UnitOfWork,
-	logger Logger,
-	metrics MetricsReporter,
+	userRepo output.UserRepository,
+	eventStore output.EventStore,
+	eventBus output.EventBus,
+	uow output.UnitOfWork,
+	logger output.Logger,
+	metrics output.MetricsReporter,
 ) *RegisterUserHandler {
 	return &RegisterUserHandler{
 		userRepo:   userRepo,
@@ -54,7 +61,10 @@ func (h *RegisterUserHandler) Handle(ctx context.Context, cmd RegisterUserComman
 	timer := h.metrics.StartTimer("register_user_duration")
 	defer timer.Stop()
 
-	var result RegisterUserResult
+	var (
+		result RegisterUserResult
+		events []event.Event
+	)
 
 	err := h.uow.WithTransaction(ctx, func(ctx context.Context) error {
 		// 1. 创建值对象
@@ -79,7 +89,7 @@ func (h *RegisterUserHandler) Handle(ctx context.Context, cmd RegisterUserComman
 			return err
 		}
 		if exists {
-			return ErrEmailAlreadyExists
+			return errors.ErrEmailAlreadyExists
 		}
 
 		// 3. 创建用户聚合根
@@ -98,14 +108,8 @@ func (h *RegisterUserHandler) Handle(ctx context.Context, cmd RegisterUserComman
 			return err
 		}
 
-		// 6. 发布事件
-		for _, event := range user.Events() {
-			if err := h.eventBus.Publish(ctx, event); err != nil {
-				return err
-			}
-		}
-
 		result.ID = user.ID()
+		events = user.Events()
 		return nil
 	})
 
@@ -115,6 +119,13 @@ func (h *RegisterUserHandler) Handle(ctx context.Context, cmd RegisterUserComman
 		return result, err
 	}
 
+	// 6. 提交后发布事件，处理器读到的是已提交的用户，发布失败不影响注册结果
+	for _, evt := range events {
+		if err := h.eventBus.Publish(ctx, evt); err != nil {
+			h.logger.Error("failed to publish user registered event", "user_id", result.ID, "error", err)
+		}
+	}
+
 	h.metrics.IncrementCounter("register_user_success")
 	return result, nil
 } 
\ No newline at end of file
@@ -1,8 +1,13 @@
 package command
 
 import (
+	"context"
+
+	"github.com/gohex/gohex/internal/domain/aggregate"
 	"github.com/gohex/gohex/internal/domain/vo"
 	"github.com/gohex/gohex/pkg/errors"
+	"github.com/gohex/gohex/internal/application/port/output"
+	cmdbus "github.com/gohex/gohex/internal/application/port/input/command"
 )
 
 // AssignRoleCommand 分配角色命令
@@ -12,19 +17,38 @@ type AssignRoleCommand struct {
 }
 
 type AssignRoleHandler struct {
-	userRepo   port.UserRepository
-	eventStore port.EventStore
-	uow        port.UnitOfWork
-	logger     Logger
-	metrics    MetricsReporter
+	userRepo   output.UserRepository
+	eventStore output.EventStore
+	eventBus   output.EventBus
+	uow        output.UnitOfWork
+	logger     output.Logger
+	metrics    output.MetricsReporter
 }
 
-func (h *AssignRoleHandler) Handle(ctx context.Context, cmd interface{}) (interface{}, error) {
-	assignCmd := cmd.(*AssignRoleCommand)
+func NewAssignRoleHandler(
+	userRepo output.UserRepository,
+	eventStore output.EventStore,
+	eventBus output.EventBus,
+	uow output.UnitOfWork,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *AssignRoleHandler {
+	return &AssignRoleHandler{
+		userRepo:   userRepo,
+		eventStore: eventStore,
+		eventBus:   eventBus,
+		uow:        uow,
+		logger:     logger,
+		metrics:    metrics,
+	}
+}
 
-	return nil, h.uow.WithTransaction(ctx, func(ctx context.Context) error {
+func (h *AssignRoleHandler) Handle(ctx context.Context, assignCmd *AssignRoleCommand) (struct{}, error) {
+	var user *aggregate.User
+	err := h.uow.WithTransaction(ctx, func(ctx context.Context) error {
 		// 1. 获取用户
-		user, err := h.userRepo.FindByID(ctx, assignCmd.UserID)
+		var err error
+		user, err = h.userRepo.FindByID(ctx, assignCmd.UserID)
 		if err != nil {
 			return err
 		}
@@ -46,6 +70,89 @@ func (h *AssignRoleHandler) Handle(ctx context.Context, cmd interface{}) (interf
 		}
 
 		// 5. 保存事件
-		return h.eventStore.SaveEvents(ctx, user.ID(), user.Events(), user.Version())
+		return h.eventStore.SaveEvents(ctx, user.ID(), user.Events(), user.OriginalVersion())
 	})
-} 
\ No newline at end of file
+	if err != nil {
+		return struct{}{}, err
+	}
+
+	// 6. 提交后发布事件，缓存失效和 webhook 等订阅方据此更新
+	for _, evt := range user.Events() {
+		if err := h.eventBus.Publish(ctx, evt); err != nil {
+			h.logger.Error("failed to publish role assigned event", "user_id", user.ID(), "error", err)
+		}
+	}
+	return struct{}{}, nil
+} 
+// BulkAssignRoleCommand 批量分配角色，用户较多时通过 DispatchAsync 异步执行
+type BulkAssignRoleCommand struct {
+	UserIDs []string `validate:"required,min=1,max=10000,dive,required"`
+	Role    string   `validate:"required"`
+}
+
+// BulkAssignRoleResult 已拥有该角色的用户计入 Skipped，失败的用户按 ID 记录原因
+type BulkAssignRoleResult struct {
+	Assigned int               `json:"assigned"`
+	Skipped  int               `json:"skipped"`
+	Failed   map[string]string `json:"failed,omitempty"`
+}
+
+// BulkAssignRoleHandler 逐个用户分配角色，每个用户独立提交，任务重新执行时已分配的用户被跳过
+type BulkAssignRoleHandler struct {
+	assign  *AssignRoleHandler
+	logger  output.Logger
+	metrics output.MetricsReporter
+}
+
+func NewBulkAssignRoleHandler(
+	userRepo output.UserRepository,
+	eventStore output.EventStore,
+	eventBus output.EventBus,
+	uow output.UnitOfWork,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *BulkAssignRoleHandler {
+	return &BulkAssignRoleHandler{
+		assign:  NewAssignRoleHandler(userRepo, eventStore, eventBus, uow, logger, metrics),
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+func (h *BulkAssignRoleHandler) Handle(ctx context.Context, bulkCmd *BulkAssignRoleCommand) (*BulkAssignRoleResult, error) {
+	if !vo.UserRole(bulkCmd.Role).IsValid() {
+		return nil, errors.NewValidationError("invalid role")
+	}
+
+	result := &BulkAssignRoleResult{}
+	for i, userID := range bulkCmd.UserIDs {
+		// 任务被取消或实例退出时停止，已处理的用户保持已提交状态
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		_, err := h.assign.Handle(ctx, &AssignRoleCommand{UserID: userID, Role: bulkCmd.Role})
+		switch {
+		case errors.Is(err, errors.ErrRoleAlreadyAssigned):
+			result.Skipped++
+		case err != nil:
+			if result.Failed == nil {
+				result.Failed = make(map[string]string)
+			}
+			result.Failed[userID] = err.Error()
+		default:
+			result.Assigned++
+		}
+
+		cmdbus.ReportProgress(ctx, (i+1)*100/len(bulkCmd.UserIDs))
+	}
+
+	h.logger.Info("roles assigned in bulk",
+		"role", bulkCmd.Role,
+		"assigned", result.Assigned,
+		"skipped", result.Skipped,
+		"failed", len(result.Failed),
+	)
+	h.metrics.IncrementCounter("bulk_role_assigned", "role", bulkCmd.Role)
+	return result, nil
+}
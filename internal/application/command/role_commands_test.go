@@ -0,0 +1,122 @@
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cmdbus "github.com/gohex/gohex/internal/application/port/input/command"
+	"github.com/gohex/gohex/internal/domain/event"
+	"github.com/gohex/gohex/internal/domain/vo"
+	"github.com/gohex/gohex/internal/testutil"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+func newBulkAssignRoleHandler(users *testutil.UserRepository) *BulkAssignRoleHandler {
+	return NewBulkAssignRoleHandler(users, testutil.NewEventStore(), &testutil.EventBus{}, testutil.UnitOfWork{},
+		testutil.NopLogger{}, testutil.NewMetrics())
+}
+
+func TestAssignRoleHandler_PublishesAfterCommit(t *testing.T) {
+	tests := []struct {
+		name          string
+		role          string
+		wantErr       error
+		wantPublished []string
+	}{
+		{name: "assigns role", role: string(vo.RoleSupport), wantPublished: []string{event.RoleAssigned}},
+		{name: "role already assigned", role: string(vo.RoleUser), wantErr: errors.ErrRoleAlreadyAssigned},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := testutil.NewUserRepository(testutil.NewUser("alice", vo.StatusActive, vo.RoleUser))
+			bus := &testutil.EventBus{}
+			h := NewAssignRoleHandler(users, testutil.NewEventStore(), bus, testutil.UnitOfWork{},
+				testutil.NopLogger{}, testutil.NewMetrics())
+
+			_, err := h.Handle(context.Background(), &AssignRoleCommand{UserID: "alice", Role: tt.role})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantPublished, typesOf(bus.Published))
+		})
+	}
+}
+
+func TestBulkAssignRoleHandler(t *testing.T) {
+	users := testutil.NewUserRepository(
+		testutil.NewUser("alice", vo.StatusActive, vo.RoleUser),
+		testutil.NewUser("bob", vo.StatusActive, vo.RoleUser, vo.RoleSupport),
+		testutil.NewUser("carol", vo.StatusActive, vo.RoleUser),
+	)
+
+	var progress []int
+	ctx := cmdbus.WithProgress(context.Background(), func(percent int) {
+		progress = append(progress, percent)
+	})
+
+	result, err := newBulkAssignRoleHandler(users).Handle(ctx, &BulkAssignRoleCommand{
+		UserIDs: []string{"alice", "bob", "missing", "carol"},
+		Role:    string(vo.RoleSupport),
+	})
+	require.NoError(t, err)
+
+	bulk := result
+	assert.Equal(t, 2, bulk.Assigned)
+	assert.Equal(t, 1, bulk.Skipped)
+	assert.Contains(t, bulk.Failed, "missing")
+	assert.Equal(t, []int{25, 50, 75, 100}, progress)
+
+	for _, id := range []string{"alice", "bob", "carol"} {
+		user, err := users.FindByID(context.Background(), id)
+		require.NoError(t, err)
+		assert.True(t, user.HasRole(vo.RoleSupport), id)
+	}
+}
+
+func TestBulkAssignRoleHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     func() context.Context
+		role    string
+		wantErr error
+	}{
+		{
+			name:    "invalid role",
+			ctx:     context.Background,
+			role:    "owner",
+			wantErr: errors.NewValidationError("invalid role"),
+		},
+		{
+			name: "cancelled",
+			ctx: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx
+			},
+			role:    string(vo.RoleSupport),
+			wantErr: context.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := testutil.NewUserRepository(testutil.NewUser("alice", vo.StatusActive, vo.RoleUser))
+
+			_, err := newBulkAssignRoleHandler(users).Handle(tt.ctx(), &BulkAssignRoleCommand{
+				UserIDs: []string{"alice"},
+				Role:    tt.role,
+			})
+			assert.Equal(t, tt.wantErr.Error(), err.Error())
+
+			user, err := users.FindByID(context.Background(), "alice")
+			require.NoError(t, err)
+			assert.False(t, user.HasRole(vo.RoleSupport))
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package command
+
+import (
+	"context"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+)
+
+// CancelScheduledCommandCommand 取消尚未执行的一次性计划或停止周期计划
+type CancelScheduledCommandCommand struct {
+	ScheduleID string `validate:"required"`
+}
+
+type CancelScheduledCommandHandler struct {
+	store   output.ScheduleStore
+	logger  output.Logger
+	metrics output.MetricsReporter
+}
+
+func NewCancelScheduledCommandHandler(store output.ScheduleStore, logger output.Logger, metrics output.MetricsReporter) *CancelScheduledCommandHandler {
+	return &CancelScheduledCommandHandler{
+		store:   store,
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+func (h *CancelScheduledCommandHandler) Handle(ctx context.Context, cancelCmd *CancelScheduledCommandCommand) (struct{}, error) {
+	if err := h.store.Cancel(ctx, cancelCmd.ScheduleID); err != nil {
+		return struct{}{}, err
+	}
+
+	h.logger.Info("scheduled command cancelled", "schedule_id", cancelCmd.ScheduleID)
+	return struct{}{}, nil
+}
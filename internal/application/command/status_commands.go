@@ -2,8 +2,10 @@ package command
 
 import (
 	"context"
+	"github.com/gohex/gohex/internal/domain/aggregate"
 	"github.com/gohex/gohex/internal/domain/vo"
 	"github.com/gohex/gohex/pkg/errors"
+	"github.com/gohex/gohex/internal/application/port/output"
 )
 
 // ChangeUserStatusCommand 修改用户状态命令
@@ -13,19 +15,38 @@ type ChangeUserStatusCommand struct {
 }
 
 type ChangeUserStatusHandler struct {
-	userRepo   port.UserRepository
-	eventStore port.EventStore
-	uow        port.UnitOfWork
-	logger     Logger
-	metrics    MetricsReporter
+	userRepo   output.UserRepository
+	eventStore output.EventStore
+	eventBus   output.EventBus
+	uow        output.UnitOfWork
+	logger     output.Logger
+	metrics    output.MetricsReporter
 }
 
-func (h *ChangeUserStatusHandler) Handle(ctx context.Context, cmd interface{}) (interface{}, error) {
-	statusCmd := cmd.(*ChangeUserStatusCommand)
+func NewChangeUserStatusHandler(
+	userRepo output.UserRepository,
+	eventStore output.EventStore,
+	eventBus output.EventBus,
+	uow output.UnitOfWork,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *ChangeUserStatusHandler {
+	return &ChangeUserStatusHandler{
+		userRepo:   userRepo,
+		eventStore: eventStore,
+		eventBus:   eventBus,
+		uow:        uow,
+		logger:     logger,
+		metrics:    metrics,
+	}
+}
 
-	return nil, h.uow.WithTransaction(ctx, func(ctx context.Context) error {
+func (h *ChangeUserStatusHandler) Handle(ctx context.Context, statusCmd *ChangeUserStatusCommand) (struct{}, error) {
+	var user *aggregate.User
+	err := h.uow.WithTransaction(ctx, func(ctx context.Context) error {
 		// 1. 获取用户
-		user, err := h.userRepo.FindByID(ctx, statusCmd.UserID)
+		var err error
+		user, err = h.userRepo.FindByID(ctx, statusCmd.UserID)
 		if err != nil {
 			return err
 		}
@@ -47,6 +68,17 @@ func (h *ChangeUserStatusHandler) Handle(ctx context.Context, cmd interface{}) (
 		}
 
 		// 5. 保存事件
-		return h.eventStore.SaveEvents(ctx, user.ID(), user.Events(), user.Version())
+		return h.eventStore.SaveEvents(ctx, user.ID(), user.Events(), user.OriginalVersion())
 	})
+	if err != nil {
+		return struct{}{}, err
+	}
+
+	// 6. 提交后发布事件，缓存失效和 webhook 等订阅方据此更新
+	for _, evt := range user.Events() {
+		if err := h.eventBus.Publish(ctx, evt); err != nil {
+			h.logger.Error("failed to publish user status event", "user_id", user.ID(), "error", err)
+		}
+	}
+	return struct{}{}, nil
 } 
\ No newline at end of file
@@ -0,0 +1,52 @@
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/domain/event"
+	"github.com/gohex/gohex/internal/domain/vo"
+	"github.com/gohex/gohex/internal/testutil"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+func TestChangeUserStatusHandler(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        string
+		wantStatus    vo.UserStatus
+		wantErr       error
+		wantPublished []string
+	}{
+		{name: "deactivates user", status: "inactive", wantStatus: vo.StatusInactive,
+			wantPublished: []string{event.UserStatusChanged}},
+		{name: "same status", status: "active", wantStatus: vo.StatusActive},
+		{name: "deleted status is rejected", status: "deleted", wantStatus: vo.StatusActive,
+			wantErr: errors.ErrInvalidStatusTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := testutil.NewUserRepository(testutil.NewUser("alice", vo.StatusActive, vo.RoleUser))
+			bus := &testutil.EventBus{}
+			h := NewChangeUserStatusHandler(users, testutil.NewEventStore(), bus, testutil.UnitOfWork{},
+				testutil.NopLogger{}, testutil.NewMetrics())
+
+			_, err := h.Handle(context.Background(), &ChangeUserStatusCommand{UserID: "alice", Status: tt.status})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			user, err := users.FindByID(context.Background(), "alice")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, user.Status())
+			// 状态变更提交后发布，缓存失效处理器据此清除用户详情和列表
+			assert.Equal(t, tt.wantPublished, typesOf(bus.Published))
+		})
+	}
+}
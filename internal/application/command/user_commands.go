@@ -2,87 +2,11 @@ package command
 
 import (
 	"context"
-	"github.com/your-org/your-project/internal/domain/aggregate"
-	"github.com/your-org/your-project/internal/domain/vo"
+	"github.com/gohex/gohex/internal/domain/aggregate"
+	"github.com/gohex/gohex/internal/domain/vo"
+	"github.com/gohex/gohex/internal/application/port/output"
 )
 
-// RegisterUserCommand 注册用户命令
-type RegisterUserCommand struct {
-	Email    string
-	Password string
-	Name     string
-	Bio      string
-}
-
-type RegisterUserHandler struct {
-	userRepo   port.UserRepository
-	eventStore port.EventStore
-	eventBus   port.EventBus
-	uow        port.UnitOfWork
-	logger     Logger
-	metrics    MetricsReporter
-}
-
-func (h *RegisterUserHandler) Handle(ctx context.Context, cmd interface{}) (interface{}, error) {
-	registerCmd := cmd.(*RegisterUserCommand)
-	
-	var result struct {
-		ID string `json:"id"`
-	}
-
-	err := h.uow.WithTransaction(ctx, func(ctx context.Context) error {
-		// 1. 创建值对象
-		email, err := vo.NewEmail(registerCmd.Email)
-		if err != nil {
-			return err
-		}
-
-		password, err := vo.NewPassword(registerCmd.Password)
-		if err != nil {
-			return err
-		}
-
-		profile, err := vo.NewUserProfile(registerCmd.Name, registerCmd.Bio)
-		if err != nil {
-			return err
-		}
-
-		// 2. 检查邮箱是否已存在
-		exists, err := h.userRepo.ExistsByEmail(ctx, email)
-		if err != nil {
-			return err
-		}
-		if exists {
-			return errors.ErrEmailAlreadyExists
-		}
-
-		// 3. 创建用户聚合根
-		user, err := aggregate.NewUser(email, password, profile)
-		if err != nil {
-			return err
-		}
-
-		// 4. 保存用户
-		if err := h.userRepo.Save(ctx, user); err != nil {
-			return err
-		}
-
-		// 5. 保存事件
-		if err := h.eventStore.SaveEvents(ctx, user.ID(), user.Events(), 0); err != nil {
-			return err
-		}
-
-		result.ID = user.ID()
-		return nil
-	})
-
-	if err != nil {
-		return nil, err
-	}
-
-	return result, nil
-}
-
 // UpdateUserProfileCommand 更新用户资料命令
 type UpdateUserProfileCommand struct {
 	UserID   string `validate:"required"`
@@ -94,20 +18,41 @@ type UpdateUserProfileCommand struct {
 }
 
 type UpdateUserProfileHandler struct {
-	userRepo   port.UserRepository
-	eventStore port.EventStore
-	cache      port.Cache
-	uow        port.UnitOfWork
-	logger     Logger
-	metrics    MetricsReporter
+	userRepo   output.UserRepository
+	eventStore output.EventStore
+	eventBus   output.EventBus
+	cache      output.Cache
+	uow        output.UnitOfWork
+	logger     output.Logger
+	metrics    output.MetricsReporter
 }
 
-func (h *UpdateUserProfileHandler) Handle(ctx context.Context, cmd interface{}) (interface{}, error) {
-	updateCmd := cmd.(*UpdateUserProfileCommand)
+func NewUpdateUserProfileHandler(
+	userRepo output.UserRepository,
+	eventStore output.EventStore,
+	eventBus output.EventBus,
+	cache output.Cache,
+	uow output.UnitOfWork,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *UpdateUserProfileHandler {
+	return &UpdateUserProfileHandler{
+		userRepo:   userRepo,
+		eventStore: eventStore,
+		eventBus:   eventBus,
+		cache:      cache,
+		uow:        uow,
+		logger:     logger,
+		metrics:    metrics,
+	}
+}
 
+func (h *UpdateUserProfileHandler) Handle(ctx context.Context, updateCmd *UpdateUserProfileCommand) (struct{}, error) {
+	var user *aggregate.User
 	err := h.uow.WithTransaction(ctx, func(ctx context.Context) error {
 		// 1. 获取用户
-		user, err := h.userRepo.FindByID(ctx, updateCmd.UserID)
+		var err error
+		user, err = h.userRepo.FindByID(ctx, updateCmd.UserID)
 		if err != nil {
 			return err
 		}
@@ -148,22 +93,22 @@ func (h *UpdateUserProfileHandler) Handle(ctx context.Context, cmd interface{})
 		}
 
 		// 8. 保存事件
-		if err := h.eventStore.SaveEvents(ctx, user.ID(), user.Events(), user.Version()); err != nil {
-			return err
-		}
-
-		// 9. 清除缓存
-		cacheKey := fmt.Sprintf("user:%s", user.ID())
-		if err := h.cache.Delete(ctx, cacheKey); err != nil {
-			h.logger.Error("failed to clear user cache", "error", err)
-		}
-
-		return nil
+		return h.eventStore.SaveEvents(ctx, user.ID(), user.Events(), user.OriginalVersion())
 	})
 
 	if err != nil {
-		return nil, err
+		return struct{}{}, err
+	}
+
+	// 9. 提交后清除缓存，事务中清除时并发读取可能在提交前把旧数据写回缓存
+	clearUserCache(ctx, h.cache, h.logger, updateCmd.UserID)
+
+	// 10. 发布事件，webhook 等订阅方据此得知资料已更新
+	for _, evt := range user.Events() {
+		if err := h.eventBus.Publish(ctx, evt); err != nil {
+			h.logger.Error("failed to publish profile updated event", "user_id", user.ID(), "error", err)
+		}
 	}
 
-	return nil, nil
+	return struct{}{}, nil
 } 
\ No newline at end of file
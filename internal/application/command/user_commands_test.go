@@ -0,0 +1,59 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/event"
+	"github.com/gohex/gohex/internal/domain/vo"
+	"github.com/gohex/gohex/internal/infrastructure/adapter/secondary/cache/memory"
+	"github.com/gohex/gohex/internal/testutil"
+)
+
+// commitObserver 提交时记录缓存中是否仍有用户详情
+type commitObserver struct {
+	output.UnitOfWork
+	cache          output.Cache
+	key            string
+	cachedAtCommit bool
+}
+
+func (u *commitObserver) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := fn(ctx); err != nil {
+		return err
+	}
+	cached, _ := u.cache.Get(ctx, u.key)
+	u.cachedAtCommit = cached != nil
+	return nil
+}
+
+func TestUpdateUserProfileHandler_ClearsCacheAfterCommit(t *testing.T) {
+	ctx := context.Background()
+	cache := memory.NewCache(time.Minute, testutil.NopLogger{}, testutil.NewMetrics())
+	require.NoError(t, cache.SetWithTags(ctx, "user:id:alice", "stale", time.Hour, []string{output.UserCacheTag("alice")}))
+	uow := &commitObserver{cache: cache, key: "user:id:alice"}
+
+	users := testutil.NewUserRepository(testutil.NewUser("alice", vo.StatusActive, vo.RoleUser))
+	bus := &testutil.EventBus{}
+	h := NewUpdateUserProfileHandler(users, testutil.NewEventStore(), bus, cache, uow,
+		testutil.NopLogger{}, testutil.NewMetrics())
+
+	_, err := h.Handle(ctx, &UpdateUserProfileCommand{UserID: "alice", Name: "Alice"})
+	require.NoError(t, err)
+
+	// 提交前缓存保持不变，提交后才失效，并发读取不会把旧数据写回
+	assert.True(t, uow.cachedAtCommit)
+	cached, err := cache.Get(ctx, "user:id:alice")
+	require.NoError(t, err)
+	assert.Nil(t, cached)
+
+	user, err := users.FindByID(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", user.Profile().Name())
+	assert.Equal(t, []string{event.UserProfileUpdated}, typesOf(bus.Published))
+}
@@ -0,0 +1,271 @@
+package command
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gohex/gohex/internal/application/dto"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/pkg/crypto"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+// webhookSecretBytes 自动生成的签名密钥随机字节数
+const webhookSecretBytes = 32
+
+// CreateWebhookSubscriptionCommand 创建 webhook 订阅命令
+type CreateWebhookSubscriptionCommand struct {
+	URL         string   `validate:"required,url"`
+	EventTypes  []string `validate:"required,min=1"`
+	Secret      string
+	Description string
+	CreatedBy   string `validate:"required"`
+}
+
+type CreateWebhookSubscriptionHandler struct {
+	repo    output.WebhookRepository
+	logger  output.Logger
+	metrics output.MetricsReporter
+}
+
+func NewCreateWebhookSubscriptionHandler(repo output.WebhookRepository, logger output.Logger, metrics output.MetricsReporter) *CreateWebhookSubscriptionHandler {
+	return &CreateWebhookSubscriptionHandler{
+		repo:    repo,
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+func (h *CreateWebhookSubscriptionHandler) Handle(ctx context.Context, createCmd *CreateWebhookSubscriptionCommand) (*dto.WebhookSubscriptionDTO, error) {
+	if err := validateWebhookURL(createCmd.URL); err != nil {
+		return nil, err
+	}
+	if err := validateWebhookEventTypes(createCmd.EventTypes); err != nil {
+		return nil, err
+	}
+
+	secret := createCmd.Secret
+	if secret == "" {
+		var err error
+		if secret, err = crypto.RandomToken(webhookSecretBytes); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	sub := &output.WebhookSubscription{
+		ID:          uuid.New().String(),
+		URL:         createCmd.URL,
+		Secret:      secret,
+		EventTypes:  createCmd.EventTypes,
+		Description: createCmd.Description,
+		Active:      true,
+		CreatedBy:   createCmd.CreatedBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := h.repo.SaveSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+
+	h.logger.Info("webhook subscription created", "subscription_id", sub.ID, "url", sub.URL, "created_by", sub.CreatedBy)
+	h.metrics.IncrementCounter("webhook_subscription_created")
+
+	// 密钥只在创建时返回一次
+	result := dto.NewWebhookSubscriptionDTO(sub)
+	result.Secret = secret
+	return result, nil
+}
+
+// UpdateWebhookSubscriptionCommand 更新 webhook 订阅命令，nil 字段保持不变
+type UpdateWebhookSubscriptionCommand struct {
+	SubscriptionID string `validate:"required"`
+	URL            *string
+	EventTypes     []string
+	Secret         *string
+	Description    *string
+	Active         *bool
+}
+
+type UpdateWebhookSubscriptionHandler struct {
+	repo    output.WebhookRepository
+	logger  output.Logger
+	metrics output.MetricsReporter
+}
+
+func NewUpdateWebhookSubscriptionHandler(repo output.WebhookRepository, logger output.Logger, metrics output.MetricsReporter) *UpdateWebhookSubscriptionHandler {
+	return &UpdateWebhookSubscriptionHandler{
+		repo:    repo,
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+func (h *UpdateWebhookSubscriptionHandler) Handle(ctx context.Context, updateCmd *UpdateWebhookSubscriptionCommand) (*dto.WebhookSubscriptionDTO, error) {
+	sub, err := h.repo.FindSubscription(ctx, updateCmd.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if updateCmd.URL != nil {
+		if err := validateWebhookURL(*updateCmd.URL); err != nil {
+			return nil, err
+		}
+		sub.URL = *updateCmd.URL
+	}
+	if updateCmd.EventTypes != nil {
+		if err := validateWebhookEventTypes(updateCmd.EventTypes); err != nil {
+			return nil, err
+		}
+		sub.EventTypes = updateCmd.EventTypes
+	}
+	if updateCmd.Secret != nil {
+		sub.Secret = *updateCmd.Secret
+	}
+	if updateCmd.Description != nil {
+		sub.Description = *updateCmd.Description
+	}
+	if updateCmd.Active != nil && *updateCmd.Active != sub.Active {
+		sub.Active = *updateCmd.Active
+		if sub.Active {
+			// 重新启用时清零失败计数，否则下一次失败会立即再次停用
+			sub.ConsecutiveFailures = 0
+			sub.DisabledReason = ""
+			sub.DisabledAt = nil
+		} else {
+			now := time.Now()
+			sub.DisabledReason = "disabled manually"
+			sub.DisabledAt = &now
+		}
+	}
+	sub.UpdatedAt = time.Now()
+
+	if err := h.repo.UpdateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+
+	h.logger.Info("webhook subscription updated", "subscription_id", sub.ID, "active", sub.Active)
+	return dto.NewWebhookSubscriptionDTO(sub), nil
+}
+
+// DeleteWebhookSubscriptionCommand 删除 webhook 订阅命令，投递记录一并删除
+type DeleteWebhookSubscriptionCommand struct {
+	SubscriptionID string `validate:"required"`
+}
+
+type DeleteWebhookSubscriptionHandler struct {
+	repo    output.WebhookRepository
+	logger  output.Logger
+	metrics output.MetricsReporter
+}
+
+func NewDeleteWebhookSubscriptionHandler(repo output.WebhookRepository, logger output.Logger, metrics output.MetricsReporter) *DeleteWebhookSubscriptionHandler {
+	return &DeleteWebhookSubscriptionHandler{
+		repo:    repo,
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+func (h *DeleteWebhookSubscriptionHandler) Handle(ctx context.Context, deleteCmd *DeleteWebhookSubscriptionCommand) (struct{}, error) {
+	if err := h.repo.DeleteSubscription(ctx, deleteCmd.SubscriptionID); err != nil {
+		return struct{}{}, err
+	}
+
+	h.logger.Info("webhook subscription deleted", "subscription_id", deleteCmd.SubscriptionID)
+	h.metrics.IncrementCounter("webhook_subscription_deleted")
+	return struct{}{}, nil
+}
+
+// RedeliverWebhookCommand 手动重新投递，复制原投递记录的请求体生成新的待投递记录
+type RedeliverWebhookCommand struct {
+	SubscriptionID string `validate:"required"`
+	DeliveryID     string `validate:"required"`
+}
+
+type RedeliverWebhookHandler struct {
+	repo    output.WebhookRepository
+	logger  output.Logger
+	metrics output.MetricsReporter
+}
+
+func NewRedeliverWebhookHandler(repo output.WebhookRepository, logger output.Logger, metrics output.MetricsReporter) *RedeliverWebhookHandler {
+	return &RedeliverWebhookHandler{
+		repo:    repo,
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+func (h *RedeliverWebhookHandler) Handle(ctx context.Context, redeliverCmd *RedeliverWebhookCommand) (*dto.WebhookDeliveryDTO, error) {
+	original, err := h.repo.FindDelivery(ctx, redeliverCmd.DeliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if original.SubscriptionID != redeliverCmd.SubscriptionID {
+		return nil, errors.NewNotFoundError("webhook delivery")
+	}
+
+	sub, err := h.repo.FindSubscription(ctx, original.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if !sub.Active {
+		return nil, errors.NewAppError(errors.ErrCodeConflict, "webhook subscription is disabled", nil)
+	}
+
+	now := time.Now()
+	delivery := &output.WebhookDelivery{
+		ID:             uuid.New().String(),
+		SubscriptionID: original.SubscriptionID,
+		// 保留原事件 ID，接收方可据此去重
+		EventID:       original.EventID,
+		EventType:     original.EventType,
+		Payload:       original.Payload,
+		Status:        output.WebhookDeliveryPending,
+		NextAttemptAt: now,
+		RedeliveryOf:  original.ID,
+		CreatedAt:     now,
+	}
+	if err := h.repo.SaveDeliveries(ctx, []*output.WebhookDelivery{delivery}); err != nil {
+		return nil, err
+	}
+
+	h.logger.Info("webhook redelivery scheduled", "delivery_id", delivery.ID, "redelivery_of", original.ID)
+	h.metrics.IncrementCounter("webhook_redelivery_requested")
+	return dto.NewWebhookDeliveryDTO(delivery), nil
+}
+
+// validateWebhookURL 只允许 http 和 https 地址
+func validateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return errors.NewValidationError("webhook url must be an absolute http or https url")
+	}
+	return nil
+}
+
+// validateWebhookEventTypes 事件类型必须是可订阅的事件或 "*"
+func validateWebhookEventTypes(eventTypes []string) error {
+	if len(eventTypes) == 0 {
+		return errors.NewValidationError("at least one event type is required")
+	}
+	for _, t := range eventTypes {
+		if t == output.WebhookAllEvents {
+			continue
+		}
+		valid := false
+		for _, allowed := range output.WebhookEventTypes {
+			if t == allowed {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return errors.NewValidationError("unsupported event type: " + t)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/event"
+	"github.com/gohex/gohex/internal/testutil"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+func errorCode(t *testing.T, err error) errors.ErrorCode {
+	t.Helper()
+	var appErr *errors.AppError
+	require.ErrorAs(t, err, &appErr)
+	return appErr.Code
+}
+
+func TestCreateWebhookSubscriptionHandler(t *testing.T) {
+	repo := testutil.NewWebhookRepository()
+	h := NewCreateWebhookSubscriptionHandler(repo, testutil.NopLogger{}, testutil.NewMetrics())
+
+	result, err := h.Handle(context.Background(), &CreateWebhookSubscriptionCommand{
+		URL:        "https://partner.example.com/hooks",
+		EventTypes: []string{event.UserCreated, event.UserLocked},
+		CreatedBy:  "admin",
+	})
+	require.NoError(t, err)
+
+	created := result
+	assert.Len(t, created.Secret, 2*webhookSecretBytes)
+	assert.True(t, created.Active)
+
+	sub, err := repo.FindSubscription(context.Background(), created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, created.Secret, sub.Secret)
+	assert.Equal(t, []string{event.UserCreated, event.UserLocked}, sub.EventTypes)
+}
+
+func TestCreateWebhookSubscriptionHandler_Validation(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		eventTypes []string
+	}{
+		{name: "relative url", url: "/hooks", eventTypes: []string{event.UserCreated}},
+		{name: "unsupported scheme", url: "ftp://partner.example.com", eventTypes: []string{event.UserCreated}},
+		{name: "no event types", url: "https://partner.example.com"},
+		{name: "security event", url: "https://partner.example.com", eventTypes: []string{event.UserLoggedIn}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := testutil.NewWebhookRepository()
+			h := NewCreateWebhookSubscriptionHandler(repo, testutil.NopLogger{}, testutil.NewMetrics())
+
+			_, err := h.Handle(context.Background(), &CreateWebhookSubscriptionCommand{
+				URL:        tt.url,
+				EventTypes: tt.eventTypes,
+				CreatedBy:  "admin",
+			})
+			assert.Equal(t, errors.ErrCodeValidation, errorCode(t, err))
+			assert.Empty(t, repo.Subscriptions)
+		})
+	}
+}
+
+func TestUpdateWebhookSubscriptionHandler_Active(t *testing.T) {
+	disabledAt := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name       string
+		sub        output.WebhookSubscription
+		active     bool
+		wantReason string
+		wantFails  int
+	}{
+		{
+			name: "re-enabling clears failures",
+			sub: output.WebhookSubscription{
+				ConsecutiveFailures: 10,
+				DisabledReason:      "disabled after 10 consecutive failed deliveries",
+				DisabledAt:          &disabledAt,
+			},
+			active: true,
+		},
+		{
+			name:       "disabling records reason",
+			sub:        output.WebhookSubscription{Active: true, ConsecutiveFailures: 3},
+			active:     false,
+			wantReason: "disabled manually",
+			wantFails:  3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := tt.sub
+			sub.ID = "sub-1"
+			repo := testutil.NewWebhookRepository(&sub)
+			h := NewUpdateWebhookSubscriptionHandler(repo, testutil.NopLogger{}, testutil.NewMetrics())
+
+			_, err := h.Handle(context.Background(), &UpdateWebhookSubscriptionCommand{
+				SubscriptionID: "sub-1",
+				Active:         &tt.active,
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.active, sub.Active)
+			assert.Equal(t, tt.wantReason, sub.DisabledReason)
+			assert.Equal(t, tt.wantFails, sub.ConsecutiveFailures)
+			assert.Equal(t, !tt.active, sub.DisabledAt != nil)
+		})
+	}
+}
+
+func TestRedeliverWebhookHandler(t *testing.T) {
+	repo := testutil.NewWebhookRepository(&output.WebhookSubscription{ID: "sub-1", Active: true})
+	original := &output.WebhookDelivery{
+		ID:             "dlv-1",
+		SubscriptionID: "sub-1",
+		EventID:        "evt-1",
+		EventType:      event.UserCreated,
+		Payload:        []byte(`{"id":"evt-1"}`),
+		Status:         output.WebhookDeliveryFailed,
+		Attempts:       8,
+	}
+	require.NoError(t, repo.SaveDeliveries(context.Background(), []*output.WebhookDelivery{original}))
+	h := NewRedeliverWebhookHandler(repo, testutil.NopLogger{}, testutil.NewMetrics())
+
+	_, err := h.Handle(context.Background(), &RedeliverWebhookCommand{SubscriptionID: "sub-1", DeliveryID: "dlv-1"})
+	require.NoError(t, err)
+
+	require.Len(t, repo.Deliveries, 2)
+	redelivery := repo.Deliveries[1]
+	assert.NotEqual(t, original.ID, redelivery.ID)
+	assert.Equal(t, original.ID, redelivery.RedeliveryOf)
+	assert.Equal(t, original.EventID, redelivery.EventID)
+	assert.Equal(t, original.Payload, redelivery.Payload)
+	assert.Equal(t, output.WebhookDeliveryPending, redelivery.Status)
+	assert.Zero(t, redelivery.Attempts)
+}
+
+func TestRedeliverWebhookHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		subscriptionID string
+		active         bool
+		wantCode       errors.ErrorCode
+	}{
+		{name: "delivery of another subscription", subscriptionID: "sub-2", active: true, wantCode: errors.ErrCodeNotFound},
+		{name: "disabled subscription", subscriptionID: "sub-1", wantCode: errors.ErrCodeConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := testutil.NewWebhookRepository(
+				&output.WebhookSubscription{ID: "sub-1", Active: tt.active},
+				&output.WebhookSubscription{ID: "sub-2", Active: true},
+			)
+			require.NoError(t, repo.SaveDeliveries(context.Background(), []*output.WebhookDelivery{
+				{ID: "dlv-1", SubscriptionID: "sub-1"},
+			}))
+			h := NewRedeliverWebhookHandler(repo, testutil.NopLogger{}, testutil.NewMetrics())
+
+			_, err := h.Handle(context.Background(), &RedeliverWebhookCommand{
+				SubscriptionID: tt.subscriptionID,
+				DeliveryID:     "dlv-1",
+			})
+			assert.Equal(t, tt.wantCode, errorCode(t, err))
+			assert.Len(t, repo.Deliveries, 1)
+		})
+	}
+}
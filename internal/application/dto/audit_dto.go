@@ -0,0 +1,57 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+)
+
+// AuditLogDTO 审计记录
+type AuditLogDTO struct {
+	ID             string                        `json:"id"`
+	Sequence       int64                         `json:"sequence"`
+	ActorID        string                        `json:"actor_id"`
+	ImpersonatorID string                        `json:"impersonator_id,omitempty"`
+	SubjectID      string                        `json:"subject_id"`
+	Action         string                        `json:"action"`
+	Result         string                        `json:"result"`
+	Error          string                        `json:"error,omitempty"`
+	IP             string                        `json:"ip"`
+	UserAgent      string                        `json:"user_agent"`
+	TraceID        string                        `json:"trace_id"`
+	Changes        map[string]output.AuditChange `json:"changes,omitempty"`
+	Hash           string                        `json:"hash"`
+	OccurredAt     time.Time                     `json:"occurred_at"`
+}
+
+func NewAuditLogDTO(entry *output.AuditEntry) *AuditLogDTO {
+	return &AuditLogDTO{
+		ID:             entry.ID,
+		Sequence:       entry.Sequence,
+		ActorID:        entry.ActorID,
+		ImpersonatorID: entry.ImpersonatorID,
+		SubjectID:      entry.SubjectID,
+		Action:         entry.Action,
+		Result:         entry.Result,
+		Error:          entry.Error,
+		IP:             entry.IP,
+		UserAgent:      entry.UserAgent,
+		TraceID:        entry.TraceID,
+		Changes:        entry.Changes,
+		Hash:           entry.Hash,
+		OccurredAt:     entry.OccurredAt,
+	}
+}
+
+// AuditLogQueryDTO 审计查询参数
+type AuditLogQueryDTO struct {
+	ActorID   string    `query:"actor_id"`
+	SubjectID string    `query:"subject_id"`
+	Action    string    `query:"action"`
+	Result    string    `query:"result" validate:"omitempty,oneof=success failure denied"`
+	From      time.Time `query:"from"`
+	To        time.Time `query:"to"`
+	Page      int       `query:"page"`
+	PageSize  int       `query:"page_size"`
+	Format    string    `query:"format" validate:"omitempty,oneof=json csv"`
+}
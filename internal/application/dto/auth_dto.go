@@ -33,12 +33,52 @@ type RefreshTokenResponseDTO struct {
 // TokenInfoDTO 令牌信息
 type TokenInfoDTO struct {
 	UserID    string    `json:"user_id"`
+	ActorID   string    `json:"actor_id,omitempty"`
 	Email     string    `json:"email"`
 	Roles     []string  `json:"roles"`
 	IssuedAt  time.Time `json:"issued_at"`
 	ExpiresAt time.Time `json:"expires_at"`
 }
 
+// EmailChangeRequestDTO 申请变更邮箱请求
+type EmailChangeRequestDTO struct {
+	NewEmail string `json:"new_email" validate:"required,email"`
+}
+
+// EmailChangeTokenDTO 确认或取消邮箱变更请求
+type EmailChangeTokenDTO struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// VerifyEmailDTO 验证邮箱请求
+type VerifyEmailDTO struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// ImpersonateRequestDTO 模拟用户请求
+type ImpersonateRequestDTO struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+// ImpersonationResponseDTO 模拟用户响应
+type ImpersonationResponseDTO struct {
+	AccessToken string    `json:"access_token"`
+	TokenType   string    `json:"token_type"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	ActorID     string    `json:"actor_id"`
+	SubjectID   string    `json:"subject_id"`
+}
+
+// AuthInfoDTO 认证信息
+type AuthInfoDTO struct {
+	UserID    string    `json:"user_id"`
+	Email     string    `json:"email"`
+	Roles     []string  `json:"roles"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// ActorID 模拟会话中的实际操作人
+	ActorID string `json:"actor_id,omitempty"`
+}
+
 // AuthErrorDTO 认证错误
 type AuthErrorDTO struct {
 	Code    string `json:"code"`
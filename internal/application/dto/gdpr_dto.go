@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+)
+
+// DataExportDTO 数据导出请求状态
+type DataExportDTO struct {
+	ID          string     `json:"id"`
+	UserID      string     `json:"user_id"`
+	Status      string     `json:"status"`
+	Error       string     `json:"error,omitempty"`
+	RequestedAt time.Time  `json:"requested_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
+
+func NewDataExportDTO(export *output.DataExport) *DataExportDTO {
+	return &DataExportDTO{
+		ID:          export.ID,
+		UserID:      export.UserID,
+		Status:      export.Status,
+		Error:       export.Error,
+		RequestedAt: export.RequestedAt,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+	}
+}
+
+// EraseUserRequestDTO 擦除用户请求
+type EraseUserRequestDTO struct {
+	Reason string `json:"reason" validate:"max=500"`
+}
@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"encoding/json"
+	"path"
+	"time"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+)
+
+// JobDTO 异步命令任务状态
+type JobDTO struct {
+	ID              string          `json:"id"`
+	CommandType     string          `json:"command_type"`
+	Status          string          `json:"status"`
+	Progress        int             `json:"progress"`
+	Attempts        int             `json:"attempts"`
+	Result          json.RawMessage `json:"result,omitempty"`
+	Error           string          `json:"error,omitempty"`
+	CancelRequested bool            `json:"cancel_requested,omitempty"`
+	CreatedAt       time.Time       `json:"created_at"`
+	StartedAt       *time.Time      `json:"started_at,omitempty"`
+	FinishedAt      *time.Time      `json:"finished_at,omitempty"`
+}
+
+func NewJobDTO(job *output.Job) *JobDTO {
+	dto := &JobDTO{
+		ID: job.ID,
+		// 只展示包名和类型名，不暴露完整包路径
+		CommandType:     path.Base(job.CommandType),
+		Status:          job.Status,
+		Progress:        job.Progress,
+		Attempts:        job.Attempts,
+		Error:           job.Error,
+		CancelRequested: job.CancelRequested && !job.IsFinished(),
+		CreatedAt:       job.CreatedAt,
+		StartedAt:       job.StartedAt,
+		FinishedAt:      job.FinishedAt,
+	}
+	if len(job.Result) > 0 {
+		dto.Result = json.RawMessage(job.Result)
+	}
+	return dto
+}
+
+// JobAcceptedDTO 命令已入队，通过 GET /api/v1/jobs/:id 查询进度
+type JobAcceptedDTO struct {
+	JobID string `json:"job_id"`
+}
@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+)
+
+// ScheduledCommandDTO 计划命令
+type ScheduledCommandDTO struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name,omitempty"`
+	CommandType string          `json:"command_type"`
+	Payload     json.RawMessage `json:"payload"`
+	Cron        string          `json:"cron,omitempty"`
+	NextRunAt   *time.Time      `json:"next_run_at,omitempty"`
+	Status      string          `json:"status"`
+	Runs        int             `json:"runs"`
+	LastRunAt   *time.Time      `json:"last_run_at,omitempty"`
+	LastError   string          `json:"last_error,omitempty"`
+	RequestedBy string          `json:"requested_by"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+func NewScheduledCommandDTO(s *output.ScheduledCommand) *ScheduledCommandDTO {
+	dto := &ScheduledCommandDTO{
+		ID:          s.ID,
+		Name:        s.Name,
+		CommandType: s.CommandType,
+		Payload:     json.RawMessage(s.Payload),
+		Cron:        s.Cron,
+		Status:      s.Status,
+		Runs:        s.Runs,
+		LastRunAt:   s.LastRunAt,
+		LastError:   s.LastError,
+		RequestedBy: s.RequestedBy,
+		CreatedAt:   s.CreatedAt,
+	}
+	// 只有等待执行的计划的下次执行时间有意义
+	if s.Status == output.ScheduleActive {
+		next := s.NextRunAt
+		dto.NextRunAt = &next
+	}
+	return dto
+}
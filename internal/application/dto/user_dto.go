@@ -2,7 +2,6 @@ package dto
 
 import (
 	"time"
-	"github.com/gohex/gohex/internal/domain/vo"
 	"github.com/gohex/gohex/internal/domain/aggregate"
 )
 
@@ -12,6 +11,7 @@ type UserDTO struct {
 	Email     string    `json:"email"`
 	Name      string    `json:"name"`
 	Bio       string    `json:"bio"`
+	Avatar    string    `json:"avatar,omitempty"`
 	Status    string    `json:"status"`
 	Roles     []string  `json:"roles"`
 	CreatedAt time.Time `json:"created_at"`
@@ -24,6 +24,7 @@ func NewUserDTO(user *aggregate.User) *UserDTO {
 		Email:     user.Email().String(),
 		Name:      user.Profile().Name(),
 		Bio:       user.Profile().Bio(),
+		Avatar:    user.Profile().Avatar(),
 		Status:    user.Status().String(),
 		Roles:     user.RoleStrings(),
 		CreatedAt: user.CreatedAt(),
@@ -37,6 +38,12 @@ type UserListDTO struct {
 	Items []UserDTO  `json:"items"`
 }
 
+// UserRolesDTO 用户角色数据传输对象
+type UserRolesDTO struct {
+	UserID string   `json:"user_id"`
+	Roles  []string `json:"roles"`
+}
+
 // CreateUserDTO 创建用户请求
 type CreateUserDTO struct {
 	Email    string `json:"email" validate:"required,email"`
@@ -66,4 +73,9 @@ type UserProfileDTO struct {
 	Location string `json:"location,omitempty"`
 	Website  string `json:"website,omitempty" validate:"omitempty,url"`
 }
- 
\ No newline at end of file
+ 
+// BulkAssignRoleRequestDTO 批量分配角色请求
+type BulkAssignRoleRequestDTO struct {
+	UserIDs []string `json:"user_ids" validate:"required,min=1,max=10000,dive,required"`
+	Role    string   `json:"role" validate:"required"`
+}
@@ -0,0 +1,97 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+)
+
+// CreateWebhookDTO 创建 webhook 订阅请求，Secret 为空时自动生成
+type CreateWebhookDTO struct {
+	URL         string   `json:"url" validate:"required,url"`
+	EventTypes  []string `json:"event_types" validate:"required,min=1"`
+	Secret      string   `json:"secret" validate:"omitempty,min=16"`
+	Description string   `json:"description" validate:"max=255"`
+}
+
+// UpdateWebhookDTO 更新 webhook 订阅请求，未提供的字段保持不变
+type UpdateWebhookDTO struct {
+	URL         *string  `json:"url" validate:"omitempty,url"`
+	EventTypes  []string `json:"event_types" validate:"omitempty,min=1"`
+	Secret      *string  `json:"secret" validate:"omitempty,min=16"`
+	Description *string  `json:"description" validate:"omitempty,max=255"`
+	Active      *bool    `json:"active"`
+}
+
+// WebhookSubscriptionDTO webhook 订阅，密钥只在创建时返回
+type WebhookSubscriptionDTO struct {
+	ID                  string     `json:"id"`
+	URL                 string     `json:"url"`
+	Secret              string     `json:"secret,omitempty"`
+	EventTypes          []string   `json:"event_types"`
+	Description         string     `json:"description,omitempty"`
+	Active              bool       `json:"active"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	DisabledReason      string     `json:"disabled_reason,omitempty"`
+	CreatedBy           string     `json:"created_by"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty"`
+}
+
+func NewWebhookSubscriptionDTO(sub *output.WebhookSubscription) *WebhookSubscriptionDTO {
+	return &WebhookSubscriptionDTO{
+		ID:                  sub.ID,
+		URL:                 sub.URL,
+		EventTypes:          sub.EventTypes,
+		Description:         sub.Description,
+		Active:              sub.Active,
+		ConsecutiveFailures: sub.ConsecutiveFailures,
+		DisabledReason:      sub.DisabledReason,
+		CreatedBy:           sub.CreatedBy,
+		CreatedAt:           sub.CreatedAt,
+		UpdatedAt:           sub.UpdatedAt,
+		DisabledAt:          sub.DisabledAt,
+	}
+}
+
+// WebhookDeliveryDTO webhook 投递记录
+type WebhookDeliveryDTO struct {
+	ID             string          `json:"id"`
+	SubscriptionID string          `json:"subscription_id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	RedeliveryOf   string          `json:"redelivery_of,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+}
+
+func NewWebhookDeliveryDTO(d *output.WebhookDelivery) *WebhookDeliveryDTO {
+	dto := &WebhookDeliveryDTO{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Payload:        json.RawMessage(d.Payload),
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		RedeliveryOf:   d.RedeliveryOf,
+		CreatedAt:      d.CreatedAt,
+		DeliveredAt:    d.DeliveredAt,
+	}
+	// 只有待投递记录的下次投递时间有意义
+	if d.Status == output.WebhookDeliveryPending {
+		next := d.NextAttemptAt
+		dto.NextAttemptAt = &next
+	}
+	return dto
+}
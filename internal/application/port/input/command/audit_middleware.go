@@ -0,0 +1,164 @@
+package command
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/pkg/actor"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+// AuditSubject 命令可实现该接口声明被操作的对象
+type AuditSubject interface {
+	AuditSubjectID() string
+}
+
+// AuditAction 命令可实现该接口自定义审计动作名称
+type AuditAction interface {
+	AuditAction() string
+}
+
+// AuditSnapshotter 获取被操作对象的快照，用于计算变更前后差异
+type AuditSnapshotter interface {
+	Snapshot(ctx context.Context, subjectID string) (map[string]interface{}, error)
+}
+
+// AuditMiddleware 为每条经过命令总线的命令写入审计记录
+type AuditMiddleware struct {
+	auditLog    output.AuditLog
+	snapshotter AuditSnapshotter
+	logger      output.Logger
+	metrics     output.MetricsReporter
+}
+
+func NewAuditMiddleware(
+	auditLog output.AuditLog,
+	snapshotter AuditSnapshotter,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *AuditMiddleware {
+	return &AuditMiddleware{
+		auditLog:    auditLog,
+		snapshotter: snapshotter,
+		logger:      logger,
+		metrics:     metrics,
+	}
+}
+
+func (m *AuditMiddleware) Execute(ctx context.Context, cmd interface{}, next Handler) (interface{}, error) {
+	subjectID := auditSubjectID(cmd)
+
+	// 1. 记录执行前快照
+	before := m.snapshot(ctx, subjectID)
+
+	// 2. 执行命令
+	result, err := next.Handle(ctx, cmd)
+
+	// 3. 写入审计记录，审计失败不影响命令结果
+	entry := &output.AuditEntry{
+		SubjectID: subjectID,
+		Action:    auditActionName(cmd),
+		Result:    auditResult(err),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	} else if before != nil {
+		entry.Changes = diffSnapshots(before, m.snapshot(ctx, subjectID))
+	}
+	if a, ok := actor.FromContext(ctx); ok {
+		entry.ActorID = a.RealActorID()
+		if a.IsImpersonated() {
+			entry.ImpersonatorID = a.ImpersonatorID
+		}
+		entry.IP = a.IP
+		entry.UserAgent = a.UserAgent
+		entry.TraceID = a.TraceID
+	} else {
+		// 未认证的命令（如登录）从命令自身取来源信息
+		entry.IP = stringField(cmd, "IP")
+		entry.UserAgent = stringField(cmd, "UserAgent")
+	}
+
+	if appendErr := m.auditLog.Append(context.WithoutCancel(ctx), entry); appendErr != nil {
+		m.logger.Error("failed to write audit entry",
+			"action", entry.Action,
+			"subject_id", subjectID,
+			"error", appendErr,
+		)
+		m.metrics.IncrementCounter("audit_write_failure", "action", entry.Action)
+	}
+
+	return result, err
+}
+
+func (m *AuditMiddleware) snapshot(ctx context.Context, subjectID string) map[string]interface{} {
+	if m.snapshotter == nil || subjectID == "" {
+		return nil
+	}
+	snap, err := m.snapshotter.Snapshot(ctx, subjectID)
+	if err != nil {
+		return nil
+	}
+	return snap
+}
+
+func auditSubjectID(cmd interface{}) string {
+	if s, ok := cmd.(AuditSubject); ok {
+		return s.AuditSubjectID()
+	}
+
+	// 约定：命令中的 SubjectID 或 UserID 字段即为被操作对象
+	if id := stringField(cmd, "SubjectID"); id != "" {
+		return id
+	}
+	return stringField(cmd, "UserID")
+}
+
+func stringField(cmd interface{}, name string) string {
+	v := reflect.Indirect(reflect.ValueOf(cmd))
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	if f := v.FieldByName(name); f.IsValid() && f.Kind() == reflect.String {
+		return f.String()
+	}
+	return ""
+}
+
+func auditActionName(cmd interface{}) string {
+	if a, ok := cmd.(AuditAction); ok {
+		return a.AuditAction()
+	}
+	return reflect.Indirect(reflect.ValueOf(cmd)).Type().Name()
+}
+
+func auditResult(err error) string {
+	if err == nil {
+		return output.AuditResultSuccess
+	}
+	var appErr *errors.AppError
+	if errors.As(err, &appErr) && (appErr.Code == errors.ErrCodeForbidden || appErr.Code == errors.ErrCodeUnauthorized) {
+		return output.AuditResultDenied
+	}
+	return output.AuditResultFailure
+}
+
+// diffSnapshots 返回前后快照中发生变化的字段
+func diffSnapshots(before, after map[string]interface{}) map[string]output.AuditChange {
+	changes := make(map[string]output.AuditChange)
+	for key, b := range before {
+		if a, ok := after[key]; !ok || !reflect.DeepEqual(a, b) {
+			changes[key] = output.AuditChange{Before: b, After: after[key]}
+		}
+	}
+	for key, a := range after {
+		if _, ok := before[key]; !ok {
+			changes[key] = output.AuditChange{Before: nil, After: a}
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	return changes
+}
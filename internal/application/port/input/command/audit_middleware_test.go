@@ -0,0 +1,95 @@
+package command_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/port/input/command"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/application/service"
+	"github.com/gohex/gohex/internal/domain/vo"
+	"github.com/gohex/gohex/internal/testutil"
+	"github.com/gohex/gohex/pkg/actor"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+type renameCommand struct {
+	UserID string
+	Name   string
+}
+
+type handlerFunc func(ctx context.Context, cmd interface{}) (interface{}, error)
+
+func (f handlerFunc) Handle(ctx context.Context, cmd interface{}) (interface{}, error) {
+	return f(ctx, cmd)
+}
+
+func TestAuditMiddleware(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantResult  string
+		wantChanged []string
+	}{
+		{name: "success records changed fields", wantResult: output.AuditResultSuccess, wantChanged: []string{"name"}},
+		{name: "forbidden is recorded as denied", err: errors.ErrInsufficientPermissions, wantResult: output.AuditResultDenied},
+		{name: "other errors are failures", err: errors.ErrUserNotFound, wantResult: output.AuditResultFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := testutil.NewUserRepository(testutil.NewUser("alice", vo.StatusActive, vo.RoleUser))
+			keys := testutil.NewKeyStore()
+			auditLog := &testutil.AuditLog{}
+			m := command.NewAuditMiddleware(auditLog, service.NewUserAuditSnapshotter(users, keys),
+				testutil.NopLogger{}, testutil.NewMetrics())
+
+			next := handlerFunc(func(ctx context.Context, cmd interface{}) (interface{}, error) {
+				if tt.err != nil {
+					return nil, tt.err
+				}
+				user, err := users.FindByID(ctx, "alice")
+				require.NoError(t, err)
+				profile, err := vo.NewUserProfile(cmd.(*renameCommand).Name, "")
+				require.NoError(t, err)
+				require.NoError(t, user.UpdateProfile(profile))
+				return nil, users.Update(ctx, user)
+			})
+
+			ctx := actor.WithActor(context.Background(), actor.Actor{UserID: "admin", IP: "10.0.0.1"})
+			_, err := m.Execute(ctx, &renameCommand{UserID: "alice", Name: "Alice Renamed"}, next)
+			assert.Equal(t, tt.err, err)
+
+			require.Len(t, auditLog.Entries, 1)
+			entry := auditLog.Entries[0]
+			assert.Equal(t, "renameCommand", entry.Action)
+			assert.Equal(t, "alice", entry.SubjectID)
+			assert.Equal(t, "admin", entry.ActorID)
+			assert.Equal(t, "10.0.0.1", entry.IP)
+			assert.Equal(t, tt.wantResult, entry.Result)
+
+			var changed []string
+			for field := range entry.Changes {
+				changed = append(changed, field)
+			}
+			assert.ElementsMatch(t, tt.wantChanged, changed)
+
+			// 变更内容只保存个人信息的摘要
+			data, err := json.Marshal(entry.Changes)
+			require.NoError(t, err)
+			assert.NotContains(t, string(data), "Alice Renamed")
+			assert.NotContains(t, string(data), "Test alice")
+			// 摘要以用户密钥计算，不能通过对候选值计算 sha256 比对
+			for _, name := range []string{"Alice Renamed", "Test alice"} {
+				sum := sha256.Sum256([]byte(name))
+				assert.NotContains(t, string(data), hex.EncodeToString(sum[:]))
+			}
+		})
+	}
+}
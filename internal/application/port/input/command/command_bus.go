@@ -4,15 +4,29 @@ import (
 	"context"
 	"reflect"
 	"time"
-	"github.com/your-org/your-project/pkg/errors"
+	"github.com/gohex/gohex/pkg/errors"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/pkg/actor"
 )
 
 // Bus 定义命令总线接口
 type Bus interface {
 	// Dispatch 分发命令并返回结果
 	Dispatch(ctx context.Context, command interface{}) (interface{}, error)
-	// Register 注册命令处理器
+	// DispatchAsync 将命令写入任务队列并返回任务 ID，由后台 worker 经中间件链执行
+	DispatchAsync(ctx context.Context, command interface{}) (string, error)
+	// RunJob 解码已入队的命令并同步执行，供后台 worker 调用
+	RunJob(ctx context.Context, job *output.Job) (interface{}, error)
+	// Schedule 在 runAt 执行一次命令，返回计划 ID
+	Schedule(ctx context.Context, command interface{}, runAt time.Time) (string, error)
+	// ScheduleCron 按 cron 表达式周期执行命令，name 唯一，重复注册时更新已有计划
+	ScheduleCron(ctx context.Context, name string, command interface{}, expr string) (string, error)
+	// Register 注册命令处理器，指针与值类型视为同一命令
 	Register(commandType interface{}, handler Handler)
+	// Describe 返回已注册的处理器和中间件
+	Describe() RegistryInfo
+	// Verify 检查注册错误以及 required 中的命令是否都有处理器
+	Verify(required ...interface{}) error
 }
 
 // Handler 定义命令处理器接口
@@ -40,21 +54,21 @@ type TransactionHandler interface {
 	WithTransaction() bool
 }
 
-// EventHandler 定义事件处理器接口
-type EventHandler interface {
-	Handler
-	// Events 返回命令执行后产生的事件
-	Events() []event.Event
-}
-
 // ValidatorMiddleware 验证中间件
 type ValidatorMiddleware struct {
 	validator Validator
-	logger    Logger
+	logger    output.Logger
+}
+
+func NewValidatorMiddleware(validator Validator, logger output.Logger) *ValidatorMiddleware {
+	return &ValidatorMiddleware{
+		validator: validator,
+		logger:    logger,
+	}
 }
 
 func (m *ValidatorMiddleware) Execute(ctx context.Context, cmd interface{}, next Handler) (interface{}, error) {
-	if err := m.validator.Struct(cmd); err != nil {
+	if err := m.validator.Validate(cmd); err != nil {
 		return nil, errors.NewValidationError(err.Error())
 	}
 	return next.Handle(ctx, cmd)
@@ -62,25 +76,30 @@ func (m *ValidatorMiddleware) Execute(ctx context.Context, cmd interface{}, next
 
 // LoggingMiddleware 日志中间件
 type LoggingMiddleware struct {
-	logger Logger
+	logger output.Logger
 }
 
 func (m *LoggingMiddleware) Execute(ctx context.Context, cmd interface{}, next Handler) (interface{}, error) {
 	cmdType := reflect.TypeOf(cmd).String()
-	m.logger.Info("executing command", "type", cmdType)
+	logger := m.logger
+	if a, ok := actor.FromContext(ctx); ok {
+		// 模拟会话中记录实际操作人，避免把操作归到被模拟用户名下
+		logger = logger.With("actor_id", a.RealActorID()).With("user_id", a.UserID)
+	}
+	logger.Info("executing command", "type", cmdType)
 
 	start := time.Now()
 	result, err := next.Handle(ctx, cmd)
 	duration := time.Since(start)
 
 	if err != nil {
-		m.logger.Error("command failed",
+		logger.Error("command failed",
 			"type", cmdType,
 			"duration", duration,
 			"error", err,
 		)
 	} else {
-		m.logger.Info("command completed",
+		logger.Info("command completed",
 			"type", cmdType,
 			"duration", duration,
 		)
@@ -91,7 +110,7 @@ func (m *LoggingMiddleware) Execute(ctx context.Context, cmd interface{}, next H
 
 // MetricsMiddleware 指标中间件
 type MetricsMiddleware struct {
-	metrics MetricsReporter
+	metrics output.MetricsReporter
 }
 
 func (m *MetricsMiddleware) Execute(ctx context.Context, cmd interface{}, next Handler) (interface{}, error) {
@@ -0,0 +1,61 @@
+package command
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+// ConcurrencyMiddleware 按命令类型隔离并发（舱壁），超出上限的命令立即拒绝而不是排队
+type ConcurrencyMiddleware struct {
+	policies HandlerPolicies
+	logger   output.Logger
+	metrics  output.MetricsReporter
+
+	mu         sync.Mutex
+	semaphores map[string]chan struct{}
+}
+
+func NewConcurrencyMiddleware(policies HandlerPolicies, logger output.Logger, metrics output.MetricsReporter) *ConcurrencyMiddleware {
+	return &ConcurrencyMiddleware{
+		policies:   policies,
+		logger:     logger,
+		metrics:    metrics,
+		semaphores: make(map[string]chan struct{}),
+	}
+}
+
+func (m *ConcurrencyMiddleware) Execute(ctx context.Context, cmd interface{}, next Handler) (interface{}, error) {
+	limit := m.policies.For(cmd).MaxConcurrency
+	if limit <= 0 {
+		return next.Handle(ctx, cmd)
+	}
+
+	name := commandTypeName(cmd)
+	sem := m.semaphore(name, limit)
+	select {
+	case sem <- struct{}{}:
+	default:
+		m.logger.Warn("command rejected by concurrency limit", "command_type", name, "limit", limit)
+		m.metrics.IncrementCounter("command_rejected", "command_type", name)
+		return nil, errors.ErrCommandConcurrencyLimit
+	}
+	defer func() { <-sem }()
+
+	return next.Handle(ctx, cmd)
+}
+
+// semaphore 首次执行某类命令时创建信号量
+func (m *ConcurrencyMiddleware) semaphore(name string, limit int) chan struct{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sem, ok := m.semaphores[name]
+	if !ok {
+		sem = make(chan struct{}, limit)
+		m.semaphores[name] = sem
+	}
+	return sem
+}
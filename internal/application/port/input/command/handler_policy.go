@@ -0,0 +1,71 @@
+package command
+
+import (
+	"reflect"
+	"time"
+)
+
+// HandlerPolicy 命令执行策略，零值表示不限制
+type HandlerPolicy struct {
+	// Timeout 单次命令执行（含重试）的最长时间
+	Timeout time.Duration
+	// MaxConcurrency 同一命令类型的最大并发数
+	MaxConcurrency int
+	// RetryAttempts 瞬时错误的最大重试次数，不含首次执行
+	RetryAttempts int
+	// RetryDelay 首次重试前的等待时间，之后按指数增长
+	RetryDelay time.Duration
+}
+
+// HandlerPolicies 全局默认策略以及按命令类型名（如 RegisterUserCommand）覆盖的策略
+type HandlerPolicies struct {
+	Default  HandlerPolicy
+	Commands map[string]HandlerPolicy
+}
+
+// For 返回命令生效的策略：覆盖项为零时继承默认值，为负数时关闭该项
+func (p HandlerPolicies) For(cmd interface{}) HandlerPolicy {
+	policy := p.Default
+	override, ok := p.Commands[commandTypeName(cmd)]
+	if !ok {
+		return policy
+	}
+
+	policy.Timeout = mergeDuration(policy.Timeout, override.Timeout)
+	policy.RetryDelay = mergeDuration(policy.RetryDelay, override.RetryDelay)
+	policy.MaxConcurrency = mergeInt(policy.MaxConcurrency, override.MaxConcurrency)
+	policy.RetryAttempts = mergeInt(policy.RetryAttempts, override.RetryAttempts)
+	return policy
+}
+
+func mergeDuration(base, override time.Duration) time.Duration {
+	switch {
+	case override < 0:
+		return 0
+	case override > 0:
+		return override
+	}
+	return base
+}
+
+func mergeInt(base, override int) int {
+	switch {
+	case override < 0:
+		return 0
+	case override > 0:
+		return override
+	}
+	return base
+}
+
+// commandTypeName 返回不含包名和指针的命令类型名，用于配置和指标标签
+func commandTypeName(cmd interface{}) string {
+	t := reflect.TypeOf(cmd)
+	if t == nil {
+		return ""
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Name()
+}
@@ -0,0 +1,192 @@
+package command_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/port/input/command"
+	"github.com/gohex/gohex/internal/testutil"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+func TestHandlerPolicies_For(t *testing.T) {
+	policies := command.HandlerPolicies{
+		Default: command.HandlerPolicy{Timeout: time.Second, MaxConcurrency: 4, RetryAttempts: 2, RetryDelay: time.Millisecond},
+		Commands: map[string]command.HandlerPolicy{
+			"renameCommand": {Timeout: 5 * time.Second, RetryAttempts: -1},
+		},
+	}
+
+	tests := []struct {
+		name string
+		cmd  interface{}
+		want command.HandlerPolicy
+	}{
+		{
+			name: "override merges with default",
+			cmd:  &renameCommand{},
+			want: command.HandlerPolicy{Timeout: 5 * time.Second, MaxConcurrency: 4, RetryDelay: time.Millisecond},
+		},
+		{
+			name: "value and pointer share the override",
+			cmd:  renameCommand{},
+			want: command.HandlerPolicy{Timeout: 5 * time.Second, MaxConcurrency: 4, RetryDelay: time.Millisecond},
+		},
+		{name: "other commands use default", cmd: &signUpCommand{}, want: policies.Default},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, policies.For(tt.cmd))
+		})
+	}
+}
+
+func TestTimeoutMiddleware(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		parent  time.Duration
+		wantErr error
+	}{
+		{name: "own deadline becomes command timeout", timeout: 10 * time.Millisecond, wantErr: errors.ErrCommandTimeout},
+		{name: "caller deadline is passed through", timeout: time.Minute, parent: 10 * time.Millisecond, wantErr: context.DeadlineExceeded},
+		{name: "no timeout configured", parent: 10 * time.Millisecond, wantErr: context.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := testutil.NewMetrics()
+			m := command.NewTimeoutMiddleware(command.HandlerPolicies{Default: command.HandlerPolicy{Timeout: tt.timeout}},
+				testutil.NopLogger{}, metrics)
+			ctx := context.Background()
+			if tt.parent > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.parent)
+				defer cancel()
+			}
+
+			_, err := m.Execute(ctx, &renameCommand{}, handlerFunc(func(ctx context.Context, cmd interface{}) (interface{}, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}))
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr == errors.ErrCommandTimeout {
+				assert.Equal(t, 1, metrics.Counter("command_timeout"))
+			} else {
+				assert.Zero(t, metrics.Counter("command_timeout"))
+			}
+		})
+	}
+}
+
+func TestConcurrencyMiddleware_RejectsOverLimit(t *testing.T) {
+	metrics := testutil.NewMetrics()
+	m := command.NewConcurrencyMiddleware(command.HandlerPolicies{Default: command.HandlerPolicy{MaxConcurrency: 1}},
+		testutil.NopLogger{}, metrics)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	blocking := handlerFunc(func(ctx context.Context, cmd interface{}) (interface{}, error) {
+		close(started)
+		<-release
+		return nil, nil
+	})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, err := m.Execute(context.Background(), &renameCommand{}, blocking)
+		assert.NoError(t, err)
+	}()
+	<-started
+
+	// 同类命令被拒绝，其他命令类型不受影响
+	_, err := m.Execute(context.Background(), &renameCommand{}, countingHandler(new(int32), 0))
+	assert.ErrorIs(t, err, errors.ErrCommandConcurrencyLimit)
+	_, err = m.Execute(context.Background(), &signUpCommand{}, countingHandler(new(int32), 0))
+	assert.NoError(t, err)
+
+	close(release)
+	wg.Wait()
+	_, err = m.Execute(context.Background(), &renameCommand{}, countingHandler(new(int32), 0))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, metrics.Counter("command_rejected"))
+}
+
+func TestRetryMiddleware(t *testing.T) {
+	tests := []struct {
+		name          string
+		errs          []error
+		wantCalls     int32
+		wantErr       error
+		wantRetries   int
+		wantExhausted int
+	}{
+		{name: "retries transient error", errs: []error{errors.ErrConcurrencyConflict}, wantCalls: 2, wantRetries: 1},
+		{
+			name:          "gives up after retry attempts",
+			errs:          []error{errors.ErrConcurrencyConflict, errors.ErrConcurrencyConflict, errors.ErrConcurrencyConflict},
+			wantCalls:     3,
+			wantErr:       errors.ErrConcurrencyConflict,
+			wantRetries:   2,
+			wantExhausted: 1,
+		},
+		{name: "does not retry other errors", errs: []error{errors.ErrUserNotFound}, wantCalls: 1, wantErr: errors.ErrUserNotFound},
+		{
+			name:        "retries wrapped deadlock",
+			errs:        []error{fmt.Errorf("update user: %w", fmt.Errorf("Error 1213: Deadlock found"))},
+			wantCalls:   2,
+			wantRetries: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := testutil.NewMetrics()
+			m := command.NewRetryMiddleware(command.HandlerPolicies{Default: command.HandlerPolicy{RetryAttempts: 2, RetryDelay: time.Millisecond}},
+				testutil.NopLogger{}, metrics)
+
+			var calls int32
+			_, err := m.Execute(context.Background(), &renameCommand{}, handlerFunc(func(ctx context.Context, cmd interface{}) (interface{}, error) {
+				n := atomic.AddInt32(&calls, 1)
+				if int(n) <= len(tt.errs) {
+					return nil, tt.errs[n-1]
+				}
+				return nil, nil
+			}))
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantCalls, calls)
+			assert.Equal(t, tt.wantRetries, metrics.Counter("command_retry"))
+			assert.Equal(t, tt.wantExhausted, metrics.Counter("command_retry_exhausted"))
+		})
+	}
+}
+
+func TestRetryMiddleware_StopsWhenContextDone(t *testing.T) {
+	m := command.NewRetryMiddleware(command.HandlerPolicies{Default: command.HandlerPolicy{RetryAttempts: 5, RetryDelay: time.Hour}},
+		testutil.NopLogger{}, testutil.NewMetrics())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	var calls int32
+	_, err := m.Execute(ctx, &renameCommand{}, handlerFunc(func(ctx context.Context, cmd interface{}) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		return nil, errors.ErrConcurrencyConflict
+	}))
+
+	assert.ErrorIs(t, err, errors.ErrConcurrencyConflict)
+	assert.Equal(t, int32(1), calls)
+}
@@ -0,0 +1,215 @@
+package command
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/pkg/actor"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+// IdempotentCommand 命令实现该接口后按自身提供的 key 去重，无需客户端传 Idempotency-Key。
+// 只适用于任何发起者重复执行都应得到同一结果的命令，注册、导出等请求只按客户端的 key 去重
+type IdempotentCommand interface {
+	IdempotencyKey() string
+}
+
+// CredentialCommand 携带密码或返回令牌的命令不去重，避免保存凭据摘要或向重放请求返回令牌
+type CredentialCommand interface {
+	CredentialCommand()
+}
+
+type idempotencyKey struct{}
+
+// WithIdempotencyKey 将客户端提供的幂等 key 写入上下文
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+// IdempotencyKeyFromContext 读取上下文中的幂等 key
+func IdempotencyKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKey{}).(string)
+	return key
+}
+
+// IdempotencyMiddleware 相同 key 的重复命令直接返回首次执行的结果。
+// 命令字段标记 idempotency:"-" 时不计入请求指纹，用于 IP 等每次请求都可能变化的元数据和密码
+type IdempotencyMiddleware struct {
+	store   output.IdempotencyStore
+	ttl     time.Duration
+	lockTTL time.Duration
+	logger  output.Logger
+	metrics output.MetricsReporter
+}
+
+func NewIdempotencyMiddleware(
+	store output.IdempotencyStore,
+	ttl time.Duration,
+	lockTTL time.Duration,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *IdempotencyMiddleware {
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+	if lockTTL <= 0 {
+		lockTTL = 30 * time.Second
+	}
+	return &IdempotencyMiddleware{
+		store:   store,
+		ttl:     ttl,
+		lockTTL: lockTTL,
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+func (m *IdempotencyMiddleware) Execute(ctx context.Context, cmd interface{}, next Handler) (interface{}, error) {
+	if _, ok := cmd.(CredentialCommand); ok {
+		return next.Handle(ctx, cmd)
+	}
+
+	key := m.resolveKey(ctx, cmd)
+	if key == "" {
+		return next.Handle(ctx, cmd)
+	}
+
+	fingerprint, err := fingerprintCommand(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. 占用 key，已存在时比较请求指纹
+	record, acquired, err := m.store.Acquire(ctx, key, fingerprint, m.lockTTL)
+	if err != nil {
+		m.logger.Error("failed to acquire idempotency key", "key", key, "error", err)
+		return nil, err
+	}
+	if !acquired {
+		return m.replay(record, fingerprint)
+	}
+
+	// 2. 执行命令并在期间续期锁，失败时释放 key 允许重试
+	stop := m.keepLocked(ctx, key)
+	result, err := next.Handle(ctx, cmd)
+	stop()
+	if err != nil {
+		if releaseErr := m.store.Release(ctx, key); releaseErr != nil {
+			m.logger.Error("failed to release idempotency key", "key", key, "error", releaseErr)
+		}
+		return nil, err
+	}
+
+	// 3. 保存结果，保存失败不影响本次命令结果
+	data, err := json.Marshal(result)
+	if err != nil {
+		m.logger.Error("failed to encode idempotent result", "key", key, "error", err)
+		return result, nil
+	}
+	if err := m.store.Complete(ctx, key, data, m.ttl); err != nil {
+		m.logger.Error("failed to store idempotent result", "key", key, "error", err)
+	}
+	return result, nil
+}
+
+// keepLocked 每三分之一锁超时续期一次，执行时间超过 lockTTL 的命令不会被重复请求再次执行。
+// 返回的 stop 等待续期协程退出，之后写入结果不会被续期覆盖
+func (m *IdempotencyMiddleware) keepLocked(ctx context.Context, key string) (stop func()) {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(m.lockTTL / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			}
+			if err := m.store.Extend(ctx, key, m.lockTTL); err != nil {
+				m.logger.Error("failed to extend idempotency lock", "key", key, "error", err)
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+func (m *IdempotencyMiddleware) replay(record *output.IdempotencyRecord, fingerprint string) (interface{}, error) {
+	if record.Fingerprint != fingerprint {
+		m.metrics.IncrementCounter("idempotency_key_reused")
+		return nil, errors.ErrIdempotencyKeyReused
+	}
+	if record.Status != output.IdempotencyCompleted {
+		m.metrics.IncrementCounter("idempotency_in_progress")
+		return nil, errors.ErrIdempotencyRequestInProgress
+	}
+
+	m.metrics.IncrementCounter("idempotency_replayed")
+	if len(record.Result) == 0 || string(record.Result) == "null" {
+		return nil, nil
+	}
+	// 返回原始 JSON，Dispatch 会按调用方声明的结果类型解码
+	return json.RawMessage(record.Result), nil
+}
+
+// resolveKey 优先使用客户端提供的 key，按发起者和命令类型隔离，避免不同用户的 key 冲突
+func (m *IdempotencyMiddleware) resolveKey(ctx context.Context, cmd interface{}) string {
+	key := IdempotencyKeyFromContext(ctx)
+	if key == "" {
+		if ic, ok := cmd.(IdempotentCommand); ok {
+			key = ic.IdempotencyKey()
+		}
+	}
+	if key == "" {
+		return ""
+	}
+
+	actorID := ""
+	if a, ok := actor.FromContext(ctx); ok {
+		actorID = a.RealActorID()
+	}
+	return fmt.Sprintf("%T:%s:%s", cmd, actorID, key)
+}
+
+// fingerprintCommand 计算命令内容的摘要，相同 key 不同内容视为误用
+func fingerprintCommand(cmd interface{}) (string, error) {
+	data, err := json.Marshal(fingerprintFields(cmd))
+	if err != nil {
+		return "", fmt.Errorf("failed to fingerprint command %T: %w", cmd, err)
+	}
+	sum := sha256.Sum256(append([]byte(fmt.Sprintf("%T:", cmd)), data...))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// fingerprintFields 返回参与指纹的导出字段，跳过标记 idempotency:"-" 的字段
+func fingerprintFields(cmd interface{}) interface{} {
+	v := reflect.Indirect(reflect.ValueOf(cmd))
+	if v.Kind() != reflect.Struct {
+		return cmd
+	}
+
+	fields := make(map[string]interface{}, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		if !f.IsExported() || f.Tag.Get("idempotency") == "-" {
+			continue
+		}
+		fields[f.Name] = v.Field(i).Interface()
+	}
+	return fields
+}
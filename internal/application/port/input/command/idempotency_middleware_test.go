@@ -0,0 +1,232 @@
+package command_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/port/input/command"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/testutil"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+// idempotencyStore 内存实现，记录到期后视为不存在
+type idempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]*output.IdempotencyRecord
+	expires map[string]time.Time
+}
+
+func newIdempotencyStore() *idempotencyStore {
+	return &idempotencyStore{
+		records: make(map[string]*output.IdempotencyRecord),
+		expires: make(map[string]time.Time),
+	}
+}
+
+func (s *idempotencyStore) live(key string) *output.IdempotencyRecord {
+	if time.Now().After(s.expires[key]) {
+		delete(s.records, key)
+	}
+	return s.records[key]
+}
+
+func (s *idempotencyStore) Acquire(ctx context.Context, key, fingerprint string, lockTTL time.Duration) (*output.IdempotencyRecord, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if record := s.live(key); record != nil {
+		copied := *record
+		return &copied, false, nil
+	}
+	s.records[key] = &output.IdempotencyRecord{Key: key, Fingerprint: fingerprint, Status: output.IdempotencyInProgress}
+	s.expires[key] = time.Now().Add(lockTTL)
+	return s.records[key], true, nil
+}
+
+func (s *idempotencyStore) Extend(ctx context.Context, key string, lockTTL time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.live(key) != nil {
+		s.expires[key] = time.Now().Add(lockTTL)
+	}
+	return nil
+}
+
+func (s *idempotencyStore) Complete(ctx context.Context, key string, result []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if record := s.live(key); record != nil {
+		record.Status, record.Result = output.IdempotencyCompleted, result
+		s.expires[key] = time.Now().Add(ttl)
+	}
+	return nil
+}
+
+func (s *idempotencyStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
+
+func (s *idempotencyStore) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.records)
+}
+
+type signUpCommand struct {
+	Email     string
+	Password  string `idempotency:"-"`
+	IP        string `idempotency:"-"`
+	UserAgent string `idempotency:"-"`
+}
+
+type signInCommand struct {
+	Email    string
+	Password string
+}
+
+func (*signInCommand) CredentialCommand() {}
+
+type purgeCommand struct {
+	UserID string
+}
+
+func (c *purgeCommand) IdempotencyKey() string { return c.UserID }
+
+// countingHandler 统计执行次数并返回递增的结果
+func countingHandler(calls *int32, delay time.Duration) command.Handler {
+	return handlerFunc(func(ctx context.Context, cmd interface{}) (interface{}, error) {
+		n := atomic.AddInt32(calls, 1)
+		time.Sleep(delay)
+		return map[string]int32{"call": n}, nil
+	})
+}
+
+func TestIdempotencyMiddleware_Fingerprint(t *testing.T) {
+	first := &signUpCommand{Email: "alice@example.com", Password: "secret-1", IP: "203.0.113.7", UserAgent: "ios/1.0"}
+
+	tests := []struct {
+		name    string
+		retry   *signUpCommand
+		wantErr error
+	}{
+		{name: "identical retry is replayed", retry: first},
+		{
+			name:  "changed metadata and password are ignored",
+			retry: &signUpCommand{Email: "alice@example.com", Password: "secret-2", IP: "198.51.100.2", UserAgent: "ios/1.1"},
+		},
+		{
+			name:    "changed payload is rejected",
+			retry:   &signUpCommand{Email: "bob@example.com", Password: "secret-1"},
+			wantErr: errors.ErrIdempotencyKeyReused,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			m := command.NewIdempotencyMiddleware(newIdempotencyStore(), time.Hour, time.Minute,
+				testutil.NopLogger{}, testutil.NewMetrics())
+			ctx := command.WithIdempotencyKey(context.Background(), "k1")
+			next := countingHandler(&calls, 0)
+
+			_, err := m.Execute(ctx, first, next)
+			require.NoError(t, err)
+
+			result, err := m.Execute(ctx, tt.retry, next)
+			assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.JSONEq(t, `{"call":1}`, string(result.(json.RawMessage)))
+		})
+	}
+}
+
+func TestIdempotencyMiddleware_CommandKey(t *testing.T) {
+	tests := []struct {
+		name      string
+		ctx       func(i int) context.Context
+		wantCalls int32
+	}{
+		{
+			name:      "command key deduplicates without a client key",
+			ctx:       func(int) context.Context { return context.Background() },
+			wantCalls: 1,
+		},
+		{
+			name: "client key takes precedence over the command key",
+			ctx: func(i int) context.Context {
+				return command.WithIdempotencyKey(context.Background(), fmt.Sprintf("k%d", i))
+			},
+			wantCalls: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			m := command.NewIdempotencyMiddleware(newIdempotencyStore(), time.Hour, time.Minute,
+				testutil.NopLogger{}, testutil.NewMetrics())
+			cmd := &purgeCommand{UserID: "alice"}
+
+			for i := 0; i < 2; i++ {
+				_, err := m.Execute(tt.ctx(i), cmd, countingHandler(&calls, 0))
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantCalls, atomic.LoadInt32(&calls))
+		})
+	}
+}
+
+func TestIdempotencyMiddleware_SkipsCredentialCommands(t *testing.T) {
+	var calls int32
+	store := newIdempotencyStore()
+	m := command.NewIdempotencyMiddleware(store, time.Hour, time.Minute, testutil.NopLogger{}, testutil.NewMetrics())
+	ctx := command.WithIdempotencyKey(context.Background(), "k1")
+	cmd := &signInCommand{Email: "alice@example.com", Password: "secret"}
+
+	for i := 0; i < 2; i++ {
+		_, err := m.Execute(ctx, cmd, countingHandler(&calls, 0))
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(2), calls)
+	assert.Zero(t, store.len())
+}
+
+func TestIdempotencyMiddleware_RenewsLockForLongCommands(t *testing.T) {
+	var calls int32
+	lockTTL := 30 * time.Millisecond
+	m := command.NewIdempotencyMiddleware(newIdempotencyStore(), time.Hour, lockTTL,
+		testutil.NopLogger{}, testutil.NewMetrics())
+	ctx := command.WithIdempotencyKey(context.Background(), "k1")
+	cmd := &signUpCommand{Email: "alice@example.com"}
+	next := countingHandler(&calls, 5*lockTTL)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := m.Execute(ctx, cmd, next)
+		done <- err
+	}()
+
+	// 首次执行已超过 lockTTL，重复请求仍应看到执行中的锁
+	time.Sleep(3 * lockTTL)
+	_, err := m.Execute(ctx, cmd, next)
+	assert.ErrorIs(t, err, errors.ErrIdempotencyRequestInProgress)
+
+	require.NoError(t, <-done)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
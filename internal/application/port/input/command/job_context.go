@@ -0,0 +1,28 @@
+package command
+
+import "context"
+
+type progressKey struct{}
+
+// ProgressFunc 上报异步命令的执行进度，percent 取值 0-100
+type ProgressFunc func(percent int)
+
+// WithProgress 由任务 worker 注入进度回调
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// ReportProgress 供长时间运行的处理器上报进度，同步执行时为空操作
+func ReportProgress(ctx context.Context, percent int) {
+	fn, ok := ctx.Value(progressKey{}).(ProgressFunc)
+	if !ok {
+		return
+	}
+	if percent < 0 {
+		percent = 0
+	}
+	if percent > 100 {
+		percent = 100
+	}
+	fn(percent)
+}
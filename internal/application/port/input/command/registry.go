@@ -0,0 +1,13 @@
+package command
+
+// HandlerInfo 已注册处理器的描述
+type HandlerInfo struct {
+	Type    string `json:"type"`
+	Handler string `json:"handler"`
+}
+
+// RegistryInfo 总线注册表快照，用于启动校验和调试
+type RegistryInfo struct {
+	Handlers   []HandlerInfo `json:"handlers"`
+	Middleware []string      `json:"middleware"`
+}
@@ -0,0 +1,66 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+// maxRetryDelay 指数退避的等待上限
+const maxRetryDelay = 30 * time.Second
+
+// RetryMiddleware 遇到死锁、并发冲突、网络错误等瞬时错误时按指数退避重试命令
+type RetryMiddleware struct {
+	policies HandlerPolicies
+	logger   output.Logger
+	metrics  output.MetricsReporter
+}
+
+func NewRetryMiddleware(policies HandlerPolicies, logger output.Logger, metrics output.MetricsReporter) *RetryMiddleware {
+	return &RetryMiddleware{
+		policies: policies,
+		logger:   logger,
+		metrics:  metrics,
+	}
+}
+
+func (m *RetryMiddleware) Execute(ctx context.Context, cmd interface{}, next Handler) (interface{}, error) {
+	policy := m.policies.For(cmd)
+	name := commandTypeName(cmd)
+	delay := policy.RetryDelay
+
+	for attempt := 0; ; attempt++ {
+		result, err := next.Handle(ctx, cmd)
+		if err == nil || !errors.IsTransient(err) {
+			return result, err
+		}
+		if attempt >= policy.RetryAttempts {
+			if attempt > 0 {
+				m.metrics.IncrementCounter("command_retry_exhausted", "command_type", name)
+			}
+			return result, err
+		}
+
+		m.logger.Warn("retrying command after transient error",
+			"command_type", name,
+			"attempt", attempt+1,
+			"delay", delay,
+			"error", err,
+		)
+		m.metrics.IncrementCounter("command_retry", "command_type", name)
+
+		// 等待期间上下文取消或超时则返回最后一次的错误
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, err
+		}
+
+		delay *= 2
+		if delay > maxRetryDelay {
+			delay = maxRetryDelay
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package command
+
+import (
+	"context"
+	stderrors "errors"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+// TimeoutMiddleware 为命令设置执行期限，超时后返回 ErrCommandTimeout
+type TimeoutMiddleware struct {
+	policies HandlerPolicies
+	logger   output.Logger
+	metrics  output.MetricsReporter
+}
+
+func NewTimeoutMiddleware(policies HandlerPolicies, logger output.Logger, metrics output.MetricsReporter) *TimeoutMiddleware {
+	return &TimeoutMiddleware{
+		policies: policies,
+		logger:   logger,
+		metrics:  metrics,
+	}
+}
+
+func (m *TimeoutMiddleware) Execute(ctx context.Context, cmd interface{}, next Handler) (interface{}, error) {
+	timeout := m.policies.For(cmd).Timeout
+	if timeout <= 0 {
+		return next.Handle(ctx, cmd)
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result, err := next.Handle(ctx, cmd)
+	// 只转换本中间件设置的期限，调用方自身的期限或取消原样返回
+	if err != nil && parent.Err() == nil && stderrors.Is(ctx.Err(), context.DeadlineExceeded) && stderrors.Is(err, context.DeadlineExceeded) {
+		name := commandTypeName(cmd)
+		m.logger.Warn("command timed out", "command_type", name, "timeout", timeout)
+		m.metrics.IncrementCounter("command_timeout", "command_type", name)
+		return nil, errors.ErrCommandTimeout
+	}
+	return result, err
+}
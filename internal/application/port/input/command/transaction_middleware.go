@@ -2,14 +2,16 @@ package command
 
 import (
     "context"
+
+    "github.com/gohex/gohex/internal/application/port/output"
 )
 
 type TransactionMiddleware struct {
-    uow    UnitOfWork
-    logger Logger
+    uow    output.UnitOfWork
+    logger output.Logger
 }
 
-func NewTransactionMiddleware(uow UnitOfWork, logger Logger) *TransactionMiddleware {
+func NewTransactionMiddleware(uow output.UnitOfWork, logger output.Logger) *TransactionMiddleware {
     return &TransactionMiddleware{
         uow:    uow,
         logger: logger,
@@ -0,0 +1,117 @@
+package command
+
+import (
+	"context"
+	"reflect"
+	"encoding/json"
+	"fmt"
+)
+
+// TypedHandler 强类型命令处理器，C 为命令类型，R 为结果类型，无返回值的命令使用 struct{}
+type TypedHandler[C any, R any] interface {
+	Handle(ctx context.Context, command C) (R, error)
+}
+
+// TypedHandlerFunc 函数形式的强类型命令处理器
+type TypedHandlerFunc[C any, R any] func(ctx context.Context, command C) (R, error)
+
+func (f TypedHandlerFunc[C, R]) Handle(ctx context.Context, command C) (R, error) {
+	return f(ctx, command)
+}
+
+// typedAdapter 将强类型处理器适配为 Handler，使其可以注册到总线并经过中间件链
+type typedAdapter[C any, R any] struct {
+	handler TypedHandler[C, R]
+}
+
+func (a typedAdapter[C, R]) Handle(ctx context.Context, command interface{}) (interface{}, error) {
+	cmd, ok := command.(C)
+	if !ok {
+		var zero C
+		return nil, fmt.Errorf("command type mismatch: expected %T, got %T", zero, command)
+	}
+	result, err := a.handler.Handle(ctx, cmd)
+	return untypedResult(result), err
+}
+
+// Unwrap 返回被包装的处理器，用于注册表展示
+func (a typedAdapter[C, R]) Unwrap() interface{} {
+	return a.handler
+}
+
+// untypedResult 将强类型结果转换为总线返回值，空指针和 struct{} 转换为 nil，
+// 与未迁移的处理器返回 nil 时的行为一致
+func untypedResult[R any](result R) interface{} {
+	v := reflect.ValueOf(result)
+	switch {
+	case !v.IsValid():
+		return nil
+	case v.Kind() == reflect.Struct && v.NumField() == 0:
+		return nil
+	case v.Kind() == reflect.Ptr || v.Kind() == reflect.Map || v.Kind() == reflect.Slice || v.Kind() == reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+	}
+	return result
+}
+
+// Adapt 将强类型处理器包装为 Handler
+func Adapt[C any, R any](handler TypedHandler[C, R]) Handler {
+	return typedAdapter[C, R]{handler: handler}
+}
+
+// untypedAdapter 将旧的 Handler 包装为强类型处理器，迁移期间使用
+type untypedAdapter[C any, R any] struct {
+	handler Handler
+}
+
+func (a untypedAdapter[C, R]) Handle(ctx context.Context, command C) (R, error) {
+	var zero R
+	result, err := a.handler.Handle(ctx, command)
+	if err != nil {
+		return zero, err
+	}
+	return castResult[R](result)
+}
+
+// FromHandler 将未迁移的 Handler 包装为强类型处理器
+func FromHandler[C any, R any](handler Handler) TypedHandler[C, R] {
+	return untypedAdapter[C, R]{handler: handler}
+}
+
+// RegisterHandler 以命令类型 C 为键注册强类型处理器，C 必须是具体类型
+func RegisterHandler[C any, R any](bus Bus, handler TypedHandler[C, R]) {
+	var zero C
+	bus.Register(zero, Adapt(handler))
+}
+
+// Dispatch 通过总线分发命令并返回强类型结果，中间件链照常执行
+func Dispatch[C any, R any](ctx context.Context, bus Bus, command C) (R, error) {
+	var zero R
+	result, err := bus.Dispatch(ctx, command)
+	if err != nil {
+		return zero, err
+	}
+	return castResult[R](result)
+}
+
+// castResult 将处理器返回值转换为 R，无返回值的命令得到零值
+func castResult[R any](result interface{}) (R, error) {
+	var zero R
+	if result == nil {
+		return zero, nil
+	}
+	r, ok := result.(R)
+	if raw, isRaw := result.(json.RawMessage); isRaw && !ok {
+		// 幂等中间件重放的结果以 JSON 保存，按 R 解码
+		if err := json.Unmarshal(raw, &r); err != nil {
+			return zero, fmt.Errorf("failed to decode replayed command result into %T: %w", zero, err)
+		}
+		return r, nil
+	}
+	if !ok {
+		return zero, fmt.Errorf("command result type mismatch: expected %T, got %T", zero, result)
+	}
+	return r, nil
+}
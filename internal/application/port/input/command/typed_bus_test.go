@@ -0,0 +1,110 @@
+package command_test
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/port/input/command"
+)
+
+// typedBus 按命令类型路由的最小总线，dispatch 可替换处理器返回值以模拟重放
+type typedBus struct {
+	command.Bus
+	handlers map[reflect.Type]command.Handler
+	dispatch func(result interface{}) interface{}
+}
+
+func (b *typedBus) Register(commandType interface{}, handler command.Handler) {
+	if b.handlers == nil {
+		b.handlers = make(map[reflect.Type]command.Handler)
+	}
+	b.handlers[reflect.TypeOf(commandType)] = handler
+}
+
+func (b *typedBus) Dispatch(ctx context.Context, cmd interface{}) (interface{}, error) {
+	result, err := b.handlers[reflect.TypeOf(cmd)].Handle(ctx, cmd)
+	if err != nil || b.dispatch == nil {
+		return result, err
+	}
+	return b.dispatch(result), nil
+}
+
+type renameResult struct {
+	UserID string `json:"user_id"`
+	Name   string `json:"name"`
+}
+
+func TestTypedBus_Dispatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		dispatch func(result interface{}) interface{}
+		want     *renameResult
+		wantErr  string
+	}{
+		{name: "returns typed result", want: &renameResult{UserID: "alice", Name: "Alice"}},
+		{
+			name: "decodes replayed result",
+			dispatch: func(result interface{}) interface{} {
+				raw, _ := json.Marshal(result)
+				return json.RawMessage(raw)
+			},
+			want: &renameResult{UserID: "alice", Name: "Alice"},
+		},
+		{
+			name:     "nil result yields zero value",
+			dispatch: func(interface{}) interface{} { return nil },
+		},
+		{
+			name:     "mismatched result is rejected",
+			dispatch: func(interface{}) interface{} { return "alice" },
+			wantErr:  "command result type mismatch",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := &typedBus{dispatch: tt.dispatch}
+			command.RegisterHandler[*renameCommand, *renameResult](bus, command.TypedHandlerFunc[*renameCommand, *renameResult](
+				func(ctx context.Context, cmd *renameCommand) (*renameResult, error) {
+					return &renameResult{UserID: cmd.UserID, Name: cmd.Name}, nil
+				}))
+
+			got, err := command.Dispatch[*renameCommand, *renameResult](context.Background(), bus,
+				&renameCommand{UserID: "alice", Name: "Alice"})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAdapt_RejectsMismatchedCommand(t *testing.T) {
+	h := command.Adapt[*renameCommand, *renameResult](command.TypedHandlerFunc[*renameCommand, *renameResult](
+		func(ctx context.Context, cmd *renameCommand) (*renameResult, error) {
+			t.Fatal("handler must not run")
+			return nil, nil
+		}))
+
+	_, err := h.Handle(context.Background(), &signUpCommand{})
+	assert.ErrorContains(t, err, "command type mismatch")
+}
+
+func TestFromHandler_WrapsUntypedHandler(t *testing.T) {
+	legacy := handlerFunc(func(ctx context.Context, cmd interface{}) (interface{}, error) {
+		return &renameResult{UserID: cmd.(*renameCommand).UserID}, nil
+	})
+
+	got, err := command.FromHandler[*renameCommand, *renameResult](legacy).
+		Handle(context.Background(), &renameCommand{UserID: "alice"})
+	require.NoError(t, err)
+	assert.Equal(t, "alice", got.UserID)
+}
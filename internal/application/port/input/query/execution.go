@@ -0,0 +1,46 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+// Execution 单次查询执行的统计信息，由中间件在执行过程中填充
+type Execution struct {
+	// Attempts 处理器实际执行次数，缓存命中时为 0
+	Attempts int
+	CacheHit bool
+	CacheKey string
+}
+
+type executionKey struct{}
+
+// WithExecution 在上下文中挂载执行统计，调用方在 Execute 返回后读取
+func WithExecution(ctx context.Context) (context.Context, *Execution) {
+	exec := &Execution{}
+	return context.WithValue(ctx, executionKey{}, exec), exec
+}
+
+// ExecutionFromContext 返回上下文中的执行统计，未挂载时返回 nil
+func ExecutionFromContext(ctx context.Context) *Execution {
+	exec, _ := ctx.Value(executionKey{}).(*Execution)
+	return exec
+}
+
+// TimeoutQuery 查询实现该接口后使用自身声明的超时时间，覆盖全局默认值
+type TimeoutQuery interface {
+	Timeout() time.Duration
+}
+
+// RetryClassifier 判断查询错误是否值得重试
+type RetryClassifier func(err error) bool
+
+// DefaultRetryClassifier 只重试死锁、连接中断等瞬时错误，校验失败、未找到、无权限等错误重试也不会成功
+func DefaultRetryClassifier(err error) bool {
+	if err == nil || err == ErrQueryTimeout {
+		return false
+	}
+	return errors.IsTransient(err)
+}
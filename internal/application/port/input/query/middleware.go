@@ -2,19 +2,17 @@ package query
 
 import (
 	"context"
+	"math/rand"
 	"reflect"
 	"time"
 
-	"github.com/your-project/port"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/pkg/errors"
 )
 
-type Middleware interface {
-	Execute(ctx context.Context, query interface{}, next Handler) (interface{}, error)
-}
-
 type ValidationMiddleware struct {
 	validator Validator
-	logger    Logger
+	logger    output.Logger
 }
 
 func (m *ValidationMiddleware) Execute(ctx context.Context, query interface{}, next Handler) (interface{}, error) {
@@ -23,20 +21,35 @@ func (m *ValidationMiddleware) Execute(ctx context.Context, query interface{}, n
 			return nil, errors.NewValidationError(err.Error())
 		}
 	}
+	if m.validator != nil {
+		if err := m.validator.Validate(query); err != nil {
+			return nil, err
+		}
+	}
 	return next.Handle(ctx, query)
 }
 
 type CacheMiddleware struct {
-	cache    port.Cache
-	logger   Logger
-	metrics  MetricsReporter
+	cache    output.Cache
+	logger   output.Logger
+	metrics  output.MetricsReporter
 }
 
 func (m *CacheMiddleware) Execute(ctx context.Context, query interface{}, next Handler) (interface{}, error) {
+	if _, ok := query.(HandlerCachedQuery); ok {
+		return next.Handle(ctx, query)
+	}
 	if cacheable, ok := query.(Cacheable); ok {
 		// 尝试从缓存获取
+		exec := ExecutionFromContext(ctx)
+		if exec != nil {
+			exec.CacheKey = cacheable.CacheKey()
+		}
 		if result, err := m.cache.Get(ctx, cacheable.CacheKey()); err == nil && result != nil {
 			m.metrics.IncrementCounter("cache_hit", "type", reflect.TypeOf(query).String())
+			if exec != nil {
+				exec.CacheHit = true
+			}
 			return result, nil
 		}
 		m.metrics.IncrementCounter("cache_miss", "type", reflect.TypeOf(query).String())
@@ -47,8 +60,8 @@ func (m *CacheMiddleware) Execute(ctx context.Context, query interface{}, next H
 			return nil, err
 		}
 
-		// 更新缓存
-		if err := m.cache.Set(ctx, cacheable.CacheKey(), result, cacheable.TTL()); err != nil {
+		// 更新缓存，带标签的查询结果可被相关事件按标签失效
+		if err := m.store(ctx, query, cacheable, result); err != nil {
 			m.logger.Error("failed to cache query result", "error", err)
 		}
 
@@ -57,15 +70,24 @@ func (m *CacheMiddleware) Execute(ctx context.Context, query interface{}, next H
 	return next.Handle(ctx, query)
 }
 
+func (m *CacheMiddleware) store(ctx context.Context, query interface{}, cacheable Cacheable, result interface{}) error {
+	tagged, ok := query.(TaggedQuery)
+	tc, supportsTags := m.cache.(output.TaggedCache)
+	if ok && supportsTags {
+		return tc.SetWithTags(ctx, cacheable.CacheKey(), result, cacheable.TTL(), tagged.CacheTags())
+	}
+	return m.cache.Set(ctx, cacheable.CacheKey(), result, cacheable.TTL())
+}
+
 // 添加工厂方法
-func NewValidationMiddleware(validator Validator, logger Logger) *ValidationMiddleware {
+func NewValidationMiddleware(validator Validator, logger output.Logger) *ValidationMiddleware {
 	return &ValidationMiddleware{
 		validator: validator,
 		logger:    logger,
 	}
 }
 
-func NewCacheMiddleware(cache port.Cache, logger Logger, metrics MetricsReporter) *CacheMiddleware {
+func NewCacheMiddleware(cache output.Cache, logger output.Logger, metrics output.MetricsReporter) *CacheMiddleware {
 	return &CacheMiddleware{
 		cache:   cache,
 		logger:  logger,
@@ -75,11 +97,11 @@ func NewCacheMiddleware(cache port.Cache, logger Logger, metrics MetricsReporter
 
 // 添加日志中间件
 type LoggingMiddleware struct {
-	logger  Logger
-	metrics MetricsReporter
+	logger  output.Logger
+	metrics output.MetricsReporter
 }
 
-func NewLoggingMiddleware(logger Logger, metrics MetricsReporter) *LoggingMiddleware {
+func NewLoggingMiddleware(logger output.Logger, metrics output.MetricsReporter) *LoggingMiddleware {
 	return &LoggingMiddleware{
 		logger:  logger,
 		metrics: metrics,
@@ -113,52 +135,113 @@ func (m *LoggingMiddleware) Execute(ctx context.Context, query interface{}, next
 	return result, err
 }
 
-// 添加新的中间件
+// RetryMiddleware 按指数退避加随机抖动重试瞬时错误，等待期间响应上下文取消
 type RetryMiddleware struct {
 	maxRetries int
 	backoff    time.Duration
-	logger     Logger
+	classifier RetryClassifier
+	logger     output.Logger
+	metrics    output.MetricsReporter
+}
+
+// maxRetryBackoff 单次重试等待的上限
+const maxRetryBackoff = 5 * time.Second
+
+func NewRetryMiddleware(maxRetries int, backoff time.Duration, classifier RetryClassifier, logger output.Logger, metrics output.MetricsReporter) *RetryMiddleware {
+	if backoff <= 0 {
+		backoff = 100 * time.Millisecond
+	}
+	if classifier == nil {
+		classifier = DefaultRetryClassifier
+	}
+	return &RetryMiddleware{
+		maxRetries: maxRetries,
+		backoff:    backoff,
+		classifier: classifier,
+		logger:     logger,
+		metrics:    metrics,
+	}
 }
 
 func (m *RetryMiddleware) Execute(ctx context.Context, query interface{}, next Handler) (interface{}, error) {
-	var lastErr error
-	for i := 0; i < m.maxRetries; i++ {
+	exec := ExecutionFromContext(ctx)
+	queryType := reflect.TypeOf(query).String()
+
+	for attempt := 0; ; attempt++ {
+		if exec != nil {
+			exec.Attempts++
+		}
+
 		result, err := next.Handle(ctx, query)
-		if err == nil {
-			return result, nil
+		if err == nil || attempt >= m.maxRetries || !m.classifier(err) {
+			return result, err
+		}
+
+		delay := m.delay(attempt)
+		m.logger.Warn("retrying query after transient error",
+			"type", queryType,
+			"attempt", attempt+1,
+			"delay", delay,
+			"error", err,
+		)
+		m.metrics.IncrementCounter("query_retry", "type", queryType)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
 		}
-		lastErr = err
-		time.Sleep(m.backoff * time.Duration(i+1))
 	}
-	return nil, lastErr
 }
 
+// delay 返回第 attempt 次重试前的等待时间，在指数退避值的一半到全值之间随机取值，避免并发请求同时重试
+func (m *RetryMiddleware) delay(attempt int) time.Duration {
+	d := m.backoff << uint(attempt)
+	if d <= 0 || d > maxRetryBackoff {
+		d = maxRetryBackoff
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// TimeoutMiddleware 为查询设置执行期限，查询实现 TimeoutQuery 时使用其声明的超时。
+// 处理器在同一个 goroutine 中执行，超时后由处理器响应上下文取消返回，不会遗留后台 goroutine
 type TimeoutMiddleware struct {
 	timeout time.Duration
-	logger  Logger
+	logger  output.Logger
+	metrics output.MetricsReporter
+}
+
+func NewTimeoutMiddleware(timeout time.Duration, logger output.Logger, metrics output.MetricsReporter) *TimeoutMiddleware {
+	return &TimeoutMiddleware{
+		timeout: timeout,
+		logger:  logger,
+		metrics: metrics,
+	}
 }
 
 func (m *TimeoutMiddleware) Execute(ctx context.Context, query interface{}, next Handler) (interface{}, error) {
-	ctx, cancel := context.WithTimeout(ctx, m.timeout)
-	defer cancel()
+	timeout := m.timeout
+	if tq, ok := query.(TimeoutQuery); ok && tq.Timeout() > 0 {
+		timeout = tq.Timeout()
+	}
+	if timeout <= 0 {
+		return next.Handle(ctx, query)
+	}
 
-	done := make(chan struct {
-		result interface{}
-		err    error
-	})
+	parent := ctx
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-	go func() {
-		result, err := next.Handle(ctx, query)
-		done <- struct {
-			result interface{}
-			err    error
-		}{result, err}
-	}()
-
-	select {
-	case <-ctx.Done():
-		return nil, errors.ErrQueryTimeout
-	case res := <-done:
-		return res.result, res.err
-	}
-} 
\ No newline at end of file
+	result, err := next.Handle(ctx, query)
+	// 只转换本中间件设置的期限，调用方自身的期限或取消原样返回
+	if err != nil && parent.Err() == nil && errors.Is(ctx.Err(), context.DeadlineExceeded) && errors.Is(err, context.DeadlineExceeded) {
+		queryType := reflect.TypeOf(query).String()
+		m.logger.Warn("query timed out", "type", queryType, "timeout", timeout)
+		m.metrics.IncrementCounter("query_timeout", "type", queryType)
+		return nil, ErrQueryTimeout
+	}
+	return result, err
+}
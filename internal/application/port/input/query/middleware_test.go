@@ -0,0 +1,157 @@
+package query_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/port/input/query"
+	"github.com/gohex/gohex/internal/infrastructure/adapter/secondary/cache/memory"
+	"github.com/gohex/gohex/internal/testutil"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+type handlerFunc func(ctx context.Context, q interface{}) (interface{}, error)
+
+func (f handlerFunc) Handle(ctx context.Context, q interface{}) (interface{}, error) {
+	return f(ctx, q)
+}
+
+// failingHandler 前 len(errs) 次依次返回 errs，之后返回 "ok"
+func failingHandler(calls *int32, errs ...error) query.Handler {
+	return handlerFunc(func(ctx context.Context, q interface{}) (interface{}, error) {
+		n := atomic.AddInt32(calls, 1)
+		if int(n) <= len(errs) {
+			return nil, errs[n-1]
+		}
+		return "ok", nil
+	})
+}
+
+var errInvalidPage = errors.NewValidationError("page must be positive")
+
+type slowQuery struct{ timeout time.Duration }
+
+func (q *slowQuery) Timeout() time.Duration { return q.timeout }
+
+type cachedQuery struct{}
+
+func (cachedQuery) CacheKey() string   { return "query:cached" }
+func (cachedQuery) TTL() time.Duration { return time.Minute }
+
+func TestRetryMiddleware(t *testing.T) {
+	tests := []struct {
+		name         string
+		errs         []error
+		wantAttempts int
+		wantErr      error
+	}{
+		{name: "retries transient errors", errs: []error{errors.ErrConcurrencyConflict, errors.ErrConcurrencyConflict}, wantAttempts: 3},
+		{name: "stops after max retries", errs: []error{errors.ErrConcurrencyConflict, errors.ErrConcurrencyConflict, errors.ErrConcurrencyConflict}, wantAttempts: 3, wantErr: errors.ErrConcurrencyConflict},
+		{name: "does not retry not found", errs: []error{errors.ErrUserNotFound}, wantAttempts: 1, wantErr: errors.ErrUserNotFound},
+		{name: "does not retry validation", errs: []error{errInvalidPage}, wantAttempts: 1, wantErr: errInvalidPage},
+		{name: "does not retry timeout", errs: []error{query.ErrQueryTimeout}, wantAttempts: 1, wantErr: query.ErrQueryTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := query.NewRetryMiddleware(2, time.Millisecond, nil, testutil.NopLogger{}, testutil.NewMetrics())
+			ctx, exec := query.WithExecution(context.Background())
+
+			var calls int32
+			result, err := m.Execute(ctx, &slowQuery{}, failingHandler(&calls, tt.errs...))
+
+			assert.Equal(t, tt.wantAttempts, exec.Attempts)
+			assert.Equal(t, int32(tt.wantAttempts), calls)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "ok", result)
+		})
+	}
+}
+
+func TestRetryMiddleware_StopsWaitingWhenContextDone(t *testing.T) {
+	m := query.NewRetryMiddleware(5, time.Minute, nil, testutil.NopLogger{}, testutil.NewMetrics())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	var calls int32
+	start := time.Now()
+	_, err := m.Execute(ctx, &slowQuery{}, failingHandler(&calls, errors.ErrConcurrencyConflict, errors.ErrConcurrencyConflict))
+
+	assert.ErrorIs(t, err, errors.ErrConcurrencyConflict)
+	assert.Equal(t, int32(1), calls)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestTimeoutMiddleware(t *testing.T) {
+	tests := []struct {
+		name         string
+		defaultTTL   time.Duration
+		parentTTL    time.Duration
+		query        *slowQuery
+		wantErr      error
+		wantTimeouts int
+	}{
+		{name: "default timeout applies", defaultTTL: 10 * time.Millisecond, query: &slowQuery{}, wantErr: query.ErrQueryTimeout, wantTimeouts: 1},
+		{name: "query timeout overrides default", defaultTTL: time.Minute, query: &slowQuery{timeout: 10 * time.Millisecond}, wantErr: query.ErrQueryTimeout, wantTimeouts: 1},
+		// 调用方的期限先到时原样返回，不计为查询超时
+		{name: "caller deadline passes through", defaultTTL: time.Minute, parentTTL: 10 * time.Millisecond, query: &slowQuery{}, wantErr: context.DeadlineExceeded},
+		{name: "completes within timeout", defaultTTL: time.Minute, query: &slowQuery{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := testutil.NewMetrics()
+			m := query.NewTimeoutMiddleware(tt.defaultTTL, testutil.NopLogger{}, metrics)
+
+			ctx := context.Background()
+			if tt.parentTTL > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.parentTTL)
+				defer cancel()
+			}
+
+			result, err := m.Execute(ctx, tt.query, handlerFunc(func(ctx context.Context, q interface{}) (interface{}, error) {
+				select {
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				case <-time.After(50 * time.Millisecond):
+					return "ok", nil
+				}
+			}))
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, tt.wantTimeouts, metrics.Counter("query_timeout"))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "ok", result)
+		})
+	}
+}
+
+func TestCacheMiddleware_RecordsExecution(t *testing.T) {
+	cache := memory.NewCache(time.Minute, testutil.NopLogger{}, testutil.NewMetrics())
+	m := query.NewCacheMiddleware(cache, testutil.NopLogger{}, testutil.NewMetrics())
+	var calls int32
+	next := failingHandler(&calls)
+
+	for _, wantHit := range []bool{false, true} {
+		ctx, exec := query.WithExecution(context.Background())
+		result, err := m.Execute(ctx, cachedQuery{}, next)
+		require.NoError(t, err)
+		assert.Equal(t, "ok", result)
+		assert.Equal(t, wantHit, exec.CacheHit)
+		assert.Equal(t, "query:cached", exec.CacheKey)
+	}
+	assert.Equal(t, int32(1), calls)
+}
@@ -1,17 +1,19 @@
 package query
 
-import "context"
+import "time"
 
-type Bus interface {
-    Execute(ctx context.Context, query interface{}) (interface{}, error)
-    Register(queryType interface{}, handler Handler)
+type Cacheable interface {
+    CacheKey() string
+    TTL() time.Duration
 }
 
-type Handler interface {
-    Handle(ctx context.Context, query interface{}) (interface{}, error)
+// TaggedQuery 可缓存查询实现该接口后，结果按标签写入缓存，由领域事件按标签失效
+type TaggedQuery interface {
+    CacheTags() []string
 }
 
-type Cacheable interface {
-    CacheKey() string
-    TTL() time.Duration
-} 
\ No newline at end of file
+// HandlerCachedQuery 处理器通过 TypedCache 自行缓存结果的查询实现该接口，CacheMiddleware 不再读写同一 key，
+// 避免把 L2 中按 JSON 解码出的 map 当作命中结果返回
+type HandlerCachedQuery interface {
+    CachedByHandler()
+}
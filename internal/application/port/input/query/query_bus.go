@@ -9,8 +9,12 @@ import (
 type Bus interface {
 	// Execute 执行查询并返回结果
 	Execute(ctx context.Context, query interface{}) (interface{}, error)
-	// Register 注册查询处理器
+	// Register 注册查询处理器，指针与值类型视为同一查询
 	Register(queryType interface{}, handler Handler)
+	// Describe 返回已注册的处理器和中间件
+	Describe() RegistryInfo
+	// Verify 检查注册错误以及 required 中的查询是否都有处理器
+	Verify(required ...interface{}) error
 }
 
 // Handler 定义查询处理器接口
@@ -0,0 +1,109 @@
+package query
+
+import (
+	"context"
+	"reflect"
+	"fmt"
+)
+
+// TypedHandler 强类型查询处理器，Q 为查询类型，R 为结果类型
+type TypedHandler[Q any, R any] interface {
+	Handle(ctx context.Context, query Q) (R, error)
+}
+
+// TypedHandlerFunc 函数形式的强类型查询处理器
+type TypedHandlerFunc[Q any, R any] func(ctx context.Context, query Q) (R, error)
+
+func (f TypedHandlerFunc[Q, R]) Handle(ctx context.Context, query Q) (R, error) {
+	return f(ctx, query)
+}
+
+// typedAdapter 将强类型处理器适配为 Handler，使其可以注册到总线并经过中间件链
+type typedAdapter[Q any, R any] struct {
+	handler TypedHandler[Q, R]
+}
+
+func (a typedAdapter[Q, R]) Handle(ctx context.Context, query interface{}) (interface{}, error) {
+	q, ok := query.(Q)
+	if !ok {
+		var zero Q
+		return nil, fmt.Errorf("query type mismatch: expected %T, got %T", zero, query)
+	}
+	result, err := a.handler.Handle(ctx, q)
+	return untypedResult(result), err
+}
+
+// Unwrap 返回被包装的处理器，用于注册表展示
+func (a typedAdapter[Q, R]) Unwrap() interface{} {
+	return a.handler
+}
+
+// untypedResult 将强类型结果转换为总线返回值，空指针和 struct{} 转换为 nil，
+// 与未迁移的处理器返回 nil 时的行为一致
+func untypedResult[R any](result R) interface{} {
+	v := reflect.ValueOf(result)
+	switch {
+	case !v.IsValid():
+		return nil
+	case v.Kind() == reflect.Struct && v.NumField() == 0:
+		return nil
+	case v.Kind() == reflect.Ptr || v.Kind() == reflect.Map || v.Kind() == reflect.Slice || v.Kind() == reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+	}
+	return result
+}
+
+// Adapt 将强类型处理器包装为 Handler
+func Adapt[Q any, R any](handler TypedHandler[Q, R]) Handler {
+	return typedAdapter[Q, R]{handler: handler}
+}
+
+// untypedAdapter 将旧的 Handler 包装为强类型处理器，迁移期间使用
+type untypedAdapter[Q any, R any] struct {
+	handler Handler
+}
+
+func (a untypedAdapter[Q, R]) Handle(ctx context.Context, query Q) (R, error) {
+	var zero R
+	result, err := a.handler.Handle(ctx, query)
+	if err != nil {
+		return zero, err
+	}
+	return castResult[R](result)
+}
+
+// FromHandler 将未迁移的 Handler 包装为强类型处理器
+func FromHandler[Q any, R any](handler Handler) TypedHandler[Q, R] {
+	return untypedAdapter[Q, R]{handler: handler}
+}
+
+// RegisterHandler 以查询类型 Q 为键注册强类型处理器，Q 必须是具体类型
+func RegisterHandler[Q any, R any](bus Bus, handler TypedHandler[Q, R]) {
+	var zero Q
+	bus.Register(zero, Adapt(handler))
+}
+
+// Execute 通过总线执行查询并返回强类型结果，缓存等中间件照常执行
+func Execute[Q any, R any](ctx context.Context, bus Bus, query Q) (R, error) {
+	var zero R
+	result, err := bus.Execute(ctx, query)
+	if err != nil {
+		return zero, err
+	}
+	return castResult[R](result)
+}
+
+// castResult 将处理器或缓存返回值转换为 R
+func castResult[R any](result interface{}) (R, error) {
+	var zero R
+	if result == nil {
+		return zero, nil
+	}
+	r, ok := result.(R)
+	if !ok {
+		return zero, fmt.Errorf("query result type mismatch: expected %T, got %T", zero, result)
+	}
+	return r, nil
+}
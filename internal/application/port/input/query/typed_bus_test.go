@@ -0,0 +1,82 @@
+package query_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/port/input/query"
+)
+
+// typedBus 按查询类型路由的最小总线，cached 非空时模拟缓存命中
+type typedBus struct {
+	query.Bus
+	handlers map[reflect.Type]query.Handler
+	cached   interface{}
+}
+
+func (b *typedBus) Register(queryType interface{}, handler query.Handler) {
+	if b.handlers == nil {
+		b.handlers = make(map[reflect.Type]query.Handler)
+	}
+	b.handlers[reflect.TypeOf(queryType)] = handler
+}
+
+func (b *typedBus) Execute(ctx context.Context, q interface{}) (interface{}, error) {
+	if b.cached != nil {
+		return b.cached, nil
+	}
+	return b.handlers[reflect.TypeOf(q)].Handle(ctx, q)
+}
+
+type getNameQuery struct {
+	UserID string
+}
+
+type otherQuery struct{}
+
+func TestTypedBus_Execute(t *testing.T) {
+	tests := []struct {
+		name    string
+		cached  interface{}
+		want    string
+		wantErr string
+	}{
+		{name: "returns typed result", want: "name-of-alice"},
+		{name: "accepts cached result of the same type", cached: "cached", want: "cached"},
+		{name: "rejects cached result of another type", cached: 42, wantErr: "result type mismatch"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := &typedBus{cached: tt.cached}
+			query.RegisterHandler[*getNameQuery, string](bus, query.TypedHandlerFunc[*getNameQuery, string](
+				func(ctx context.Context, q *getNameQuery) (string, error) {
+					return "name-of-" + q.UserID, nil
+				}))
+
+			got, err := query.Execute[*getNameQuery, string](context.Background(), bus, &getNameQuery{UserID: "alice"})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.Empty(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAdapt_RejectsMismatchedQuery(t *testing.T) {
+	h := query.Adapt[*getNameQuery, string](query.TypedHandlerFunc[*getNameQuery, string](
+		func(ctx context.Context, q *getNameQuery) (string, error) {
+			t.Fatal("handler must not run")
+			return "", nil
+		}))
+
+	_, err := h.Handle(context.Background(), &otherQuery{})
+	assert.ErrorContains(t, err, "query type mismatch")
+}
@@ -1,5 +1,7 @@
 package query
 
+import "reflect"
+
 type Validator interface {
     Validate(interface{}) error
 }
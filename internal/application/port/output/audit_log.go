@@ -0,0 +1,71 @@
+package output
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// 审计结果
+const (
+	AuditResultSuccess = "success"
+	AuditResultFailure = "failure"
+	AuditResultDenied  = "denied"
+)
+
+// ErrAuditChainTruncated 哈希链首尾与锚点不一致，有记录在保留期清理之外被整段删除
+var ErrAuditChainTruncated = errors.New("audit chain does not match its anchors")
+
+// AuditLog 定义只追加的审计日志接口
+type AuditLog interface {
+	// Append 追加审计记录，实现需保证哈希链连续
+	Append(ctx context.Context, entry *AuditEntry) error
+	// Find 按条件查询审计记录
+	Find(ctx context.Context, filter AuditFilter) ([]*AuditEntry, int64, error)
+	// Verify 校验 [from, to] 区间内的哈希链，返回第一条被篡改的记录 ID；
+	// 区间不设起点或终点时同时与锚点比对，链首或链尾缺失时返回 ErrAuditChainTruncated
+	Verify(ctx context.Context, from, to time.Time) (string, error)
+	// RedactSubject 擦除主体相关记录中的变更内容，哈希链凭 changes_hash 仍可校验
+	RedactSubject(ctx context.Context, subjectID string) (int64, error)
+	// Purge 删除超过保留期的记录，并将被删除部分的最后一条记录写入锚点
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// AuditEntry 审计记录
+type AuditEntry struct {
+	ID             string                 `json:"id"`
+	Sequence       int64                  `json:"sequence"`
+	ActorID        string                 `json:"actor_id"`
+	ImpersonatorID string                 `json:"impersonator_id,omitempty"`
+	SubjectID      string                 `json:"subject_id"`
+	Action         string                 `json:"action"`
+	Result         string                 `json:"result"`
+	Error          string                 `json:"error,omitempty"`
+	IP             string                 `json:"ip"`
+	UserAgent      string                 `json:"user_agent"`
+	TraceID        string                 `json:"trace_id"`
+	Changes        map[string]AuditChange `json:"changes,omitempty"`
+	// ChangesHash 变更内容的摘要，参与哈希链计算，changes 被擦除后仍可校验
+	ChangesHash string    `json:"changes_hash"`
+	PrevHash    string    `json:"prev_hash"`
+	Hash        string    `json:"hash"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
+
+// AuditChange 字段变更前后的值
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditFilter 审计查询条件
+type AuditFilter struct {
+	ActorID   string
+	SubjectID string
+	Action    string
+	Result    string
+	From      time.Time
+	To        time.Time
+	Offset    int
+	Limit     int
+}
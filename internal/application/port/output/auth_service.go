@@ -3,6 +3,8 @@ package output
 import (
     "context"
     "time"
+
+    "github.com/gohex/gohex/internal/domain/aggregate"
 )
 
 type AuthService interface {
@@ -20,13 +22,6 @@ type AuthService interface {
     IsTokenRevoked(ctx context.Context, token string) bool
 }
 
-type TokenClaims struct {
-    UserID    string
-    Email     string
-    Roles     []string
-    ExpiresAt time.Time
-}
-
 type TokenInfo struct {
     TokenClaims
     IssuedAt  time.Time
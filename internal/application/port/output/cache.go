@@ -19,6 +19,37 @@ type Cache interface {
 	Keys(ctx context.Context, pattern string) ([]string, error)
 }
 
+// TagUsersList 所有用户列表查询共享的缓存标签
+const TagUsersList = "users:list"
+
+// UserCacheTag 单个用户相关缓存的标签
+func UserCacheTag(userID string) string {
+	return "user:" + userID
+}
+
+// TaggedCache 支持为缓存项附加标签，并按标签批量失效
+type TaggedCache interface {
+	Cache
+	// SetWithTags 写入缓存并将 key 记录到每个标签的索引中
+	SetWithTags(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) error
+	// InvalidateTags 删除带有任一标签的所有缓存项
+	InvalidateTags(ctx context.Context, tags ...string) error
+}
+
+// RawCache 以编码后的字节读写的缓存，强类型缓存通过它按 Codec 编解码，
+// 避免先解码为 map 再转换为具体类型
+type RawCache interface {
+	// GetRaw 未命中时返回 nil
+	GetRaw(ctx context.Context, key string) ([]byte, error)
+	SetRaw(ctx context.Context, key string, data []byte, ttl time.Duration, tags []string) error
+}
+
+// LocalCache 进程内缓存层，值保持写入时的具体类型，命中时无需解码
+type LocalCache interface {
+	GetLocal(key string) (interface{}, bool)
+	SetLocal(key string, value interface{}, ttl time.Duration, tags []string)
+}
+
 // Cacheable 定义可缓存接口
 type Cacheable interface {
 	CacheKey() string
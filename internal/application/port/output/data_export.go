@@ -0,0 +1,38 @@
+package output
+
+import (
+	"context"
+	"time"
+)
+
+// 数据导出状态
+const (
+	DataExportPending    = "pending"
+	DataExportProcessing = "processing"
+	DataExportCompleted  = "completed"
+	DataExportFailed     = "failed"
+)
+
+// DataExport 用户数据导出请求
+type DataExport struct {
+	ID          string
+	UserID      string
+	RequestedBy string
+	Status      string
+	Archive     []byte
+	Error       string
+	RequestedAt time.Time
+	CompletedAt *time.Time
+	ExpiresAt   *time.Time
+}
+
+// DataExportRepository 数据导出请求仓储
+type DataExportRepository interface {
+	Save(ctx context.Context, export *DataExport) error
+	Update(ctx context.Context, export *DataExport) error
+	FindByID(ctx context.Context, id string) (*DataExport, error)
+	// ClaimPending 将一条待处理请求标记为处理中并返回，没有时返回 nil
+	ClaimPending(ctx context.Context) (*DataExport, error)
+	DeleteByUserID(ctx context.Context, userID string) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
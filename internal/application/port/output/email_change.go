@@ -0,0 +1,34 @@
+package output
+
+import (
+	"context"
+	"time"
+)
+
+// PendingEmailChange 待确认的邮箱变更，只保存令牌摘要
+type PendingEmailChange struct {
+	ID               string
+	UserID           string
+	OldEmail         string
+	NewEmail         string
+	ConfirmTokenHash string
+	CancelTokenHash  string
+	RequestedAt      time.Time
+	ExpiresAt        time.Time
+}
+
+// IsExpired 是否已过期
+func (c *PendingEmailChange) IsExpired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
+
+// EmailChangeRepository 邮箱变更请求仓储，每个用户最多一条待确认记录
+type EmailChangeRepository interface {
+	Save(ctx context.Context, change *PendingEmailChange) error
+	FindByConfirmToken(ctx context.Context, tokenHash string) (*PendingEmailChange, error)
+	FindByCancelToken(ctx context.Context, tokenHash string) (*PendingEmailChange, error)
+	Delete(ctx context.Context, id string) error
+	DeleteByUserID(ctx context.Context, userID string) error
+	// DeleteExpired 删除已过期的待确认记录，返回删除条数
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
@@ -7,6 +7,10 @@ type EmailService interface {
     SendVerificationEmail(email string, verificationCode string) error
     SendLoginNotification(email string, ip string, userAgent string) error
     SendAccountLockedNotification(email string, reason string) error
+    // SendEmailChangeConfirmation 向新邮箱发送确认链接
+    SendEmailChangeConfirmation(newEmail string, confirmToken string) error
+    // SendEmailChangeNotice 通知旧邮箱并附带取消链接
+    SendEmailChangeNotice(oldEmail string, newEmail string, cancelToken string) error
 }
 
 type EmailTemplateConfig struct {
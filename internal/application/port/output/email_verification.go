@@ -0,0 +1,30 @@
+package output
+
+import (
+	"context"
+	"time"
+)
+
+// EmailVerification 待完成的邮箱验证，只保存令牌摘要
+type EmailVerification struct {
+	ID        string
+	UserID    string
+	Email     string
+	TokenHash string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// IsExpired 是否已过期
+func (v *EmailVerification) IsExpired(now time.Time) bool {
+	return !now.Before(v.ExpiresAt)
+}
+
+// EmailVerificationRepository 邮箱验证仓储，每个用户最多一条待验证记录
+type EmailVerificationRepository interface {
+	Save(ctx context.Context, verification *EmailVerification) error
+	// FindByID 记录已被重新发送替换或已完成验证时返回 ErrEmailVerificationNotFound
+	FindByID(ctx context.Context, id string) (*EmailVerification, error)
+	FindByToken(ctx context.Context, tokenHash string) (*EmailVerification, error)
+	Delete(ctx context.Context, id string) error
+}
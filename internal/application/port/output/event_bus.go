@@ -2,7 +2,7 @@ package output
 
 import (
 	"context"
-	"github.com/your-org/your-project/internal/domain/event"
+	"github.com/gohex/gohex/internal/domain/event"
 )
 
 type EventBus interface {
@@ -2,23 +2,16 @@ package output
 
 import (
 	"context"
-	"github.com/your-org/your-project/internal/domain/event"
-	"time"
+
+	"github.com/gohex/gohex/internal/domain/event"
 )
 
+// EventStore 事件存储，按聚合版本追加并读取事件
 type EventStore interface {
+	// SaveEvents 追加事件，聚合当前版本不等于 expectedVersion 时返回 ErrConcurrencyConflict
 	SaveEvents(ctx context.Context, aggregateID string, events []event.Event, expectedVersion int) error
 	GetEvents(ctx context.Context, aggregateID string) ([]event.Event, error)
-	GetEventsFrom(ctx context.Context, aggregateID string, fromVersion int) ([]event.Event, error)
-	GetEventsByType(ctx context.Context, eventType string) ([]event.Event, error)
-	GetEventsByTimeRange(ctx context.Context, startTime, endTime time.Time) ([]event.Event, error)
-	GetAggregateHistory(ctx context.Context, aggregateID string) (*AggregateHistory, error)
+	// RedactEvents 清空聚合未加密事件的数据，用于擦除启用加密前写入的历史事件，
+	// 不携带个人数据的事件保留原样；被清空的事件读取时只保留描述信息
+	RedactEvents(ctx context.Context, aggregateID string) (int64, error)
 }
-
-type AggregateHistory struct {
-	AggregateID string
-	Events      []event.Event
-	Version     int
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-} 
\ No newline at end of file
@@ -0,0 +1,33 @@
+package output
+
+import (
+	"context"
+	"time"
+)
+
+// 幂等记录状态
+const (
+	IdempotencyInProgress = "in_progress"
+	IdempotencyCompleted  = "completed"
+)
+
+// IdempotencyRecord 幂等 key 对应的请求指纹和执行结果
+type IdempotencyRecord struct {
+	Key         string    `json:"key"`
+	Fingerprint string    `json:"fingerprint"`
+	Status      string    `json:"status"`
+	Result      []byte    `json:"result,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// IdempotencyStore 保存幂等记录，Acquire 必须是原子操作以处理并发重复请求
+type IdempotencyStore interface {
+	// Acquire 占用 key 并标记为执行中，key 已存在时返回已有记录且 acquired 为 false
+	Acquire(ctx context.Context, key, fingerprint string, lockTTL time.Duration) (record *IdempotencyRecord, acquired bool, err error)
+	// Extend 将执行中锁的超时时间重置为 lockTTL，长时间运行的命令执行期间定期调用
+	Extend(ctx context.Context, key string, lockTTL time.Duration) error
+	// Complete 保存执行结果，ttl 内的重复请求直接返回该结果
+	Complete(ctx context.Context, key string, result []byte, ttl time.Duration) error
+	// Release 执行失败时释放 key，允许客户端重试
+	Release(ctx context.Context, key string) error
+}
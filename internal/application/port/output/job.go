@@ -0,0 +1,69 @@
+package output
+
+import (
+	"context"
+	"time"
+)
+
+// 异步任务状态
+const (
+	JobPending   = "pending"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+	JobCancelled = "cancelled"
+	// JobDeadLettered 多次因实例退出而中断的任务不再重试，需要人工处理
+	JobDeadLettered = "dead_lettered"
+)
+
+// Job 异步执行的命令
+type Job struct {
+	ID          string
+	CommandType string
+	Payload     []byte
+	Status      string
+	Progress    int
+	Result      []byte
+	Error       string
+	RequestedBy string
+	// ImpersonatorID 模拟会话中入队时的实际操作人
+	ImpersonatorID string
+	// IP 和 UserAgent 入队请求的来源，执行时恢复到审计上下文
+	IP        string
+	UserAgent string
+	// Attempts 已被领取的次数，每次 Claim 加一
+	Attempts int
+	// CancelRequested 运行中的任务被请求取消，由 worker 中断执行
+	CancelRequested bool
+	CreatedAt       time.Time
+	StartedAt       *time.Time
+	FinishedAt      *time.Time
+	HeartbeatAt     *time.Time
+}
+
+// IsFinished 任务是否已结束
+func (j *Job) IsFinished() bool {
+	return j.Status == JobSucceeded || j.Status == JobFailed || j.Status == JobCancelled ||
+		j.Status == JobDeadLettered
+}
+
+// JobQueue 持久化的异步任务队列
+type JobQueue interface {
+	Enqueue(ctx context.Context, job *Job) error
+	FindByID(ctx context.Context, id string) (*Job, error)
+	// Claim 将一条待执行任务标记为运行中并返回，没有时返回 nil
+	Claim(ctx context.Context) (*Job, error)
+	// Heartbeat 更新运行中任务的心跳和进度，返回是否已被请求取消
+	Heartbeat(ctx context.Context, id string, progress int) (bool, error)
+	Complete(ctx context.Context, id string, result []byte) error
+	Fail(ctx context.Context, id string, reason string) error
+	// Cancel 待执行任务直接取消，运行中任务标记为请求取消
+	Cancel(ctx context.Context, id string) error
+	// MarkCancelled 运行中任务响应取消请求后调用
+	MarkCancelled(ctx context.Context, id string) error
+	// RequeueStale 将心跳早于 before 的运行中任务重新置为待执行，用于进程崩溃后恢复；
+	// 已领取 maxAttempts 次的任务标记为死信，返回重新入队和死信的任务数
+	RequeueStale(ctx context.Context, before time.Time, maxAttempts int) (requeued, deadLettered int64, err error)
+	// DeleteFinished 删除结束时间早于 before 的任务
+	DeleteFinished(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,19 @@
+package output
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrKeyShredded 主体的加密密钥已被删除，相关数据不可恢复
+var ErrKeyShredded = errors.New("encryption key has been shredded")
+
+// KeyStore 管理按主体（用户）划分的数据加密密钥，用于加密粉碎
+type KeyStore interface {
+	// GetOrCreateKey 获取主体密钥，不存在时创建
+	GetOrCreateKey(ctx context.Context, subjectID string) ([]byte, error)
+	// GetKey 获取主体密钥，密钥已删除时返回 ErrKeyShredded
+	GetKey(ctx context.Context, subjectID string) ([]byte, error)
+	// DeleteKey 删除主体密钥，之后该主体的加密数据无法解密
+	DeleteKey(ctx context.Context, subjectID string) error
+}
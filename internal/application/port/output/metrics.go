@@ -4,6 +4,8 @@ type MetricsReporter interface {
     IncrementCounter(name string, tags ...string)
     Gauge(name string, value float64, tags ...string)
     StartTimer(name string, tags ...string) Timer
+    // Histogram 记录一次观测值，如以秒为单位的延迟
+    Histogram(name string, value float64, tags ...string)
 }
 
 type Timer interface {
@@ -0,0 +1,10 @@
+package output
+
+import "context"
+
+// ProcessedEventStore 记录事件处理器已处理过的事件，用于至少一次投递下的去重
+type ProcessedEventStore interface {
+	// MarkProcessed 记录 (handlerID, eventID)，已存在时返回 false。
+	// 在事务中调用时与处理器的写入一起提交或回滚
+	MarkProcessed(ctx context.Context, handlerID, eventID string) (bool, error)
+}
@@ -0,0 +1,9 @@
+package output
+
+import "context"
+
+// ProvisioningService 在下游系统中开通和注销用户账号，实现需保证重复调用无副作用
+type ProvisioningService interface {
+	Provision(ctx context.Context, userID string, email string) error
+	Deprovision(ctx context.Context, userID string) error
+}
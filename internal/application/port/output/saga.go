@@ -0,0 +1,54 @@
+package output
+
+import (
+	"context"
+	"time"
+)
+
+// saga 实例状态
+const (
+	SagaRunning = "running"
+	// SagaCompensating 某一步失败，正在逆序执行已完成步骤的补偿
+	SagaCompensating = "compensating"
+	SagaCompleted    = "completed"
+	// SagaCompensated 失败且补偿已全部完成
+	SagaCompensated = "compensated"
+	// SagaFailed 补偿也失败，需要人工处理
+	SagaFailed = "failed"
+)
+
+// SagaInstance 一个流程实例的持久化状态
+type SagaInstance struct {
+	ID   string
+	Type string
+	// CorrelationID 用于把领域事件关联到实例，同一类型下唯一
+	CorrelationID string
+	Status        string
+	// Step 当前步骤下标
+	Step int
+	Data map[string]string
+	// DeadlineAt 当前步骤等待事件的截止时间
+	DeadlineAt *time.Time
+	// TimeoutScheduleID 到期时触发失败的计划命令，步骤推进时取消
+	TimeoutScheduleID string
+	Error      string
+	// Version 乐观锁版本，事件和超时并发推进同一实例时只有一方成功
+	Version   int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// IsFinished 是否已结束
+func (s *SagaInstance) IsFinished() bool {
+	return s.Status == SagaCompleted || s.Status == SagaCompensated || s.Status == SagaFailed
+}
+
+// SagaRepository saga 实例仓储
+type SagaRepository interface {
+	// Save 保存新实例，同一类型和关联 ID 已存在时返回冲突错误
+	Save(ctx context.Context, instance *SagaInstance) error
+	// Update 按版本更新实例并递增版本，版本不一致时返回 ErrConcurrencyConflict
+	Update(ctx context.Context, instance *SagaInstance) error
+	FindByID(ctx context.Context, id string) (*SagaInstance, error)
+	FindByCorrelation(ctx context.Context, sagaType, correlationID string) (*SagaInstance, error)
+}
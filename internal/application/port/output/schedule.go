@@ -0,0 +1,80 @@
+package output
+
+import (
+	"context"
+	"time"
+)
+
+// 计划命令状态
+const (
+	ScheduleActive = "active"
+	// ScheduleCompleted 一次性计划已执行
+	ScheduleCompleted = "completed"
+	ScheduleFailed    = "failed"
+	ScheduleCancelled = "cancelled"
+)
+
+// ScheduledCommand 在指定时间或按 cron 表达式周期执行的命令
+type ScheduledCommand struct {
+	ID string
+	// Name 周期计划的唯一名称，一次性计划为空
+	Name        string
+	CommandType string
+	Payload     []byte
+	// Cron 标准五段式 cron 表达式，为空表示一次性计划
+	Cron      string
+	NextRunAt time.Time
+	Status    string
+	Runs      int
+	LastRunAt *time.Time
+	LastError string
+	// RequestedBy 计划创建者，执行时作为命令的发起者
+	RequestedBy    string
+	ImpersonatorID string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// IsRecurring 是否为周期计划
+func (s *ScheduledCommand) IsRecurring() bool {
+	return s.Cron != ""
+}
+
+// ScheduleFilter 计划查询条件
+type ScheduleFilter struct {
+	Status      string
+	CommandType string
+	Offset      int
+	Limit       int
+}
+
+// ScheduleStore 计划命令存储
+type ScheduleStore interface {
+	Save(ctx context.Context, schedule *ScheduledCommand) error
+	// SaveNamed 按 Name 保存周期计划，已存在时更新命令、表达式和下次执行时间，返回计划 ID
+	SaveNamed(ctx context.Context, schedule *ScheduledCommand) (string, error)
+	FindByID(ctx context.Context, id string) (*ScheduledCommand, error)
+	List(ctx context.Context, filter ScheduleFilter) ([]*ScheduledCommand, int64, error)
+	// Cancel 取消仍在等待执行的计划
+	Cancel(ctx context.Context, id string) error
+	// FindDue 返回下次执行时间不晚于 now 的计划，按执行时间排序
+	FindDue(ctx context.Context, now time.Time, limit int) ([]*ScheduledCommand, error)
+	// RecordRun 记录一次执行结果，更新状态和下次执行时间
+	RecordRun(ctx context.Context, schedule *ScheduledCommand) error
+}
+
+// CommandScheduler 延迟或周期执行命令，命令到期后经命令总线的中间件链执行
+type CommandScheduler interface {
+	// Schedule 在 runAt 执行一次命令，返回计划 ID
+	Schedule(ctx context.Context, command interface{}, runAt time.Time) (string, error)
+	// ScheduleCron 按 cron 表达式周期执行命令，同名计划已存在时更新
+	ScheduleCron(ctx context.Context, name string, command interface{}, expr string) (string, error)
+}
+
+// LeaderLease 基于租约的选主，同一时刻只有一个持有者
+type LeaderLease interface {
+	// TryAcquire 获取或续约租约，租约被其他未过期的持有者占用时返回 false
+	TryAcquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+	// Release 释放自己持有的租约
+	Release(ctx context.Context, name, holder string) error
+}
@@ -3,20 +3,45 @@ package output
 import (
 	"context"
 	"time"
-	"github.com/your-org/your-project/internal/domain/aggregate"
+	"github.com/gohex/gohex/internal/domain/aggregate"
 )
 
 // TokenClaims 令牌声明
 type TokenClaims struct {
-	UserID    string    `json:"user_id"`
-	Email     string    `json:"email"`
-	Roles     []string  `json:"roles"`
-	ExpiresAt time.Time `json:"exp"`
+	UserID    string      `json:"user_id"`
+	Email     string      `json:"email"`
+	Roles     []string    `json:"roles"`
+	ExpiresAt time.Time   `json:"exp"`
+	Actor     *TokenActor `json:"act,omitempty"`
+}
+
+// TokenActor 代表实际操作人 (RFC 8693 act 声明)
+type TokenActor struct {
+	Subject string `json:"sub"`
+}
+
+// IsImpersonated 令牌是否为模拟令牌
+func (c *TokenClaims) IsImpersonated() bool {
+	return c.Actor != nil && c.Actor.Subject != ""
+}
+
+// ActorID 返回实际操作人，非模拟令牌时即为 UserID
+func (c *TokenClaims) ActorID() string {
+	if c.IsImpersonated() {
+		return c.Actor.Subject
+	}
+	return c.UserID
 }
 
 // TokenService 令牌服务接口
 type TokenService interface {
 	GenerateToken(user *aggregate.User) (string, time.Time, error)
+	// GenerateImpersonationToken 生成代表 subject、由 actor 持有的短期令牌
+	GenerateImpersonationToken(actor *aggregate.User, subject *aggregate.User, ttl time.Duration) (string, time.Time, error)
+	// GeneratePasswordResetToken 生成仅用于重置密码的短期令牌
+	GeneratePasswordResetToken(user *aggregate.User) (string, error)
 	ValidateToken(ctx context.Context, token string) (*TokenClaims, error)
 	RevokeToken(ctx context.Context, token string) error
+	// RevokeUserTokens 吊销用户在此之前签发的全部令牌
+	RevokeUserTokens(ctx context.Context, userID string) error
 } 
\ No newline at end of file
@@ -0,0 +1,152 @@
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Codec 缓存值的编解码器
+type Codec[T any] interface {
+	Encode(value T) ([]byte, error)
+	Decode(data []byte) (T, error)
+}
+
+// JSONCodec 使用 JSON 编解码缓存值
+type JSONCodec[T any] struct{}
+
+func (JSONCodec[T]) Encode(value T) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (JSONCodec[T]) Decode(data []byte) (T, error) {
+	var value T
+	err := json.Unmarshal(data, &value)
+	return value, err
+}
+
+// TypedCache 强类型缓存，依次查找进程内缓存和远程缓存，并合并同一 key 的并发回源请求
+type TypedCache[T any] struct {
+	cache Cache
+	codec Codec[T]
+	group singleflight.Group
+}
+
+// NewTypedCache 创建强类型缓存，codec 为 nil 时使用 JSON
+func NewTypedCache[T any](cache Cache, codec Codec[T]) *TypedCache[T] {
+	if codec == nil {
+		codec = JSONCodec[T]{}
+	}
+	return &TypedCache[T]{
+		cache: cache,
+		codec: codec,
+	}
+}
+
+// Get 读取缓存，第二个返回值表示是否命中
+func (c *TypedCache[T]) Get(ctx context.Context, key string) (T, bool, error) {
+	return c.lookup(ctx, key, 0, nil)
+}
+
+// Set 编码后写入远程缓存，并以具体类型写入进程内缓存
+func (c *TypedCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration, tags ...string) error {
+	data, err := c.codec.Encode(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache value %s: %w", key, err)
+	}
+
+	if rc, ok := c.cache.(RawCache); ok {
+		err = rc.SetRaw(ctx, key, data, ttl, tags)
+	} else if tc, ok := c.cache.(TaggedCache); ok {
+		err = tc.SetWithTags(ctx, key, json.RawMessage(data), ttl, tags)
+	} else {
+		err = c.cache.Set(ctx, key, json.RawMessage(data), ttl)
+	}
+	if err != nil {
+		return err
+	}
+
+	if lc, ok := c.cache.(LocalCache); ok {
+		lc.SetLocal(key, value, ttl, tags)
+	}
+	return nil
+}
+
+// GetOrLoad 未命中时调用 load 回源并写入缓存；同一 key 的并发未命中只回源一次。
+// 缓存读写失败不影响结果，只会多一次回源
+func (c *TypedCache[T]) GetOrLoad(
+	ctx context.Context,
+	key string,
+	ttl time.Duration,
+	tags []string,
+	load func(ctx context.Context) (T, error),
+) (T, error) {
+	if value, ok, err := c.lookup(ctx, key, ttl, tags); err == nil && ok {
+		return value, nil
+	}
+
+	v, err, _ := c.group.Do(key, func() (interface{}, error) {
+		// 排队期间前一个请求可能已经写入缓存
+		if value, ok, err := c.lookup(ctx, key, ttl, tags); err == nil && ok {
+			return value, nil
+		}
+
+		value, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		_ = c.Set(ctx, key, value, ttl, tags...)
+		return value, nil
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	value, _ := v.(T)
+	return value, nil
+}
+
+// lookup 查找缓存；从远程缓存解码后，tags 非空时回填进程内缓存，
+// 没有标签的值不回填，避免按标签失效时遗漏进程内副本
+func (c *TypedCache[T]) lookup(ctx context.Context, key string, ttl time.Duration, tags []string) (T, bool, error) {
+	var zero T
+
+	lc, local := c.cache.(LocalCache)
+	if local {
+		if v, ok := lc.GetLocal(key); ok {
+			if value, ok := v.(T); ok {
+				return value, true, nil
+			}
+		}
+	}
+
+	data, err := c.getRaw(ctx, key)
+	if err != nil || data == nil {
+		return zero, false, err
+	}
+
+	value, err := c.codec.Decode(data)
+	if err != nil {
+		return zero, false, fmt.Errorf("failed to decode cache value %s: %w", key, err)
+	}
+	if local && len(tags) > 0 {
+		lc.SetLocal(key, value, ttl, tags)
+	}
+	return value, true, nil
+}
+
+// getRaw 缓存不支持字节读取时，将通用解码结果重新编码为 JSON
+func (c *TypedCache[T]) getRaw(ctx context.Context, key string) ([]byte, error) {
+	if rc, ok := c.cache.(RawCache); ok {
+		return rc.GetRaw(ctx, key)
+	}
+
+	v, err := c.cache.Get(ctx, key)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
@@ -0,0 +1,81 @@
+package output_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/dto"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/infrastructure/adapter/secondary/cache/memory"
+	"github.com/gohex/gohex/internal/testutil"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+func newMemoryCache() output.TaggedCache {
+	return memory.NewCache(time.Minute, testutil.NopLogger{}, testutil.NewMetrics())
+}
+
+func TestTypedCache_GetOrLoadKeepsConcreteType(t *testing.T) {
+	ctx := context.Background()
+	users := output.NewTypedCache[*dto.UserDTO](newMemoryCache(), nil)
+	load := func(ctx context.Context) (*dto.UserDTO, error) {
+		return &dto.UserDTO{ID: "alice", Email: "alice@example.com"}, nil
+	}
+
+	_, err := users.GetOrLoad(ctx, "user:id:alice", time.Minute, nil, load)
+	require.NoError(t, err)
+
+	got, ok, err := users.Get(ctx, "user:id:alice")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, &dto.UserDTO{ID: "alice", Email: "alice@example.com"}, got)
+}
+
+func TestTypedCache_GetOrLoadCoalescesMisses(t *testing.T) {
+	ctx := context.Background()
+	users := output.NewTypedCache[*dto.UserDTO](newMemoryCache(), nil)
+
+	var loads int32
+	release := make(chan struct{})
+	load := func(ctx context.Context) (*dto.UserDTO, error) {
+		atomic.AddInt32(&loads, 1)
+		<-release
+		return &dto.UserDTO{ID: "alice"}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := users.GetOrLoad(ctx, "user:id:alice", time.Minute, nil, load)
+			assert.NoError(t, err)
+			assert.Equal(t, "alice", got.ID)
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), loads)
+}
+
+func TestTypedCache_GetOrLoadDoesNotCacheErrors(t *testing.T) {
+	ctx := context.Background()
+	users := output.NewTypedCache[*dto.UserDTO](newMemoryCache(), nil)
+
+	_, err := users.GetOrLoad(ctx, "user:id:alice", time.Minute, nil, func(ctx context.Context) (*dto.UserDTO, error) {
+		return nil, errors.ErrUserNotFound
+	})
+	assert.ErrorIs(t, err, errors.ErrUserNotFound)
+
+	_, ok, err := users.Get(ctx, "user:id:alice")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
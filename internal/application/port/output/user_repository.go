@@ -2,29 +2,27 @@ package output
 
 import (
 	"context"
-	"github.com/your-org/your-project/internal/domain/aggregate"
-	"github.com/your-org/your-project/internal/domain/vo"
+	"time"
+	"github.com/gohex/gohex/internal/domain/aggregate"
+	"github.com/gohex/gohex/internal/domain/vo"
 )
 
 type UserRepository interface {
 	// 基本操作
 	Save(ctx context.Context, user *aggregate.User) error
 	Update(ctx context.Context, user *aggregate.User) error
+	// Delete 软删除用户
 	Delete(ctx context.Context, id string) error
+	// Purge 永久删除已软删除的用户
+	Purge(ctx context.Context, id string) error
 
 	// 查询方法
 	FindByID(ctx context.Context, id string) (*aggregate.User, error)
 	FindByEmail(ctx context.Context, email vo.Email) (*aggregate.User, error)
 	FindAll(ctx context.Context, params FindAllParams) ([]*aggregate.User, int64, error)
 	ExistsByEmail(ctx context.Context, email vo.Email) (bool, error)
-
-	// 批量操作
-	SaveBatch(ctx context.Context, users []*aggregate.User) error
-	FindByIDs(ctx context.Context, ids []string) ([]*aggregate.User, error)
-
-	// 统计方法
-	Count(ctx context.Context, status string) (int64, error)
-	CountByRole(ctx context.Context, role vo.UserRole) (int64, error)
+	// FindDeletedBefore 按 ID 升序返回 before 之前软删除且 ID 大于 afterID 的用户，用于分页遍历
+	FindDeletedBefore(ctx context.Context, before time.Time, afterID string, limit int) ([]*aggregate.User, error)
 }
 
 type FindAllParams struct {
@@ -34,4 +32,6 @@ type FindAllParams struct {
 	Limit    int
 	SortBy   string
 	SortDir  string
+	// IncludeDeleted 为 true 时包含已软删除的用户
+	IncludeDeleted bool
 } 
\ No newline at end of file
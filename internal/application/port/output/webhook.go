@@ -0,0 +1,108 @@
+package output
+
+import (
+	"context"
+	"time"
+
+	"github.com/gohex/gohex/internal/domain/event"
+)
+
+// WebhookAllEvents 订阅所有事件类型
+const WebhookAllEvents = "*"
+
+// WebhookEventTypes 可以通过 webhook 订阅的事件，登录、密码和模拟会话等安全相关事件不对外推送
+var WebhookEventTypes = []string{
+	event.UserCreated,
+	event.UserProfileUpdated,
+	event.UserEmailChanged,
+	event.UserStatusChanged,
+	event.UserLocked,
+	event.UserUnlocked,
+	event.UserDeactivated,
+	event.UserReactivated,
+	event.RoleAssigned,
+	event.RoleRevoked,
+	event.UserDeleted,
+	event.UserRestored,
+	event.UserErased,
+}
+
+// webhook 投递状态
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	// WebhookDeliveryFailed 重试次数耗尽，只能手动重新投递
+	WebhookDeliveryFailed = "failed"
+)
+
+// WebhookSubscription 合作方订阅的 webhook
+type WebhookSubscription struct {
+	ID          string
+	URL         string
+	// Secret 签名密钥，只在创建时返回给调用方
+	Secret      string
+	EventTypes  []string
+	Description string
+	Active      bool
+	// ConsecutiveFailures 连续投递失败次数，成功后清零，达到阈值时自动停用
+	ConsecutiveFailures int
+	DisabledReason      string
+	CreatedBy           string
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+	DisabledAt          *time.Time
+}
+
+// Matches 订阅是否包含指定事件类型
+func (s *WebhookSubscription) Matches(eventType string) bool {
+	for _, t := range s.EventTypes {
+		if t == WebhookAllEvents || t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery 一个事件到一个订阅的投递记录
+type WebhookDelivery struct {
+	ID             string
+	SubscriptionID string
+	EventID        string
+	EventType      string
+	Payload        []byte
+	Status         string
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastStatusCode int
+	LastError      string
+	// RedeliveryOf 手动重新投递时指向原投递记录
+	RedeliveryOf string
+	CreatedAt    time.Time
+	DeliveredAt  *time.Time
+}
+
+// WebhookRepository webhook 订阅和投递记录仓储
+type WebhookRepository interface {
+	SaveSubscription(ctx context.Context, sub *WebhookSubscription) error
+	UpdateSubscription(ctx context.Context, sub *WebhookSubscription) error
+	FindSubscription(ctx context.Context, id string) (*WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]*WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id string) error
+	// FindActiveSubscriptions 返回订阅了指定事件类型的启用中的订阅
+	FindActiveSubscriptions(ctx context.Context, eventType string) ([]*WebhookSubscription, error)
+	// RecordSuccess 清零订阅的连续失败次数
+	RecordSuccess(ctx context.Context, subscriptionID string) error
+	// RecordFailure 增加订阅的连续失败次数，达到 disableAfter 时停用订阅并返回 true
+	RecordFailure(ctx context.Context, subscriptionID string, disableAfter int, reason string) (bool, error)
+
+	SaveDeliveries(ctx context.Context, deliveries []*WebhookDelivery) error
+	UpdateDelivery(ctx context.Context, delivery *WebhookDelivery) error
+	FindDelivery(ctx context.Context, id string) (*WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, subscriptionID string, offset, limit int) ([]*WebhookDelivery, int64, error)
+	// ClaimDue 领取最多 limit 条到期的待投递记录，领取后 lease 内不会被其他实例领取
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*WebhookDelivery, error)
+	// RedactDeliveries 清空聚合相关投递记录中的事件数据，用于擦除个人信息
+	RedactDeliveries(ctx context.Context, aggregateID string) (int64, error)
+	// DeleteFinished 删除创建时间早于 before 的已结束投递记录
+	DeleteFinished(ctx context.Context, before time.Time) (int64, error)
+}
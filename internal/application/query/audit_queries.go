@@ -0,0 +1,109 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	"github.com/gohex/gohex/internal/application/dto"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+// ListAuditLogsQuery 审计日志查询
+type ListAuditLogsQuery struct {
+	Filter   output.AuditFilter
+	Page     int
+	PageSize int
+}
+
+func (q ListAuditLogsQuery) Validate() error {
+	if q.Page <= 0 {
+		return errors.NewValidationError("page must be greater than 0")
+	}
+	if q.PageSize <= 0 || q.PageSize > 100 {
+		return errors.NewValidationError("page size must be between 1 and 100")
+	}
+	if !q.Filter.From.IsZero() && !q.Filter.To.IsZero() && q.Filter.From.After(q.Filter.To) {
+		return errors.NewValidationError("from must be before to")
+	}
+	return nil
+}
+
+type ListAuditLogsHandler struct {
+	auditLog output.AuditLog
+	logger   output.Logger
+	metrics  output.MetricsReporter
+}
+
+func NewListAuditLogsHandler(auditLog output.AuditLog, logger output.Logger, metrics output.MetricsReporter) *ListAuditLogsHandler {
+	return &ListAuditLogsHandler{
+		auditLog: auditLog,
+		logger:   logger,
+		metrics:  metrics,
+	}
+}
+
+func (h *ListAuditLogsHandler) Handle(ctx context.Context, query *ListAuditLogsQuery) (*PagedResult, error) {
+	filter := query.Filter
+	filter.Offset = (query.Page - 1) * query.PageSize
+	filter.Limit = query.PageSize
+
+	entries, total, err := h.auditLog.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*dto.AuditLogDTO, len(entries))
+	for i, entry := range entries {
+		items[i] = dto.NewAuditLogDTO(entry)
+	}
+
+	return NewPagedResult(items, total, query.Page, query.PageSize), nil
+}
+
+// ExportAuditLogsQuery 审计日志导出查询
+type ExportAuditLogsQuery struct {
+	Filter  output.AuditFilter
+	MaxRows int
+}
+
+// Timeout 导出可能扫描大量记录，超时时间长于普通查询
+func (q ExportAuditLogsQuery) Timeout() time.Duration {
+	return 2 * time.Minute
+}
+
+type ExportAuditLogsHandler struct {
+	auditLog output.AuditLog
+	logger   output.Logger
+	metrics  output.MetricsReporter
+}
+
+func NewExportAuditLogsHandler(auditLog output.AuditLog, logger output.Logger, metrics output.MetricsReporter) *ExportAuditLogsHandler {
+	return &ExportAuditLogsHandler{
+		auditLog: auditLog,
+		logger:   logger,
+		metrics:  metrics,
+	}
+}
+
+func (h *ExportAuditLogsHandler) Handle(ctx context.Context, query *ExportAuditLogsQuery) ([]*dto.AuditLogDTO, error) {
+	filter := query.Filter
+	filter.Offset = 0
+	filter.Limit = query.MaxRows
+
+	entries, total, err := h.auditLog.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if query.MaxRows > 0 && total > int64(query.MaxRows) {
+		return nil, errors.NewValidationError("too many audit entries, narrow the filter")
+	}
+
+	items := make([]*dto.AuditLogDTO, len(entries))
+	for i, entry := range entries {
+		items[i] = dto.NewAuditLogDTO(entry)
+	}
+
+	h.metrics.IncrementCounter("audit_export")
+	return items, nil
+}
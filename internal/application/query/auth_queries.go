@@ -2,7 +2,9 @@ package query
 
 import (
 	"context"
-	"github.com/your-org/your-project/internal/application/dto"
+	"github.com/gohex/gohex/internal/application/dto"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/pkg/errors"
 )
 
 // ValidateTokenQuery 验证令牌查询
@@ -11,15 +13,22 @@ type ValidateTokenQuery struct {
 }
 
 type ValidateTokenHandler struct {
-	tokenSvc port.TokenService
-	userRepo port.UserRepository
-	logger   Logger
-	metrics  MetricsReporter
+	tokenSvc output.TokenService
+	userRepo output.UserRepository
+	logger   output.Logger
+	metrics  output.MetricsReporter
 }
 
-func (h *ValidateTokenHandler) Handle(ctx context.Context, q interface{}) (interface{}, error) {
-	query := q.(*ValidateTokenQuery)
+func NewValidateTokenHandler(tokenSvc output.TokenService, userRepo output.UserRepository, logger output.Logger, metrics output.MetricsReporter) *ValidateTokenHandler {
+	return &ValidateTokenHandler{
+		tokenSvc: tokenSvc,
+		userRepo: userRepo,
+		logger:   logger,
+		metrics:  metrics,
+	}
+}
 
+func (h *ValidateTokenHandler) Handle(ctx context.Context, query *ValidateTokenQuery) (*dto.AuthInfoDTO, error) {
 	// 1. 验证令牌
 	claims, err := h.tokenSvc.ValidateToken(ctx, query.Token)
 	if err != nil {
@@ -43,5 +52,6 @@ func (h *ValidateTokenHandler) Handle(ctx context.Context, q interface{}) (inter
 		Email:     user.Email().String(),
 		Roles:     user.RoleStrings(),
 		ExpiresAt: claims.ExpiresAt,
+		ActorID:   claims.ActorID(),
 	}, nil
 } 
\ No newline at end of file
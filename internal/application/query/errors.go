@@ -1,7 +1,5 @@
 package query
 
-import "github.com/your-org/your-project/pkg/errors"
-
 type QueryError struct {
     Code    string
     Message string
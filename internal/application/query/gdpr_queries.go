@@ -0,0 +1,56 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+// GetDataExportQuery 获取数据导出归档
+type GetDataExportQuery struct {
+	UserID   string
+	ExportID string
+}
+
+func (q GetDataExportQuery) Validate() error {
+	if q.UserID == "" {
+		return errors.NewValidationError("user_id is required")
+	}
+	if q.ExportID == "" {
+		return errors.NewValidationError("export_id is required")
+	}
+	return nil
+}
+
+type GetDataExportHandler struct {
+	exportRepo output.DataExportRepository
+	logger     output.Logger
+	metrics    output.MetricsReporter
+}
+
+func NewGetDataExportHandler(exportRepo output.DataExportRepository, logger output.Logger, metrics output.MetricsReporter) *GetDataExportHandler {
+	return &GetDataExportHandler{
+		exportRepo: exportRepo,
+		logger:     logger,
+		metrics:    metrics,
+	}
+}
+
+func (h *GetDataExportHandler) Handle(ctx context.Context, query *GetDataExportQuery) (*output.DataExport, error) {
+	export, err := h.exportRepo.FindByID(ctx, query.ExportID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 不暴露其他用户的导出请求是否存在
+	if export.UserID != query.UserID {
+		return nil, errors.NewNotFoundError("data export")
+	}
+	if export.ExpiresAt != nil && export.ExpiresAt.Before(time.Now()) {
+		return nil, errors.NewNotFoundError("data export")
+	}
+
+	return export, nil
+}
@@ -2,7 +2,9 @@ package query
 
 import (
 	"context"
-	"github.com/your-org/your-project/internal/application/dto"
+	"github.com/gohex/gohex/internal/application/dto"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/vo"
 )
 
 // GetUserByEmailQuery 通过邮箱查询用户
@@ -11,15 +13,22 @@ type GetUserByEmailQuery struct {
 }
 
 type GetUserByEmailHandler struct {
-	userRepo port.UserRepository
-	cache    port.Cache
-	logger   Logger
-	metrics  MetricsReporter
+	userRepo output.UserRepository
+	cache    output.Cache
+	logger   output.Logger
+	metrics  output.MetricsReporter
 }
 
-func (h *GetUserByEmailHandler) Handle(ctx context.Context, q interface{}) (interface{}, error) {
-	query := q.(*GetUserByEmailQuery)
+func NewGetUserByEmailHandler(userRepo output.UserRepository, cache output.Cache, logger output.Logger, metrics output.MetricsReporter) *GetUserByEmailHandler {
+	return &GetUserByEmailHandler{
+		userRepo: userRepo,
+		cache:    cache,
+		logger:   logger,
+		metrics:  metrics,
+	}
+}
 
+func (h *GetUserByEmailHandler) Handle(ctx context.Context, query *GetUserByEmailQuery) (*dto.UserDTO, error) {
 	email, err := vo.NewEmail(query.Email)
 	if err != nil {
 		return nil, err
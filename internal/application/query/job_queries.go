@@ -0,0 +1,51 @@
+package query
+
+import (
+	"context"
+
+	"github.com/gohex/gohex/internal/application/dto"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+// GetJobQuery 获取异步任务状态
+type GetJobQuery struct {
+	JobID       string
+	RequestedBy string
+	IsAdmin     bool
+}
+
+func (q GetJobQuery) Validate() error {
+	if q.JobID == "" {
+		return errors.NewValidationError("job_id is required")
+	}
+	return nil
+}
+
+type GetJobHandler struct {
+	jobQueue output.JobQueue
+	logger   output.Logger
+	metrics  output.MetricsReporter
+}
+
+func NewGetJobHandler(jobQueue output.JobQueue, logger output.Logger, metrics output.MetricsReporter) *GetJobHandler {
+	return &GetJobHandler{
+		jobQueue: jobQueue,
+		logger:   logger,
+		metrics:  metrics,
+	}
+}
+
+func (h *GetJobHandler) Handle(ctx context.Context, query *GetJobQuery) (*dto.JobDTO, error) {
+	job, err := h.jobQueue.FindByID(ctx, query.JobID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 不暴露其他用户的任务是否存在
+	if !query.IsAdmin && job.RequestedBy != query.RequestedBy {
+		return nil, errors.NewNotFoundError("job")
+	}
+
+	return dto.NewJobDTO(job), nil
+}
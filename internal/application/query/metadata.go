@@ -1,11 +1,18 @@
 package query
 
+import (
+    "time"
+
+    "github.com/google/uuid"
+)
+
 type QueryMetadata struct {
     QueryID     string                 `json:"query_id"`
     QueryType   string                 `json:"query_type"`
     StartTime   time.Time              `json:"start_time"`
     EndTime     time.Time              `json:"end_time"`
     Duration    time.Duration          `json:"duration"`
+    Attempts    int                    `json:"attempts"`
     CacheHit    bool                   `json:"cache_hit"`
     CacheKey    string                 `json:"cache_key,omitempty"`
     TraceID     string                 `json:"trace_id"`
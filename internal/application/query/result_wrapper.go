@@ -1,6 +1,15 @@
 package query
 
-import "time"
+import (
+    "context"
+    "fmt"
+    "math"
+    "time"
+
+    querybus "github.com/gohex/gohex/internal/application/port/input/query"
+    "github.com/gohex/gohex/pkg/actor"
+    "github.com/gohex/gohex/pkg/tracer"
+)
 
 type QueryResult struct {
     Data      interface{}
@@ -9,6 +18,7 @@ type QueryResult struct {
     CacheHit  bool
     TraceID   string
     Timestamp time.Time
+    Metadata  *QueryMetadata
 }
 
 func NewQueryResult(data interface{}, err error, duration time.Duration) *QueryResult {
@@ -41,4 +51,33 @@ func NewPagedQueryResult(items interface{}, total int64, page, pageSize int, dur
         HasMore:    page < totalPages,
         Items:      items,
     }
-} 
\ No newline at end of file
+}
+
+// ExecuteWithResult 通过总线执行查询，并返回包含执行次数、缓存命中、耗时和追踪 ID 的结果，
+// HTTP 查询接口据此填写响应头
+func ExecuteWithResult(ctx context.Context, bus querybus.Bus, query interface{}) *QueryResult {
+    metadata := NewQueryMetadata()
+    metadata.QueryType = fmt.Sprintf("%T", query)
+    if a, ok := actor.FromContext(ctx); ok {
+        metadata.UserID = a.RealActorID()
+    }
+    metadata.TraceID, metadata.SpanID = tracer.IDs(ctx)
+
+    ctx, exec := querybus.WithExecution(ctx)
+    data, err := bus.Execute(ctx, query)
+    metadata.Complete()
+
+    metadata.Attempts = exec.Attempts
+    if metadata.Attempts == 0 && !exec.CacheHit {
+        // 未启用重试中间件时处理器只执行一次
+        metadata.Attempts = 1
+    }
+    metadata.CacheHit = exec.CacheHit
+    metadata.CacheKey = exec.CacheKey
+
+    result := NewQueryResult(data, err, metadata.Duration)
+    result.CacheHit = metadata.CacheHit
+    result.TraceID = metadata.TraceID
+    result.Metadata = metadata
+    return result
+}
@@ -0,0 +1,119 @@
+package query
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+
+	querybus "github.com/gohex/gohex/internal/application/port/input/query"
+	"github.com/gohex/gohex/internal/infrastructure/adapter/secondary/cache/memory"
+	"github.com/gohex/gohex/internal/testutil"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+type handlerFunc func(ctx context.Context, q interface{}) (interface{}, error)
+
+func (f handlerFunc) Handle(ctx context.Context, q interface{}) (interface{}, error) {
+	return f(ctx, q)
+}
+
+// chainBus 依次经过中间件后执行 handler
+type chainBus struct {
+	querybus.Bus
+	middleware []querybus.Middleware
+	handler    querybus.Handler
+}
+
+func (b *chainBus) Execute(ctx context.Context, q interface{}) (interface{}, error) {
+	next := b.handler
+	for i := len(b.middleware) - 1; i >= 0; i-- {
+		m, inner := b.middleware[i], next
+		next = handlerFunc(func(ctx context.Context, q interface{}) (interface{}, error) {
+			return m.Execute(ctx, q, inner)
+		})
+	}
+	return next.Handle(ctx, q)
+}
+
+type cachedNameQuery struct{}
+
+func (cachedNameQuery) CacheKey() string   { return "name:alice" }
+func (cachedNameQuery) TTL() time.Duration { return time.Minute }
+
+func TestExecuteWithResult(t *testing.T) {
+	calls := 0
+	bus := &chainBus{
+		middleware: []querybus.Middleware{
+			querybus.NewCacheMiddleware(memory.NewCache(time.Minute, testutil.NopLogger{}, testutil.NewMetrics()),
+				testutil.NopLogger{}, testutil.NewMetrics()),
+			querybus.NewRetryMiddleware(2, time.Millisecond, nil, testutil.NopLogger{}, testutil.NewMetrics()),
+		},
+		handler: handlerFunc(func(ctx context.Context, q interface{}) (interface{}, error) {
+			calls++
+			if calls == 1 {
+				return nil, errors.ErrConcurrencyConflict
+			}
+			return "Alice", nil
+		}),
+	}
+
+	tests := []struct {
+		name         string
+		wantAttempts int
+		wantCacheHit bool
+	}{
+		{name: "miss retries handler", wantAttempts: 2},
+		{name: "hit skips handler", wantAttempts: 0, wantCacheHit: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ExecuteWithResult(context.Background(), bus, cachedNameQuery{})
+
+			require.NoError(t, result.Error)
+			assert.Equal(t, "Alice", result.Data)
+			assert.Equal(t, tt.wantCacheHit, result.CacheHit)
+			require.NotNil(t, result.Metadata)
+			assert.Equal(t, tt.wantAttempts, result.Metadata.Attempts)
+			assert.Equal(t, "name:alice", result.Metadata.CacheKey)
+			assert.Equal(t, "query.cachedNameQuery", result.Metadata.QueryType)
+			assert.Equal(t, result.Duration, result.Metadata.Duration)
+		})
+	}
+	assert.Equal(t, 2, calls)
+}
+
+func TestExecuteWithResult_WithoutRetryCountsSingleAttempt(t *testing.T) {
+	bus := &chainBus{handler: handlerFunc(func(ctx context.Context, q interface{}) (interface{}, error) {
+		return nil, errors.ErrUserNotFound
+	})}
+
+	result := ExecuteWithResult(context.Background(), bus, cachedNameQuery{})
+
+	assert.ErrorIs(t, result.Error, errors.ErrUserNotFound)
+	assert.Equal(t, 1, result.Metadata.Attempts)
+	assert.False(t, result.CacheHit)
+}
+
+func TestExecuteWithResult_RecordsSpanContext(t *testing.T) {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01},
+		SpanID:     trace.SpanID{0x02},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+	bus := &chainBus{handler: handlerFunc(func(ctx context.Context, q interface{}) (interface{}, error) {
+		return "Alice", nil
+	})}
+
+	result := ExecuteWithResult(ctx, bus, cachedNameQuery{})
+
+	require.NoError(t, result.Error)
+	assert.Equal(t, sc.TraceID().String(), result.TraceID)
+	assert.Equal(t, sc.TraceID().String(), result.Metadata.TraceID)
+	assert.Equal(t, sc.SpanID().String(), result.Metadata.SpanID)
+}
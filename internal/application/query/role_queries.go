@@ -2,7 +2,8 @@ package query
 
 import (
 	"context"
-	"github.com/your-org/your-project/internal/application/dto"
+	"github.com/gohex/gohex/internal/application/dto"
+	"github.com/gohex/gohex/internal/application/port/output"
 )
 
 // GetUserRolesQuery 获取用户角色查询
@@ -11,14 +12,20 @@ type GetUserRolesQuery struct {
 }
 
 type GetUserRolesHandler struct {
-	userRepo port.UserRepository
-	logger   Logger
-	metrics  MetricsReporter
+	userRepo output.UserRepository
+	logger   output.Logger
+	metrics  output.MetricsReporter
 }
 
-func (h *GetUserRolesHandler) Handle(ctx context.Context, q interface{}) (interface{}, error) {
-	query := q.(*GetUserRolesQuery)
+func NewGetUserRolesHandler(userRepo output.UserRepository, logger output.Logger, metrics output.MetricsReporter) *GetUserRolesHandler {
+	return &GetUserRolesHandler{
+		userRepo: userRepo,
+		logger:   logger,
+		metrics:  metrics,
+	}
+}
 
+func (h *GetUserRolesHandler) Handle(ctx context.Context, query *GetUserRolesQuery) (*dto.UserRolesDTO, error) {
 	// 1. 获取用户
 	user, err := h.userRepo.FindByID(ctx, query.UserID)
 	if err != nil {
@@ -0,0 +1,64 @@
+package query
+
+import (
+	"context"
+
+	"github.com/gohex/gohex/internal/application/dto"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+// ListScheduledCommandsQuery 计划命令列表，按下次执行时间排序分页
+type ListScheduledCommandsQuery struct {
+	Status      string
+	CommandType string
+	Page        int
+	PageSize    int
+}
+
+func (q ListScheduledCommandsQuery) Validate() error {
+	switch q.Status {
+	case "", output.ScheduleActive, output.ScheduleCompleted, output.ScheduleFailed, output.ScheduleCancelled:
+	default:
+		return errors.NewValidationError("invalid schedule status")
+	}
+	if q.Page <= 0 {
+		return errors.NewValidationError("page must be greater than 0")
+	}
+	if q.PageSize <= 0 || q.PageSize > 100 {
+		return errors.NewValidationError("page size must be between 1 and 100")
+	}
+	return nil
+}
+
+type ListScheduledCommandsHandler struct {
+	store   output.ScheduleStore
+	logger  output.Logger
+	metrics output.MetricsReporter
+}
+
+func NewListScheduledCommandsHandler(store output.ScheduleStore, logger output.Logger, metrics output.MetricsReporter) *ListScheduledCommandsHandler {
+	return &ListScheduledCommandsHandler{
+		store:   store,
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+func (h *ListScheduledCommandsHandler) Handle(ctx context.Context, query *ListScheduledCommandsQuery) (*PagedResult, error) {
+	schedules, total, err := h.store.List(ctx, output.ScheduleFilter{
+		Status:      query.Status,
+		CommandType: query.CommandType,
+		Offset:      (query.Page - 1) * query.PageSize,
+		Limit:       query.PageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*dto.ScheduledCommandDTO, len(schedules))
+	for i, s := range schedules {
+		items[i] = dto.NewScheduledCommandDTO(s)
+	}
+	return NewPagedResult(items, total, query.Page, query.PageSize), nil
+}
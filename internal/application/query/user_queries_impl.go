@@ -4,9 +4,10 @@ import (
 	"context"
 	"fmt"
 	"time"
-	"github.com/your-org/your-project/internal/application/dto"
-	"github.com/your-org/your-project/internal/domain/vo"
-	"github.com/your-org/your-project/pkg/errors"
+	"github.com/gohex/gohex/internal/application/dto"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/vo"
+	"github.com/gohex/gohex/pkg/errors"
 )
 
 // GetUserByIDQuery 实现 Cacheable 接口
@@ -22,49 +23,53 @@ func (q GetUserByIDQuery) TTL() time.Duration {
 	return time.Hour * 24
 }
 
+func (q GetUserByIDQuery) CacheTags() []string {
+	return []string{output.UserCacheTag(q.ID)}
+}
+
+// CachedByHandler 处理器通过 TypedCache 缓存，总线缓存中间件跳过该查询
+func (q GetUserByIDQuery) CachedByHandler() {}
+
 type GetUserByIDHandler struct {
-	userRepo port.UserRepository
-	cache    port.Cache
-	logger   Logger
-	metrics  MetricsReporter
-}
-
-func (h *GetUserByIDHandler) Handle(ctx context.Context, q interface{}) (interface{}, error) {
-	query := q.(*GetUserByIDQuery)
-	
-	// 1. 尝试从缓存获取
-	cacheKey := query.CacheKey()
-	if cached, err := h.cache.Get(ctx, cacheKey); err == nil && cached != nil {
-		h.metrics.IncrementCounter("cache_hit", "type", "user")
-		return cached.(*dto.UserDTO), nil
-	}
-	h.metrics.IncrementCounter("cache_miss", "type", "user")
+	userRepo output.UserRepository
+	cache    output.Cache
+	users    *output.TypedCache[*dto.UserDTO]
+	logger   output.Logger
+	metrics  output.MetricsReporter
+}
 
-	// 2. 从数据库获取
-	user, err := h.userRepo.FindByID(ctx, query.ID)
-	if err != nil {
-		return nil, err
+func NewGetUserByIDHandler(userRepo output.UserRepository, cache output.Cache, logger output.Logger, metrics output.MetricsReporter) *GetUserByIDHandler {
+	return &GetUserByIDHandler{
+		userRepo: userRepo,
+		cache:    cache,
+		users:    output.NewTypedCache[*dto.UserDTO](cache, nil),
+		logger:   logger,
+		metrics:  metrics,
 	}
+}
 
-	// 3. 转换为 DTO
-	userDTO := &dto.UserDTO{
-		ID:        user.ID(),
-		Email:     user.Email().String(),
-		Name:      user.Profile().Name(),
-		Bio:       user.Profile().Bio(),
-		Avatar:    user.Profile().Avatar(),
-		Status:    user.Status().String(),
-		Roles:     user.RoleStrings(),
-		CreatedAt: user.CreatedAt(),
-		UpdatedAt: user.UpdatedAt(),
-	}
+func (h *GetUserByIDHandler) Handle(ctx context.Context, query *GetUserByIDQuery) (*dto.UserDTO, error) {
+	// 依次查找进程内缓存和 Redis，未命中时从数据库获取，并发的未命中只回源一次
+	return h.users.GetOrLoad(ctx, query.CacheKey(), query.TTL(), query.CacheTags(), func(ctx context.Context) (*dto.UserDTO, error) {
+		h.metrics.IncrementCounter("cache_miss", "type", "user")
 
-	// 4. 更新缓存
-	if err := h.cache.Set(ctx, cacheKey, userDTO, query.TTL()); err != nil {
-		h.logger.Error("failed to cache user", "error", err)
-	}
+		user, err := h.userRepo.FindByID(ctx, query.ID)
+		if err != nil {
+			return nil, err
+		}
 
-	return userDTO, nil
+		return &dto.UserDTO{
+			ID:        user.ID(),
+			Email:     user.Email().String(),
+			Name:      user.Profile().Name(),
+			Bio:       user.Profile().Bio(),
+			Avatar:    user.Profile().Avatar(),
+			Status:    user.Status().String(),
+			Roles:     user.RoleStrings(),
+			CreatedAt: user.CreatedAt(),
+			UpdatedAt: user.UpdatedAt(),
+		}, nil
+	})
 }
 
 // ListUsersQuery 实现 Cacheable 接口
@@ -84,6 +89,14 @@ func (q ListUsersQuery) TTL() time.Duration {
 	return time.Hour * 24
 }
 
+// CacheTags 列表结果依赖所有用户，任一用户变更都需要失效
+func (q ListUsersQuery) CacheTags() []string {
+	return []string{output.TagUsersList}
+}
+
+// CachedByHandler 处理器通过 TypedCache 缓存，总线缓存中间件跳过该查询
+func (q ListUsersQuery) CachedByHandler() {}
+
 func (q ListUsersQuery) Validate() error {
 	if q.Page <= 0 {
 		return errors.NewValidationError("page must be greater than 0")
@@ -118,78 +131,64 @@ func isValidSortDirection(dir string) bool {
 }
 
 type ListUsersHandler struct {
-	userRepo port.UserRepository
-	cache    port.Cache
-	logger   Logger
-	metrics  MetricsReporter
+	userRepo output.UserRepository
+	lists    *output.TypedCache[*dto.UserListDTO]
+	logger   output.Logger
+	metrics  output.MetricsReporter
 }
 
 func NewListUsersHandler(
-	userRepo port.UserRepository,
-	cache port.Cache,
-	logger Logger,
-	metrics MetricsReporter,
+	userRepo output.UserRepository,
+	cache output.Cache,
+	logger output.Logger,
+	metrics output.MetricsReporter,
 ) *ListUsersHandler {
 	return &ListUsersHandler{
 		userRepo: userRepo,
-		cache:    cache,
+		lists:    output.NewTypedCache[*dto.UserListDTO](cache, nil),
 		logger:   logger,
 		metrics:  metrics,
 	}
 }
 
-func (h *ListUsersHandler) Handle(ctx context.Context, q interface{}) (interface{}, error) {
-	query := q.(*ListUsersQuery)
-	
+func (h *ListUsersHandler) Handle(ctx context.Context, query *ListUsersQuery) (*dto.UserListDTO, error) {
 	// 添加缓存键前缀，便于批量清除
 	cacheKey := fmt.Sprintf("users:list:%s", query.CacheKey())
-	// 1. 尝试从缓存获取
-	if cached, err := h.cache.Get(ctx, cacheKey); err == nil && cached != nil {
-		h.metrics.IncrementCounter("cache_hit", "type", "users")
-		return cached.(*dto.UserDTO), nil
-	}
-	h.metrics.IncrementCounter("cache_miss", "type", "users")
-
-	// 2. 从数据库获取
-	users, err := h.userRepo.FindAll(ctx, query.Page, query.PageSize, query.Status, query.SortBy, query.SortDir)
-	if err != nil {
-		return nil, err
-	}
-
-	// 3. 转换为 DTO
-	userDTOs := make([]*dto.UserDTO, len(users))
-	for i, user := range users {
-		userDTOs[i] = &dto.UserDTO{
-			ID:        user.ID(),
-			Email:     user.Email().String(),
-			Name:      user.Profile().Name(),
-			Bio:       user.Profile().Bio(),
-			Avatar:    user.Profile().Avatar(),
-			Status:    user.Status().String(),
-			Roles:     user.RoleStrings(),
-			CreatedAt: user.CreatedAt(),
-			UpdatedAt: user.UpdatedAt(),
+	return h.lists.GetOrLoad(ctx, cacheKey, query.TTL(), query.CacheTags(), func(ctx context.Context) (*dto.UserListDTO, error) {
+		h.metrics.IncrementCounter("cache_miss", "type", "users")
+
+		users, total, err := h.userRepo.FindAll(ctx, output.FindAllParams{
+			Status:  query.Status,
+			Offset:  (query.Page - 1) * query.PageSize,
+			Limit:   query.PageSize,
+			SortBy:  query.SortBy,
+			SortDir: query.SortDir,
+		})
+		if err != nil {
+			return nil, err
 		}
-	}
 
-	// 4. 更新缓存
-	if err := h.cache.Set(ctx, cacheKey, userDTOs, query.TTL()); err != nil {
-		h.logger.Error("failed to cache users", "error", err)
-	}
-
-	return userDTOs, nil
+		items := make([]dto.UserDTO, len(users))
+		for i, user := range users {
+			items[i] = *dto.NewUserDTO(user)
+		}
+		return &dto.UserListDTO{
+			Total: total,
+			Items: items,
+		}, nil
+	})
 }
 
 // 添加缓存清理方法
 func (h *GetUserByIDHandler) clearCache(ctx context.Context, userID string) {
-	cacheKeys := []string{
-		fmt.Sprintf("user:id:%s", userID),
-		"users:list", // 清除列表缓存
-	}
-	
-	for _, key := range cacheKeys {
-		if err := h.cache.Delete(ctx, key); err != nil {
-			h.logger.Error("failed to clear cache", "key", key, "error", err)
+	tc, ok := h.cache.(output.TaggedCache)
+	if !ok {
+		if err := h.cache.Delete(ctx, fmt.Sprintf("user:id:%s", userID)); err != nil {
+			h.logger.Error("failed to clear cache", "user_id", userID, "error", err)
 		}
+		return
 	}
-} 
\ No newline at end of file
+	if err := tc.InvalidateTags(ctx, output.UserCacheTag(userID), output.TagUsersList); err != nil {
+		h.logger.Error("failed to clear cache", "user_id", userID, "error", err)
+	}
+}
@@ -0,0 +1,129 @@
+package query
+
+import (
+	"context"
+
+	"github.com/gohex/gohex/internal/application/dto"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+// ListWebhookSubscriptionsQuery 列出所有 webhook 订阅
+type ListWebhookSubscriptionsQuery struct{}
+
+func (q ListWebhookSubscriptionsQuery) Validate() error {
+	return nil
+}
+
+type ListWebhookSubscriptionsHandler struct {
+	repo    output.WebhookRepository
+	logger  output.Logger
+	metrics output.MetricsReporter
+}
+
+func NewListWebhookSubscriptionsHandler(repo output.WebhookRepository, logger output.Logger, metrics output.MetricsReporter) *ListWebhookSubscriptionsHandler {
+	return &ListWebhookSubscriptionsHandler{
+		repo:    repo,
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+func (h *ListWebhookSubscriptionsHandler) Handle(ctx context.Context, _ *ListWebhookSubscriptionsQuery) ([]*dto.WebhookSubscriptionDTO, error) {
+	subs, err := h.repo.ListSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*dto.WebhookSubscriptionDTO, len(subs))
+	for i, sub := range subs {
+		items[i] = dto.NewWebhookSubscriptionDTO(sub)
+	}
+	return items, nil
+}
+
+// GetWebhookSubscriptionQuery 获取单个 webhook 订阅
+type GetWebhookSubscriptionQuery struct {
+	SubscriptionID string
+}
+
+func (q GetWebhookSubscriptionQuery) Validate() error {
+	if q.SubscriptionID == "" {
+		return errors.NewValidationError("subscription_id is required")
+	}
+	return nil
+}
+
+type GetWebhookSubscriptionHandler struct {
+	repo    output.WebhookRepository
+	logger  output.Logger
+	metrics output.MetricsReporter
+}
+
+func NewGetWebhookSubscriptionHandler(repo output.WebhookRepository, logger output.Logger, metrics output.MetricsReporter) *GetWebhookSubscriptionHandler {
+	return &GetWebhookSubscriptionHandler{
+		repo:    repo,
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+func (h *GetWebhookSubscriptionHandler) Handle(ctx context.Context, query *GetWebhookSubscriptionQuery) (*dto.WebhookSubscriptionDTO, error) {
+	sub, err := h.repo.FindSubscription(ctx, query.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.NewWebhookSubscriptionDTO(sub), nil
+}
+
+// ListWebhookDeliveriesQuery 订阅的投递记录，按创建时间倒序分页
+type ListWebhookDeliveriesQuery struct {
+	SubscriptionID string
+	Page           int
+	PageSize       int
+}
+
+func (q ListWebhookDeliveriesQuery) Validate() error {
+	if q.SubscriptionID == "" {
+		return errors.NewValidationError("subscription_id is required")
+	}
+	if q.Page <= 0 {
+		return errors.NewValidationError("page must be greater than 0")
+	}
+	if q.PageSize <= 0 || q.PageSize > 100 {
+		return errors.NewValidationError("page size must be between 1 and 100")
+	}
+	return nil
+}
+
+type ListWebhookDeliveriesHandler struct {
+	repo    output.WebhookRepository
+	logger  output.Logger
+	metrics output.MetricsReporter
+}
+
+func NewListWebhookDeliveriesHandler(repo output.WebhookRepository, logger output.Logger, metrics output.MetricsReporter) *ListWebhookDeliveriesHandler {
+	return &ListWebhookDeliveriesHandler{
+		repo:    repo,
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+func (h *ListWebhookDeliveriesHandler) Handle(ctx context.Context, query *ListWebhookDeliveriesQuery) (*PagedResult, error) {
+	// 订阅不存在时返回 404 而不是空列表
+	if _, err := h.repo.FindSubscription(ctx, query.SubscriptionID); err != nil {
+		return nil, err
+	}
+
+	deliveries, total, err := h.repo.ListDeliveries(ctx, query.SubscriptionID, (query.Page-1)*query.PageSize, query.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*dto.WebhookDeliveryDTO, len(deliveries))
+	for i, d := range deliveries {
+		items[i] = dto.NewWebhookDeliveryDTO(d)
+	}
+	return NewPagedResult(items, total, query.Page, query.PageSize), nil
+}
@@ -0,0 +1,342 @@
+package saga
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gohex/gohex/internal/application/command"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/event"
+	"github.com/gohex/gohex/pkg/actor"
+	"github.com/gohex/gohex/pkg/errors"
+	cmdbus "github.com/gohex/gohex/internal/application/port/input/command"
+)
+
+// Manager 流程管理器：把领域事件关联到 saga 实例，推进步骤并通过命令总线分发命令，
+// 失败或超时时逆序执行已完成步骤的补偿
+type Manager struct {
+	definitions map[string]Definition
+	byEvent     map[string][]Definition
+	repo        output.SagaRepository
+	commandBus  cmdbus.Bus
+	logger      output.Logger
+	metrics     output.MetricsReporter
+}
+
+func NewManager(
+	repo output.SagaRepository,
+	commandBus cmdbus.Bus,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+	definitions ...Definition,
+) *Manager {
+	m := &Manager{
+		definitions: make(map[string]Definition),
+		byEvent:     make(map[string][]Definition),
+		repo:        repo,
+		commandBus:  commandBus,
+		logger:      logger,
+		metrics:     metrics,
+	}
+
+	for _, def := range definitions {
+		m.definitions[def.Type()] = def
+
+		eventTypes := map[string]bool{def.StartEvent(): true}
+		for _, step := range def.Steps() {
+			if step.AwaitEvent != "" {
+				eventTypes[step.AwaitEvent] = true
+			}
+		}
+		for eventType := range eventTypes {
+			m.byEvent[eventType] = append(m.byEvent[eventType], def)
+		}
+	}
+	return m
+}
+
+// EventTypes 返回需要订阅的事件类型
+func (m *Manager) EventTypes() []string {
+	types := make([]string, 0, len(m.byEvent))
+	for eventType := range m.byEvent {
+		types = append(types, eventType)
+	}
+	return types
+}
+
+func (m *Manager) HandlerID() string {
+	return "saga_manager"
+}
+
+// Handle 启动新实例或推进正在等待该事件的实例
+func (m *Manager) Handle(ctx context.Context, evt event.Event) error {
+	var errs []error
+	for _, def := range m.byEvent[evt.Type()] {
+		correlationID := def.Correlate(evt)
+		if correlationID == "" {
+			continue
+		}
+
+		var err error
+		if evt.Type() == def.StartEvent() {
+			err = m.start(ctx, def, correlationID, evt)
+		} else {
+			err = m.resume(ctx, def, correlationID, evt)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("saga %s: %w", def.Type(), err))
+		}
+	}
+	return stderrors.Join(errs...)
+}
+
+// HandleTimeout 处理到期的步骤超时命令，实例已推进、结束或被其他事件抢先更新时忽略
+func (m *Manager) HandleTimeout(ctx context.Context, cmd *StepTimeoutCommand) (struct{}, error) {
+	instance, err := m.repo.FindByID(ctx, cmd.SagaID)
+	if err != nil {
+		var appErr *errors.AppError
+		if errors.As(err, &appErr) && appErr.Code == errors.ErrCodeNotFound {
+			return struct{}{}, nil
+		}
+		return struct{}{}, err
+	}
+	if instance.Status != output.SagaRunning || instance.Step != cmd.Step {
+		return struct{}{}, nil
+	}
+
+	def, ok := m.definitions[instance.Type]
+	if !ok {
+		m.logger.Error("unknown saga type", "saga_id", instance.ID, "type", instance.Type)
+		return struct{}{}, nil
+	}
+	step, ok := m.currentStep(def, instance)
+	if !ok {
+		return struct{}{}, m.abort(ctx, def, instance)
+	}
+	m.metrics.IncrementCounter("saga_step_timeout", "saga", instance.Type, "step", step.Name)
+
+	// 等待中的步骤命令已执行，超时时同样需要补偿；超时命令正在执行，无需取消
+	instance.TimeoutScheduleID = ""
+	err = m.fail(ctx, def, instance, fmt.Sprintf("step %s timed out", step.Name), true)
+	if errors.Is(err, errors.ErrConcurrencyConflict) {
+		// 事件已先一步推进了实例
+		return struct{}{}, nil
+	}
+	return struct{}{}, err
+}
+
+func (m *Manager) start(ctx context.Context, def Definition, correlationID string, evt event.Event) error {
+	data, err := def.Init(evt)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	instance := &output.SagaInstance{
+		ID:            uuid.New().String(),
+		Type:          def.Type(),
+		CorrelationID: correlationID,
+		Status:        output.SagaRunning,
+		Data:          data,
+		Version:       1,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := m.repo.Save(ctx, instance); err != nil {
+		if errors.Is(err, errors.ErrSagaAlreadyStarted) {
+			// 起始事件重复投递
+			return nil
+		}
+		return err
+	}
+
+	m.logger.Info("saga started", "saga_id", instance.ID, "type", instance.Type, "correlation_id", correlationID)
+	m.metrics.IncrementCounter("saga_started", "saga", instance.Type)
+	return m.run(ctx, def, instance)
+}
+
+func (m *Manager) resume(ctx context.Context, def Definition, correlationID string, evt event.Event) error {
+	instance, err := m.repo.FindByCorrelation(ctx, def.Type(), correlationID)
+	if err != nil {
+		var appErr *errors.AppError
+		if errors.As(err, &appErr) && appErr.Code == errors.ErrCodeNotFound {
+			return nil
+		}
+		return err
+	}
+
+	if instance.Status != output.SagaRunning {
+		return nil
+	}
+	step, ok := m.currentStep(def, instance)
+	if !ok {
+		return m.abort(ctx, def, instance)
+	}
+	// 只有正在等待该事件的实例才会推进，迟到或重复的事件直接忽略
+	if step.AwaitEvent != evt.Type() {
+		return nil
+	}
+
+	m.cancelTimeout(ctx, instance)
+	instance.Step++
+	return m.run(ctx, def, instance)
+}
+
+// run 从当前步骤开始执行，直到需要等待事件、完成或失败
+func (m *Manager) run(ctx context.Context, def Definition, instance *output.SagaInstance) error {
+	steps := def.Steps()
+
+	for instance.Step < len(steps) {
+		step := steps[instance.Step]
+
+		if step.Command != nil {
+			if cmd := step.Command(instance); cmd != nil {
+				if _, err := m.dispatch(ctx, cmd); err != nil {
+					m.logger.Warn("saga step failed",
+						"saga_id", instance.ID,
+						"type", instance.Type,
+						"step", step.Name,
+						"error", err,
+					)
+					m.metrics.IncrementCounter("saga_step_failure", "saga", instance.Type, "step", step.Name)
+					return m.fail(ctx, def, instance, fmt.Sprintf("step %s failed: %v", step.Name, err), false)
+				}
+			}
+		}
+
+		if step.AwaitEvent != "" {
+			if step.Timeout > 0 {
+				m.scheduleTimeout(ctx, instance, time.Now().Add(step.Timeout))
+			}
+			return m.save(ctx, instance)
+		}
+
+		instance.Step++
+	}
+
+	instance.Status = output.SagaCompleted
+	if err := m.save(ctx, instance); err != nil {
+		return err
+	}
+
+	m.logger.Info("saga completed", "saga_id", instance.ID, "type", instance.Type)
+	m.metrics.IncrementCounter("saga_completed", "saga", instance.Type)
+	return nil
+}
+
+// fail 逆序补偿已完成的步骤，includeCurrent 为 true 时当前步骤也参与补偿
+func (m *Manager) fail(ctx context.Context, def Definition, instance *output.SagaInstance, reason string, includeCurrent bool) error {
+	steps := def.Steps()
+
+	instance.Status = output.SagaCompensating
+	instance.Error = reason
+	m.cancelTimeout(ctx, instance)
+	// 先持久化状态，并发推进同一实例时只有一方能继续
+	if err := m.save(ctx, instance); err != nil {
+		return err
+	}
+
+	last := instance.Step - 1
+	if includeCurrent {
+		last = instance.Step
+	}
+
+	for i := last; i >= 0; i-- {
+		step := steps[i]
+		if step.Compensate == nil {
+			continue
+		}
+		cmd := step.Compensate(instance)
+		if cmd == nil {
+			continue
+		}
+
+		if _, err := m.dispatch(ctx, cmd); err != nil {
+			instance.Status = output.SagaFailed
+			instance.Error = fmt.Sprintf("%s; compensation of step %s failed: %v", reason, step.Name, err)
+			m.logger.Error("saga compensation failed, manual intervention required",
+				"saga_id", instance.ID,
+				"type", instance.Type,
+				"step", step.Name,
+				"error", err,
+			)
+			m.metrics.IncrementCounter("saga_compensation_failure", "saga", instance.Type, "step", step.Name)
+			return m.save(ctx, instance)
+		}
+	}
+
+	instance.Status = output.SagaCompensated
+	m.logger.Warn("saga compensated", "saga_id", instance.ID, "type", instance.Type, "reason", reason)
+	m.metrics.IncrementCounter("saga_compensated", "saga", instance.Type)
+	return m.save(ctx, instance)
+}
+
+// currentStep 返回实例当前所在的步骤，实例在步骤推进和保存之间中断、
+// 或部署了步骤更少的定义时，持久化的下标可能越界
+func (m *Manager) currentStep(def Definition, instance *output.SagaInstance) (Step, bool) {
+	steps := def.Steps()
+	if instance.Step < 0 || instance.Step >= len(steps) {
+		return Step{}, false
+	}
+	return steps[instance.Step], true
+}
+
+// abort 步骤下标越界时无法确定哪些步骤已执行，不做补偿，标记为失败等待人工处理
+func (m *Manager) abort(ctx context.Context, def Definition, instance *output.SagaInstance) error {
+	instance.Status = output.SagaFailed
+	instance.Error = fmt.Sprintf("step %d out of range, definition has %d steps", instance.Step, len(def.Steps()))
+	m.cancelTimeout(ctx, instance)
+
+	m.logger.Error("saga step out of range, manual intervention required",
+		"saga_id", instance.ID,
+		"type", instance.Type,
+		"step", instance.Step,
+	)
+	m.metrics.IncrementCounter("saga_step_out_of_range", "saga", instance.Type)
+	return m.save(ctx, instance)
+}
+
+// scheduleTimeout 通过命令总线安排当前步骤的超时命令，计划失败时实例仍保存截止时间，只是不会自动超时
+func (m *Manager) scheduleTimeout(ctx context.Context, instance *output.SagaInstance, deadline time.Time) {
+	instance.DeadlineAt = &deadline
+
+	cmd := &StepTimeoutCommand{SagaID: instance.ID, Step: instance.Step}
+	id, err := m.commandBus.Schedule(actor.WithSystem(ctx), cmd, deadline)
+	if err != nil {
+		m.logger.Error("failed to schedule saga step timeout", "saga_id", instance.ID, "step", instance.Step, "error", err)
+		m.metrics.IncrementCounter("saga_timeout_schedule_failure", "saga", instance.Type)
+		return
+	}
+	instance.TimeoutScheduleID = id
+}
+
+// cancelTimeout 取消当前步骤的超时命令；取消失败时超时命令执行时发现步骤已变化，同样会被忽略
+func (m *Manager) cancelTimeout(ctx context.Context, instance *output.SagaInstance) {
+	instance.DeadlineAt = nil
+	if instance.TimeoutScheduleID == "" {
+		return
+	}
+
+	cmd := &command.CancelScheduledCommandCommand{ScheduleID: instance.TimeoutScheduleID}
+	if _, err := m.dispatch(ctx, cmd); err != nil {
+		m.logger.Warn("failed to cancel saga step timeout",
+			"saga_id", instance.ID,
+			"schedule_id", instance.TimeoutScheduleID,
+			"error", err,
+		)
+	}
+	instance.TimeoutScheduleID = ""
+}
+
+// dispatch 以系统身份分发命令，审计记录中可区分流程发起的操作
+func (m *Manager) dispatch(ctx context.Context, cmd interface{}) (interface{}, error) {
+	return m.commandBus.Dispatch(actor.WithSystem(ctx), cmd)
+}
+
+func (m *Manager) save(ctx context.Context, instance *output.SagaInstance) error {
+	instance.UpdatedAt = time.Now()
+	return m.repo.Update(ctx, instance)
+}
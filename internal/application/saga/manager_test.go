@@ -0,0 +1,332 @@
+package saga
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/command"
+	cmdbus "github.com/gohex/gohex/internal/application/port/input/command"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/event"
+	"github.com/gohex/gohex/internal/testutil"
+	"github.com/gohex/gohex/pkg/actor"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+// sagaRepository 内存 saga 仓储，按版本号做乐观锁
+type sagaRepository struct {
+	instances map[string]*output.SagaInstance
+}
+
+func newSagaRepository() *sagaRepository {
+	return &sagaRepository{instances: make(map[string]*output.SagaInstance)}
+}
+
+func (r *sagaRepository) Save(ctx context.Context, instance *output.SagaInstance) error {
+	if _, err := r.FindByCorrelation(ctx, instance.Type, instance.CorrelationID); err == nil {
+		return errors.ErrSagaAlreadyStarted
+	}
+	saved := *instance
+	r.instances[instance.ID] = &saved
+	return nil
+}
+
+func (r *sagaRepository) Update(ctx context.Context, instance *output.SagaInstance) error {
+	stored, ok := r.instances[instance.ID]
+	if !ok || stored.Version != instance.Version {
+		return errors.ErrConcurrencyConflict
+	}
+	instance.Version++
+	saved := *instance
+	r.instances[instance.ID] = &saved
+	return nil
+}
+
+func (r *sagaRepository) FindByID(ctx context.Context, id string) (*output.SagaInstance, error) {
+	instance, ok := r.instances[id]
+	if !ok {
+		return nil, errors.NewNotFoundError("saga")
+	}
+	found := *instance
+	return &found, nil
+}
+
+func (r *sagaRepository) FindByCorrelation(ctx context.Context, sagaType, correlationID string) (*output.SagaInstance, error) {
+	for _, instance := range r.instances {
+		if instance.Type == sagaType && instance.CorrelationID == correlationID {
+			found := *instance
+			return &found, nil
+		}
+	}
+	return nil, errors.NewNotFoundError("saga")
+}
+
+// only 返回唯一的实例
+func (r *sagaRepository) only(t *testing.T) *output.SagaInstance {
+	t.Helper()
+	require.Len(t, r.instances, 1)
+	for _, instance := range r.instances {
+		return instance
+	}
+	return nil
+}
+
+// commandBus 记录分发的命令类型、计划的超时命令和被取消的计划，fail 中的命令返回错误
+type commandBus struct {
+	cmdbus.Bus
+	dispatched []string
+	fail       map[string]bool
+	actors     []string
+	scheduled  []*StepTimeoutCommand
+	runAt      []time.Time
+	cancelled  []string
+}
+
+func (b *commandBus) Schedule(ctx context.Context, cmd interface{}, runAt time.Time) (string, error) {
+	b.scheduled = append(b.scheduled, cmd.(*StepTimeoutCommand))
+	b.runAt = append(b.runAt, runAt)
+	return fmt.Sprintf("schedule-%d", len(b.scheduled)), nil
+}
+
+func (b *commandBus) Dispatch(ctx context.Context, cmd interface{}) (interface{}, error) {
+	if c, ok := cmd.(*command.CancelScheduledCommandCommand); ok {
+		b.cancelled = append(b.cancelled, c.ScheduleID)
+		return nil, nil
+	}
+	name := reflect.TypeOf(cmd).Elem().Name()
+	b.dispatched = append(b.dispatched, name)
+	if a, ok := actor.FromContext(ctx); ok {
+		b.actors = append(b.actors, a.UserID)
+	}
+	if b.fail[name] {
+		return nil, stderrors.New(name + " failed")
+	}
+	return nil, nil
+}
+
+func newManager(failing ...string) (*Manager, *sagaRepository, *commandBus) {
+	repo := newSagaRepository()
+	bus := &commandBus{fail: make(map[string]bool)}
+	for _, name := range failing {
+		bus.fail[name] = true
+	}
+	m := NewManager(repo, bus, testutil.NopLogger{}, testutil.NewMetrics(), NewOnboardingSaga(time.Hour))
+	return m, repo, bus
+}
+
+func userCreated() event.Event {
+	return event.NewUserCreatedEvent("alice", "alice@example.com", "Alice")
+}
+
+func TestManager_OnboardingCompletes(t *testing.T) {
+	m, repo, bus := newManager()
+	ctx := context.Background()
+
+	require.NoError(t, m.Handle(ctx, userCreated()))
+
+	instance := repo.only(t)
+	assert.Equal(t, output.SagaRunning, instance.Status)
+	assert.Equal(t, 0, instance.Step)
+	require.NotNil(t, instance.DeadlineAt)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), *instance.DeadlineAt, time.Minute)
+	assert.Equal(t, map[string]string{"user_id": "alice"}, instance.Data)
+	assert.Equal(t, []string{"SendVerificationEmailCommand"}, bus.dispatched)
+	assert.Equal(t, []*StepTimeoutCommand{{SagaID: instance.ID, Step: 0}}, bus.scheduled)
+	assert.Equal(t, *instance.DeadlineAt, bus.runAt[0])
+	assert.Equal(t, "schedule-1", instance.TimeoutScheduleID)
+
+	require.NoError(t, m.Handle(ctx, event.NewEmailVerifiedEvent("alice", "alice@example.com")))
+
+	instance = repo.only(t)
+	assert.Equal(t, output.SagaCompleted, instance.Status)
+	assert.Nil(t, instance.DeadlineAt)
+	assert.Empty(t, instance.TimeoutScheduleID)
+	assert.Equal(t, []string{"schedule-1"}, bus.cancelled)
+	assert.Equal(t, []string{"SendVerificationEmailCommand", "ProvisionUserCommand", "SendWelcomeEmailCommand"}, bus.dispatched)
+	assert.Equal(t, []string{actor.SystemUserID, actor.SystemUserID, actor.SystemUserID}, bus.actors)
+
+	// 取消前已到期的超时命令在实例完成后执行，不再补偿
+	_, err := m.HandleTimeout(ctx, bus.scheduled[0])
+	require.NoError(t, err)
+	assert.Equal(t, output.SagaCompleted, repo.only(t).Status)
+	assert.NotContains(t, bus.dispatched, "DeprovisionUserCommand")
+}
+
+func TestManager_IgnoresDuplicateAndUnrelatedEvents(t *testing.T) {
+	m, repo, bus := newManager()
+	ctx := context.Background()
+
+	require.NoError(t, m.Handle(ctx, userCreated()))
+	require.NoError(t, m.Handle(ctx, userCreated()))
+	// 没有对应实例的事件
+	require.NoError(t, m.Handle(ctx, event.NewEmailVerifiedEvent("bob", "bob@example.com")))
+
+	assert.Equal(t, output.SagaRunning, repo.only(t).Status)
+	assert.Equal(t, []string{"SendVerificationEmailCommand"}, bus.dispatched)
+
+	// 完成后迟到的重复事件
+	require.NoError(t, m.Handle(ctx, event.NewEmailVerifiedEvent("alice", "alice@example.com")))
+	require.NoError(t, m.Handle(ctx, event.NewEmailVerifiedEvent("alice", "alice@example.com")))
+	assert.Len(t, bus.dispatched, 3)
+}
+
+func TestManager_Compensation(t *testing.T) {
+	tests := []struct {
+		name           string
+		failing        []string
+		wantStatus     string
+		wantDispatched []string
+	}{
+		{
+			name:       "welcome failure deprovisions",
+			failing:    []string{"SendWelcomeEmailCommand"},
+			wantStatus: output.SagaCompensated,
+			wantDispatched: []string{
+				"SendVerificationEmailCommand", "ProvisionUserCommand", "SendWelcomeEmailCommand", "DeprovisionUserCommand",
+			},
+		},
+		{
+			name:       "provision failure has nothing to undo",
+			failing:    []string{"ProvisionUserCommand"},
+			wantStatus: output.SagaCompensated,
+			wantDispatched: []string{
+				"SendVerificationEmailCommand", "ProvisionUserCommand",
+			},
+		},
+		{
+			name:       "failed compensation needs manual intervention",
+			failing:    []string{"SendWelcomeEmailCommand", "DeprovisionUserCommand"},
+			wantStatus: output.SagaFailed,
+			wantDispatched: []string{
+				"SendVerificationEmailCommand", "ProvisionUserCommand", "SendWelcomeEmailCommand", "DeprovisionUserCommand",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, repo, bus := newManager(tt.failing...)
+			ctx := context.Background()
+
+			require.NoError(t, m.Handle(ctx, userCreated()))
+			require.NoError(t, m.Handle(ctx, event.NewEmailVerifiedEvent("alice", "alice@example.com")))
+
+			instance := repo.only(t)
+			assert.Equal(t, tt.wantStatus, instance.Status)
+			assert.Contains(t, instance.Error, tt.failing[0]+" failed")
+			assert.Equal(t, tt.wantDispatched, bus.dispatched)
+		})
+	}
+}
+
+func TestManager_HandleTimeout(t *testing.T) {
+	m, repo, bus := newManager()
+	ctx := context.Background()
+	require.NoError(t, m.Handle(ctx, userCreated()))
+	require.Len(t, bus.scheduled, 1)
+
+	_, err := m.HandleTimeout(ctx, bus.scheduled[0])
+	require.NoError(t, err)
+
+	instance := repo.only(t)
+	assert.Equal(t, output.SagaCompensated, instance.Status)
+	assert.Equal(t, "step verify_email timed out", instance.Error)
+	assert.Nil(t, instance.DeadlineAt)
+	assert.Empty(t, instance.TimeoutScheduleID)
+	// 正在执行的超时命令无需取消
+	assert.Empty(t, bus.cancelled)
+
+	// 超时后才验证邮箱，不再开通
+	require.NoError(t, m.Handle(ctx, event.NewEmailVerifiedEvent("alice", "alice@example.com")))
+	assert.Equal(t, []string{"SendVerificationEmailCommand"}, bus.dispatched)
+}
+
+func TestManager_TimeoutLosesToConcurrentEvent(t *testing.T) {
+	m, repo, bus := newManager()
+	ctx := context.Background()
+	require.NoError(t, m.Handle(ctx, userCreated()))
+
+	// 超时命令读取实例后，验证事件先推进了实例
+	stale, err := repo.FindByID(ctx, repo.only(t).ID)
+	require.NoError(t, err)
+	require.NoError(t, m.Handle(ctx, event.NewEmailVerifiedEvent("alice", "alice@example.com")))
+
+	err = m.fail(ctx, m.definitions[OnboardingSagaType], stale, "step verify_email timed out", true)
+	assert.ErrorIs(t, err, errors.ErrConcurrencyConflict)
+	assert.Equal(t, output.SagaCompleted, repo.only(t).Status)
+	assert.NotContains(t, bus.dispatched, "DeprovisionUserCommand")
+}
+
+func TestManager_StepOutOfRange(t *testing.T) {
+	tests := []struct {
+		name   string
+		handle func(ctx context.Context, m *Manager, instance *output.SagaInstance) error
+	}{
+		{
+			name: "event",
+			handle: func(ctx context.Context, m *Manager, instance *output.SagaInstance) error {
+				return m.Handle(ctx, event.NewEmailVerifiedEvent("alice", "alice@example.com"))
+			},
+		},
+		{
+			name: "timeout",
+			handle: func(ctx context.Context, m *Manager, instance *output.SagaInstance) error {
+				_, err := m.HandleTimeout(ctx, &StepTimeoutCommand{SagaID: instance.ID, Step: instance.Step})
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, repo, bus := newManager()
+			ctx := context.Background()
+			// 部署了步骤更少的定义后遗留的运行中实例
+			instance := &output.SagaInstance{
+				ID:            "saga-1",
+				Type:          OnboardingSagaType,
+				CorrelationID: "alice",
+				Status:        output.SagaRunning,
+				Step:          5,
+				Data:          map[string]string{"user_id": "alice"},
+				Version:       1,
+			}
+			require.NoError(t, repo.Save(ctx, instance))
+
+			require.NoError(t, tt.handle(ctx, m, instance))
+
+			instance = repo.only(t)
+			assert.Equal(t, output.SagaFailed, instance.Status)
+			assert.Contains(t, instance.Error, "step 5 out of range")
+			assert.Empty(t, bus.dispatched)
+		})
+	}
+}
+
+func TestManager_EventTypes(t *testing.T) {
+	m, _, _ := newManager()
+
+	assert.ElementsMatch(t, []string{event.UserCreated, event.UserEmailVerified}, m.EventTypes())
+}
+
+func TestOnboardingSaga_Init(t *testing.T) {
+	s := NewOnboardingSaga(time.Hour)
+
+	data, err := s.Init(userCreated())
+	require.NoError(t, err)
+	// saga 数据不含个人信息，擦除用户时无需清理
+	assert.Equal(t, map[string]string{"user_id": "alice"}, data)
+
+	_, err = s.Init(event.NewEmailVerifiedEvent("alice", "alice@example.com"))
+	assert.Error(t, err)
+
+	cmd := s.Steps()[1].Compensate(&output.SagaInstance{Data: data})
+	assert.Equal(t, &command.DeprovisionUserCommand{UserID: "alice"}, cmd)
+}
@@ -0,0 +1,73 @@
+package saga
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gohex/gohex/internal/application/command"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/event"
+)
+
+// OnboardingSagaType 注册流程的 saga 类型
+const OnboardingSagaType = "user_onboarding"
+
+// OnboardingSaga 注册流程：验证邮箱 → 在下游系统开通 → 发送欢迎邮件。
+// 邮箱在期限内未验证时流程结束，不开通下游账号；欢迎邮件发送失败时注销已开通的账号
+type OnboardingSaga struct {
+	verificationTimeout time.Duration
+}
+
+func NewOnboardingSaga(verificationTimeout time.Duration) *OnboardingSaga {
+	return &OnboardingSaga{verificationTimeout: verificationTimeout}
+}
+
+func (s *OnboardingSaga) Type() string {
+	return OnboardingSagaType
+}
+
+func (s *OnboardingSaga) StartEvent() string {
+	return event.UserCreated
+}
+
+// Correlate 以用户 ID 关联
+func (s *OnboardingSaga) Correlate(evt event.Event) string {
+	return evt.AggregateID()
+}
+
+// Init 只保存用户 ID，邮箱和姓名由各步骤的命令处理器读取，擦除用户时无需清理 saga 数据
+func (s *OnboardingSaga) Init(evt event.Event) (map[string]string, error) {
+	if _, ok := evt.(*event.UserCreatedEvent); !ok {
+		return nil, fmt.Errorf("unexpected event %T for %s", evt, evt.Type())
+	}
+
+	return map[string]string{"user_id": evt.AggregateID()}, nil
+}
+
+func (s *OnboardingSaga) Steps() []Step {
+	return []Step{
+		{
+			Name: "verify_email",
+			Command: func(instance *output.SagaInstance) interface{} {
+				return &command.SendVerificationEmailCommand{UserID: instance.Data["user_id"]}
+			},
+			AwaitEvent: event.UserEmailVerified,
+			Timeout:    s.verificationTimeout,
+		},
+		{
+			Name: "provision",
+			Command: func(instance *output.SagaInstance) interface{} {
+				return &command.ProvisionUserCommand{UserID: instance.Data["user_id"]}
+			},
+			Compensate: func(instance *output.SagaInstance) interface{} {
+				return &command.DeprovisionUserCommand{UserID: instance.Data["user_id"]}
+			},
+		},
+		{
+			Name: "welcome",
+			Command: func(instance *output.SagaInstance) interface{} {
+				return &command.SendWelcomeEmailCommand{UserID: instance.Data["user_id"]}
+			},
+		},
+	}
+}
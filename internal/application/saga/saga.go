@@ -0,0 +1,40 @@
+package saga
+
+import (
+	"time"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/event"
+)
+
+// Step 流程中的一步：分发命令，可选地等待某个领域事件后再进入下一步
+type Step struct {
+	Name string
+	// Command 本步骤分发的命令，返回 nil 表示不分发命令，只等待事件
+	Command func(instance *output.SagaInstance) interface{}
+	// AwaitEvent 命令成功后需要等待的事件类型，为空时命令成功即完成本步骤
+	AwaitEvent string
+	// Timeout 等待事件的最长时间，到期时由计划的 StepTimeoutCommand 使流程失败并开始补偿，0 表示不超时
+	Timeout time.Duration
+	// Compensate 流程失败时撤销本步骤的命令，nil 表示无需补偿
+	Compensate func(instance *output.SagaInstance) interface{}
+}
+
+// StepTimeoutCommand 等待事件的步骤到期时由调度器分发，实例已离开该步骤时忽略
+type StepTimeoutCommand struct {
+	SagaID string `validate:"required"`
+	Step   int
+}
+
+// Definition 一类流程的定义
+type Definition interface {
+	// Type 流程类型，持久化到实例中，不可随意修改
+	Type() string
+	// StartEvent 创建新实例的事件类型
+	StartEvent() string
+	// Correlate 返回事件对应实例的关联 ID，空字符串表示忽略该事件
+	Correlate(evt event.Event) string
+	// Init 根据起始事件初始化实例数据，后续步骤从中读取命令参数
+	Init(evt event.Event) (map[string]string, error)
+	Steps() []Step
+}
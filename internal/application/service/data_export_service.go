@@ -0,0 +1,137 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/gohex/gohex/internal/application/dto"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/event"
+)
+
+// DataExportService 汇总用户的全部个人数据并打包为 zip
+type DataExportService struct {
+	userRepo   output.UserRepository
+	eventStore output.EventStore
+	auditLog   output.AuditLog
+	logger     output.Logger
+	metrics    output.MetricsReporter
+}
+
+func NewDataExportService(
+	userRepo output.UserRepository,
+	eventStore output.EventStore,
+	auditLog output.AuditLog,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *DataExportService {
+	return &DataExportService{
+		userRepo:   userRepo,
+		eventStore: eventStore,
+		auditLog:   auditLog,
+		logger:     logger,
+		metrics:    metrics,
+	}
+}
+
+type exportedEvent struct {
+	Type       string      `json:"type"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+type exportedSession struct {
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	LoginAt   time.Time `json:"login_at"`
+}
+
+// Assemble 生成导出归档
+func (s *DataExportService) Assemble(ctx context.Context, userID string) ([]byte, error) {
+	timer := s.metrics.StartTimer("data_export_assemble_duration")
+	defer timer.Stop()
+
+	// 1. 用户资料和角色
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	profile := dto.NewUserDTO(user)
+
+	// 2. 事件和登录会话
+	events, err := s.eventStore.GetEvents(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	exportedEvents := make([]exportedEvent, 0, len(events))
+	sessions := make([]exportedSession, 0)
+	for _, evt := range events {
+		exportedEvents = append(exportedEvents, exportedEvent{
+			Type:       evt.Type(),
+			OccurredAt: evt.OccurredAt(),
+			Data:       evt,
+		})
+		if login, ok := evt.(*event.UserLoggedInEvent); ok {
+			sessions = append(sessions, exportedSession{
+				IP:        login.IP,
+				UserAgent: login.UserAgent,
+				LoginAt:   login.LoginAt,
+			})
+		}
+	}
+
+	// 3. 审计记录：本人被操作的和本人发起的
+	asSubject, _, err := s.auditLog.Find(ctx, output.AuditFilter{SubjectID: userID})
+	if err != nil {
+		return nil, err
+	}
+	asActor, _, err := s.auditLog.Find(ctx, output.AuditFilter{ActorID: userID})
+	if err != nil {
+		return nil, err
+	}
+	audit := make([]*dto.AuditLogDTO, 0, len(asSubject)+len(asActor))
+	seen := make(map[string]bool)
+	for _, entry := range append(asSubject, asActor...) {
+		if seen[entry.ID] {
+			continue
+		}
+		seen[entry.ID] = true
+		audit = append(audit, dto.NewAuditLogDTO(entry))
+	}
+
+	// 4. 打包
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", profile},
+		{"roles.json", user.RoleStrings()},
+		{"sessions.json", sessions},
+		{"audit.json", audit},
+		{"events.json", exportedEvents},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range files {
+		w, err := zw.Create(f.name)
+		if err != nil {
+			return nil, err
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(f.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	s.metrics.IncrementCounter("data_export_assembled")
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+)
+
+// UserAuditSnapshotter 为审计中间件提供用户快照
+type UserAuditSnapshotter struct {
+	userRepo output.UserRepository
+	keyStore output.KeyStore
+}
+
+func NewUserAuditSnapshotter(userRepo output.UserRepository, keyStore output.KeyStore) *UserAuditSnapshotter {
+	return &UserAuditSnapshotter{userRepo: userRepo, keyStore: keyStore}
+}
+
+// Snapshot 返回用户可审计的字段，不包含密码等敏感信息；
+// 个人信息只记录以用户密钥计算的 HMAC，审计记录能反映字段是否变化而不保存原值，
+// 擦除用户时密钥随之粉碎，历史摘要无法再与候选值比对
+func (s *UserAuditSnapshotter) Snapshot(ctx context.Context, userID string) (map[string]interface{}, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := map[string]interface{}{
+		"status": user.Status().String(),
+		"roles":  user.RoleStrings(),
+	}
+	fields := map[string]string{
+		"email":    user.Email().String(),
+		"name":     user.Profile().Name(),
+		"bio":      user.Profile().Bio(),
+		"avatar":   user.Profile().Avatar(),
+		"location": user.Profile().Location(),
+		"website":  user.Profile().Website(),
+	}
+
+	// 已擦除的用户没有个人信息，也不为其重新创建密钥
+	if user.IsErased() {
+		for field := range fields {
+			snapshot[field] = ""
+		}
+		return snapshot, nil
+	}
+
+	key, err := s.keyStore.GetOrCreateKey(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for field, value := range fields {
+		snapshot[field] = fingerprint(key, value)
+	}
+	return snapshot, nil
+}
+
+// fingerprint 返回值的 HMAC-SHA256 摘要，空值保持为空
+func fingerprint(key []byte, value string) string {
+	if value == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+	return "hmac-sha256:" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -2,22 +2,24 @@ package service
 
 import (
 	"context"
-	"github.com/your-org/your-project/internal/application/dto"
-	"github.com/your-org/your-project/internal/domain/vo"
+	"github.com/gohex/gohex/internal/application/dto"
+	"github.com/gohex/gohex/internal/domain/aggregate"
+	"github.com/gohex/gohex/internal/domain/vo"
+	"github.com/gohex/gohex/internal/application/port/output"
 )
 
 type UserQueryService struct {
-	userRepo port.UserRepository
-	cache    port.Cache
-	logger   Logger
-	metrics  MetricsReporter
+	userRepo output.UserRepository
+	cache    output.Cache
+	logger   output.Logger
+	metrics  output.MetricsReporter
 }
 
 func NewUserQueryService(
-	userRepo port.UserRepository,
-	cache port.Cache,
-	logger Logger,
-	metrics MetricsReporter,
+	userRepo output.UserRepository,
+	cache output.Cache,
+	logger output.Logger,
+	metrics output.MetricsReporter,
 ) *UserQueryService {
 	return &UserQueryService{
 		userRepo: userRepo,
@@ -41,13 +43,8 @@ func (s *UserQueryService) FindByEmail(ctx context.Context, email string) (*dto.
 	return s.toDTO(user), nil
 }
 
-func (s *UserQueryService) ListUsers(ctx context.Context, query ListUsersQuery) (*dto.UserListDTO, error) {
-	total, err := s.userRepo.Count(ctx, query.Status)
-	if err != nil {
-		return nil, err
-	}
-
-	users, err := s.userRepo.FindAll(ctx, query.Status, query.Offset(), query.Limit())
+func (s *UserQueryService) ListUsers(ctx context.Context, params output.FindAllParams) (*dto.UserListDTO, error) {
+	users, total, err := s.userRepo.FindAll(ctx, params)
 	if err != nil {
 		return nil, err
 	}
@@ -64,15 +61,5 @@ func (s *UserQueryService) ListUsers(ctx context.Context, query ListUsersQuery)
 }
 
 func (s *UserQueryService) toDTO(user *aggregate.User) *dto.UserDTO {
-	return &dto.UserDTO{
-		ID:        user.ID(),
-		Email:     user.Email().String(),
-		Name:      user.Profile().Name(),
-		Bio:       user.Profile().Bio(),
-		Avatar:    user.Profile().Avatar(),
-		Status:    user.Status().String(),
-		Roles:     user.RoleStrings(),
-		CreatedAt: user.CreatedAt(),
-		UpdatedAt: user.UpdatedAt(),
-	}
+	return dto.NewUserDTO(user)
 } 
\ No newline at end of file
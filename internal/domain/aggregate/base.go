@@ -1,7 +1,7 @@
 package aggregate
 
 import (
-	"github.com/your-org/your-project/internal/domain/event"
+	"github.com/gohex/gohex/internal/domain/event"
 )
 
 // BaseAggregate 提供聚合根的基础实现
@@ -27,6 +27,11 @@ func (a *BaseAggregate) Version() int {
 	return a.version
 }
 
+// OriginalVersion 记录未保存事件之前的版本，保存事件时作为期望版本
+func (a *BaseAggregate) OriginalVersion() int {
+	return a.version - len(a.events)
+}
+
 func (a *BaseAggregate) Events() []event.Event {
 	return a.events
 }
@@ -35,7 +40,11 @@ func (a *BaseAggregate) ClearEvents() {
 	a.events = make([]event.Event, 0)
 }
 
-func (a *BaseAggregate) AddEvent(event event.Event) {
-	a.events = append(a.events, event)
+// AddEvent 记录新事件，事件的聚合版本为记录后的版本
+func (a *BaseAggregate) AddEvent(e event.Event) {
 	a.version++
+	if m, ok := e.(event.Mutable); ok {
+		m.SetAggregateVersion(a.version)
+	}
+	a.events = append(a.events, e)
 } 
\ No newline at end of file
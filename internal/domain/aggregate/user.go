@@ -1,6 +1,8 @@
 package aggregate
 
 import (
+	"fmt"
+
 	"github.com/google/uuid"
 	"github.com/gohex/gohex/internal/domain/event"
 	"github.com/gohex/gohex/internal/domain/vo"
@@ -17,6 +19,11 @@ type User struct {
 	roles     []vo.UserRole
 	createdAt time.Time
 	updatedAt time.Time
+	deletedAt *time.Time
+	// lockedUntil 登录失败锁定的自动解锁时间，管理员冻结或其他状态下为 nil
+	lockedUntil *time.Time
+	// erasedAt 个人信息被擦除的时间，未擦除时为 nil
+	erasedAt *time.Time
 }
 
 func NewUser(email vo.Email, password vo.Password, profile vo.UserProfile) (*User, error) {
@@ -40,6 +47,41 @@ func NewUser(email vo.Email, password vo.Password, profile vo.UserProfile) (*Use
 	return user, nil
 }
 
+// ReconstituteUser 从持久化数据重建用户聚合根，不产生事件
+func ReconstituteUser(
+	id string,
+	email vo.Email,
+	password vo.Password,
+	profile vo.UserProfile,
+	status vo.UserStatus,
+	roles []vo.UserRole,
+	createdAt time.Time,
+	updatedAt time.Time,
+	deletedAt *time.Time,
+	lockedUntil *time.Time,
+	erasedAt *time.Time,
+	version int,
+) *User {
+	if len(roles) == 0 {
+		roles = []vo.UserRole{vo.RoleUser}
+	}
+	base := NewBaseAggregate(id)
+	base.version = version
+	return &User{
+		BaseAggregate: base,
+		email:         email,
+		password:      password,
+		profile:       profile,
+		status:        status,
+		roles:         roles,
+		createdAt:     createdAt,
+		updatedAt:     updatedAt,
+		deletedAt:     deletedAt,
+		lockedUntil:   lockedUntil,
+		erasedAt:      erasedAt,
+	}
+}
+
 // Getters
 func (u *User) Email() vo.Email { return u.email }
 func (u *User) Password() vo.Password { return u.password }
@@ -48,6 +90,19 @@ func (u *User) Status() vo.UserStatus { return u.status }
 func (u *User) Roles() []vo.UserRole { return u.roles }
 func (u *User) CreatedAt() time.Time { return u.createdAt }
 func (u *User) UpdatedAt() time.Time { return u.updatedAt }
+func (u *User) DeletedAt() *time.Time { return u.deletedAt }
+func (u *User) LockedUntil() *time.Time { return u.lockedUntil }
+func (u *User) ErasedAt() *time.Time { return u.erasedAt }
+
+// IsLockedOut 账户是否因登录失败被锁定，管理员冻结的账户返回 false
+func (u *User) IsLockedOut() bool {
+	return u.status == vo.StatusSuspended && u.lockedUntil != nil
+}
+
+// LockExpired 登录失败锁定是否已到期
+func (u *User) LockExpired(now time.Time) bool {
+	return u.IsLockedOut() && !now.Before(*u.lockedUntil)
+}
 
 // Business Methods
 func (u *User) UpdateProfile(profile vo.UserProfile) error {
@@ -67,6 +122,33 @@ func (u *User) UpdateProfile(profile vo.UserProfile) error {
 	return nil
 }
 
+// ChangeEmail 变更邮箱，唯一性由调用方在确认时检查；已删除的用户不能变更
+func (u *User) ChangeEmail(email vo.Email) error {
+	if u.IsDeleted() {
+		return errors.ErrInactiveUser
+	}
+	if u.email.String() == email.String() {
+		return errors.ErrEmailUnchanged
+	}
+
+	oldEmail := u.email
+	u.email = email
+	u.updatedAt = time.Now()
+
+	u.AddEvent(event.NewEmailChangedEvent(u.ID(), oldEmail.String(), email.String()))
+	return nil
+}
+
+// VerifyEmail 确认当前邮箱可用，邮箱已变更时验证无效
+func (u *User) VerifyEmail(email string) error {
+	if u.email.String() != email {
+		return errors.ErrEmailVerificationStale
+	}
+
+	u.AddEvent(event.NewEmailVerifiedEvent(u.ID(), email))
+	return nil
+}
+
 func (u *User) ChangePassword(current, new vo.Password) error {
 	if err := u.password.Compare(current.Hash()); err != nil {
 		return errors.ErrInvalidPassword
@@ -75,7 +157,7 @@ func (u *User) ChangePassword(current, new vo.Password) error {
 	u.password = new
 	u.updatedAt = time.Now()
 
-	u.AddEvent(event.NewPasswordChangedEvent(u.ID()))
+	u.AddEvent(event.NewPasswordChangedEvent(u.ID(), u.email.String()))
 	return nil
 }
 
@@ -83,22 +165,38 @@ func (u *User) ResetPassword(new vo.Password) error {
 	u.password = new
 	u.updatedAt = time.Now()
 
-	u.AddEvent(event.NewPasswordResetEvent(u.ID()))
+	u.AddEvent(event.NewPasswordResetEvent(u.ID(), u.email.String()))
 	return nil
 }
 
 func (u *User) ChangeStatus(status vo.UserStatus) error {
 	if !status.IsValid() {
-		return errors.ErrInvalidStatus
+		return errors.ErrInvalidUserStatus
 	}
 
 	if u.status == status {
+		// 管理员冻结已被锁定的账户时改为无期限冻结，到期的自动解锁不再生效
+		if u.lockedUntil != nil {
+			u.lockedUntil = nil
+			u.updatedAt = time.Now()
+		}
 		return nil
 	}
 
+	// 删除和恢复需要维护 deleted_at，只能通过 Delete / Restore 完成
+	if status.IsDeleted() || u.status.IsDeleted() {
+		return errors.ErrInvalidStatusTransition
+	}
+
+	return u.transitionTo(status)
+}
+
+// transitionTo 按状态机迁移状态并记录状态变更事件
+func (u *User) transitionTo(status vo.UserStatus) error {
 	oldStatus := u.status
-	u.status = status
-	u.updatedAt = time.Now()
+	if err := u.setStatus(status); err != nil {
+		return err
+	}
 
 	u.AddEvent(event.NewUserStatusChangedEvent(
 		u.ID(),
@@ -109,6 +207,19 @@ func (u *User) ChangeStatus(status vo.UserStatus) error {
 	return nil
 }
 
+// setStatus 按状态机迁移状态，不记录事件；
+// 停用、恢复等生命周期操作只记录各自的事件，每次迁移对应一个事件
+func (u *User) setStatus(status vo.UserStatus) error {
+	if !u.status.CanTransitionTo(status) {
+		return errors.ErrInvalidStatusTransition
+	}
+
+	u.status = status
+	u.lockedUntil = nil
+	u.updatedAt = time.Now()
+	return nil
+}
+
 func (u *User) AssignRole(role vo.UserRole) error {
 	if !role.IsValid() {
 		return errors.ErrInvalidRole
@@ -164,16 +275,108 @@ func (u *User) ValidatePassword(plaintext string) error {
 	return u.password.Compare(plaintext)
 }
 
-func (u *User) Lock() error {
+// Lock 锁定账户直到 until，reason 记录在 UserLockedEvent 中。
+// 只能锁定正常账户，否则到期解锁会把停用或冻结的账户恢复为正常
+func (u *User) Lock(reason string, until time.Time) error {
+	if u.status != vo.StatusActive {
+		return errors.ErrInvalidStatusTransition
+	}
 	if err := u.ChangeStatus(vo.StatusSuspended); err != nil {
 		return err
 	}
-	u.AddEvent(event.NewUserLockedEvent(u.ID()))
+	u.lockedUntil = &until
+	u.AddEvent(event.NewUserLockedEvent(u.ID(), reason, until))
 	return nil
 }
 
+// Unlock 解除登录失败锁定，管理员冻结的账户只能由管理员修改状态
 func (u *User) Unlock() error {
-	return u.ChangeStatus(vo.StatusActive)
+	if !u.IsLockedOut() {
+		return errors.ErrInvalidStatusTransition
+	}
+	if err := u.ChangeStatus(vo.StatusActive); err != nil {
+		return err
+	}
+	u.AddEvent(event.NewUserUnlockedEvent(u.ID()))
+	return nil
+}
+
+// Deactivate 停用用户，停用后不能登录但数据保留
+func (u *User) Deactivate(reason string) error {
+	if !u.status.CanBeDeactivated() {
+		return errors.ErrInvalidStatusTransition
+	}
+	if err := u.setStatus(vo.StatusInactive); err != nil {
+		return err
+	}
+	u.AddEvent(event.NewUserDeactivatedEvent(u.ID(), reason))
+	return nil
+}
+
+// Reactivate 重新启用已停用或已冻结的用户
+func (u *User) Reactivate() error {
+	if !u.status.CanBeActivated() {
+		return errors.ErrInvalidStatusTransition
+	}
+	if err := u.setStatus(vo.StatusActive); err != nil {
+		return err
+	}
+	u.AddEvent(event.NewUserReactivatedEvent(u.ID()))
+	return nil
+}
+
+// Delete 软删除用户，宽限期结束前可通过 Restore 恢复
+func (u *User) Delete(deletedBy string) error {
+	if u.status.IsDeleted() {
+		return errors.ErrInvalidStatusTransition
+	}
+	if err := u.setStatus(vo.StatusDeleted); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	u.deletedAt = &now
+	u.AddEvent(event.NewUserDeletedEvent(u.ID(), deletedBy, now))
+	return nil
+}
+
+// Restore 恢复软删除的用户，已擦除个人信息的用户不能恢复
+func (u *User) Restore(restoredBy string) error {
+	if !u.status.IsDeleted() {
+		return errors.ErrUserNotDeleted
+	}
+	if u.IsErased() {
+		return errors.ErrCannotRestoreErasedUser
+	}
+	if err := u.setStatus(vo.StatusActive); err != nil {
+		return err
+	}
+
+	u.deletedAt = nil
+	u.AddEvent(event.NewUserRestoredEvent(u.ID(), restoredBy))
+	return nil
+}
+
+// IsDeleted 用户是否已被软删除
+func (u *User) IsDeleted() bool {
+	return u.status.IsDeleted()
+}
+
+// IsPurgeable 软删除超过宽限期后可以永久删除
+func (u *User) IsPurgeable(gracePeriod time.Duration, now time.Time) bool {
+	return u.deletedAt != nil && !u.deletedAt.Add(gracePeriod).After(now)
+}
+
+// Purge 在宽限期结束后标记用户将被永久删除
+func (u *User) Purge(gracePeriod time.Duration, now time.Time) error {
+	if !u.IsDeleted() {
+		return errors.ErrUserNotDeleted
+	}
+	if !u.IsPurgeable(gracePeriod, now) {
+		return errors.ErrDeletionGracePeriodActive
+	}
+	u.AddEvent(event.NewUserPurgedEvent(u.ID()))
+	return nil
 }
 
 func (u *User) IsActive() bool {
@@ -206,6 +409,93 @@ func (u *User) RoleStrings() []string {
 	return roles
 }
 
+// CanBeImpersonatedBy 检查 actor 是否可以模拟当前用户
+func (u *User) CanBeImpersonatedBy(actor *User) error {
+	if !actor.HasPermission(vo.PermissionImpersonate) {
+		return errors.ErrInsufficientPermissions
+	}
+	if actor.ID() == u.ID() {
+		return errors.ErrCannotImpersonateSelf
+	}
+	// 不允许模拟管理员，避免权限提升
+	if u.HasRole(vo.RoleAdmin) {
+		return errors.ErrCannotImpersonateAdmin
+	}
+	if !u.IsActive() {
+		return errors.ErrInactiveUser
+	}
+	return nil
+}
+
+// StartImpersonation 记录 actor 开始以当前用户身份操作
+func (u *User) StartImpersonation(actor *User, reason string, ip string, userAgent string, expiresAt time.Time) error {
+	if err := u.CanBeImpersonatedBy(actor); err != nil {
+		return err
+	}
+
+	u.AddEvent(event.NewUserImpersonationStartedEvent(
+		u.ID(),
+		actor.ID(),
+		reason,
+		ip,
+		userAgent,
+		expiresAt,
+	))
+	return nil
+}
+
+// EndImpersonation 记录 actor 结束模拟
+func (u *User) EndImpersonation(actorID string) {
+	u.AddEvent(event.NewUserImpersonationEndedEvent(u.ID(), actorID))
+}
+
+// erasedEmailDomain 擦除后占位邮箱使用的保留域名
+const erasedEmailDomain = "@erased.invalid"
+
+// Erase 匿名化用户的个人信息（被遗忘权），操作不可逆
+func (u *User) Erase(requestedBy string) error {
+	if u.IsErased() {
+		return errors.ErrUserAlreadyErased
+	}
+
+	email, err := vo.NewEmail(fmt.Sprintf("erased+%s%s", u.ID(), erasedEmailDomain))
+	if err != nil {
+		return err
+	}
+	profile, err := vo.NewUserProfile("Erased User", "")
+	if err != nil {
+		return err
+	}
+
+	// 未删除的用户按状态机迁移到已删除，已删除的用户保留原删除时间
+	if u.status != vo.StatusDeleted {
+		if err := u.setStatus(vo.StatusDeleted); err != nil {
+			return err
+		}
+	}
+
+	u.email = email
+	u.profile = profile
+	// 空哈希无法通过任何密码校验
+	u.password = vo.NewPasswordFromHash("")
+	u.roles = []vo.UserRole{vo.RoleUser}
+	u.updatedAt = time.Now()
+	erasedAt := u.updatedAt
+	u.erasedAt = &erasedAt
+	if u.deletedAt == nil {
+		deletedAt := u.updatedAt
+		u.deletedAt = &deletedAt
+	}
+
+	u.AddEvent(event.NewUserErasedEvent(u.ID(), requestedBy))
+	return nil
+}
+
+// IsErased 用户个人信息是否已被擦除，以擦除标记为准，不依据邮箱判断
+func (u *User) IsErased() bool {
+	return u.erasedAt != nil
+}
+
 func (u *User) RecordLogin(ip string, userAgent string) {
 	u.AddEvent(event.NewUserLoggedInEvent(
 		u.ID(),
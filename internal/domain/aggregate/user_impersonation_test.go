@@ -0,0 +1,95 @@
+package aggregate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/domain/vo"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+func newTestUser(t *testing.T, id string, status vo.UserStatus, roles ...vo.UserRole) *User {
+	t.Helper()
+	email, err := vo.NewEmail(id + "@example.com")
+	require.NoError(t, err)
+	profile, err := vo.NewUserProfile("Test "+id, "")
+	require.NoError(t, err)
+	now := time.Now()
+	return ReconstituteUser(id, email, vo.NewPasswordFromHash("hash"), profile, status, roles, now, now, nil, nil, nil, 1)
+}
+
+func TestUser_CanBeImpersonatedBy(t *testing.T) {
+	tests := []struct {
+		name    string
+		actor   *User
+		subject *User
+		wantErr error
+	}{
+		{
+			name:    "support may impersonate a regular user",
+			actor:   newTestUser(t, "support", vo.StatusActive, vo.RoleSupport),
+			subject: newTestUser(t, "alice", vo.StatusActive, vo.RoleUser),
+		},
+		{
+			name:    "admin may impersonate a moderator",
+			actor:   newTestUser(t, "admin", vo.StatusActive, vo.RoleAdmin),
+			subject: newTestUser(t, "mod", vo.StatusActive, vo.RoleUser, vo.RoleMod),
+		},
+		{
+			name:    "regular user lacks the permission",
+			actor:   newTestUser(t, "bob", vo.StatusActive, vo.RoleUser),
+			subject: newTestUser(t, "alice", vo.StatusActive, vo.RoleUser),
+			wantErr: errors.ErrInsufficientPermissions,
+		},
+		{
+			name:    "user without persisted roles falls back to the default role",
+			actor:   newTestUser(t, "bob", vo.StatusActive),
+			subject: newTestUser(t, "alice", vo.StatusActive, vo.RoleUser),
+			wantErr: errors.ErrInsufficientPermissions,
+		},
+		{
+			name:    "admins cannot be impersonated",
+			actor:   newTestUser(t, "support", vo.StatusActive, vo.RoleSupport),
+			subject: newTestUser(t, "root", vo.StatusActive, vo.RoleUser, vo.RoleAdmin),
+			wantErr: errors.ErrCannotImpersonateAdmin,
+		},
+		{
+			name:    "cannot impersonate self",
+			actor:   newTestUser(t, "support", vo.StatusActive, vo.RoleSupport),
+			subject: newTestUser(t, "support", vo.StatusActive, vo.RoleSupport),
+			wantErr: errors.ErrCannotImpersonateSelf,
+		},
+		{
+			name:    "inactive users cannot be impersonated",
+			actor:   newTestUser(t, "support", vo.StatusActive, vo.RoleSupport),
+			subject: newTestUser(t, "alice", vo.StatusInactive, vo.RoleUser),
+			wantErr: errors.ErrInactiveUser,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.subject.CanBeImpersonatedBy(tt.actor)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestUser_StartImpersonationRecordsEventOnlyWhenAllowed(t *testing.T) {
+	actor := newTestUser(t, "support", vo.StatusActive, vo.RoleSupport)
+
+	allowed := newTestUser(t, "alice", vo.StatusActive, vo.RoleUser)
+	require.NoError(t, allowed.StartImpersonation(actor, "ticket-1", "127.0.0.1", "test", time.Now().Add(time.Hour)))
+	assert.Len(t, allowed.Events(), 1)
+
+	denied := newTestUser(t, "root", vo.StatusActive, vo.RoleAdmin)
+	require.Error(t, denied.StartImpersonation(actor, "ticket-2", "127.0.0.1", "test", time.Now().Add(time.Hour)))
+	assert.Empty(t, denied.Events())
+}
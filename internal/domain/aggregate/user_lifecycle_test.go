@@ -0,0 +1,152 @@
+package aggregate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/domain/event"
+	"github.com/gohex/gohex/internal/domain/vo"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+func eventTypes(u *User) []string {
+	var types []string
+	for _, e := range u.Events() {
+		types = append(types, e.Type())
+	}
+	return types
+}
+
+func TestUser_LifecycleTransitions(t *testing.T) {
+	tests := []struct {
+		name       string
+		from       vo.UserStatus
+		apply      func(u *User) error
+		wantStatus vo.UserStatus
+		wantEvents []string
+		wantErr    error
+	}{
+		{
+			name:       "deactivate",
+			from:       vo.StatusActive,
+			apply:      func(u *User) error { return u.Deactivate("left company") },
+			wantStatus: vo.StatusInactive,
+			wantEvents: []string{event.UserDeactivated},
+		},
+		{
+			name:       "reactivate",
+			from:       vo.StatusInactive,
+			apply:      func(u *User) error { return u.Reactivate() },
+			wantStatus: vo.StatusActive,
+			wantEvents: []string{event.UserReactivated},
+		},
+		{
+			name:       "delete",
+			from:       vo.StatusSuspended,
+			apply:      func(u *User) error { return u.Delete("admin") },
+			wantStatus: vo.StatusDeleted,
+			wantEvents: []string{event.UserDeleted},
+		},
+		{
+			name:       "restore",
+			from:       vo.StatusDeleted,
+			apply:      func(u *User) error { return u.Restore("admin") },
+			wantStatus: vo.StatusActive,
+			wantEvents: []string{event.UserRestored},
+		},
+		{
+			name:       "change status",
+			from:       vo.StatusActive,
+			apply:      func(u *User) error { return u.ChangeStatus(vo.StatusSuspended) },
+			wantStatus: vo.StatusSuspended,
+			wantEvents: []string{event.UserStatusChanged},
+		},
+		{
+			name:       "deactivate inactive user",
+			from:       vo.StatusInactive,
+			apply:      func(u *User) error { return u.Deactivate("again") },
+			wantStatus: vo.StatusInactive,
+			wantErr:    errors.ErrInvalidStatusTransition,
+		},
+		{
+			name:       "restore active user",
+			from:       vo.StatusActive,
+			apply:      func(u *User) error { return u.Restore("admin") },
+			wantStatus: vo.StatusActive,
+			wantErr:    errors.ErrUserNotDeleted,
+		},
+		{
+			name:       "change status cannot delete",
+			from:       vo.StatusActive,
+			apply:      func(u *User) error { return u.ChangeStatus(vo.StatusDeleted) },
+			wantStatus: vo.StatusActive,
+			wantErr:    errors.ErrInvalidStatusTransition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newTestUser(t, "alice", tt.from, vo.RoleUser)
+
+			err := tt.apply(u)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantStatus, u.Status())
+			assert.Equal(t, tt.wantEvents, eventTypes(u))
+		})
+	}
+}
+
+func TestUser_DeleteAndPurge(t *testing.T) {
+	u := newTestUser(t, "alice", vo.StatusActive, vo.RoleUser)
+	require.NoError(t, u.Delete("admin"))
+	require.NotNil(t, u.DeletedAt())
+
+	grace := 30 * 24 * time.Hour
+	assert.ErrorIs(t, u.Purge(grace, time.Now()), errors.ErrDeletionGracePeriodActive)
+	require.NoError(t, u.Purge(grace, u.DeletedAt().Add(grace)))
+	assert.Equal(t, []string{event.UserDeleted, event.UserPurged}, eventTypes(u))
+
+	require.NoError(t, u.Restore("admin"))
+	assert.Nil(t, u.DeletedAt())
+}
+
+func TestUser_AdminSuspendOverridesLockout(t *testing.T) {
+	u := newTestUser(t, "alice", vo.StatusActive, vo.RoleUser)
+	require.NoError(t, u.Lock("too many failed login attempts", time.Now().Add(30*time.Minute)))
+	assert.True(t, u.IsLockedOut())
+
+	// 锁定期间管理员冻结，到期的自动解锁不能解除冻结
+	require.NoError(t, u.ChangeStatus(vo.StatusSuspended))
+	assert.False(t, u.IsLockedOut())
+	assert.Nil(t, u.LockedUntil())
+	assert.ErrorIs(t, u.Unlock(), errors.ErrInvalidStatusTransition)
+	assert.Equal(t, vo.StatusSuspended, u.Status())
+}
+
+func TestUser_Erase(t *testing.T) {
+	// 使用保留域名注册的用户不是已擦除用户
+	email, err := vo.NewEmail("x@erased.invalid")
+	require.NoError(t, err)
+	profile, err := vo.NewUserProfile("X", "")
+	require.NoError(t, err)
+	now := time.Now()
+	x := ReconstituteUser("x", email, vo.NewPasswordFromHash("hash"), profile, vo.StatusActive, nil, now, now, nil, nil, nil, 1)
+	assert.False(t, x.IsErased())
+
+	u := newTestUser(t, "alice", vo.StatusActive, vo.RoleAdmin)
+	require.NoError(t, u.Lock("too many failed login attempts", now.Add(time.Minute)))
+	require.NoError(t, u.Erase("admin"))
+	assert.True(t, u.IsErased())
+	assert.NotNil(t, u.ErasedAt())
+	assert.Equal(t, vo.StatusDeleted, u.Status())
+	assert.NotNil(t, u.DeletedAt())
+	assert.Nil(t, u.LockedUntil())
+	assert.ErrorIs(t, u.Erase("admin"), errors.ErrUserAlreadyErased)
+}
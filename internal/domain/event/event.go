@@ -0,0 +1,98 @@
+package event
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultSchemaVersion 事件数据结构的初始版本
+const DefaultSchemaVersion = 1
+
+// Event 领域事件，具体事件嵌入 BaseEvent，导出字段即事件数据
+type Event interface {
+	// ID 事件唯一标识，跨存储和消息中间件保持不变，用于处理器去重
+	ID() string
+	Type() string
+	AggregateID() string
+	// AggregateVersion 产生该事件后聚合的版本
+	AggregateVersion() int
+	// SchemaVersion 事件数据结构的版本，结构不兼容变更时递增
+	SchemaVersion() int
+	OccurredAt() time.Time
+	Metadata() Metadata
+}
+
+// Header 事件数据之外的描述信息，事件存储和消息中间件分别保存，读取时用于还原事件
+type Header struct {
+	ID               string
+	Type             string
+	AggregateID      string
+	AggregateVersion int
+	SchemaVersion    int
+	OccurredAt       time.Time
+	Metadata         Metadata
+}
+
+// HeaderOf 返回事件的描述信息
+func HeaderOf(e Event) Header {
+	return Header{
+		ID:               e.ID(),
+		Type:             e.Type(),
+		AggregateID:      e.AggregateID(),
+		AggregateVersion: e.AggregateVersion(),
+		SchemaVersion:    e.SchemaVersion(),
+		OccurredAt:       e.OccurredAt(),
+		Metadata:         e.Metadata(),
+	}
+}
+
+// Anonymous 不携带任何个人数据的事件实现该接口，事件存储以明文保存，
+// 主体密钥粉碎后仍可读取，擦除记录本身因此不会随密钥一起丢失
+type Anonymous interface {
+	Event
+	Anonymous()
+}
+
+// Mutable 嵌入 BaseEvent 的事件指针均实现，聚合和基础设施通过它补充描述信息
+type Mutable interface {
+	Event
+	// Restore 从存储或消息中还原事件时恢复原始描述信息
+	Restore(h Header)
+	SetAggregateVersion(version int)
+	SetMetadata(md Metadata)
+}
+
+type BaseEvent struct {
+	header Header
+}
+
+func NewBaseEvent(aggregateID string, eventType string) BaseEvent {
+	return BaseEvent{
+		header: Header{
+			ID:            uuid.New().String(),
+			Type:          eventType,
+			AggregateID:   aggregateID,
+			SchemaVersion: DefaultSchemaVersion,
+			OccurredAt:    time.Now(),
+		},
+	}
+}
+
+func (e BaseEvent) ID() string            { return e.header.ID }
+func (e BaseEvent) Type() string          { return e.header.Type }
+func (e BaseEvent) AggregateID() string   { return e.header.AggregateID }
+func (e BaseEvent) AggregateVersion() int { return e.header.AggregateVersion }
+func (e BaseEvent) SchemaVersion() int    { return e.header.SchemaVersion }
+func (e BaseEvent) OccurredAt() time.Time { return e.header.OccurredAt }
+func (e BaseEvent) Metadata() Metadata    { return e.header.Metadata }
+
+func (e *BaseEvent) Restore(h Header) {
+	if h.SchemaVersion == 0 {
+		h.SchemaVersion = DefaultSchemaVersion
+	}
+	e.header = h
+}
+
+func (e *BaseEvent) SetAggregateVersion(version int) { e.header.AggregateVersion = version }
+func (e *BaseEvent) SetMetadata(md Metadata)         { e.header.Metadata = md }
@@ -0,0 +1,25 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBaseEvent_UniqueID(t *testing.T) {
+	a := NewUserCreatedEvent("alice", "alice@example.com", "Alice")
+	b := NewUserCreatedEvent("alice", "alice@example.com", "Alice")
+
+	assert.NotEmpty(t, a.ID())
+	assert.NotEqual(t, a.ID(), b.ID())
+}
+
+func TestBaseEvent_RestoreKeepsID(t *testing.T) {
+	original := NewUserCreatedEvent("alice", "alice@example.com", "Alice")
+
+	restored := &UserCreatedEvent{}
+	restored.Restore(HeaderOf(original))
+
+	assert.Equal(t, original.ID(), restored.ID())
+	assert.Equal(t, HeaderOf(original), HeaderOf(restored))
+}
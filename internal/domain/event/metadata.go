@@ -0,0 +1,66 @@
+package event
+
+import (
+	"context"
+
+	"github.com/gohex/gohex/pkg/actor"
+)
+
+// Metadata 事件的发起者和因果链
+type Metadata struct {
+	// ActorID 触发事件的用户，模拟会话中为被模拟用户
+	ActorID string `json:"actor_id,omitempty"`
+	// ImpersonatorID 模拟会话中的实际操作人
+	ImpersonatorID string `json:"impersonator_id,omitempty"`
+	// CorrelationID 同一业务流程共享，从发起请求到其引发的所有命令和事件保持不变
+	CorrelationID string `json:"correlation_id,omitempty"`
+	// CausationID 直接导致该事件的请求或事件的 ID
+	CausationID string `json:"causation_id,omitempty"`
+}
+
+type causeKey struct{}
+
+type cause struct {
+	correlationID string
+	causationID   string
+}
+
+// WithCorrelation 在请求入口写入关联 ID，请求中产生的事件以该请求为起点
+func WithCorrelation(ctx context.Context, correlationID string) context.Context {
+	return context.WithValue(ctx, causeKey{}, cause{correlationID: correlationID, causationID: correlationID})
+}
+
+// CausedBy 处理事件时使用，之后产生的事件沿用 e 的关联 ID 并以 e 为直接原因
+func CausedBy(ctx context.Context, e Event) context.Context {
+	correlationID := e.Metadata().CorrelationID
+	if correlationID == "" {
+		correlationID = e.ID()
+	}
+	return context.WithValue(ctx, causeKey{}, cause{correlationID: correlationID, causationID: e.ID()})
+}
+
+// CauseFromContext 返回上下文中的关联 ID 和因果 ID
+func CauseFromContext(ctx context.Context) (correlationID, causationID string) {
+	c, _ := ctx.Value(causeKey{}).(cause)
+	return c.correlationID, c.causationID
+}
+
+// Stamp 为尚未设置 Metadata 的事件补充发起者和因果链，保存或发布前调用
+func Stamp(ctx context.Context, events ...Event) {
+	correlationID, causationID := CauseFromContext(ctx)
+	a, _ := actor.FromContext(ctx)
+	md := Metadata{
+		ActorID:        a.UserID,
+		ImpersonatorID: a.ImpersonatorID,
+		CorrelationID:  correlationID,
+		CausationID:    causationID,
+	}
+
+	for _, e := range events {
+		m, ok := e.(Mutable)
+		if !ok || e.Metadata() != (Metadata{}) {
+			continue
+		}
+		m.SetMetadata(md)
+	}
+}
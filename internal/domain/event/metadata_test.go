@@ -0,0 +1,56 @@
+package event
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gohex/gohex/pkg/actor"
+)
+
+func TestCausedBy(t *testing.T) {
+	request := WithCorrelation(context.Background(), "req-1")
+
+	// 请求中产生的事件以请求为起点
+	created := NewUserCreatedEvent("alice", "alice@example.com", "Alice")
+	Stamp(request, created)
+	assert.Equal(t, "req-1", created.Metadata().CorrelationID)
+	assert.Equal(t, "req-1", created.Metadata().CausationID)
+
+	// 处理 created 时产生的事件沿用关联 ID，以 created 为直接原因
+	verified := NewEmailVerifiedEvent("alice", "alice@example.com")
+	Stamp(CausedBy(context.Background(), created), verified)
+	assert.Equal(t, "req-1", verified.Metadata().CorrelationID)
+	assert.Equal(t, created.ID(), verified.Metadata().CausationID)
+}
+
+func TestCausedBy_StartsCorrelationAtUncorrelatedEvent(t *testing.T) {
+	root := NewUserCreatedEvent("alice", "alice@example.com", "Alice")
+
+	correlationID, causationID := CauseFromContext(CausedBy(context.Background(), root))
+
+	assert.Equal(t, root.ID(), correlationID)
+	assert.Equal(t, root.ID(), causationID)
+}
+
+func TestStamp(t *testing.T) {
+	ctx := actor.WithActor(WithCorrelation(context.Background(), "req-1"),
+		actor.Actor{UserID: "alice", ImpersonatorID: "support"})
+
+	fresh := NewUserProfileUpdatedEvent("alice", "Alice", "")
+	replayed := NewUserProfileUpdatedEvent("alice", "Alice", "")
+	replayed.(Mutable).SetMetadata(Metadata{ActorID: "bob", CorrelationID: "req-0", CausationID: "req-0"})
+
+	Stamp(ctx, fresh, replayed)
+
+	assert.Equal(t, Metadata{
+		ActorID:        "alice",
+		ImpersonatorID: "support",
+		CorrelationID:  "req-1",
+		CausationID:    "req-1",
+	}, fresh.Metadata())
+	// 已有 Metadata 的事件保持不变
+	assert.Equal(t, "bob", replayed.Metadata().ActorID)
+	assert.Equal(t, "req-0", replayed.Metadata().CorrelationID)
+}
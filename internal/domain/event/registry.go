@@ -0,0 +1,73 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// factories 已知事件类型，事件存储和消息中间件读取事件时按类型还原为具体结构
+var factories = map[string]func() Mutable{
+	UserCreated:              func() Mutable { return &UserCreatedEvent{} },
+	UserProfileUpdated:       func() Mutable { return &UserProfileUpdatedEvent{} },
+	PasswordChanged:          func() Mutable { return &PasswordChangedEvent{} },
+	PasswordReset:            func() Mutable { return &PasswordResetEvent{} },
+	UserStatusChanged:        func() Mutable { return &UserStatusChangedEvent{} },
+	RoleAssigned:             func() Mutable { return &UserRoleAssignedEvent{} },
+	RoleRevoked:              func() Mutable { return &UserRoleRevokedEvent{} },
+	UserLoggedIn:             func() Mutable { return &UserLoggedInEvent{} },
+	UserLocked:               func() Mutable { return &UserLockedEvent{} },
+	UserUnlocked:             func() Mutable { return &UserUnlockedEvent{} },
+	UserImpersonationStarted: func() Mutable { return &UserImpersonationStartedEvent{} },
+	UserImpersonationEnded:   func() Mutable { return &UserImpersonationEndedEvent{} },
+	UserErased:               func() Mutable { return &UserErasedEvent{} },
+	UserDeactivated:          func() Mutable { return &UserDeactivatedEvent{} },
+	UserReactivated:          func() Mutable { return &UserReactivatedEvent{} },
+	UserDeleted:              func() Mutable { return &UserDeletedEvent{} },
+	UserRestored:             func() Mutable { return &UserRestoredEvent{} },
+	UserPurged:               func() Mutable { return &UserPurgedEvent{} },
+	UserEmailChanged:         func() Mutable { return &EmailChangedEvent{} },
+	UserEmailVerified:        func() Mutable { return &EmailVerifiedEvent{} },
+}
+
+// AnonymousTypes 返回不携带个人数据的已知事件类型
+func AnonymousTypes() []string {
+	var types []string
+	for t, factory := range factories {
+		if _, ok := factory().(Anonymous); ok {
+			types = append(types, t)
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+// RawEvent 未知类型或数据已不可读（如密钥已粉碎）的事件，只保留描述信息和原始数据
+type RawEvent struct {
+	BaseEvent
+	Payload json.RawMessage `json:"-"`
+}
+
+func NewRawEvent(h Header, payload json.RawMessage) *RawEvent {
+	e := &RawEvent{Payload: payload}
+	e.Restore(h)
+	return e
+}
+
+// Decode 按 h.Type 解码事件数据并恢复描述信息，未知类型返回 RawEvent，
+// 旧版本发布方的事件可以被新版本消费方读取
+func Decode(h Header, payload []byte) (Event, error) {
+	factory, ok := factories[h.Type]
+	if !ok {
+		return NewRawEvent(h, payload), nil
+	}
+
+	e := factory()
+	if len(payload) > 0 {
+		if err := json.Unmarshal(payload, e); err != nil {
+			return nil, fmt.Errorf("failed to decode %s event %s: %w", h.Type, h.ID, err)
+		}
+	}
+	e.Restore(h)
+	return e, nil
+}
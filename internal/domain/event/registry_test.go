@@ -0,0 +1,34 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnonymousTypes(t *testing.T) {
+	assert.Equal(t, []string{UserErased, UserPurged}, AnonymousTypes())
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  Header
+		payload []byte
+		want    interface{}
+	}{
+		{"known type", Header{ID: "e1", Type: UserErased}, []byte(`{"requested_by":"admin"}`), &UserErasedEvent{}},
+		{"unknown type", Header{ID: "e2", Type: "partner.synced"}, []byte(`{}`), &RawEvent{}},
+		{"shredded payload", Header{ID: "e3", Type: UserCreated}, nil, &UserCreatedEvent{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := Decode(tt.header, tt.payload)
+			assert.NoError(t, err)
+			assert.IsType(t, tt.want, e)
+			assert.Equal(t, tt.header.ID, e.ID())
+			assert.Equal(t, DefaultSchemaVersion, e.SchemaVersion())
+		})
+	}
+}
@@ -2,13 +2,14 @@ package event
 
 import (
 	"time"
-	"github.com/your-org/your-project/internal/domain/vo"
+	"github.com/gohex/gohex/internal/domain/vo"
 )
 
 const (
 	UserCreated       = "user.created"
 	UserProfileUpdated = "user.profile_updated"
 	PasswordChanged   = "user.password_changed"
+	PasswordReset     = "user.password_reset"
 	RoleAssigned     = "user.role_assigned"
 	UserStatusChanged = "user.status_changed"
 	UserDeactivated   = "user.deactivated"
@@ -16,6 +17,19 @@ const (
 	UserLocked        = "user.locked"
 	UserUnlocked      = "user.unlocked"
 	RoleRevoked       = "user.role_revoked"
+
+	UserImpersonationStarted = "user.impersonation_started"
+	UserImpersonationEnded   = "user.impersonation_ended"
+
+	UserErased = "user.erased"
+
+	UserReactivated = "user.reactivated"
+	UserDeleted     = "user.deleted"
+	UserRestored    = "user.restored"
+	UserPurged      = "user.purged"
+
+	UserEmailChanged  = "user.email_changed"
+	UserEmailVerified = "user.email_verified"
 )
 
 type UserCreatedEvent struct {
@@ -50,6 +64,36 @@ func NewUserProfileUpdatedEvent(userID string, name string, bio string) Event {
 	}
 }
 
+// PasswordChangedEvent 用户修改了密码，Email 用于发送变更通知
+type PasswordChangedEvent struct {
+	BaseEvent
+	Email     string    `json:"email"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+func NewPasswordChangedEvent(userID string, email string) Event {
+	return &PasswordChangedEvent{
+		BaseEvent: NewBaseEvent(userID, PasswordChanged),
+		Email:     email,
+		ChangedAt: time.Now(),
+	}
+}
+
+// PasswordResetEvent 用户通过重置流程设置了新密码
+type PasswordResetEvent struct {
+	BaseEvent
+	Email   string    `json:"email"`
+	ResetAt time.Time `json:"reset_at"`
+}
+
+func NewPasswordResetEvent(userID string, email string) Event {
+	return &PasswordResetEvent{
+		BaseEvent: NewBaseEvent(userID, PasswordReset),
+		Email:     email,
+		ResetAt:   time.Now(),
+	}
+}
+
 type UserStatusChangedEvent struct {
 	BaseEvent
 	OldStatus vo.UserStatus `json:"old_status"`
@@ -98,15 +142,17 @@ func NewUserLoggedInEvent(userID string, ip string, userAgent string) Event {
 
 type UserLockedEvent struct {
 	BaseEvent
-	Reason    string    `json:"reason"`
-	LockedAt  time.Time `json:"locked_at"`
+	Reason      string    `json:"reason"`
+	LockedAt    time.Time `json:"locked_at"`
+	LockedUntil time.Time `json:"locked_until"`
 }
 
-func NewUserLockedEvent(userID string, reason string) Event {
+func NewUserLockedEvent(userID string, reason string, until time.Time) Event {
 	return &UserLockedEvent{
-		BaseEvent: NewBaseEvent(userID, UserLocked),
-		Reason:    reason,
-		LockedAt:  time.Now(),
+		BaseEvent:   NewBaseEvent(userID, UserLocked),
+		Reason:      reason,
+		LockedAt:    time.Now(),
+		LockedUntil: until,
 	}
 }
 
@@ -136,4 +182,163 @@ func NewUserRoleRevokedEvent(userID string, role vo.UserRole) Event {
 	}
 }
 
-// 其他事件类型的实现... 
\ No newline at end of file
+// UserImpersonationStartedEvent 管理员开始模拟用户
+type UserImpersonationStartedEvent struct {
+	BaseEvent
+	ActorID   string    `json:"actor_id"`
+	Reason    string    `json:"reason"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	ExpiresAt time.Time `json:"expires_at"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+func NewUserImpersonationStartedEvent(userID string, actorID string, reason string, ip string, userAgent string, expiresAt time.Time) Event {
+	return &UserImpersonationStartedEvent{
+		BaseEvent: NewBaseEvent(userID, UserImpersonationStarted),
+		ActorID:   actorID,
+		Reason:    reason,
+		IP:        ip,
+		UserAgent: userAgent,
+		ExpiresAt: expiresAt,
+		StartedAt: time.Now(),
+	}
+}
+
+// UserImpersonationEndedEvent 管理员结束模拟用户
+type UserImpersonationEndedEvent struct {
+	BaseEvent
+	ActorID string    `json:"actor_id"`
+	EndedAt time.Time `json:"ended_at"`
+}
+
+func NewUserImpersonationEndedEvent(userID string, actorID string) Event {
+	return &UserImpersonationEndedEvent{
+		BaseEvent: NewBaseEvent(userID, UserImpersonationEnded),
+		ActorID:   actorID,
+		EndedAt:   time.Now(),
+	}
+}
+
+// UserErasedEvent 用户个人数据已被擦除，不携带任何个人信息
+type UserErasedEvent struct {
+	BaseEvent
+	RequestedBy string    `json:"requested_by"`
+	ErasedAt    time.Time `json:"erased_at"`
+}
+
+func (*UserErasedEvent) Anonymous() {}
+
+func NewUserErasedEvent(userID string, requestedBy string) Event {
+	return &UserErasedEvent{
+		BaseEvent:   NewBaseEvent(userID, UserErased),
+		RequestedBy: requestedBy,
+		ErasedAt:    time.Now(),
+	}
+}
+
+// UserDeactivatedEvent 用户被停用
+type UserDeactivatedEvent struct {
+	BaseEvent
+	Reason        string    `json:"reason"`
+	DeactivatedAt time.Time `json:"deactivated_at"`
+}
+
+func NewUserDeactivatedEvent(userID string, reason string) Event {
+	return &UserDeactivatedEvent{
+		BaseEvent:     NewBaseEvent(userID, UserDeactivated),
+		Reason:        reason,
+		DeactivatedAt: time.Now(),
+	}
+}
+
+// UserReactivatedEvent 用户被重新启用
+type UserReactivatedEvent struct {
+	BaseEvent
+	ReactivatedAt time.Time `json:"reactivated_at"`
+}
+
+func NewUserReactivatedEvent(userID string) Event {
+	return &UserReactivatedEvent{
+		BaseEvent:     NewBaseEvent(userID, UserReactivated),
+		ReactivatedAt: time.Now(),
+	}
+}
+
+// UserDeletedEvent 用户被软删除，宽限期内可恢复
+type UserDeletedEvent struct {
+	BaseEvent
+	DeletedBy string    `json:"deleted_by"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+func NewUserDeletedEvent(userID string, deletedBy string, deletedAt time.Time) Event {
+	return &UserDeletedEvent{
+		BaseEvent: NewBaseEvent(userID, UserDeleted),
+		DeletedBy: deletedBy,
+		DeletedAt: deletedAt,
+	}
+}
+
+// UserRestoredEvent 软删除的用户被恢复
+type UserRestoredEvent struct {
+	BaseEvent
+	RestoredBy string    `json:"restored_by"`
+	RestoredAt time.Time `json:"restored_at"`
+}
+
+func NewUserRestoredEvent(userID string, restoredBy string) Event {
+	return &UserRestoredEvent{
+		BaseEvent:  NewBaseEvent(userID, UserRestored),
+		RestoredBy: restoredBy,
+		RestoredAt: time.Now(),
+	}
+}
+
+// UserPurgedEvent 宽限期结束后用户记录被永久删除
+type UserPurgedEvent struct {
+	BaseEvent
+	PurgedAt time.Time `json:"purged_at"`
+}
+
+func (*UserPurgedEvent) Anonymous() {}
+
+func NewUserPurgedEvent(userID string) Event {
+	return &UserPurgedEvent{
+		BaseEvent: NewBaseEvent(userID, UserPurged),
+		PurgedAt:  time.Now(),
+	}
+}
+
+// EmailChangedEvent 用户邮箱已变更
+type EmailChangedEvent struct {
+	BaseEvent
+	OldEmail  string    `json:"old_email"`
+	NewEmail  string    `json:"new_email"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+func NewEmailChangedEvent(userID string, oldEmail string, newEmail string) Event {
+	return &EmailChangedEvent{
+		BaseEvent: NewBaseEvent(userID, UserEmailChanged),
+		OldEmail:  oldEmail,
+		NewEmail:  newEmail,
+		ChangedAt: time.Now(),
+	}
+}
+
+// EmailVerifiedEvent 用户已通过邮件中的链接验证邮箱
+type EmailVerifiedEvent struct {
+	BaseEvent
+	Email      string    `json:"email"`
+	VerifiedAt time.Time `json:"verified_at"`
+}
+
+func NewEmailVerifiedEvent(userID string, email string) Event {
+	return &EmailVerifiedEvent{
+		BaseEvent:  NewBaseEvent(userID, UserEmailVerified),
+		Email:      email,
+		VerifiedAt: time.Now(),
+	}
+}
+ 
@@ -2,20 +2,23 @@ package service
 
 import (
 	"context"
-	"github.com/your-org/your-project/internal/domain/aggregate"
-	"github.com/your-org/your-project/internal/domain/vo"
+	"github.com/gohex/gohex/pkg/errors"
+	"github.com/gohex/gohex/internal/domain/aggregate"
+	"github.com/gohex/gohex/internal/domain/vo"
 )
 
+// UserReader 领域服务读取用户所需的仓储能力，由应用层的用户仓储实现，领域层不依赖应用层
+type UserReader interface {
+	ExistsByEmail(ctx context.Context, email vo.Email) (bool, error)
+	FindByID(ctx context.Context, id string) (*aggregate.User, error)
+}
+
 type UserService struct {
-	userRepo port.UserRepository
-	logger   Logger
+	userRepo UserReader
 }
 
-func NewUserService(userRepo port.UserRepository, logger Logger) *UserService {
-	return &UserService{
-		userRepo: userRepo,
-		logger:   logger,
-	}
+func NewUserService(userRepo UserReader) *UserService {
+	return &UserService{userRepo: userRepo}
 }
 
 func (s *UserService) ValidateUniqueEmail(ctx context.Context, email vo.Email) error {
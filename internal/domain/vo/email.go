@@ -3,6 +3,8 @@ package vo
 import (
 	"regexp"
 	"strings"
+
+	"github.com/gohex/gohex/pkg/errors"
 )
 
 type Email struct {
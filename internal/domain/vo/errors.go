@@ -0,0 +1,46 @@
+package vo
+
+import "github.com/gohex/gohex/pkg/errors"
+
+// 值对象校验错误
+var (
+	ErrInvalidPassword = &errors.AppError{
+		Code:    errors.ErrCodeValidation,
+		Message: "invalid password",
+	}
+
+	ErrPasswordTooShort = &errors.AppError{
+		Code:    errors.ErrCodeValidation,
+		Message: "password is too short",
+	}
+
+	ErrPasswordTooWeak = &errors.AppError{
+		Code:    errors.ErrCodeValidation,
+		Message: "password must contain upper case, lower case, digit and special characters",
+	}
+
+	ErrInvalidAvatarURL = &errors.AppError{
+		Code:    errors.ErrCodeValidation,
+		Message: "invalid avatar url",
+	}
+
+	ErrInvalidWebsiteURL = &errors.AppError{
+		Code:    errors.ErrCodeValidation,
+		Message: "invalid website url",
+	}
+
+	ErrInvalidName = &errors.AppError{
+		Code:    errors.ErrCodeValidation,
+		Message: "name is required",
+	}
+
+	ErrNameTooLong = &errors.AppError{
+		Code:    errors.ErrCodeValidation,
+		Message: "name is too long",
+	}
+
+	ErrBioTooLong = &errors.AppError{
+		Code:    errors.ErrCodeValidation,
+		Message: "bio is too long",
+	}
+)
@@ -3,15 +3,20 @@ package vo
 type UserRole string
 
 const (
-	RoleUser  UserRole = "user"
-	RoleAdmin UserRole = "admin"
-	RoleMod   UserRole = "moderator"
+	RoleUser    UserRole = "user"
+	RoleAdmin   UserRole = "admin"
+	RoleMod     UserRole = "moderator"
+	RoleSupport UserRole = "support"
 )
 
+// PermissionImpersonate 以其他用户身份登录的权限
+const PermissionImpersonate = "users.impersonate"
+
 var validRoles = map[UserRole]bool{
-	RoleUser:  true,
-	RoleAdmin: true,
-	RoleMod:   true,
+	RoleUser:    true,
+	RoleAdmin:   true,
+	RoleMod:     true,
+	RoleSupport: true,
 }
 
 func (r UserRole) IsValid() bool {
@@ -28,6 +33,8 @@ func (r UserRole) HasPermission(permission string) bool {
 		return true
 	case RoleMod:
 		return isModeratorPermission(permission)
+	case RoleSupport:
+		return isSupportPermission(permission)
 	case RoleUser:
 		return isUserPermission(permission)
 	default:
@@ -44,6 +51,14 @@ func isModeratorPermission(permission string) bool {
 	return moderatorPermissions[permission]
 }
 
+func isSupportPermission(permission string) bool {
+	supportPermissions := map[string]bool{
+		"users.view":          true,
+		PermissionImpersonate: true,
+	}
+	return supportPermissions[permission]
+}
+
 func isUserPermission(permission string) bool {
 	userPermissions := map[string]bool{
 		"profile.view":   true,
@@ -34,4 +34,26 @@ func (s UserStatus) CanBeActivated() bool {
 
 func (s UserStatus) CanBeDeactivated() bool {
 	return s == StatusActive
-} 
\ No newline at end of file
+}
+
+func (s UserStatus) IsDeleted() bool {
+	return s == StatusDeleted
+}
+
+// statusTransitions 允许的状态迁移，删除和恢复只能通过 User.Delete / User.Restore 完成
+var statusTransitions = map[UserStatus][]UserStatus{
+	StatusActive:    {StatusInactive, StatusSuspended, StatusDeleted},
+	StatusInactive:  {StatusActive, StatusSuspended, StatusDeleted},
+	StatusSuspended: {StatusActive, StatusInactive, StatusDeleted},
+	StatusDeleted:   {StatusActive},
+}
+
+// CanTransitionTo 检查是否允许从当前状态迁移到目标状态
+func (s UserStatus) CanTransitionTo(target UserStatus) bool {
+	for _, t := range statusTransitions[s] {
+		if t == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/gohex/gohex/internal/application/dto"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/application/query"
+	"github.com/gohex/gohex/pkg/errors"
+	querybus "github.com/gohex/gohex/internal/application/port/input/query"
+)
+
+// AuditHandler 审计日志查询接口（仅管理员）
+type AuditHandler struct {
+	queryBus      querybus.Bus
+	maxExportRows int
+	logger        output.Logger
+}
+
+func NewAuditHandler(queryBus querybus.Bus, maxExportRows int, logger output.Logger) *AuditHandler {
+	return &AuditHandler{
+		queryBus:      queryBus,
+		maxExportRows: maxExportRows,
+		logger:        logger,
+	}
+}
+
+// ListAuditLogs 分页查询审计日志
+func (h *AuditHandler) ListAuditLogs(c echo.Context) error {
+	var params dto.AuditLogQueryDTO
+	if err := c.Bind(&params); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	q := &query.ListAuditLogsQuery{
+		Filter:   toAuditFilter(params),
+		Page:     params.Page,
+		PageSize: params.PageSize,
+	}
+	if q.Page == 0 {
+		q.Page = 1
+	}
+	if q.PageSize == 0 {
+		q.PageSize = 20
+	}
+
+	result, err := executeQuery(c.Request().Context(), c, h.queryBus, q)
+	if err != nil {
+		return h.handleError(err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// ExportAuditLogs 以 CSV 或 JSON 导出审计日志
+func (h *AuditHandler) ExportAuditLogs(c echo.Context) error {
+	var params dto.AuditLogQueryDTO
+	if err := c.Bind(&params); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	result, err := executeQuery(c.Request().Context(), c, h.queryBus, &query.ExportAuditLogsQuery{
+		Filter:  toAuditFilter(params),
+		MaxRows: h.maxExportRows,
+	})
+	if err != nil {
+		return h.handleError(err)
+	}
+	items := result.([]*dto.AuditLogDTO)
+
+	filename := fmt.Sprintf("audit-%s", time.Now().UTC().Format("20060102T150405Z"))
+
+	switch params.Format {
+	case "csv":
+		c.Response().Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s.csv"`, filename))
+		c.Response().WriteHeader(http.StatusOK)
+		return writeAuditCSV(c.Response(), items)
+	default:
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s.json"`, filename))
+		return c.JSON(http.StatusOK, items)
+	}
+}
+
+func (h *AuditHandler) handleError(err error) error {
+	h.logger.Error("audit request failed", "error", err)
+
+	var appErr *errors.AppError
+	if errors.As(err, &appErr) {
+		return echo.NewHTTPError(appErr.HTTPStatusCode(), appErr.Message)
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, "Internal Server Error")
+}
+
+func toAuditFilter(params dto.AuditLogQueryDTO) output.AuditFilter {
+	return output.AuditFilter{
+		ActorID:   params.ActorID,
+		SubjectID: params.SubjectID,
+		Action:    params.Action,
+		Result:    params.Result,
+		From:      params.From,
+		To:        params.To,
+	}
+}
+
+func writeAuditCSV(w http.ResponseWriter, items []*dto.AuditLogDTO) error {
+	writer := csv.NewWriter(w)
+	header := []string{
+		"sequence", "id", "occurred_at", "actor_id", "impersonator_id", "subject_id",
+		"action", "result", "error", "ip", "user_agent", "trace_id", "changes", "hash",
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		changes := ""
+		if len(item.Changes) > 0 {
+			data, err := json.Marshal(item.Changes)
+			if err != nil {
+				return err
+			}
+			changes = string(data)
+		}
+		if err := writer.Write([]string{
+			strconv.FormatInt(item.Sequence, 10),
+			item.ID,
+			item.OccurredAt.Format(time.RFC3339Nano),
+			item.ActorID,
+			item.ImpersonatorID,
+			item.SubjectID,
+			item.Action,
+			item.Result,
+			item.Error,
+			item.IP,
+			item.UserAgent,
+			item.TraceID,
+			changes,
+			item.Hash,
+		}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
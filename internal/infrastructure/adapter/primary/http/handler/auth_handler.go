@@ -3,28 +3,36 @@ package handler
 import (
 	"net/http"
 	"github.com/labstack/echo/v4"
-	"github.com/your-org/your-project/internal/application/command"
-	"github.com/your-org/your-project/internal/application/dto"
+	"github.com/go-playground/validator/v10"
+	"github.com/gohex/gohex/internal/application/command"
+	"github.com/gohex/gohex/internal/application/dto"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/infrastructure/adapter/primary/http/middleware"
+	"github.com/gohex/gohex/pkg/errors"
+	cmdbus "github.com/gohex/gohex/internal/application/port/input/command"
+	querybus "github.com/gohex/gohex/internal/application/port/input/query"
 )
 
 type AuthHandler struct {
-	commandBus  command.Bus
-	queryBus    query.Bus
-	authService AuthService
-	logger      Logger
+	commandBus cmdbus.Bus
+	queryBus   querybus.Bus
+	logger     output.Logger
+	metrics    output.MetricsReporter
+	validator  *validator.Validate
 }
 
 func NewAuthHandler(
-	commandBus command.Bus,
-	queryBus query.Bus,
-	authService AuthService,
-	logger Logger,
+	commandBus cmdbus.Bus,
+	queryBus querybus.Bus,
+	logger output.Logger,
+	metrics output.MetricsReporter,
 ) *AuthHandler {
 	return &AuthHandler{
-		commandBus:  commandBus,
-		queryBus:    queryBus,
-		authService: authService,
-		logger:      logger,
+		commandBus: commandBus,
+		queryBus:   queryBus,
+		logger:     logger,
+		metrics:    metrics,
+		validator:  validator.New(),
 	}
 }
 
@@ -61,7 +69,7 @@ func (h *AuthHandler) Logout(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
 	}
 
-	token := extractToken(c.Request().Header.Get("Authorization"))
+	token := middleware.BearerToken(c)
 	if token == "" {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid token")
 	}
@@ -71,7 +79,7 @@ func (h *AuthHandler) Logout(c echo.Context) error {
 		Token:  token,
 	}
 
-	if err := h.commandBus.Dispatch(c.Request().Context(), cmd); err != nil {
+	if _, err := h.commandBus.Dispatch(c.Request().Context(), cmd); err != nil {
 		h.logger.Error("logout failed", "error", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
@@ -79,27 +87,78 @@ func (h *AuthHandler) Logout(c echo.Context) error {
 	return c.NoContent(http.StatusOK)
 }
 
-// RefreshToken 刷新访问令牌
-func (h *AuthHandler) RefreshToken(c echo.Context) error {
-	// 1. 绑定请求
-	var req struct {
-		RefreshToken string `json:"refresh_token" validate:"required"`
+// EndImpersonation 结束当前模拟会话
+func (h *AuthHandler) EndImpersonation(c echo.Context) error {
+	actorID, _ := c.Get("impersonator_id").(string)
+	if actorID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "not an impersonation session")
 	}
-	if err := c.Bind(&req); err != nil {
+
+	token := middleware.BearerToken(c)
+	if token == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid token")
+	}
+
+	cmd := &command.EndImpersonationCommand{
+		ActorID:   actorID,
+		SubjectID: c.Get("user_id").(string),
+		Token:     token,
+	}
+
+	if _, err := h.commandBus.Dispatch(c.Request().Context(), cmd); err != nil {
+		h.logger.Error("end impersonation failed", "error", err)
+		return h.handleError(err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// ConfirmEmailChange 通过新邮箱中的链接确认变更
+func (h *AuthHandler) ConfirmEmailChange(c echo.Context) error {
+	var req dto.EmailChangeTokenDTO
+	if err := c.Bind(&req); err != nil || req.Token == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "token is required")
+	}
+
+	cmd := &command.ConfirmEmailChangeCommand{Token: req.Token}
+	if _, err := h.commandBus.Dispatch(c.Request().Context(), cmd); err != nil {
+		h.logger.Error("confirm email change failed", "error", err)
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// VerifyEmail 通过注册后邮件中的链接验证邮箱
+func (h *AuthHandler) VerifyEmail(c echo.Context) error {
+	var req dto.VerifyEmailDTO
+	if err := c.Bind(&req); err != nil || req.Token == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "token is required")
+	}
+
+	cmd := &command.VerifyEmailCommand{Token: req.Token}
+	if _, err := h.commandBus.Dispatch(c.Request().Context(), cmd); err != nil {
+		h.logger.Error("verify email failed", "error", err)
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	// 2. 执行刷新命令
-	cmd := &command.RefreshTokenCommand{
-		RefreshToken: req.RefreshToken,
+	return c.NoContent(http.StatusOK)
+}
+
+// CancelEmailChange 通过旧邮箱中的链接取消变更
+func (h *AuthHandler) CancelEmailChange(c echo.Context) error {
+	var req dto.EmailChangeTokenDTO
+	if err := c.Bind(&req); err != nil || req.Token == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "token is required")
 	}
-	result, err := h.commandBus.Dispatch(c.Request().Context(), cmd)
-	if err != nil {
-		h.logger.Error("refresh token failed", "error", err)
-		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+
+	cmd := &command.CancelEmailChangeCommand{Token: req.Token}
+	if _, err := h.commandBus.Dispatch(c.Request().Context(), cmd); err != nil {
+		h.logger.Error("cancel email change failed", "error", err)
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	return c.JSON(http.StatusOK, result)
+	return c.NoContent(http.StatusOK)
 }
 
 func (h *AuthHandler) Register(c echo.Context) error {
@@ -120,14 +179,14 @@ func (h *AuthHandler) Register(c echo.Context) error {
 	}
 
 	// 3. 执行注册命令
-	cmd := &command.RegisterUserCommand{
+	cmd := command.RegisterUserCommand{
 		Email:    req.Email,
 		Password: req.Password,
 		Name:     req.Name,
 		Bio:      req.Bio,
 	}
 
-	result, err := h.commandBus.Dispatch(c.Request().Context(), cmd)
+	result, err := cmdbus.Dispatch[command.RegisterUserCommand, command.RegisterUserResult](c.Request().Context(), h.commandBus, cmd)
 	if err != nil {
 		h.logger.Error("registration failed", "error", err)
 		return h.handleError(err)
@@ -137,4 +196,17 @@ func (h *AuthHandler) Register(c echo.Context) error {
 	h.metrics.IncrementCounter("user_registered")
 
 	return c.JSON(http.StatusCreated, result)
-} 
\ No newline at end of file
+} 
+
+func (h *AuthHandler) handleError(err error) error {
+	var appErr *errors.AppError
+	if errors.As(err, &appErr) {
+		return echo.NewHTTPError(appErr.HTTPStatusCode(), appErr.Message)
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, "Internal Server Error")
+}
+
+func (h *AuthHandler) handleValidationError(err error) error {
+	h.metrics.IncrementCounter("validation_error")
+	return echo.NewHTTPError(http.StatusBadRequest, errors.NewValidationError(err.Error()))
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	cmdbus "github.com/gohex/gohex/internal/application/port/input/command"
+	querybus "github.com/gohex/gohex/internal/application/port/input/query"
+)
+
+// DebugHandler 运行时诊断接口（仅管理员）
+type DebugHandler struct {
+	commandBus cmdbus.Bus
+	queryBus   querybus.Bus
+}
+
+func NewDebugHandler(commandBus cmdbus.Bus, queryBus querybus.Bus) *DebugHandler {
+	return &DebugHandler{
+		commandBus: commandBus,
+		queryBus:   queryBus,
+	}
+}
+
+// BusRegistry 输出命令和查询总线已注册的处理器与中间件
+func (h *DebugHandler) BusRegistry(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"commands": h.commandBus.Describe(),
+		"queries":  h.queryBus.Describe(),
+	})
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/gohex/gohex/internal/application/command"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/application/query"
+	"github.com/gohex/gohex/pkg/errors"
+	cmdbus "github.com/gohex/gohex/internal/application/port/input/command"
+	querybus "github.com/gohex/gohex/internal/application/port/input/query"
+)
+
+// JobHandler 异步命令任务的状态查询和取消
+type JobHandler struct {
+	commandBus cmdbus.Bus
+	queryBus   querybus.Bus
+	logger     output.Logger
+}
+
+func NewJobHandler(commandBus cmdbus.Bus, queryBus querybus.Bus, logger output.Logger) *JobHandler {
+	return &JobHandler{
+		commandBus: commandBus,
+		queryBus:   queryBus,
+		logger:     logger,
+	}
+}
+
+// GetJob 返回任务状态、进度、结果和错误，只有发起者或管理员可见
+func (h *JobHandler) GetJob(c echo.Context) error {
+	q := &query.GetJobQuery{
+		JobID:       c.Param("id"),
+		RequestedBy: c.Get("user_id").(string),
+		IsAdmin:     isAdmin(c),
+	}
+	if err := q.Validate(); err != nil {
+		return h.handleError(err)
+	}
+
+	result, err := executeQuery(c.Request().Context(), c, h.queryBus, q)
+	if err != nil {
+		return h.handleError(err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// CancelJob 取消任务，运行中的任务异步中断，返回 202
+func (h *JobHandler) CancelJob(c echo.Context) error {
+	cmd := &command.CancelJobCommand{
+		JobID:       c.Param("id"),
+		RequestedBy: c.Get("user_id").(string),
+		IsAdmin:     isAdmin(c),
+	}
+
+	if _, err := h.commandBus.Dispatch(c.Request().Context(), cmd); err != nil {
+		return h.handleError(err)
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
+
+func (h *JobHandler) handleError(err error) error {
+	h.logger.Error("job request failed", "error", err)
+
+	var appErr *errors.AppError
+	if errors.As(err, &appErr) {
+		return echo.NewHTTPError(appErr.HTTPStatusCode(), appErr.Message)
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, "Internal Server Error")
+}
+
+// isAdmin 当前请求用户是否具有管理员角色
+func isAdmin(c echo.Context) bool {
+	roles, ok := c.Get("user_roles").([]string)
+	if !ok {
+		return false
+	}
+	for _, role := range roles {
+		if role == "admin" {
+			return true
+		}
+	}
+	return false
+}
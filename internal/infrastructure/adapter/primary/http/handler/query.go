@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	querybus "github.com/gohex/gohex/internal/application/port/input/query"
+	"github.com/gohex/gohex/internal/application/query"
+)
+
+// 查询执行元数据的响应头
+const (
+	HeaderQueryID       = "X-Query-ID"
+	HeaderQueryAttempts = "X-Query-Attempts"
+	HeaderCache         = "X-Cache"
+	HeaderTraceID       = "X-Trace-ID"
+)
+
+// executeQuery 通过总线执行查询，并把执行元数据写入响应头，查询失败时同样写入便于排查
+func executeQuery(ctx context.Context, c echo.Context, bus querybus.Bus, q interface{}) (interface{}, error) {
+	result := query.ExecuteWithResult(ctx, bus, q)
+
+	header := c.Response().Header()
+	header.Set(HeaderQueryID, result.Metadata.QueryID)
+	header.Set(HeaderQueryAttempts, strconv.Itoa(result.Metadata.Attempts))
+	if result.CacheHit {
+		header.Set(HeaderCache, "HIT")
+	} else {
+		header.Set(HeaderCache, "MISS")
+	}
+	if result.TraceID != "" {
+		header.Set(HeaderTraceID, result.TraceID)
+	}
+
+	return result.Data, result.Error
+}
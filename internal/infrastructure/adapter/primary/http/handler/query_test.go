@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	querybus "github.com/gohex/gohex/internal/application/port/input/query"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+// resultBus 返回固定结果，cacheHit 时模拟缓存中间件命中
+type resultBus struct {
+	querybus.Bus
+	result   interface{}
+	err      error
+	cacheHit bool
+}
+
+func (b *resultBus) Execute(ctx context.Context, q interface{}) (interface{}, error) {
+	if exec := querybus.ExecutionFromContext(ctx); exec != nil {
+		exec.CacheHit = b.cacheHit
+	}
+	return b.result, b.err
+}
+
+func TestExecuteQuery_SetsMetadataHeaders(t *testing.T) {
+	tests := []struct {
+		name         string
+		bus          *resultBus
+		wantCache    string
+		wantAttempts string
+		wantErr      error
+	}{
+		{name: "miss", bus: &resultBus{result: "ok"}, wantCache: "MISS", wantAttempts: "1"},
+		{name: "hit", bus: &resultBus{result: "ok", cacheHit: true}, wantCache: "HIT", wantAttempts: "0"},
+		{name: "error", bus: &resultBus{err: errors.ErrUserNotFound}, wantCache: "MISS", wantAttempts: "1", wantErr: errors.ErrUserNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+			result, err := executeQuery(context.Background(), c, tt.bus, struct{}{})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "ok", result)
+			}
+			assert.NotEmpty(t, rec.Header().Get(HeaderQueryID))
+			assert.Equal(t, tt.wantCache, rec.Header().Get(HeaderCache))
+			assert.Equal(t, tt.wantAttempts, rec.Header().Get(HeaderQueryAttempts))
+			// 未启用追踪时不返回 trace ID
+			assert.Empty(t, rec.Header().Get(HeaderTraceID))
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/gohex/gohex/internal/application/command"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/application/query"
+	"github.com/gohex/gohex/pkg/errors"
+	cmdbus "github.com/gohex/gohex/internal/application/port/input/command"
+	querybus "github.com/gohex/gohex/internal/application/port/input/query"
+)
+
+// ScheduleHandler 计划命令查询和取消接口（仅管理员）
+type ScheduleHandler struct {
+	commandBus cmdbus.Bus
+	queryBus   querybus.Bus
+	logger     output.Logger
+}
+
+func NewScheduleHandler(commandBus cmdbus.Bus, queryBus querybus.Bus, logger output.Logger) *ScheduleHandler {
+	return &ScheduleHandler{
+		commandBus: commandBus,
+		queryBus:   queryBus,
+		logger:     logger,
+	}
+}
+
+// ListSchedules 分页查询计划命令，可按状态和命令类型过滤
+func (h *ScheduleHandler) ListSchedules(c echo.Context) error {
+	q := &query.ListScheduledCommandsQuery{
+		Status:      c.QueryParam("status"),
+		CommandType: c.QueryParam("command_type"),
+		Page:        1,
+		PageSize:    20,
+	}
+	if page, err := strconv.Atoi(c.QueryParam("page")); err == nil {
+		q.Page = page
+	}
+	if pageSize, err := strconv.Atoi(c.QueryParam("page_size")); err == nil {
+		q.PageSize = pageSize
+	}
+	if err := q.Validate(); err != nil {
+		return h.handleError(err)
+	}
+
+	result, err := executeQuery(c.Request().Context(), c, h.queryBus, q)
+	if err != nil {
+		return h.handleError(err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// CancelSchedule 取消仍在等待执行的计划
+func (h *ScheduleHandler) CancelSchedule(c echo.Context) error {
+	cmd := &command.CancelScheduledCommandCommand{ScheduleID: c.Param("id")}
+
+	if _, err := h.commandBus.Dispatch(c.Request().Context(), cmd); err != nil {
+		return h.handleError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *ScheduleHandler) handleError(err error) error {
+	h.logger.Error("schedule request failed", "error", err)
+
+	var appErr *errors.AppError
+	if errors.As(err, &appErr) {
+		return echo.NewHTTPError(appErr.HTTPStatusCode(), appErr.Message)
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, "Internal Server Error")
+}
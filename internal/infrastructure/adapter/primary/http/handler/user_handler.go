@@ -2,6 +2,7 @@ package handler
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -9,6 +10,9 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/gohex/gohex/internal/application/dto"
 	"github.com/gohex/gohex/internal/application/command"
+	cmdbus "github.com/gohex/gohex/internal/application/port/input/command"
+	querybus "github.com/gohex/gohex/internal/application/port/input/query"
+	"github.com/gohex/gohex/internal/application/port/output"
 	"github.com/gohex/gohex/internal/application/query"
 	"github.com/gohex/gohex/pkg/errors"
 	"github.com/gohex/gohex/pkg/tracer"
@@ -16,19 +20,19 @@ import (
 
 // UserHandler 处理所有用户相关的 HTTP 请求
 type UserHandler struct {
-	commandBus command.Bus
-	queryBus   query.Bus
-	logger     Logger
-	metrics    MetricsReporter
+	commandBus cmdbus.Bus
+	queryBus   querybus.Bus
+	logger     output.Logger
+	metrics    output.MetricsReporter
 	validator  *validator.Validate
 }
 
 // NewUserHandler 创建新的 UserHandler 实例
 func NewUserHandler(
-	commandBus command.Bus,
-	queryBus query.Bus,
-	logger Logger,
-	metrics MetricsReporter,
+	commandBus cmdbus.Bus,
+	queryBus querybus.Bus,
+	logger output.Logger,
+	metrics output.MetricsReporter,
 ) *UserHandler {
 	return &UserHandler{
 		commandBus: commandBus,
@@ -75,7 +79,7 @@ func (h *UserHandler) Register(c echo.Context) error {
 	}
 
 	// 4. 执行命令
-	result, err := h.commandBus.Dispatch(ctx, cmd)
+	result, err := cmdbus.Dispatch[command.RegisterUserCommand, command.RegisterUserResult](ctx, h.commandBus, cmd)
 	if err != nil {
 		return h.handleError(err)
 	}
@@ -94,8 +98,8 @@ func (h *UserHandler) GetUser(c echo.Context) error {
 		return h.handleError(errors.NewValidationError("user_id is required"))
 	}
 
-	q := query.GetUserQuery{ID: userID}
-	user, err := h.queryBus.Execute(ctx, q)
+	q := &query.GetUserQuery{ID: userID}
+	user, err := querybus.Execute[*query.GetUserQuery, *dto.UserDTO](ctx, h.queryBus, q)
 	if err != nil {
 		return h.handleError(err)
 	}
@@ -105,8 +109,11 @@ func (h *UserHandler) GetUser(c echo.Context) error {
 
 // UpdateProfileRequest 更新用户资料请求
 type UpdateProfileRequest struct {
-	Name string `json:"name" validate:"required"`
-	Bio  string `json:"bio" validate:"max=500"`
+	Name     string `json:"name" validate:"required"`
+	Bio      string `json:"bio" validate:"max=500"`
+	Avatar   string `json:"avatar" validate:"omitempty,url"`
+	Location string `json:"location"`
+	Website  string `json:"website" validate:"omitempty,url"`
 }
 
 // UpdateProfile 更新用户资料
@@ -115,8 +122,8 @@ func (h *UserHandler) UpdateProfile(c echo.Context) error {
 	defer span.End()
 
 	userID := c.Param("id")
-	if userID == "" {
-		return h.handleError(errors.NewValidationError("user_id is required"))
+	if err := h.authorizeSelfOrAdmin(c, userID); err != nil {
+		return h.handleError(err)
 	}
 
 	var req UpdateProfileRequest
@@ -128,19 +135,334 @@ func (h *UserHandler) UpdateProfile(c echo.Context) error {
 		return h.handleValidationError(err)
 	}
 
-	cmd := command.UpdateProfileCommand{
-		UserID: userID,
-		Name:   req.Name,
-		Bio:    req.Bio,
+	cmd := &command.UpdateUserProfileCommand{
+		UserID:   userID,
+		Name:     req.Name,
+		Bio:      req.Bio,
+		Avatar:   req.Avatar,
+		Location: req.Location,
+		Website:  req.Website,
+	}
+
+	if _, err := h.commandBus.Dispatch(ctx, cmd); err != nil {
+		return h.handleError(err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// UpdateUserStatusRequest 修改用户状态请求
+type UpdateUserStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=active inactive suspended"`
+}
+
+// UpdateUserStatus 修改用户状态，仅管理员可用
+func (h *UserHandler) UpdateUserStatus(c echo.Context) error {
+	span, ctx := tracer.StartSpan(c.Request().Context(), "UserHandler.UpdateUserStatus")
+	defer span.End()
+
+	if !isAdmin(c) {
+		return h.handleError(errors.ErrPermissionDenied)
+	}
+
+	var req UpdateUserStatusRequest
+	if err := c.Bind(&req); err != nil {
+		return h.handleError(err)
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return h.handleValidationError(err)
+	}
+
+	cmd := &command.ChangeUserStatusCommand{
+		UserID: c.Param("id"),
+		Status: req.Status,
+	}
+
+	if _, err := h.commandBus.Dispatch(ctx, cmd); err != nil {
+		return h.handleError(err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// ChangePasswordRequest 修改密码请求
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=8"`
+}
+
+// ChangePassword 修改密码，只能由用户本人发起
+func (h *UserHandler) ChangePassword(c echo.Context) error {
+	span, ctx := tracer.StartSpan(c.Request().Context(), "UserHandler.ChangePassword")
+	defer span.End()
+
+	userID := c.Param("id")
+	if userID == "" || c.Get("user_id") != userID {
+		return h.handleError(errors.ErrPermissionDenied)
+	}
+
+	var req ChangePasswordRequest
+	if err := c.Bind(&req); err != nil {
+		return h.handleError(err)
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return h.handleValidationError(err)
+	}
+
+	cmd := &command.ChangePasswordCommand{
+		UserID:          userID,
+		CurrentPassword: req.CurrentPassword,
+		NewPassword:     req.NewPassword,
 	}
 
-	if err := h.commandBus.Dispatch(ctx, cmd); err != nil {
+	if _, err := h.commandBus.Dispatch(ctx, cmd); err != nil {
 		return h.handleError(err)
 	}
 
 	return c.NoContent(http.StatusOK)
 }
 
+// Impersonate 以目标用户身份签发短期令牌
+func (h *UserHandler) Impersonate(c echo.Context) error {
+	span, ctx := tracer.StartSpan(c.Request().Context(), "UserHandler.Impersonate")
+	defer span.End()
+
+	subjectID := c.Param("id")
+	if subjectID == "" {
+		return h.handleError(errors.NewValidationError("user_id is required"))
+	}
+
+	var req dto.ImpersonateRequestDTO
+	if err := c.Bind(&req); err != nil {
+		return h.handleError(err)
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return h.handleValidationError(err)
+	}
+
+	cmd := &command.ImpersonateUserCommand{
+		ActorID:   c.Get("user_id").(string),
+		SubjectID: subjectID,
+		Reason:    req.Reason,
+		IP:        c.RealIP(),
+		UserAgent: c.Request().UserAgent(),
+	}
+
+	result, err := h.commandBus.Dispatch(ctx, cmd)
+	if err != nil {
+		return h.handleError(err)
+	}
+
+	return c.JSON(http.StatusCreated, result)
+}
+
+// RequestEmailChange 申请变更邮箱，只能由用户本人发起
+func (h *UserHandler) RequestEmailChange(c echo.Context) error {
+	span, ctx := tracer.StartSpan(c.Request().Context(), "UserHandler.RequestEmailChange")
+	defer span.End()
+
+	userID := c.Param("id")
+	if userID == "" || c.Get("user_id") != userID {
+		return h.handleError(errors.ErrPermissionDenied)
+	}
+
+	var req dto.EmailChangeRequestDTO
+	if err := c.Bind(&req); err != nil {
+		return h.handleError(err)
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return h.handleValidationError(err)
+	}
+
+	cmd := &command.RequestEmailChangeCommand{
+		UserID:   userID,
+		NewEmail: req.NewEmail,
+	}
+
+	result, err := h.commandBus.Dispatch(ctx, cmd)
+	if err != nil {
+		return h.handleError(err)
+	}
+
+	return c.JSON(http.StatusAccepted, result)
+}
+
+// RequestDataExport 申请导出个人数据，归档由后台任务异步生成
+func (h *UserHandler) RequestDataExport(c echo.Context) error {
+	span, ctx := tracer.StartSpan(c.Request().Context(), "UserHandler.RequestDataExport")
+	defer span.End()
+
+	userID := c.Param("id")
+	if err := h.authorizeSelfOrAdmin(c, userID); err != nil {
+		return h.handleError(err)
+	}
+
+	cmd := &command.RequestDataExportCommand{
+		UserID:      userID,
+		RequestedBy: c.Get("user_id").(string),
+	}
+
+	result, err := h.commandBus.Dispatch(ctx, cmd)
+	if err != nil {
+		return h.handleError(err)
+	}
+
+	return c.JSON(http.StatusAccepted, result)
+}
+
+// DownloadDataExport 下载数据导出归档，未完成时返回处理状态
+func (h *UserHandler) DownloadDataExport(c echo.Context) error {
+	span, ctx := tracer.StartSpan(c.Request().Context(), "UserHandler.DownloadDataExport")
+	defer span.End()
+
+	userID := c.Param("id")
+	if err := h.authorizeSelfOrAdmin(c, userID); err != nil {
+		return h.handleError(err)
+	}
+
+	q := &query.GetDataExportQuery{
+		UserID:   userID,
+		ExportID: c.Param("export_id"),
+	}
+
+	result, err := executeQuery(ctx, c, h.queryBus, q)
+	if err != nil {
+		return h.handleError(err)
+	}
+
+	export := result.(*output.DataExport)
+	switch export.Status {
+	case output.DataExportCompleted:
+		h.metrics.IncrementCounter("data_export_downloaded")
+		c.Response().Header().Set(echo.HeaderContentDisposition,
+			fmt.Sprintf("attachment; filename=%q", "export-"+export.ID+".zip"))
+		return c.Blob(http.StatusOK, "application/zip", export.Archive)
+	case output.DataExportFailed:
+		return c.JSON(http.StatusOK, dto.NewDataExportDTO(export))
+	default:
+		return c.JSON(http.StatusAccepted, dto.NewDataExportDTO(export))
+	}
+}
+
+// EraseUser 擦除用户个人信息（被遗忘权），操作不可逆
+func (h *UserHandler) EraseUser(c echo.Context) error {
+	span, ctx := tracer.StartSpan(c.Request().Context(), "UserHandler.EraseUser")
+	defer span.End()
+
+	userID := c.Param("id")
+	if err := h.authorizeSelfOrAdmin(c, userID); err != nil {
+		return h.handleError(err)
+	}
+
+	var req dto.EraseUserRequestDTO
+	if err := c.Bind(&req); err != nil {
+		return h.handleError(err)
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return h.handleValidationError(err)
+	}
+
+	cmd := &command.EraseUserCommand{
+		UserID:      userID,
+		RequestedBy: c.Get("user_id").(string),
+		Reason:      req.Reason,
+	}
+
+	if _, err := h.commandBus.Dispatch(ctx, cmd); err != nil {
+		return h.handleError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// DeleteUser 软删除用户，宽限期内管理员可以恢复
+func (h *UserHandler) DeleteUser(c echo.Context) error {
+	span, ctx := tracer.StartSpan(c.Request().Context(), "UserHandler.DeleteUser")
+	defer span.End()
+
+	userID := c.Param("id")
+	if err := h.authorizeSelfOrAdmin(c, userID); err != nil {
+		return h.handleError(err)
+	}
+
+	cmd := &command.DeleteUserCommand{
+		UserID:      userID,
+		RequestedBy: c.Get("user_id").(string),
+	}
+
+	if _, err := h.commandBus.Dispatch(ctx, cmd); err != nil {
+		return h.handleError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// RestoreUser 恢复宽限期内的软删除用户，仅管理员可用
+func (h *UserHandler) RestoreUser(c echo.Context) error {
+	span, ctx := tracer.StartSpan(c.Request().Context(), "UserHandler.RestoreUser")
+	defer span.End()
+
+	userID := c.Param("id")
+	if userID == "" {
+		return h.handleError(errors.NewValidationError("user_id is required"))
+	}
+
+	cmd := &command.RestoreUserCommand{
+		UserID:      userID,
+		RequestedBy: c.Get("user_id").(string),
+	}
+
+	if _, err := h.commandBus.Dispatch(ctx, cmd); err != nil {
+		return h.handleError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// BulkAssignRole 批量分配角色，命令异步执行，返回 202 和任务 ID
+func (h *UserHandler) BulkAssignRole(c echo.Context) error {
+	span, ctx := tracer.StartSpan(c.Request().Context(), "UserHandler.BulkAssignRole")
+	defer span.End()
+
+	var req dto.BulkAssignRoleRequestDTO
+	if err := c.Bind(&req); err != nil {
+		return h.handleError(err)
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return h.handleValidationError(err)
+	}
+
+	cmd := &command.BulkAssignRoleCommand{
+		UserIDs: req.UserIDs,
+		Role:    req.Role,
+	}
+
+	jobID, err := h.commandBus.DispatchAsync(ctx, cmd)
+	if err != nil {
+		return h.handleError(err)
+	}
+
+	c.Response().Header().Set(echo.HeaderLocation, "/api/v1/jobs/"+jobID)
+	return c.JSON(http.StatusAccepted, dto.JobAcceptedDTO{JobID: jobID})
+}
+
+// authorizeSelfOrAdmin 只允许用户本人或管理员操作
+func (h *UserHandler) authorizeSelfOrAdmin(c echo.Context, userID string) error {
+	if userID == "" {
+		return errors.NewValidationError("user_id is required")
+	}
+	if c.Get("user_id") == userID || isAdmin(c) {
+		return nil
+	}
+	return errors.ErrPermissionDenied
+}
+
 // handleError 统一错误处理
 func (h *UserHandler) handleError(err error) error {
 	h.logger.Error("request failed", "error", err)
@@ -171,6 +493,12 @@ func (h *UserHandler) statusCodeFromError(err *errors.AppError) int {
 		return http.StatusConflict
 	case errors.ErrCodeUnauthorized:
 		return http.StatusUnauthorized
+	case errors.ErrCodeForbidden:
+		return http.StatusForbidden
+	case errors.ErrCodeUnprocessable:
+		return http.StatusUnprocessableEntity
+	case errors.ErrCodeUnavailable:
+		return http.StatusServiceUnavailable
 	default:
 		return http.StatusInternalServerError
 	}
@@ -200,7 +528,7 @@ func (h *UserHandler) ListUsers(c echo.Context) error {
 		SortDir:  params.SortDir,
 	}
 
-	result, err := h.queryBus.Execute(ctx, query)
+	result, err := executeQuery(ctx, c, h.queryBus, query)
 	if err != nil {
 		return h.handleError(err)
 	}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/command"
+	"github.com/gohex/gohex/internal/application/dto"
+	cmdbus "github.com/gohex/gohex/internal/application/port/input/command"
+	"github.com/gohex/gohex/internal/testutil"
+)
+
+// asyncBus 记录异步分发的命令
+type asyncBus struct {
+	cmdbus.Bus
+	dispatched []interface{}
+}
+
+func (b *asyncBus) DispatchAsync(ctx context.Context, cmd interface{}) (string, error) {
+	b.dispatched = append(b.dispatched, cmd)
+	return "job-1", nil
+}
+
+func TestUserHandler_BulkAssignRole(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantCmd    *command.BulkAssignRoleCommand
+	}{
+		{
+			name:       "enqueues command",
+			body:       `{"user_ids":["alice","bob"],"role":"support"}`,
+			wantStatus: http.StatusAccepted,
+			wantCmd:    &command.BulkAssignRoleCommand{UserIDs: []string{"alice", "bob"}, Role: "support"},
+		},
+		{name: "no users", body: `{"user_ids":[],"role":"support"}`, wantStatus: http.StatusBadRequest},
+		{name: "empty user id", body: `{"user_ids":[""],"role":"support"}`, wantStatus: http.StatusBadRequest},
+		{name: "missing role", body: `{"user_ids":["alice"]}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := &asyncBus{}
+			h := NewUserHandler(bus, nil, testutil.NopLogger{}, testutil.NewMetrics())
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/users/roles", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			err := h.BulkAssignRole(e.NewContext(req, rec))
+			if tt.wantCmd == nil {
+				var httpErr *echo.HTTPError
+				require.ErrorAs(t, err, &httpErr)
+				assert.Equal(t, tt.wantStatus, httpErr.Code)
+				assert.Empty(t, bus.dispatched)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, "/api/v1/jobs/job-1", rec.Header().Get(echo.HeaderLocation))
+			var accepted dto.JobAcceptedDTO
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &accepted))
+			assert.Equal(t, "job-1", accepted.JobID)
+			assert.Equal(t, []interface{}{tt.wantCmd}, bus.dispatched)
+		})
+	}
+}
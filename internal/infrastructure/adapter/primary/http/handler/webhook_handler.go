@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/gohex/gohex/internal/application/command"
+	"github.com/gohex/gohex/internal/application/dto"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/application/query"
+	"github.com/gohex/gohex/pkg/errors"
+	cmdbus "github.com/gohex/gohex/internal/application/port/input/command"
+	querybus "github.com/gohex/gohex/internal/application/port/input/query"
+)
+
+// WebhookHandler webhook 订阅管理和投递记录接口（仅管理员）
+type WebhookHandler struct {
+	commandBus cmdbus.Bus
+	queryBus   querybus.Bus
+	validator  *validator.Validate
+	logger     output.Logger
+}
+
+func NewWebhookHandler(commandBus cmdbus.Bus, queryBus querybus.Bus, logger output.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		commandBus: commandBus,
+		queryBus:   queryBus,
+		validator:  validator.New(),
+		logger:     logger,
+	}
+}
+
+// CreateSubscription 创建订阅，响应中包含签名密钥，之后不再返回
+func (h *WebhookHandler) CreateSubscription(c echo.Context) error {
+	var req dto.CreateWebhookDTO
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := h.validator.Struct(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	cmd := &command.CreateWebhookSubscriptionCommand{
+		URL:         req.URL,
+		EventTypes:  req.EventTypes,
+		Secret:      req.Secret,
+		Description: req.Description,
+		CreatedBy:   c.Get("user_id").(string),
+	}
+
+	result, err := h.commandBus.Dispatch(c.Request().Context(), cmd)
+	if err != nil {
+		return h.handleError(err)
+	}
+
+	return c.JSON(http.StatusCreated, result)
+}
+
+// ListSubscriptions 列出所有订阅
+func (h *WebhookHandler) ListSubscriptions(c echo.Context) error {
+	result, err := executeQuery(c.Request().Context(), c, h.queryBus, &query.ListWebhookSubscriptionsQuery{})
+	if err != nil {
+		return h.handleError(err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetSubscription 获取订阅详情
+func (h *WebhookHandler) GetSubscription(c echo.Context) error {
+	q := &query.GetWebhookSubscriptionQuery{SubscriptionID: c.Param("id")}
+	if err := q.Validate(); err != nil {
+		return h.handleError(err)
+	}
+
+	result, err := executeQuery(c.Request().Context(), c, h.queryBus, q)
+	if err != nil {
+		return h.handleError(err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// UpdateSubscription 更新订阅，active=true 可重新启用被自动停用的订阅
+func (h *WebhookHandler) UpdateSubscription(c echo.Context) error {
+	var req dto.UpdateWebhookDTO
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := h.validator.Struct(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	cmd := &command.UpdateWebhookSubscriptionCommand{
+		SubscriptionID: c.Param("id"),
+		URL:            req.URL,
+		EventTypes:     req.EventTypes,
+		Secret:         req.Secret,
+		Description:    req.Description,
+		Active:         req.Active,
+	}
+
+	result, err := h.commandBus.Dispatch(c.Request().Context(), cmd)
+	if err != nil {
+		return h.handleError(err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// DeleteSubscription 删除订阅及其投递记录
+func (h *WebhookHandler) DeleteSubscription(c echo.Context) error {
+	cmd := &command.DeleteWebhookSubscriptionCommand{SubscriptionID: c.Param("id")}
+
+	if _, err := h.commandBus.Dispatch(c.Request().Context(), cmd); err != nil {
+		return h.handleError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListDeliveries 分页查询订阅的投递记录
+func (h *WebhookHandler) ListDeliveries(c echo.Context) error {
+	q := &query.ListWebhookDeliveriesQuery{
+		SubscriptionID: c.Param("id"),
+		Page:           1,
+		PageSize:       20,
+	}
+	if page, err := strconv.Atoi(c.QueryParam("page")); err == nil {
+		q.Page = page
+	}
+	if pageSize, err := strconv.Atoi(c.QueryParam("page_size")); err == nil {
+		q.PageSize = pageSize
+	}
+	if err := q.Validate(); err != nil {
+		return h.handleError(err)
+	}
+
+	result, err := executeQuery(c.Request().Context(), c, h.queryBus, q)
+	if err != nil {
+		return h.handleError(err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// Redeliver 重新投递一条记录，返回新的待投递记录
+func (h *WebhookHandler) Redeliver(c echo.Context) error {
+	cmd := &command.RedeliverWebhookCommand{
+		SubscriptionID: c.Param("id"),
+		DeliveryID:     c.Param("delivery_id"),
+	}
+
+	result, err := h.commandBus.Dispatch(c.Request().Context(), cmd)
+	if err != nil {
+		return h.handleError(err)
+	}
+
+	return c.JSON(http.StatusAccepted, result)
+}
+
+func (h *WebhookHandler) handleError(err error) error {
+	h.logger.Error("webhook request failed", "error", err)
+
+	var appErr *errors.AppError
+	if errors.As(err, &appErr) {
+		return echo.NewHTTPError(appErr.HTTPStatusCode(), appErr.Message)
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, "Internal Server Error")
+}
@@ -3,20 +3,25 @@ package middleware
 import (
 	"strings"
 	"github.com/labstack/echo/v4"
-	"github.com/your-org/your-project/internal/application/query"
+	"github.com/gohex/gohex/internal/application/dto"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/application/query"
+	"github.com/gohex/gohex/pkg/actor"
+	"github.com/gohex/gohex/pkg/errors"
+	querybus "github.com/gohex/gohex/internal/application/port/input/query"
 	"net/http"
 )
 
 type AuthMiddleware struct {
-	queryBus query.QueryBus
-	logger   Logger
-	metrics  MetricsReporter
+	queryBus querybus.Bus
+	logger   output.Logger
+	metrics  output.MetricsReporter
 }
 
 func NewAuthMiddleware(
-	queryBus query.QueryBus,
-	logger Logger,
-	metrics MetricsReporter,
+	queryBus querybus.Bus,
+	logger output.Logger,
+	metrics output.MetricsReporter,
 ) *AuthMiddleware {
 	return &AuthMiddleware{
 		queryBus: queryBus,
@@ -31,7 +36,7 @@ func (m *AuthMiddleware) Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
 		defer timer.Stop()
 
 		// 1. 提取令牌
-		token := extractToken(c.Request().Header.Get("Authorization"))
+		token := BearerToken(c)
 		if token == "" {
 			m.metrics.IncrementCounter("auth_middleware_missing_token")
 			return echo.NewHTTPError(401, "missing token")
@@ -42,13 +47,14 @@ func (m *AuthMiddleware) Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
 		result, err := m.queryBus.Execute(c.Request().Context(), q)
 		if err != nil {
 			m.metrics.IncrementCounter("auth_middleware_invalid_token")
-			return echo.NewHTTPError(401, "invalid token")
+			return authError(err, "invalid token")
 		}
 
 		// 3. 设置上下文
 		authInfo := result.(*dto.AuthInfoDTO)
 		c.Set("user_id", authInfo.UserID)
 		c.Set("roles", authInfo.Roles)
+		setActor(c, authInfo.UserID, authInfo.ActorID)
 
 		m.metrics.IncrementCounter("auth_middleware_success")
 		return next(c)
@@ -92,15 +98,32 @@ func (m *AuthMiddleware) RequireRoles(roles ...string) echo.MiddlewareFunc {
 	}
 }
 
-func extractToken(auth string) string {
-	if auth == "" {
-		return ""
+// ForbidImpersonation 拒绝模拟会话访问敏感接口
+func ForbidImpersonation() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if actor.IsImpersonated(c.Request().Context()) {
+				return echo.NewHTTPError(http.StatusForbidden, "action not allowed while impersonating")
+			}
+			return next(c)
+		}
 	}
-	parts := strings.SplitN(auth, " ", 2)
-	if len(parts) != 2 || parts[0] != "Bearer" {
-		return ""
+}
+
+// setActor 将实际操作人写入 echo 上下文和请求上下文，供日志和审计使用
+func setActor(c echo.Context, userID string, actorID string) {
+	a := actor.Actor{
+		UserID:    userID,
+		IP:        c.RealIP(),
+		UserAgent: c.Request().UserAgent(),
+		TraceID:   c.Request().Header.Get(echo.HeaderXRequestID),
 	}
-	return parts[1]
+	if actorID != "" && actorID != userID {
+		a.ImpersonatorID = actorID
+		c.Set("impersonator_id", actorID)
+	}
+	c.Set("actor_id", a.RealActorID())
+	c.SetRequest(c.Request().WithContext(actor.WithActor(c.Request().Context(), a)))
 }
 
 func hasAnyRole(userRoles []string, requiredRoles []string) bool {
@@ -114,37 +137,35 @@ func hasAnyRole(userRoles []string, requiredRoles []string) bool {
 	return false
 }
 
-func RequireAuth(authService AuthService) echo.MiddlewareFunc {
+// RequireAuth 校验访问令牌，令牌已吊销或吊销状态无法确认时拒绝请求
+func RequireAuth(tokenSvc output.TokenService) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			// 获取令牌
-			token := extractToken(c)
+			token := BearerToken(c)
 			if token == "" {
 				return echo.NewHTTPError(http.StatusUnauthorized, "missing token")
 			}
 			
 			// 验证令牌
-			claims, err := authService.ValidateToken(c.Request().Context(), token)
+			claims, err := tokenSvc.ValidateToken(c.Request().Context(), token)
 			if err != nil {
-				return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
-			}
-			
-			// 检查令牌是否已吊销
-			if authService.IsTokenRevoked(c.Request().Context(), token) {
-				return echo.NewHTTPError(http.StatusUnauthorized, "token revoked")
+				return authError(err, err.Error())
 			}
 			
 			// 设置用户信息到上下文
 			c.Set("user_id", claims.UserID)
 			c.Set("user_email", claims.Email)
 			c.Set("user_roles", claims.Roles)
+			setActor(c, claims.UserID, claims.ActorID())
 			
 			return next(c)
 		}
 	}
 }
 
-func extractToken(c echo.Context) string {
+// BearerToken 从 Authorization 请求头读取 Bearer 令牌
+func BearerToken(c echo.Context) string {
 	auth := c.Request().Header.Get("Authorization")
 	if auth == "" {
 		return ""
@@ -156,4 +177,12 @@ func extractToken(c echo.Context) string {
 	}
 	
 	return parts[1]
-} 
\ No newline at end of file
+} 
+
+// authError 吊销状态无法确认时返回 503，客户端可稍后重试；其余校验失败返回 401
+func authError(err error, message string) error {
+	if errors.Is(err, errors.ErrTokenRevocationUnavailable) {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, errors.ErrTokenRevocationUnavailable.Message)
+	}
+	return echo.NewHTTPError(http.StatusUnauthorized, message)
+}
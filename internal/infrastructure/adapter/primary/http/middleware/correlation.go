@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/gohex/gohex/internal/domain/event"
+)
+
+// maxRequestIDLength 超长的 X-Request-ID 视为无效，重新生成
+const maxRequestIDLength = 64
+
+// Correlation 以 X-Request-ID 作为关联 ID 写入请求上下文，请求中产生的事件及其引发的后续处理共享该 ID。
+// 请求未携带时生成新的 ID 并通过响应头返回
+func Correlation() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			id := c.Request().Header.Get(echo.HeaderXRequestID)
+			if id == "" || len(id) > maxRequestIDLength {
+				id = uuid.NewString()
+				c.Request().Header.Set(echo.HeaderXRequestID, id)
+			}
+
+			ctx := event.WithCorrelation(c.Request().Context(), id)
+			c.SetRequest(c.Request().WithContext(ctx))
+			c.Response().Header().Set(echo.HeaderXRequestID, id)
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/gohex/gohex/internal/application/port/input/command"
+)
+
+// IdempotencyKeyHeader 客户端重试时携带的幂等 key 请求头
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// maxIdempotencyKeyLength 限制 key 长度，避免占用过多存储
+const maxIdempotencyKeyLength = 255
+
+// Idempotency 将 Idempotency-Key 请求头写入请求上下文，由命令总线的幂等中间件去重
+func Idempotency() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := c.Request().Header.Get(IdempotencyKeyHeader)
+			if key == "" {
+				return next(c)
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				return echo.NewHTTPError(http.StatusBadRequest, "Idempotency-Key header is too long")
+			}
+
+			ctx := command.WithIdempotencyKey(c.Request().Context(), key)
+			c.SetRequest(c.Request().WithContext(ctx))
+			c.Response().Header().Set(IdempotencyKeyHeader, key)
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/pkg/actor"
+)
+
+type LoggerMiddleware struct {
+	logger output.Logger
+}
+
+// NewLoggerMiddleware 记录每个请求的方法、路由、状态码和耗时
+func NewLoggerMiddleware(logger output.Logger) echo.MiddlewareFunc {
+	m := &LoggerMiddleware{logger: logger}
+	return m.Handle
+}
+
+func (m *LoggerMiddleware) Handle(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		start := time.Now()
+		err := next(c)
+		if err != nil {
+			// 先交给错误处理器写入响应，才能拿到最终状态码
+			c.Error(err)
+		}
+
+		fields := []interface{}{
+			"method", c.Request().Method,
+			"path", c.Path(),
+			"status", c.Response().Status,
+			"duration_ms", time.Since(start).Milliseconds(),
+			"request_id", c.Response().Header().Get(echo.HeaderXRequestID),
+		}
+		if a, ok := actor.FromContext(c.Request().Context()); ok {
+			fields = append(fields, "actor_id", a.RealActorID())
+		}
+		m.logger.Info("http request", fields...)
+
+		return nil
+	}
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+)
+
+type MetricsMiddleware struct {
+	metrics output.MetricsReporter
+}
+
+// NewMetricsMiddleware 按路由和状态码统计请求数和耗时，使用路由模板避免路径参数造成标签爆炸
+func NewMetricsMiddleware(metrics output.MetricsReporter) echo.MiddlewareFunc {
+	m := &MetricsMiddleware{metrics: metrics}
+	return m.Handle
+}
+
+func (m *MetricsMiddleware) Handle(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		timer := m.metrics.StartTimer("http_request_duration", "method", c.Request().Method, "route", c.Path())
+		defer timer.Stop()
+
+		err := next(c)
+		if err != nil {
+			c.Error(err)
+		}
+
+		m.metrics.IncrementCounter("http_requests_total",
+			"method", c.Request().Method,
+			"route", c.Path(),
+			"status", strconv.Itoa(c.Response().Status),
+		)
+		return nil
+	}
+}
@@ -4,14 +4,16 @@ import (
 	"fmt"
 	"runtime"
 	"github.com/labstack/echo/v4"
+
+	"github.com/gohex/gohex/internal/application/port/output"
 )
 
 type RecoveryMiddleware struct {
-	logger  Logger
-	metrics MetricsReporter
+	logger  output.Logger
+	metrics output.MetricsReporter
 }
 
-func NewRecoveryMiddleware(logger Logger, metrics MetricsReporter) echo.MiddlewareFunc {
+func NewRecoveryMiddleware(logger output.Logger, metrics output.MetricsReporter) echo.MiddlewareFunc {
 	r := &RecoveryMiddleware{
 		logger:  logger,
 		metrics: metrics,
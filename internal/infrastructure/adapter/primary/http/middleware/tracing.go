@@ -1,68 +1,39 @@
 package middleware
 
 import (
-	"github.com/gohex/gohex/pkg/tracer"
-	"github.com/gohex/gohex/pkg/logger"
-	"github.com/gohex/gohex/pkg/metrics"
+	"strconv"
+
 	"github.com/labstack/echo/v4"
-	"github.com/opentracing/opentracing-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"github.com/gohex/gohex/pkg/tracer"
 )
 
-type TracingMiddleware struct {
-	tracer  Tracer
-	logger  Logger
-	metrics MetricsReporter
-}
-
-func NewTracingMiddleware(tracer Tracer, logger Logger, metrics MetricsReporter) *TracingMiddleware {
-	return &TracingMiddleware{
-		tracer:  tracer,
-		logger:  logger,
-		metrics: metrics,
-	}
-}
-
-func (m *TracingMiddleware) Handle(next echo.HandlerFunc) echo.HandlerFunc {
-	return func(c echo.Context) error {
-		req := c.Request()
-		
-		// 创建或继承 span
-		spanCtx, span := m.tracer.StartSpan(req.Context(), "http_request",
-			tracer.Tag("http.method", req.Method),
-			tracer.Tag("http.url", req.URL.String()),
-		)
-		defer span.End()
+// Tracing 为每个请求创建 span，并继承上游通过 traceparent 传递的追踪上下文
+func Tracing() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
 
-		// 注入跟踪头
-		m.tracer.Inject(spanCtx, req.Header)
+			// 继承上游追踪上下文
+			ctx := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+			span, ctx := tracer.StartSpan(ctx, "http_request",
+				tracer.Tag("http.method", req.Method),
+				tracer.Tag("http.route", c.Path()),
+			)
+			defer span.End()
 
-		// 设置请求上下文
-		c.SetRequest(req.WithContext(spanCtx))
+			c.SetRequest(req.WithContext(ctx))
 
-		// 执行请求
-		err := next(c)
+			err := next(c)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			span.SetAttributes(tracer.Tag("http.status_code", strconv.Itoa(c.Response().Status)))
 
-		// 记录响应信息
-		if err != nil {
-			span.SetTag("error", true)
-			span.SetTag("error.message", err.Error())
+			return err
 		}
-		span.SetTag("http.status_code", c.Response().Status)
-
-		return err
 	}
 }
-
-func Tracing() echo.MiddlewareFunc {
-	return func(next echo.HandlerFunc) echo.HandlerFunc {
-		return func(c echo.Context) error {
-			span := opentracing.StartSpan(
-				"gohex.http.request",
-				opentracing.Tag{Key: "service", Value: "gohex"},
-			)
-			defer span.Finish()
-			
-			// ...
-		}
-	}
-} 
\ No newline at end of file
@@ -2,17 +2,26 @@ package http
 
 import (
 	"github.com/labstack/echo/v4"
-	"github.com/your-org/your-project/internal/infrastructure/adapter/primary/http/handler"
-	"github.com/your-org/your-project/internal/infrastructure/adapter/primary/http/middleware"
+	"github.com/go-playground/validator/v10"
+	"github.com/gohex/gohex/internal/infrastructure/adapter/primary/http/handler"
+	"github.com/gohex/gohex/internal/infrastructure/adapter/primary/http/middleware"
+	"github.com/gohex/gohex/internal/infrastructure/config"
+	"github.com/gohex/gohex/internal/infrastructure/resilience"
+	cmdbus "github.com/gohex/gohex/internal/application/port/input/command"
+	querybus "github.com/gohex/gohex/internal/application/port/input/query"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/pkg/errors"
 	"net/http"
 	"fmt"
 )
 
 type Router struct {
 	echo    *echo.Echo
-	logger  Logger
-	metrics MetricsReporter
+	logger  output.Logger
+	metrics output.MetricsReporter
 	config  APIConfig
+	// production 生产环境不向客户端返回内部错误详情
+	production bool
 }
 
 type APIConfig struct {
@@ -22,101 +31,114 @@ type APIConfig struct {
 }
 
 func NewRouter(
-	logger Logger,
-	metrics MetricsReporter,
-	commandBus command.Bus,
-	queryBus query.Bus,
-	authService AuthService,
+	cfg *config.Config,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+	commandBus cmdbus.Bus,
+	queryBus querybus.Bus,
+	tokenSvc output.TokenService,
+	breakers *resilience.Registry,
 ) *Router {
 	e := echo.New()
-	
-	// 自定义错误处理
-	e.HTTPErrorHandler = func(err error, c echo.Context) {
-		var (
-			code = http.StatusInternalServerError
-			msg  interface{} = "Internal Server Error"
-		)
-
-		if he, ok := err.(*echo.HTTPError); ok {
-			code = he.Code
-			msg = he.Message
-		} else if ae, ok := err.(*errors.AppError); ok {
-			code = ae.HTTPStatusCode()
-			msg = ae.Error()
-		}
-
-		// 记录错误
-		if code >= 500 {
-			logger.Error("request failed", 
-				"path", c.Request().URL.Path,
-				"error", err,
-			)
-		}
-
-		// 不在生产环境暴露内部错误
-		if code == http.StatusInternalServerError && config.IsProduction() {
-			msg = "Internal Server Error"
-		}
-
-		if !c.Response().Committed {
-			if c.Request().Method == http.MethodHead {
-				c.NoContent(code)
-			} else {
-				c.JSON(code, map[string]interface{}{
-					"error": msg,
-				})
-			}
-		}
+	r := &Router{
+		echo:       e,
+		logger:     logger,
+		metrics:    metrics,
+		config:     APIConfig{Version: cfg.App.Version},
+		production: cfg.App.Environment == "production",
 	}
+
+	// 自定义错误处理
+	e.HTTPErrorHandler = r.errorHandler
 	
 	// API 版本
-	v1 := e.Group("/api/v1")
-	
-	// API 文档
-	if cfg.Swagger.Enabled {
-		e.GET("/swagger/*", echoSwagger.WrapHandler)
-	}
+	v1 := e.Group("/api/v1", r.addVersionHeaders)
 	
-	// 健康检查
+	// 健康检查，有依赖熔断时服务仍可用，状态为 degraded
 	e.GET("/health", func(c echo.Context) error {
-		return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+		status := "ok"
+		if breakers.Degraded() {
+			status = "degraded"
+		}
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"status":   status,
+			"circuits": breakers.Snapshot(),
+		})
 	})
 	
 	// 全局中间件
 	e.Use(middleware.NewRecoveryMiddleware(logger, metrics))
+	e.Use(middleware.Correlation())
 	e.Use(middleware.NewLoggerMiddleware(logger))
 	e.Use(middleware.NewMetricsMiddleware(metrics))
-	e.Use(middleware.NewTracingMiddleware())
+	e.Use(middleware.Tracing())
+	e.Use(middleware.Idempotency())
 	
 	// 创建处理器
-	authHandler := handler.NewAuthHandler(commandBus, queryBus, authService, logger)
-	userHandler := handler.NewUserHandler(commandBus, queryBus, logger)
+	authHandler := handler.NewAuthHandler(commandBus, queryBus, logger, metrics)
+	userHandler := handler.NewUserHandler(commandBus, queryBus, logger, metrics)
+	auditHandler := handler.NewAuditHandler(queryBus, cfg.CommandBus.Middleware.Audit.MaxExportRows, logger)
+	debugHandler := handler.NewDebugHandler(commandBus, queryBus)
+	jobHandler := handler.NewJobHandler(commandBus, queryBus, logger)
+	webhookHandler := handler.NewWebhookHandler(commandBus, queryBus, logger)
+	scheduleHandler := handler.NewScheduleHandler(commandBus, queryBus, logger)
 	
 	// 认证路由
 	auth := v1.Group("/auth")
 	{
 		auth.POST("/login", authHandler.Login)
 		auth.POST("/register", authHandler.Register)
-		auth.POST("/refresh", authHandler.RefreshToken)
-		auth.POST("/logout", authHandler.Logout, middleware.RequireAuth(authService))
+		auth.POST("/logout", authHandler.Logout, middleware.RequireAuth(tokenSvc))
+		auth.POST("/impersonation/end", authHandler.EndImpersonation, middleware.RequireAuth(tokenSvc))
+		auth.POST("/email-change/confirm", authHandler.ConfirmEmailChange)
+		auth.POST("/email-change/cancel", authHandler.CancelEmailChange)
+		auth.POST("/verify-email", authHandler.VerifyEmail)
 	}
 	
 	// 用户路由
-	users := v1.Group("/users", middleware.RequireAuth(authService))
+	users := v1.Group("/users", middleware.RequireAuth(tokenSvc))
 	{
 		users.GET("", userHandler.ListUsers)
 		users.GET("/:id", userHandler.GetUser)
-		users.PUT("/:id", userHandler.UpdateUser)
-		users.DELETE("/:id", userHandler.DeleteUser)
-		users.PUT("/:id/status", userHandler.UpdateUserStatus)
-		users.PUT("/:id/password", userHandler.ChangePassword)
+		users.PUT("/:id", userHandler.UpdateProfile)
+		users.DELETE("/:id", userHandler.DeleteUser, middleware.ForbidImpersonation())
+		users.PUT("/:id/status", userHandler.UpdateUserStatus, middleware.ForbidImpersonation())
+		users.PUT("/:id/password", userHandler.ChangePassword, middleware.ForbidImpersonation())
+		users.POST("/:id/email", userHandler.RequestEmailChange, middleware.ForbidImpersonation())
+		users.POST("/:id/impersonate", userHandler.Impersonate, middleware.ForbidImpersonation())
+		users.POST("/:id/exports", userHandler.RequestDataExport, middleware.ForbidImpersonation())
+		users.GET("/:id/exports/:export_id", userHandler.DownloadDataExport, middleware.ForbidImpersonation())
+		users.POST("/:id/erasure", userHandler.EraseUser, middleware.ForbidImpersonation())
 	}
-	
-	return &Router{
-		echo:    e,
-		logger:  logger,
-		metrics: metrics,
+
+	// 异步任务路由
+	jobs := v1.Group("/jobs", middleware.RequireAuth(tokenSvc))
+	{
+		jobs.GET("/:id", jobHandler.GetJob)
+		jobs.POST("/:id/cancel", jobHandler.CancelJob)
 	}
+
+	// 管理路由
+	authMiddleware := middleware.NewAuthMiddleware(queryBus, logger, metrics)
+	admin := v1.Group("/admin", middleware.RequireAuth(tokenSvc), authMiddleware.RequireRoles("admin"))
+	{
+		admin.GET("/audit-logs", auditHandler.ListAuditLogs)
+		admin.GET("/audit-logs/export", auditHandler.ExportAuditLogs)
+		admin.POST("/users/:id/restore", userHandler.RestoreUser)
+		admin.POST("/users/roles", userHandler.BulkAssignRole)
+		admin.GET("/debug/buses", debugHandler.BusRegistry)
+		admin.POST("/webhooks", webhookHandler.CreateSubscription)
+		admin.GET("/webhooks", webhookHandler.ListSubscriptions)
+		admin.GET("/webhooks/:id", webhookHandler.GetSubscription)
+		admin.PUT("/webhooks/:id", webhookHandler.UpdateSubscription)
+		admin.DELETE("/webhooks/:id", webhookHandler.DeleteSubscription)
+		admin.GET("/webhooks/:id/deliveries", webhookHandler.ListDeliveries)
+		admin.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", webhookHandler.Redeliver)
+		admin.GET("/schedules", scheduleHandler.ListSchedules)
+		admin.POST("/schedules/:id/cancel", scheduleHandler.CancelSchedule)
+	}
+	
+	return r
 }
 
 func (r *Router) Handler() http.Handler {
@@ -126,13 +148,13 @@ func (r *Router) Handler() http.Handler {
 func (r *Router) addVersionHeaders(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		// 添加版本头
-		c.Response().Header().Set("API-Version", r.config.API.Version)
+		c.Response().Header().Set("API-Version", r.config.Version)
 		
 		// 如果 API 已弃用，添加相关头
-		if r.config.API.Deprecated {
+		if r.config.Deprecated {
 			c.Response().Header().Set("Deprecation", "true")
-			if r.config.API.SunsetDate != "" {
-				c.Response().Header().Set("Sunset", r.config.API.SunsetDate)
+			if r.config.SunsetDate != "" {
+				c.Response().Header().Set("Sunset", r.config.SunsetDate)
 			}
 		}
 		
@@ -165,6 +187,12 @@ func (r *Router) errorHandler(err error, c echo.Context) {
 		}
 	}
 
+	// 不在生产环境暴露内部错误
+	if code == http.StatusInternalServerError && r.production {
+		msg = "Internal Server Error"
+		details = nil
+	}
+
 	// 记录错误
 	if code >= 500 {
 		r.logger.Error("request failed",
@@ -0,0 +1,68 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	cmdbusimpl "github.com/gohex/gohex/internal/infrastructure/bus/command"
+	querybusimpl "github.com/gohex/gohex/internal/infrastructure/bus/query"
+	"github.com/gohex/gohex/internal/infrastructure/config"
+	"github.com/gohex/gohex/internal/infrastructure/resilience"
+	"github.com/gohex/gohex/internal/testutil"
+)
+
+func TestRouter_ForbidsImpersonationOnSensitiveRoutes(t *testing.T) {
+	logger, metrics := testutil.NopLogger{}, testutil.NewMetrics()
+	tokens := testutil.NewTokenService()
+	tokens.Claims["admin-token"] = &output.TokenClaims{
+		UserID:    "admin",
+		Roles:     []string{"admin"},
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	// 模拟管理员账户时同样拒绝
+	tokens.Claims["impersonation-token"] = &output.TokenClaims{
+		UserID:    "alice",
+		Roles:     []string{"admin"},
+		ExpiresAt: time.Now().Add(time.Hour),
+		Actor:     &output.TokenActor{Subject: "support"},
+	}
+	router := NewRouter(&config.Config{}, logger, metrics,
+		cmdbusimpl.NewCommandBus(logger, metrics, nil, nil),
+		querybusimpl.NewQueryBus(logger, metrics),
+		tokens,
+		resilience.NewRegistry(config.ResilienceConfig{}, logger, metrics))
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{name: "change status", method: http.MethodPut, path: "/api/v1/users/alice/status", body: `{"status":"suspended"}`},
+		{name: "request data export", method: http.MethodPost, path: "/api/v1/users/alice/exports"},
+		{name: "download data export", method: http.MethodGet, path: "/api/v1/users/alice/exports/export-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serve := func(token string) int {
+				req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+				req.Header.Set("Authorization", "Bearer "+token)
+				req.Header.Set("Content-Type", "application/json")
+				rec := httptest.NewRecorder()
+				router.echo.ServeHTTP(rec, req)
+				return rec.Code
+			}
+
+			assert.Equal(t, http.StatusForbidden, serve("impersonation-token"))
+			// 非模拟会话照常进入处理器
+			assert.NotEqual(t, http.StatusForbidden, serve("admin-token"))
+		})
+	}
+}
@@ -6,17 +6,19 @@ import (
 	"net/http"
 	"sync"
 	"time"
+
+	"github.com/gohex/gohex/internal/application/port/output"
 )
 
 type Server struct {
 	router  *Router
 	server  *http.Server
-	logger  Logger
-	metrics MetricsReporter
-	db      Database
-	cache   Cache
-	mq      MessageQueue
+	logger  output.Logger
+	metrics output.MetricsReporter
+	checks  []Check
 	config  ServerConfig
+	// stopHealth 停止后台健康检查
+	stopHealth context.CancelFunc
 }
 
 type ServerConfig struct {
@@ -37,8 +39,8 @@ type HealthCheckConfig struct {
 
 type healthChecker struct {
 	config   HealthCheckConfig
-	logger   Logger
-	metrics  MetricsReporter
+	logger   output.Logger
+	metrics  output.MetricsReporter
 	checks   []Check
 	failures int
 	mu       sync.Mutex
@@ -53,11 +55,9 @@ type Check struct {
 func NewServer(
 	cfg ServerConfig,
 	router *Router,
-	logger Logger,
-	metrics MetricsReporter,
-	db Database,
-	cache Cache,
-	mq MessageQueue,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+	checks ...Check,
 ) *Server {
 	return &Server{
 		router: router,
@@ -70,9 +70,7 @@ func NewServer(
 		},
 		logger:  logger,
 		metrics: metrics,
-		db:      db,
-		cache:   cache,
-		mq:      mq,
+		checks:  checks,
 		config:  cfg,
 	}
 }
@@ -84,7 +82,9 @@ func (s *Server) Start() error {
 	s.metrics.IncrementCounter("http_server_start")
 	
 	// 启动健康检查
-	go s.startHealthCheck()
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stopHealth = cancel
+	go s.startHealthCheck(ctx)
 	
 	if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 		s.logger.Error("failed to start server", "error", err)
@@ -100,6 +100,9 @@ func (s *Server) Stop(ctx context.Context) error {
 	
 	// 记录关闭指标
 	s.metrics.IncrementCounter("http_server_stop")
+	if s.stopHealth != nil {
+		s.stopHealth()
+	}
 	
 	if err := s.server.Shutdown(ctx); err != nil {
 		s.logger.Error("failed to stop server", "error", err)
@@ -110,63 +113,37 @@ func (s *Server) Stop(ctx context.Context) error {
 	return nil
 }
 
-func (s *Server) startHealthCheck() {
-	if !s.config.Health.Enabled {
+func (s *Server) startHealthCheck(ctx context.Context) {
+	if !s.config.Health.Enabled || len(s.checks) == 0 {
 		return
 	}
 
 	// 等待初始延迟
-	time.Sleep(s.config.Health.InitialDelay)
+	select {
+	case <-time.After(s.config.Health.InitialDelay):
+	case <-ctx.Done():
+		return
+	}
 
 	checker := &healthChecker{
 		config:  s.config.Health,
 		logger:  s.logger,
 		metrics: s.metrics,
-		checks: []Check{
-			{Name: "database", Check: s.db.Ping, Required: true},
-			{Name: "cache", Check: s.cache.Ping, Required: false},
-			{Name: "mq", Check: s.mq.Ping, Required: false},
-		},
+		checks:  s.checks,
 	}
 
-	go checker.start()
+	checker.start(ctx)
 }
 
-func (s *Server) checkHealth() error {
-	// 添加上下文和超时控制
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	// 检查数据库连接
-	if err := s.db.PingContext(ctx); err != nil {
-		return fmt.Errorf("database check failed: %w", err)
-	}
-
-	// 检查缓存连接
-	if err := s.cache.Ping(ctx); err != nil {
-		return fmt.Errorf("cache check failed: %w", err)
-	}
-
-	// 检查消息队列连接
-	if err := s.mq.Ping(ctx); err != nil {
-		return fmt.Errorf("message queue check failed: %w", err)
-	}
-
-	return nil
-}
-
-func (h *healthChecker) start() {
+func (h *healthChecker) start(ctx context.Context) {
 	ticker := time.NewTicker(h.config.Interval)
 	defer ticker.Stop()
 
-	done := make(chan struct{})
-	defer close(done)
-
 	for {
 		select {
 		case <-ticker.C:
 			h.runChecks()
-		case <-done:
+		case <-ctx.Done():
 			return
 		}
 	}
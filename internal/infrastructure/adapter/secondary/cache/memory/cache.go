@@ -0,0 +1,313 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+)
+
+type entry struct {
+	value interface{}
+	// raw 通过 SetRaw 写入的已编码数据
+	raw       []byte
+	tags      []string
+	expiresAt time.Time
+}
+
+func (e *entry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && now.After(e.expiresAt)
+}
+
+// cache 进程内缓存，实现与 Redis 适配器相同的接口，适用于测试和单实例部署。
+// 值按写入时的原样保存，不做序列化
+type cache struct {
+	mu              sync.RWMutex
+	items           map[string]*entry
+	tags            map[string]map[string]struct{}
+	cleanupInterval time.Duration
+	lastCleanup     time.Time
+	logger          output.Logger
+	metrics         output.MetricsReporter
+}
+
+// NewCache 过期条目在读取时删除，并在写入时按 cleanupInterval 批量清理
+func NewCache(cleanupInterval time.Duration, logger output.Logger, metrics output.MetricsReporter) output.TaggedCache {
+	if cleanupInterval <= 0 {
+		cleanupInterval = time.Minute
+	}
+	return &cache{
+		items:           make(map[string]*entry),
+		tags:            make(map[string]map[string]struct{}),
+		cleanupInterval: cleanupInterval,
+		lastCleanup:     time.Now(),
+		logger:          logger,
+		metrics:         metrics,
+	}
+}
+
+func (c *cache) Get(ctx context.Context, key string) (interface{}, error) {
+	e, ok := c.lookup(key)
+	if !ok {
+		c.metrics.IncrementCounter("cache_miss")
+		return nil, nil
+	}
+
+	c.metrics.IncrementCounter("cache_hit")
+	if e.raw != nil {
+		var value interface{}
+		if err := json.Unmarshal(e.raw, &value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+	return e.value, nil
+}
+
+func (c *cache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	c.store(key, &entry{value: value}, ttl)
+	return nil
+}
+
+func (c *cache) SetWithTags(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) error {
+	c.store(key, &entry{value: value, tags: tags}, ttl)
+	return nil
+}
+
+func (c *cache) GetRaw(ctx context.Context, key string) ([]byte, error) {
+	e, ok := c.lookup(key)
+	if !ok {
+		c.metrics.IncrementCounter("cache_miss")
+		return nil, nil
+	}
+
+	c.metrics.IncrementCounter("cache_hit")
+	if e.raw != nil {
+		return e.raw, nil
+	}
+	return json.Marshal(e.value)
+}
+
+func (c *cache) SetRaw(ctx context.Context, key string, data []byte, ttl time.Duration, tags []string) error {
+	c.store(key, &entry{raw: data, tags: tags}, ttl)
+	return nil
+}
+
+func (c *cache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.remove(key)
+	return nil
+}
+
+func (c *cache) Increment(ctx context.Context, key string, value int64) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var current int64
+	e, ok := c.items[key]
+	if ok && !e.expired(time.Now()) {
+		n, err := toInt64(e)
+		if err != nil {
+			return 0, fmt.Errorf("cache value %s is not an integer: %w", key, err)
+		}
+		current = n
+	} else {
+		// 过期条目可能仍在标签索引中，先删除
+		c.remove(key)
+		e = &entry{}
+		c.items[key] = e
+	}
+
+	current += value
+	e.value = current
+	e.raw = nil
+	return current, nil
+}
+
+func (c *cache) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[key]
+	if !ok || e.expired(time.Now()) {
+		return nil
+	}
+	if ttl <= 0 {
+		c.remove(key)
+		return nil
+	}
+	e.expiresAt = time.Now().Add(ttl)
+	return nil
+}
+
+func (c *cache) GetMulti(ctx context.Context, keys []string) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(keys))
+	for _, key := range keys {
+		value, err := c.Get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if value != nil {
+			result[key] = value
+		}
+	}
+	return result, nil
+}
+
+func (c *cache) SetMulti(ctx context.Context, items map[string]interface{}, ttl time.Duration) error {
+	for key, value := range items {
+		c.store(key, &entry{value: value}, ttl)
+	}
+	return nil
+}
+
+func (c *cache) DeleteMulti(ctx context.Context, keys []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		c.remove(key)
+	}
+	return nil
+}
+
+func (c *cache) Clear(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = make(map[string]*entry)
+	c.tags = make(map[string]map[string]struct{})
+	return nil
+}
+
+// Keys 支持 Redis 风格的 * ? [] 通配符
+func (c *cache) Keys(ctx context.Context, pattern string) ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := time.Now()
+	var keys []string
+	for key, e := range c.items {
+		if e.expired(now) {
+			continue
+		}
+		matched, err := path.Match(pattern, key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key pattern %q: %w", pattern, err)
+		}
+		if matched {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (c *cache) InvalidateTags(ctx context.Context, tags ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, tag := range tags {
+		for key := range c.tags[tag] {
+			c.remove(key)
+		}
+		delete(c.tags, tag)
+	}
+	c.metrics.IncrementCounter("cache_tag_invalidated")
+	return nil
+}
+
+func (c *cache) lookup(key string) (*entry, bool) {
+	c.mu.RLock()
+	e, ok := c.items[key]
+	c.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	if e.expired(time.Now()) {
+		c.mu.Lock()
+		// 加写锁期间可能已被重新写入
+		if current, ok := c.items[key]; ok && current == e {
+			c.remove(key)
+		}
+		c.mu.Unlock()
+		return nil, false
+	}
+	return e, true
+}
+
+func (c *cache) store(key string, e *entry, ttl time.Duration) {
+	now := time.Now()
+	if ttl > 0 {
+		e.expiresAt = now.Add(ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.remove(key)
+	c.items[key] = e
+	for _, tag := range e.tags {
+		keys, ok := c.tags[tag]
+		if !ok {
+			keys = make(map[string]struct{})
+			c.tags[tag] = keys
+		}
+		keys[key] = struct{}{}
+	}
+
+	if now.Sub(c.lastCleanup) >= c.cleanupInterval {
+		c.cleanup(now)
+	}
+}
+
+// cleanup 删除所有过期条目，调用方需持有写锁
+func (c *cache) cleanup(now time.Time) {
+	for key, e := range c.items {
+		if e.expired(now) {
+			c.remove(key)
+		}
+	}
+	c.lastCleanup = now
+	c.metrics.Gauge("memory_cache_entries", float64(len(c.items)))
+}
+
+// remove 删除条目及其标签索引，调用方需持有写锁
+func (c *cache) remove(key string) {
+	e, ok := c.items[key]
+	if !ok {
+		return
+	}
+	delete(c.items, key)
+	for _, tag := range e.tags {
+		if keys, ok := c.tags[tag]; ok {
+			delete(keys, key)
+			if len(keys) == 0 {
+				delete(c.tags, tag)
+			}
+		}
+	}
+}
+
+func toInt64(e *entry) (int64, error) {
+	if e.raw != nil {
+		var n int64
+		err := json.Unmarshal(e.raw, &n)
+		return n, err
+	}
+	switch v := e.value.(type) {
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	case float64:
+		return int64(v), nil
+	default:
+		return 0, fmt.Errorf("unexpected type %T", e.value)
+	}
+}
@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/testutil"
+)
+
+func newTestCache() *cache {
+	return NewCache(time.Minute, testutil.NopLogger{}, testutil.NewMetrics()).(*cache)
+}
+
+func TestCache_Expiry(t *testing.T) {
+	ctx := context.Background()
+	c := newTestCache()
+	require.NoError(t, c.Set(ctx, "short", "v", 5*time.Millisecond))
+	require.NoError(t, c.Set(ctx, "forever", "v", 0))
+
+	time.Sleep(10 * time.Millisecond)
+
+	value, err := c.Get(ctx, "short")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+	value, err = c.Get(ctx, "forever")
+	require.NoError(t, err)
+	assert.Equal(t, "v", value)
+
+	require.NoError(t, c.Expire(ctx, "forever", 0))
+	value, err = c.Get(ctx, "forever")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+}
+
+func TestCache_Increment(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(c *cache)
+		want    int64
+		wantErr bool
+	}{
+		{name: "missing key starts at zero", want: 3},
+		{name: "adds to stored integer", setup: func(c *cache) { _ = c.Set(context.Background(), "n", 4, 0) }, want: 7},
+		{name: "adds to raw integer", setup: func(c *cache) { _ = c.SetRaw(context.Background(), "n", []byte("10"), 0, nil) }, want: 13},
+		{name: "rejects non integer", setup: func(c *cache) { _ = c.Set(context.Background(), "n", "abc", 0) }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestCache()
+			if tt.setup != nil {
+				tt.setup(c)
+			}
+
+			n, err := c.Increment(context.Background(), "n", 3)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, n)
+		})
+	}
+}
+
+func TestCache_IncrementAfterExpiryDropsTags(t *testing.T) {
+	ctx := context.Background()
+	c := newTestCache()
+	require.NoError(t, c.SetWithTags(ctx, "n", 1, 5*time.Millisecond, []string{"t"}))
+	time.Sleep(10 * time.Millisecond)
+
+	_, err := c.Increment(ctx, "n", 1)
+	require.NoError(t, err)
+	require.NoError(t, c.InvalidateTags(ctx, "t"))
+
+	// 计数器不再属于过期条目的标签
+	value, err := c.Get(ctx, "n")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), value)
+}
+
+func TestCache_KeysAndMulti(t *testing.T) {
+	ctx := context.Background()
+	c := newTestCache()
+	require.NoError(t, c.SetMulti(ctx, map[string]interface{}{"user:1": 1, "user:2": 2, "role:1": 3}, time.Minute))
+
+	keys, err := c.Keys(ctx, "user:*")
+	require.NoError(t, err)
+	sort.Strings(keys)
+	assert.Equal(t, []string{"user:1", "user:2"}, keys)
+	_, err = c.Keys(ctx, "[")
+	assert.Error(t, err)
+
+	require.NoError(t, c.DeleteMulti(ctx, []string{"user:1"}))
+	values, err := c.GetMulti(ctx, []string{"user:1", "user:2"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"user:2": 2}, values)
+
+	require.NoError(t, c.Clear(ctx))
+	keys, err = c.Keys(ctx, "*")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
+
+func TestCache_InvalidateTags(t *testing.T) {
+	ctx := context.Background()
+	c := newTestCache()
+	require.NoError(t, c.SetWithTags(ctx, "a", 1, time.Minute, []string{"user:alice", "users:list"}))
+	require.NoError(t, c.SetRaw(ctx, "b", []byte(`"b"`), time.Minute, []string{"users:list"}))
+	require.NoError(t, c.SetWithTags(ctx, "c", 3, time.Minute, []string{"user:bob"}))
+
+	require.NoError(t, c.InvalidateTags(ctx, "users:list"))
+
+	values, err := c.GetMulti(ctx, []string{"a", "b", "c"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"c": 3}, values)
+	assert.Empty(t, c.tags["user:alice"])
+}
@@ -0,0 +1,276 @@
+package multitier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/infrastructure/config"
+)
+
+// invalidation 实例间广播的失效消息
+type invalidation struct {
+	Origin string   `json:"origin"`
+	Keys   []string `json:"keys,omitempty"`
+	Tags   []string `json:"tags,omitempty"`
+	All    bool     `json:"all,omitempty"`
+}
+
+// Cache 两级缓存：进程内 LRU 为 L1，Redis 为 L2。
+// 写入和失效先作用于 L2，再清理本实例 L1 并通过 Redis 发布订阅通知其他实例
+type Cache struct {
+	l2         output.TaggedCache
+	l1         *lru
+	enabled    bool
+	ttl        time.Duration
+	client     redis.UniversalClient
+	channel    string
+	instanceID string
+	logger     output.Logger
+	metrics    output.MetricsReporter
+}
+
+func NewCache(
+	l2 output.TaggedCache,
+	client redis.UniversalClient,
+	cfg config.LocalCacheConfig,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *Cache {
+	if cfg.MaxEntries <= 0 {
+		cfg.MaxEntries = 10000
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = time.Minute
+	}
+	if cfg.InvalidationChannel == "" {
+		cfg.InvalidationChannel = "cache:invalidate"
+	}
+	return &Cache{
+		l2:         l2,
+		l1:         newLRU(cfg.MaxEntries),
+		enabled:    cfg.Enabled,
+		ttl:        cfg.TTL,
+		client:     client,
+		channel:    cfg.InvalidationChannel,
+		instanceID: uuid.New().String(),
+		logger:     logger,
+		metrics:    metrics,
+	}
+}
+
+// Start 订阅其他实例的失效消息，直到 ctx 取消
+func (c *Cache) Start(ctx context.Context) {
+	if !c.enabled {
+		return
+	}
+
+	pubsub := c.client.Subscribe(ctx, c.channel)
+	go func() {
+		defer pubsub.Close()
+
+		ch := pubsub.Channel()
+		for {
+			select {
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				c.apply(msg.Payload)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (c *Cache) apply(payload string) {
+	var msg invalidation
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+		c.logger.Error("invalid cache invalidation message", "error", err)
+		return
+	}
+	if msg.Origin == c.instanceID {
+		return
+	}
+
+	switch {
+	case msg.All:
+		c.l1.clear()
+	default:
+		c.l1.delete(msg.Keys...)
+		c.l1.deleteTags(msg.Tags...)
+	}
+	c.metrics.IncrementCounter("l1_cache_remote_invalidation")
+}
+
+// broadcast 通知其他实例清理 L1，发布失败时其他实例最多在 L1 ttl 内读到旧值
+func (c *Cache) broadcast(ctx context.Context, msg invalidation) {
+	if !c.enabled {
+		return
+	}
+
+	msg.Origin = c.instanceID
+	data, err := json.Marshal(msg)
+	if err != nil {
+		c.logger.Error("failed to encode cache invalidation", "error", err)
+		return
+	}
+	if err := c.client.Publish(ctx, c.channel, data).Err(); err != nil {
+		c.logger.Error("failed to publish cache invalidation", "error", err)
+		c.metrics.IncrementCounter("l1_cache_publish_failure")
+	}
+}
+
+func (c *Cache) localTTL(ttl time.Duration) time.Duration {
+	if ttl <= 0 || ttl > c.ttl {
+		return c.ttl
+	}
+	return ttl
+}
+
+// Get L1 未命中时读取 L2；L2 的值没有标签信息，不回填 L1
+func (c *Cache) Get(ctx context.Context, key string) (interface{}, error) {
+	if v, ok := c.GetLocal(key); ok {
+		return v, nil
+	}
+	return c.l2.Get(ctx, key)
+}
+
+func (c *Cache) GetLocal(key string) (interface{}, bool) {
+	if !c.enabled {
+		return nil, false
+	}
+
+	v, ok := c.l1.get(key)
+	if ok {
+		c.metrics.IncrementCounter("l1_cache_hit")
+	} else {
+		c.metrics.IncrementCounter("l1_cache_miss")
+	}
+	return v, ok
+}
+
+func (c *Cache) SetLocal(key string, value interface{}, ttl time.Duration, tags []string) {
+	if !c.enabled {
+		return
+	}
+	c.l1.set(key, value, c.localTTL(ttl), tags)
+	c.metrics.Gauge("l1_cache_entries", float64(c.l1.len()))
+}
+
+func (c *Cache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	if err := c.l2.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+	c.l1.delete(key)
+	c.broadcast(ctx, invalidation{Keys: []string{key}})
+	return nil
+}
+
+// SetWithTags 值带有标签，可以安全地以原始类型写入 L1
+func (c *Cache) SetWithTags(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) error {
+	if err := c.l2.SetWithTags(ctx, key, value, ttl, tags); err != nil {
+		return err
+	}
+	c.broadcast(ctx, invalidation{Keys: []string{key}})
+	if len(tags) > 0 {
+		c.SetLocal(key, value, ttl, tags)
+	} else {
+		c.l1.delete(key)
+	}
+	return nil
+}
+
+func (c *Cache) GetRaw(ctx context.Context, key string) ([]byte, error) {
+	rc, ok := c.l2.(output.RawCache)
+	if !ok {
+		return nil, fmt.Errorf("cache %T does not support raw access", c.l2)
+	}
+	return rc.GetRaw(ctx, key)
+}
+
+// SetRaw 只写 L2，L1 由强类型缓存以解码后的值回填
+func (c *Cache) SetRaw(ctx context.Context, key string, data []byte, ttl time.Duration, tags []string) error {
+	rc, ok := c.l2.(output.RawCache)
+	if !ok {
+		return fmt.Errorf("cache %T does not support raw access", c.l2)
+	}
+	if err := rc.SetRaw(ctx, key, data, ttl, tags); err != nil {
+		return err
+	}
+	c.l1.delete(key)
+	c.broadcast(ctx, invalidation{Keys: []string{key}})
+	return nil
+}
+
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	c.l1.delete(key)
+	if err := c.l2.Delete(ctx, key); err != nil {
+		return err
+	}
+	c.broadcast(ctx, invalidation{Keys: []string{key}})
+	return nil
+}
+
+func (c *Cache) DeleteMulti(ctx context.Context, keys []string) error {
+	c.l1.delete(keys...)
+	if err := c.l2.DeleteMulti(ctx, keys); err != nil {
+		return err
+	}
+	c.broadcast(ctx, invalidation{Keys: keys})
+	return nil
+}
+
+func (c *Cache) InvalidateTags(ctx context.Context, tags ...string) error {
+	c.l1.deleteTags(tags...)
+	if err := c.l2.InvalidateTags(ctx, tags...); err != nil {
+		return err
+	}
+	c.broadcast(ctx, invalidation{Tags: tags})
+	return nil
+}
+
+func (c *Cache) Clear(ctx context.Context) error {
+	c.l1.clear()
+	if err := c.l2.Clear(ctx); err != nil {
+		return err
+	}
+	c.broadcast(ctx, invalidation{All: true})
+	return nil
+}
+
+func (c *Cache) SetMulti(ctx context.Context, items map[string]interface{}, ttl time.Duration) error {
+	if err := c.l2.SetMulti(ctx, items, ttl); err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	c.l1.delete(keys...)
+	c.broadcast(ctx, invalidation{Keys: keys})
+	return nil
+}
+
+// GetMulti、Increment、Expire、Keys 直接读写 L2
+
+func (c *Cache) GetMulti(ctx context.Context, keys []string) (map[string]interface{}, error) {
+	return c.l2.GetMulti(ctx, keys)
+}
+
+func (c *Cache) Increment(ctx context.Context, key string, value int64) (int64, error) {
+	return c.l2.Increment(ctx, key, value)
+}
+
+func (c *Cache) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return c.l2.Expire(ctx, key, ttl)
+}
+
+func (c *Cache) Keys(ctx context.Context, pattern string) ([]string, error) {
+	return c.l2.Keys(ctx, pattern)
+}
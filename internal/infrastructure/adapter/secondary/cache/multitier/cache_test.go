@@ -0,0 +1,144 @@
+package multitier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/dto"
+	querybus "github.com/gohex/gohex/internal/application/port/input/query"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/application/query"
+	"github.com/gohex/gohex/internal/domain/vo"
+	rediscache "github.com/gohex/gohex/internal/infrastructure/adapter/secondary/cache/redis"
+	querybusimpl "github.com/gohex/gohex/internal/infrastructure/bus/query"
+	"github.com/gohex/gohex/internal/infrastructure/config"
+	"github.com/gohex/gohex/internal/testutil"
+)
+
+// newInstances 创建共享同一 Redis 的多个两级缓存实例，模拟多实例部署
+func newInstances(t *testing.T, n int) []*Cache {
+	t.Helper()
+	server := miniredis.RunT(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	caches := make([]*Cache, n)
+	for i := range caches {
+		client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+		t.Cleanup(func() { _ = client.Close() })
+		l2 := rediscache.NewRedisCache(client, "test", testutil.NopLogger{}, testutil.NewMetrics())
+		caches[i] = NewCache(l2, client, config.LocalCacheConfig{Enabled: true, TTL: time.Minute},
+			testutil.NopLogger{}, testutil.NewMetrics())
+		caches[i].Start(ctx)
+	}
+	// 等待订阅建立
+	time.Sleep(50 * time.Millisecond)
+	return caches
+}
+
+func TestCache_ServesTypedValuesFromL1(t *testing.T) {
+	ctx := context.Background()
+	cache := newInstances(t, 1)[0]
+	users := output.NewTypedCache[*dto.UserDTO](cache, nil)
+	tags := []string{output.UserCacheTag("alice")}
+
+	require.NoError(t, users.Set(ctx, "user:id:alice", &dto.UserDTO{ID: "alice"}, time.Minute, tags...))
+
+	v, ok := cache.GetLocal("user:id:alice")
+	require.True(t, ok)
+	assert.IsType(t, &dto.UserDTO{}, v)
+}
+
+func TestCache_InvalidatesOtherInstances(t *testing.T) {
+	tests := []struct {
+		name       string
+		invalidate func(ctx context.Context, c *Cache) error
+	}{
+		{name: "tag", invalidate: func(ctx context.Context, c *Cache) error {
+			return c.InvalidateTags(ctx, output.UserCacheTag("alice"))
+		}},
+		{name: "key", invalidate: func(ctx context.Context, c *Cache) error { return c.Delete(ctx, "user:id:alice") }},
+		{name: "clear", invalidate: func(ctx context.Context, c *Cache) error { return c.Clear(ctx) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			caches := newInstances(t, 2)
+			writer, reader := caches[0], caches[1]
+			tags := []string{output.UserCacheTag("alice")}
+
+			require.NoError(t, output.NewTypedCache[*dto.UserDTO](writer, nil).
+				Set(ctx, "user:id:alice", &dto.UserDTO{ID: "alice"}, time.Minute, tags...))
+			// 另一实例从 L2 解码后回填 L1
+			_, err := output.NewTypedCache[*dto.UserDTO](reader, nil).GetOrLoad(ctx, "user:id:alice", time.Minute, tags,
+				func(ctx context.Context) (*dto.UserDTO, error) {
+					t.Fatal("value should come from L2")
+					return nil, nil
+				})
+			require.NoError(t, err)
+			_, ok := reader.GetLocal("user:id:alice")
+			require.True(t, ok)
+
+			require.NoError(t, tt.invalidate(ctx, writer))
+
+			require.Eventually(t, func() bool {
+				_, ok := reader.GetLocal("user:id:alice")
+				return !ok
+			}, time.Second, 10*time.Millisecond)
+			value, err := reader.Get(ctx, "user:id:alice")
+			require.NoError(t, err)
+			assert.Nil(t, value)
+		})
+	}
+}
+
+func TestCache_QueryBusDecodesHandlerCachedValuesFromL2(t *testing.T) {
+	ctx := context.Background()
+	cache := newInstances(t, 1)[0]
+	logger, metrics := testutil.NopLogger{}, testutil.NewMetrics()
+	repo := testutil.NewUserRepository(testutil.NewUser("alice", vo.StatusActive))
+
+	bus := querybusimpl.NewQueryBus(logger, metrics, querybus.NewCacheMiddleware(cache, logger, metrics))
+	querybus.RegisterHandler[*query.GetUserByIDQuery, *dto.UserDTO](bus,
+		query.NewGetUserByIDHandler(repo, cache, logger, metrics))
+
+	q := &query.GetUserByIDQuery{ID: "alice"}
+	_, err := querybus.Execute[*query.GetUserByIDQuery, *dto.UserDTO](ctx, bus, q)
+	require.NoError(t, err)
+
+	// 清空 L1 并删除仓储中的用户，第二次查询只能从 L2 解码
+	cache.l1.delete(q.CacheKey())
+	require.NoError(t, repo.Delete(ctx, "alice"))
+
+	user, err := querybus.Execute[*query.GetUserByIDQuery, *dto.UserDTO](ctx, bus, q)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", user.ID)
+}
+
+func TestLRU(t *testing.T) {
+	c := newLRU(2)
+	c.set("a", 1, time.Minute, []string{"t1"})
+	c.set("b", 2, time.Minute, []string{"t2"})
+	_, _ = c.get("a")
+	c.set("c", 3, time.Minute, []string{"t1"})
+
+	// b 最久未使用，被淘汰
+	_, ok := c.get("b")
+	assert.False(t, ok)
+	assert.Equal(t, 2, c.len())
+
+	c.deleteTags("t1")
+	assert.Equal(t, 0, c.len())
+
+	c.set("d", 4, time.Millisecond, nil)
+	time.Sleep(5 * time.Millisecond)
+	_, ok = c.get("d")
+	assert.False(t, ok)
+}
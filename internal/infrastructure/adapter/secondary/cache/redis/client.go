@@ -0,0 +1,39 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/gohex/gohex/internal/infrastructure/config"
+)
+
+// NewClient 按部署模式创建单机、哨兵或集群客户端，并检查连接
+func NewClient(cfg config.RedisConfig) (redis.UniversalClient, error) {
+	opts := &redis.UniversalOptions{
+		Addrs:            cfg.Addresses(),
+		Password:         cfg.Password,
+		DB:               cfg.DB,
+		PoolSize:         cfg.PoolSize,
+		SentinelPassword: cfg.SentinelPassword,
+	}
+
+	var client redis.UniversalClient
+	switch cfg.Mode {
+	case config.RedisModeSentinel:
+		opts.MasterName = cfg.MasterName
+		client = redis.NewFailoverClient(opts.Failover())
+	case config.RedisModeCluster:
+		client = redis.NewClusterClient(opts.Cluster())
+	default:
+		client = redis.NewClient(opts.Simple())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return client, nil
+}
@@ -0,0 +1,28 @@
+package redis
+
+import (
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/infrastructure/config"
+)
+
+func TestNewClient(t *testing.T) {
+	server := miniredis.RunT(t)
+	cfg := config.RedisConfig{Addrs: []string{server.Addr()}}
+
+	client, err := NewClient(cfg)
+	require.NoError(t, err)
+	defer client.Close()
+	assert.IsType(t, &redis.Client{}, client)
+
+	// 连接失败时不返回客户端
+	server.Close()
+	client, err = NewClient(cfg)
+	assert.Error(t, err)
+	assert.Nil(t, client)
+}
@@ -0,0 +1,103 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/gohex/gohex/internal/application/port/output"
+)
+
+const idempotencyKeyPrefix = "idempotency:"
+
+type idempotencyStore struct {
+	client  redis.UniversalClient
+	logger  output.Logger
+	metrics output.MetricsReporter
+}
+
+func NewIdempotencyStore(client redis.UniversalClient, logger output.Logger, metrics output.MetricsReporter) output.IdempotencyStore {
+	return &idempotencyStore{
+		client:  client,
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+func (s *idempotencyStore) Acquire(ctx context.Context, key, fingerprint string, lockTTL time.Duration) (*output.IdempotencyRecord, bool, error) {
+	record := &output.IdempotencyRecord{
+		Key:         key,
+		Fingerprint: fingerprint,
+		Status:      output.IdempotencyInProgress,
+		CreatedAt:   time.Now(),
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// SET NX 保证并发的重复请求只有一个能占用 key，锁到期后自动释放
+	acquired, err := s.client.SetNX(ctx, idempotencyKeyPrefix+key, data, lockTTL).Result()
+	if err != nil {
+		s.metrics.IncrementCounter("idempotency_store_error")
+		return nil, false, err
+	}
+	if acquired {
+		return record, true, nil
+	}
+
+	existing, err := s.get(ctx, key)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing == nil {
+		// 记录恰好在两次调用之间过期，按新请求重试一次
+		return s.Acquire(ctx, key, fingerprint, lockTTL)
+	}
+	return existing, false, nil
+}
+
+// Extend 只续期仍存在的 key，锁已到期时不重新创建
+func (s *idempotencyStore) Extend(ctx context.Context, key string, lockTTL time.Duration) error {
+	return s.client.PExpire(ctx, idempotencyKeyPrefix+key, lockTTL).Err()
+}
+
+func (s *idempotencyStore) Complete(ctx context.Context, key string, result []byte, ttl time.Duration) error {
+	record, err := s.get(ctx, key)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		s.logger.Warn("idempotency lock expired before completion", "key", key)
+		return nil
+	}
+
+	record.Status = output.IdempotencyCompleted
+	record.Result = result
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return s.client.SetXX(ctx, idempotencyKeyPrefix+key, data, ttl).Err()
+}
+
+func (s *idempotencyStore) Release(ctx context.Context, key string) error {
+	return s.client.Del(ctx, idempotencyKeyPrefix+key).Err()
+}
+
+func (s *idempotencyStore) get(ctx context.Context, key string) (*output.IdempotencyRecord, error) {
+	data, err := s.client.Get(ctx, idempotencyKeyPrefix+key).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var record output.IdempotencyRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
@@ -0,0 +1,43 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// GetRaw 返回未解码的缓存数据，未命中时返回 nil
+func (c *redisCache) GetRaw(ctx context.Context, key string) ([]byte, error) {
+	timer := c.metrics.StartTimer("redis_get_duration")
+	defer timer.Stop()
+
+	data, err := c.client.Get(ctx, c.key(key)).Bytes()
+	if err == redis.Nil {
+		c.metrics.IncrementCounter("cache_miss")
+		return nil, nil
+	}
+	if err != nil {
+		c.logger.Error("redis get failed", "key", key, "error", err)
+		c.metrics.IncrementCounter("cache_error")
+		return nil, err
+	}
+
+	c.metrics.IncrementCounter("cache_hit")
+	return data, nil
+}
+
+// SetRaw 写入已编码的数据，并加入标签索引
+func (c *redisCache) SetRaw(ctx context.Context, key string, data []byte, ttl time.Duration, tags []string) error {
+	timer := c.metrics.StartTimer("redis_set_duration")
+	defer timer.Stop()
+
+	if err := c.setWithTags(ctx, key, data, ttl, tags); err != nil {
+		c.logger.Error("redis set failed", "key", key, "error", err)
+		c.metrics.IncrementCounter("cache_error")
+		return err
+	}
+
+	c.metrics.IncrementCounter("cache_set")
+	return nil
+}
@@ -3,47 +3,56 @@ package redis
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
 	"time"
+
 	"github.com/redis/go-redis/v9"
-	"github.com/your-org/your-project/internal/application/port/output"
+	"github.com/gohex/gohex/internal/application/port/output"
 )
 
+// scanBatchSize SCAN 每次返回的建议条数
+const scanBatchSize = 500
+
+var errClearWithoutNamespace = errors.New("refusing to clear cache without namespace")
+
 type redisCache struct {
-	client  *redis.Client
-	logger  Logger
-	metrics MetricsReporter
+	client    redis.UniversalClient
+	namespace string
+	logger    output.Logger
+	metrics   output.MetricsReporter
 }
 
-func NewRedisCache(client *redis.Client, logger Logger, metrics MetricsReporter) output.Cache {
+// NewRedisCache 所有 key 以 namespace 为前缀，Keys 和 Clear 只作用于该命名空间
+func NewRedisCache(client redis.UniversalClient, namespace string, logger output.Logger, metrics output.MetricsReporter) output.TaggedCache {
+	prefix := ""
+	if namespace != "" {
+		prefix = strings.TrimSuffix(namespace, ":") + ":"
+	}
 	return &redisCache{
-		client:  client,
-		logger:  logger,
-		metrics: metrics,
+		client:    client,
+		namespace: prefix,
+		logger:    logger,
+		metrics:   metrics,
 	}
 }
 
-func (c *redisCache) Get(ctx context.Context, key string) (interface{}, error) {
-	timer := c.metrics.StartTimer("redis_get_duration")
-	defer timer.Stop()
+func (c *redisCache) key(key string) string {
+	return c.namespace + key
+}
 
-	val, err := c.client.Get(ctx, key).Result()
-	if err == redis.Nil {
-		c.metrics.IncrementCounter("cache_miss")
-		return nil, nil
-	}
-	if err != nil {
-		c.logger.Error("redis get failed", "error", err)
-		c.metrics.IncrementCounter("cache_error")
+func (c *redisCache) Get(ctx context.Context, key string) (interface{}, error) {
+	data, err := c.GetRaw(ctx, key)
+	if err != nil || data == nil {
 		return nil, err
 	}
 
 	var result interface{}
-	if err := json.Unmarshal([]byte(val), &result); err != nil {
-		c.logger.Error("failed to unmarshal cache value", "error", err)
+	if err := json.Unmarshal(data, &result); err != nil {
+		c.logger.Error("failed to unmarshal cache value", "key", key, "error", err)
 		return nil, err
 	}
-
-	c.metrics.IncrementCounter("cache_hit")
 	return result, nil
 }
 
@@ -53,12 +62,12 @@ func (c *redisCache) Set(ctx context.Context, key string, value interface{}, ttl
 
 	data, err := json.Marshal(value)
 	if err != nil {
-		c.logger.Error("failed to marshal cache value", "error", err)
+		c.logger.Error("failed to marshal cache value", "key", key, "error", err)
 		return err
 	}
 
-	if err := c.client.Set(ctx, key, data, ttl).Err(); err != nil {
-		c.logger.Error("redis set failed", "error", err)
+	if err := c.client.Set(ctx, c.key(key), data, ttl).Err(); err != nil {
+		c.logger.Error("redis set failed", "key", key, "error", err)
 		c.metrics.IncrementCounter("cache_error")
 		return err
 	}
@@ -71,8 +80,8 @@ func (c *redisCache) Delete(ctx context.Context, key string) error {
 	timer := c.metrics.StartTimer("cache_delete_duration")
 	defer timer.Stop()
 
-	if err := c.client.Del(ctx, key).Err(); err != nil {
-		c.logger.Error("failed to delete from cache", "error", err)
+	if err := c.client.Unlink(ctx, c.key(key)).Err(); err != nil {
+		c.logger.Error("failed to delete from cache", "key", key, "error", err)
 		c.metrics.IncrementCounter("cache_error")
 		return err
 	}
@@ -85,9 +94,9 @@ func (c *redisCache) Increment(ctx context.Context, key string, value int64) (in
 	timer := c.metrics.StartTimer("cache_increment_duration")
 	defer timer.Stop()
 
-	result, err := c.client.IncrBy(ctx, key, value).Result()
+	result, err := c.client.IncrBy(ctx, c.key(key), value).Result()
 	if err != nil {
-		c.logger.Error("failed to increment cache", "error", err)
+		c.logger.Error("failed to increment cache", "key", key, "error", err)
 		c.metrics.IncrementCounter("cache_error")
 		return 0, err
 	}
@@ -97,5 +106,196 @@ func (c *redisCache) Increment(ctx context.Context, key string, value int64) (in
 }
 
 func (c *redisCache) Expire(ctx context.Context, key string, ttl time.Duration) error {
-	return c.client.Expire(ctx, key, ttl).Err()
-} 
\ No newline at end of file
+	if err := c.client.Expire(ctx, c.key(key), ttl).Err(); err != nil {
+		c.logger.Error("failed to expire cache key", "key", key, "error", err)
+		c.metrics.IncrementCounter("cache_error")
+		return err
+	}
+	return nil
+}
+
+// GetMulti 只返回命中的 key；集群模式下各 key 可能分布在不同槽位，使用流水线逐个 GET
+func (c *redisCache) GetMulti(ctx context.Context, keys []string) (map[string]interface{}, error) {
+	timer := c.metrics.StartTimer("redis_get_multi_duration")
+	defer timer.Stop()
+
+	result := make(map[string]interface{}, len(keys))
+	if len(keys) == 0 {
+		return result, nil
+	}
+
+	cmds := make([]*redis.StringCmd, len(keys))
+	_, err := c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			cmds[i] = pipe.Get(ctx, c.key(key))
+		}
+		return nil
+	})
+	if err != nil && err != redis.Nil {
+		c.logger.Error("redis get multi failed", "keys", len(keys), "error", err)
+		c.metrics.IncrementCounter("cache_error")
+		return nil, err
+	}
+
+	for i, cmd := range cmds {
+		data, err := cmd.Bytes()
+		if err == redis.Nil {
+			c.metrics.IncrementCounter("cache_miss")
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var value interface{}
+		if err := json.Unmarshal(data, &value); err != nil {
+			c.logger.Error("failed to unmarshal cache value", "key", keys[i], "error", err)
+			continue
+		}
+		result[keys[i]] = value
+		c.metrics.IncrementCounter("cache_hit")
+	}
+	return result, nil
+}
+
+func (c *redisCache) SetMulti(ctx context.Context, items map[string]interface{}, ttl time.Duration) error {
+	timer := c.metrics.StartTimer("redis_set_multi_duration")
+	defer timer.Stop()
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	encoded := make(map[string][]byte, len(items))
+	for key, value := range items {
+		data, err := json.Marshal(value)
+		if err != nil {
+			c.logger.Error("failed to marshal cache value", "key", key, "error", err)
+			return err
+		}
+		encoded[key] = data
+	}
+
+	_, err := c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for key, data := range encoded {
+			pipe.Set(ctx, c.key(key), data, ttl)
+		}
+		return nil
+	})
+	if err != nil {
+		c.logger.Error("redis set multi failed", "keys", len(items), "error", err)
+		c.metrics.IncrementCounter("cache_error")
+		return err
+	}
+
+	c.metrics.IncrementCounter("cache_set")
+	return nil
+}
+
+func (c *redisCache) DeleteMulti(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = c.key(key)
+	}
+	if err := c.unlink(ctx, prefixed); err != nil {
+		c.logger.Error("redis delete multi failed", "keys", len(keys), "error", err)
+		c.metrics.IncrementCounter("cache_error")
+		return err
+	}
+
+	c.metrics.IncrementCounter("cache_delete")
+	return nil
+}
+
+// Clear 删除当前命名空间下的所有 key，不影响其他环境；未设置命名空间时拒绝执行
+func (c *redisCache) Clear(ctx context.Context) error {
+	if c.namespace == "" {
+		return errClearWithoutNamespace
+	}
+
+	var deleted int
+	err := c.scan(ctx, c.namespace+"*", func(keys []string) error {
+		deleted += len(keys)
+		return c.unlink(ctx, keys)
+	})
+	if err != nil {
+		c.logger.Error("failed to clear cache", "namespace", c.namespace, "error", err)
+		c.metrics.IncrementCounter("cache_error")
+		return err
+	}
+
+	c.logger.Info("cache cleared", "namespace", c.namespace, "keys", deleted)
+	return nil
+}
+
+// Keys 使用 SCAN 遍历匹配的 key，返回值不含命名空间前缀
+func (c *redisCache) Keys(ctx context.Context, pattern string) ([]string, error) {
+	var result []string
+	err := c.scan(ctx, c.key(pattern), func(keys []string) error {
+		for _, key := range keys {
+			result = append(result, strings.TrimPrefix(key, c.namespace))
+		}
+		return nil
+	})
+	if err != nil {
+		c.logger.Error("failed to scan cache keys", "pattern", pattern, "error", err)
+		c.metrics.IncrementCounter("cache_error")
+		return nil, err
+	}
+	return result, nil
+}
+
+// scan 按批遍历匹配的 key；集群模式下遍历每个主节点
+func (c *redisCache) scan(ctx context.Context, match string, fn func(keys []string) error) error {
+	cluster, ok := c.client.(*redis.ClusterClient)
+	if !ok {
+		return scanNode(ctx, c.client, match, fn)
+	}
+
+	// ForEachMaster 并发遍历各节点，回调需要串行执行
+	var mu sync.Mutex
+	locked := func(keys []string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		return fn(keys)
+	}
+	return cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+		return scanNode(ctx, node, match, locked)
+	})
+}
+
+func scanNode(ctx context.Context, client redis.Cmdable, match string, fn func(keys []string) error) error {
+	iter := client.Scan(ctx, 0, match, scanBatchSize).Iterator()
+	batch := make([]string, 0, scanBatchSize)
+	for iter.Next(ctx) {
+		batch = append(batch, iter.Val())
+		if len(batch) == scanBatchSize {
+			if err := fn(batch); err != nil {
+				return err
+			}
+			batch = make([]string, 0, scanBatchSize)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+	return nil
+}
+
+// unlink 逐个 UNLINK，避免集群模式下多 key 命令跨槽位
+func (c *redisCache) unlink(ctx context.Context, keys []string) error {
+	_, err := c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			pipe.Unlink(ctx, key)
+		}
+		return nil
+	})
+	return err
+}
@@ -0,0 +1,102 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/testutil"
+)
+
+func TestRedisCache_Multi(t *testing.T) {
+	ctx := context.Background()
+	cache, server := newTestCache(t, "test")
+
+	require.NoError(t, cache.SetMulti(ctx, map[string]interface{}{"a": "1", "b": float64(2)}, time.Minute))
+	assert.Equal(t, `"1"`, mustGet(t, server, "test:a"))
+
+	values, err := cache.GetMulti(ctx, []string{"a", "b", "missing"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": "1", "b": float64(2)}, values)
+
+	require.NoError(t, cache.DeleteMulti(ctx, []string{"a", "missing"}))
+	values, err = cache.GetMulti(ctx, []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"b": float64(2)}, values)
+}
+
+func TestRedisCache_GetMissReturnsNil(t *testing.T) {
+	cache, _ := newTestCache(t, "test")
+
+	value, err := cache.Get(context.Background(), "missing")
+
+	require.NoError(t, err)
+	assert.Nil(t, value)
+}
+
+func TestRedisCache_NamespaceIsolation(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	staging := NewRedisCache(client, "gohex:staging", testutil.NopLogger{}, testutil.NewMetrics())
+	prod := NewRedisCache(client, "gohex:prod:", testutil.NopLogger{}, testutil.NewMetrics())
+
+	// 超过一批 SCAN 的数量，确认分批遍历完整
+	for i := 0; i < scanBatchSize+10; i++ {
+		require.NoError(t, staging.Set(ctx, fmt.Sprintf("user:id:%d", i), i, time.Minute))
+	}
+	require.NoError(t, prod.Set(ctx, "user:id:1", "prod", time.Minute))
+
+	keys, err := prod.Keys(ctx, "user:*")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user:id:1"}, keys)
+
+	keys, err = staging.Keys(ctx, "user:id:1?")
+	require.NoError(t, err)
+	sort.Strings(keys)
+	assert.Len(t, keys, 10)
+	assert.Equal(t, "user:id:10", keys[0])
+
+	require.NoError(t, staging.Clear(ctx))
+	keys, err = staging.Keys(ctx, "*")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+	value, err := prod.Get(ctx, "user:id:1")
+	require.NoError(t, err)
+	assert.Equal(t, "prod", value)
+}
+
+func TestRedisCache_ClearRequiresNamespace(t *testing.T) {
+	cache, server := newTestCache(t, "")
+	require.NoError(t, cache.Set(context.Background(), "a", "1", time.Minute))
+
+	assert.ErrorIs(t, cache.Clear(context.Background()), errClearWithoutNamespace)
+	assert.True(t, server.Exists("a"))
+}
+
+func TestRedisCache_IncrementAndExpire(t *testing.T) {
+	ctx := context.Background()
+	cache, server := newTestCache(t, "test")
+
+	n, err := cache.Increment(ctx, "login:alice", 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	require.NoError(t, cache.Expire(ctx, "login:alice", time.Minute))
+
+	assert.Equal(t, time.Minute, server.TTL("test:login:alice"))
+}
+
+func mustGet(t *testing.T, server *miniredis.Miniredis, key string) string {
+	t.Helper()
+	value, err := server.Get(key)
+	require.NoError(t, err)
+	return value
+}
@@ -0,0 +1,76 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// tagKeyPrefix 标签索引为集合，成员是带有该标签的缓存 key（含命名空间）
+const tagKeyPrefix = "tag:"
+
+func (c *redisCache) tagKey(tag string) string {
+	return c.namespace + tagKeyPrefix + tag
+}
+
+func (c *redisCache) SetWithTags(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		c.logger.Error("failed to marshal cache value", "key", key, "error", err)
+		return err
+	}
+	return c.SetRaw(ctx, key, data, ttl, tags)
+}
+
+// setWithTags 写入缓存并加入标签索引。
+// 缓存 key 与标签索引在集群中可能位于不同槽位，使用流水线而非 Lua 脚本；
+// 索引只会延长过期时间（EXPIRE NX/GT，需要 Redis 7），保证不早于其中任一缓存项过期
+func (c *redisCache) setWithTags(ctx context.Context, key string, data []byte, ttl time.Duration, tags []string) error {
+	_, err := c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, c.key(key), data, ttl)
+		for _, tag := range tags {
+			tagKey := c.tagKey(tag)
+			pipe.SAdd(ctx, tagKey, c.key(key))
+			if ttl > 0 {
+				pipe.ExpireNX(ctx, tagKey, ttl)
+				pipe.ExpireGT(ctx, tagKey, ttl)
+			} else {
+				pipe.Persist(ctx, tagKey)
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+// InvalidateTags 删除标签索引中的所有缓存项以及索引本身
+func (c *redisCache) InvalidateTags(ctx context.Context, tags ...string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	var deleted int
+	for _, tag := range tags {
+		tagKey := c.tagKey(tag)
+		keys, err := c.client.SMembers(ctx, tagKey).Result()
+		if err != nil {
+			c.logger.Error("failed to read cache tag", "tag", tag, "error", err)
+			c.metrics.IncrementCounter("cache_error")
+			return err
+		}
+
+		// 先删除缓存项再删除索引，中途失败时索引仍在，可以重试
+		if err := c.unlink(ctx, append(keys, tagKey)); err != nil {
+			c.logger.Error("failed to invalidate cache tag", "tag", tag, "error", err)
+			c.metrics.IncrementCounter("cache_error")
+			return err
+		}
+		deleted += len(keys)
+	}
+
+	c.metrics.IncrementCounter("cache_tag_invalidated")
+	c.logger.Debug("cache tags invalidated", "tags", tags, "keys", deleted)
+	return nil
+}
@@ -0,0 +1,70 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/testutil"
+)
+
+// newTestCache 基于 miniredis 创建命名空间为 namespace 的缓存
+func newTestCache(t *testing.T, namespace string) (output.TaggedCache, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewRedisCache(client, namespace, testutil.NopLogger{}, testutil.NewMetrics()), server
+}
+
+func TestTaggedCache_InvalidateTags(t *testing.T) {
+	ctx := context.Background()
+	cache, server := newTestCache(t, "test")
+
+	require.NoError(t, cache.SetWithTags(ctx, "user:id:alice", "alice", time.Minute, []string{output.UserCacheTag("alice")}))
+	require.NoError(t, cache.SetWithTags(ctx, "users:list:1", "page-1", time.Minute, []string{output.TagUsersList}))
+	require.NoError(t, cache.SetWithTags(ctx, "user:id:bob", "bob", time.Minute, []string{output.UserCacheTag("bob")}))
+	assert.True(t, server.Exists("test:tag:user:alice"))
+
+	require.NoError(t, cache.InvalidateTags(ctx, output.UserCacheTag("alice"), output.TagUsersList))
+
+	for key, want := range map[string]interface{}{"user:id:alice": nil, "users:list:1": nil, "user:id:bob": "bob"} {
+		got, err := cache.Get(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, key)
+	}
+	assert.False(t, server.Exists("test:tag:user:alice"))
+	assert.False(t, server.Exists("test:tag:"+output.TagUsersList))
+}
+
+func TestTaggedCache_TagIndexOutlivesEntries(t *testing.T) {
+	tests := []struct {
+		name    string
+		ttls    []time.Duration
+		wantTTL time.Duration
+	}{
+		{name: "extends to the longest entry", ttls: []time.Duration{time.Minute, time.Hour}, wantTTL: time.Hour},
+		{name: "never shortens", ttls: []time.Duration{time.Hour, time.Minute}, wantTTL: time.Hour},
+		{name: "persists for entries without ttl", ttls: []time.Duration{time.Minute, 0}, wantTTL: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			cache, server := newTestCache(t, "test")
+
+			for i, ttl := range tt.ttls {
+				key := []string{"a", "b"}[i]
+				require.NoError(t, cache.SetWithTags(ctx, key, key, ttl, []string{output.TagUsersList}))
+			}
+
+			assert.Equal(t, tt.wantTTL, server.TTL("test:tag:"+output.TagUsersList))
+		})
+	}
+}
@@ -4,17 +4,14 @@ import (
 	"bytes"
 	"fmt"
 	"html/template"
-	"github.com/gohex/gohex/internal/application/port"
-	"github.com/gohex/gohex/pkg/errors"
-	"github.com/gohex/gohex/internal/domain/vo"
-	"github.com/gohex/gohex/pkg/tracer"
+	"github.com/gohex/gohex/internal/application/port/output"
 	"gopkg.in/gomail.v2"
 )
 
 type smtpEmailService struct {
 	config  SMTPConfig
-	logger  Logger
-	metrics MetricsReporter
+	logger  output.Logger
+	metrics output.MetricsReporter
 }
 
 type SMTPConfig struct {
@@ -26,7 +23,7 @@ type SMTPConfig struct {
 	WebsiteURL string
 }
 
-func NewSMTPEmailService(config SMTPConfig, logger Logger, metrics MetricsReporter) port.EmailService {
+func NewSMTPEmailService(config SMTPConfig, logger output.Logger, metrics output.MetricsReporter) output.EmailService {
 	return &smtpEmailService{
 		config:  config,
 		logger:  logger,
@@ -95,6 +92,93 @@ func (s *smtpEmailService) SendPasswordChangedNotification(email string) error {
 	return nil
 }
 
+func (s *smtpEmailService) SendEmailChangeConfirmation(newEmail string, confirmToken string) error {
+	timer := s.metrics.StartTimer("email_send_duration", "type", "email_change_confirm")
+	defer timer.Stop()
+
+	data := map[string]interface{}{
+		"ConfirmURL": fmt.Sprintf("%s/email-change/confirm?token=%s", s.config.WebsiteURL, confirmToken),
+	}
+
+	if err := s.sendEmail(newEmail, "Confirm Your New Email", "email_change_confirm.html", data); err != nil {
+		s.metrics.IncrementCounter("email_send_failure", "type", "email_change_confirm")
+		return err
+	}
+
+	s.metrics.IncrementCounter("email_send_success", "type", "email_change_confirm")
+	return nil
+}
+
+func (s *smtpEmailService) SendEmailChangeNotice(oldEmail string, newEmail string, cancelToken string) error {
+	timer := s.metrics.StartTimer("email_send_duration", "type", "email_change_notice")
+	defer timer.Stop()
+
+	data := map[string]interface{}{
+		"NewEmail":  newEmail,
+		"CancelURL": fmt.Sprintf("%s/email-change/cancel?token=%s", s.config.WebsiteURL, cancelToken),
+	}
+
+	if err := s.sendEmail(oldEmail, "Your Email Is Being Changed", "email_change_notice.html", data); err != nil {
+		s.metrics.IncrementCounter("email_send_failure", "type", "email_change_notice")
+		return err
+	}
+
+	s.metrics.IncrementCounter("email_send_success", "type", "email_change_notice")
+	return nil
+}
+
+func (s *smtpEmailService) SendVerificationEmail(email string, verificationCode string) error {
+	timer := s.metrics.StartTimer("email_send_duration", "type", "verification")
+	defer timer.Stop()
+
+	data := map[string]interface{}{
+		"VerifyURL": fmt.Sprintf("%s/verify-email?token=%s", s.config.WebsiteURL, verificationCode),
+	}
+
+	if err := s.sendEmail(email, "Verify Your Email", "verification.html", data); err != nil {
+		s.metrics.IncrementCounter("email_send_failure", "type", "verification")
+		return err
+	}
+
+	s.metrics.IncrementCounter("email_send_success", "type", "verification")
+	return nil
+}
+
+func (s *smtpEmailService) SendLoginNotification(email string, ip string, userAgent string) error {
+	timer := s.metrics.StartTimer("email_send_duration", "type", "login")
+	defer timer.Stop()
+
+	data := map[string]interface{}{
+		"IP":        ip,
+		"UserAgent": userAgent,
+	}
+
+	if err := s.sendEmail(email, "New Sign-in to Your Account", "login_notification.html", data); err != nil {
+		s.metrics.IncrementCounter("email_send_failure", "type", "login")
+		return err
+	}
+
+	s.metrics.IncrementCounter("email_send_success", "type", "login")
+	return nil
+}
+
+func (s *smtpEmailService) SendAccountLockedNotification(email string, reason string) error {
+	timer := s.metrics.StartTimer("email_send_duration", "type", "account_locked")
+	defer timer.Stop()
+
+	data := map[string]interface{}{
+		"Reason": reason,
+	}
+
+	if err := s.sendEmail(email, "Your Account Has Been Locked", "account_locked.html", data); err != nil {
+		s.metrics.IncrementCounter("email_send_failure", "type", "account_locked")
+		return err
+	}
+
+	s.metrics.IncrementCounter("email_send_success", "type", "account_locked")
+	return nil
+}
+
 func (s *smtpEmailService) sendEmail(to, subject, templateName string, data interface{}) error {
 	tmpl, err := template.ParseFiles(fmt.Sprintf("templates/emails/%s", templateName))
 	if err != nil {
@@ -119,5 +203,4 @@ func (s *smtpEmailService) sendEmail(to, subject, templateName string, data inte
 
 	return nil
 }
-
-// 其他方法实现... 
\ No newline at end of file
+ 
\ No newline at end of file
@@ -3,22 +3,26 @@ package logger
 import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
-	"github.com/gohex/gohex/internal/application/port"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/infrastructure/config"
 )
 
 type zapLogger struct {
 	logger *zap.SugaredLogger
 }
 
-func NewZapLogger(config LogConfig) (Logger, error) {
+// NewZapLogger 生产环境输出 JSON，其他环境输出便于阅读的格式
+func NewZapLogger(config config.LogConfig, environment string) (output.Logger, error) {
 	var cfg zap.Config
-	if config.Environment == "production" {
+	if environment == "production" {
 		cfg = zap.NewProductionConfig()
 	} else {
 		cfg = zap.NewDevelopmentConfig()
 	}
 
-	cfg.OutputPaths = []string{config.OutputPath}
+	if config.OutputPath != "" {
+		cfg.OutputPaths = []string{config.OutputPath}
+	}
 	cfg.Level = zap.NewAtomicLevelAt(getLogLevel(config.Level))
 
 	logger, err := cfg.Build()
@@ -47,7 +51,7 @@ func (l *zapLogger) Error(msg string, args ...interface{}) {
 	l.logger.Errorw(msg, args...)
 }
 
-func (l *zapLogger) With(key string, value interface{}) Logger {
+func (l *zapLogger) With(key string, value interface{}) output.Logger {
 	return &zapLogger{
 		logger: l.logger.With(key, value),
 	}
@@ -0,0 +1,95 @@
+package envelope
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"github.com/gohex/gohex/internal/domain/event"
+	"github.com/gohex/gohex/internal/infrastructure/config"
+)
+
+// Envelope 各消息中间件适配器共用的事件消息体格式
+type Envelope struct {
+	EventID          string            `json:"event_id"`
+	EventType        string            `json:"event_type"`
+	SchemaVersion    int               `json:"schema_version"`
+	AggregateID      string            `json:"aggregate_id"`
+	AggregateType    string            `json:"aggregate_type"`
+	AggregateVersion int               `json:"aggregate_version"`
+	OccurredAt       time.Time         `json:"occurred_at"`
+	Metadata         event.Metadata    `json:"metadata"`
+	TraceContext     map[string]string `json:"trace_context,omitempty"`
+	Payload          json.RawMessage   `json:"payload"`
+}
+
+// New 包装事件并写入当前上下文的发起者、因果链和追踪信息
+func New(ctx context.Context, evt event.Event) (*Envelope, error) {
+	event.Stamp(ctx, evt)
+
+	payload, err := json.Marshal(evt)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &Envelope{
+		EventID:          evt.ID(),
+		EventType:        evt.Type(),
+		SchemaVersion:    evt.SchemaVersion(),
+		AggregateID:      evt.AggregateID(),
+		AggregateType:    config.AggregateType(evt.Type()),
+		AggregateVersion: evt.AggregateVersion(),
+		OccurredAt:       evt.OccurredAt(),
+		Metadata:         evt.Metadata(),
+		Payload:          payload,
+	}
+
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) > 0 {
+		env.TraceContext = carrier
+	}
+
+	return env, nil
+}
+
+// Decode 解析消息体并还原事件，不是信封格式或负载无法解码的消息返回错误
+func Decode(value []byte) (*Envelope, event.Event, error) {
+	var env Envelope
+	if err := json.Unmarshal(value, &env); err != nil {
+		return nil, nil, err
+	}
+	if env.EventType == "" || env.EventID == "" {
+		return nil, nil, errors.New("message is not an event envelope")
+	}
+
+	evt, err := event.Decode(env.Header(), env.Payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &env, evt, nil
+}
+
+// Header 返回信封中的事件描述信息
+func (e *Envelope) Header() event.Header {
+	return event.Header{
+		ID:               e.EventID,
+		Type:             e.EventType,
+		AggregateID:      e.AggregateID,
+		AggregateVersion: e.AggregateVersion,
+		SchemaVersion:    e.SchemaVersion,
+		OccurredAt:       e.OccurredAt,
+		Metadata:         e.Metadata,
+	}
+}
+
+// Context 返回带有发布方追踪信息的上下文
+func (e *Envelope) Context(ctx context.Context) context.Context {
+	if len(e.TraceContext) == 0 {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(e.TraceContext))
+}
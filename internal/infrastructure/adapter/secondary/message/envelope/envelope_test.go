@@ -0,0 +1,73 @@
+package envelope
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/gohex/gohex/internal/domain/event"
+)
+
+func TestEnvelope_RoundTrip(t *testing.T) {
+	evt := event.NewUserCreatedEvent("alice", "alice@example.com", "Alice")
+
+	env, err := New(context.Background(), evt)
+	require.NoError(t, err)
+	data, err := json.Marshal(env)
+	require.NoError(t, err)
+
+	decoded, got, err := Decode(data)
+	require.NoError(t, err)
+	assert.Equal(t, evt.ID(), decoded.EventID)
+	assert.Equal(t, "user", decoded.AggregateType)
+	assert.Equal(t, evt.ID(), got.ID())
+	assert.Equal(t, event.UserCreated, got.Type())
+	assert.Equal(t, "alice", got.AggregateID())
+	assert.True(t, evt.OccurredAt().Equal(got.OccurredAt()))
+}
+
+func TestDecode_RejectsForeignMessages(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{name: "not json", value: "user.created"},
+		{name: "missing event id", value: `{"event_type":"user.created","payload":{}}`},
+		{name: "missing event type", value: `{"event_id":"e1","payload":{}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Decode([]byte(tt.value))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestEnvelope_PropagatesTraceContext(t *testing.T) {
+	previous := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(previous) })
+
+	traceID, _ := trace.TraceIDFromHex("0af7651916cd43dd8448eb211c80319c")
+	spanID, _ := trace.SpanIDFromHex("b7ad6b7169203331")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	env, err := New(ctx, event.NewUserCreatedEvent("alice", "alice@example.com", "Alice"))
+	require.NoError(t, err)
+	require.NotEmpty(t, env.TraceContext)
+
+	remote := trace.SpanContextFromContext(env.Context(context.Background()))
+	assert.Equal(t, traceID, remote.TraceID())
+	assert.True(t, remote.IsRemote())
+}
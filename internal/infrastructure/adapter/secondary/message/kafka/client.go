@@ -0,0 +1,70 @@
+package kafka
+
+import (
+	"fmt"
+
+	"github.com/Shopify/sarama"
+	"github.com/gohex/gohex/internal/infrastructure/config"
+)
+
+// NewSaramaConfig 根据配置生成生产者和消费者共用的 sarama 配置
+func NewSaramaConfig(cfg config.KafkaConfig) (*sarama.Config, error) {
+	sc := sarama.NewConfig()
+
+	if cfg.Version != "" {
+		version, err := sarama.ParseKafkaVersion(cfg.Version)
+		if err != nil {
+			return nil, err
+		}
+		sc.Version = version
+	}
+
+	// 同步生产者需要等待发送结果，异步模式下由后台协程读取
+	sc.Producer.Return.Successes = true
+	sc.Producer.Return.Errors = true
+	sc.Producer.RequiredAcks = sarama.WaitForAll
+	// 按 key 哈希分区，同一聚合的事件总是进入同一分区
+	sc.Producer.Partitioner = sarama.NewHashPartitioner
+
+	p := cfg.Producer
+	if p.Idempotent || p.TransactionalID != "" {
+		if !sc.Version.IsAtLeast(sarama.V0_11_0_0) {
+			return nil, fmt.Errorf("kafka idempotent producer requires version >= 0.11.0.0, got %s", sc.Version)
+		}
+		sc.Producer.Idempotent = true
+		sc.Net.MaxOpenRequests = 1
+		if sc.Producer.Retry.Max == 0 {
+			sc.Producer.Retry.Max = 3
+		}
+	}
+	if p.TransactionalID != "" {
+		sc.Producer.Transaction.ID = p.TransactionalID
+	}
+	if p.FlushMessages > 0 {
+		sc.Producer.Flush.Messages = p.FlushMessages
+	}
+	if p.FlushFrequency > 0 {
+		sc.Producer.Flush.Frequency = p.FlushFrequency
+	}
+
+	switch p.Compression {
+	case "", "none":
+		sc.Producer.Compression = sarama.CompressionNone
+	case "gzip":
+		sc.Producer.Compression = sarama.CompressionGZIP
+	case "snappy":
+		sc.Producer.Compression = sarama.CompressionSnappy
+	case "lz4":
+		sc.Producer.Compression = sarama.CompressionLZ4
+	case "zstd":
+		sc.Producer.Compression = sarama.CompressionZSTD
+	default:
+		return nil, fmt.Errorf("unsupported kafka compression: %s", p.Compression)
+	}
+
+	sc.Consumer.Offsets.Initial = sarama.OffsetOldest
+	// 只读取已提交事务中的消息
+	sc.Consumer.IsolationLevel = sarama.ReadCommitted
+
+	return sc, nil
+}
@@ -0,0 +1,78 @@
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/infrastructure/config"
+)
+
+func TestNewSaramaConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.KafkaConfig
+		check   func(t *testing.T, sc *sarama.Config)
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			check: func(t *testing.T, sc *sarama.Config) {
+				assert.Equal(t, sarama.WaitForAll, sc.Producer.RequiredAcks)
+				assert.False(t, sc.Producer.Idempotent)
+				assert.Equal(t, sarama.ReadCommitted, sc.Consumer.IsolationLevel)
+				assert.Equal(t, sarama.OffsetOldest, sc.Consumer.Offsets.Initial)
+			},
+		},
+		{
+			name: "idempotent producer",
+			cfg:  config.KafkaConfig{Version: "2.8.0", Producer: config.KafkaProducerConfig{Idempotent: true}},
+			check: func(t *testing.T, sc *sarama.Config) {
+				assert.True(t, sc.Producer.Idempotent)
+				assert.Equal(t, 1, sc.Net.MaxOpenRequests)
+				require.NoError(t, sc.Validate())
+			},
+		},
+		{
+			name: "transactional producer implies idempotence",
+			cfg:  config.KafkaConfig{Version: "2.8.0", Producer: config.KafkaProducerConfig{TransactionalID: "gohex-api"}},
+			check: func(t *testing.T, sc *sarama.Config) {
+				assert.True(t, sc.Producer.Idempotent)
+				assert.Equal(t, "gohex-api", sc.Producer.Transaction.ID)
+			},
+		},
+		{
+			name: "batching and compression",
+			cfg: config.KafkaConfig{Producer: config.KafkaProducerConfig{
+				FlushMessages: 100, FlushFrequency: 50 * time.Millisecond, Compression: "zstd",
+			}},
+			check: func(t *testing.T, sc *sarama.Config) {
+				assert.Equal(t, 100, sc.Producer.Flush.Messages)
+				assert.Equal(t, 50*time.Millisecond, sc.Producer.Flush.Frequency)
+				assert.Equal(t, sarama.CompressionZSTD, sc.Producer.Compression)
+			},
+		},
+		{
+			name:    "idempotence needs a recent broker",
+			cfg:     config.KafkaConfig{Version: "0.10.2.0", Producer: config.KafkaProducerConfig{Idempotent: true}},
+			wantErr: "requires version",
+		},
+		{name: "unknown compression", cfg: config.KafkaConfig{Producer: config.KafkaProducerConfig{Compression: "brotli"}}, wantErr: "unsupported kafka compression"},
+		{name: "invalid version", cfg: config.KafkaConfig{Version: "latest"}, wantErr: "invalid version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc, err := NewSaramaConfig(tt.cfg)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			tt.check(t, sc)
+		})
+	}
+}
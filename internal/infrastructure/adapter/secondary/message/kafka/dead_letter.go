@@ -0,0 +1,200 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Shopify/sarama"
+)
+
+// DeadLetter 死信主题中的一条消息
+type DeadLetter struct {
+	Partition      int32
+	Offset         int64
+	Key            []byte
+	Payload        []byte
+	EventType      string
+	OriginalTopic  string
+	ConsumerGroup  string
+	FailedHandlers string
+	Error          string
+	Attempts       int
+	FailedAt       string
+	Timestamp      time.Time
+	headers        []*sarama.RecordHeader
+}
+
+// DeadLetterAdmin 查看、重放和清空死信主题
+type DeadLetterAdmin struct {
+	client   sarama.Client
+	producer sarama.SyncProducer
+	admin    sarama.ClusterAdmin
+	topic    string
+}
+
+func NewDeadLetterAdmin(
+	client sarama.Client,
+	producer sarama.SyncProducer,
+	admin sarama.ClusterAdmin,
+	topic string,
+) *DeadLetterAdmin {
+	return &DeadLetterAdmin{
+		client:   client,
+		producer: producer,
+		admin:    admin,
+		topic:    topic,
+	}
+}
+
+// List 从最早的位置读取当前所有死信消息，limit 大于 0 时最多返回 limit 条
+func (a *DeadLetterAdmin) List(ctx context.Context, limit int) ([]DeadLetter, error) {
+	var letters []DeadLetter
+	err := a.scan(ctx, func(l DeadLetter) bool {
+		letters = append(letters, l)
+		return limit <= 0 || len(letters) < limit
+	})
+	return letters, err
+}
+
+// Replay 将匹配的死信消息重新发送到原始主题，match 为 nil 时重放全部；
+// 消息保留消费组和失败处理器信息，只有此前失败的处理器会再次处理
+func (a *DeadLetterAdmin) Replay(ctx context.Context, match func(DeadLetter) bool) (int, error) {
+	replayed := 0
+	var sendErr error
+	err := a.scan(ctx, func(l DeadLetter) bool {
+		if match != nil && !match(l) {
+			return true
+		}
+		if l.OriginalTopic == "" {
+			sendErr = fmt.Errorf("dead letter %d/%d has no original topic", l.Partition, l.Offset)
+			return false
+		}
+
+		_, _, sendErr = a.producer.SendMessage(&sarama.ProducerMessage{
+			Topic: l.OriginalTopic,
+			Key:   sarama.ByteEncoder(l.Key),
+			Value: sarama.ByteEncoder(l.Payload),
+			Headers: mergeHeaders(l.headers, map[string]string{
+				HeaderRetryAttempt:      "",
+				HeaderNotBefore:         "",
+				HeaderError:             "",
+				HeaderFailedAt:          "",
+				HeaderOriginalTopic:     "",
+				HeaderOriginalPartition: "",
+				HeaderOriginalOffset:    "",
+			}),
+		})
+		if sendErr != nil {
+			return false
+		}
+		replayed++
+		return true
+	})
+	if err != nil {
+		return replayed, err
+	}
+	return replayed, sendErr
+}
+
+// Purge 删除死信主题中当前所有消息
+func (a *DeadLetterAdmin) Purge(ctx context.Context) error {
+	partitions, err := a.client.Partitions(a.topic)
+	if err != nil {
+		return err
+	}
+
+	offsets := make(map[int32]int64, len(partitions))
+	for _, p := range partitions {
+		newest, err := a.client.GetOffset(a.topic, p, sarama.OffsetNewest)
+		if err != nil {
+			return err
+		}
+		offsets[p] = newest
+	}
+
+	return a.admin.DeleteRecords(a.topic, offsets)
+}
+
+// scan 依次读取每个分区从最早位置到当前末尾的消息，fn 返回 false 时停止
+func (a *DeadLetterAdmin) scan(ctx context.Context, fn func(DeadLetter) bool) error {
+	consumer, err := sarama.NewConsumerFromClient(a.client)
+	if err != nil {
+		return err
+	}
+	defer consumer.Close()
+
+	partitions, err := a.client.Partitions(a.topic)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range partitions {
+		oldest, err := a.client.GetOffset(a.topic, p, sarama.OffsetOldest)
+		if err != nil {
+			return err
+		}
+		newest, err := a.client.GetOffset(a.topic, p, sarama.OffsetNewest)
+		if err != nil {
+			return err
+		}
+		if oldest >= newest {
+			continue
+		}
+
+		more, err := a.scanPartition(ctx, consumer, p, oldest, newest, fn)
+		if err != nil || !more {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *DeadLetterAdmin) scanPartition(
+	ctx context.Context,
+	consumer sarama.Consumer,
+	partition int32,
+	from, to int64,
+	fn func(DeadLetter) bool,
+) (bool, error) {
+	pc, err := consumer.ConsumePartition(a.topic, partition, from)
+	if err != nil {
+		return false, err
+	}
+	defer pc.Close()
+
+	for {
+		select {
+		case msg := <-pc.Messages():
+			if !fn(toDeadLetter(msg)) {
+				return false, nil
+			}
+			if msg.Offset+1 >= to {
+				return true, nil
+			}
+		case err := <-pc.Errors():
+			return false, err
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+}
+
+func toDeadLetter(msg *sarama.ConsumerMessage) DeadLetter {
+	l := DeadLetter{
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Key:       msg.Key,
+		Payload:   msg.Value,
+		Attempts:  retryAttempt(msg.Headers),
+		Timestamp: msg.Timestamp,
+		headers:   msg.Headers,
+	}
+	l.EventType, _ = headerValue(msg.Headers, HeaderEventType)
+	l.OriginalTopic, _ = headerValue(msg.Headers, HeaderOriginalTopic)
+	l.ConsumerGroup, _ = headerValue(msg.Headers, HeaderConsumerGroup)
+	l.FailedHandlers, _ = headerValue(msg.Headers, HeaderFailedHandlers)
+	l.Error, _ = headerValue(msg.Headers, HeaderError)
+	l.FailedAt, _ = headerValue(msg.Headers, HeaderFailedAt)
+	return l
+}
@@ -0,0 +1,47 @@
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToDeadLetter(t *testing.T) {
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	msg := &sarama.ConsumerMessage{
+		Partition: 1,
+		Offset:    7,
+		Key:       []byte("alice"),
+		Value:     []byte(`{"event_type":"user.created"}`),
+		Timestamp: ts,
+		Headers: recordHeaders(
+			HeaderEventType, "user.created",
+			HeaderOriginalTopic, "events.user.created",
+			HeaderConsumerGroup, "gohex",
+			HeaderFailedHandlers, "mailer",
+			HeaderError, "smtp down",
+			HeaderFailedAt, "2026-01-02T03:04:05Z",
+			HeaderRetryAttempt, "3",
+		),
+	}
+
+	l := toDeadLetter(msg)
+
+	assert.Equal(t, DeadLetter{
+		Partition:      1,
+		Offset:         7,
+		Key:            []byte("alice"),
+		Payload:        []byte(`{"event_type":"user.created"}`),
+		EventType:      "user.created",
+		OriginalTopic:  "events.user.created",
+		ConsumerGroup:  "gohex",
+		FailedHandlers: "mailer",
+		Error:          "smtp down",
+		Attempts:       3,
+		FailedAt:       "2026-01-02T03:04:05Z",
+		Timestamp:      ts,
+		headers:        msg.Headers,
+	}, l)
+}
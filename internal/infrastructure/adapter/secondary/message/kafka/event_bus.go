@@ -3,92 +3,223 @@ package kafka
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/Shopify/sarama"
 	"github.com/gohex/gohex/internal/domain/event"
-	"github.com/gohex/gohex/pkg/errors"
-	"github.com/gohex/gohex/internal/application/port"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/infrastructure/adapter/secondary/message/envelope"
+	"github.com/gohex/gohex/internal/infrastructure/config"
 	"github.com/gohex/gohex/pkg/tracer"
 )
 
 type kafkaEventBus struct {
 	producer   sarama.SyncProducer
+	async      sarama.AsyncProducer
 	consumer   sarama.ConsumerGroup
-	handlers   map[string][]port.EventHandler
-	logger     Logger
-	metrics    MetricsReporter
+	cfg        config.KafkaConfig
+	handlers   map[string][]output.EventHandler
+	mu         sync.RWMutex
+	// txMu 事务生产者同一时间只能有一个进行中的事务
+	txMu       sync.Mutex
+	wg         sync.WaitGroup
+	logger     output.Logger
+	metrics    output.MetricsReporter
 }
 
+// NewKafkaEventBus 基于同一个 client 创建生产者，client 配置应由 NewSaramaConfig 生成
 func NewKafkaEventBus(
-	producer sarama.SyncProducer,
+	client sarama.Client,
 	consumer sarama.ConsumerGroup,
-	logger Logger,
-	metrics MetricsReporter,
-) port.EventBus {
-	return &kafkaEventBus{
+	cfg config.KafkaConfig,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) (output.EventBus, error) {
+	producer, err := sarama.NewSyncProducerFromClient(client)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &kafkaEventBus{
 		producer:  producer,
 		consumer:  consumer,
-		handlers:  make(map[string][]port.EventHandler),
+		cfg:       cfg,
+		handlers:  make(map[string][]output.EventHandler),
 		logger:    logger,
 		metrics:   metrics,
 	}
+
+	if cfg.Producer.Async {
+		async, err := sarama.NewAsyncProducerFromClient(client)
+		if err != nil {
+			producer.Close()
+			return nil, err
+		}
+		b.async = async
+		b.wg.Add(2)
+		go b.drainSuccesses()
+		go b.drainErrors()
+	}
+
+	return b, nil
 }
 
+// Publish 以聚合 ID 作为消息 key，同一聚合的事件进入同一分区并保持顺序；
+// 一次调用的所有事件批量发送，配置事务时要么全部可见要么全部不可见
 func (b *kafkaEventBus) Publish(ctx context.Context, events ...event.Event) error {
 	span, ctx := tracer.StartSpan(ctx, "kafkaEventBus.Publish")
 	defer span.End()
 
+	if len(events) == 0 {
+		return nil
+	}
+
+	timer := b.metrics.StartTimer("event_publish_duration")
+	defer timer.Stop()
+
+	msgs := make([]*sarama.ProducerMessage, 0, len(events))
 	for _, evt := range events {
-		timer := b.metrics.StartTimer("event_publish_duration")
-		msg, err := b.serializeEvent(evt)
+		msg, err := b.newMessage(ctx, evt)
 		if err != nil {
-			timer.Stop()
 			return err
 		}
+		msgs = append(msgs, msg)
+	}
+
+	if b.async != nil {
+		for _, msg := range msgs {
+			select {
+			case b.async.Input() <- msg:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	}
 
-		_, _, err = b.producer.SendMessage(&sarama.ProducerMessage{
-			Topic: b.eventToTopic(evt),
-			Value: sarama.StringEncoder(msg),
-			Headers: []sarama.RecordHeader{
-				{
-					Key:   []byte("event_type"),
-					Value: []byte(evt.Type()),
-				},
-			},
-		})
+	if err := b.send(msgs); err != nil {
+		b.logger.Error("failed to publish events",
+			"count", len(msgs),
+			"error", err,
+		)
+		b.metrics.IncrementCounter("event_publish_failure")
+		return err
+	}
 
-		timer.Stop()
+	b.metrics.IncrementCounter("event_publish_success")
+	return nil
+}
 
-		if err != nil {
-			b.logger.Error("failed to publish event",
-				"event_type", evt.Type(),
-				"error", err,
-			)
-			b.metrics.IncrementCounter("event_publish_failure")
-			return err
+func (b *kafkaEventBus) newMessage(ctx context.Context, evt event.Event) (*sarama.ProducerMessage, error) {
+	env, err := envelope.New(ctx, evt)
+	if err != nil {
+		return nil, err
+	}
+	value, err := json.Marshal(env)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sarama.ProducerMessage{
+		Topic: b.cfg.EventTopic(evt.Type()),
+		Key:   sarama.StringEncoder(evt.AggregateID()),
+		Value: sarama.ByteEncoder(value),
+		Headers: []sarama.RecordHeader{
+			{Key: []byte(HeaderEventType), Value: []byte(env.EventType)},
+			{Key: []byte(HeaderEventID), Value: []byte(env.EventID)},
+			{Key: []byte(HeaderSchemaVersion), Value: []byte(strconv.Itoa(env.SchemaVersion))},
+		},
+		Metadata: env.EventType,
+	}, nil
+}
+
+// send 同步发送一批消息，配置了事务 ID 时包在一个事务中
+func (b *kafkaEventBus) send(msgs []*sarama.ProducerMessage) error {
+	if b.cfg.Producer.TransactionalID == "" {
+		return b.producer.SendMessages(msgs)
+	}
+
+	b.txMu.Lock()
+	defer b.txMu.Unlock()
+
+	if err := b.producer.BeginTxn(); err != nil {
+		return err
+	}
+	if err := b.producer.SendMessages(msgs); err != nil {
+		if abortErr := b.producer.AbortTxn(); abortErr != nil {
+			b.logger.Error("failed to abort kafka transaction", "error", abortErr)
 		}
+		return err
+	}
+	return b.producer.CommitTxn()
+}
 
+func (b *kafkaEventBus) drainSuccesses() {
+	defer b.wg.Done()
+	for range b.async.Successes() {
 		b.metrics.IncrementCounter("event_publish_success")
 	}
+}
+
+// drainErrors 异步发送失败的事件无法返回给调用方，只能记录
+func (b *kafkaEventBus) drainErrors() {
+	defer b.wg.Done()
+	for perr := range b.async.Errors() {
+		eventType, _ := perr.Msg.Metadata.(string)
+		b.logger.Error("failed to publish event",
+			"event_type", eventType,
+			"topic", perr.Msg.Topic,
+			"error", perr.Err,
+		)
+		b.metrics.IncrementCounter("event_publish_failure")
+	}
+}
 
-	return nil
+func (b *kafkaEventBus) Subscribe(eventType string, handler output.EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
 }
 
-func (b *kafkaEventBus) Subscribe(handler port.EventHandler, eventTypes ...string) {
-	for _, eventType := range eventTypes {
-		b.handlers[eventType] = append(b.handlers[eventType], handler)
+func (b *kafkaEventBus) Unsubscribe(eventType string, handler output.EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	handlers := b.handlers[eventType]
+	for i, h := range handlers {
+		if h.HandlerID() == handler.HandlerID() {
+			b.handlers[eventType] = append(handlers[:i:i], handlers[i+1:]...)
+			return
+		}
 	}
 }
 
+// Close 停止消费并等待异步生产者发送完缓冲中的消息
+func (b *kafkaEventBus) Close() error {
+	if err := b.consumer.Close(); err != nil {
+		return err
+	}
+	if b.async != nil {
+		b.async.AsyncClose()
+		b.wg.Wait()
+	}
+	return b.producer.Close()
+}
+
 func (b *kafkaEventBus) Start(ctx context.Context) error {
 	topics := b.getSubscribedTopics()
-	
+
 	go func() {
 		for {
 			err := b.consumer.Consume(ctx, topics, &consumerGroupHandler{
-				handlers: b.handlers,
-				logger:   b.logger,
-				metrics:  b.metrics,
+				bus:     b,
+				logger:  b.logger,
+				metrics: b.metrics,
 			})
 			if err != nil {
 				b.logger.Error("failed to consume messages", "error", err)
@@ -103,67 +234,201 @@ func (b *kafkaEventBus) Start(ctx context.Context) error {
 }
 
 type consumerGroupHandler struct {
-	handlers map[string][]port.EventHandler
-	logger   Logger
-	metrics  MetricsReporter
+	bus     *kafkaEventBus
+	logger  output.Logger
+	metrics output.MetricsReporter
 }
 
+func (h *consumerGroupHandler) Setup(sarama.ConsumerGroupSession) error   { return nil }
+func (h *consumerGroupHandler) Cleanup(sarama.ConsumerGroupSession) error { return nil }
+
+// ConsumeClaim 消息只有在处理成功或已转入重试、死信主题后才提交，
+// 转发失败时返回错误结束本次会话，消息会在重新平衡后再次投递
 func (h *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
 	for msg := range claim.Messages() {
-		timer := h.metrics.StartTimer("event_process_duration")
-		
-		eventType := string(msg.Headers[0].Value)
-		handlers := h.handlers[eventType]
-
-		event, err := h.deserializeEvent(eventType, msg.Value)
+		timer := h.metrics.StartTimer("event_process_duration", "topic", msg.Topic)
+		err := h.process(session.Context(), msg)
+		timer.Stop()
 		if err != nil {
-			h.logger.Error("failed to deserialize event", "error", err)
+			return err
+		}
+		session.MarkMessage(msg, "")
+	}
+	return nil
+}
+
+func (h *consumerGroupHandler) process(ctx context.Context, msg *sarama.ConsumerMessage) error {
+	// 重试主题中的消息等到延迟结束再处理，同一主题延迟相同，不会打乱顺序
+	if wait := time.Until(notBefore(msg.Headers)); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
 			timer.Stop()
+			return ctx.Err()
+		}
+	}
+
+	eventType, ok := headerValue(msg.Headers, HeaderEventType)
+	if !ok {
+		return h.deadLetter(msg, nil, fmt.Errorf("missing %s header", HeaderEventType))
+	}
+
+	env, evt, err := envelope.Decode(msg.Value)
+	if err != nil {
+		h.logger.Error("failed to decode event envelope", "event_type", eventType, "error", err)
+		return h.deadLetter(msg, nil, err)
+	}
+	ctx = env.Context(ctx)
+
+	handlers := h.handlersFor(msg, eventType)
+
+	var (
+		failed  []string
+		lastErr error
+	)
+	for _, handler := range handlers {
+		if err := handler.Handle(ctx, evt); err != nil {
+			h.logger.Error("failed to handle event",
+				"event_type", eventType,
+				"handler", handler.HandlerID(),
+				"attempt", retryAttempt(msg.Headers),
+				"error", err,
+			)
+			h.metrics.IncrementCounter("event_handle_failure", "type", eventType)
+			failed = append(failed, handler.HandlerID())
+			lastErr = err
 			continue
 		}
+		h.metrics.IncrementCounter("event_handle_success", "type", eventType)
+	}
 
-		for _, handler := range handlers {
-			if err := handler.Handle(session.Context(), event); err != nil {
-				h.logger.Error("failed to handle event",
-					"event_type", eventType,
-					"error", err,
-				)
-				h.metrics.IncrementCounter("event_handle_failure")
-			} else {
-				h.metrics.IncrementCounter("event_handle_success")
-			}
+	if len(failed) == 0 {
+		return nil
+	}
+	return h.retry(msg, failed, lastErr)
+}
+
+// handlersFor 返回需要处理该消息的处理器；重试或重放的消息只交给此前失败的处理器，
+// 其他消费组收到重放消息时直接忽略
+func (h *consumerGroupHandler) handlersFor(msg *sarama.ConsumerMessage, eventType string) []output.EventHandler {
+	h.bus.mu.RLock()
+	handlers := h.bus.handlers[eventType]
+	h.bus.mu.RUnlock()
+
+	only := failedHandlers(msg.Headers)
+	if only == nil {
+		return handlers
+	}
+	if group, ok := headerValue(msg.Headers, HeaderConsumerGroup); ok && group != h.bus.cfg.ConsumerGroup {
+		return nil
+	}
+
+	filtered := make([]output.EventHandler, 0, len(only))
+	for _, handler := range handlers {
+		if only[handler.HandlerID()] {
+			filtered = append(filtered, handler)
 		}
+	}
+	return filtered
+}
 
-		session.MarkMessage(msg, "")
-		timer.Stop()
+// retry 将消息转入下一个重试主题，重试次数耗尽后转入死信主题
+func (h *consumerGroupHandler) retry(msg *sarama.ConsumerMessage, failed []string, cause error) error {
+	attempt := retryAttempt(msg.Headers) + 1
+	delays := h.bus.cfg.Retry.Delays
+	if attempt > len(delays) {
+		return h.deadLetter(msg, failed, cause)
 	}
+
+	overrides := h.failureHeaders(msg, failed, cause)
+	overrides[HeaderRetryAttempt] = strconv.Itoa(attempt)
+	overrides[HeaderNotBefore] = strconv.FormatInt(time.Now().Add(delays[attempt-1]).UnixMilli(), 10)
+
+	topic := h.bus.cfg.RetryTopic(attempt)
+	if err := h.forward(topic, msg, overrides); err != nil {
+		return err
+	}
+
+	h.metrics.IncrementCounter("event_retry_scheduled", "attempt", strconv.Itoa(attempt))
 	return nil
 }
 
-func (b *kafkaEventBus) serializeEvent(evt event.Event) (string, error) {
-	data := map[string]interface{}{
-		"id":           evt.AggregateID(),
-		"type":         evt.Type(),
-		"occurred_at":  evt.OccurredAt(),
-		"data":         evt.Data(),
+// deadLetter 将原始消息体连同失败信息转入死信主题
+func (h *consumerGroupHandler) deadLetter(msg *sarama.ConsumerMessage, failed []string, cause error) error {
+	overrides := h.failureHeaders(msg, failed, cause)
+	overrides[HeaderRetryAttempt] = strconv.Itoa(retryAttempt(msg.Headers))
+
+	topic := h.bus.cfg.DeadLetter()
+	if err := h.forward(topic, msg, overrides); err != nil {
+		return err
 	}
-	
-	bytes, err := json.Marshal(data)
-	if err != nil {
-		return "", err
+
+	h.logger.Warn("event moved to dead letter topic",
+		"topic", topic,
+		"original_topic", overrides[HeaderOriginalTopic],
+		"error", cause,
+	)
+	h.metrics.IncrementCounter("event_dead_lettered")
+	return nil
+}
+
+// failureHeaders 记录原始位置和失败原因，重试链路中保留第一次消费时的原始位置
+func (h *consumerGroupHandler) failureHeaders(msg *sarama.ConsumerMessage, failed []string, cause error) map[string]string {
+	overrides := map[string]string{
+		HeaderConsumerGroup: h.bus.cfg.ConsumerGroup,
+		HeaderFailedAt:      time.Now().UTC().Format(time.RFC3339Nano),
+		HeaderNotBefore:     "",
+	}
+	if cause != nil {
+		overrides[HeaderError] = cause.Error()
+	}
+	if len(failed) > 0 {
+		overrides[HeaderFailedHandlers] = strings.Join(failed, ",")
+	}
+	if _, ok := headerValue(msg.Headers, HeaderOriginalTopic); !ok {
+		overrides[HeaderOriginalTopic] = msg.Topic
+		overrides[HeaderOriginalPartition] = strconv.Itoa(int(msg.Partition))
+		overrides[HeaderOriginalOffset] = strconv.FormatInt(msg.Offset, 10)
 	}
-	
-	return string(bytes), nil
+	return overrides
 }
 
-func (b *kafkaEventBus) eventToTopic(evt event.Event) string {
-	return "events." + evt.Type()
+func (h *consumerGroupHandler) forward(topic string, msg *sarama.ConsumerMessage, overrides map[string]string) error {
+	err := h.bus.send([]*sarama.ProducerMessage{{
+		Topic:   topic,
+		Key:     sarama.ByteEncoder(msg.Key),
+		Value:   sarama.ByteEncoder(msg.Value),
+		Headers: mergeHeaders(msg.Headers, overrides),
+	}})
+	if err != nil {
+		h.logger.Error("failed to forward event",
+			"topic", topic,
+			"original_topic", msg.Topic,
+			"offset", msg.Offset,
+			"error", err,
+		)
+		h.metrics.IncrementCounter("event_forward_failure", "topic", topic)
+	}
+	return err
 }
 
 func (b *kafkaEventBus) getSubscribedTopics() []string {
-	topics := make([]string, 0, len(b.handlers))
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	topics := make([]string, 0, len(b.handlers)+len(b.cfg.Retry.Delays))
 	for eventType := range b.handlers {
-		topics = append(topics, "events."+eventType)
+		// 按聚合划分主题时多种事件共用一个主题
+		topic := b.cfg.EventTopic(eventType)
+		if !seen[topic] {
+			seen[topic] = true
+			topics = append(topics, topic)
+		}
+	}
+	if len(b.handlers) > 0 {
+		topics = append(topics, b.cfg.RetryTopics()...)
 	}
 	return topics
-} 
\ No newline at end of file
+}
@@ -0,0 +1,319 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/event"
+	"github.com/gohex/gohex/internal/infrastructure/adapter/secondary/message/envelope"
+	"github.com/gohex/gohex/internal/infrastructure/config"
+	"github.com/gohex/gohex/internal/testutil"
+)
+
+// syncProducer 记录发送的消息和事务调用
+type syncProducer struct {
+	sarama.SyncProducer
+	sent []*sarama.ProducerMessage
+	err  error
+	txn  []string
+}
+
+func (p *syncProducer) BeginTxn() error {
+	p.txn = append(p.txn, "begin")
+	return nil
+}
+
+func (p *syncProducer) CommitTxn() error {
+	p.txn = append(p.txn, "commit")
+	return nil
+}
+
+func (p *syncProducer) AbortTxn() error {
+	p.txn = append(p.txn, "abort")
+	return nil
+}
+
+func (p *syncProducer) SendMessages(msgs []*sarama.ProducerMessage) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.sent = append(p.sent, msgs...)
+	return nil
+}
+
+// failingHandler 记录调用次数，err 非空时处理失败
+type failingHandler struct {
+	id    string
+	err   error
+	calls int
+}
+
+func (h *failingHandler) HandlerID() string { return h.id }
+
+func (h *failingHandler) Handle(ctx context.Context, evt event.Event) error {
+	h.calls++
+	return h.err
+}
+
+func newTestBus(producer *syncProducer, handlers ...output.EventHandler) *kafkaEventBus {
+	b := &kafkaEventBus{
+		producer: producer,
+		cfg: config.KafkaConfig{
+			ConsumerGroup: "gohex",
+			Retry:         config.KafkaRetryConfig{Delays: []time.Duration{time.Second, time.Minute}},
+		},
+		handlers: make(map[string][]output.EventHandler),
+		logger:   testutil.NopLogger{},
+		metrics:  testutil.NewMetrics(),
+	}
+	for _, h := range handlers {
+		b.Subscribe(event.UserCreated, h)
+	}
+	return b
+}
+
+// consumed 构造消费到的消息，headers 为额外的消息头
+func consumed(t *testing.T, topic string, headers map[string]string) *sarama.ConsumerMessage {
+	t.Helper()
+	env, err := envelope.New(context.Background(), event.NewUserCreatedEvent("alice", "alice@example.com", "Alice"))
+	require.NoError(t, err)
+	value, err := json.Marshal(env)
+	require.NoError(t, err)
+
+	msg := &sarama.ConsumerMessage{Topic: topic, Partition: 2, Offset: 42, Key: []byte("alice"), Value: value,
+		Headers: []*sarama.RecordHeader{{Key: []byte(HeaderEventType), Value: []byte(event.UserCreated)}}}
+	for k, v := range headers {
+		msg.Headers = append(msg.Headers, &sarama.RecordHeader{Key: []byte(k), Value: []byte(v)})
+	}
+	return msg
+}
+
+func header(msg *sarama.ProducerMessage, key string) string {
+	for _, h := range msg.Headers {
+		if string(h.Key) == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func TestConsumerGroupHandler_RetryAndDeadLetter(t *testing.T) {
+	tests := []struct {
+		name          string
+		topic         string
+		headers       map[string]string
+		wantTopic     string
+		wantAttempt   string
+		wantOrigTopic string
+	}{
+		{name: "first failure goes to first retry topic", topic: "events.user.created", wantTopic: "gohex.retry.1", wantAttempt: "1", wantOrigTopic: "events.user.created"},
+		{
+			name:          "retry failure goes to next retry topic",
+			topic:         "gohex.retry.1",
+			headers:       map[string]string{HeaderRetryAttempt: "1", HeaderOriginalTopic: "events.user.created", HeaderFailedHandlers: "mailer"},
+			wantTopic:     "gohex.retry.2",
+			wantAttempt:   "2",
+			wantOrigTopic: "events.user.created",
+		},
+		{
+			name:          "exhausted retries go to dead letter topic",
+			topic:         "gohex.retry.2",
+			headers:       map[string]string{HeaderRetryAttempt: "2", HeaderOriginalTopic: "events.user.created", HeaderFailedHandlers: "mailer"},
+			wantTopic:     "gohex.dlq",
+			wantAttempt:   "2",
+			wantOrigTopic: "events.user.created",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			producer := &syncProducer{}
+			audit := &failingHandler{id: "audit"}
+			mailer := &failingHandler{id: "mailer", err: stderrors.New("smtp down")}
+			h := &consumerGroupHandler{bus: newTestBus(producer, audit, mailer), logger: testutil.NopLogger{}, metrics: testutil.NewMetrics()}
+
+			require.NoError(t, h.process(context.Background(), consumed(t, tt.topic, tt.headers)))
+
+			require.Len(t, producer.sent, 1)
+			sent := producer.sent[0]
+			assert.Equal(t, tt.wantTopic, sent.Topic)
+			assert.Equal(t, tt.wantAttempt, header(sent, HeaderRetryAttempt))
+			assert.Equal(t, tt.wantOrigTopic, header(sent, HeaderOriginalTopic))
+			assert.Equal(t, "mailer", header(sent, HeaderFailedHandlers))
+			assert.Equal(t, "gohex", header(sent, HeaderConsumerGroup))
+			assert.Equal(t, "smtp down", header(sent, HeaderError))
+			assert.Equal(t, 1, mailer.calls)
+			// 重试消息只交给此前失败的处理器
+			if tt.headers == nil {
+				assert.Equal(t, 1, audit.calls)
+			} else {
+				assert.Zero(t, audit.calls)
+			}
+			if tt.wantTopic == "gohex.dlq" {
+				assert.Empty(t, header(sent, HeaderNotBefore))
+			} else {
+				assert.NotEmpty(t, header(sent, HeaderNotBefore))
+			}
+		})
+	}
+}
+
+func TestConsumerGroupHandler_KeepsOriginalPosition(t *testing.T) {
+	producer := &syncProducer{}
+	h := &consumerGroupHandler{
+		bus:     newTestBus(producer, &failingHandler{id: "mailer", err: stderrors.New("smtp down")}),
+		logger:  testutil.NopLogger{},
+		metrics: testutil.NewMetrics(),
+	}
+
+	require.NoError(t, h.process(context.Background(), consumed(t, "events.user.created", nil)))
+
+	sent := producer.sent[0]
+	assert.Equal(t, "2", header(sent, HeaderOriginalPartition))
+	assert.Equal(t, "42", header(sent, HeaderOriginalOffset))
+	assert.Equal(t, event.UserCreated, header(sent, HeaderEventType))
+}
+
+func TestConsumerGroupHandler_UndecodableMessagesAreDeadLettered(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  *sarama.ConsumerMessage
+	}{
+		{name: "missing event type", msg: &sarama.ConsumerMessage{Topic: "events.user.created", Value: []byte(`{}`)}},
+		{name: "invalid envelope", msg: &sarama.ConsumerMessage{Topic: "events.user.created", Value: []byte(`not json`),
+			Headers: []*sarama.RecordHeader{{Key: []byte(HeaderEventType), Value: []byte(event.UserCreated)}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			producer := &syncProducer{}
+			handler := &failingHandler{id: "audit"}
+			h := &consumerGroupHandler{bus: newTestBus(producer, handler), logger: testutil.NopLogger{}, metrics: testutil.NewMetrics()}
+
+			require.NoError(t, h.process(context.Background(), tt.msg))
+
+			require.Len(t, producer.sent, 1)
+			assert.Equal(t, "gohex.dlq", producer.sent[0].Topic)
+			assert.Zero(t, handler.calls)
+		})
+	}
+}
+
+func TestConsumerGroupHandler_IgnoresReplayForOtherGroup(t *testing.T) {
+	producer := &syncProducer{}
+	handler := &failingHandler{id: "mailer"}
+	h := &consumerGroupHandler{bus: newTestBus(producer, handler), logger: testutil.NopLogger{}, metrics: testutil.NewMetrics()}
+
+	msg := consumed(t, "events.user.created", map[string]string{HeaderConsumerGroup: "other", HeaderFailedHandlers: "mailer"})
+	require.NoError(t, h.process(context.Background(), msg))
+
+	assert.Zero(t, handler.calls)
+	assert.Empty(t, producer.sent)
+}
+
+func TestConsumerGroupHandler_ForwardFailureIsReturned(t *testing.T) {
+	producer := &syncProducer{err: stderrors.New("broker unavailable")}
+	h := &consumerGroupHandler{
+		bus:     newTestBus(producer, &failingHandler{id: "mailer", err: stderrors.New("smtp down")}),
+		logger:  testutil.NopLogger{},
+		metrics: testutil.NewMetrics(),
+	}
+
+	// 转发失败时不提交位移，消息会再次投递
+	assert.Error(t, h.process(context.Background(), consumed(t, "events.user.created", nil)))
+}
+
+func TestConsumerGroupHandler_WaitsForRetryDelay(t *testing.T) {
+	producer := &syncProducer{}
+	handler := &failingHandler{id: "mailer"}
+	h := &consumerGroupHandler{bus: newTestBus(producer, handler), logger: testutil.NopLogger{}, metrics: testutil.NewMetrics()}
+	notBefore := time.Now().Add(time.Hour).UnixMilli()
+	msg := consumed(t, "gohex.retry.1", map[string]string{HeaderNotBefore: strconv.FormatInt(notBefore, 10)})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, h.process(ctx, msg), context.DeadlineExceeded)
+	assert.Zero(t, handler.calls)
+}
+
+func TestKafkaEventBus_Publish(t *testing.T) {
+	tests := []struct {
+		name       string
+		topicMode  string
+		wantTopics []string
+	}{
+		{name: "topic per event", wantTopics: []string{"events.user.created", "events.user.profile_updated"}},
+		{name: "topic per aggregate", topicMode: config.KafkaTopicPerAggregate, wantTopics: []string{"events.user", "events.user"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			producer := &syncProducer{}
+			b := newTestBus(producer)
+			b.cfg.TopicMode = tt.topicMode
+			created := event.NewUserCreatedEvent("alice", "alice@example.com", "Alice")
+			updated := event.NewUserProfileUpdatedEvent("alice", "Alice A.", "")
+
+			require.NoError(t, b.Publish(context.Background(), created, updated))
+
+			// 一次调用的事件在同一批中发送，以聚合 ID 为 key
+			require.Len(t, producer.sent, 2)
+			for i, msg := range producer.sent {
+				assert.Equal(t, tt.wantTopics[i], msg.Topic)
+				key, err := msg.Key.Encode()
+				require.NoError(t, err)
+				assert.Equal(t, "alice", string(key))
+			}
+			assert.Equal(t, created.ID(), header(producer.sent[0], HeaderEventID))
+			assert.Equal(t, event.UserProfileUpdated, header(producer.sent[1], HeaderEventType))
+			assert.Empty(t, producer.txn)
+
+			value, err := producer.sent[0].Value.Encode()
+			require.NoError(t, err)
+			env, evt, err := envelope.Decode(value)
+			require.NoError(t, err)
+			assert.Equal(t, "user", env.AggregateType)
+			assert.Equal(t, created.ID(), evt.ID())
+		})
+	}
+}
+
+func TestKafkaEventBus_PublishTransactional(t *testing.T) {
+	tests := []struct {
+		name    string
+		sendErr error
+		wantTxn []string
+	}{
+		{name: "commits batch", wantTxn: []string{"begin", "commit"}},
+		{name: "aborts on send failure", sendErr: stderrors.New("broker unavailable"), wantTxn: []string{"begin", "abort"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			producer := &syncProducer{err: tt.sendErr}
+			b := newTestBus(producer)
+			b.cfg.Producer.TransactionalID = "gohex-api"
+
+			err := b.Publish(context.Background(),
+				event.NewUserCreatedEvent("alice", "alice@example.com", "Alice"),
+				event.NewUserCreatedEvent("bob", "bob@example.com", "Bob"))
+
+			if tt.sendErr != nil {
+				assert.ErrorIs(t, err, tt.sendErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantTxn, producer.txn)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package kafka
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Shopify/sarama"
+)
+
+// 消息头
+const (
+	HeaderEventType     = "event_type"
+	HeaderEventID       = "event_id"
+	HeaderSchemaVersion = "schema_version"
+	// 以下由重试和死信流程写入
+	HeaderRetryAttempt      = "retry_attempt"
+	HeaderNotBefore         = "not_before"
+	HeaderOriginalTopic     = "original_topic"
+	HeaderOriginalPartition = "original_partition"
+	HeaderOriginalOffset    = "original_offset"
+	HeaderConsumerGroup     = "consumer_group"
+	HeaderFailedHandlers    = "failed_handlers"
+	HeaderError             = "error"
+	HeaderFailedAt          = "failed_at"
+)
+
+// headerValue 按 key 查找消息头，不依赖消息头的顺序
+func headerValue(headers []*sarama.RecordHeader, key string) (string, bool) {
+	for _, h := range headers {
+		if h != nil && string(h.Key) == key {
+			return string(h.Value), true
+		}
+	}
+	return "", false
+}
+
+// retryAttempt 返回消息已经历的重试次数，原始消息为 0
+func retryAttempt(headers []*sarama.RecordHeader) int {
+	v, ok := headerValue(headers, HeaderRetryAttempt)
+	if !ok {
+		return 0
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// notBefore 返回重试消息最早可处理的时间
+func notBefore(headers []*sarama.RecordHeader) time.Time {
+	v, ok := headerValue(headers, HeaderNotBefore)
+	if !ok {
+		return time.Time{}
+	}
+	ms, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
+
+// failedHandlers 返回上次处理失败的处理器，为空表示所有处理器都要执行
+func failedHandlers(headers []*sarama.RecordHeader) map[string]bool {
+	v, ok := headerValue(headers, HeaderFailedHandlers)
+	if !ok || v == "" {
+		return nil
+	}
+	ids := make(map[string]bool)
+	for _, id := range strings.Split(v, ",") {
+		ids[id] = true
+	}
+	return ids
+}
+
+// mergeHeaders 复制原消息头并用 overrides 覆盖同名项，值为空的项会被移除
+func mergeHeaders(headers []*sarama.RecordHeader, overrides map[string]string) []sarama.RecordHeader {
+	merged := make([]sarama.RecordHeader, 0, len(headers)+len(overrides))
+	for _, h := range headers {
+		if h == nil {
+			continue
+		}
+		if _, ok := overrides[string(h.Key)]; ok {
+			continue
+		}
+		merged = append(merged, sarama.RecordHeader{Key: h.Key, Value: h.Value})
+	}
+	for k, v := range overrides {
+		if v == "" {
+			continue
+		}
+		merged = append(merged, sarama.RecordHeader{Key: []byte(k), Value: []byte(v)})
+	}
+	return merged
+}
@@ -0,0 +1,63 @@
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+)
+
+func recordHeaders(kv ...string) []*sarama.RecordHeader {
+	headers := make([]*sarama.RecordHeader, 0, len(kv)/2)
+	for i := 0; i < len(kv); i += 2 {
+		headers = append(headers, &sarama.RecordHeader{Key: []byte(kv[i]), Value: []byte(kv[i+1])})
+	}
+	return headers
+}
+
+func TestRetryAttempt(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers []*sarama.RecordHeader
+		want    int
+	}{
+		{name: "original message", want: 0},
+		{name: "retried message", headers: recordHeaders(HeaderEventType, "user.created", HeaderRetryAttempt, "2"), want: 2},
+		{name: "invalid value", headers: recordHeaders(HeaderRetryAttempt, "x"), want: 0},
+		{name: "negative value", headers: recordHeaders(HeaderRetryAttempt, "-1"), want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, retryAttempt(tt.headers))
+		})
+	}
+}
+
+func TestNotBefore(t *testing.T) {
+	at := time.UnixMilli(1700000000123)
+
+	assert.True(t, notBefore(recordHeaders(HeaderNotBefore, "1700000000123")).Equal(at))
+	assert.True(t, notBefore(nil).IsZero())
+	assert.True(t, notBefore(recordHeaders(HeaderNotBefore, "soon")).IsZero())
+}
+
+func TestFailedHandlers(t *testing.T) {
+	assert.Nil(t, failedHandlers(nil))
+	assert.Nil(t, failedHandlers(recordHeaders(HeaderFailedHandlers, "")))
+	assert.Equal(t, map[string]bool{"audit": true, "mailer": true},
+		failedHandlers(recordHeaders(HeaderFailedHandlers, "audit,mailer")))
+}
+
+func TestMergeHeaders(t *testing.T) {
+	merged := mergeHeaders(
+		append(recordHeaders(HeaderEventType, "user.created", HeaderRetryAttempt, "1", HeaderError, "boom"), nil),
+		map[string]string{HeaderRetryAttempt: "2", HeaderError: ""},
+	)
+
+	assert.ElementsMatch(t, []sarama.RecordHeader{
+		{Key: []byte(HeaderEventType), Value: []byte("user.created")},
+		{Key: []byte(HeaderRetryAttempt), Value: []byte("2")},
+	}, merged)
+}
@@ -0,0 +1,316 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/gohex/gohex/internal/domain/event"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/infrastructure/adapter/secondary/message/envelope"
+	"github.com/gohex/gohex/internal/infrastructure/config"
+)
+
+const (
+	defaultStream          = "GOHEX"
+	defaultSubjectPrefix   = "gohex"
+	defaultAckWait         = 30 * time.Second
+	defaultMaxDeliver      = 5
+	defaultNakDelay        = 5 * time.Second
+	defaultDuplicateWindow = 2 * time.Minute
+
+	headerEventType = "Event-Type"
+)
+
+type natsEventBus struct {
+	conn   *nats.Conn
+	js     jetstream.JetStream
+	stream jetstream.Stream
+	cfg    config.NATSConfig
+	// ctx 为 Start 传入的上下文，Start 之后的订阅直接创建消费者
+	ctx           context.Context
+	subscriptions map[string][]*subscription
+	mu            sync.Mutex
+	// inflight 正在执行的处理器，Close 时等待其完成
+	inflight sync.WaitGroup
+	logger   output.Logger
+	metrics  output.MetricsReporter
+}
+
+// subscription 一个处理器对应一个持久消费者，处理失败互不影响
+type subscription struct {
+	eventType string
+	handler   output.EventHandler
+	consume   jetstream.ConsumeContext
+}
+
+func NewEventBus(conn *nats.Conn, cfg config.NATSConfig, logger output.Logger, metrics output.MetricsReporter) (output.EventBus, error) {
+	js, err := jetstream.New(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Stream == "" {
+		cfg.Stream = defaultStream
+	}
+	if cfg.SubjectPrefix == "" {
+		cfg.SubjectPrefix = defaultSubjectPrefix
+	}
+	if cfg.Durable == "" {
+		cfg.Durable = cfg.SubjectPrefix
+	}
+	if cfg.AckWait <= 0 {
+		cfg.AckWait = defaultAckWait
+	}
+	if cfg.MaxDeliver <= 0 {
+		cfg.MaxDeliver = defaultMaxDeliver
+	}
+	if cfg.NakDelay <= 0 {
+		cfg.NakDelay = defaultNakDelay
+	}
+	if cfg.DuplicateWindow <= 0 {
+		cfg.DuplicateWindow = defaultDuplicateWindow
+	}
+
+	return &natsEventBus{
+		conn:          conn,
+		js:            js,
+		cfg:           cfg,
+		subscriptions: make(map[string][]*subscription),
+		logger:        logger,
+		metrics:       metrics,
+	}, nil
+}
+
+// Start 创建或更新流，并为已订阅的处理器创建消费者
+func (b *natsEventBus) Start(ctx context.Context) error {
+	stream, err := b.js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:       b.cfg.Stream,
+		Subjects:   []string{b.cfg.SubjectPrefix + ".>"},
+		Storage:    jetstream.FileStorage,
+		Duplicates: b.cfg.DuplicateWindow,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create stream %s: %w", b.cfg.Stream, err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.stream = stream
+	b.ctx = ctx
+	for _, subs := range b.subscriptions {
+		for _, sub := range subs {
+			if err := b.consume(sub); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Publish 以事件 ID 作为消息 ID，DuplicateWindow 内重复发布的同一事件只保存一次
+func (b *natsEventBus) Publish(ctx context.Context, events ...event.Event) error {
+	for _, evt := range events {
+		timer := b.metrics.StartTimer("event_publish_duration", "type", evt.Type())
+		err := b.publish(ctx, evt)
+		timer.Stop()
+
+		if err != nil {
+			b.logger.Error("failed to publish event",
+				"event_type", evt.Type(),
+				"error", err,
+			)
+			b.metrics.IncrementCounter("event_publish_failure", "type", evt.Type())
+			return err
+		}
+		b.metrics.IncrementCounter("event_publish_success", "type", evt.Type())
+	}
+	return nil
+}
+
+func (b *natsEventBus) publish(ctx context.Context, evt event.Event) error {
+	env, err := envelope.New(ctx, evt)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	msg := nats.NewMsg(b.subject(evt.Type()))
+	msg.Data = data
+	msg.Header.Set(headerEventType, env.EventType)
+
+	_, err = b.js.PublishMsg(ctx, msg, jetstream.WithMsgID(env.EventID))
+	return err
+}
+
+func (b *natsEventBus) Subscribe(eventType string, handler output.EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub := &subscription{eventType: eventType, handler: handler}
+	b.subscriptions[eventType] = append(b.subscriptions[eventType], sub)
+
+	if b.stream == nil {
+		return
+	}
+	if err := b.consume(sub); err != nil {
+		b.logger.Error("failed to subscribe to event",
+			"event_type", eventType,
+			"handler", handler.HandlerID(),
+			"error", err,
+		)
+	}
+}
+
+// Unsubscribe 停止投递给该处理器，服务端保留持久消费者，重新订阅后从上次确认的位置继续
+func (b *natsEventBus) Unsubscribe(eventType string, handler output.EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subs := b.subscriptions[eventType]
+	for i, sub := range subs {
+		if sub.handler.HandlerID() != handler.HandlerID() {
+			continue
+		}
+		if sub.consume != nil {
+			sub.consume.Stop()
+		}
+		b.subscriptions[eventType] = append(subs[:i:i], subs[i+1:]...)
+		return
+	}
+}
+
+// Close 停止所有消费者，等待正在执行的处理器完成后断开连接
+func (b *natsEventBus) Close() error {
+	b.mu.Lock()
+	for _, subs := range b.subscriptions {
+		for _, sub := range subs {
+			if sub.consume != nil {
+				sub.consume.Stop()
+			}
+		}
+	}
+	b.subscriptions = make(map[string][]*subscription)
+	b.mu.Unlock()
+
+	b.inflight.Wait()
+	return b.conn.Drain()
+}
+
+// consume 为订阅创建持久消费者并开始接收消息，调用方需持有 b.mu
+func (b *natsEventBus) consume(sub *subscription) error {
+	consumer, err := b.stream.CreateOrUpdateConsumer(b.ctx, jetstream.ConsumerConfig{
+		Durable:       b.durableName(sub),
+		FilterSubject: b.subject(sub.eventType),
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		AckWait:       b.cfg.AckWait,
+		MaxDeliver:    b.cfg.MaxDeliver,
+		MaxAckPending: b.cfg.MaxAckPending,
+		DeliverPolicy: jetstream.DeliverAllPolicy,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create consumer for %s: %w", sub.handler.HandlerID(), err)
+	}
+
+	cc, err := consumer.Consume(func(msg jetstream.Msg) {
+		b.inflight.Add(1)
+		defer b.inflight.Done()
+		b.handle(sub, msg)
+	})
+	if err != nil {
+		return err
+	}
+	sub.consume = cc
+	return nil
+}
+
+// handle 处理成功时 ack；处理失败时 nak 并按投递次数递增延迟，由服务端重新投递；
+// 无法解析的消息 term，不再投递
+func (b *natsEventBus) handle(sub *subscription, msg jetstream.Msg) {
+	handlerID := sub.handler.HandlerID()
+	timer := b.metrics.StartTimer("event_process_duration", "type", sub.eventType, "handler", handlerID)
+	defer timer.Stop()
+
+	env, evt, err := envelope.Decode(msg.Data())
+	if err != nil {
+		b.logger.Error("failed to decode event envelope",
+			"subject", msg.Subject(),
+			"handler", handlerID,
+			"error", err,
+		)
+		b.metrics.IncrementCounter("event_decode_failure", "type", sub.eventType)
+		b.ack(msg.Term())
+		return
+	}
+
+	// 处理时间不超过 AckWait，避免处理尚未结束时服务端就重新投递
+	ctx, cancel := context.WithTimeout(env.Context(b.ctx), b.cfg.AckWait)
+	defer cancel()
+
+	if err := sub.handler.Handle(ctx, evt); err != nil {
+		delivered := deliveryCount(msg)
+		b.logger.Error("failed to handle event",
+			"event_type", env.EventType,
+			"event_id", env.EventID,
+			"handler", handlerID,
+			"delivered", delivered,
+			"error", err,
+		)
+		b.metrics.IncrementCounter("event_handle_failure", "type", env.EventType, "handler", handlerID)
+		if delivered >= b.cfg.MaxDeliver {
+			b.logger.Error("event delivery attempts exhausted",
+				"event_type", env.EventType,
+				"event_id", env.EventID,
+				"handler", handlerID,
+			)
+			b.metrics.IncrementCounter("event_delivery_exhausted", "type", env.EventType, "handler", handlerID)
+			b.ack(msg.Term())
+			return
+		}
+		b.ack(msg.NakWithDelay(b.cfg.NakDelay * time.Duration(delivered)))
+		return
+	}
+
+	b.metrics.IncrementCounter("event_handle_success", "type", env.EventType, "handler", handlerID)
+	b.ack(msg.Ack())
+}
+
+func (b *natsEventBus) ack(err error) {
+	if err != nil && !errors.Is(err, nats.ErrConnectionClosed) {
+		b.logger.Warn("failed to acknowledge message", "error", err)
+	}
+}
+
+func (b *natsEventBus) subject(eventType string) string {
+	return b.cfg.SubjectPrefix + "." + eventType
+}
+
+// durableName 持久消费者名称不能包含 . * > 和空白
+func (b *natsEventBus) durableName(sub *subscription) string {
+	name := b.cfg.Durable + "_" + sub.handler.HandlerID() + "_" + sub.eventType
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '.', '*', '>', ' ', '\t', '\n':
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+func deliveryCount(msg jetstream.Msg) int {
+	meta, err := msg.Metadata()
+	if err != nil {
+		return 1
+	}
+	return int(meta.NumDelivered)
+}
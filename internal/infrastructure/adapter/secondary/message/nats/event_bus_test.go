@@ -0,0 +1,181 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/domain/event"
+	"github.com/gohex/gohex/internal/infrastructure/adapter/secondary/message/envelope"
+	"github.com/gohex/gohex/internal/infrastructure/config"
+	"github.com/gohex/gohex/internal/testutil"
+)
+
+// message 记录确认结果的 JetStream 消息，delivered 为 0 时元数据不可用
+type message struct {
+	jetstream.Msg
+	data      []byte
+	delivered uint64
+	acked     bool
+	termed    bool
+	nakDelay  time.Duration
+	nakked    bool
+}
+
+func (m *message) Data() []byte    { return m.data }
+func (m *message) Subject() string { return "gohex.user.created" }
+
+func (m *message) Metadata() (*jetstream.MsgMetadata, error) {
+	if m.delivered == 0 {
+		return nil, jetstream.ErrNotJSMessage
+	}
+	return &jetstream.MsgMetadata{NumDelivered: m.delivered}, nil
+}
+
+func (m *message) Ack() error {
+	m.acked = true
+	return nil
+}
+
+func (m *message) Term() error {
+	m.termed = true
+	return nil
+}
+
+func (m *message) NakWithDelay(delay time.Duration) error {
+	m.nakked, m.nakDelay = true, delay
+	return nil
+}
+
+// publisher 记录发布的消息
+type publisher struct {
+	jetstream.JetStream
+	published []*nats.Msg
+}
+
+func (p *publisher) PublishMsg(ctx context.Context, msg *nats.Msg, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+	p.published = append(p.published, msg)
+	return &jetstream.PubAck{}, nil
+}
+
+type recordingHandler struct {
+	id       string
+	err      error
+	received []event.Event
+}
+
+func (h *recordingHandler) HandlerID() string { return h.id }
+
+func (h *recordingHandler) Handle(ctx context.Context, evt event.Event) error {
+	h.received = append(h.received, evt)
+	return h.err
+}
+
+func newTestBus(js jetstream.JetStream) (*natsEventBus, *testutil.Metrics) {
+	metrics := testutil.NewMetrics()
+	return &natsEventBus{
+		js: js,
+		cfg: config.NATSConfig{
+			SubjectPrefix: defaultSubjectPrefix,
+			Durable:       defaultSubjectPrefix,
+			AckWait:       time.Second,
+			MaxDeliver:    3,
+			NakDelay:      time.Second,
+		},
+		ctx:           context.Background(),
+		subscriptions: make(map[string][]*subscription),
+		logger:        testutil.NopLogger{},
+		metrics:       metrics,
+	}, metrics
+}
+
+func envelopeBody(t *testing.T) []byte {
+	t.Helper()
+	env, err := envelope.New(context.Background(), event.NewUserCreatedEvent("alice", "alice@example.com", "Alice"))
+	require.NoError(t, err)
+	body, err := json.Marshal(env)
+	require.NoError(t, err)
+	return body
+}
+
+func TestEventBus_Handle(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       []byte
+		delivered  uint64
+		handlerErr error
+		wantAck    bool
+		wantTerm   bool
+		wantNak    time.Duration
+		wantCalls  int
+	}{
+		{name: "acks handled event", body: envelopeBody(t), delivered: 1, wantAck: true, wantCalls: 1},
+		// 延迟随投递次数递增
+		{name: "naks failed event", body: envelopeBody(t), delivered: 2, handlerErr: stderrors.New("smtp down"), wantNak: 2 * time.Second, wantCalls: 1},
+		{name: "naks without metadata as first delivery", body: envelopeBody(t), handlerErr: stderrors.New("smtp down"), wantNak: time.Second, wantCalls: 1},
+		{name: "terms after last delivery", body: envelopeBody(t), delivered: 3, handlerErr: stderrors.New("smtp down"), wantTerm: true, wantCalls: 1},
+		{name: "terms undecodable message", body: []byte("not json"), delivered: 1, wantTerm: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, _ := newTestBus(nil)
+			handler := &recordingHandler{id: "notifications", err: tt.handlerErr}
+			msg := &message{data: tt.body, delivered: tt.delivered}
+
+			b.handle(&subscription{eventType: event.UserCreated, handler: handler}, msg)
+
+			assert.Len(t, handler.received, tt.wantCalls)
+			assert.Equal(t, tt.wantAck, msg.acked)
+			assert.Equal(t, tt.wantTerm, msg.termed)
+			assert.Equal(t, tt.wantNak > 0, msg.nakked)
+			assert.Equal(t, tt.wantNak, msg.nakDelay)
+		})
+	}
+}
+
+func TestEventBus_PublishSubject(t *testing.T) {
+	js := &publisher{}
+	b, metrics := newTestBus(js)
+
+	err := b.Publish(context.Background(), event.NewUserCreatedEvent("alice", "alice@example.com", "Alice"))
+	require.NoError(t, err)
+
+	require.Len(t, js.published, 1)
+	msg := js.published[0]
+	assert.Equal(t, "gohex.user.created", msg.Subject)
+	assert.Equal(t, event.UserCreated, msg.Header.Get(headerEventType))
+	_, evt, err := envelope.Decode(msg.Data)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", evt.AggregateID())
+	assert.Equal(t, 1, metrics.Counter("event_publish_success"))
+}
+
+func TestEventBus_DurableName(t *testing.T) {
+	b, _ := newTestBus(nil)
+
+	tests := []struct {
+		name      string
+		handlerID string
+		eventType string
+		want      string
+	}{
+		{name: "dots replaced", handlerID: "notifications", eventType: event.UserCreated, want: "gohex_notifications_user_created"},
+		{name: "wildcards and spaces replaced", handlerID: "cache invalidation*", eventType: "user.>", want: "gohex_cache_invalidation__user__"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := &subscription{eventType: tt.eventType, handler: &recordingHandler{id: tt.handlerID}}
+			assert.Equal(t, tt.want, b.durableName(sub))
+			assert.Equal(t, "gohex."+tt.eventType, b.subject(tt.eventType))
+		})
+	}
+}
@@ -0,0 +1,470 @@
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/gohex/gohex/internal/domain/event"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/infrastructure/adapter/secondary/message/envelope"
+	"github.com/gohex/gohex/internal/infrastructure/config"
+)
+
+const (
+	defaultExchange       = "gohex.events"
+	defaultPrefetch       = 50
+	defaultConfirmTimeout = 5 * time.Second
+	defaultReconnectDelay = 2 * time.Second
+
+	headerEventType = "event_type"
+)
+
+type rabbitEventBus struct {
+	cfg config.RabbitMQConfig
+
+	// connMu 保护连接和发布通道，断开后由下一次使用者重新建立
+	connMu    sync.Mutex
+	conn      *amqp.Connection
+	publishCh *amqp.Channel
+
+	// ctx 为 Start 传入的上下文，Close 时取消以停止所有消费循环
+	ctx    context.Context
+	cancel context.CancelFunc
+	// queues 按处理器 ID 保存订阅，每个处理器在订阅组内有一个持久队列
+	queues map[string]*queue
+	mu     sync.Mutex
+	wg     sync.WaitGroup
+
+	logger  output.Logger
+	metrics output.MetricsReporter
+}
+
+// queue 一个处理器的持久队列，绑定该处理器订阅的所有事件类型
+type queue struct {
+	name       string
+	handler    output.EventHandler
+	eventTypes map[string]bool
+	cancel     context.CancelFunc
+}
+
+func NewEventBus(cfg config.RabbitMQConfig, logger output.Logger, metrics output.MetricsReporter) output.EventBus {
+	if cfg.Exchange == "" {
+		cfg.Exchange = defaultExchange
+	}
+	if cfg.DeadLetterExchange == "" {
+		cfg.DeadLetterExchange = cfg.Exchange + ".dlx"
+	}
+	if cfg.Prefetch <= 0 {
+		cfg.Prefetch = defaultPrefetch
+	}
+	if cfg.ConfirmTimeout <= 0 {
+		cfg.ConfirmTimeout = defaultConfirmTimeout
+	}
+	if cfg.ReconnectDelay <= 0 {
+		cfg.ReconnectDelay = defaultReconnectDelay
+	}
+
+	return &rabbitEventBus{
+		cfg:     cfg,
+		queues:  make(map[string]*queue),
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+// Start 声明交换机并开始消费已订阅的队列
+func (b *rabbitEventBus) Start(ctx context.Context) error {
+	ch, err := b.channel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+
+	if err := b.declareExchanges(ch); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.ctx, b.cancel = context.WithCancel(ctx)
+	for _, q := range b.queues {
+		b.startConsumer(q)
+	}
+	return nil
+}
+
+// Publish 开启发布确认，broker 确认持久化后才返回
+func (b *rabbitEventBus) Publish(ctx context.Context, events ...event.Event) error {
+	for _, evt := range events {
+		timer := b.metrics.StartTimer("event_publish_duration", "type", evt.Type())
+		err := b.publish(ctx, evt)
+		timer.Stop()
+
+		if err != nil {
+			b.logger.Error("failed to publish event",
+				"event_type", evt.Type(),
+				"error", err,
+			)
+			b.metrics.IncrementCounter("event_publish_failure", "type", evt.Type())
+			return err
+		}
+		b.metrics.IncrementCounter("event_publish_success", "type", evt.Type())
+	}
+	return nil
+}
+
+func (b *rabbitEventBus) publish(ctx context.Context, evt event.Event) error {
+	env, err := envelope.New(ctx, evt)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	ch, err := b.publishChannel()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, b.cfg.ConfirmTimeout)
+	defer cancel()
+
+	confirm, err := ch.PublishWithDeferredConfirmWithContext(ctx, b.cfg.Exchange, env.EventType, false, false, amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		MessageId:    env.EventID,
+		Type:         env.EventType,
+		Timestamp:    env.OccurredAt,
+		Headers:      amqp.Table{headerEventType: env.EventType},
+		Body:         body,
+	})
+	if err != nil {
+		return err
+	}
+
+	acked, err := confirm.WaitContext(ctx)
+	if err != nil {
+		return err
+	}
+	if !acked {
+		return fmt.Errorf("broker rejected event %s", env.EventID)
+	}
+	return nil
+}
+
+func (b *rabbitEventBus) Subscribe(eventType string, handler output.EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	q, ok := b.queues[handler.HandlerID()]
+	if !ok {
+		q = &queue{
+			name:       b.queueName(handler),
+			handler:    handler,
+			eventTypes: make(map[string]bool),
+		}
+		b.queues[handler.HandlerID()] = q
+	}
+	q.eventTypes[eventType] = true
+
+	if b.ctx == nil {
+		return
+	}
+	// 已在消费时只需补充绑定，新队列则启动消费循环
+	if ok && q.cancel != nil {
+		if err := b.bind(q, eventType); err != nil {
+			b.logger.Error("failed to bind queue",
+				"queue", q.name,
+				"event_type", eventType,
+				"error", err,
+			)
+		}
+		return
+	}
+	b.startConsumer(q)
+}
+
+// Unsubscribe 解除事件类型的绑定，处理器不再订阅任何事件时停止消费，队列保留在 broker 上
+func (b *rabbitEventBus) Unsubscribe(eventType string, handler output.EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	q, ok := b.queues[handler.HandlerID()]
+	if !ok || !q.eventTypes[eventType] {
+		return
+	}
+	delete(q.eventTypes, eventType)
+
+	if b.ctx != nil {
+		if err := b.unbind(q, eventType); err != nil {
+			b.logger.Warn("failed to unbind queue",
+				"queue", q.name,
+				"event_type", eventType,
+				"error", err,
+			)
+		}
+	}
+
+	if len(q.eventTypes) == 0 {
+		if q.cancel != nil {
+			q.cancel()
+		}
+		delete(b.queues, handler.HandlerID())
+	}
+}
+
+// Close 停止所有消费循环并等待正在处理的消息确认后关闭连接
+func (b *rabbitEventBus) Close() error {
+	b.mu.Lock()
+	if b.cancel != nil {
+		b.cancel()
+	}
+	b.mu.Unlock()
+
+	b.wg.Wait()
+
+	b.connMu.Lock()
+	defer b.connMu.Unlock()
+
+	if b.conn == nil || b.conn.IsClosed() {
+		return nil
+	}
+	return b.conn.Close()
+}
+
+// startConsumer 启动队列的消费循环，调用方需持有 b.mu
+func (b *rabbitEventBus) startConsumer(q *queue) {
+	ctx, cancel := context.WithCancel(b.ctx)
+	q.cancel = cancel
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.consumeLoop(ctx, q)
+	}()
+}
+
+// consumeLoop 通道或连接断开后等待 ReconnectDelay 重新建立并恢复消费，直到 ctx 取消
+func (b *rabbitEventBus) consumeLoop(ctx context.Context, q *queue) {
+	for {
+		err := b.consume(ctx, q)
+		if ctx.Err() != nil {
+			return
+		}
+
+		b.logger.Warn("rabbitmq consumer interrupted, reconnecting",
+			"queue", q.name,
+			"error", err,
+		)
+		b.metrics.IncrementCounter("event_consumer_reconnect", "queue", q.name)
+
+		timer := time.NewTimer(b.cfg.ReconnectDelay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+func (b *rabbitEventBus) consume(ctx context.Context, q *queue) error {
+	ch, err := b.channel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+
+	if err := b.declareQueue(ch, q); err != nil {
+		return err
+	}
+	if err := ch.Qos(b.cfg.Prefetch, 0, false); err != nil {
+		return err
+	}
+
+	deliveries, err := ch.ConsumeWithContext(ctx, q.name, "", false, false, false, false, nil)
+	if err != nil {
+		return err
+	}
+
+	for d := range deliveries {
+		b.handle(ctx, q, d)
+	}
+	return errors.New("delivery channel closed")
+}
+
+// handle 处理成功时 ack，失败或无法解析时 nack 且不重新入队，由 DLX 转入死信队列
+func (b *rabbitEventBus) handle(ctx context.Context, q *queue, d amqp.Delivery) {
+	handlerID := q.handler.HandlerID()
+	timer := b.metrics.StartTimer("event_process_duration", "type", d.RoutingKey, "handler", handlerID)
+	defer timer.Stop()
+
+	env, evt, err := envelope.Decode(d.Body)
+	if err != nil {
+		b.logger.Error("failed to decode event envelope",
+			"queue", q.name,
+			"routing_key", d.RoutingKey,
+			"error", err,
+		)
+		b.metrics.IncrementCounter("event_decode_failure", "type", d.RoutingKey)
+		b.ack(d.Nack(false, false))
+		return
+	}
+
+	// 消费循环被取消时让当前消息处理完，确认后再退出
+	if err := q.handler.Handle(env.Context(context.WithoutCancel(ctx)), evt); err != nil {
+		b.logger.Error("failed to handle event",
+			"event_type", env.EventType,
+			"event_id", env.EventID,
+			"handler", handlerID,
+			"error", err,
+		)
+		b.metrics.IncrementCounter("event_handle_failure", "type", env.EventType, "handler", handlerID)
+		b.metrics.IncrementCounter("event_dead_lettered", "queue", q.name)
+		b.ack(d.Nack(false, false))
+		return
+	}
+
+	b.metrics.IncrementCounter("event_handle_success", "type", env.EventType, "handler", handlerID)
+	b.ack(d.Ack(false))
+}
+
+func (b *rabbitEventBus) ack(err error) {
+	if err != nil && !errors.Is(err, amqp.ErrClosed) {
+		b.logger.Warn("failed to acknowledge message", "error", err)
+	}
+}
+
+func (b *rabbitEventBus) declareExchanges(ch *amqp.Channel) error {
+	if err := ch.ExchangeDeclare(b.cfg.Exchange, amqp.ExchangeTopic, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare exchange %s: %w", b.cfg.Exchange, err)
+	}
+	if err := ch.ExchangeDeclare(b.cfg.DeadLetterExchange, amqp.ExchangeDirect, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare exchange %s: %w", b.cfg.DeadLetterExchange, err)
+	}
+	return nil
+}
+
+// declareQueue 声明队列及其死信队列并绑定所有订阅的事件类型，重连后重复调用是幂等的
+func (b *rabbitEventBus) declareQueue(ch *amqp.Channel, q *queue) error {
+	if err := b.declareExchanges(ch); err != nil {
+		return err
+	}
+
+	dlq := q.name + ".dlq"
+	if _, err := ch.QueueDeclare(dlq, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare queue %s: %w", dlq, err)
+	}
+	if err := ch.QueueBind(dlq, q.name, b.cfg.DeadLetterExchange, false, nil); err != nil {
+		return fmt.Errorf("failed to bind queue %s: %w", dlq, err)
+	}
+
+	if _, err := ch.QueueDeclare(q.name, true, false, false, false, amqp.Table{
+		"x-dead-letter-exchange":    b.cfg.DeadLetterExchange,
+		"x-dead-letter-routing-key": q.name,
+	}); err != nil {
+		return fmt.Errorf("failed to declare queue %s: %w", q.name, err)
+	}
+
+	b.mu.Lock()
+	eventTypes := make([]string, 0, len(q.eventTypes))
+	for eventType := range q.eventTypes {
+		eventTypes = append(eventTypes, eventType)
+	}
+	b.mu.Unlock()
+
+	for _, eventType := range eventTypes {
+		if err := ch.QueueBind(q.name, eventType, b.cfg.Exchange, false, nil); err != nil {
+			return fmt.Errorf("failed to bind queue %s: %w", q.name, err)
+		}
+	}
+	return nil
+}
+
+func (b *rabbitEventBus) bind(q *queue, eventType string) error {
+	ch, err := b.channel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+	return ch.QueueBind(q.name, eventType, b.cfg.Exchange, false, nil)
+}
+
+func (b *rabbitEventBus) unbind(q *queue, eventType string) error {
+	ch, err := b.channel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+	return ch.QueueUnbind(q.name, eventType, b.cfg.Exchange, nil)
+}
+
+// channel 在当前连接上打开新通道，连接已断开时重新连接
+func (b *rabbitEventBus) channel() (*amqp.Channel, error) {
+	b.connMu.Lock()
+	defer b.connMu.Unlock()
+
+	conn, err := b.connection()
+	if err != nil {
+		return nil, err
+	}
+	return conn.Channel()
+}
+
+// publishChannel 返回开启了发布确认的共享通道，通道或连接断开后重新建立
+func (b *rabbitEventBus) publishChannel() (*amqp.Channel, error) {
+	b.connMu.Lock()
+	defer b.connMu.Unlock()
+
+	if b.publishCh != nil && !b.publishCh.IsClosed() {
+		return b.publishCh, nil
+	}
+
+	conn, err := b.connection()
+	if err != nil {
+		return nil, err
+	}
+	ch, err := conn.Channel()
+	if err != nil {
+		return nil, err
+	}
+	if err := ch.Confirm(false); err != nil {
+		ch.Close()
+		return nil, err
+	}
+
+	b.publishCh = ch
+	return ch, nil
+}
+
+// connection 返回可用连接，调用方需持有 b.connMu
+func (b *rabbitEventBus) connection() (*amqp.Connection, error) {
+	if b.conn != nil && !b.conn.IsClosed() {
+		return b.conn, nil
+	}
+
+	conn, err := amqp.Dial(b.cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to rabbitmq: %w", err)
+	}
+	if b.conn != nil {
+		b.logger.Info("reconnected to rabbitmq")
+		b.metrics.IncrementCounter("rabbitmq_reconnect")
+	}
+
+	b.conn = conn
+	b.publishCh = nil
+	return conn, nil
+}
+
+func (b *rabbitEventBus) queueName(handler output.EventHandler) string {
+	return b.cfg.Group + "." + handler.HandlerID()
+}
@@ -0,0 +1,154 @@
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/domain/event"
+	"github.com/gohex/gohex/internal/infrastructure/adapter/secondary/message/envelope"
+	"github.com/gohex/gohex/internal/infrastructure/config"
+	"github.com/gohex/gohex/internal/testutil"
+)
+
+// acknowledger 记录消息的确认结果
+type acknowledger struct {
+	acked   bool
+	nacked  bool
+	requeue bool
+}
+
+func (a *acknowledger) Ack(tag uint64, multiple bool) error {
+	a.acked = true
+	return nil
+}
+
+func (a *acknowledger) Nack(tag uint64, multiple, requeue bool) error {
+	a.nacked, a.requeue = true, requeue
+	return nil
+}
+
+func (a *acknowledger) Reject(tag uint64, requeue bool) error {
+	return a.Nack(tag, false, requeue)
+}
+
+type recordingHandler struct {
+	id       string
+	err      error
+	received []event.Event
+}
+
+func (h *recordingHandler) HandlerID() string { return h.id }
+
+func (h *recordingHandler) Handle(ctx context.Context, evt event.Event) error {
+	h.received = append(h.received, evt)
+	return h.err
+}
+
+func newTestBus() (*rabbitEventBus, *testutil.Metrics) {
+	metrics := testutil.NewMetrics()
+	return NewEventBus(config.RabbitMQConfig{Group: "gohex"}, testutil.NopLogger{}, metrics).(*rabbitEventBus), metrics
+}
+
+func envelopeBody(t *testing.T) []byte {
+	t.Helper()
+	env, err := envelope.New(context.Background(), event.NewUserCreatedEvent("alice", "alice@example.com", "Alice"))
+	require.NoError(t, err)
+	body, err := json.Marshal(env)
+	require.NoError(t, err)
+	return body
+}
+
+func TestEventBus_Handle(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       []byte
+		handlerErr error
+		wantAck    bool
+		wantCalls  int
+	}{
+		{name: "acks handled event", body: envelopeBody(t), wantAck: true, wantCalls: 1},
+		{name: "dead letters failed event", body: envelopeBody(t), handlerErr: stderrors.New("smtp down"), wantCalls: 1},
+		{name: "dead letters undecodable message", body: []byte("not json")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, metrics := newTestBus()
+			handler := &recordingHandler{id: "mailer", err: tt.handlerErr}
+			ack := &acknowledger{}
+
+			b.handle(context.Background(), &queue{name: "gohex.mailer", handler: handler},
+				amqp.Delivery{Acknowledger: ack, RoutingKey: event.UserCreated, Body: tt.body})
+
+			assert.Len(t, handler.received, tt.wantCalls)
+			assert.Equal(t, tt.wantAck, ack.acked)
+			assert.Equal(t, !tt.wantAck, ack.nacked)
+			// 不重新入队，由 DLX 转入死信队列
+			assert.False(t, ack.requeue)
+			if tt.handlerErr != nil {
+				assert.Equal(t, 1, metrics.Counter("event_dead_lettered"))
+			}
+		})
+	}
+}
+
+func TestEventBus_HandleFinishesAfterCancel(t *testing.T) {
+	b, _ := newTestBus()
+	var handlerCtxErr error
+	handler := &ctxHandler{fn: func(ctx context.Context) { handlerCtxErr = ctx.Err() }}
+	ack := &acknowledger{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	b.handle(ctx, &queue{name: "gohex.audit", handler: handler},
+		amqp.Delivery{Acknowledger: ack, RoutingKey: event.UserCreated, Body: envelopeBody(t)})
+
+	assert.NoError(t, handlerCtxErr)
+	assert.True(t, ack.acked)
+}
+
+type ctxHandler struct {
+	fn func(ctx context.Context)
+}
+
+func (h *ctxHandler) HandlerID() string { return "audit" }
+
+func (h *ctxHandler) Handle(ctx context.Context, evt event.Event) error {
+	h.fn(ctx)
+	return nil
+}
+
+func TestEventBus_SubscriptionsShareQueuePerHandler(t *testing.T) {
+	b, _ := newTestBus()
+	mailer := &recordingHandler{id: "mailer"}
+
+	b.Subscribe(event.UserCreated, mailer)
+	b.Subscribe(event.UserDeleted, mailer)
+	b.Subscribe(event.UserCreated, &recordingHandler{id: "audit"})
+
+	require.Len(t, b.queues, 2)
+	assert.Equal(t, "gohex.mailer", b.queues["mailer"].name)
+	assert.Equal(t, map[string]bool{event.UserCreated: true, event.UserDeleted: true}, b.queues["mailer"].eventTypes)
+
+	b.Unsubscribe(event.UserCreated, mailer)
+	assert.Equal(t, map[string]bool{event.UserDeleted: true}, b.queues["mailer"].eventTypes)
+	b.Unsubscribe(event.UserDeleted, mailer)
+	assert.NotContains(t, b.queues, "mailer")
+	assert.Contains(t, b.queues, "audit")
+}
+
+func TestNewEventBus_Defaults(t *testing.T) {
+	b, _ := newTestBus()
+
+	assert.Equal(t, defaultExchange, b.cfg.Exchange)
+	assert.Equal(t, defaultExchange+".dlx", b.cfg.DeadLetterExchange)
+	assert.Equal(t, defaultPrefetch, b.cfg.Prefetch)
+	assert.Equal(t, defaultConfirmTimeout, b.cfg.ConfirmTimeout)
+	assert.Equal(t, defaultReconnectDelay, b.cfg.ReconnectDelay)
+}
@@ -1,107 +1,173 @@
 package metrics
 
 import (
+	"sync"
 	"time"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/gohex/gohex/internal/application/port"
+	"github.com/gohex/gohex/internal/application/port/output"
 )
 
 type prometheusMetrics struct {
+	namespace string
+	registerer prometheus.Registerer
+
+	mu       sync.Mutex
 	counters map[string]*prometheus.CounterVec
 	gauges   map[string]*prometheus.GaugeVec
 	timers   map[string]*prometheus.HistogramVec
+	// histograms 记录非计时类的观测值，桶从 0.1 到约 3276 按 2 倍递增
+	histograms map[string]*prometheus.HistogramVec
+	// labels 指标首次注册时的标签名，同名指标之后的上报按这些标签取值
+	labels map[string][]string
+}
+
+func NewPrometheusMetrics(namespace string) output.MetricsReporter {
+	return newPrometheusMetrics(namespace, prometheus.DefaultRegisterer)
 }
 
-func NewPrometheusMetrics(namespace string) MetricsReporter {
+func newPrometheusMetrics(namespace string, registerer prometheus.Registerer) *prometheusMetrics {
 	return &prometheusMetrics{
-		counters: make(map[string]*prometheus.CounterVec),
-		gauges:   make(map[string]*prometheus.GaugeVec),
-		timers:   make(map[string]*prometheus.HistogramVec),
+		namespace:  namespace,
+		registerer: registerer,
+		counters:   make(map[string]*prometheus.CounterVec),
+		gauges:     make(map[string]*prometheus.GaugeVec),
+		timers:     make(map[string]*prometheus.HistogramVec),
+		histograms: make(map[string]*prometheus.HistogramVec),
+		labels:     make(map[string][]string),
 	}
 }
 
 func (m *prometheusMetrics) IncrementCounter(name string, tags ...string) {
+	m.mu.Lock()
 	counter, ok := m.counters[name]
 	if !ok {
 		counter = prometheus.NewCounterVec(
 			prometheus.CounterOpts{
-				Name: name,
+				Namespace: m.namespace,
+				Name:      name,
 			},
-			extractTagKeys(tags),
+			m.labelKeys(name, tags),
 		)
-		prometheus.MustRegister(counter)
+		m.register(counter)
 		m.counters[name] = counter
 	}
-	counter.WithLabelValues(extractTagValues(tags)...).Inc()
+	values := m.labelValues(name, tags)
+	m.mu.Unlock()
+
+	counter.WithLabelValues(values...).Inc()
 }
 
 func (m *prometheusMetrics) Gauge(name string, value float64, tags ...string) {
+	m.mu.Lock()
 	gauge, ok := m.gauges[name]
 	if !ok {
 		gauge = prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
-				Name: name,
+				Namespace: m.namespace,
+				Name:      name,
 			},
-			extractTagKeys(tags),
+			m.labelKeys(name, tags),
 		)
-		prometheus.MustRegister(gauge)
+		m.register(gauge)
 		m.gauges[name] = gauge
 	}
-	gauge.WithLabelValues(extractTagValues(tags)...).Set(value)
+	values := m.labelValues(name, tags)
+	m.mu.Unlock()
+
+	gauge.WithLabelValues(values...).Set(value)
 }
 
-func (m *prometheusMetrics) StartTimer(name string, tags ...string) Timer {
+func (m *prometheusMetrics) StartTimer(name string, tags ...string) output.Timer {
+	m.mu.Lock()
 	timer, ok := m.timers[name]
 	if !ok {
 		timer = prometheus.NewHistogramVec(
 			prometheus.HistogramOpts{
-				Name:    name,
-				Buckets: prometheus.DefBuckets,
+				Namespace: m.namespace,
+				Name:      name,
+				Buckets:   prometheus.DefBuckets,
 			},
-			extractTagKeys(tags),
+			m.labelKeys(name, tags),
 		)
-		prometheus.MustRegister(timer)
+		m.register(timer)
 		m.timers[name] = timer
 	}
-	
-	start := time.Now()
+	values := m.labelValues(name, tags)
+	m.mu.Unlock()
+
 	return &prometheusTimer{
-		start:     start,
-		histogram: timer,
-		tags:      tags,
+		start:    time.Now(),
+		observer: timer.WithLabelValues(values...),
 	}
 }
 
-type prometheusTimer struct {
-	start     time.Time
-	histogram *prometheus.HistogramVec
-	tags      []string
-}
+func (m *prometheusMetrics) Histogram(name string, value float64, tags ...string) {
+	m.mu.Lock()
+	histogram, ok := m.histograms[name]
+	if !ok {
+		histogram = prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: m.namespace,
+				Name:      name,
+				Buckets:   prometheus.ExponentialBuckets(0.1, 2, 16),
+			},
+			m.labelKeys(name, tags),
+		)
+		m.register(histogram)
+		m.histograms[name] = histogram
+	}
+	values := m.labelValues(name, tags)
+	m.mu.Unlock()
 
-func (t *prometheusTimer) Stop() {
-	duration := time.Since(t.start).Seconds()
-	t.histogram.WithLabelValues(extractTagValues(t.tags)...).Observe(duration)
+	histogram.WithLabelValues(values...).Observe(value)
 }
 
-func (t *prometheusTimer) Duration() float64 {
-	return time.Since(t.start).Seconds()
+// register 注册指标，已存在同名指标时沿用（如多个实例共享默认注册表）
+func (m *prometheusMetrics) register(c prometheus.Collector) {
+	if err := m.registerer.Register(c); err != nil {
+		if _, ok := err.(prometheus.AlreadyRegisteredError); !ok {
+			panic(err)
+		}
+	}
 }
 
-// 添加辅助方法
-func extractTagKeys(tags []string) []string {
+// labelKeys 记录并返回指标的标签名，需持有锁
+func (m *prometheusMetrics) labelKeys(name string, tags []string) []string {
+	if keys, ok := m.labels[name]; ok {
+		return keys
+	}
 	keys := make([]string, len(tags)/2)
-	for i := 0; i < len(tags); i += 2 {
+	for i := 0; i+1 < len(tags); i += 2 {
 		keys[i/2] = tags[i]
 	}
+	m.labels[name] = keys
 	return keys
 }
 
-func extractTagValues(tags []string) []string {
-	values := make([]string, len(tags)/2)
-	for i := 1; i < len(tags); i += 2 {
-		values[(i-1)/2] = tags[i]
+// labelValues 按首次注册的标签名取值，缺少的标签取空值，多余的标签忽略，需持有锁
+func (m *prometheusMetrics) labelValues(name string, tags []string) []string {
+	keys := m.labels[name]
+	values := make([]string, len(keys))
+	for i, key := range keys {
+		for j := 0; j+1 < len(tags); j += 2 {
+			if tags[j] == key {
+				values[i] = tags[j+1]
+				break
+			}
+		}
 	}
 	return values
 }
 
-// 实现其他方法... 
\ No newline at end of file
+type prometheusTimer struct {
+	start    time.Time
+	observer prometheus.Observer
+}
+
+func (t *prometheusTimer) Stop() {
+	t.observer.Observe(time.Since(t.start).Seconds())
+}
+
+func (t *prometheusTimer) Duration() float64 {
+	return time.Since(t.start).Seconds()
+}
@@ -0,0 +1,448 @@
+package mysql
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/pkg/tracer"
+)
+
+// genesisHash 哈希链的起点
+var genesisHash = strings.Repeat("0", 64)
+
+type auditLog struct {
+	db      *sql.DB
+	logger  output.Logger
+	metrics output.MetricsReporter
+}
+
+func NewAuditLog(db *sql.DB, logger output.Logger, metrics output.MetricsReporter) output.AuditLog {
+	return &auditLog{
+		db:      db,
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+func (l *auditLog) Append(ctx context.Context, entry *output.AuditEntry) error {
+	span, ctx := tracer.StartSpan(ctx, "auditLog.Append")
+	defer span.End()
+
+	timer := l.metrics.StartTimer("audit_append_duration")
+	defer timer.Stop()
+
+	if entry.ID == "" {
+		entry.ID = uuid.New().String()
+	}
+	if entry.OccurredAt.IsZero() {
+		entry.OccurredAt = time.Now()
+	}
+	entry.OccurredAt = entry.OccurredAt.UTC().Truncate(time.Microsecond)
+
+	// 审计记录不参与业务事务，命令回滚时仍需保留
+	tx, err := l.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// 锁住锚点，保证并发追加时哈希链连续
+	anchor, err := loadAuditAnchor(ctx, tx, true)
+	if err != nil {
+		return err
+	}
+	entry.PrevHash = anchor.headHash
+
+	if entry.ChangesHash, err = hashAuditChanges(entry.Changes); err != nil {
+		return err
+	}
+	entry.Hash = hashAuditEntry(entry)
+
+	var changes interface{}
+	if len(entry.Changes) > 0 {
+		data, err := json.Marshal(entry.Changes)
+		if err != nil {
+			return err
+		}
+		changes = data
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO audit_logs (
+			id, actor_id, impersonator_id, subject_id, action, result, error,
+			ip, user_agent, trace_id, changes, changes_hash, prev_hash, hash, occurred_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		entry.ID,
+		entry.ActorID,
+		entry.ImpersonatorID,
+		entry.SubjectID,
+		entry.Action,
+		entry.Result,
+		entry.Error,
+		entry.IP,
+		entry.UserAgent,
+		entry.TraceID,
+		changes,
+		entry.ChangesHash,
+		entry.PrevHash,
+		entry.Hash,
+		entry.OccurredAt,
+	)
+	if err != nil {
+		l.logger.Error("failed to append audit entry", "action", entry.Action, "error", err)
+		l.metrics.IncrementCounter("audit_append_failure")
+		return err
+	}
+
+	if entry.Sequence, err = result.LastInsertId(); err != nil {
+		return err
+	}
+
+	// 链尾记录在审计表之外，删除最新的记录可以被发现
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO audit_chain (id, head_seq, head_hash, purged_seq, purged_hash) VALUES (1, ?, ?, 0, ?)
+		ON DUPLICATE KEY UPDATE head_seq = VALUES(head_seq), head_hash = VALUES(head_hash)
+	`, entry.Sequence, entry.Hash, genesisHash); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	l.metrics.IncrementCounter("audit_append_success")
+	return nil
+}
+
+func (l *auditLog) Find(ctx context.Context, filter output.AuditFilter) ([]*output.AuditEntry, int64, error) {
+	span, ctx := tracer.StartSpan(ctx, "auditLog.Find")
+	defer span.End()
+
+	where, args := buildAuditWhere(filter)
+
+	var total int64
+	if err := l.db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM audit_logs"+where, args...,
+	).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := auditSelect + where + " ORDER BY seq DESC"
+	if filter.Limit > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, filter.Limit, filter.Offset)
+	}
+
+	rows, err := l.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	entries, err := scanAuditEntries(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+func (l *auditLog) Verify(ctx context.Context, from, to time.Time) (string, error) {
+	span, ctx := tracer.StartSpan(ctx, "auditLog.Verify")
+	defer span.End()
+
+	// 在同一快照中读取记录和锚点，避免与并发追加交错
+	tx, err := l.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	where, args := buildAuditWhere(output.AuditFilter{From: from, To: to})
+	rows, err := tx.QueryContext(ctx, auditSelect+where+" ORDER BY seq ASC", args...)
+	if err != nil {
+		return "", err
+	}
+	entries, err := scanAuditEntries(rows)
+	rows.Close()
+	if err != nil {
+		return "", err
+	}
+
+	anchor, err := loadAuditAnchor(ctx, tx, false)
+	if err != nil {
+		return "", err
+	}
+	if err := anchor.check(entries, from.IsZero(), to.IsZero()); err != nil {
+		l.logger.Error("audit chain truncated", "head_seq", anchor.headSeq, "purged_seq", anchor.purgedSeq)
+		l.metrics.IncrementCounter("audit_chain_broken")
+		return "", err
+	}
+
+	prev := ""
+	for _, entry := range entries {
+		// 区间内第一条记录的 prev_hash 无法在区间内校验，从它开始接续
+		if prev != "" && entry.PrevHash != prev {
+			l.metrics.IncrementCounter("audit_chain_broken")
+			return entry.ID, nil
+		}
+		// changes 被擦除后以记录的摘要参与校验，未擦除时摘要必须与内容一致
+		if len(entry.Changes) > 0 || entry.ChangesHash == "" {
+			digest, err := hashAuditChanges(entry.Changes)
+			if err != nil {
+				return "", err
+			}
+			if digest != entry.ChangesHash {
+				l.metrics.IncrementCounter("audit_chain_broken")
+				return entry.ID, nil
+			}
+		}
+		if hashAuditEntry(entry) != entry.Hash {
+			l.metrics.IncrementCounter("audit_chain_broken")
+			return entry.ID, nil
+		}
+		prev = entry.Hash
+	}
+
+	return "", nil
+}
+
+// RedactSubject 清空主体相关记录的 changes，触发器只允许这一种修改
+func (l *auditLog) RedactSubject(ctx context.Context, subjectID string) (int64, error) {
+	span, ctx := tracer.StartSpan(ctx, "auditLog.RedactSubject")
+	defer span.End()
+
+	result, err := conn(ctx, l.db).ExecContext(ctx,
+		"UPDATE audit_logs SET changes = NULL WHERE subject_id = ? AND changes IS NOT NULL",
+		subjectID,
+	)
+	if err != nil {
+		l.logger.Error("failed to redact audit entries", "subject_id", subjectID, "error", err)
+		return 0, err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	l.metrics.IncrementCounter("audit_entries_redacted")
+	return n, nil
+}
+
+func (l *auditLog) Purge(ctx context.Context, before time.Time) (int64, error) {
+	span, ctx := tracer.StartSpan(ctx, "auditLog.Purge")
+	defer span.End()
+
+	tx, err := l.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err := loadAuditAnchor(ctx, tx, true); err != nil {
+		return 0, err
+	}
+
+	// 按序号删除链首的连续一段，链中不会出现空洞
+	var (
+		lastSeq  int64
+		lastHash string
+	)
+	err = tx.QueryRowContext(ctx,
+		"SELECT seq, hash FROM audit_logs WHERE occurred_at < ? ORDER BY seq DESC LIMIT 1",
+		before,
+	).Scan(&lastSeq, &lastHash)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM audit_logs WHERE seq <= ?", lastSeq)
+	if err != nil {
+		l.logger.Error("failed to purge audit logs", "error", err)
+		return 0, err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	// 记录被删除部分的最后一条，剩余的第一条记录须接续它
+	if _, err := tx.ExecContext(ctx,
+		"UPDATE audit_chain SET purged_seq = ?, purged_hash = ? WHERE id = 1",
+		lastSeq, lastHash,
+	); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	l.logger.Info("audit logs purged", "before", before, "count", n)
+	l.metrics.Gauge("audit_purged_entries", float64(n))
+	return n, nil
+}
+
+// auditAnchor 保存在 audit_chain 表中的哈希链锚点
+type auditAnchor struct {
+	headSeq    int64
+	headHash   string
+	purgedSeq  int64
+	purgedHash string
+}
+
+// loadAuditAnchor 读取锚点，尚未写入过记录时返回创世锚点
+func loadAuditAnchor(ctx context.Context, q querier, forUpdate bool) (auditAnchor, error) {
+	query := "SELECT head_seq, head_hash, purged_seq, purged_hash FROM audit_chain WHERE id = 1"
+	if forUpdate {
+		query += " FOR UPDATE"
+	}
+
+	var a auditAnchor
+	err := q.QueryRowContext(ctx, query).Scan(&a.headSeq, &a.headHash, &a.purgedSeq, &a.purgedHash)
+	if err == sql.ErrNoRows {
+		return auditAnchor{headHash: genesisHash, purgedHash: genesisHash}, nil
+	}
+	return a, err
+}
+
+// check 比对链首和链尾，entries 须按序号升序
+func (a auditAnchor) check(entries []*output.AuditEntry, checkFirst, checkLast bool) error {
+	if len(entries) == 0 {
+		// 全部记录已清理时链尾等于清理锚点
+		if checkFirst && checkLast && a.headHash != a.purgedHash {
+			return output.ErrAuditChainTruncated
+		}
+		return nil
+	}
+	if checkFirst && entries[0].PrevHash != a.purgedHash {
+		return output.ErrAuditChainTruncated
+	}
+	if checkLast && entries[len(entries)-1].Hash != a.headHash {
+		return output.ErrAuditChainTruncated
+	}
+	return nil
+}
+
+const auditSelect = `
+	SELECT seq, id, actor_id, impersonator_id, subject_id, action, result, COALESCE(error, ''),
+		ip, user_agent, trace_id, changes, changes_hash, prev_hash, hash, occurred_at
+	FROM audit_logs`
+
+func buildAuditWhere(filter output.AuditFilter) (string, []interface{}) {
+	var (
+		conds []string
+		args  []interface{}
+	)
+	if filter.ActorID != "" {
+		// 按操作人查询时同时匹配模拟会话中的实际操作人
+		conds = append(conds, "(actor_id = ? OR impersonator_id = ?)")
+		args = append(args, filter.ActorID, filter.ActorID)
+	}
+	if filter.SubjectID != "" {
+		conds = append(conds, "subject_id = ?")
+		args = append(args, filter.SubjectID)
+	}
+	if filter.Action != "" {
+		conds = append(conds, "action = ?")
+		args = append(args, filter.Action)
+	}
+	if filter.Result != "" {
+		conds = append(conds, "result = ?")
+		args = append(args, filter.Result)
+	}
+	if !filter.From.IsZero() {
+		conds = append(conds, "occurred_at >= ?")
+		args = append(args, filter.From)
+	}
+	if !filter.To.IsZero() {
+		conds = append(conds, "occurred_at <= ?")
+		args = append(args, filter.To)
+	}
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+func scanAuditEntries(rows *sql.Rows) ([]*output.AuditEntry, error) {
+	var entries []*output.AuditEntry
+	for rows.Next() {
+		var (
+			entry   output.AuditEntry
+			changes []byte
+		)
+		if err := rows.Scan(
+			&entry.Sequence,
+			&entry.ID,
+			&entry.ActorID,
+			&entry.ImpersonatorID,
+			&entry.SubjectID,
+			&entry.Action,
+			&entry.Result,
+			&entry.Error,
+			&entry.IP,
+			&entry.UserAgent,
+			&entry.TraceID,
+			&changes,
+			&entry.ChangesHash,
+			&entry.PrevHash,
+			&entry.Hash,
+			&entry.OccurredAt,
+		); err != nil {
+			return nil, err
+		}
+		if len(changes) > 0 {
+			if err := json.Unmarshal(changes, &entry.Changes); err != nil {
+				return nil, err
+			}
+		}
+		entry.OccurredAt = entry.OccurredAt.UTC()
+		entries = append(entries, &entry)
+	}
+	return entries, rows.Err()
+}
+
+// hashAuditChanges 计算变更内容的摘要
+func hashAuditChanges(changes map[string]output.AuditChange) (string, error) {
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// hashAuditEntry 计算 sha256(prev_hash || 规范化记录)，变更内容以摘要参与计算
+func hashAuditEntry(entry *output.AuditEntry) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%d|",
+		entry.PrevHash,
+		entry.ID,
+		entry.ActorID,
+		entry.ImpersonatorID,
+		entry.SubjectID,
+		entry.Action,
+		entry.Result,
+		entry.Error,
+		entry.IP,
+		entry.UserAgent,
+		entry.TraceID,
+		entry.ChangesHash,
+		entry.OccurredAt.UnixMicro(),
+	)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,213 @@
+package mysql
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/testutil"
+)
+
+// auditChain 构造一条连续的哈希链
+func auditChain(t *testing.T, n int) []*output.AuditEntry {
+	t.Helper()
+	prev := genesisHash
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries := make([]*output.AuditEntry, n)
+	for i := range entries {
+		e := &output.AuditEntry{
+			ID:         string(rune('a' + i)),
+			Sequence:   int64(i + 1),
+			SubjectID:  "u1",
+			Action:     "UpdateUserProfileCommand",
+			Result:     output.AuditResultSuccess,
+			Changes:    map[string]output.AuditChange{"name": {Before: "Old", After: "Alice"}},
+			PrevHash:   prev,
+			OccurredAt: start.Add(time.Duration(i) * time.Minute),
+		}
+		var err error
+		e.ChangesHash, err = hashAuditChanges(e.Changes)
+		require.NoError(t, err)
+		e.Hash = hashAuditEntry(e)
+		prev = e.Hash
+		entries[i] = e
+	}
+	return entries
+}
+
+func auditRows(t *testing.T, entries []*output.AuditEntry) *sqlmock.Rows {
+	t.Helper()
+	rows := sqlmock.NewRows([]string{"seq", "id", "actor_id", "impersonator_id", "subject_id", "action", "result",
+		"error", "ip", "user_agent", "trace_id", "changes", "changes_hash", "prev_hash", "hash", "occurred_at"})
+	for _, e := range entries {
+		var changes []byte
+		if e.Changes != nil {
+			var err error
+			changes, err = json.Marshal(e.Changes)
+			require.NoError(t, err)
+		}
+		rows.AddRow(e.Sequence, e.ID, e.ActorID, e.ImpersonatorID, e.SubjectID, e.Action, e.Result,
+			e.Error, e.IP, e.UserAgent, e.TraceID, changes, e.ChangesHash, e.PrevHash, e.Hash, e.OccurredAt)
+	}
+	return rows
+}
+
+func anchorRow(seq int64, hash string, purgedSeq int64, purgedHash string) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"head_seq", "head_hash", "purged_seq", "purged_hash"}).
+		AddRow(seq, hash, purgedSeq, purgedHash)
+}
+
+func TestAuditLog_Verify(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(entries []*output.AuditEntry)
+		broken string
+	}{
+		{name: "intact chain", tamper: func([]*output.AuditEntry) {}},
+		{
+			name:   "redacted changes still verify",
+			tamper: func(entries []*output.AuditEntry) { entries[1].Changes = nil },
+		},
+		{
+			name: "modified changes are detected",
+			tamper: func(entries []*output.AuditEntry) {
+				entries[1].Changes["name"] = output.AuditChange{Before: "Old", After: "Mallory"}
+			},
+			broken: "b",
+		},
+		{
+			name:   "modified changes hash is detected",
+			tamper: func(entries []*output.AuditEntry) { entries[2].Changes, entries[2].ChangesHash = nil, genesisHash },
+			broken: "c",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			entries := auditChain(t, 3)
+			head := entries[2].Hash
+			tt.tamper(entries)
+			mock.ExpectBegin()
+			mock.ExpectQuery(regexp.QuoteMeta("FROM audit_logs")).WillReturnRows(auditRows(t, entries))
+			mock.ExpectQuery(regexp.QuoteMeta("FROM audit_chain")).WillReturnRows(anchorRow(3, head, 0, genesisHash))
+			mock.ExpectRollback()
+
+			log := NewAuditLog(db, testutil.NopLogger{}, testutil.NewMetrics())
+			broken, err := log.Verify(context.Background(), time.Time{}, time.Time{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.broken, broken)
+		})
+	}
+}
+
+func TestAuditLog_VerifyDetectsTruncation(t *testing.T) {
+	chain := auditChain(t, 4)
+
+	tests := []struct {
+		name     string
+		entries  []*output.AuditEntry
+		from, to time.Time
+		anchor   *sqlmock.Rows
+		wantErr  error
+	}{
+		{
+			name:    "purged prefix matches anchor",
+			entries: chain[2:],
+			anchor:  anchorRow(4, chain[3].Hash, 2, chain[1].Hash),
+		},
+		{
+			name:    "prefix deleted outside purge",
+			entries: chain[2:],
+			anchor:  anchorRow(4, chain[3].Hash, 0, genesisHash),
+			wantErr: output.ErrAuditChainTruncated,
+		},
+		{
+			name:    "latest entries deleted",
+			entries: chain[:2],
+			anchor:  anchorRow(4, chain[3].Hash, 0, genesisHash),
+			wantErr: output.ErrAuditChainTruncated,
+		},
+		{
+			name:    "all entries deleted",
+			anchor:  anchorRow(4, chain[3].Hash, 0, genesisHash),
+			wantErr: output.ErrAuditChainTruncated,
+		},
+		{
+			name:    "bounded range skips anchors",
+			entries: chain[1:3],
+			from:    chain[1].OccurredAt,
+			to:      chain[2].OccurredAt,
+			anchor:  anchorRow(4, chain[3].Hash, 0, genesisHash),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectBegin()
+			mock.ExpectQuery(regexp.QuoteMeta("FROM audit_logs")).WillReturnRows(auditRows(t, tt.entries))
+			mock.ExpectQuery(regexp.QuoteMeta("FROM audit_chain")).WillReturnRows(tt.anchor)
+			mock.ExpectRollback()
+
+			log := NewAuditLog(db, testutil.NopLogger{}, testutil.NewMetrics())
+			broken, err := log.Verify(context.Background(), tt.from, tt.to)
+			assert.Empty(t, broken)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestAuditLog_PurgeAdvancesAnchor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	before := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM audit_chain WHERE id = 1 FOR UPDATE")).
+		WillReturnRows(anchorRow(9, "head", 0, genesisHash))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT seq, hash FROM audit_logs WHERE occurred_at < ?")).
+		WithArgs(before).WillReturnRows(sqlmock.NewRows([]string{"seq", "hash"}).AddRow(5, "h5"))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM audit_logs WHERE seq <= ?")).
+		WithArgs(5).WillReturnResult(sqlmock.NewResult(0, 5))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE audit_chain SET purged_seq = ?, purged_hash = ?")).
+		WithArgs(5, "h5").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	n, err := NewAuditLog(db, testutil.NopLogger{}, testutil.NewMetrics()).Purge(context.Background(), before)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuditLog_RedactSubject(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE audit_logs SET changes = NULL WHERE subject_id = ?")).
+		WithArgs("u1").WillReturnResult(sqlmock.NewResult(0, 2))
+
+	n, err := NewAuditLog(db, testutil.NopLogger{}, testutil.NewMetrics()).RedactSubject(context.Background(), "u1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
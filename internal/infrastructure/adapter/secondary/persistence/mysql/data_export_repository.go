@@ -0,0 +1,155 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/pkg/errors"
+	"github.com/gohex/gohex/pkg/tracer"
+)
+
+type dataExportRepository struct {
+	db      *sql.DB
+	logger  output.Logger
+	metrics output.MetricsReporter
+}
+
+func NewDataExportRepository(db *sql.DB, logger output.Logger, metrics output.MetricsReporter) output.DataExportRepository {
+	return &dataExportRepository{
+		db:      db,
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+func (r *dataExportRepository) Save(ctx context.Context, export *output.DataExport) error {
+	span, ctx := tracer.StartSpan(ctx, "dataExportRepository.Save")
+	defer span.End()
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
+		INSERT INTO data_exports (id, user_id, requested_by, status, requested_at)
+		VALUES (?, ?, ?, ?, ?)
+	`,
+		export.ID,
+		export.UserID,
+		export.RequestedBy,
+		export.Status,
+		export.RequestedAt,
+	)
+	return err
+}
+
+func (r *dataExportRepository) Update(ctx context.Context, export *output.DataExport) error {
+	span, ctx := tracer.StartSpan(ctx, "dataExportRepository.Update")
+	defer span.End()
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE data_exports
+		SET status = ?, archive = ?, error = ?, completed_at = ?, expires_at = ?
+		WHERE id = ?
+	`,
+		export.Status,
+		export.Archive,
+		export.Error,
+		export.CompletedAt,
+		export.ExpiresAt,
+		export.ID,
+	)
+	return err
+}
+
+func (r *dataExportRepository) FindByID(ctx context.Context, id string) (*output.DataExport, error) {
+	span, ctx := tracer.StartSpan(ctx, "dataExportRepository.FindByID")
+	defer span.End()
+
+	var (
+		export    output.DataExport
+		exportErr sql.NullString
+	)
+	err := conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT id, user_id, requested_by, status, archive, error, requested_at, completed_at, expires_at
+		FROM data_exports WHERE id = ?
+	`, id).Scan(
+		&export.ID,
+		&export.UserID,
+		&export.RequestedBy,
+		&export.Status,
+		&export.Archive,
+		&exportErr,
+		&export.RequestedAt,
+		&export.CompletedAt,
+		&export.ExpiresAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("data export")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	export.Error = exportErr.String
+	return &export, nil
+}
+
+func (r *dataExportRepository) ClaimPending(ctx context.Context) (*output.DataExport, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// SKIP LOCKED 允许多个实例并行处理不同请求
+	var export output.DataExport
+	err = tx.QueryRowContext(ctx, `
+		SELECT id, user_id, requested_by, requested_at
+		FROM data_exports
+		WHERE status = ?
+		ORDER BY requested_at ASC
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	`, output.DataExportPending).Scan(
+		&export.ID,
+		&export.UserID,
+		&export.RequestedBy,
+		&export.RequestedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"UPDATE data_exports SET status = ? WHERE id = ?",
+		output.DataExportProcessing, export.ID,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	export.Status = output.DataExportProcessing
+	return &export, nil
+}
+
+// DeleteByUserID 擦除和永久删除用户时调用，在调用方的事务中执行
+func (r *dataExportRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, "DELETE FROM data_exports WHERE user_id = ?", userID)
+	return err
+}
+
+func (r *dataExportRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result, err := conn(ctx, r.db).ExecContext(ctx,
+		"DELETE FROM data_exports WHERE expires_at IS NOT NULL AND expires_at < ?",
+		now,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -1,14 +1,16 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/gohex/gohex/internal/infrastructure/config"
+	"github.com/gohex/gohex/pkg/uow"
 )
 
 func NewConnection(cfg config.DatabaseConfig) (*sql.DB, error) {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=true",
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=true&multiStatements=true",
 		cfg.Username,
 		cfg.Password,
 		cfg.Host,
@@ -30,4 +32,18 @@ func NewConnection(cfg config.DatabaseConfig) (*sql.DB, error) {
 	}
 
 	return db, nil
-} 
\ No newline at end of file
+} 
+// querier *sql.DB 和 *sql.Tx 共有的查询方法
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// conn 上下文中有工作单元的事务时加入该事务，否则直接使用连接池
+func conn(ctx context.Context, db *sql.DB) querier {
+	if tx, ok := uow.FromContext(ctx); ok {
+		return tx
+	}
+	return db
+}
@@ -0,0 +1,117 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/pkg/errors"
+	"github.com/gohex/gohex/pkg/tracer"
+)
+
+type emailChangeRepository struct {
+	db      *sql.DB
+	logger  output.Logger
+	metrics output.MetricsReporter
+}
+
+func NewEmailChangeRepository(db *sql.DB, logger output.Logger, metrics output.MetricsReporter) output.EmailChangeRepository {
+	return &emailChangeRepository{
+		db:      db,
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+// Save 保存变更请求，同一用户的旧请求会被替换
+func (r *emailChangeRepository) Save(ctx context.Context, change *output.PendingEmailChange) error {
+	span, ctx := tracer.StartSpan(ctx, "emailChangeRepository.Save")
+	defer span.End()
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
+		REPLACE INTO email_changes (
+			id, user_id, old_email, new_email, confirm_token_hash, cancel_token_hash, requested_at, expires_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		change.ID,
+		change.UserID,
+		change.OldEmail,
+		change.NewEmail,
+		change.ConfirmTokenHash,
+		change.CancelTokenHash,
+		change.RequestedAt,
+		change.ExpiresAt,
+	)
+	if err != nil {
+		r.logger.Error("failed to save email change", "user_id", change.UserID, "error", err)
+	}
+	return err
+}
+
+func (r *emailChangeRepository) FindByConfirmToken(ctx context.Context, tokenHash string) (*output.PendingEmailChange, error) {
+	span, ctx := tracer.StartSpan(ctx, "emailChangeRepository.FindByConfirmToken")
+	defer span.End()
+
+	return r.findOne(ctx, "confirm_token_hash", tokenHash)
+}
+
+func (r *emailChangeRepository) FindByCancelToken(ctx context.Context, tokenHash string) (*output.PendingEmailChange, error) {
+	span, ctx := tracer.StartSpan(ctx, "emailChangeRepository.FindByCancelToken")
+	defer span.End()
+
+	return r.findOne(ctx, "cancel_token_hash", tokenHash)
+}
+
+func (r *emailChangeRepository) Delete(ctx context.Context, id string) error {
+	span, ctx := tracer.StartSpan(ctx, "emailChangeRepository.Delete")
+	defer span.End()
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, "DELETE FROM email_changes WHERE id = ?", id)
+	return err
+}
+
+// DeleteByUserID 擦除和永久删除用户时调用，在调用方的事务中执行
+func (r *emailChangeRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	span, ctx := tracer.StartSpan(ctx, "emailChangeRepository.DeleteByUserID")
+	defer span.End()
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, "DELETE FROM email_changes WHERE user_id = ?", userID)
+	return err
+}
+
+func (r *emailChangeRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	span, ctx := tracer.StartSpan(ctx, "emailChangeRepository.DeleteExpired")
+	defer span.End()
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, "DELETE FROM email_changes WHERE expires_at <= ?", now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// findOne column 只会是上面两个固定列名
+func (r *emailChangeRepository) findOne(ctx context.Context, column, tokenHash string) (*output.PendingEmailChange, error) {
+	var change output.PendingEmailChange
+	err := conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT id, user_id, old_email, new_email, confirm_token_hash, cancel_token_hash, requested_at, expires_at
+		FROM email_changes WHERE `+column+` = ?
+	`, tokenHash).Scan(
+		&change.ID,
+		&change.UserID,
+		&change.OldEmail,
+		&change.NewEmail,
+		&change.ConfirmTokenHash,
+		&change.CancelTokenHash,
+		&change.RequestedAt,
+		&change.ExpiresAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, errors.ErrEmailChangeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
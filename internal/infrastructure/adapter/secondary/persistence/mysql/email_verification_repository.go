@@ -0,0 +1,89 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/pkg/errors"
+	"github.com/gohex/gohex/pkg/tracer"
+)
+
+type emailVerificationRepository struct {
+	db      *sql.DB
+	logger  output.Logger
+	metrics output.MetricsReporter
+}
+
+func NewEmailVerificationRepository(db *sql.DB, logger output.Logger, metrics output.MetricsReporter) output.EmailVerificationRepository {
+	return &emailVerificationRepository{
+		db:      db,
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+// Save 保存验证记录，同一用户的旧记录会被替换，旧链接随之失效
+func (r *emailVerificationRepository) Save(ctx context.Context, verification *output.EmailVerification) error {
+	span, ctx := tracer.StartSpan(ctx, "emailVerificationRepository.Save")
+	defer span.End()
+
+	_, err := r.db.ExecContext(ctx, `
+		REPLACE INTO email_verifications (id, user_id, email, token_hash, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`,
+		verification.ID,
+		verification.UserID,
+		verification.Email,
+		verification.TokenHash,
+		verification.CreatedAt,
+		verification.ExpiresAt,
+	)
+	if err != nil {
+		r.logger.Error("failed to save email verification", "user_id", verification.UserID, "error", err)
+	}
+	return err
+}
+
+func (r *emailVerificationRepository) FindByID(ctx context.Context, id string) (*output.EmailVerification, error) {
+	span, ctx := tracer.StartSpan(ctx, "emailVerificationRepository.FindByID")
+	defer span.End()
+
+	return r.findOne(ctx, "id = ?", id)
+}
+
+func (r *emailVerificationRepository) FindByToken(ctx context.Context, tokenHash string) (*output.EmailVerification, error) {
+	span, ctx := tracer.StartSpan(ctx, "emailVerificationRepository.FindByToken")
+	defer span.End()
+
+	return r.findOne(ctx, "token_hash = ?", tokenHash)
+}
+
+func (r *emailVerificationRepository) findOne(ctx context.Context, cond string, arg interface{}) (*output.EmailVerification, error) {
+	var verification output.EmailVerification
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, user_id, email, token_hash, created_at, expires_at
+		FROM email_verifications WHERE `+cond, arg).Scan(
+		&verification.ID,
+		&verification.UserID,
+		&verification.Email,
+		&verification.TokenHash,
+		&verification.CreatedAt,
+		&verification.ExpiresAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, errors.ErrEmailVerificationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &verification, nil
+}
+
+func (r *emailVerificationRepository) Delete(ctx context.Context, id string) error {
+	span, ctx := tracer.StartSpan(ctx, "emailVerificationRepository.Delete")
+	defer span.End()
+
+	_, err := r.db.ExecContext(ctx, "DELETE FROM email_verifications WHERE id = ?", id)
+	return err
+}
@@ -4,40 +4,66 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"strings"
 	"time"
 	"github.com/google/uuid"
 	"github.com/gohex/gohex/internal/domain/event"
-	"github.com/gohex/gohex/internal/application/port"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/pkg/crypto"
 	"github.com/gohex/gohex/pkg/errors"
 	"github.com/gohex/gohex/pkg/tracer"
+	"github.com/gohex/gohex/pkg/uow"
 )
 
-type EventStore interface {
-	port.EventStore
-	MarkEventsAsPublished(ctx context.Context, aggregateID string, version int) error
-}
-
 type eventStore struct {
-	db      *sql.DB
-	logger  Logger
-	metrics MetricsReporter
+	db       *sql.DB
+	keyStore output.KeyStore // 为空时不加密事件数据
+	logger   output.Logger
+	metrics  output.MetricsReporter
 }
 
+const eventColumns = `id, aggregate_id, type, version, schema_version, data, encrypted, redacted, occurred_at,
+	actor_id, impersonator_id, correlation_id, causation_id`
+
 type eventModel struct {
-	ID           string          `db:"id"`
-	AggregateID  string          `db:"aggregate_id"`
-	Type         string          `db:"type"`
-	Version      int             `db:"version"`
-	Data         json.RawMessage `db:"data"`
-	OccurredAt   time.Time       `db:"occurred_at"`
-	PublishedAt  *time.Time      `db:"published_at"`
+	ID             string          `db:"id"`
+	AggregateID    string          `db:"aggregate_id"`
+	Type           string          `db:"type"`
+	Version        int             `db:"version"`
+	SchemaVersion  int             `db:"schema_version"`
+	Data           json.RawMessage `db:"data"`
+	Encrypted      bool            `db:"encrypted"`
+	Redacted       bool            `db:"redacted"`
+	OccurredAt     time.Time       `db:"occurred_at"`
+	ActorID        sql.NullString  `db:"actor_id"`
+	ImpersonatorID sql.NullString  `db:"impersonator_id"`
+	CorrelationID  sql.NullString  `db:"correlation_id"`
+	CausationID    sql.NullString  `db:"causation_id"`
 }
 
-func NewEventStore(db *sql.DB, logger Logger, metrics MetricsReporter) EventStore {
+func (m *eventModel) header() event.Header {
+	return event.Header{
+		ID:               m.ID,
+		Type:             m.Type,
+		AggregateID:      m.AggregateID,
+		AggregateVersion: m.Version,
+		SchemaVersion:    m.SchemaVersion,
+		OccurredAt:       m.OccurredAt,
+		Metadata: event.Metadata{
+			ActorID:        m.ActorID.String,
+			ImpersonatorID: m.ImpersonatorID.String,
+			CorrelationID:  m.CorrelationID.String,
+			CausationID:    m.CausationID.String,
+		},
+	}
+}
+
+func NewEventStore(db *sql.DB, keyStore output.KeyStore, logger output.Logger, metrics output.MetricsReporter) output.EventStore {
 	return &eventStore{
-		db:      db,
-		logger:  logger,
-		metrics: metrics,
+		db:       db,
+		keyStore: keyStore,
+		logger:   logger,
+		metrics:  metrics,
 	}
 }
 
@@ -45,15 +71,21 @@ func (s *eventStore) SaveEvents(ctx context.Context, aggregateID string, events
 	span, ctx := tracer.StartSpan(ctx, "eventStore.SaveEvents")
 	defer span.End()
 
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
-		return err
+	// 在工作单元内保存时加入其事务，否则单独开启事务；
+	// 新建的主体密钥与事件在同一事务中写入
+	tx, ok := uow.FromContext(ctx)
+	if !ok {
+		var err error
+		if tx, err = s.db.BeginTx(ctx, nil); err != nil {
+			return err
+		}
+		defer tx.Rollback()
+		ctx = uow.WithTransaction(ctx, tx)
 	}
-	defer tx.Rollback()
 
 	// 检查版本
 	var currentVersion int
-	err = tx.QueryRowContext(ctx, 
+	err := tx.QueryRowContext(ctx,
 		"SELECT COALESCE(MAX(version), 0) FROM events WHERE aggregate_id = ?",
 		aggregateID,
 	).Scan(&currentVersion)
@@ -63,13 +95,14 @@ func (s *eventStore) SaveEvents(ctx context.Context, aggregateID string, events
 	}
 
 	if currentVersion != expectedVersion {
-		return ErrConcurrencyConflict
+		return errors.ErrConcurrencyConflict
 	}
 
-	// 保存事件
+	// 保存事件，同一对象随后发布到事件总线，发起者和版本在此确定
+	event.Stamp(ctx, events...)
 	stmt, err := tx.PrepareContext(ctx, `
-		INSERT INTO events (id, aggregate_id, type, version, data, occurred_at)
-		VALUES (?, ?, ?, ?, ?, ?)
+		INSERT INTO events (`+eventColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, FALSE, ?, ?, ?, ?, ?)
 	`)
 	if err != nil {
 		return err
@@ -77,24 +110,54 @@ func (s *eventStore) SaveEvents(ctx context.Context, aggregateID string, events
 	defer stmt.Close()
 
 	for i, e := range events {
-		data, err := json.Marshal(e.Data())
+		version := expectedVersion + i + 1
+		if m, ok := e.(event.Mutable); ok {
+			m.SetAggregateVersion(version)
+		}
+
+		data, err := json.Marshal(e)
 		if err != nil {
 			return err
 		}
 
+		// 不携带个人数据的事件以明文保存，擦除记录不会随密钥一起粉碎
+		encrypted := false
+		if _, anonymous := e.(event.Anonymous); s.keyStore != nil && !anonymous {
+			if data, err = s.encrypt(ctx, e.AggregateID(), data); err != nil {
+				return err
+			}
+			encrypted = true
+		}
+
+		// 使用事件自身的 ID，读取和转发时保持不变
+		id := e.ID()
+		if id == "" {
+			id = uuid.New().String()
+		}
+
+		md := e.Metadata()
 		_, err = stmt.ExecContext(ctx,
-			uuid.New().String(),
+			id,
 			e.AggregateID(),
 			e.Type(),
-			expectedVersion+i+1,
+			version,
+			e.SchemaVersion(),
 			data,
+			encrypted,
 			e.OccurredAt(),
+			sql.NullString{String: md.ActorID, Valid: md.ActorID != ""},
+			sql.NullString{String: md.ImpersonatorID, Valid: md.ImpersonatorID != ""},
+			sql.NullString{String: md.CorrelationID, Valid: md.CorrelationID != ""},
+			sql.NullString{String: md.CausationID, Valid: md.CausationID != ""},
 		)
 		if err != nil {
 			return err
 		}
 	}
 
+	if ok {
+		return nil
+	}
 	return tx.Commit()
 }
 
@@ -103,7 +166,7 @@ func (s *eventStore) GetEvents(ctx context.Context, aggregateID string) ([]event
 	defer span.End()
 
 	query := `
-		SELECT id, aggregate_id, type, version, data, occurred_at
+		SELECT ` + eventColumns + `
 		FROM events
 		WHERE aggregate_id = ?
 		ORDER BY version ASC
@@ -123,34 +186,108 @@ func (s *eventStore) GetEvents(ctx context.Context, aggregateID string) ([]event
 			&model.AggregateID,
 			&model.Type,
 			&model.Version,
+			&model.SchemaVersion,
 			&model.Data,
+			&model.Encrypted,
+			&model.Redacted,
 			&model.OccurredAt,
+			&model.ActorID,
+			&model.ImpersonatorID,
+			&model.CorrelationID,
+			&model.CausationID,
 		)
 		if err != nil {
 			return nil, err
 		}
 
-		event, err := s.deserializeEvent(&model)
+		if model.Redacted {
+			// 启用加密前写入的事件已在擦除时清空
+			events = append(events, event.NewRawEvent(model.header(), nil))
+			continue
+		}
+
+		if model.Encrypted {
+			model.Data, err = s.decrypt(ctx, model.AggregateID, model.Data)
+			if err == output.ErrKeyShredded {
+				// 用户已被擦除，只保留事件描述信息
+				events = append(events, event.NewRawEvent(model.header(), nil))
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		evt, err := event.Decode(model.header(), model.Data)
 		if err != nil {
 			return nil, err
 		}
-		events = append(events, event)
+		events = append(events, evt)
 	}
 
-	return events, nil
+	return events, rows.Err()
 }
 
-func (s *eventStore) deserializeEvent(model *eventModel) (event.Event, error) {
-	// 根据事件类型反序列化数据
-	switch model.Type {
-	case event.UserCreated:
-		var data event.UserCreatedEvent
-		if err := json.Unmarshal(model.Data, &data); err != nil {
-			return nil, err
+func (s *eventStore) RedactEvents(ctx context.Context, aggregateID string) (int64, error) {
+	span, ctx := tracer.StartSpan(ctx, "eventStore.RedactEvents")
+	defer span.End()
+
+	query := `
+		UPDATE events SET data = JSON_OBJECT(), redacted = TRUE
+		WHERE aggregate_id = ? AND encrypted = FALSE AND redacted = FALSE`
+	args := []interface{}{aggregateID}
+	if types := event.AnonymousTypes(); len(types) > 0 {
+		query += " AND type NOT IN (?" + strings.Repeat(", ?", len(types)-1) + ")"
+		for _, t := range types {
+			args = append(args, t)
 		}
-		return &data, nil
-	// ... 其他事件类型的处理
-	default:
-		return nil, ErrUnknownEventType
 	}
-} 
\ No newline at end of file
+
+	result, err := conn(ctx, s.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		s.logger.Error("failed to redact events", "aggregate_id", aggregateID, "error", err)
+		return 0, err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	s.metrics.IncrementCounter("events_redacted")
+	return n, nil
+}
+
+// encrypt 使用聚合（用户）自己的密钥加密事件数据，删除密钥即可粉碎其中的个人信息
+func (s *eventStore) encrypt(ctx context.Context, aggregateID string, data []byte) ([]byte, error) {
+	key, err := s.keyStore.GetOrCreateKey(ctx, aggregateID)
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext, err := crypto.Encrypt(key, data, []byte(aggregateID))
+	if err != nil {
+		return nil, err
+	}
+
+	// data 列为 JSON 类型，密文以 base64 字符串存储
+	return json.Marshal(ciphertext)
+}
+
+func (s *eventStore) decrypt(ctx context.Context, aggregateID string, data []byte) ([]byte, error) {
+	if s.keyStore == nil {
+		return nil, output.ErrKeyShredded
+	}
+
+	var ciphertext []byte
+	if err := json.Unmarshal(data, &ciphertext); err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyStore.GetKey(ctx, aggregateID)
+	if err != nil {
+		return nil, err
+	}
+
+	return crypto.Decrypt(key, ciphertext, []byte(aggregateID))
+}
@@ -0,0 +1,132 @@
+package mysql
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/domain/event"
+	"github.com/gohex/gohex/internal/testutil"
+	"github.com/gohex/gohex/pkg/uow"
+)
+
+// plaintext 匹配未加密的事件数据，即合法的 JSON 对象
+type plaintext bool
+
+func (p plaintext) Match(v driver.Value) bool {
+	data, ok := v.([]byte)
+	if !ok {
+		return false
+	}
+	var obj map[string]interface{}
+	return (json.Unmarshal(data, &obj) == nil) == bool(p)
+}
+
+func TestEventStore_SaveEventsJoinsUnitOfWork(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	keys := testutil.NewKeyStore()
+	store := NewEventStore(db, keys, testutil.NopLogger{}, testutil.NewMetrics())
+
+	mock.ExpectBegin()
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	ctx := uow.WithTransaction(context.Background(), tx)
+
+	// 已在工作单元内时不再开启和提交事务
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COALESCE(MAX(version), 0) FROM events")).
+		WithArgs("u1").WillReturnRows(sqlmock.NewRows([]string{"v"}).AddRow(1))
+	prep := mock.ExpectPrepare(regexp.QuoteMeta("INSERT INTO events"))
+	anyArg := sqlmock.AnyArg()
+	prep.ExpectExec().
+		WithArgs(anyArg, "u1", event.UserProfileUpdated, 2, 1, plaintext(false), true, anyArg, anyArg, anyArg, anyArg, anyArg).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	prep.ExpectExec().
+		WithArgs(anyArg, "u1", event.UserErased, 3, 1, plaintext(true), false, anyArg, anyArg, anyArg, anyArg, anyArg).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = store.SaveEvents(ctx, "u1", []event.Event{
+		event.NewUserProfileUpdatedEvent("u1", "Alice", ""),
+		event.NewUserErasedEvent("u1", "admin"),
+	}, 1)
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEventStore_GetEventsAfterErasure(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	// u1 的密钥已粉碎
+	store := NewEventStore(db, testutil.NewKeyStore(), testutil.NopLogger{}, testutil.NewMetrics())
+
+	ciphertext, _ := json.Marshal([]byte("ciphertext"))
+	erased, _ := json.Marshal(map[string]string{"requested_by": "admin"})
+	now := time.Now()
+	columns := []string{"id", "aggregate_id", "type", "version", "schema_version", "data", "encrypted", "redacted",
+		"occurred_at", "actor_id", "impersonator_id", "correlation_id", "causation_id"}
+	rows := sqlmock.NewRows(columns).
+		AddRow("e1", "u1", event.UserCreated, 1, 1, []byte(`{}`), false, true, now, nil, nil, nil, nil).
+		AddRow("e2", "u1", event.UserProfileUpdated, 2, 1, ciphertext, true, false, now, nil, nil, nil, nil).
+		AddRow("e3", "u1", event.UserErased, 3, 1, erased, false, false, now, nil, nil, nil, nil)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM events")).WithArgs("u1").WillReturnRows(rows)
+
+	events, err := store.GetEvents(context.Background(), "u1")
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.IsType(t, &event.RawEvent{}, events[0])
+	assert.IsType(t, &event.RawEvent{}, events[1])
+	require.IsType(t, &event.UserErasedEvent{}, events[2])
+	assert.Equal(t, "admin", events[2].(*event.UserErasedEvent).RequestedBy)
+}
+
+func TestEventStore_RedactEventsKeepsAnonymousEvents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := NewEventStore(db, nil, testutil.NopLogger{}, testutil.NewMetrics())
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE events SET data = JSON_OBJECT(), redacted = TRUE")).
+		WithArgs("u1", event.UserErased, event.UserPurged).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+
+	n, err := store.RedactEvents(context.Background(), "u1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEventStore_SaveEventsKeepsEventID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := NewEventStore(db, testutil.NewKeyStore(), testutil.NopLogger{}, testutil.NewMetrics())
+	evt := event.NewUserErasedEvent("u1", "admin")
+
+	mock.ExpectBegin()
+	tx, err := db.Begin()
+	require.NoError(t, err)
+
+	// 行 ID 即事件 ID，读取和转发时去重键保持不变
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COALESCE(MAX(version), 0) FROM events")).
+		WithArgs("u1").WillReturnRows(sqlmock.NewRows([]string{"v"}).AddRow(0))
+	anyArg := sqlmock.AnyArg()
+	mock.ExpectPrepare(regexp.QuoteMeta("INSERT INTO events")).ExpectExec().
+		WithArgs(evt.ID(), "u1", event.UserErased, 1, anyArg, anyArg, anyArg, anyArg, anyArg, anyArg, anyArg, anyArg).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, store.SaveEvents(uow.WithTransaction(context.Background(), tx), "u1", []event.Event{evt}, 0))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
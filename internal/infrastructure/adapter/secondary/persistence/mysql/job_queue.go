@@ -0,0 +1,282 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/pkg/errors"
+	"github.com/gohex/gohex/pkg/tracer"
+)
+
+const jobColumns = `id, command_type, payload, status, progress, result, error, requested_by,
+	impersonator_id, ip, user_agent, cancel_requested, attempts, created_at, started_at, finished_at, heartbeat_at`
+
+type jobQueue struct {
+	db      *sql.DB
+	logger  output.Logger
+	metrics output.MetricsReporter
+}
+
+func NewJobQueue(db *sql.DB, logger output.Logger, metrics output.MetricsReporter) output.JobQueue {
+	return &jobQueue{
+		db:      db,
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+func (q *jobQueue) Enqueue(ctx context.Context, job *output.Job) error {
+	span, ctx := tracer.StartSpan(ctx, "jobQueue.Enqueue")
+	defer span.End()
+
+	_, err := q.db.ExecContext(ctx, `
+		INSERT INTO jobs (id, command_type, payload, status, requested_by, impersonator_id, ip, user_agent, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		job.ID,
+		job.CommandType,
+		job.Payload,
+		job.Status,
+		job.RequestedBy,
+		sql.NullString{String: job.ImpersonatorID, Valid: job.ImpersonatorID != ""},
+		job.IP,
+		job.UserAgent,
+		job.CreatedAt,
+	)
+	if err != nil {
+		q.metrics.IncrementCounter("job_enqueue_failure")
+		return err
+	}
+
+	q.metrics.IncrementCounter("job_enqueued", "command_type", job.CommandType)
+	return nil
+}
+
+func (q *jobQueue) FindByID(ctx context.Context, id string) (*output.Job, error) {
+	span, ctx := tracer.StartSpan(ctx, "jobQueue.FindByID")
+	defer span.End()
+
+	job, err := scanJob(q.db.QueryRowContext(ctx, "SELECT "+jobColumns+" FROM jobs WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("job")
+	}
+	return job, err
+}
+
+func (q *jobQueue) Claim(ctx context.Context) (*output.Job, error) {
+	tx, err := q.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// SKIP LOCKED 允许多个实例并行领取不同任务
+	job, err := scanJob(tx.QueryRowContext(ctx, `
+		SELECT `+jobColumns+`
+		FROM jobs
+		WHERE status = ?
+		ORDER BY created_at ASC
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	`, output.JobPending))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if _, err := tx.ExecContext(ctx,
+		"UPDATE jobs SET status = ?, started_at = ?, heartbeat_at = ?, attempts = attempts + 1 WHERE id = ?",
+		output.JobRunning, now, now, job.ID,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	job.Status = output.JobRunning
+	job.Attempts++
+	job.StartedAt = &now
+	job.HeartbeatAt = &now
+	return job, nil
+}
+
+func (q *jobQueue) Heartbeat(ctx context.Context, id string, progress int) (bool, error) {
+	if _, err := q.db.ExecContext(ctx,
+		"UPDATE jobs SET heartbeat_at = ?, progress = ? WHERE id = ? AND status = ?",
+		time.Now(), progress, id, output.JobRunning,
+	); err != nil {
+		return false, err
+	}
+
+	var cancelRequested bool
+	err := q.db.QueryRowContext(ctx, "SELECT cancel_requested FROM jobs WHERE id = ?", id).Scan(&cancelRequested)
+	if err == sql.ErrNoRows {
+		return false, errors.NewNotFoundError("job")
+	}
+	return cancelRequested, err
+}
+
+func (q *jobQueue) Complete(ctx context.Context, id string, result []byte) error {
+	return q.finish(ctx, id, output.JobSucceeded, result, "")
+}
+
+func (q *jobQueue) Fail(ctx context.Context, id string, reason string) error {
+	return q.finish(ctx, id, output.JobFailed, nil, reason)
+}
+
+func (q *jobQueue) MarkCancelled(ctx context.Context, id string) error {
+	return q.finish(ctx, id, output.JobCancelled, nil, "")
+}
+
+// finish 只更新运行中的任务，避免覆盖已取消或已被其他实例重新领取的结果
+func (q *jobQueue) finish(ctx context.Context, id, status string, result []byte, reason string) error {
+	_, err := q.db.ExecContext(ctx, `
+		UPDATE jobs
+		SET status = ?, result = ?, error = ?, progress = IF(? = ?, 100, progress), finished_at = ?
+		WHERE id = ? AND status = ?
+	`,
+		status,
+		result,
+		reason,
+		status, output.JobSucceeded,
+		time.Now(),
+		id,
+		output.JobRunning,
+	)
+	if err != nil {
+		return err
+	}
+
+	q.metrics.IncrementCounter("job_finished", "status", status)
+	return nil
+}
+
+func (q *jobQueue) Cancel(ctx context.Context, id string) error {
+	span, ctx := tracer.StartSpan(ctx, "jobQueue.Cancel")
+	defer span.End()
+
+	tx, err := q.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var status string
+	err = tx.QueryRowContext(ctx, "SELECT status FROM jobs WHERE id = ? FOR UPDATE", id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return errors.NewNotFoundError("job")
+	}
+	if err != nil {
+		return err
+	}
+
+	switch status {
+	case output.JobPending:
+		_, err = tx.ExecContext(ctx,
+			"UPDATE jobs SET status = ?, finished_at = ? WHERE id = ?",
+			output.JobCancelled, time.Now(), id,
+		)
+	case output.JobRunning:
+		_, err = tx.ExecContext(ctx, "UPDATE jobs SET cancel_requested = 1 WHERE id = ?", id)
+	default:
+		return errors.ErrJobNotCancellable
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (q *jobQueue) DeleteFinished(ctx context.Context, before time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx,
+		"DELETE FROM jobs WHERE status IN (?, ?, ?, ?) AND finished_at < ?",
+		output.JobSucceeded, output.JobFailed, output.JobCancelled, output.JobDeadLettered, before,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (q *jobQueue) RequeueStale(ctx context.Context, before time.Time, maxAttempts int) (int64, int64, error) {
+	now := time.Now()
+
+	// 已请求取消的任务直接结束，不再重新执行
+	if _, err := q.db.ExecContext(ctx, `
+		UPDATE jobs SET status = ?, finished_at = ?
+		WHERE status = ? AND cancel_requested = 1 AND heartbeat_at < ?
+	`, output.JobCancelled, now, output.JobRunning, before); err != nil {
+		return 0, 0, err
+	}
+
+	// 领取次数达到上限的任务很可能每次都导致实例退出，转为死信避免无限重试
+	result, err := q.db.ExecContext(ctx, `
+		UPDATE jobs SET status = ?, error = ?, finished_at = ?
+		WHERE status = ? AND heartbeat_at < ? AND attempts >= ?
+	`, output.JobDeadLettered, deadLetterReason(maxAttempts), now, output.JobRunning, before, maxAttempts)
+	if err != nil {
+		return 0, 0, err
+	}
+	deadLettered, err := result.RowsAffected()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	result, err = q.db.ExecContext(ctx, `
+		UPDATE jobs SET status = ?, started_at = NULL, heartbeat_at = NULL
+		WHERE status = ? AND heartbeat_at < ? AND attempts < ?
+	`, output.JobPending, output.JobRunning, before, maxAttempts)
+	if err != nil {
+		return 0, deadLettered, err
+	}
+	requeued, err := result.RowsAffected()
+	return requeued, deadLettered, err
+}
+
+// deadLetterReason 死信任务记录的错误信息
+func deadLetterReason(maxAttempts int) string {
+	return fmt.Sprintf("job abandoned after %d attempts without completing", maxAttempts)
+}
+
+func scanJob(row interface{ Scan(...interface{}) error }) (*output.Job, error) {
+	var (
+		job            output.Job
+		jobErr         sql.NullString
+		impersonatorID sql.NullString
+	)
+	err := row.Scan(
+		&job.ID,
+		&job.CommandType,
+		&job.Payload,
+		&job.Status,
+		&job.Progress,
+		&job.Result,
+		&jobErr,
+		&job.RequestedBy,
+		&impersonatorID,
+		&job.IP,
+		&job.UserAgent,
+		&job.CancelRequested,
+		&job.Attempts,
+		&job.CreatedAt,
+		&job.StartedAt,
+		&job.FinishedAt,
+		&job.HeartbeatAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	job.Error = jobErr.String
+	job.ImpersonatorID = impersonatorID.String
+	return &job, nil
+}
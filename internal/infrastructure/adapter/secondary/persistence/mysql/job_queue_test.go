@@ -0,0 +1,70 @@
+package mysql
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/testutil"
+)
+
+func TestJobQueue_ClaimCountsAttempts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "command_type", "payload", "status", "progress", "result", "error",
+		"requested_by", "impersonator_id", "ip", "user_agent", "cancel_requested", "attempts",
+		"created_at", "started_at", "finished_at", "heartbeat_at"}).
+		AddRow("j1", "command.BulkAssignRoleCommand", []byte(`{}`), output.JobPending, 40, nil, nil,
+			"alice", nil, "203.0.113.7", "curl/8.0", false, 1, created, nil, nil, nil)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("FOR UPDATE SKIP LOCKED")).
+		WithArgs(output.JobPending).
+		WillReturnRows(rows)
+	mock.ExpectExec(regexp.QuoteMeta("attempts = attempts + 1")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	queue := NewJobQueue(db, testutil.NopLogger{}, testutil.NewMetrics())
+	job, err := queue.Claim(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	assert.Equal(t, output.JobRunning, job.Status)
+	assert.Equal(t, 2, job.Attempts)
+	assert.Equal(t, "203.0.113.7", job.IP)
+	assert.Equal(t, "curl/8.0", job.UserAgent)
+}
+
+func TestJobQueue_RequeueStaleDeadLettersExhaustedJobs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	before := time.Now().Add(-time.Minute)
+	mock.ExpectExec(regexp.QuoteMeta("cancel_requested = 1")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("attempts >= ?")).
+		WithArgs(output.JobDeadLettered, deadLetterReason(3), sqlmock.AnyArg(), output.JobRunning, before, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("attempts < ?")).
+		WithArgs(output.JobPending, output.JobRunning, before, 3).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	queue := NewJobQueue(db, testutil.NopLogger{}, testutil.NewMetrics())
+	requeued, deadLettered, err := queue.RequeueStale(context.Background(), before, 3)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	assert.Equal(t, int64(2), requeued)
+	assert.Equal(t, int64(1), deadLettered)
+}
@@ -0,0 +1,96 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/pkg/crypto"
+	"github.com/gohex/gohex/pkg/tracer"
+)
+
+// keyStore 将每个用户的数据密钥用主密钥包裹后存储在 MySQL
+type keyStore struct {
+	db        *sql.DB
+	masterKey []byte
+	logger    output.Logger
+	metrics   output.MetricsReporter
+}
+
+func NewKeyStore(db *sql.DB, masterKey []byte, logger output.Logger, metrics output.MetricsReporter) output.KeyStore {
+	return &keyStore{
+		db:        db,
+		masterKey: masterKey,
+		logger:    logger,
+		metrics:   metrics,
+	}
+}
+
+func (s *keyStore) GetOrCreateKey(ctx context.Context, subjectID string) ([]byte, error) {
+	span, ctx := tracer.StartSpan(ctx, "keyStore.GetOrCreateKey")
+	defer span.End()
+
+	key, err := s.GetKey(ctx, subjectID)
+	if err == nil {
+		return key, nil
+	}
+	if err != output.ErrKeyShredded {
+		return nil, err
+	}
+
+	key, err = crypto.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	wrapped, err := crypto.Encrypt(s.masterKey, key, []byte(subjectID))
+	if err != nil {
+		return nil, err
+	}
+
+	// 并发创建时以先写入者为准；在工作单元内创建时随事务一起提交或回滚
+	if _, err := conn(ctx, s.db).ExecContext(ctx,
+		"INSERT IGNORE INTO encryption_keys (subject_id, wrapped_key) VALUES (?, ?)",
+		subjectID, wrapped,
+	); err != nil {
+		s.logger.Error("failed to store encryption key", "subject_id", subjectID, "error", err)
+		return nil, err
+	}
+
+	s.metrics.IncrementCounter("encryption_key_created")
+	return s.GetKey(ctx, subjectID)
+}
+
+func (s *keyStore) GetKey(ctx context.Context, subjectID string) ([]byte, error) {
+	var wrapped []byte
+	err := conn(ctx, s.db).QueryRowContext(ctx,
+		"SELECT wrapped_key FROM encryption_keys WHERE subject_id = ?",
+		subjectID,
+	).Scan(&wrapped)
+	if err == sql.ErrNoRows {
+		return nil, output.ErrKeyShredded
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return crypto.Decrypt(s.masterKey, wrapped, []byte(subjectID))
+}
+
+func (s *keyStore) DeleteKey(ctx context.Context, subjectID string) error {
+	span, ctx := tracer.StartSpan(ctx, "keyStore.DeleteKey")
+	defer span.End()
+
+	// 在工作单元内删除，擦除失败回滚时密钥仍然保留
+	if _, err := conn(ctx, s.db).ExecContext(ctx,
+		"DELETE FROM encryption_keys WHERE subject_id = ?",
+		subjectID,
+	); err != nil {
+		s.logger.Error("failed to shred encryption key", "subject_id", subjectID, "error", err)
+		return err
+	}
+
+	s.logger.Info("encryption key shredded", "subject_id", subjectID)
+	s.metrics.IncrementCounter("encryption_key_shredded")
+	return nil
+}
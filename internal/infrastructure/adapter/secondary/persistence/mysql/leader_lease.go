@@ -0,0 +1,51 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+)
+
+type leaderLease struct {
+	db      *sql.DB
+	logger  output.Logger
+	metrics output.MetricsReporter
+}
+
+func NewLeaderLease(db *sql.DB, logger output.Logger, metrics output.MetricsReporter) output.LeaderLease {
+	return &leaderLease{
+		db:      db,
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+func (l *leaderLease) TryAcquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	// 租约过期或本就由自己持有时接管/续约，时间以数据库为准避免实例间时钟偏差
+	_, err := l.db.ExecContext(ctx, `
+		INSERT INTO leader_leases (name, holder, expires_at)
+		VALUES (?, ?, NOW(3) + INTERVAL ? MICROSECOND)
+		ON DUPLICATE KEY UPDATE
+			holder = IF(expires_at < NOW(3) OR holder = VALUES(holder), VALUES(holder), holder),
+			expires_at = IF(holder = VALUES(holder), VALUES(expires_at), expires_at)
+	`, name, holder, ttl.Microseconds())
+	if err != nil {
+		return false, err
+	}
+
+	var current string
+	if err := l.db.QueryRowContext(ctx,
+		"SELECT holder FROM leader_leases WHERE name = ?", name,
+	).Scan(&current); err != nil {
+		return false, err
+	}
+	return current == holder, nil
+}
+
+func (l *leaderLease) Release(ctx context.Context, name, holder string) error {
+	_, err := l.db.ExecContext(ctx,
+		"DELETE FROM leader_leases WHERE name = ? AND holder = ?", name, holder)
+	return err
+}
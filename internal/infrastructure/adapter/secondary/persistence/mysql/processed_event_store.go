@@ -0,0 +1,52 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/pkg/tracer"
+	"github.com/gohex/gohex/pkg/uow"
+)
+
+type processedEventStore struct {
+	db      *sql.DB
+	logger  output.Logger
+	metrics output.MetricsReporter
+}
+
+func NewProcessedEventStore(db *sql.DB, logger output.Logger, metrics output.MetricsReporter) output.ProcessedEventStore {
+	return &processedEventStore{
+		db:      db,
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+// MarkProcessed 插入处理记录，主键冲突说明已处理过。
+// 未提交的插入会锁住该行，并发的重复投递会等待当前处理结束
+func (s *processedEventStore) MarkProcessed(ctx context.Context, handlerID, eventID string) (bool, error) {
+	span, ctx := tracer.StartSpan(ctx, "processedEventStore.MarkProcessed")
+	defer span.End()
+
+	query := "INSERT IGNORE INTO processed_events (handler_id, event_id) VALUES (?, ?)"
+
+	var (
+		result sql.Result
+		err    error
+	)
+	if tx, ok := uow.FromContext(ctx); ok {
+		result, err = tx.ExecContext(ctx, query, handlerID, eventID)
+	} else {
+		result, err = s.db.ExecContext(ctx, query, handlerID, eventID)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
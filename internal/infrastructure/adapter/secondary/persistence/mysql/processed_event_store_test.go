@@ -0,0 +1,67 @@
+package mysql
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/testutil"
+	"github.com/gohex/gohex/pkg/uow"
+)
+
+func TestProcessedEventStore_MarkProcessed(t *testing.T) {
+	tests := []struct {
+		name      string
+		affected  int64
+		wantFirst bool
+	}{
+		{name: "first delivery", affected: 1, wantFirst: true},
+		{name: "duplicate delivery", affected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO processed_events")).
+				WithArgs("user_notifications", "e1").
+				WillReturnResult(sqlmock.NewResult(0, tt.affected))
+
+			store := NewProcessedEventStore(db, testutil.NopLogger{}, testutil.NewMetrics())
+			first, err := store.MarkProcessed(context.Background(), "user_notifications", "e1")
+			require.NoError(t, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+			assert.Equal(t, tt.wantFirst, first)
+		})
+	}
+}
+
+func TestProcessedEventStore_JoinsUnitOfWork(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	tx, err := db.Begin()
+	require.NoError(t, err)
+
+	// 处理器失败时处理记录随事务回滚
+	mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO processed_events")).
+		WithArgs("user_notifications", "e1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectRollback()
+
+	store := NewProcessedEventStore(db, testutil.NopLogger{}, testutil.NewMetrics())
+	first, err := store.MarkProcessed(uow.WithTransaction(context.Background(), tx), "user_notifications", "e1")
+	require.NoError(t, err)
+	assert.True(t, first)
+
+	require.NoError(t, tx.Rollback())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
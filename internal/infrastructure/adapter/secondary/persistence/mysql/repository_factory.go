@@ -2,18 +2,18 @@ package mysql
 
 import (
 	"database/sql"
-	"github.com/gohex/gohex/internal/application/port"
+	"github.com/gohex/gohex/internal/application/port/output"
 )
 
 // RepositoryFactory 创建仓储实例的工厂
 type RepositoryFactory struct {
 	db      *sql.DB
-	logger  Logger
-	metrics MetricsReporter
+	logger  output.Logger
+	metrics output.MetricsReporter
 }
 
 // NewRepositoryFactory 创建仓储工厂实例
-func NewRepositoryFactory(db *sql.DB, logger Logger, metrics MetricsReporter) *RepositoryFactory {
+func NewRepositoryFactory(db *sql.DB, logger output.Logger, metrics output.MetricsReporter) *RepositoryFactory {
 	return &RepositoryFactory{
 		db:      db,
 		logger:  logger,
@@ -22,19 +22,11 @@ func NewRepositoryFactory(db *sql.DB, logger Logger, metrics MetricsReporter) *R
 }
 
 // CreateUserRepository 创建用户仓储实例
-func (f *RepositoryFactory) CreateUserRepository() port.UserRepository {
-	return &userRepositoryImpl{
-		db:      f.db,
-		logger:  f.logger,
-		metrics: f.metrics,
-	}
+func (f *RepositoryFactory) CreateUserRepository() output.UserRepository {
+	return NewUserRepository(f.db, f.logger, f.metrics)
 }
 
-// CreateEventStore 创建事件存储实例
-func (f *RepositoryFactory) CreateEventStore() port.EventStore {
-	return &mysqlEventStore{
-		db:      f.db,
-		logger:  f.logger,
-		metrics: f.metrics,
-	}
+// CreateEventStore 创建事件存储实例，个人数据使用 keyStore 中的用户密钥加密
+func (f *RepositoryFactory) CreateEventStore(keyStore output.KeyStore) output.EventStore {
+	return NewEventStore(f.db, keyStore, f.logger, f.metrics)
 }
\ No newline at end of file
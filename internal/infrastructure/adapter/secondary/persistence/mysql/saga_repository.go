@@ -0,0 +1,170 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/pkg/errors"
+	"github.com/gohex/gohex/pkg/tracer"
+)
+
+const sagaColumns = `
+	id, type, correlation_id, status, step, data, deadline_at, timeout_schedule_id, error, version, created_at, updated_at`
+
+// sagaRepository saga 实例仓储，上下文中有工作单元的事务时加入该事务，
+// 实例状态与处理的事件一起提交或回滚
+type sagaRepository struct {
+	db      *sql.DB
+	logger  output.Logger
+	metrics output.MetricsReporter
+}
+
+func NewSagaRepository(db *sql.DB, logger output.Logger, metrics output.MetricsReporter) output.SagaRepository {
+	return &sagaRepository{
+		db:      db,
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+// Save 依赖 (type, correlation_id) 唯一键，重复的起始事件不会创建第二个实例
+func (r *sagaRepository) Save(ctx context.Context, instance *output.SagaInstance) error {
+	span, ctx := tracer.StartSpan(ctx, "sagaRepository.Save")
+	defer span.End()
+
+	data, err := json.Marshal(instance.Data)
+	if err != nil {
+		return err
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, `
+		INSERT IGNORE INTO saga_instances (
+			id, type, correlation_id, status, step, data, deadline_at, timeout_schedule_id, error, version, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		instance.ID,
+		instance.Type,
+		instance.CorrelationID,
+		instance.Status,
+		instance.Step,
+		data,
+		instance.DeadlineAt,
+		instance.TimeoutScheduleID,
+		instance.Error,
+		instance.Version,
+		instance.CreatedAt,
+		instance.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return errors.ErrSagaAlreadyStarted
+	}
+	return nil
+}
+
+func (r *sagaRepository) Update(ctx context.Context, instance *output.SagaInstance) error {
+	span, ctx := tracer.StartSpan(ctx, "sagaRepository.Update")
+	defer span.End()
+
+	data, err := json.Marshal(instance.Data)
+	if err != nil {
+		return err
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE saga_instances
+		SET status = ?, step = ?, data = ?, deadline_at = ?, timeout_schedule_id = ?, error = ?, version = version + 1, updated_at = ?
+		WHERE id = ? AND version = ?
+	`,
+		instance.Status,
+		instance.Step,
+		data,
+		instance.DeadlineAt,
+		instance.TimeoutScheduleID,
+		instance.Error,
+		instance.UpdatedAt,
+		instance.ID,
+		instance.Version,
+	)
+	if err != nil {
+		return err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return errors.ErrConcurrencyConflict
+	}
+
+	instance.Version++
+	return nil
+}
+
+func (r *sagaRepository) FindByID(ctx context.Context, id string) (*output.SagaInstance, error) {
+	span, ctx := tracer.StartSpan(ctx, "sagaRepository.FindByID")
+	defer span.End()
+
+	row := conn(ctx, r.db).QueryRowContext(ctx, "SELECT "+sagaColumns+" FROM saga_instances WHERE id = ?", id)
+	instance, err := scanSagaInstance(row)
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("saga")
+	}
+	return instance, err
+}
+
+func (r *sagaRepository) FindByCorrelation(ctx context.Context, sagaType, correlationID string) (*output.SagaInstance, error) {
+	span, ctx := tracer.StartSpan(ctx, "sagaRepository.FindByCorrelation")
+	defer span.End()
+
+	row := conn(ctx, r.db).QueryRowContext(ctx,
+		"SELECT "+sagaColumns+" FROM saga_instances WHERE type = ? AND correlation_id = ?",
+		sagaType, correlationID,
+	)
+	instance, err := scanSagaInstance(row)
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("saga")
+	}
+	return instance, err
+}
+
+func scanSagaInstance(row interface{ Scan(...interface{}) error }) (*output.SagaInstance, error) {
+	var (
+		instance output.SagaInstance
+		data     []byte
+		sagaErr  sql.NullString
+	)
+	err := row.Scan(
+		&instance.ID,
+		&instance.Type,
+		&instance.CorrelationID,
+		&instance.Status,
+		&instance.Step,
+		&data,
+		&instance.DeadlineAt,
+		&instance.TimeoutScheduleID,
+		&sagaErr,
+		&instance.Version,
+		&instance.CreatedAt,
+		&instance.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &instance.Data); err != nil {
+		return nil, err
+	}
+
+	instance.Error = sagaErr.String
+	return &instance, nil
+}
@@ -0,0 +1,174 @@
+package mysql
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/testutil"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+func newSagaInstance() *output.SagaInstance {
+	now := time.Now()
+	return &output.SagaInstance{
+		ID:            "s1",
+		Type:          "user_onboarding",
+		CorrelationID: "alice",
+		Status:        output.SagaRunning,
+		Data:          map[string]string{"user_id": "alice"},
+		Version:       1,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+func TestSagaRepository_SaveDuplicateStart(t *testing.T) {
+	tests := []struct {
+		name     string
+		affected int64
+		wantErr  error
+	}{
+		{name: "new instance", affected: 1},
+		{name: "already started", affected: 0, wantErr: errors.ErrSagaAlreadyStarted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO saga_instances")).
+				WillReturnResult(sqlmock.NewResult(0, tt.affected))
+
+			repo := NewSagaRepository(db, testutil.NopLogger{}, testutil.NewMetrics())
+			err = repo.Save(context.Background(), newSagaInstance())
+			require.NoError(t, mock.ExpectationsWereMet())
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSagaRepository_UpdateOptimisticLock(t *testing.T) {
+	tests := []struct {
+		name        string
+		affected    int64
+		wantErr     error
+		wantVersion int
+	}{
+		{name: "current version", affected: 1, wantVersion: 2},
+		{name: "stale version", affected: 0, wantErr: errors.ErrConcurrencyConflict, wantVersion: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			instance := newSagaInstance()
+			instance.Step = 1
+			anyArg := sqlmock.AnyArg()
+			mock.ExpectExec(regexp.QuoteMeta("WHERE id = ? AND version = ?")).
+				WithArgs(output.SagaRunning, 1, []byte(`{"user_id":"alice"}`), anyArg, "", "", anyArg, "s1", 1).
+				WillReturnResult(sqlmock.NewResult(0, tt.affected))
+
+			repo := NewSagaRepository(db, testutil.NopLogger{}, testutil.NewMetrics())
+			err = repo.Update(context.Background(), instance)
+			require.NoError(t, mock.ExpectationsWereMet())
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantVersion, instance.Version)
+		})
+	}
+}
+
+func TestSagaRepository_FindByCorrelationNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE type = ? AND correlation_id = ?")).
+		WithArgs("user_onboarding", "bob").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	repo := NewSagaRepository(db, testutil.NopLogger{}, testutil.NewMetrics())
+	_, err = repo.FindByCorrelation(context.Background(), "user_onboarding", "bob")
+
+	var appErr *errors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, errors.ErrCodeNotFound, appErr.Code)
+}
+
+func TestSagaRepository_JoinsUnitOfWork(t *testing.T) {
+	tests := []struct {
+		name   string
+		expect func(mock sqlmock.Sqlmock)
+		call   func(ctx context.Context, repo output.SagaRepository) error
+	}{
+		{
+			name: "save",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO saga_instances")).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			call: func(ctx context.Context, repo output.SagaRepository) error {
+				return repo.Save(ctx, newSagaInstance())
+			},
+		},
+		{
+			name: "update",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE saga_instances")).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			call: func(ctx context.Context, repo output.SagaRepository) error {
+				return repo.Update(ctx, newSagaInstance())
+			},
+		},
+		{
+			name: "find by correlation",
+			expect: func(mock sqlmock.Sqlmock) {
+				now := time.Now()
+				mock.ExpectQuery(regexp.QuoteMeta("WHERE type = ? AND correlation_id = ?")).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "type", "correlation_id", "status", "step", "data",
+						"deadline_at", "timeout_schedule_id", "error", "version", "created_at", "updated_at"}).
+						AddRow("s1", "user_onboarding", "alice", output.SagaRunning, 0, []byte(`{"user_id":"alice"}`),
+							nil, "", nil, 1, now, now))
+			},
+			call: func(ctx context.Context, repo output.SagaRepository) error {
+				_, err := repo.FindByCorrelation(ctx, "user_onboarding", "alice")
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 仓储自身的连接没有任何期望，绕过事务的语句会失败
+			db, _, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+			ctx, txMock := newTxContext(t)
+			tt.expect(txMock)
+
+			repo := NewSagaRepository(db, testutil.NopLogger{}, testutil.NewMetrics())
+			require.NoError(t, tt.call(ctx, repo))
+			assert.NoError(t, txMock.ExpectationsWereMet())
+		})
+	}
+}
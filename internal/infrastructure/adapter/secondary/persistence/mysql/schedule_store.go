@@ -0,0 +1,265 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/pkg/errors"
+	"github.com/gohex/gohex/pkg/tracer"
+)
+
+const scheduleColumns = `id, name, command_type, payload, cron, next_run_at, status, runs, last_run_at,
+	last_error, requested_by, impersonator_id, created_at, updated_at`
+
+type scheduleStore struct {
+	db      *sql.DB
+	logger  output.Logger
+	metrics output.MetricsReporter
+}
+
+func NewScheduleStore(db *sql.DB, logger output.Logger, metrics output.MetricsReporter) output.ScheduleStore {
+	return &scheduleStore{
+		db:      db,
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+func (s *scheduleStore) Save(ctx context.Context, schedule *output.ScheduledCommand) error {
+	span, ctx := tracer.StartSpan(ctx, "scheduleStore.Save")
+	defer span.End()
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO scheduled_commands
+			(id, name, command_type, payload, cron, next_run_at, status, requested_by, impersonator_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		schedule.ID,
+		sql.NullString{String: schedule.Name, Valid: schedule.Name != ""},
+		schedule.CommandType,
+		schedule.Payload,
+		sql.NullString{String: schedule.Cron, Valid: schedule.Cron != ""},
+		schedule.NextRunAt,
+		schedule.Status,
+		schedule.RequestedBy,
+		sql.NullString{String: schedule.ImpersonatorID, Valid: schedule.ImpersonatorID != ""},
+		schedule.CreatedAt,
+		schedule.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	s.metrics.IncrementCounter("scheduled_command_created", "command_type", schedule.CommandType)
+	return nil
+}
+
+func (s *scheduleStore) SaveNamed(ctx context.Context, schedule *output.ScheduledCommand) (string, error) {
+	span, ctx := tracer.StartSpan(ctx, "scheduleStore.SaveNamed")
+	defer span.End()
+
+	// 多个实例启动时会注册同一个周期计划，按名称合并；已取消的计划保持取消
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO scheduled_commands
+			(id, name, command_type, payload, cron, next_run_at, status, requested_by, impersonator_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			next_run_at = IF(cron <=> VALUES(cron) AND status = VALUES(status), next_run_at, VALUES(next_run_at)),
+			status = IF(status = ?, status, VALUES(status)),
+			command_type = VALUES(command_type),
+			payload = VALUES(payload),
+			cron = VALUES(cron),
+			updated_at = VALUES(updated_at)
+	`,
+		schedule.ID,
+		schedule.Name,
+		schedule.CommandType,
+		schedule.Payload,
+		schedule.Cron,
+		schedule.NextRunAt,
+		schedule.Status,
+		schedule.RequestedBy,
+		sql.NullString{String: schedule.ImpersonatorID, Valid: schedule.ImpersonatorID != ""},
+		schedule.CreatedAt,
+		schedule.UpdatedAt,
+		output.ScheduleCancelled,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	var id string
+	if err := s.db.QueryRowContext(ctx,
+		"SELECT id FROM scheduled_commands WHERE name = ?", schedule.Name,
+	).Scan(&id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (s *scheduleStore) FindByID(ctx context.Context, id string) (*output.ScheduledCommand, error) {
+	span, ctx := tracer.StartSpan(ctx, "scheduleStore.FindByID")
+	defer span.End()
+
+	schedule, err := scanSchedule(s.db.QueryRowContext(ctx,
+		"SELECT "+scheduleColumns+" FROM scheduled_commands WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("scheduled command")
+	}
+	return schedule, err
+}
+
+func (s *scheduleStore) List(ctx context.Context, filter output.ScheduleFilter) ([]*output.ScheduledCommand, int64, error) {
+	span, ctx := tracer.StartSpan(ctx, "scheduleStore.List")
+	defer span.End()
+
+	where := " WHERE 1 = 1"
+	var args []interface{}
+	if filter.Status != "" {
+		where += " AND status = ?"
+		args = append(args, filter.Status)
+	}
+	if filter.CommandType != "" {
+		where += " AND command_type = ?"
+		args = append(args, filter.CommandType)
+	}
+
+	var total int64
+	if err := s.db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM scheduled_commands"+where, args...,
+	).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT "+scheduleColumns+" FROM scheduled_commands"+where+" ORDER BY next_run_at LIMIT ? OFFSET ?",
+		append(args, filter.Limit, filter.Offset)...,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var schedules []*output.ScheduledCommand
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, total, rows.Err()
+}
+
+func (s *scheduleStore) Cancel(ctx context.Context, id string) error {
+	span, ctx := tracer.StartSpan(ctx, "scheduleStore.Cancel")
+	defer span.End()
+
+	result, err := s.db.ExecContext(ctx,
+		"UPDATE scheduled_commands SET status = ?, updated_at = ? WHERE id = ? AND status = ?",
+		output.ScheduleCancelled, time.Now(), id, output.ScheduleActive,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		// 区分不存在和已结束
+		if _, err := s.FindByID(ctx, id); err != nil {
+			return err
+		}
+		return errors.ErrScheduleNotCancellable
+	}
+
+	s.metrics.IncrementCounter("scheduled_command_cancelled")
+	return nil
+}
+
+func (s *scheduleStore) FindDue(ctx context.Context, now time.Time, limit int) ([]*output.ScheduledCommand, error) {
+	span, ctx := tracer.StartSpan(ctx, "scheduleStore.FindDue")
+	defer span.End()
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+scheduleColumns+` FROM scheduled_commands
+		WHERE status = ? AND next_run_at <= ?
+		ORDER BY next_run_at
+		LIMIT ?
+	`, output.ScheduleActive, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schedules []*output.ScheduledCommand
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, rows.Err()
+}
+
+func (s *scheduleStore) RecordRun(ctx context.Context, schedule *output.ScheduledCommand) error {
+	span, ctx := tracer.StartSpan(ctx, "scheduleStore.RecordRun")
+	defer span.End()
+
+	// 执行期间计划可能已被取消，只更新仍为 active 的记录
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE scheduled_commands
+		SET status = ?, next_run_at = ?, runs = ?, last_run_at = ?, last_error = ?, updated_at = ?
+		WHERE id = ? AND status = ?
+	`,
+		schedule.Status,
+		schedule.NextRunAt,
+		schedule.Runs,
+		schedule.LastRunAt,
+		sql.NullString{String: schedule.LastError, Valid: schedule.LastError != ""},
+		time.Now(),
+		schedule.ID,
+		output.ScheduleActive,
+	)
+	return err
+}
+
+func scanSchedule(row interface{ Scan(...interface{}) error }) (*output.ScheduledCommand, error) {
+	var (
+		schedule       output.ScheduledCommand
+		name           sql.NullString
+		cron           sql.NullString
+		lastError      sql.NullString
+		impersonatorID sql.NullString
+	)
+	err := row.Scan(
+		&schedule.ID,
+		&name,
+		&schedule.CommandType,
+		&schedule.Payload,
+		&cron,
+		&schedule.NextRunAt,
+		&schedule.Status,
+		&schedule.Runs,
+		&schedule.LastRunAt,
+		&lastError,
+		&schedule.RequestedBy,
+		&impersonatorID,
+		&schedule.CreatedAt,
+		&schedule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule.Name = name.String
+	schedule.Cron = cron.String
+	schedule.LastError = lastError.String
+	schedule.ImpersonatorID = impersonatorID.String
+	return &schedule, nil
+}
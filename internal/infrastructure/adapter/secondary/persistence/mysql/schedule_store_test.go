@@ -0,0 +1,130 @@
+package mysql
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/testutil"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+func scheduleRows(status string) *sqlmock.Rows {
+	now := time.Now()
+	return sqlmock.NewRows([]string{"id", "name", "command_type", "payload", "cron", "next_run_at", "status",
+		"runs", "last_run_at", "last_error", "requested_by", "impersonator_id", "created_at", "updated_at"}).
+		AddRow("s1", nil, "command.UnlockUserCommand", []byte(`{"UserID":"alice"}`), nil, now, status,
+			1, now, nil, "system", nil, now, now)
+}
+
+func TestScheduleStore_Cancel(t *testing.T) {
+	tests := []struct {
+		name     string
+		affected int64
+		existing *sqlmock.Rows
+		wantErr  error
+		wantCode errors.ErrorCode
+	}{
+		{name: "active schedule", affected: 1},
+		{name: "already executed", existing: scheduleRows(output.ScheduleCompleted), wantErr: errors.ErrScheduleNotCancellable},
+		{name: "unknown schedule", existing: sqlmock.NewRows([]string{"id"}), wantCode: errors.ErrCodeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectExec(regexp.QuoteMeta("UPDATE scheduled_commands SET status = ?")).
+				WithArgs(output.ScheduleCancelled, sqlmock.AnyArg(), "s1", output.ScheduleActive).
+				WillReturnResult(sqlmock.NewResult(0, tt.affected))
+			if tt.existing != nil {
+				mock.ExpectQuery(regexp.QuoteMeta("FROM scheduled_commands WHERE id = ?")).
+					WithArgs("s1").
+					WillReturnRows(tt.existing)
+			}
+
+			metrics := testutil.NewMetrics()
+			store := NewScheduleStore(db, testutil.NopLogger{}, metrics)
+			err = store.Cancel(context.Background(), "s1")
+			require.NoError(t, mock.ExpectationsWereMet())
+
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.wantCode != "":
+				var appErr *errors.AppError
+				require.ErrorAs(t, err, &appErr)
+				assert.Equal(t, tt.wantCode, appErr.Code)
+			default:
+				assert.NoError(t, err)
+				assert.Equal(t, 1, metrics.Counter("scheduled_command_cancelled"))
+			}
+		})
+	}
+}
+
+func TestScheduleStore_SaveNamedReturnsExistingID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	// 其他实例已注册同名计划，返回已有计划的 ID
+	mock.ExpectExec(regexp.QuoteMeta("ON DUPLICATE KEY UPDATE")).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM scheduled_commands WHERE name = ?")).
+		WithArgs("expire_email_changes").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("existing"))
+
+	store := NewScheduleStore(db, testutil.NopLogger{}, testutil.NewMetrics())
+	id, err := store.SaveNamed(context.Background(), &output.ScheduledCommand{
+		ID:          "new",
+		Name:        "expire_email_changes",
+		CommandType: "command.ExpireEmailChangesCommand",
+		Payload:     []byte(`{}`),
+		Cron:        "*/15 * * * *",
+		Status:      output.ScheduleActive,
+	})
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, "existing", id)
+}
+
+func TestLeaderLease_TryAcquire(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		want    bool
+	}{
+		{name: "acquired", current: "node-a", want: true},
+		{name: "held by another instance", current: "node-b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectExec(regexp.QuoteMeta("INSERT INTO leader_leases")).
+				WithArgs("scheduler", "node-a", int64(15_000_000)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT holder FROM leader_leases")).
+				WithArgs("scheduler").
+				WillReturnRows(sqlmock.NewRows([]string{"holder"}).AddRow(tt.current))
+
+			lease := NewLeaderLease(db, testutil.NopLogger{}, testutil.NewMetrics())
+			leader, err := lease.TryAcquire(context.Background(), "scheduler", "node-a", 15*time.Second)
+			require.NoError(t, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+			assert.Equal(t, tt.want, leader)
+		})
+	}
+}
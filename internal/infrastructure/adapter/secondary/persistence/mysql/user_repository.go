@@ -3,19 +3,22 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"time"
 
 	"github.com/gohex/gohex/internal/domain/aggregate"
 	"github.com/gohex/gohex/internal/domain/vo"
-	"github.com/gohex/gohex/internal/application/port"
+	"github.com/gohex/gohex/internal/application/port/output"
 	"github.com/gohex/gohex/pkg/errors"
 	"github.com/gohex/gohex/pkg/tracer"
 )
 
+// userRepository 用户仓储，上下文中有工作单元的事务时所有语句加入该事务，
+// 用户的变更与其事件、审计记录一起提交或回滚
 type userRepository struct {
 	db        *sql.DB
-	logger    Logger
-	metrics   MetricsReporter
+	logger    output.Logger
+	metrics   output.MetricsReporter
 }
 
 type userModel struct {
@@ -26,11 +29,36 @@ type userModel struct {
 	Bio       string    `db:"bio"`
 	Avatar    string    `db:"avatar"`
 	Status    string    `db:"status"`
-	CreatedAt time.Time `db:"created_at"`
-	UpdatedAt time.Time `db:"updated_at"`
+	CreatedAt time.Time    `db:"created_at"`
+	UpdatedAt time.Time    `db:"updated_at"`
+	DeletedAt sql.NullTime `db:"deleted_at"`
+	LockedUntil sql.NullTime `db:"locked_until"`
+	ErasedAt    sql.NullTime `db:"erased_at"`
+	Version   int          `db:"version"`
 }
 
-func NewUserRepository(db *sql.DB, logger Logger, metrics MetricsReporter) *userRepository {
+const userColumns = "id, email, password, name, bio, avatar, status, created_at, updated_at, deleted_at, locked_until, erased_at, version"
+
+// scanUser 按 userColumns 的顺序扫描一行
+func scanUser(row interface{ Scan(...interface{}) error }, model *userModel) error {
+	return row.Scan(
+		&model.ID,
+		&model.Email,
+		&model.Password,
+		&model.Name,
+		&model.Bio,
+		&model.Avatar,
+		&model.Status,
+		&model.CreatedAt,
+		&model.UpdatedAt,
+		&model.DeletedAt,
+		&model.LockedUntil,
+		&model.ErasedAt,
+		&model.Version,
+	)
+}
+
+func NewUserRepository(db *sql.DB, logger output.Logger, metrics output.MetricsReporter) *userRepository {
 	return &userRepository{
 		db:      db,
 		logger:  logger,
@@ -46,11 +74,11 @@ func (r *userRepository) Save(ctx context.Context, user *aggregate.User) error {
 	defer timer.Stop()
 
 	query := `
-		INSERT INTO users (id, email, password, name, bio, avatar, status, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO users (id, email, password, name, bio, avatar, status, created_at, updated_at, version)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
 		user.ID(),
 		user.Email().String(),
 		user.Password().Hash(),
@@ -60,6 +88,7 @@ func (r *userRepository) Save(ctx context.Context, user *aggregate.User) error {
 		user.Status().String(),
 		user.CreatedAt(),
 		user.UpdatedAt(),
+		user.Version(),
 	)
 
 	if err != nil {
@@ -68,38 +97,107 @@ func (r *userRepository) Save(ctx context.Context, user *aggregate.User) error {
 		return err
 	}
 
+	if err := r.saveRoles(ctx, user); err != nil {
+		r.logger.Error("failed to save user roles", "error", err)
+		r.metrics.IncrementCounter("repository_save_user_error")
+		return err
+	}
+
 	r.metrics.IncrementCounter("repository_save_user_success")
 	return nil
 }
 
+func (r *userRepository) Update(ctx context.Context, user *aggregate.User) error {
+	span, ctx := tracer.StartSpan(ctx, "userRepository.Update")
+	defer span.End()
+
+	timer := r.metrics.StartTimer("repository_update_user")
+	defer timer.Stop()
+
+	query := `
+		UPDATE users
+		SET email = ?, password = ?, name = ?, bio = ?, avatar = ?, status = ?, updated_at = ?, deleted_at = ?, locked_until = ?, erased_at = ?, version = ?
+		WHERE id = ?
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		user.Email().String(),
+		user.Password().Hash(),
+		user.Profile().Name(),
+		user.Profile().Bio(),
+		user.Profile().Avatar(),
+		user.Status().String(),
+		user.UpdatedAt(),
+		user.DeletedAt(),
+		user.LockedUntil(),
+		user.ErasedAt(),
+		user.Version(),
+		user.ID(),
+	)
+	if err != nil {
+		r.logger.Error("failed to update user", "error", err)
+		r.metrics.IncrementCounter("repository_update_user_error")
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.ErrUserNotFound
+	}
+
+	if err := r.saveRoles(ctx, user); err != nil {
+		r.logger.Error("failed to update user roles", "error", err)
+		r.metrics.IncrementCounter("repository_update_user_error")
+		return err
+	}
+
+	r.metrics.IncrementCounter("repository_update_user_success")
+	return nil
+}
+
 func (r *userRepository) FindByID(ctx context.Context, id string) (*aggregate.User, error) {
 	span, ctx := tracer.StartSpan(ctx, "userRepository.FindByID")
 	defer span.End()
 
+	// 按 ID 查询包含已软删除的用户，便于恢复和清理
 	var model userModel
-	query := `SELECT * FROM users WHERE id = ?`
-	
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&model.ID,
-		&model.Email,
-		&model.Password,
-		&model.Name,
-		&model.Bio,
-		&model.Avatar,
-		&model.Status,
-		&model.CreatedAt,
-		&model.UpdatedAt,
-	)
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = ?`
+
+	err := scanUser(conn(ctx, r.db).QueryRowContext(ctx, query, id), &model)
 
 	if err == sql.ErrNoRows {
-		return nil, ErrUserNotFound
+		return nil, errors.ErrUserNotFound
 	}
 	if err != nil {
 		r.logger.Error("failed to find user", "error", err)
 		return nil, err
 	}
 
-	return r.toAggregate(&model)
+	return r.withRoles(ctx, &model)
+}
+
+// FindByEmail 通过邮箱查找用户，已软删除的用户不能通过邮箱找到，也就无法登录
+func (r *userRepository) FindByEmail(ctx context.Context, email vo.Email) (*aggregate.User, error) {
+	span, ctx := tracer.StartSpan(ctx, "userRepository.FindByEmail")
+	defer span.End()
+
+	var model userModel
+	query := `SELECT ` + userColumns + ` FROM users WHERE email = ? AND deleted_at IS NULL`
+
+	err := scanUser(conn(ctx, r.db).QueryRowContext(ctx, query, email.String()), &model)
+
+	if err == sql.ErrNoRows {
+		return nil, errors.ErrUserNotFound
+	}
+	if err != nil {
+		r.logger.Error("failed to find user by email", "error", err)
+		return nil, err
+	}
+
+	return r.withRoles(ctx, &model)
 }
 
 func (r *userRepository) ExistsByEmail(ctx context.Context, email vo.Email) (bool, error) {
@@ -109,7 +207,7 @@ func (r *userRepository) ExistsByEmail(ctx context.Context, email vo.Email) (boo
 	var exists bool
 	query := `SELECT EXISTS(SELECT 1 FROM users WHERE email = ?)`
 	
-	err := r.db.QueryRowContext(ctx, query, email.String()).Scan(&exists)
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, email.String()).Scan(&exists)
 	if err != nil {
 		r.logger.Error("failed to check email existence", "error", err)
 		return false, err
@@ -118,8 +216,232 @@ func (r *userRepository) ExistsByEmail(ctx context.Context, email vo.Email) (boo
 	return exists, nil
 }
 
+// FindAll 分页查询用户，默认不包含已软删除的用户
+func (r *userRepository) FindAll(ctx context.Context, params output.FindAllParams) ([]*aggregate.User, int64, error) {
+	span, ctx := tracer.StartSpan(ctx, "userRepository.FindAll")
+	defer span.End()
+
+	var (
+		conds []string
+		args  []interface{}
+	)
+	if !params.IncludeDeleted {
+		conds = append(conds, "deleted_at IS NULL")
+	}
+	if params.Status != "" {
+		conds = append(conds, "status = ?")
+		args = append(args, params.Status)
+	}
+	if params.Role != "" {
+		conds = append(conds, "id IN (SELECT user_id FROM user_roles WHERE role = ?)")
+		args = append(args, params.Role)
+	}
+
+	where := ""
+	if len(conds) > 0 {
+		where = " WHERE " + strings.Join(conds, " AND ")
+	}
+
+	var total int64
+	if err := conn(ctx, r.db).QueryRowContext(ctx, "SELECT COUNT(*) FROM users"+where, args...).Scan(&total); err != nil {
+		r.logger.Error("failed to count users", "error", err)
+		return nil, 0, err
+	}
+
+	query := "SELECT " + userColumns + " FROM users" + where + " ORDER BY " + userOrderBy(params.SortBy, params.SortDir)
+	if params.Limit > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, params.Limit, params.Offset)
+	}
+
+	users, err := r.queryUsers(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+// Delete 软删除用户，记录保留到宽限期结束后由 Purge 永久删除
+func (r *userRepository) Delete(ctx context.Context, id string) error {
+	span, ctx := tracer.StartSpan(ctx, "userRepository.Delete")
+	defer span.End()
+
+	result, err := conn(ctx, r.db).ExecContext(ctx,
+		"UPDATE users SET status = ?, deleted_at = ?, locked_until = NULL, updated_at = ? WHERE id = ? AND deleted_at IS NULL",
+		vo.StatusDeleted.String(), time.Now(), time.Now(), id,
+	)
+	if err != nil {
+		r.logger.Error("failed to delete user", "error", err)
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.ErrUserNotFound
+	}
+	return nil
+}
+
+// FindDeletedBefore 查询在指定时间之前被软删除的用户，按 ID 分页，失败的记录不会阻塞后续页
+func (r *userRepository) FindDeletedBefore(ctx context.Context, before time.Time, afterID string, limit int) ([]*aggregate.User, error) {
+	span, ctx := tracer.StartSpan(ctx, "userRepository.FindDeletedBefore")
+	defer span.End()
+
+	query := "SELECT " + userColumns + " FROM users WHERE deleted_at IS NOT NULL AND deleted_at <= ? AND id > ? ORDER BY id LIMIT ?"
+	return r.queryUsers(ctx, query, before, afterID, limit)
+}
+
+// Purge 永久删除已软删除的用户
+func (r *userRepository) Purge(ctx context.Context, id string) error {
+	span, ctx := tracer.StartSpan(ctx, "userRepository.Purge")
+	defer span.End()
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, "DELETE FROM users WHERE id = ? AND deleted_at IS NOT NULL", id)
+	if err != nil {
+		r.logger.Error("failed to purge user", "error", err)
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.ErrUserNotDeleted
+	}
+
+	r.metrics.IncrementCounter("repository_purge_user_success")
+	return nil
+}
+
+func (r *userRepository) queryUsers(ctx context.Context, query string, args ...interface{}) ([]*aggregate.User, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("failed to query users", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var models []*userModel
+	for rows.Next() {
+		var model userModel
+		if err := scanUser(rows, &model); err != nil {
+			return nil, err
+		}
+		models = append(models, &model)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	ids := make([]string, len(models))
+	for i, model := range models {
+		ids[i] = model.ID
+	}
+	roles, err := r.loadRoles(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*aggregate.User, 0, len(models))
+	for _, model := range models {
+		user, err := r.toAggregate(model, roles[model.ID])
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// withRoles 加载单个用户的角色并转换为聚合根
+func (r *userRepository) withRoles(ctx context.Context, model *userModel) (*aggregate.User, error) {
+	roles, err := r.loadRoles(ctx, model.ID)
+	if err != nil {
+		return nil, err
+	}
+	return r.toAggregate(model, roles[model.ID])
+}
+
+// loadRoles 按用户 ID 批量查询角色
+func (r *userRepository) loadRoles(ctx context.Context, userIDs ...string) (map[string][]vo.UserRole, error) {
+	roles := make(map[string][]vo.UserRole, len(userIDs))
+	if len(userIDs) == 0 {
+		return roles, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(userIDs)), ",")
+	args := make([]interface{}, len(userIDs))
+	for i, id := range userIDs {
+		args[i] = id
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		"SELECT user_id, role FROM user_roles WHERE user_id IN ("+placeholders+") ORDER BY created_at, role",
+		args...,
+	)
+	if err != nil {
+		r.logger.Error("failed to load user roles", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID, role string
+		if err := rows.Scan(&userID, &role); err != nil {
+			return nil, err
+		}
+		roles[userID] = append(roles[userID], vo.UserRole(role))
+	}
+	return roles, rows.Err()
+}
+
+// saveRoles 使 user_roles 与聚合中的角色一致：先删除已撤销的角色，再补充新分配的角色；
+// 两条语句须在调用方的事务中执行，避免中途失败时用户失去全部角色
+func (r *userRepository) saveRoles(ctx context.Context, user *aggregate.User) error {
+	roles := user.RoleStrings()
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(roles)), ",")
+	args := make([]interface{}, 0, len(roles)*2+1)
+	args = append(args, user.ID())
+	for _, role := range roles {
+		args = append(args, role)
+	}
+	if _, err := conn(ctx, r.db).ExecContext(ctx,
+		"DELETE FROM user_roles WHERE user_id = ? AND role NOT IN ("+placeholders+")",
+		args...,
+	); err != nil {
+		return err
+	}
+
+	values := strings.TrimSuffix(strings.Repeat("(?, ?),", len(roles)), ",")
+	args = args[:0]
+	for _, role := range roles {
+		args = append(args, user.ID(), role)
+	}
+	_, err := conn(ctx, r.db).ExecContext(ctx, "INSERT IGNORE INTO user_roles (user_id, role) VALUES "+values, args...)
+	return err
+}
+
+// userOrderBy 将排序参数映射为白名单内的列
+func userOrderBy(sortBy, sortDir string) string {
+	column := "created_at"
+	switch sortBy {
+	case "created_at", "updated_at", "name", "email":
+		column = sortBy
+	}
+	if strings.EqualFold(sortDir, "asc") {
+		return column + " ASC"
+	}
+	return column + " DESC"
+}
+
 // toAggregate 将数据模型转换为聚合根
-func (r *userRepository) toAggregate(model *userModel) (*aggregate.User, error) {
+func (r *userRepository) toAggregate(model *userModel, roles []vo.UserRole) (*aggregate.User, error) {
 	email, err := vo.NewEmail(model.Email)
 	if err != nil {
 		return nil, err
@@ -131,20 +453,42 @@ func (r *userRepository) toAggregate(model *userModel) (*aggregate.User, error)
 	if err != nil {
 		return nil, err
 	}
-	profile = profile.UpdateAvatar(model.Avatar)
+	if model.Avatar != "" {
+		if profile, err = profile.WithAvatar(model.Avatar); err != nil {
+			return nil, err
+		}
+	}
 
 	status := vo.UserStatus(model.Status)
 	if !status.IsValid() {
-		return nil, ErrInvalidUserStatus
+		return nil, errors.ErrInvalidUserStatus
 	}
 
-	user := &aggregate.User{
-		BaseAggregate: aggregate.NewBaseAggregate(model.ID),
-		email:         email,
-		password:      password,
-		profile:       profile,
-		status:        status,
+	var deletedAt *time.Time
+	if model.DeletedAt.Valid {
+		deletedAt = &model.DeletedAt.Time
+	}
+	var lockedUntil *time.Time
+	if model.LockedUntil.Valid {
+		lockedUntil = &model.LockedUntil.Time
+	}
+	var erasedAt *time.Time
+	if model.ErasedAt.Valid {
+		erasedAt = &model.ErasedAt.Time
 	}
 
-	return user, nil
+	return aggregate.ReconstituteUser(
+		model.ID,
+		email,
+		password,
+		profile,
+		status,
+		roles,
+		model.CreatedAt,
+		model.UpdatedAt,
+		deletedAt,
+		lockedUntil,
+		erasedAt,
+		model.Version,
+	), nil
 } 
\ No newline at end of file
@@ -0,0 +1,210 @@
+package mysql
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/vo"
+	"github.com/gohex/gohex/internal/testutil"
+	"github.com/gohex/gohex/pkg/uow"
+)
+
+func newUserRepositoryMock(t *testing.T) (*userRepository, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return NewUserRepository(db, testutil.NopLogger{}, testutil.NewMetrics()), mock
+}
+
+// newTxContext 返回带有工作单元事务的上下文；事务来自另一个模拟连接，
+// 绕过事务直接使用连接池的语句不会匹配该事务的期望
+func newTxContext(t *testing.T) (context.Context, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	mock.ExpectBegin()
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	t.Cleanup(func() { tx.Rollback() })
+	return uow.WithTransaction(context.Background(), tx), mock
+}
+
+func userRow(id string) *sqlmock.Rows {
+	now := time.Now()
+	return sqlmock.NewRows([]string{"id", "email", "password", "name", "bio", "avatar", "status",
+		"created_at", "updated_at", "deleted_at", "locked_until", "erased_at", "version"}).
+		AddRow(id, id+"@example.com", "hash", "Name "+id, "", "", "active", now, now, nil, nil, nil, 3)
+}
+
+func TestUserRepository_FindByIDLoadsRoles(t *testing.T) {
+	tests := []struct {
+		name  string
+		rows  [][]string
+		roles []vo.UserRole
+	}{
+		{"persisted roles", [][]string{{"u1", "user"}, {"u1", "support"}}, []vo.UserRole{vo.RoleUser, vo.RoleSupport}},
+		{"no rows falls back to default role", nil, []vo.UserRole{vo.RoleUser}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, mock := newUserRepositoryMock(t)
+
+			mock.ExpectQuery(regexp.QuoteMeta("FROM users WHERE id = ?")).WithArgs("u1").WillReturnRows(userRow("u1"))
+			roleRows := sqlmock.NewRows([]string{"user_id", "role"})
+			for _, r := range tt.rows {
+				roleRows.AddRow(r[0], r[1])
+			}
+			mock.ExpectQuery(regexp.QuoteMeta("FROM user_roles WHERE user_id IN (?)")).WithArgs("u1").WillReturnRows(roleRows)
+
+			user, err := repo.FindByID(context.Background(), "u1")
+			require.NoError(t, err)
+			assert.Equal(t, tt.roles, user.Roles())
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUserRepository_FindAllLoadsRolesInOneQuery(t *testing.T) {
+	repo, mock := newUserRepositoryMock(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM users WHERE deleted_at IS NULL AND id IN (SELECT user_id FROM user_roles WHERE role = ?)")).
+		WithArgs("support").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	rows := userRow("u1")
+	rows.AddRow("u2", "u2@example.com", "hash", "Name u2", "", "", "active", time.Now(), time.Now(), nil, nil, nil, 1)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM users WHERE deleted_at IS NULL AND id IN")).WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM user_roles WHERE user_id IN (?,?)")).
+		WithArgs("u1", "u2").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "role"}).AddRow("u1", "support").AddRow("u2", "support").AddRow("u2", "admin"))
+
+	users, total, err := repo.FindAll(context.Background(), output.FindAllParams{Role: "support", Limit: 20})
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, total)
+	require.Len(t, users, 2)
+	assert.Equal(t, []vo.UserRole{vo.RoleSupport}, users[0].Roles())
+	assert.Equal(t, []vo.UserRole{vo.RoleSupport, vo.RoleAdmin}, users[1].Roles())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_UpdateSyncsRoles(t *testing.T) {
+	repo, mock := newUserRepositoryMock(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM users WHERE id = ?")).WithArgs("u1").WillReturnRows(userRow("u1"))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM user_roles")).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "role"}).AddRow("u1", "user"))
+
+	user, err := repo.FindByID(context.Background(), "u1")
+	require.NoError(t, err)
+	require.NoError(t, user.AssignRole(vo.RoleSupport))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users")).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM user_roles WHERE user_id = ? AND role NOT IN (?,?)")).
+		WithArgs("u1", "user", "support").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO user_roles (user_id, role) VALUES (?, ?),(?, ?)")).
+		WithArgs("u1", "user", "u1", "support").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.Update(context.Background(), user))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_SaveRolesJoinsUnitOfWork(t *testing.T) {
+	repo, mock := newUserRepositoryMock(t)
+	ctx, txMock := newTxContext(t)
+
+	// 删除和补充角色在同一事务中执行，不会在两者之间留下没有角色的用户
+	txMock.ExpectExec(regexp.QuoteMeta("DELETE FROM user_roles WHERE user_id = ? AND role NOT IN (?)")).
+		WithArgs("u1", "admin").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	txMock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO user_roles (user_id, role) VALUES (?, ?)")).
+		WithArgs("u1", "admin").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.saveRoles(ctx, testutil.NewUser("u1", vo.StatusActive, vo.RoleAdmin)))
+	assert.NoError(t, txMock.ExpectationsWereMet())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_JoinsUnitOfWork(t *testing.T) {
+	tests := []struct {
+		name   string
+		expect func(mock sqlmock.Sqlmock)
+		call   func(ctx context.Context, repo *userRepository) error
+	}{
+		{
+			name: "save",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO users")).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM user_roles")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO user_roles")).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			call: func(ctx context.Context, repo *userRepository) error {
+				return repo.Save(ctx, testutil.NewUser("u1", vo.StatusActive, vo.RoleUser))
+			},
+		},
+		{
+			name: "update",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE users")).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM user_roles")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO user_roles")).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			call: func(ctx context.Context, repo *userRepository) error {
+				return repo.Update(ctx, testutil.NewUser("u1", vo.StatusActive, vo.RoleUser))
+			},
+		},
+		{
+			name: "delete",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET status = ?, deleted_at = ?")).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			call: func(ctx context.Context, repo *userRepository) error {
+				return repo.Delete(ctx, "u1")
+			},
+		},
+		{
+			name: "purge",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM users WHERE id = ?")).WithArgs("u1").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			call: func(ctx context.Context, repo *userRepository) error {
+				return repo.Purge(ctx, "u1")
+			},
+		},
+		{
+			name: "find by id",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("FROM users WHERE id = ?")).WithArgs("u1").WillReturnRows(userRow("u1"))
+				mock.ExpectQuery(regexp.QuoteMeta("FROM user_roles")).WillReturnRows(sqlmock.NewRows([]string{"user_id", "role"}))
+			},
+			call: func(ctx context.Context, repo *userRepository) error {
+				_, err := repo.FindByID(ctx, "u1")
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, mock := newUserRepositoryMock(t)
+			ctx, txMock := newTxContext(t)
+			tt.expect(txMock)
+
+			require.NoError(t, tt.call(ctx, repo))
+			assert.NoError(t, txMock.ExpectationsWereMet())
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,465 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/pkg/errors"
+	"github.com/gohex/gohex/pkg/tracer"
+)
+
+const webhookSubscriptionColumns = `
+	id, url, secret, event_types, description, active, consecutive_failures,
+	disabled_reason, created_by, created_at, updated_at, disabled_at`
+
+const webhookDeliveryColumns = `
+	id, subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at,
+	last_status_code, last_error, redelivery_of, created_at, delivered_at`
+
+type webhookRepository struct {
+	db      *sql.DB
+	logger  output.Logger
+	metrics output.MetricsReporter
+}
+
+func NewWebhookRepository(db *sql.DB, logger output.Logger, metrics output.MetricsReporter) output.WebhookRepository {
+	return &webhookRepository{
+		db:      db,
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+func (r *webhookRepository) SaveSubscription(ctx context.Context, sub *output.WebhookSubscription) error {
+	span, ctx := tracer.StartSpan(ctx, "webhookRepository.SaveSubscription")
+	defer span.End()
+
+	eventTypes, err := json.Marshal(sub.EventTypes)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO webhook_subscriptions (id, url, secret, event_types, description, active, created_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		sub.ID,
+		sub.URL,
+		sub.Secret,
+		eventTypes,
+		sub.Description,
+		sub.Active,
+		sub.CreatedBy,
+		sub.CreatedAt,
+		sub.UpdatedAt,
+	)
+	return err
+}
+
+func (r *webhookRepository) UpdateSubscription(ctx context.Context, sub *output.WebhookSubscription) error {
+	span, ctx := tracer.StartSpan(ctx, "webhookRepository.UpdateSubscription")
+	defer span.End()
+
+	eventTypes, err := json.Marshal(sub.EventTypes)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		UPDATE webhook_subscriptions
+		SET url = ?, secret = ?, event_types = ?, description = ?, active = ?,
+			consecutive_failures = ?, disabled_reason = ?, updated_at = ?, disabled_at = ?
+		WHERE id = ?
+	`,
+		sub.URL,
+		sub.Secret,
+		eventTypes,
+		sub.Description,
+		sub.Active,
+		sub.ConsecutiveFailures,
+		sub.DisabledReason,
+		sub.UpdatedAt,
+		sub.DisabledAt,
+		sub.ID,
+	)
+	return err
+}
+
+func (r *webhookRepository) FindSubscription(ctx context.Context, id string) (*output.WebhookSubscription, error) {
+	span, ctx := tracer.StartSpan(ctx, "webhookRepository.FindSubscription")
+	defer span.End()
+
+	row := r.db.QueryRowContext(ctx,
+		"SELECT "+webhookSubscriptionColumns+" FROM webhook_subscriptions WHERE id = ?", id)
+	sub, err := scanWebhookSubscription(row)
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("webhook subscription")
+	}
+	return sub, err
+}
+
+func (r *webhookRepository) ListSubscriptions(ctx context.Context) ([]*output.WebhookSubscription, error) {
+	span, ctx := tracer.StartSpan(ctx, "webhookRepository.ListSubscriptions")
+	defer span.End()
+
+	return r.querySubscriptions(ctx,
+		"SELECT "+webhookSubscriptionColumns+" FROM webhook_subscriptions ORDER BY created_at DESC")
+}
+
+func (r *webhookRepository) DeleteSubscription(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM webhook_deliveries WHERE subscription_id = ?", id); err != nil {
+		return err
+	}
+	result, err := tx.ExecContext(ctx, "DELETE FROM webhook_subscriptions WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errors.NewNotFoundError("webhook subscription")
+	}
+	return tx.Commit()
+}
+
+func (r *webhookRepository) FindActiveSubscriptions(ctx context.Context, eventType string) ([]*output.WebhookSubscription, error) {
+	span, ctx := tracer.StartSpan(ctx, "webhookRepository.FindActiveSubscriptions")
+	defer span.End()
+
+	subs, err := r.querySubscriptions(ctx,
+		"SELECT "+webhookSubscriptionColumns+" FROM webhook_subscriptions WHERE active = 1")
+	if err != nil {
+		return nil, err
+	}
+
+	// 订阅数量有限，事件类型在内存中过滤
+	matched := subs[:0]
+	for _, sub := range subs {
+		if sub.Matches(eventType) {
+			matched = append(matched, sub)
+		}
+	}
+	return matched, nil
+}
+
+func (r *webhookRepository) RecordSuccess(ctx context.Context, subscriptionID string) error {
+	_, err := r.db.ExecContext(ctx,
+		"UPDATE webhook_subscriptions SET consecutive_failures = 0 WHERE id = ? AND consecutive_failures > 0",
+		subscriptionID,
+	)
+	return err
+}
+
+func (r *webhookRepository) RecordFailure(ctx context.Context, subscriptionID string, disableAfter int, reason string) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var (
+		failures int
+		active   bool
+	)
+	err = tx.QueryRowContext(ctx,
+		"SELECT consecutive_failures, active FROM webhook_subscriptions WHERE id = ? FOR UPDATE",
+		subscriptionID,
+	).Scan(&failures, &active)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	failures++
+	disable := active && disableAfter > 0 && failures >= disableAfter
+	if disable {
+		now := time.Now()
+		_, err = tx.ExecContext(ctx, `
+			UPDATE webhook_subscriptions
+			SET consecutive_failures = ?, active = 0, disabled_reason = ?, disabled_at = ?, updated_at = ?
+			WHERE id = ?
+		`, failures, reason, now, now, subscriptionID)
+	} else {
+		_, err = tx.ExecContext(ctx,
+			"UPDATE webhook_subscriptions SET consecutive_failures = ? WHERE id = ?",
+			failures, subscriptionID,
+		)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return disable, tx.Commit()
+}
+
+func (r *webhookRepository) SaveDeliveries(ctx context.Context, deliveries []*output.WebhookDelivery) error {
+	span, ctx := tracer.StartSpan(ctx, "webhookRepository.SaveDeliveries")
+	defer span.End()
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, len(deliveries))
+	args := make([]interface{}, 0, len(deliveries)*9)
+	for i, d := range deliveries {
+		placeholders[i] = "(?, ?, ?, ?, ?, ?, ?, ?, ?)"
+		args = append(args,
+			d.ID,
+			d.SubscriptionID,
+			d.EventID,
+			d.EventType,
+			d.Payload,
+			d.Status,
+			d.NextAttemptAt,
+			sql.NullString{String: d.RedeliveryOf, Valid: d.RedeliveryOf != ""},
+			d.CreatedAt,
+		)
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO webhook_deliveries
+			(id, subscription_id, event_id, event_type, payload, status, next_attempt_at, redelivery_of, created_at)
+		VALUES `+strings.Join(placeholders, ", "),
+		args...,
+	)
+	return err
+}
+
+func (r *webhookRepository) UpdateDelivery(ctx context.Context, d *output.WebhookDelivery) error {
+	span, ctx := tracer.StartSpan(ctx, "webhookRepository.UpdateDelivery")
+	defer span.End()
+
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = ?, attempts = ?, next_attempt_at = ?, last_status_code = ?, last_error = ?, delivered_at = ?
+		WHERE id = ?
+	`,
+		d.Status,
+		d.Attempts,
+		d.NextAttemptAt,
+		d.LastStatusCode,
+		d.LastError,
+		d.DeliveredAt,
+		d.ID,
+	)
+	return err
+}
+
+func (r *webhookRepository) FindDelivery(ctx context.Context, id string) (*output.WebhookDelivery, error) {
+	span, ctx := tracer.StartSpan(ctx, "webhookRepository.FindDelivery")
+	defer span.End()
+
+	row := r.db.QueryRowContext(ctx,
+		"SELECT "+webhookDeliveryColumns+" FROM webhook_deliveries WHERE id = ?", id)
+	d, err := scanWebhookDelivery(row)
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("webhook delivery")
+	}
+	return d, err
+}
+
+func (r *webhookRepository) ListDeliveries(ctx context.Context, subscriptionID string, offset, limit int) ([]*output.WebhookDelivery, int64, error) {
+	span, ctx := tracer.StartSpan(ctx, "webhookRepository.ListDeliveries")
+	defer span.End()
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM webhook_deliveries WHERE subscription_id = ?", subscriptionID,
+	).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries
+		WHERE subscription_id = ?
+		ORDER BY created_at DESC
+		LIMIT ? OFFSET ?
+	`, subscriptionID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var deliveries []*output.WebhookDelivery
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, total, rows.Err()
+}
+
+func (r *webhookRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*output.WebhookDelivery, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// SKIP LOCKED 允许多个实例并行投递；领取后推迟 next_attempt_at，进程崩溃时租约到期后自动重新投递
+	rows, err := tx.QueryContext(ctx, `
+		SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries
+		WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY next_attempt_at ASC
+		LIMIT ?
+		FOR UPDATE SKIP LOCKED
+	`, output.WebhookDeliveryPending, now, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var deliveries []*output.WebhookDelivery
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(deliveries) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(deliveries))
+	args := make([]interface{}, 0, len(deliveries)+1)
+	args = append(args, now.Add(lease))
+	for i, d := range deliveries {
+		ids[i] = "?"
+		args = append(args, d.ID)
+	}
+	if _, err := tx.ExecContext(ctx,
+		"UPDATE webhook_deliveries SET next_attempt_at = ? WHERE id IN ("+strings.Join(ids, ", ")+")",
+		args...,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// RedactDeliveries 将投递记录中的 data 置为 null，保留事件 ID 和类型，未投递的记录照常发送
+func (r *webhookRepository) RedactDeliveries(ctx context.Context, aggregateID string) (int64, error) {
+	result, err := conn(ctx, r.db).ExecContext(ctx,
+		"UPDATE webhook_deliveries SET payload = JSON_SET(payload, '$.data', NULL) WHERE aggregate_id = ?",
+		aggregateID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *webhookRepository) DeleteFinished(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx,
+		"DELETE FROM webhook_deliveries WHERE status IN (?, ?) AND created_at < ?",
+		output.WebhookDeliverySucceeded, output.WebhookDeliveryFailed, before,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *webhookRepository) querySubscriptions(ctx context.Context, query string, args ...interface{}) ([]*output.WebhookSubscription, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subs []*output.WebhookSubscription
+	for rows.Next() {
+		sub, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+	return subs, rows.Err()
+}
+
+func scanWebhookSubscription(row interface{ Scan(...interface{}) error }) (*output.WebhookSubscription, error) {
+	var (
+		sub            output.WebhookSubscription
+		eventTypes     []byte
+		description    sql.NullString
+		disabledReason sql.NullString
+	)
+	err := row.Scan(
+		&sub.ID,
+		&sub.URL,
+		&sub.Secret,
+		&eventTypes,
+		&description,
+		&sub.Active,
+		&sub.ConsecutiveFailures,
+		&disabledReason,
+		&sub.CreatedBy,
+		&sub.CreatedAt,
+		&sub.UpdatedAt,
+		&sub.DisabledAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(eventTypes, &sub.EventTypes); err != nil {
+		return nil, err
+	}
+
+	sub.Description = description.String
+	sub.DisabledReason = disabledReason.String
+	return &sub, nil
+}
+
+func scanWebhookDelivery(row interface{ Scan(...interface{}) error }) (*output.WebhookDelivery, error) {
+	var (
+		d            output.WebhookDelivery
+		statusCode   sql.NullInt64
+		lastError    sql.NullString
+		redeliveryOf sql.NullString
+	)
+	err := row.Scan(
+		&d.ID,
+		&d.SubscriptionID,
+		&d.EventID,
+		&d.EventType,
+		&d.Payload,
+		&d.Status,
+		&d.Attempts,
+		&d.NextAttemptAt,
+		&statusCode,
+		&lastError,
+		&redeliveryOf,
+		&d.CreatedAt,
+		&d.DeliveredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	d.LastStatusCode = int(statusCode.Int64)
+	d.LastError = lastError.String
+	d.RedeliveryOf = redeliveryOf.String
+	return &d, nil
+}
@@ -0,0 +1,110 @@
+package mysql
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/testutil"
+)
+
+func TestWebhookRepository_RecordFailure(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int
+		active       bool
+		disableAfter int
+		wantDisabled bool
+	}{
+		{name: "below threshold", failures: 1, active: true, disableAfter: 3},
+		{name: "reaches threshold", failures: 2, active: true, disableAfter: 3, wantDisabled: true},
+		{name: "never disables when threshold is zero", failures: 100, active: true},
+		{name: "already disabled", failures: 5, disableAfter: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectBegin()
+			mock.ExpectQuery(regexp.QuoteMeta("FOR UPDATE")).
+				WithArgs("sub-1").
+				WillReturnRows(sqlmock.NewRows([]string{"consecutive_failures", "active"}).AddRow(tt.failures, tt.active))
+			if tt.wantDisabled {
+				mock.ExpectExec(regexp.QuoteMeta("active = 0")).
+					WithArgs(tt.failures+1, "too many failures", sqlmock.AnyArg(), sqlmock.AnyArg(), "sub-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			} else {
+				mock.ExpectExec(regexp.QuoteMeta("SET consecutive_failures = ? WHERE")).
+					WithArgs(tt.failures+1, "sub-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+			mock.ExpectCommit()
+
+			repo := NewWebhookRepository(db, testutil.NopLogger{}, testutil.NewMetrics())
+			disabled, err := repo.RecordFailure(context.Background(), "sub-1", tt.disableAfter, "too many failures")
+			require.NoError(t, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+
+			assert.Equal(t, tt.wantDisabled, disabled)
+		})
+	}
+}
+
+func TestWebhookRepository_ClaimDueExtendsLease(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "subscription_id", "event_id", "event_type", "payload", "status",
+		"attempts", "next_attempt_at", "last_status_code", "last_error", "redelivery_of", "created_at", "delivered_at"}).
+		AddRow("d1", "sub-1", "evt-1", "user.created", []byte(`{}`), output.WebhookDeliveryPending,
+			0, now, nil, nil, nil, now, nil).
+		AddRow("d2", "sub-1", "evt-2", "user.locked", []byte(`{}`), output.WebhookDeliveryPending,
+			2, now, 502, "unexpected status 502", "d0", now, nil)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("FOR UPDATE SKIP LOCKED")).
+		WithArgs(output.WebhookDeliveryPending, now, 10).
+		WillReturnRows(rows)
+	mock.ExpectExec(regexp.QuoteMeta("SET next_attempt_at = ? WHERE id IN (?, ?)")).
+		WithArgs(now.Add(20*time.Second), "d1", "d2").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	repo := NewWebhookRepository(db, testutil.NopLogger{}, testutil.NewMetrics())
+	deliveries, err := repo.ClaimDue(context.Background(), now, 20*time.Second, 10)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	require.Len(t, deliveries, 2)
+	assert.Equal(t, 502, deliveries[1].LastStatusCode)
+	assert.Equal(t, "unexpected status 502", deliveries[1].LastError)
+	assert.Equal(t, "d0", deliveries[1].RedeliveryOf)
+}
+
+func TestWebhookRepository_ClaimDueNothingDue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("FOR UPDATE SKIP LOCKED")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	repo := NewWebhookRepository(db, testutil.NopLogger{}, testutil.NewMetrics())
+	deliveries, err := repo.ClaimDue(context.Background(), time.Now(), time.Minute, 10)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	assert.Empty(t, deliveries)
+}
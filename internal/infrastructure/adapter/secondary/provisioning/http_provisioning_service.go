@@ -0,0 +1,103 @@
+package provisioning
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/infrastructure/config"
+	"github.com/gohex/gohex/pkg/tracer"
+)
+
+// maxErrorBody 错误信息中保留的响应体最大字节数
+const maxErrorBody = 512
+
+// httpProvisioningService 通过 HTTP 调用下游开通服务：
+// POST {url}/users 开通，DELETE {url}/users/{id} 注销，下游需按用户 ID 保证幂等
+type httpProvisioningService struct {
+	baseURL string
+	client  *http.Client
+	logger  output.Logger
+	metrics output.MetricsReporter
+}
+
+func NewHTTPProvisioningService(cfg config.ProvisioningConfig, logger output.Logger, metrics output.MetricsReporter) output.ProvisioningService {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	return &httpProvisioningService{
+		baseURL: strings.TrimRight(cfg.URL, "/"),
+		client:  &http.Client{Timeout: timeout},
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+func (s *httpProvisioningService) Provision(ctx context.Context, userID string, email string) error {
+	span, ctx := tracer.StartSpan(ctx, "provisioningService.Provision")
+	defer span.End()
+
+	if s.baseURL == "" {
+		s.logger.Debug("provisioning disabled, skipping", "user_id", userID)
+		return nil
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"user_id": userID,
+		"email":   email,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.do(ctx, http.MethodPost, s.baseURL+"/users", body)
+}
+
+func (s *httpProvisioningService) Deprovision(ctx context.Context, userID string) error {
+	span, ctx := tracer.StartSpan(ctx, "provisioningService.Deprovision")
+	defer span.End()
+
+	if s.baseURL == "" {
+		return nil
+	}
+
+	return s.do(ctx, http.MethodDelete, s.baseURL+"/users/"+url.PathEscape(userID), nil)
+}
+
+func (s *httpProvisioningService) do(ctx context.Context, method, target string, body []byte) error {
+	timer := s.metrics.StartTimer("provisioning_request_duration", "method", method)
+	defer timer.Stop()
+
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		s.metrics.IncrementCounter("provisioning_request_failure", "method", method)
+		return err
+	}
+	defer resp.Body.Close()
+
+	// 注销不存在的用户视为成功
+	if resp.StatusCode < 300 || (method == http.MethodDelete && resp.StatusCode == http.StatusNotFound) {
+		return nil
+	}
+
+	s.metrics.IncrementCounter("provisioning_request_failure", "method", method)
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	return fmt.Errorf("provisioning %s %s: unexpected status %d: %s", method, target, resp.StatusCode, bytes.TrimSpace(msg))
+}
@@ -0,0 +1,93 @@
+package provisioning
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/infrastructure/config"
+	"github.com/gohex/gohex/internal/testutil"
+)
+
+type downstreamRequest struct {
+	method string
+	path   string
+	body   string
+}
+
+func newDownstream(t *testing.T, status int) (*httptest.Server, *[]downstreamRequest) {
+	t.Helper()
+	var requests []downstreamRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, downstreamRequest{method: r.Method, path: r.URL.EscapedPath(), body: string(body)})
+		w.WriteHeader(status)
+		w.Write([]byte("quota exceeded\n"))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestHTTPProvisioningService_Provision(t *testing.T) {
+	srv, requests := newDownstream(t, http.StatusCreated)
+	s := NewHTTPProvisioningService(config.ProvisioningConfig{URL: srv.URL + "/"}, testutil.NopLogger{}, testutil.NewMetrics())
+
+	require.NoError(t, s.Provision(context.Background(), "alice", "alice@example.com"))
+
+	require.Len(t, *requests, 1)
+	req := (*requests)[0]
+	assert.Equal(t, http.MethodPost, req.method)
+	assert.Equal(t, "/users", req.path)
+	assert.JSONEq(t, `{"user_id":"alice","email":"alice@example.com"}`, req.body)
+}
+
+func TestHTTPProvisioningService_Status(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		deprovision bool
+		wantErr     string
+	}{
+		{name: "provision rejected", status: http.StatusUnprocessableEntity,
+			wantErr: "unexpected status 422: quota exceeded"},
+		{name: "deprovision succeeds", status: http.StatusNoContent, deprovision: true},
+		{name: "deprovision of unknown user succeeds", status: http.StatusNotFound, deprovision: true},
+		{name: "deprovision fails", status: http.StatusBadGateway, deprovision: true,
+			wantErr: "unexpected status 502: quota exceeded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, requests := newDownstream(t, tt.status)
+			metrics := testutil.NewMetrics()
+			s := NewHTTPProvisioningService(config.ProvisioningConfig{URL: srv.URL}, testutil.NopLogger{}, metrics)
+
+			var err error
+			if tt.deprovision {
+				err = s.Deprovision(context.Background(), "alice/1")
+				assert.Equal(t, "/users/alice%2F1", (*requests)[0].path)
+			} else {
+				err = s.Provision(context.Background(), "alice", "alice@example.com")
+			}
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.Equal(t, 1, metrics.Counter("provisioning_request_failure"))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestHTTPProvisioningService_DisabledWithoutURL(t *testing.T) {
+	s := NewHTTPProvisioningService(config.ProvisioningConfig{}, testutil.NopLogger{}, testutil.NewMetrics())
+
+	assert.NoError(t, s.Provision(context.Background(), "alice", "alice@example.com"))
+	assert.NoError(t, s.Deprovision(context.Background(), "alice"))
+}
@@ -0,0 +1,309 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+// 所有 key 使用同一个哈希标签 {jobs}，集群模式下位于同一槽位，Lua 脚本可以同时操作
+const (
+	jobKeyPrefix   = "{jobs}:job:"
+	pendingJobsKey = "{jobs}:pending"
+	// runningJobsKey 有序集合，分数为最近一次心跳的毫秒时间戳
+	runningJobsKey = "{jobs}:running"
+)
+
+// claimScript 从待执行列表右端弹出任务，跳过已取消的任务
+var claimScript = redis.NewScript(`
+local id = redis.call('RPOP', KEYS[1])
+while id do
+	local key = ARGV[1] .. id
+	if redis.call('HGET', key, 'status') == 'pending' then
+		redis.call('HSET', key, 'status', 'running', 'started_at', ARGV[2], 'heartbeat_at', ARGV[2])
+		redis.call('HINCRBY', key, 'attempts', 1)
+		redis.call('ZADD', KEYS[2], ARGV[2], id)
+		return id
+	end
+	id = redis.call('RPOP', KEYS[1])
+end
+return false
+`)
+
+var heartbeatScript = redis.NewScript(`
+local status = redis.call('HGET', KEYS[1], 'status')
+if not status then
+	return false
+end
+if status == 'running' then
+	redis.call('HSET', KEYS[1], 'heartbeat_at', ARGV[1], 'progress', ARGV[2])
+	redis.call('ZADD', KEYS[2], ARGV[1], ARGV[3])
+end
+return redis.call('HGET', KEYS[1], 'cancel_requested')
+`)
+
+// finishScript 只结束运行中的任务，并为结果设置保留期
+var finishScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'status') ~= 'running' then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'status', ARGV[1], 'result', ARGV[2], 'error', ARGV[3], 'finished_at', ARGV[4])
+if ARGV[1] == 'succeeded' then
+	redis.call('HSET', KEYS[1], 'progress', 100)
+end
+redis.call('ZREM', KEYS[2], ARGV[5])
+redis.call('PEXPIRE', KEYS[1], ARGV[6])
+return 1
+`)
+
+var cancelScript = redis.NewScript(`
+local status = redis.call('HGET', KEYS[1], 'status')
+if not status then
+	return false
+end
+if status == 'pending' then
+	redis.call('HSET', KEYS[1], 'status', 'cancelled', 'finished_at', ARGV[1])
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	return 'cancelled'
+end
+if status == 'running' then
+	redis.call('HSET', KEYS[1], 'cancel_requested', 1)
+	return 'requested'
+end
+return 'finished'
+`)
+
+// requeueScript 心跳超时的任务重新放回待执行列表右端，优先被领取；
+// 领取次数达到上限的任务转为死信。返回 1 表示重新入队，2 表示死信
+var requeueScript = redis.NewScript(`
+local status = redis.call('HGET', KEYS[1], 'status')
+if status ~= 'running' then
+	redis.call('ZREM', KEYS[2], ARGV[2])
+	return 0
+end
+local heartbeat = tonumber(redis.call('HGET', KEYS[1], 'heartbeat_at')) or 0
+if heartbeat >= tonumber(ARGV[1]) then
+	return 0
+end
+redis.call('ZREM', KEYS[2], ARGV[2])
+if redis.call('HGET', KEYS[1], 'cancel_requested') == '1' then
+	redis.call('HSET', KEYS[1], 'status', 'cancelled', 'finished_at', ARGV[3])
+	redis.call('PEXPIRE', KEYS[1], ARGV[4])
+	return 0
+end
+if (tonumber(redis.call('HGET', KEYS[1], 'attempts')) or 0) >= tonumber(ARGV[5]) then
+	redis.call('HSET', KEYS[1], 'status', 'dead_lettered', 'error', ARGV[6], 'finished_at', ARGV[3])
+	redis.call('PEXPIRE', KEYS[1], ARGV[4])
+	return 2
+end
+redis.call('HSET', KEYS[1], 'status', 'pending', 'started_at', '', 'heartbeat_at', '')
+redis.call('RPUSH', KEYS[3], ARGV[2])
+return 1
+`)
+
+type jobQueue struct {
+	client    redis.UniversalClient
+	retention time.Duration
+	logger    output.Logger
+	metrics   output.MetricsReporter
+}
+
+// NewJobQueue 基于 Redis 的任务队列，结束的任务在 retention 后自动过期
+func NewJobQueue(client redis.UniversalClient, retention time.Duration, logger output.Logger, metrics output.MetricsReporter) output.JobQueue {
+	if retention <= 0 {
+		retention = 7 * 24 * time.Hour
+	}
+	return &jobQueue{
+		client:    client,
+		retention: retention,
+		logger:    logger,
+		metrics:   metrics,
+	}
+}
+
+func (q *jobQueue) Enqueue(ctx context.Context, job *output.Job) error {
+	_, err := q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, jobKeyPrefix+job.ID, map[string]interface{}{
+			"command_type":     job.CommandType,
+			"payload":          job.Payload,
+			"status":           job.Status,
+			"progress":         0,
+			"requested_by":     job.RequestedBy,
+			"impersonator_id":  job.ImpersonatorID,
+			"ip":               job.IP,
+			"user_agent":       job.UserAgent,
+			"cancel_requested": 0,
+			"attempts":         0,
+			"created_at":       job.CreatedAt.UnixMilli(),
+		})
+		pipe.LPush(ctx, pendingJobsKey, job.ID)
+		return nil
+	})
+	if err != nil {
+		q.metrics.IncrementCounter("job_enqueue_failure")
+		return err
+	}
+
+	q.metrics.IncrementCounter("job_enqueued", "command_type", job.CommandType)
+	return nil
+}
+
+func (q *jobQueue) FindByID(ctx context.Context, id string) (*output.Job, error) {
+	fields, err := q.client.HGetAll(ctx, jobKeyPrefix+id).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, errors.NewNotFoundError("job")
+	}
+	return toJob(id, fields), nil
+}
+
+func (q *jobQueue) Claim(ctx context.Context) (*output.Job, error) {
+	now := time.Now().UnixMilli()
+	id, err := claimScript.Run(ctx, q.client,
+		[]string{pendingJobsKey, runningJobsKey},
+		jobKeyPrefix, now,
+	).Text()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return q.FindByID(ctx, id)
+}
+
+func (q *jobQueue) Heartbeat(ctx context.Context, id string, progress int) (bool, error) {
+	cancelRequested, err := heartbeatScript.Run(ctx, q.client,
+		[]string{jobKeyPrefix + id, runningJobsKey},
+		time.Now().UnixMilli(), progress, id,
+	).Text()
+	if err == redis.Nil {
+		return false, errors.NewNotFoundError("job")
+	}
+	if err != nil {
+		return false, err
+	}
+	return cancelRequested == "1", nil
+}
+
+func (q *jobQueue) Complete(ctx context.Context, id string, result []byte) error {
+	return q.finish(ctx, id, output.JobSucceeded, result, "")
+}
+
+func (q *jobQueue) Fail(ctx context.Context, id string, reason string) error {
+	return q.finish(ctx, id, output.JobFailed, nil, reason)
+}
+
+func (q *jobQueue) MarkCancelled(ctx context.Context, id string) error {
+	return q.finish(ctx, id, output.JobCancelled, nil, "")
+}
+
+func (q *jobQueue) finish(ctx context.Context, id, status string, result []byte, reason string) error {
+	err := finishScript.Run(ctx, q.client,
+		[]string{jobKeyPrefix + id, runningJobsKey},
+		status, result, reason, time.Now().UnixMilli(), id, q.retention.Milliseconds(),
+	).Err()
+	if err != nil {
+		return err
+	}
+
+	q.metrics.IncrementCounter("job_finished", "status", status)
+	return nil
+}
+
+func (q *jobQueue) Cancel(ctx context.Context, id string) error {
+	outcome, err := cancelScript.Run(ctx, q.client,
+		[]string{jobKeyPrefix + id},
+		time.Now().UnixMilli(), q.retention.Milliseconds(),
+	).Text()
+	if err == redis.Nil {
+		return errors.NewNotFoundError("job")
+	}
+	if err != nil {
+		return err
+	}
+	if outcome == "finished" {
+		return errors.ErrJobNotCancellable
+	}
+	return nil
+}
+
+func (q *jobQueue) RequeueStale(ctx context.Context, before time.Time, maxAttempts int) (int64, int64, error) {
+	cutoff := before.UnixMilli()
+	ids, err := q.client.ZRangeByScore(ctx, runningJobsKey, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: "(" + strconv.FormatInt(cutoff, 10),
+	}).Result()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	reason := fmt.Sprintf("job abandoned after %d attempts without completing", maxAttempts)
+	var requeued, deadLettered int64
+	for _, id := range ids {
+		outcome, err := requeueScript.Run(ctx, q.client,
+			[]string{jobKeyPrefix + id, runningJobsKey, pendingJobsKey},
+			cutoff, id, time.Now().UnixMilli(), q.retention.Milliseconds(), maxAttempts, reason,
+		).Int64()
+		if err != nil {
+			return requeued, deadLettered, err
+		}
+		switch outcome {
+		case 1:
+			requeued++
+		case 2:
+			deadLettered++
+		}
+	}
+	return requeued, deadLettered, nil
+}
+
+// DeleteFinished 结束的任务已设置过期时间，由 Redis 自动删除
+func (q *jobQueue) DeleteFinished(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func toJob(id string, fields map[string]string) *output.Job {
+	progress, _ := strconv.Atoi(fields["progress"])
+	attempts, _ := strconv.Atoi(fields["attempts"])
+	job := &output.Job{
+		ID:              id,
+		CommandType:     fields["command_type"],
+		Payload:         []byte(fields["payload"]),
+		Status:          fields["status"],
+		Progress:        progress,
+		Error:           fields["error"],
+		RequestedBy:     fields["requested_by"],
+		ImpersonatorID:  fields["impersonator_id"],
+		IP:              fields["ip"],
+		UserAgent:       fields["user_agent"],
+		Attempts:        attempts,
+		CancelRequested: fields["cancel_requested"] == "1",
+		StartedAt:       parseMillis(fields["started_at"]),
+		FinishedAt:      parseMillis(fields["finished_at"]),
+		HeartbeatAt:     parseMillis(fields["heartbeat_at"]),
+	}
+	if result := fields["result"]; result != "" {
+		job.Result = []byte(result)
+	}
+	if createdAt := parseMillis(fields["created_at"]); createdAt != nil {
+		job.CreatedAt = *createdAt
+	}
+	return job
+}
+
+func parseMillis(value string) *time.Time {
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || ms == 0 {
+		return nil
+	}
+	t := time.UnixMilli(ms)
+	return &t
+}
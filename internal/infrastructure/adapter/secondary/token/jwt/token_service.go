@@ -2,14 +2,22 @@ package jwt
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"strconv"
 	"time"
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/gohex/gohex/internal/domain/aggregate"
-	"github.com/gohex/gohex/internal/application/port"
+	"github.com/gohex/gohex/internal/application/port/output"
 	"github.com/gohex/gohex/pkg/errors"
-	"github.com/gohex/gohex/pkg/tracer"
 )
 
+// passwordResetTTL 密码重置令牌有效期
+const passwordResetTTL = time.Hour
+
+// purposePasswordReset 密码重置令牌的用途声明，不能用于访问接口
+const purposePasswordReset = "password_reset"
+
 type Config struct {
 	SecretKey     string
 	TokenDuration time.Duration
@@ -17,17 +25,17 @@ type Config struct {
 
 type jwtTokenService struct {
 	config  Config
-	cache   port.Cache // 用于存储已吊销的令牌
-	logger  Logger
-	metrics MetricsReporter
+	cache   output.Cache // 用于存储已吊销的令牌
+	logger  output.Logger
+	metrics output.MetricsReporter
 }
 
 func NewJWTTokenService(
 	config Config,
-	cache port.Cache,
-	logger Logger,
-	metrics MetricsReporter,
-) port.TokenService {
+	cache output.Cache,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) output.TokenService {
 	return &jwtTokenService{
 		config:  config,
 		cache:   cache,
@@ -40,12 +48,14 @@ func (s *jwtTokenService) GenerateToken(user *aggregate.User) (string, time.Time
 	timer := s.metrics.StartTimer("token_generation_duration")
 	defer timer.Stop()
 
-	expiresAt := time.Now().Add(s.config.TokenDuration)
+	now := time.Now()
+	expiresAt := now.Add(s.config.TokenDuration)
 
 	claims := jwt.MapClaims{
 		"user_id": user.ID(),
 		"email":   user.Email().String(),
 		"roles":   user.RoleStrings(),
+		"iat":     issuedAt(now),
 		"exp":     expiresAt.Unix(),
 	}
 
@@ -61,12 +71,71 @@ func (s *jwtTokenService) GenerateToken(user *aggregate.User) (string, time.Time
 	return signedToken, expiresAt, nil
 }
 
-func (s *jwtTokenService) ValidateToken(ctx context.Context, tokenString string) (*port.TokenClaims, error) {
+func (s *jwtTokenService) GenerateImpersonationToken(actor *aggregate.User, subject *aggregate.User, ttl time.Duration) (string, time.Time, error) {
+	timer := s.metrics.StartTimer("token_generation_duration", "type", "impersonation")
+	defer timer.Stop()
+
+	// 模拟令牌不得超过普通令牌的有效期
+	if ttl <= 0 || ttl > s.config.TokenDuration {
+		ttl = s.config.TokenDuration
+	}
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+
+	claims := jwt.MapClaims{
+		"user_id": subject.ID(),
+		"email":   subject.Email().String(),
+		"roles":   subject.RoleStrings(),
+		"iat":     issuedAt(now),
+		"exp":     expiresAt.Unix(),
+		// RFC 8693: act 声明标识实际操作人
+		"act": map[string]interface{}{
+			"sub": actor.ID(),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signedToken, err := token.SignedString([]byte(s.config.SecretKey))
+	if err != nil {
+		s.logger.Error("failed to sign impersonation token", "error", err)
+		s.metrics.IncrementCounter("token_generation_failure", "type", "impersonation")
+		return "", time.Time{}, err
+	}
+
+	s.metrics.IncrementCounter("token_generation_success", "type", "impersonation")
+	return signedToken, expiresAt, nil
+}
+
+func (s *jwtTokenService) GeneratePasswordResetToken(user *aggregate.User) (string, error) {
+	claims := jwt.MapClaims{
+		"user_id": user.ID(),
+		"purpose": purposePasswordReset,
+		"iat":     time.Now().Unix(),
+		"exp":     time.Now().Add(passwordResetTTL).Unix(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signedToken, err := token.SignedString([]byte(s.config.SecretKey))
+	if err != nil {
+		s.logger.Error("failed to sign password reset token", "error", err)
+		s.metrics.IncrementCounter("token_generation_failure", "type", "password_reset")
+		return "", err
+	}
+
+	s.metrics.IncrementCounter("token_generation_success", "type", "password_reset")
+	return signedToken, nil
+}
+
+func (s *jwtTokenService) ValidateToken(ctx context.Context, tokenString string) (*output.TokenClaims, error) {
 	timer := s.metrics.StartTimer("token_validation_duration")
 	defer timer.Stop()
 
-	// 1. 检查令牌是否被吊销
-	if s.isTokenRevoked(ctx, tokenString) {
+	// 1. 检查令牌是否被吊销，吊销状态无法确认时拒绝
+	revoked, err := s.isTokenRevoked(ctx, tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
 		return nil, errors.ErrTokenRevoked
 	}
 
@@ -85,7 +154,7 @@ func (s *jwtTokenService) ValidateToken(ctx context.Context, tokenString string)
 
 	// 3. 验证声明
 	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok || !token.Valid {
+	if !ok || !token.Valid || claims["purpose"] != nil {
 		s.metrics.IncrementCounter("token_validation_failure")
 		return nil, errors.ErrInvalidToken
 	}
@@ -97,12 +166,35 @@ func (s *jwtTokenService) ValidateToken(ctx context.Context, tokenString string)
 		roles[i] = role.(string)
 	}
 
+	// 5. 检查用户级吊销，缺少签发时间的令牌无法判断，一律拒绝
+	iat, ok := claims["iat"].(float64)
+	if !ok {
+		s.metrics.IncrementCounter("token_validation_failure")
+		return nil, errors.ErrInvalidToken
+	}
+	revoked, err = s.isRevokedForUser(ctx, claims["user_id"].(string), iat)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		s.metrics.IncrementCounter("token_validation_failure")
+		return nil, errors.ErrTokenRevoked
+	}
+
+	var actor *output.TokenActor
+	if act, ok := claims["act"].(map[string]interface{}); ok {
+		if sub, ok := act["sub"].(string); ok && sub != "" {
+			actor = &output.TokenActor{Subject: sub}
+		}
+	}
+
 	s.metrics.IncrementCounter("token_validation_success")
-	return &port.TokenClaims{
+	return &output.TokenClaims{
 		UserID:    claims["user_id"].(string),
 		Email:     claims["email"].(string),
 		Roles:     roles,
 		ExpiresAt: expiresAt,
+		Actor:     actor,
 	}, nil
 }
 
@@ -122,8 +214,63 @@ func (s *jwtTokenService) RevokeToken(ctx context.Context, token string) error {
 	return nil
 }
 
-func (s *jwtTokenService) isTokenRevoked(ctx context.Context, token string) bool {
+func (s *jwtTokenService) isTokenRevoked(ctx context.Context, token string) (bool, error) {
 	key := "revoked_token:" + token
-	revoked, _ := s.cache.Get(ctx, key)
-	return revoked != nil
-} 
\ No newline at end of file
+	revoked, err := s.cache.Get(ctx, key)
+	if err != nil {
+		return false, s.revocationUnavailable(err)
+	}
+	return revoked != nil, nil
+}
+
+func (s *jwtTokenService) RevokeUserTokens(ctx context.Context, userID string) error {
+	// 记录吊销时间，早于该时间签发的令牌全部失效
+	key := "revoked_user:" + userID
+	if err := s.cache.Set(ctx, key, time.Now().UnixMilli(), s.config.TokenDuration); err != nil {
+		s.logger.Error("failed to revoke user tokens", "user_id", userID, "error", err)
+		s.metrics.IncrementCounter("token_revocation_failure", "type", "user")
+		return err
+	}
+
+	s.metrics.IncrementCounter("token_revocation_success", "type", "user")
+	return nil
+}
+
+// isRevokedForUser 吊销时间和签发时间精度均为毫秒，同一毫秒内签发的令牌也视为已吊销
+func (s *jwtTokenService) isRevokedForUser(ctx context.Context, userID string, iat float64) (bool, error) {
+	revokedAt, err := s.cache.Get(ctx, "revoked_user:"+userID)
+	if err != nil {
+		return false, s.revocationUnavailable(err)
+	}
+	if revokedAt == nil {
+		return false, nil
+	}
+
+	issuedAtMilli := int64(math.Round(iat * 1000))
+	switch v := revokedAt.(type) {
+	case int64:
+		return issuedAtMilli <= v, nil
+	case float64:
+		return issuedAtMilli <= int64(v), nil
+	case string:
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return false, s.revocationUnavailable(err)
+		}
+		return issuedAtMilli <= n, nil
+	}
+	return false, s.revocationUnavailable(fmt.Errorf("unexpected revocation value %T", revokedAt))
+}
+
+// revocationUnavailable 缓存不可用时无法确认令牌是否已吊销，按失败关闭处理；
+// 保留原始错误，熔断器据此统计依赖故障
+func (s *jwtTokenService) revocationUnavailable(err error) error {
+	s.logger.Error("failed to check token revocation", "error", err)
+	s.metrics.IncrementCounter("token_revocation_check_failure")
+	return fmt.Errorf("%w: %v", errors.ErrTokenRevocationUnavailable, err)
+}
+
+// issuedAt 以带小数的秒表示签发时间，保留毫秒精度供吊销比较
+func issuedAt(t time.Time) float64 {
+	return float64(t.UnixMilli()) / 1000
+}
@@ -0,0 +1,64 @@
+package jwt
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/vo"
+	"github.com/gohex/gohex/internal/infrastructure/adapter/secondary/cache/memory"
+	"github.com/gohex/gohex/internal/testutil"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+// unavailableCache 模拟 Redis 不可用
+type unavailableCache struct {
+	output.Cache
+}
+
+func (unavailableCache) Get(ctx context.Context, key string) (interface{}, error) {
+	return nil, stderrors.New("connection refused")
+}
+
+func newTokenService(cache output.Cache) output.TokenService {
+	return NewJWTTokenService(Config{SecretKey: "secret", TokenDuration: time.Hour},
+		cache, testutil.NopLogger{}, testutil.NewMetrics())
+}
+
+func TestTokenService_RevokeUserTokensWithinSameSecond(t *testing.T) {
+	ctx := context.Background()
+	svc := newTokenService(memory.NewCache(time.Minute, testutil.NopLogger{}, testutil.NewMetrics()))
+	user := testutil.NewUser("alice", vo.StatusActive, vo.RoleUser)
+
+	before, _, err := svc.GenerateToken(user)
+	require.NoError(t, err)
+	time.Sleep(2 * time.Millisecond)
+	require.NoError(t, svc.RevokeUserTokens(ctx, "alice"))
+	time.Sleep(2 * time.Millisecond)
+	after, _, err := svc.GenerateToken(user)
+	require.NoError(t, err)
+
+	// 两个令牌通常在同一秒内签发，按毫秒比较才能区分
+	_, err = svc.ValidateToken(ctx, before)
+	assert.ErrorIs(t, err, errors.ErrTokenRevoked)
+	claims, err := svc.ValidateToken(ctx, after)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", claims.UserID)
+}
+
+func TestTokenService_FailsClosedWhenCacheUnavailable(t *testing.T) {
+	svc := newTokenService(unavailableCache{})
+	token, _, err := svc.GenerateToken(testutil.NewUser("alice", vo.StatusActive, vo.RoleUser))
+	require.NoError(t, err)
+
+	claims, err := svc.ValidateToken(context.Background(), token)
+	assert.Nil(t, claims)
+	assert.ErrorIs(t, err, errors.ErrTokenRevocationUnavailable)
+	// 不是直接的 AppError，熔断器将其计为依赖故障
+	assert.False(t, errors.IsAppError(err))
+}
@@ -0,0 +1,66 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/infrastructure/config"
+)
+
+// RetentionWorker 定期清理超过保留期的审计记录
+type RetentionWorker struct {
+	auditLog output.AuditLog
+	config   config.AuditConfig
+	logger   output.Logger
+	metrics  output.MetricsReporter
+}
+
+func NewRetentionWorker(
+	auditLog output.AuditLog,
+	cfg config.AuditConfig,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *RetentionWorker {
+	return &RetentionWorker{
+		auditLog: auditLog,
+		config:   cfg,
+		logger:   logger,
+		metrics:  metrics,
+	}
+}
+
+// Start 启动清理循环，直到 ctx 取消
+func (w *RetentionWorker) Start(ctx context.Context) {
+	if w.config.Retention <= 0 {
+		w.logger.Info("audit retention disabled, entries are kept forever")
+		return
+	}
+
+	interval := w.config.PurgeInterval
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			w.purge(ctx)
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (w *RetentionWorker) purge(ctx context.Context) {
+	before := time.Now().Add(-w.config.Retention)
+	if _, err := w.auditLog.Purge(ctx, before); err != nil {
+		w.logger.Error("audit retention purge failed", "error", err)
+		w.metrics.IncrementCounter("audit_purge_failure")
+	}
+}
@@ -2,24 +2,44 @@ package bootstrap
 
 import (
 	"context"
-	"net/http"
 	"github.com/gohex/gohex/internal/infrastructure/config"
-	"github.com/gohex/gohex/internal/infrastructure/container"
-	"github.com/gohex/gohex/internal/application/command"
-	"github.com/gohex/gohex/internal/application/query"
-	"github.com/gohex/gohex/internal/domain/event"
-	"github.com/gohex/gohex/internal/domain/service"
+	"github.com/gohex/gohex/internal/infrastructure/observability"
+	httpadapter "github.com/gohex/gohex/internal/infrastructure/adapter/primary/http"
+	"github.com/gohex/gohex/internal/infrastructure/adapter/secondary/cache/multitier"
+	"github.com/gohex/gohex/internal/infrastructure/adapter/secondary/cache/redis"
+	"github.com/gohex/gohex/internal/infrastructure/adapter/secondary/persistence/mysql"
+	"github.com/gohex/gohex/internal/infrastructure/adapter/secondary/provisioning"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/application/saga"
+	"github.com/gohex/gohex/internal/infrastructure/audit"
+	eventhandler "github.com/gohex/gohex/internal/infrastructure/event/handler"
+	"github.com/gohex/gohex/internal/infrastructure/gdpr"
+	"github.com/gohex/gohex/internal/infrastructure/jobs"
+	"github.com/gohex/gohex/internal/infrastructure/resilience"
+	"github.com/gohex/gohex/internal/infrastructure/scheduler"
+	"github.com/gohex/gohex/internal/infrastructure/validator"
+	"github.com/gohex/gohex/internal/infrastructure/webhook"
+	appservice "github.com/gohex/gohex/internal/application/service"
+	"github.com/gohex/gohex/pkg/uow"
+	cmdbus "github.com/gohex/gohex/internal/application/port/input/command"
+	querybus "github.com/gohex/gohex/internal/application/port/input/query"
 )
 
 type Application struct {
-	config      *config.Config
-	logger      Logger
-	metrics     MetricsReporter
-	tracer      Tracer
-	commandBus  command.Bus
-	queryBus    query.Bus
-	eventBus    event.Bus
-	httpServer  *http.Server
+	config       *config.Config
+	logger       output.Logger
+	metrics      output.MetricsReporter
+	tracer       *observability.Tracer
+	commandBus   cmdbus.Bus
+	queryBus     querybus.Bus
+	eventBus     output.EventBus
+	httpServer   *httpadapter.Server
+	auditWorker  *audit.RetentionWorker
+	exportWorker *gdpr.ExportWorker
+	jobWorker    *jobs.Worker
+	webhookWorker *webhook.DeliveryWorker
+	schedulerWorker *scheduler.Worker
+	cache        *multitier.Cache
 }
 
 func NewApplication(configPath string) (*Application, error) {
@@ -30,60 +50,164 @@ func NewApplication(configPath string) (*Application, error) {
 	}
 
 	// 2. 初始化基础设施
-	logger := initLogger(cfg.Log)
+	logger := initLogger(cfg)
 	metrics := initMetrics(cfg.Metrics)
-	tracer := initTracer(cfg.Tracing)
+	tracer := initTracer(cfg)
+	breakers := resilience.NewRegistry(cfg.Resilience, logger, metrics)
 
-	// 3. 初始化数据库连接
+	// 3. 初始化数据库连接和缓存
 	db := initDatabase(cfg.Database)
-	cache := initCache(cfg.Redis)
+	redisClient := initRedisClient(cfg.Redis)
+	cache := initCache(cfg, redisClient, breakers, logger, metrics)
 
 	// 4. 创建仓储
+	masterKey, err := cfg.GDPR.MasterKeyBytes()
+	if err != nil {
+		return nil, err
+	}
+	keyStore := mysql.NewKeyStore(db, masterKey, logger, metrics)
 	userRepo := mysql.NewUserRepository(db, logger, metrics)
-	eventStore := mysql.NewEventStore(db, logger, metrics)
+	eventStore := mysql.NewEventStore(db, keyStore, logger, metrics)
+	auditLog := mysql.NewAuditLog(db, logger, metrics)
+	exportRepo := mysql.NewDataExportRepository(db, logger, metrics)
+	changeRepo := mysql.NewEmailChangeRepository(db, logger, metrics)
+	verificationRepo := mysql.NewEmailVerificationRepository(db, logger, metrics)
+	jobQueue := initJobQueue(cfg, db, redisClient, logger, metrics)
+	webhookRepo := mysql.NewWebhookRepository(db, logger, metrics)
+	processedEvents := mysql.NewProcessedEventStore(db, logger, metrics)
+	sagaRepo := mysql.NewSagaRepository(db, logger, metrics)
+	scheduleStore := mysql.NewScheduleStore(db, logger, metrics)
+	idempotencyStore := redis.NewIdempotencyStore(redisClient, logger, metrics)
 
 	// 5. 创建服务
-	userService := service.NewUserService(userRepo, logger)
-	authService := service.NewAuthService(cfg.JWT, logger)
-	emailService := service.NewEmailService(cfg.SMTP, logger)
+	tokenService := initTokenService(cfg, initRevocationStore(cfg, redisClient, logger, metrics), breakers, logger, metrics)
+	emailService := initEmailService(cfg.SMTP, breakers, logger, metrics)
+
+	// 6. 创建命令和查询总线，命令事务与事件处理共用同一个工作单元
+	unitOfWork := uow.NewUnitOfWork(db, logger, metrics)
+	validators := validator.NewValidatorFactory(logger)
+	commandBus := initCommandBus(cfg, logger, metrics, unitOfWork, validators.CreateCommandValidator(),
+		auditLog, userRepo, keyStore, jobQueue, idempotencyStore, scheduleStore)
+	queryBus := initQueryBus(cfg, logger, metrics, cache, validators.CreateQueryValidator())
+	eventBus := initEventBus(cfg, breakers, logger, metrics)
+	subscribeCacheInvalidation(eventBus, eventhandler.NewCacheInvalidationHandler(cache, logger, metrics))
+	notifications := eventhandler.NewUserEventHandler(emailService, logger, metrics)
+	subscribeDeduplicated(eventBus,
+		eventhandler.NewIdempotentHandler(notifications, processedEvents, unitOfWork, logger, metrics),
+		notifications.EventTypes(),
+	)
+	sagaManager := saga.NewManager(sagaRepo, commandBus, logger, metrics,
+		saga.NewOnboardingSaga(cfg.Sagas.Onboarding.VerificationTimeout),
+	)
+	subscribeDeduplicated(eventBus,
+		eventhandler.NewIdempotentHandler(sagaManager, processedEvents, unitOfWork, logger, metrics),
+		sagaManager.EventTypes(),
+	)
+	if cfg.Webhooks.Enabled {
+		webhooks := eventhandler.NewWebhookHandler(webhookRepo, logger, metrics)
+		subscribeDeduplicated(eventBus,
+			eventhandler.NewIdempotentHandler(webhooks, processedEvents, unitOfWork, logger, metrics),
+			webhooks.EventTypes(),
+		)
+	}
 
-	// 6. 创建命令和查询总线
-	commandBus := initCommandBus(cfg, logger, metrics, db)
-	queryBus := initQueryBus(cfg, logger, metrics, cache)
-	eventBus := initEventBus(cfg, logger, metrics)
+	// 7. 注册处理器并校验必需的处理器均已注册
+	deps := handlerDeps{
+		userRepo:         userRepo,
+		eventStore:       eventStore,
+		eventBus:         eventBus,
+		keyStore:         keyStore,
+		auditLog:         auditLog,
+		exportRepo:       exportRepo,
+		changeRepo:       changeRepo,
+		verificationRepo: verificationRepo,
+		webhookRepo:      webhookRepo,
+		jobQueue:         jobQueue,
+		scheduleStore:    scheduleStore,
+		cache:            cache,
+		uow:              unitOfWork,
+		tokenSvc:         tokenService,
+		emailSvc:         emailService,
+		provisioning:     provisioning.NewHTTPProvisioningService(cfg.Provisioning, logger, metrics),
+		sagas:            sagaManager,
+	}
+	registerCommandHandlers(cfg, commandBus, deps, logger, metrics)
+	registerQueryHandlers(queryBus, deps, logger, metrics)
+	if err := verifyBuses(commandBus, queryBus, logger); err != nil {
+		return nil, err
+	}
 
-	// 7. 创建 HTTP 服务器
-	httpServer := initHTTPServer(cfg.HTTP, logger, metrics)
+	// 8. 创建 HTTP 服务器
+	httpServer := initHTTPServer(cfg, commandBus, queryBus, tokenService, breakers, db, redisClient, logger, metrics)
 
 	return &Application{
-		config:     cfg,
-		logger:     logger,
-		metrics:    metrics,
-		tracer:     tracer,
-		commandBus: commandBus,
-		queryBus:   queryBus,
-		eventBus:   eventBus,
-		httpServer: httpServer,
+		config:      cfg,
+		logger:      logger,
+		metrics:     metrics,
+		tracer:      tracer,
+		commandBus:  commandBus,
+		queryBus:    queryBus,
+		eventBus:    eventBus,
+		httpServer:  httpServer,
+		auditWorker: audit.NewRetentionWorker(auditLog, cfg.CommandBus.Middleware.Audit, logger, metrics),
+		exportWorker: gdpr.NewExportWorker(
+			exportRepo,
+			appservice.NewDataExportService(userRepo, eventStore, auditLog, logger, metrics),
+			cfg.GDPR,
+			logger,
+			metrics,
+		),
+		jobWorker:   jobs.NewWorker(jobQueue, commandBus, cfg.Jobs, logger, metrics),
+		webhookWorker: webhook.NewDeliveryWorker(webhookRepo, cfg.Webhooks, logger, metrics),
+		schedulerWorker: scheduler.NewWorker(
+			scheduleStore,
+			mysql.NewLeaderLease(db, logger, metrics),
+			commandBus,
+			cfg.Scheduler,
+			logger,
+			metrics,
+		),
+		cache:       cache,
 	}, nil
 }
 
+// Config 返回应用配置
+func (app *Application) Config() *config.Config {
+	return app.config
+}
+
 func (app *Application) Start(ctx context.Context) error {
-	// 1. 启动追踪器
-	if err := app.tracer.Start(ctx); err != nil {
-		return err
+	// 1. 启动事件总线，外部消息中间件在此创建流或开始消费
+	if s, ok := app.eventBus.(interface{ Start(context.Context) error }); ok {
+		if err := s.Start(ctx); err != nil {
+			return err
+		}
 	}
 
-	// 2. 启动指标收集器
-	if err := app.metrics.Start(ctx); err != nil {
-		return err
-	}
+	// 订阅其他实例的进程内缓存失效消息
+	app.cache.Start(ctx)
+
+	// 2. 启动审计日志清理
+	app.auditWorker.Start(ctx)
+
+	// 3. 启动数据导出任务
+	app.exportWorker.Start(ctx)
+
+	// 4. 启动异步命令任务
+	app.jobWorker.Start(ctx)
 
-	// 3. 启动事件总线
-	if err := app.eventBus.Start(ctx); err != nil {
-		return err
+	// 5. 启动 webhook 投递
+	app.webhookWorker.Start(ctx)
+
+	// 6. 注册周期计划并启动调度器，已删除用户清理和 saga 步骤超时同样由调度器分发
+	if app.config.Scheduler.Enabled {
+		if err := scheduleRecurringCommands(ctx, app.commandBus); err != nil {
+			return err
+		}
 	}
+	app.schedulerWorker.Start(ctx)
 
-	// 4. 启动 HTTP 服务器
+	// 7. 启动 HTTP 服务器
 	return app.httpServer.Start()
 }
 
@@ -93,20 +217,17 @@ func (app *Application) Stop(ctx context.Context) error {
 		app.logger.Error("failed to stop http server", "error", err)
 	}
 
-	// 2. 停止事件总线
-	if err := app.eventBus.Stop(ctx); err != nil {
+	// 2. 停止事件总线，等待已入队的事件处理完成
+	if err := app.eventBus.Close(); err != nil {
 		app.logger.Error("failed to stop event bus", "error", err)
 	}
 
-	// 3. 停止指标收集器
-	if err := app.metrics.Stop(ctx); err != nil {
-		app.logger.Error("failed to stop metrics reporter", "error", err)
-	}
-
-	// 4. 停止追踪器
-	if err := app.tracer.Stop(ctx); err != nil {
-		app.logger.Error("failed to stop tracer", "error", err)
+	// 3. 停止追踪器，导出剩余的 span
+	if app.tracer != nil {
+		if err := app.tracer.Close(); err != nil {
+			app.logger.Error("failed to stop tracer", "error", err)
+		}
 	}
 
 	return nil
-} 
\ No newline at end of file
+}
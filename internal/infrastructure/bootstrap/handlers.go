@@ -0,0 +1,150 @@
+package bootstrap
+
+import (
+	"github.com/gohex/gohex/internal/application/command"
+	"github.com/gohex/gohex/internal/application/dto"
+	cmdbus "github.com/gohex/gohex/internal/application/port/input/command"
+	querybus "github.com/gohex/gohex/internal/application/port/input/query"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/application/query"
+	"github.com/gohex/gohex/internal/application/saga"
+	"github.com/gohex/gohex/internal/infrastructure/config"
+)
+
+// handlerDeps 命令和查询处理器依赖的仓储与服务
+type handlerDeps struct {
+	userRepo         output.UserRepository
+	eventStore       output.EventStore
+	eventBus         output.EventBus
+	keyStore         output.KeyStore
+	auditLog         output.AuditLog
+	exportRepo       output.DataExportRepository
+	changeRepo       output.EmailChangeRepository
+	verificationRepo output.EmailVerificationRepository
+	webhookRepo      output.WebhookRepository
+	jobQueue         output.JobQueue
+	scheduleStore    output.ScheduleStore
+	cache            output.Cache
+	uow              output.UnitOfWork
+	tokenSvc         output.TokenService
+	emailSvc         output.EmailService
+	provisioning     output.ProvisioningService
+	sagas            *saga.Manager
+}
+
+// registerCommandHandlers 以强类型处理器注册所有命令，除 RegisterUserCommand 外命令均以指针类型分发
+func registerCommandHandlers(cfg *config.Config, bus cmdbus.Bus, deps handlerDeps, logger output.Logger, metrics output.MetricsReporter) {
+	// 注册与认证
+	cmdbus.RegisterHandler[command.RegisterUserCommand, command.RegisterUserResult](bus,
+		command.NewRegisterUserHandler(deps.userRepo, deps.eventStore, deps.eventBus, deps.uow, logger, metrics))
+	cmdbus.RegisterHandler[*command.LoginCommand, *dto.LoginResponseDTO](bus, command.NewLoginHandler(
+		deps.userRepo, deps.tokenSvc, deps.eventStore, deps.eventBus, deps.uow, deps.cache, bus, logger, metrics))
+	cmdbus.RegisterHandler[*command.LogoutCommand, struct{}](bus, command.NewLogoutHandler(deps.tokenSvc, logger, metrics))
+	cmdbus.RegisterHandler[*command.UnlockUserCommand, struct{}](bus, command.NewUnlockUserHandler(
+		deps.userRepo, deps.eventStore, deps.eventBus, deps.uow, deps.cache, logger, metrics))
+	cmdbus.RegisterHandler[*command.ImpersonateUserCommand, *dto.ImpersonationResponseDTO](bus, command.NewImpersonateUserHandler(
+		deps.userRepo, deps.tokenSvc, deps.eventStore, deps.uow,
+		cfg.Auth.Impersonation.Enabled, cfg.Auth.Impersonation.TTL, logger, metrics))
+	cmdbus.RegisterHandler[*command.EndImpersonationCommand, struct{}](bus,
+		command.NewEndImpersonationHandler(deps.userRepo, deps.tokenSvc, deps.eventStore, deps.uow, logger, metrics))
+
+	// 用户资料、密码和角色
+	cmdbus.RegisterHandler[*command.UpdateUserProfileCommand, struct{}](bus, command.NewUpdateUserProfileHandler(
+		deps.userRepo, deps.eventStore, deps.eventBus, deps.cache, deps.uow, logger, metrics))
+	cmdbus.RegisterHandler[*command.ChangeUserStatusCommand, struct{}](bus,
+		command.NewChangeUserStatusHandler(deps.userRepo, deps.eventStore, deps.eventBus, deps.uow, logger, metrics))
+	cmdbus.RegisterHandler[*command.AssignRoleCommand, struct{}](bus,
+		command.NewAssignRoleHandler(deps.userRepo, deps.eventStore, deps.eventBus, deps.uow, logger, metrics))
+	cmdbus.RegisterHandler[*command.BulkAssignRoleCommand, *command.BulkAssignRoleResult](bus,
+		command.NewBulkAssignRoleHandler(deps.userRepo, deps.eventStore, deps.eventBus, deps.uow, logger, metrics))
+	cmdbus.RegisterHandler[*command.ChangePasswordCommand, struct{}](bus,
+		command.NewChangePasswordHandler(deps.userRepo, deps.eventStore, deps.uow, logger, metrics))
+	cmdbus.RegisterHandler[*command.ResetPasswordCommand, struct{}](bus,
+		command.NewResetPasswordHandler(deps.userRepo, deps.eventStore, deps.uow, logger, metrics))
+	cmdbus.RegisterHandler[*command.RequestPasswordResetCommand, struct{}](bus,
+		command.NewRequestPasswordResetHandler(deps.userRepo, deps.tokenSvc, deps.emailSvc, logger, metrics))
+
+	// 邮箱变更
+	cmdbus.RegisterHandler[*command.RequestEmailChangeCommand, *command.RequestEmailChangeResult](bus, command.NewRequestEmailChangeHandler(
+		deps.userRepo, deps.changeRepo, deps.emailSvc, cfg.Auth.EmailChange.TTL, logger, metrics))
+	cmdbus.RegisterHandler[*command.ConfirmEmailChangeCommand, struct{}](bus, command.NewConfirmEmailChangeHandler(
+		deps.userRepo, deps.changeRepo, deps.eventStore, deps.eventBus, deps.tokenSvc, deps.cache, deps.uow, logger, metrics))
+	cmdbus.RegisterHandler[*command.CancelEmailChangeCommand, struct{}](bus, command.NewCancelEmailChangeHandler(deps.changeRepo, logger, metrics))
+	cmdbus.RegisterHandler[*command.ExpireEmailChangesCommand, int64](bus, command.NewExpireEmailChangesHandler(deps.changeRepo, logger, metrics))
+
+	// GDPR 与生命周期
+	cmdbus.RegisterHandler[*command.RequestDataExportCommand, *dto.DataExportDTO](bus,
+		command.NewRequestDataExportHandler(deps.userRepo, deps.exportRepo, logger, metrics))
+	cmdbus.RegisterHandler[*command.EraseUserCommand, struct{}](bus, command.NewEraseUserHandler(
+		deps.userRepo, deps.eventStore, deps.eventBus, deps.keyStore, deps.exportRepo, deps.changeRepo,
+		deps.auditLog, deps.webhookRepo, deps.cache, deps.uow, logger, metrics))
+	lifecycle := command.NewUserLifecycleHandler(
+		deps.userRepo, deps.eventStore, deps.eventBus, deps.cache, deps.uow, logger, metrics)
+	cmdbus.RegisterHandler[*command.DeactivateUserCommand, struct{}](bus,
+		cmdbus.TypedHandlerFunc[*command.DeactivateUserCommand, struct{}](lifecycle.Deactivate))
+	cmdbus.RegisterHandler[*command.ReactivateUserCommand, struct{}](bus,
+		cmdbus.TypedHandlerFunc[*command.ReactivateUserCommand, struct{}](lifecycle.Reactivate))
+	cmdbus.RegisterHandler[*command.DeleteUserCommand, struct{}](bus,
+		cmdbus.TypedHandlerFunc[*command.DeleteUserCommand, struct{}](lifecycle.Delete))
+	cmdbus.RegisterHandler[*command.RestoreUserCommand, struct{}](bus,
+		cmdbus.TypedHandlerFunc[*command.RestoreUserCommand, struct{}](lifecycle.Restore))
+	cmdbus.RegisterHandler[*command.PurgeUserCommand, struct{}](bus, command.NewPurgeUserHandler(
+		deps.userRepo, deps.eventStore, deps.eventBus, deps.keyStore, deps.exportRepo, deps.changeRepo,
+		deps.auditLog, deps.webhookRepo, deps.cache, deps.uow, cfg.Lifecycle.DeletionGracePeriod, logger, metrics))
+	cmdbus.RegisterHandler[*command.PurgeDeletedUsersCommand, int64](bus, command.NewPurgeDeletedUsersHandler(
+		deps.userRepo, bus, cfg.Lifecycle.DeletionGracePeriod, cfg.Lifecycle.PurgeBatchSize, logger, metrics))
+
+	// 开通流程
+	cmdbus.RegisterHandler[*command.SendVerificationEmailCommand, struct{}](bus, command.NewSendVerificationEmailHandler(
+		deps.userRepo, deps.verificationRepo, deps.emailSvc, bus,
+		cfg.Sagas.Onboarding.VerificationTimeout, cfg.Sagas.Onboarding.ReminderAfter, logger, metrics))
+	cmdbus.RegisterHandler[*command.SendVerificationReminderCommand, struct{}](bus, command.NewSendVerificationReminderHandler(
+		deps.userRepo, deps.verificationRepo, deps.emailSvc, logger, metrics))
+	cmdbus.RegisterHandler[*command.VerifyEmailCommand, struct{}](bus, command.NewVerifyEmailHandler(
+		deps.userRepo, deps.verificationRepo, deps.eventStore, deps.eventBus, deps.uow, logger, metrics))
+	cmdbus.RegisterHandler[*command.ProvisionUserCommand, struct{}](bus, command.NewProvisionUserHandler(deps.userRepo, deps.provisioning, logger, metrics))
+	cmdbus.RegisterHandler[*command.DeprovisionUserCommand, struct{}](bus, command.NewDeprovisionUserHandler(deps.provisioning, logger, metrics))
+	cmdbus.RegisterHandler[*command.SendWelcomeEmailCommand, struct{}](bus, command.NewSendWelcomeEmailHandler(deps.userRepo, deps.emailSvc, logger, metrics))
+	cmdbus.RegisterHandler[*saga.StepTimeoutCommand, struct{}](bus,
+		cmdbus.TypedHandlerFunc[*saga.StepTimeoutCommand, struct{}](deps.sagas.HandleTimeout))
+
+	// 任务、计划和 webhook
+	cmdbus.RegisterHandler[*command.CancelJobCommand, struct{}](bus, command.NewCancelJobHandler(deps.jobQueue, logger, metrics))
+	cmdbus.RegisterHandler[*command.CancelScheduledCommandCommand, struct{}](bus,
+		command.NewCancelScheduledCommandHandler(deps.scheduleStore, logger, metrics))
+	cmdbus.RegisterHandler[*command.CreateWebhookSubscriptionCommand, *dto.WebhookSubscriptionDTO](bus,
+		command.NewCreateWebhookSubscriptionHandler(deps.webhookRepo, logger, metrics))
+	cmdbus.RegisterHandler[*command.UpdateWebhookSubscriptionCommand, *dto.WebhookSubscriptionDTO](bus,
+		command.NewUpdateWebhookSubscriptionHandler(deps.webhookRepo, logger, metrics))
+	cmdbus.RegisterHandler[*command.DeleteWebhookSubscriptionCommand, struct{}](bus,
+		command.NewDeleteWebhookSubscriptionHandler(deps.webhookRepo, logger, metrics))
+	cmdbus.RegisterHandler[*command.RedeliverWebhookCommand, *dto.WebhookDeliveryDTO](bus,
+		command.NewRedeliverWebhookHandler(deps.webhookRepo, logger, metrics))
+}
+
+// registerQueryHandlers 注册所有查询处理器
+func registerQueryHandlers(bus querybus.Bus, deps handlerDeps, logger output.Logger, metrics output.MetricsReporter) {
+	// 用户与认证
+	querybus.RegisterHandler[*query.GetUserQuery, *dto.UserDTO](bus,
+		query.NewGetUserHandler(deps.userRepo, deps.cache, logger, metrics))
+	querybus.RegisterHandler[*query.GetUserByIDQuery, *dto.UserDTO](bus, query.NewGetUserByIDHandler(deps.userRepo, deps.cache, logger, metrics))
+	querybus.RegisterHandler[*query.GetUserByEmailQuery, *dto.UserDTO](bus, query.NewGetUserByEmailHandler(deps.userRepo, deps.cache, logger, metrics))
+	querybus.RegisterHandler[*query.ListUsersQuery, *dto.UserListDTO](bus, query.NewListUsersHandler(deps.userRepo, deps.cache, logger, metrics))
+	querybus.RegisterHandler[*query.GetUserRolesQuery, *dto.UserRolesDTO](bus, query.NewGetUserRolesHandler(deps.userRepo, logger, metrics))
+	querybus.RegisterHandler[*query.ValidateTokenQuery, *dto.AuthInfoDTO](bus, query.NewValidateTokenHandler(deps.tokenSvc, deps.userRepo, logger, metrics))
+
+	// 审计与 GDPR
+	querybus.RegisterHandler[*query.ListAuditLogsQuery, *query.PagedResult](bus, query.NewListAuditLogsHandler(deps.auditLog, logger, metrics))
+	querybus.RegisterHandler[*query.ExportAuditLogsQuery, []*dto.AuditLogDTO](bus, query.NewExportAuditLogsHandler(deps.auditLog, logger, metrics))
+	querybus.RegisterHandler[*query.GetDataExportQuery, *output.DataExport](bus, query.NewGetDataExportHandler(deps.exportRepo, logger, metrics))
+
+	// 任务、计划和 webhook
+	querybus.RegisterHandler[*query.GetJobQuery, *dto.JobDTO](bus, query.NewGetJobHandler(deps.jobQueue, logger, metrics))
+	querybus.RegisterHandler[*query.ListScheduledCommandsQuery, *query.PagedResult](bus, query.NewListScheduledCommandsHandler(deps.scheduleStore, logger, metrics))
+	querybus.RegisterHandler[*query.ListWebhookSubscriptionsQuery, []*dto.WebhookSubscriptionDTO](bus,
+		query.NewListWebhookSubscriptionsHandler(deps.webhookRepo, logger, metrics))
+	querybus.RegisterHandler[*query.GetWebhookSubscriptionQuery, *dto.WebhookSubscriptionDTO](bus,
+		query.NewGetWebhookSubscriptionHandler(deps.webhookRepo, logger, metrics))
+	querybus.RegisterHandler[*query.ListWebhookDeliveriesQuery, *query.PagedResult](bus,
+		query.NewListWebhookDeliveriesHandler(deps.webhookRepo, logger, metrics))
+}
@@ -1,36 +1,56 @@
 package bootstrap
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
+	"time"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/Shopify/sarama"
+	"github.com/nats-io/nats.go"
 	"github.com/gohex/gohex/internal/infrastructure/config"
-	"github.com/gohex/gohex/internal/infrastructure/logger"
-	"github.com/gohex/gohex/internal/infrastructure/metrics"
-	"github.com/gohex/gohex/internal/infrastructure/tracing"
+	"github.com/gohex/gohex/internal/infrastructure/observability"
+	httpadapter "github.com/gohex/gohex/internal/infrastructure/adapter/primary/http"
+	"github.com/gohex/gohex/internal/infrastructure/adapter/secondary/email"
+	"github.com/gohex/gohex/internal/infrastructure/adapter/secondary/logger"
+	"github.com/gohex/gohex/internal/infrastructure/adapter/secondary/metrics"
+	"github.com/gohex/gohex/internal/infrastructure/adapter/secondary/token/jwt"
 	"github.com/gohex/gohex/internal/infrastructure/adapter/secondary/persistence/mysql"
 	"github.com/gohex/gohex/internal/infrastructure/adapter/secondary/cache/redis"
-	"github.com/gohex/gohex/internal/application/command"
-	"github.com/gohex/gohex/internal/application/query"
-	"github.com/gohex/gohex/internal/domain/event"
+	redisqueue "github.com/gohex/gohex/internal/infrastructure/adapter/secondary/queue/redis"
+	"github.com/gohex/gohex/internal/infrastructure/adapter/secondary/cache/memory"
+	"github.com/gohex/gohex/internal/infrastructure/adapter/secondary/cache/multitier"
+	"github.com/gohex/gohex/internal/infrastructure/adapter/secondary/message/kafka"
+	natsbus "github.com/gohex/gohex/internal/infrastructure/adapter/secondary/message/nats"
+	"github.com/gohex/gohex/internal/infrastructure/adapter/secondary/message/rabbitmq"
+	"github.com/gohex/gohex/internal/infrastructure/resilience"
+	cmdbusimpl "github.com/gohex/gohex/internal/infrastructure/bus/command"
+	eventbus "github.com/gohex/gohex/internal/infrastructure/bus/event"
+	querybusimpl "github.com/gohex/gohex/internal/infrastructure/bus/query"
+	"github.com/gohex/gohex/internal/application/port/output"
+	appservice "github.com/gohex/gohex/internal/application/service"
+	cmdbus "github.com/gohex/gohex/internal/application/port/input/command"
+	querybus "github.com/gohex/gohex/internal/application/port/input/query"
 )
 
-func initLogger(cfg config.LogConfig) Logger {
-	logger, err := logger.NewZapLogger(cfg)
+func initLogger(cfg *config.Config) output.Logger {
+	logger, err := logger.NewZapLogger(cfg.Log, cfg.App.Environment)
 	if err != nil {
 		panic(err)
 	}
 	return logger
 }
 
-func initMetrics(cfg config.MetricsConfig) MetricsReporter {
-	metrics, err := metrics.NewPrometheusMetrics(cfg)
-	if err != nil {
-		panic(err)
-	}
-	return metrics
+func initMetrics(cfg config.MetricsConfig) output.MetricsReporter {
+	return metrics.NewPrometheusMetrics(cfg.Namespace)
 }
 
-func initTracer(cfg config.TracingConfig) Tracer {
-	tracer, err := tracing.NewJaegerTracer(cfg)
+// initTracer 启用时注册全局 TracerProvider，pkg/tracer 创建的 span 随之导出；未启用时返回 nil，span 为 no-op
+func initTracer(cfg *config.Config) *observability.Tracer {
+	if !cfg.Tracing.Enabled {
+		return nil
+	}
+	tracer, err := observability.NewTracer(cfg.App.Name, cfg.Tracing.Endpoint)
 	if err != nil {
 		panic(err)
 	}
@@ -45,56 +65,220 @@ func initDatabase(cfg config.DatabaseConfig) *sql.DB {
 	return db
 }
 
-func initCache(cfg config.RedisConfig) Cache {
-	cache, err := redis.NewClient(cfg)
+// initRedisClient 按部署模式创建缓存、任务队列和幂等存储共用的 Redis 客户端
+func initRedisClient(cfg config.RedisConfig) goredis.UniversalClient {
+	client, err := redis.NewClient(cfg)
 	if err != nil {
 		panic(err)
 	}
-	return cache
+	return client
 }
 
-func initCommandBus(
+// initCache 按配置选择 Redis 或内存存储；使用 Redis 时可在前面加一层进程内缓存，Redis 故障时熔断并回源
+func initCache(
 	cfg *config.Config,
-	logger Logger,
-	metrics MetricsReporter,
+	redisClient goredis.UniversalClient,
+	breakers *resilience.Registry,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *multitier.Cache {
+	local := cfg.Cache.Local
+
+	var store output.TaggedCache
+	switch cfg.Cache.Driver {
+	case "", config.CacheDriverRedis:
+		store = resilience.NewCache(
+			redis.NewRedisCache(redisClient, cfg.CacheNamespace(), logger, metrics),
+			breakers.Get(resilience.BreakerRedis),
+			logger,
+		)
+	case config.CacheDriverMemory:
+		store = memory.NewCache(cfg.Cache.CleanupInterval, logger, metrics)
+		// 内存存储本身就在进程内，不需要再加一层
+		local.Enabled = false
+	default:
+		panic(fmt.Sprintf("unsupported cache driver: %s", cfg.Cache.Driver))
+	}
+
+	return multitier.NewCache(store, redisClient, local, logger, metrics)
+}
+
+// initJobQueue 按配置创建异步命令任务队列，默认使用 MySQL
+func initJobQueue(cfg *config.Config, db *sql.DB, redisClient goredis.UniversalClient, logger output.Logger, metrics output.MetricsReporter) output.JobQueue {
+	switch cfg.Jobs.Driver {
+	case "", config.JobDriverMySQL:
+		return mysql.NewJobQueue(db, logger, metrics)
+	case config.JobDriverRedis:
+		return redisqueue.NewJobQueue(redisClient, cfg.Jobs.Retention, logger, metrics)
+	default:
+		panic(fmt.Sprintf("unsupported job queue driver: %s", cfg.Jobs.Driver))
+	}
+}
+
+// initRevocationStore 令牌吊销列表的存储，直接使用底层存储而不是查询缓存：
+// 查询缓存在 Redis 故障时降级为未命中，吊销列表必须如实返回错误才能失败关闭
+func initRevocationStore(cfg *config.Config, redisClient goredis.UniversalClient, logger output.Logger, metrics output.MetricsReporter) output.Cache {
+	switch cfg.Cache.Driver {
+	case "", config.CacheDriverRedis:
+		return redis.NewRedisCache(redisClient, cfg.CacheNamespace(), logger, metrics)
+	case config.CacheDriverMemory:
+		return memory.NewCache(cfg.Cache.CleanupInterval, logger, metrics)
+	default:
+		panic(fmt.Sprintf("unsupported cache driver: %s", cfg.Cache.Driver))
+	}
+}
+
+// initTokenService 签发和校验访问令牌，吊销列表存储故障时熔断并拒绝校验
+func initTokenService(cfg *config.Config, revocations output.Cache, breakers *resilience.Registry, logger output.Logger, metrics output.MetricsReporter) output.TokenService {
+	tokens := jwt.NewJWTTokenService(jwt.Config{
+		SecretKey:     cfg.JWT.SecretKey,
+		TokenDuration: cfg.JWT.TokenDuration,
+	}, revocations, logger, metrics)
+	return resilience.NewTokenService(tokens, breakers.Get(resilience.BreakerToken))
+}
+
+// initEmailService SMTP 故障时熔断，避免拖慢事件处理
+func initEmailService(cfg config.SMTPConfig, breakers *resilience.Registry, logger output.Logger, metrics output.MetricsReporter) output.EmailService {
+	smtp := email.NewSMTPEmailService(email.SMTPConfig{
+		Host:       cfg.Host,
+		Port:       cfg.Port,
+		Username:   cfg.Username,
+		Password:   cfg.Password,
+		From:       cfg.From,
+		WebsiteURL: cfg.WebsiteURL,
+	}, logger, metrics)
+	return resilience.NewEmailService(smtp, breakers.Get(resilience.BreakerSMTP))
+}
+
+// initHTTPServer 创建路由和 HTTP 服务器，数据库和 Redis 作为健康检查项
+func initHTTPServer(
+	cfg *config.Config,
+	commandBus cmdbus.Bus,
+	queryBus querybus.Bus,
+	tokenSvc output.TokenService,
+	breakers *resilience.Registry,
 	db *sql.DB,
-) command.Bus {
-	uow := mysql.NewUnitOfWork(db, logger)
-	factory := command.NewCommandBusFactory(
+	redisClient goredis.UniversalClient,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *httpadapter.Server {
+	router := httpadapter.NewRouter(cfg, logger, metrics, commandBus, queryBus, tokenSvc, breakers)
+	return httpadapter.NewServer(
+		httpadapter.ServerConfig{
+			Port:         cfg.HTTP.Port,
+			ReadTimeout:  cfg.HTTP.ReadTimeout,
+			WriteTimeout: cfg.HTTP.WriteTimeout,
+			IdleTimeout:  cfg.HTTP.IdleTimeout,
+			Health: httpadapter.HealthCheckConfig{
+				Enabled:          true,
+				Interval:         30 * time.Second,
+				Timeout:          5 * time.Second,
+				FailureThreshold: 3,
+			},
+		},
+		router,
+		logger,
+		metrics,
+		httpadapter.Check{Name: "database", Check: db.PingContext, Required: true},
+		httpadapter.Check{Name: "redis", Check: func(ctx context.Context) error {
+			return redisClient.Ping(ctx).Err()
+		}},
+	)
+}
+
+func initCommandBus(
+	cfg *config.Config,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+	uow output.UnitOfWork,
+	validator cmdbus.Validator,
+	auditLog output.AuditLog,
+	userRepo output.UserRepository,
+	keyStore output.KeyStore,
+	jobQueue output.JobQueue,
+	idempotencyStore output.IdempotencyStore,
+	scheduleStore output.ScheduleStore,
+) cmdbus.Bus {
+	// 调度器未启用时没有实例执行计划，Schedule 返回 ErrSchedulingDisabled 而不是保存永远不会执行的计划
+	if !cfg.Scheduler.Enabled {
+		scheduleStore = nil
+	}
+	factory := cmdbusimpl.NewCommandBusFactory(
 		cfg.CommandBus,
 		logger,
 		metrics,
 		uow,
-		tracer,
+		auditLog,
+		appservice.NewUserAuditSnapshotter(userRepo, keyStore),
+		validator,
+		jobQueue,
+		idempotencyStore,
+		scheduleStore,
 	)
 	return factory.CreateCommandBus()
 }
 
 func initQueryBus(
 	cfg *config.Config,
-	logger Logger,
-	metrics MetricsReporter,
-	cache Cache,
-) query.Bus {
-	factory := query.NewQueryBusFactory(
+	logger output.Logger,
+	metrics output.MetricsReporter,
+	cache output.Cache,
+	validator querybus.Validator,
+) querybus.Bus {
+	factory := querybusimpl.NewQueryBusFactory(
 		cfg.QueryBus,
 		logger,
 		metrics,
 		cache,
-		tracer,
+		validator,
 	)
 	return factory.CreateQueryBus()
 }
 
+// initEventBus 按配置选择进程内、Kafka、NATS JetStream 或 RabbitMQ 事件总线，外部中间件故障时熔断
 func initEventBus(
 	cfg *config.Config,
-	logger Logger,
-	metrics MetricsReporter,
-) event.Bus {
-	factory := event.NewEventBusFactory(
-		cfg.EventBus,
-		logger,
-		metrics,
-	)
-	return factory.CreateEventBus()
+	breakers *resilience.Registry,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) output.EventBus {
+	switch cfg.EventBus.Driver {
+	case config.EventBusDriverKafka:
+		saramaCfg, err := kafka.NewSaramaConfig(cfg.Kafka)
+		if err != nil {
+			panic(err)
+		}
+		client, err := sarama.NewClient(cfg.Kafka.Brokers, saramaCfg)
+		if err != nil {
+			panic(err)
+		}
+		consumer, err := sarama.NewConsumerGroupFromClient(cfg.Kafka.ConsumerGroup, client)
+		if err != nil {
+			panic(err)
+		}
+		bus, err := kafka.NewKafkaEventBus(client, consumer, cfg.Kafka, logger, metrics)
+		if err != nil {
+			panic(err)
+		}
+		return resilience.NewEventBus(bus, breakers.Get(resilience.BreakerKafka))
+	case config.EventBusDriverNATS:
+		conn, err := nats.Connect(cfg.NATS.URL, nats.Name(cfg.App.Name))
+		if err != nil {
+			panic(err)
+		}
+		bus, err := natsbus.NewEventBus(conn, cfg.NATS, logger, metrics)
+		if err != nil {
+			panic(err)
+		}
+		return resilience.NewEventBus(bus, breakers.Get(resilience.BreakerNATS))
+	case config.EventBusDriverRabbitMQ:
+		rabbitCfg := cfg.EventBus.RabbitMQ
+		if rabbitCfg.Group == "" {
+			rabbitCfg.Group = cfg.App.Name
+		}
+		bus := rabbitmq.NewEventBus(rabbitCfg, logger, metrics)
+		return resilience.NewEventBus(bus, breakers.Get(resilience.BreakerRabbitMQ))
+	default:
+		return eventbus.NewEventBus(cfg.CommandBus.Middleware.Events, logger, metrics)
+	}
 } 
\ No newline at end of file
@@ -0,0 +1,63 @@
+package bootstrap
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/command"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/vo"
+	"github.com/gohex/gohex/internal/infrastructure/config"
+	"github.com/gohex/gohex/internal/infrastructure/resilience"
+	"github.com/gohex/gohex/internal/testutil"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+func TestTokenService_RejectsRevokedTokenWhenRedisDown(t *testing.T) {
+	ctx := context.Background()
+	logger, metrics := testutil.NopLogger{}, testutil.NewMetrics()
+	server := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: server.Addr(), MaxRetries: -1})
+	defer client.Close()
+
+	cfg := &config.Config{JWT: config.JWTConfig{SecretKey: "secret", TokenDuration: time.Hour}}
+	breakers := resilience.NewRegistry(config.ResilienceConfig{
+		CircuitBreaker: config.CircuitBreakerConfig{FailureThreshold: 5, OpenTimeout: time.Minute},
+	}, logger, metrics)
+	tokens := initTokenService(cfg, initRevocationStore(cfg, client, logger, metrics), breakers, logger, metrics)
+
+	user := testutil.NewUser("alice", vo.StatusActive, vo.RoleUser)
+	revoked, _, err := tokens.GenerateToken(user)
+	require.NoError(t, err)
+	require.NoError(t, tokens.RevokeToken(ctx, revoked))
+	other, _, err := tokens.GenerateToken(user)
+	require.NoError(t, err)
+
+	// Redis 故障时无法确认吊销状态，已吊销的令牌不能通过校验，吊销请求也不能报告成功
+	server.Close()
+	_, err = tokens.ValidateToken(ctx, revoked)
+	assert.Error(t, err)
+	assert.Error(t, tokens.RevokeToken(ctx, other))
+	assert.Error(t, tokens.RevokeUserTokens(ctx, "alice"))
+}
+
+// scheduleStore 计划存储，不应被访问
+type scheduleStore struct {
+	output.ScheduleStore
+}
+
+func TestCommandBus_RefusesSchedulesWhenSchedulerDisabled(t *testing.T) {
+	logger, metrics := testutil.NopLogger{}, testutil.NewMetrics()
+	commandBus := initCommandBus(&config.Config{}, logger, metrics, testutil.UnitOfWork{}, nil,
+		nil, nil, nil, nil, nil, scheduleStore{})
+
+	// 没有实例执行计划时明确失败，调用方不会以为解锁或超时已经安排
+	_, err := commandBus.Schedule(context.Background(), &command.UnlockUserCommand{UserID: "alice"}, time.Now())
+	assert.ErrorIs(t, err, errors.ErrSchedulingDisabled)
+}
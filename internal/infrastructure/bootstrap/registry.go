@@ -0,0 +1,119 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gohex/gohex/internal/application/command"
+	cmdbus "github.com/gohex/gohex/internal/application/port/input/command"
+	querybus "github.com/gohex/gohex/internal/application/port/input/query"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/application/query"
+	"github.com/gohex/gohex/internal/application/saga"
+	eventhandler "github.com/gohex/gohex/internal/infrastructure/event/handler"
+)
+
+// requiredCommands HTTP 处理器和后台任务会分发的命令，启动时必须都有处理器
+var requiredCommands = []interface{}{
+	command.RegisterUserCommand{},
+	command.LoginCommand{},
+	command.LogoutCommand{},
+	command.UpdateUserProfileCommand{},
+	command.ChangePasswordCommand{},
+	command.ChangeUserStatusCommand{},
+	command.BulkAssignRoleCommand{},
+	command.ImpersonateUserCommand{},
+	command.EndImpersonationCommand{},
+	command.RequestEmailChangeCommand{},
+	command.ConfirmEmailChangeCommand{},
+	command.CancelEmailChangeCommand{},
+	command.RequestDataExportCommand{},
+	command.EraseUserCommand{},
+	command.DeleteUserCommand{},
+	command.RestoreUserCommand{},
+	command.PurgeUserCommand{},
+	command.PurgeDeletedUsersCommand{},
+	command.CancelJobCommand{},
+	command.CreateWebhookSubscriptionCommand{},
+	command.UpdateWebhookSubscriptionCommand{},
+	command.DeleteWebhookSubscriptionCommand{},
+	command.RedeliverWebhookCommand{},
+	command.SendVerificationEmailCommand{},
+	command.SendVerificationReminderCommand{},
+	command.VerifyEmailCommand{},
+	command.ProvisionUserCommand{},
+	command.DeprovisionUserCommand{},
+	command.SendWelcomeEmailCommand{},
+	command.UnlockUserCommand{},
+	command.ExpireEmailChangesCommand{},
+	command.CancelScheduledCommandCommand{},
+	saga.StepTimeoutCommand{},
+}
+
+// requiredQueries HTTP 处理器和中间件会执行的查询
+var requiredQueries = []interface{}{
+	query.ValidateTokenQuery{},
+	query.GetUserQuery{},
+	query.ListUsersQuery{},
+	query.GetDataExportQuery{},
+	query.ListAuditLogsQuery{},
+	query.ExportAuditLogsQuery{},
+	query.GetJobQuery{},
+	query.ListWebhookSubscriptionsQuery{},
+	query.GetWebhookSubscriptionQuery{},
+	query.ListWebhookDeliveriesQuery{},
+	query.ListScheduledCommandsQuery{},
+}
+
+// verifyBuses 校验总线注册表，缺少处理器或重复注册时启动失败
+func verifyBuses(commandBus cmdbus.Bus, queryBus querybus.Bus, logger output.Logger) error {
+	err := errors.Join(
+		commandBus.Verify(requiredCommands...),
+		queryBus.Verify(requiredQueries...),
+	)
+	if err != nil {
+		logger.Error("bus registry verification failed", "error", err)
+		return err
+	}
+
+	logger.Info("bus registry verified",
+		"commands", len(commandBus.Describe().Handlers),
+		"queries", len(queryBus.Describe().Handlers),
+	)
+	return nil
+}
+
+// recurringCommands 启动时注册的周期计划，名称相同的计划在多个实例间只保存一份
+var recurringCommands = []struct {
+	name    string
+	cron    string
+	command interface{}
+}{
+	{"email_changes.expire", "*/15 * * * *", &command.ExpireEmailChangesCommand{}},
+	{"users.purge", "0 * * * *", &command.PurgeDeletedUsersCommand{}},
+}
+
+// scheduleRecurringCommands 注册周期计划，表达式或命令变更后随部署更新
+func scheduleRecurringCommands(ctx context.Context, commandBus cmdbus.Bus) error {
+	var errs []error
+	for _, r := range recurringCommands {
+		if _, err := commandBus.ScheduleCron(ctx, r.name, r.command, r.cron); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// subscribeCacheInvalidation 订阅会改变用户数据的事件，按标签失效查询缓存
+func subscribeCacheInvalidation(eventBus output.EventBus, handler *eventhandler.CacheInvalidationHandler) {
+	for _, eventType := range handler.EventTypes() {
+		eventBus.Subscribe(eventType, handler)
+	}
+}
+
+// subscribeDeduplicated 订阅事件类型，处理器按事件 ID 去重，至少一次投递下重复的事件只处理一次
+func subscribeDeduplicated(eventBus output.EventBus, handler *eventhandler.IdempotentHandler, eventTypes []string) {
+	for _, eventType := range eventTypes {
+		eventBus.Subscribe(eventType, handler)
+	}
+}
@@ -0,0 +1,50 @@
+package bootstrap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/command"
+	cmdbus "github.com/gohex/gohex/internal/application/port/input/command"
+	"github.com/gohex/gohex/internal/infrastructure/config"
+	cmdbusimpl "github.com/gohex/gohex/internal/infrastructure/bus/command"
+	querybusimpl "github.com/gohex/gohex/internal/infrastructure/bus/query"
+	"github.com/gohex/gohex/internal/testutil"
+)
+
+func TestVerifyBuses(t *testing.T) {
+	logger, metrics := testutil.NopLogger{}, testutil.NewMetrics()
+
+	t.Run("all required handlers registered", func(t *testing.T) {
+		commandBus := cmdbusimpl.NewCommandBus(logger, metrics, nil, nil)
+		queryBus := querybusimpl.NewQueryBus(logger, metrics)
+		registerCommandHandlers(&config.Config{}, commandBus, handlerDeps{}, logger, metrics)
+		registerQueryHandlers(queryBus, handlerDeps{}, logger, metrics)
+
+		require.NoError(t, verifyBuses(commandBus, queryBus, logger))
+	})
+
+	t.Run("missing handler fails", func(t *testing.T) {
+		commandBus := cmdbusimpl.NewCommandBus(logger, metrics, nil, nil)
+		queryBus := querybusimpl.NewQueryBus(logger, metrics)
+		registerQueryHandlers(queryBus, handlerDeps{}, logger, metrics)
+
+		err := verifyBuses(commandBus, queryBus, logger)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "ImpersonateUserCommand")
+	})
+
+	t.Run("duplicate registration fails", func(t *testing.T) {
+		commandBus := cmdbusimpl.NewCommandBus(logger, metrics, nil, nil)
+		queryBus := querybusimpl.NewQueryBus(logger, metrics)
+		registerCommandHandlers(&config.Config{}, commandBus, handlerDeps{}, logger, metrics)
+		registerQueryHandlers(queryBus, handlerDeps{}, logger, metrics)
+		cmdbus.RegisterHandler[*command.LogoutCommand, struct{}](commandBus, command.NewLogoutHandler(nil, logger, metrics))
+
+		err := verifyBuses(commandBus, queryBus, logger)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already registered")
+	})
+}
@@ -2,35 +2,55 @@ package command
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+	"github.com/gohex/gohex/internal/application/port/input/command"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/infrastructure/bus/registry"
+	"github.com/gohex/gohex/pkg/actor"
+	"github.com/gohex/gohex/pkg/errors"
 )
 
 type commandBus struct {
-	handlers   map[reflect.Type]Handler
-	middleware []Middleware
-	logger     Logger
-	metrics    MetricsReporter
+	handlers   *registry.Registry
+	middleware []command.Middleware
+	jobs       output.JobQueue
+	schedules  output.ScheduleStore
+	logger     output.Logger
+	metrics    output.MetricsReporter
 }
 
-func NewCommandBus(logger Logger, metrics MetricsReporter, middleware ...Middleware) Bus {
+// NewCommandBus 创建命令总线，jobs 为 nil 时不支持异步分发，schedules 为 nil 时不支持计划执行
+func NewCommandBus(
+	logger output.Logger,
+	metrics output.MetricsReporter,
+	jobs output.JobQueue,
+	schedules output.ScheduleStore,
+	middleware ...command.Middleware,
+) command.Bus {
 	return &commandBus{
-		handlers:   make(map[reflect.Type]Handler),
+		handlers:   registry.New("command"),
 		middleware: middleware,
+		jobs:       jobs,
+		schedules:  schedules,
 		logger:     logger,
 		metrics:    metrics,
 	}
 }
 
 func (b *commandBus) Dispatch(ctx context.Context, cmd interface{}) (interface{}, error) {
-	cmdType := reflect.TypeOf(cmd)
-	handler, exists := b.handlers[cmdType]
-	if !exists {
-		return nil, fmt.Errorf("no handler registered for command type: %v", cmdType)
+	// 指针与值类型统一转换为处理器注册时的形式
+	h, cmd, err := b.handlers.Lookup(cmd)
+	if err != nil {
+		return nil, err
 	}
 
 	// 构建中间件链
-	var next Handler = handler
+	var next command.Handler = h.(command.Handler)
 	for i := len(b.middleware) - 1; i >= 0; i-- {
 		m := b.middleware[i]
 		current := next
@@ -44,10 +64,177 @@ func (b *commandBus) Dispatch(ctx context.Context, cmd interface{}) (interface{}
 	return next.Handle(ctx, cmd)
 }
 
-func (b *commandBus) Register(cmdType interface{}, handler Handler) {
-	t := reflect.TypeOf(cmdType)
-	if _, exists := b.handlers[t]; exists {
-		panic(fmt.Sprintf("handler already registered for command type: %v", t))
+func (b *commandBus) DispatchAsync(ctx context.Context, cmd interface{}) (string, error) {
+	if b.jobs == nil {
+		return "", errors.ErrAsyncDispatchDisabled
+	}
+
+	// 入队前确认处理器存在，避免任务到 worker 中才失败
+	if _, _, err := b.handlers.Lookup(cmd); err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(cmd)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode command %T: %w", cmd, err)
+	}
+
+	job := &output.Job{
+		ID:          uuid.New().String(),
+		CommandType: registry.TypeName(cmd),
+		Payload:     payload,
+		Status:      output.JobPending,
+		CreatedAt:   time.Now(),
+	}
+	if a, ok := actor.FromContext(ctx); ok {
+		job.RequestedBy = a.UserID
+		job.ImpersonatorID = a.ImpersonatorID
+		job.IP = a.IP
+		job.UserAgent = a.UserAgent
+	}
+
+	if err := b.jobs.Enqueue(ctx, job); err != nil {
+		b.logger.Error("failed to enqueue command", "command_type", job.CommandType, "error", err)
+		return "", err
+	}
+
+	b.logger.Info("command enqueued", "job_id", job.ID, "command_type", job.CommandType)
+	return job.ID, nil
+}
+
+func (b *commandBus) RunJob(ctx context.Context, job *output.Job) (interface{}, error) {
+	cmd, err := b.handlers.Decode(job.CommandType, job.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	// 恢复入队时的发起者，审计和权限检查与同步执行一致
+	ctx = actor.WithActor(ctx, actor.Actor{
+		UserID:         job.RequestedBy,
+		ImpersonatorID: job.ImpersonatorID,
+		IP:             job.IP,
+		UserAgent:      job.UserAgent,
+	})
+	return b.Dispatch(ctx, cmd)
+}
+
+func (b *commandBus) Schedule(ctx context.Context, cmd interface{}, runAt time.Time) (string, error) {
+	schedule, err := b.newSchedule(ctx, cmd)
+	if err != nil {
+		return "", err
+	}
+	schedule.NextRunAt = runAt
+
+	if err := b.schedules.Save(ctx, schedule); err != nil {
+		b.logger.Error("failed to schedule command", "command_type", schedule.CommandType, "error", err)
+		return "", err
+	}
+
+	b.logger.Info("command scheduled",
+		"schedule_id", schedule.ID,
+		"command_type", schedule.CommandType,
+		"run_at", runAt,
+	)
+	return schedule.ID, nil
+}
+
+func (b *commandBus) ScheduleCron(ctx context.Context, name string, cmd interface{}, expr string) (string, error) {
+	spec, err := cron.ParseStandard(expr)
+	if err != nil {
+		return "", errors.NewValidationError(fmt.Sprintf("invalid cron expression %q: %v", expr, err))
+	}
+
+	schedule, err := b.newSchedule(ctx, cmd)
+	if err != nil {
+		return "", err
+	}
+	schedule.Name = name
+	schedule.Cron = expr
+	schedule.NextRunAt = spec.Next(time.Now())
+
+	id, err := b.schedules.SaveNamed(ctx, schedule)
+	if err != nil {
+		b.logger.Error("failed to schedule command", "name", name, "command_type", schedule.CommandType, "error", err)
+		return "", err
+	}
+
+	b.logger.Info("recurring command scheduled",
+		"schedule_id", id,
+		"name", name,
+		"command_type", schedule.CommandType,
+		"cron", expr,
+	)
+	return id, nil
+}
+
+// newSchedule 校验处理器并编码命令，计划以当前发起者身份执行，无发起者时以系统身份执行
+func (b *commandBus) newSchedule(ctx context.Context, cmd interface{}) (*output.ScheduledCommand, error) {
+	if b.schedules == nil {
+		return nil, errors.ErrSchedulingDisabled
+	}
+
+	if _, _, err := b.handlers.Lookup(cmd); err != nil {
+		return nil, err
 	}
-	b.handlers[t] = handler
-} 
\ No newline at end of file
+
+	payload, err := json.Marshal(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode command %T: %w", cmd, err)
+	}
+
+	now := time.Now()
+	schedule := &output.ScheduledCommand{
+		ID:          uuid.New().String(),
+		CommandType: registry.TypeName(cmd),
+		Payload:     payload,
+		Status:      output.ScheduleActive,
+		RequestedBy: actor.SystemUserID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if a, ok := actor.FromContext(ctx); ok {
+		schedule.RequestedBy = a.UserID
+		schedule.ImpersonatorID = a.ImpersonatorID
+	}
+	return schedule, nil
+}
+
+// Register 注册处理器，重复注册不再 panic，由启动时的 Verify 统一报告
+func (b *commandBus) Register(cmdType interface{}, handler command.Handler) {
+	b.handlers.Add(cmdType, handler)
+}
+
+func (b *commandBus) Describe() command.RegistryInfo {
+	entries := b.handlers.Entries()
+	info := command.RegistryInfo{
+		Handlers:   make([]command.HandlerInfo, 0, len(entries)),
+		Middleware: make([]string, 0, len(b.middleware)),
+	}
+	for _, e := range entries {
+		info.Handlers = append(info.Handlers, command.HandlerInfo{
+			Type:    e.Type.String(),
+			Handler: registry.HandlerName(e.Handler),
+		})
+	}
+	for _, m := range b.middleware {
+		info.Middleware = append(info.Middleware, fmt.Sprintf("%T", m))
+	}
+	return info
+}
+
+func (b *commandBus) Verify(required ...interface{}) error {
+	if err := b.handlers.Verify(required...); err != nil {
+		return fmt.Errorf("command bus verification failed: %w", err)
+	}
+	return nil
+}
+
+type middlewareHandler struct {
+	middleware command.Middleware
+	next       command.Handler
+	command    interface{}
+}
+
+func (h middlewareHandler) Handle(ctx context.Context, cmd interface{}) (interface{}, error) {
+	return h.middleware.Execute(ctx, cmd, h.next)
+}
@@ -0,0 +1,205 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cmdbus "github.com/gohex/gohex/internal/application/port/input/command"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/testutil"
+	"github.com/gohex/gohex/pkg/actor"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+// jobQueue 只记录入队的任务
+type jobQueue struct {
+	output.JobQueue
+	enqueued []*output.Job
+}
+
+func (q *jobQueue) Enqueue(ctx context.Context, job *output.Job) error {
+	q.enqueued = append(q.enqueued, job)
+	return nil
+}
+
+// scheduleStore 只记录保存的计划
+type scheduleStore struct {
+	output.ScheduleStore
+	saved []*output.ScheduledCommand
+}
+
+func (s *scheduleStore) Save(ctx context.Context, schedule *output.ScheduledCommand) error {
+	s.saved = append(s.saved, schedule)
+	return nil
+}
+
+func (s *scheduleStore) SaveNamed(ctx context.Context, schedule *output.ScheduledCommand) (string, error) {
+	s.saved = append(s.saved, schedule)
+	return schedule.ID, nil
+}
+
+type renameCommand struct {
+	UserID string
+	Name   string
+}
+
+// actorHandler 记录执行时上下文中的发起者
+type actorHandler struct {
+	actor actor.Actor
+	cmd   *renameCommand
+}
+
+func (h *actorHandler) Handle(ctx context.Context, cmd interface{}) (interface{}, error) {
+	h.actor, _ = actor.FromContext(ctx)
+	h.cmd = cmd.(*renameCommand)
+	return nil, nil
+}
+
+func TestCommandBus_RunJobRestoresActor(t *testing.T) {
+	queue := &jobQueue{}
+	bus := NewCommandBus(testutil.NopLogger{}, testutil.NewMetrics(), queue, nil)
+	handler := &actorHandler{}
+	bus.Register(&renameCommand{}, handler)
+
+	requester := actor.Actor{
+		UserID:         "alice",
+		ImpersonatorID: "support",
+		IP:             "203.0.113.7",
+		UserAgent:      "curl/8.0",
+	}
+	jobID, err := bus.DispatchAsync(actor.WithActor(context.Background(), requester),
+		&renameCommand{UserID: "alice", Name: "Alice"})
+	require.NoError(t, err)
+	require.Len(t, queue.enqueued, 1)
+
+	job := queue.enqueued[0]
+	assert.Equal(t, jobID, job.ID)
+	assert.Equal(t, output.JobPending, job.Status)
+
+	// worker 在没有请求上下文的情况下执行任务
+	_, err = bus.RunJob(context.Background(), job)
+	require.NoError(t, err)
+	assert.Equal(t, requester, handler.actor)
+	assert.Equal(t, &renameCommand{UserID: "alice", Name: "Alice"}, handler.cmd)
+}
+
+func TestCommandBus_DispatchAsyncWithoutQueue(t *testing.T) {
+	bus := NewCommandBus(testutil.NopLogger{}, testutil.NewMetrics(), nil, nil)
+	bus.Register(&renameCommand{}, &actorHandler{})
+
+	_, err := bus.DispatchAsync(context.Background(), &renameCommand{})
+	assert.ErrorIs(t, err, errors.ErrAsyncDispatchDisabled)
+}
+
+func TestCommandBus_ScheduleRunsAsRequester(t *testing.T) {
+	store := &scheduleStore{}
+	bus := NewCommandBus(testutil.NopLogger{}, testutil.NewMetrics(), nil, store)
+	handler := &actorHandler{}
+	bus.Register(&renameCommand{}, handler)
+
+	requester := actor.Actor{UserID: "alice", ImpersonatorID: "support"}
+	runAt := time.Now().Add(30 * time.Minute)
+	id, err := bus.Schedule(actor.WithActor(context.Background(), requester),
+		&renameCommand{UserID: "alice", Name: "Alice"}, runAt)
+	require.NoError(t, err)
+	require.Len(t, store.saved, 1)
+
+	schedule := store.saved[0]
+	assert.Equal(t, id, schedule.ID)
+	assert.Equal(t, output.ScheduleActive, schedule.Status)
+	assert.Equal(t, runAt, schedule.NextRunAt)
+	assert.False(t, schedule.IsRecurring())
+
+	// 调度器按计划构造任务执行
+	_, err = bus.RunJob(context.Background(), &output.Job{
+		ID:             schedule.ID,
+		CommandType:    schedule.CommandType,
+		Payload:        schedule.Payload,
+		RequestedBy:    schedule.RequestedBy,
+		ImpersonatorID: schedule.ImpersonatorID,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "alice", handler.actor.UserID)
+	assert.Equal(t, "support", handler.actor.ImpersonatorID)
+	assert.Equal(t, &renameCommand{UserID: "alice", Name: "Alice"}, handler.cmd)
+}
+
+func TestCommandBus_ScheduleCron(t *testing.T) {
+	store := &scheduleStore{}
+	bus := NewCommandBus(testutil.NopLogger{}, testutil.NewMetrics(), nil, store)
+	bus.Register(&renameCommand{}, &actorHandler{})
+
+	_, err := bus.ScheduleCron(context.Background(), "rename", &renameCommand{}, "*/15 * * * *")
+	require.NoError(t, err)
+	require.Len(t, store.saved, 1)
+
+	schedule := store.saved[0]
+	assert.Equal(t, "rename", schedule.Name)
+	assert.Equal(t, actor.SystemUserID, schedule.RequestedBy)
+	assert.True(t, schedule.NextRunAt.After(time.Now()))
+	assert.True(t, schedule.NextRunAt.Before(time.Now().Add(15*time.Minute+time.Second)))
+}
+
+func TestCommandBus_ScheduleErrors(t *testing.T) {
+	type unknownCommand struct{}
+
+	tests := []struct {
+		name     string
+		store    output.ScheduleStore
+		schedule func(bus cmdbus.Bus) error
+		wantErr  error
+		wantCode errors.ErrorCode
+	}{
+		{
+			name:  "scheduling disabled",
+			store: nil,
+			schedule: func(bus cmdbus.Bus) error {
+				_, err := bus.Schedule(context.Background(), &renameCommand{}, time.Now())
+				return err
+			},
+			wantErr: errors.ErrSchedulingDisabled,
+		},
+		{
+			name:  "invalid cron expression",
+			store: &scheduleStore{},
+			schedule: func(bus cmdbus.Bus) error {
+				_, err := bus.ScheduleCron(context.Background(), "rename", &renameCommand{}, "every minute")
+				return err
+			},
+			wantCode: errors.ErrCodeValidation,
+		},
+		{
+			name:  "no handler",
+			store: &scheduleStore{},
+			schedule: func(bus cmdbus.Bus) error {
+				_, err := bus.Schedule(context.Background(), &unknownCommand{}, time.Now())
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := NewCommandBus(testutil.NopLogger{}, testutil.NewMetrics(), nil, tt.store)
+			bus.Register(&renameCommand{}, &actorHandler{})
+
+			err := tt.schedule(bus)
+			require.Error(t, err)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			if tt.wantCode != "" {
+				var appErr *errors.AppError
+				require.ErrorAs(t, err, &appErr)
+				assert.Equal(t, tt.wantCode, appErr.Code)
+			}
+			if store, ok := tt.store.(*scheduleStore); ok {
+				assert.Empty(t, store.saved)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package command
+
+import (
+    "time"
+
+    "github.com/gohex/gohex/internal/application/port/input/command"
+    "github.com/gohex/gohex/internal/application/port/output"
+    "github.com/gohex/gohex/internal/infrastructure/config"
+)
+
+type CommandBusFactory interface {
+    CreateCommandBus() command.Bus
+}
+
+type commandBusFactory struct {
+    config      config.CommandBusConfig
+    logger      output.Logger
+    metrics     output.MetricsReporter
+    uow         output.UnitOfWork
+    auditLog    output.AuditLog
+    snapshotter command.AuditSnapshotter
+    validator   command.Validator
+    jobs        output.JobQueue
+    idempotency output.IdempotencyStore
+    schedules   output.ScheduleStore
+}
+
+func NewCommandBusFactory(
+    cfg config.CommandBusConfig,
+    logger output.Logger,
+    metrics output.MetricsReporter,
+    uow output.UnitOfWork,
+    auditLog output.AuditLog,
+    snapshotter command.AuditSnapshotter,
+    validator command.Validator,
+    jobs output.JobQueue,
+    idempotency output.IdempotencyStore,
+    schedules output.ScheduleStore,
+) CommandBusFactory {
+    return &commandBusFactory{
+        config:      cfg,
+        logger:      logger,
+        metrics:     metrics,
+        uow:         uow,
+        auditLog:    auditLog,
+        snapshotter: snapshotter,
+        validator:   validator,
+        jobs:        jobs,
+        idempotency: idempotency,
+        schedules:   schedules,
+    }
+}
+
+func (f *commandBusFactory) CreateCommandBus() command.Bus {
+    // 创建中间件
+    middleware := f.createMiddleware()
+    
+    // 创建命令总线
+    return NewCommandBus(f.logger, f.metrics, f.jobs, f.schedules, middleware...)
+}
+
+func (f *commandBusFactory) createMiddleware() []command.Middleware {
+    var middleware []command.Middleware
+    
+    // 按配置添加中间件
+    // 幂等放在最外层，重放的请求不会再次写入审计记录
+    if f.config.Middleware.Idempotency.Enabled {
+        middleware = append(middleware, command.NewIdempotencyMiddleware(
+            f.idempotency,
+            f.config.Middleware.Idempotency.TTL,
+            f.config.Middleware.Idempotency.LockTTL,
+            f.logger,
+            f.metrics,
+        ))
+    }
+
+    // 审计位于事务之外，命令回滚时审计记录仍然保留
+    if f.config.Middleware.Audit.Enabled {
+        middleware = append(middleware, command.NewAuditMiddleware(f.auditLog, f.snapshotter, f.logger, f.metrics))
+    }
+
+    if f.config.Middleware.Validation.Enabled {
+        middleware = append(middleware, command.NewValidatorMiddleware(f.validator, f.logger))
+    }
+    
+    // 并发限制、超时、重试按命令类型生效；重试位于事务之外，每次重试开启新事务
+    policies := f.handlerPolicies()
+    middleware = append(middleware,
+        command.NewConcurrencyMiddleware(policies, f.logger, f.metrics),
+        command.NewTimeoutMiddleware(policies, f.logger, f.metrics),
+        command.NewRetryMiddleware(policies, f.logger, f.metrics),
+    )
+
+    if f.config.Middleware.Transaction.Enabled {
+        middleware = append(middleware, command.NewTransactionMiddleware(f.uow, f.logger))
+    }
+    
+    // ... 添加其他中间件
+    
+    return middleware
+}
+
+// handlerPolicies 将全局处理器配置和按命令类型的覆盖配置转换为执行策略
+func (f *commandBusFactory) handlerPolicies() command.HandlerPolicies {
+    policies := command.HandlerPolicies{
+        Default:  toHandlerPolicy(f.config.Handlers.Timeout, f.config.Handlers.MaxConcurrency,
+            f.config.Handlers.RetryAttempts, f.config.Handlers.RetryDelay),
+        Commands: make(map[string]command.HandlerPolicy, len(f.config.Handlers.Commands)),
+    }
+    for name, c := range f.config.Handlers.Commands {
+        policies.Commands[name] = toHandlerPolicy(c.Timeout, c.MaxConcurrency, c.RetryAttempts, c.RetryDelay)
+    }
+    return policies
+}
+
+func toHandlerPolicy(timeout time.Duration, maxConcurrency, retryAttempts int, retryDelay time.Duration) command.HandlerPolicy {
+    return command.HandlerPolicy{
+        Timeout:        timeout,
+        MaxConcurrency: maxConcurrency,
+        RetryAttempts:  retryAttempts,
+        RetryDelay:     retryDelay,
+    }
+}
@@ -2,54 +2,290 @@ package event
 
 import (
 	"context"
+	stderrors "errors"
+	"fmt"
+	"runtime/debug"
 	"sync"
-	"github.com/your-org/your-project/internal/domain/event"
-	"github.com/your-org/your-project/internal/application/port/output"
+	"time"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/event"
+	"github.com/gohex/gohex/internal/infrastructure/config"
+	"github.com/gohex/gohex/pkg/errors"
+	"github.com/gohex/gohex/pkg/uow"
+)
+
+const (
+	defaultWorkers      = 1
+	defaultQueueSize    = 100
+	defaultDrainTimeout = 10 * time.Second
 )
 
 type eventBus struct {
-	handlers map[string][]output.EventHandler
+	cfg      config.EventConfig
+	handlers map[string][]*subscription
+	closed   bool
 	mu       sync.RWMutex
-	logger   Logger
-	metrics  MetricsReporter
+	logger   output.Logger
+	metrics  output.MetricsReporter
+}
+
+// delivery 排队等待异步处理的事件
+type delivery struct {
+	ctx context.Context
+	evt event.Event
 }
 
-func NewEventBus(logger Logger, metrics MetricsReporter) output.EventBus {
+// subscription 一个处理器的订阅，异步模式下持有独立的队列和工作协程
+type subscription struct {
+	eventType string
+	handler   output.EventHandler
+	queue     chan delivery
+	// stop 关闭后不再接受新事件，阻塞中的发布立即返回
+	stop     chan struct{}
+	stopOnce sync.Once
+	// mu 保护 queue 的关闭，发布时持读锁
+	mu      sync.RWMutex
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+// NewEventBus 创建进程内事件总线，cfg.AsyncPublishing 为 true 时异步分发
+func NewEventBus(cfg config.EventConfig, logger output.Logger, metrics output.MetricsReporter) output.EventBus {
+	if cfg.Workers <= 0 {
+		cfg.Workers = defaultWorkers
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultQueueSize
+	}
+	if cfg.Backpressure == "" {
+		cfg.Backpressure = config.EventBackpressureBlock
+	}
+	if cfg.DrainTimeout <= 0 {
+		cfg.DrainTimeout = defaultDrainTimeout
+	}
 	return &eventBus{
-		handlers: make(map[string][]output.EventHandler),
+		cfg:      cfg,
+		handlers: make(map[string][]*subscription),
 		logger:   logger,
 		metrics:  metrics,
 	}
 }
 
+// Publish 同步模式下依次交给所有处理器，异步模式下只负责入队。
+// 处理器脱离调用方的事务执行，各自成功或失败，处理失败只记录日志和指标，不返回给发布方，
+// 避免一个处理器出错导致命令回滚；返回的错误只来自总线本身（已关闭、队列已满）
 func (b *eventBus) Publish(ctx context.Context, events ...event.Event) error {
-	for _, evt := range events {
-		timer := b.metrics.StartTimer("event_publish_duration", "type", evt.Type())
-		defer timer.Stop()
+	event.Stamp(ctx, events...)
+	ctx = uow.WithoutTransaction(ctx)
 
+	var errs []error
+	for _, evt := range events {
 		b.mu.RLock()
-		handlers := b.handlers[evt.Type()]
+		if b.closed {
+			b.mu.RUnlock()
+			return errors.ErrEventBusClosed
+		}
+		subs := b.handlers[evt.Type()]
 		b.mu.RUnlock()
 
-		for _, handler := range handlers {
-			if err := handler.Handle(ctx, evt); err != nil {
-				b.logger.Error("failed to handle event",
-					"type", evt.Type(),
-					"error", err,
-				)
-				b.metrics.IncrementCounter("event_handle_failure", "type", evt.Type())
-				return err
+		for _, sub := range subs {
+			if !b.cfg.AsyncPublishing {
+				// 错误已在 handle 中记录，不影响其他处理器和发布方
+				_ = b.handle(ctx, sub, evt)
+				continue
+			}
+			if err := b.enqueue(ctx, sub, evt); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", sub.handler.HandlerID(), err))
 			}
-			b.metrics.IncrementCounter("event_handle_success", "type", evt.Type())
 		}
 	}
-	return nil
+	return stderrors.Join(errs...)
 }
 
 func (b *eventBus) Subscribe(eventType string, handler output.EventHandler) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	b.handlers[eventType] = append(b.handlers[eventType], handler)
-	b.logger.Info("subscribed to event", "type", eventType)
-} 
\ No newline at end of file
+	if b.closed {
+		b.logger.Warn("event bus closed, subscription ignored",
+			"type", eventType,
+			"handler", handler.HandlerID(),
+		)
+		return
+	}
+
+	sub := &subscription{
+		eventType: eventType,
+		handler:   handler,
+		stop:      make(chan struct{}),
+	}
+	if b.cfg.AsyncPublishing {
+		sub.queue = make(chan delivery, b.cfg.BatchSize)
+		for i := 0; i < b.cfg.Workers; i++ {
+			sub.wg.Add(1)
+			go b.work(sub)
+		}
+	}
+
+	b.handlers[eventType] = append(b.handlers[eventType], sub)
+	b.logger.Info("subscribed to event", "type", eventType, "handler", handler.HandlerID())
+}
+
+// Unsubscribe 移除处理器，已入队的事件仍会在后台处理完
+func (b *eventBus) Unsubscribe(eventType string, handler output.EventHandler) {
+	b.mu.Lock()
+	subs := b.handlers[eventType]
+	var removed *subscription
+	for i, sub := range subs {
+		if sub.handler.HandlerID() == handler.HandlerID() {
+			removed = sub
+			b.handlers[eventType] = append(subs[:i:i], subs[i+1:]...)
+			break
+		}
+	}
+	if len(b.handlers[eventType]) == 0 {
+		delete(b.handlers, eventType)
+	}
+	b.mu.Unlock()
+
+	if removed == nil {
+		return
+	}
+	removed.close()
+	b.logger.Info("unsubscribed from event", "type", eventType, "handler", handler.HandlerID())
+}
+
+// Close 停止接受新事件，并在 DrainTimeout 内等待队列中的事件处理完成
+func (b *eventBus) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	var subs []*subscription
+	for _, list := range b.handlers {
+		subs = append(subs, list...)
+	}
+	b.handlers = make(map[string][]*subscription)
+	b.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		for _, sub := range subs {
+			sub.close()
+			sub.wg.Wait()
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(b.cfg.DrainTimeout):
+		b.logger.Error("event bus drain timed out", "timeout", b.cfg.DrainTimeout)
+		return fmt.Errorf("event bus drain timed out after %s", b.cfg.DrainTimeout)
+	}
+}
+
+// enqueue 将事件放入处理器队列，队列满时按 Backpressure 等待或丢弃
+func (b *eventBus) enqueue(ctx context.Context, sub *subscription, evt event.Event) error {
+	sub.mu.RLock()
+	defer sub.mu.RUnlock()
+
+	if sub.stopped {
+		return errors.ErrEventBusClosed
+	}
+
+	// 异步处理不随请求结束而取消，但保留上下文中的值；调用方已在 Publish 中剥离事务，
+	// 否则处理器会在调用方提交或回滚后继续使用已结束的事务
+	d := delivery{ctx: context.WithoutCancel(ctx), evt: evt}
+
+	if b.cfg.Backpressure == config.EventBackpressureDrop {
+		select {
+		case sub.queue <- d:
+		default:
+			b.logger.Warn("event queue full, event dropped",
+				"type", evt.Type(),
+				"handler", sub.handler.HandlerID(),
+			)
+			b.metrics.IncrementCounter("event_dropped", "type", evt.Type(), "handler", sub.handler.HandlerID())
+			return errors.ErrEventQueueFull
+		}
+	} else {
+		select {
+		case sub.queue <- d:
+		case <-sub.stop:
+			return errors.ErrEventBusClosed
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	b.metrics.Gauge("event_queue_depth", float64(len(sub.queue)), "handler", sub.handler.HandlerID())
+	return nil
+}
+
+func (b *eventBus) work(sub *subscription) {
+	defer sub.wg.Done()
+
+	for d := range sub.queue {
+		// 错误已在 handle 中记录，不影响其他事件和处理器
+		_ = b.handle(d.ctx, sub, d.evt)
+	}
+}
+
+// handle 调用单个处理器，处理器的 panic 转换为错误
+func (b *eventBus) handle(ctx context.Context, sub *subscription, evt event.Event) (err error) {
+	handlerID := sub.handler.HandlerID()
+	timer := b.metrics.StartTimer("event_handle_duration", "type", evt.Type(), "handler", handlerID)
+	defer timer.Stop()
+
+	defer func() {
+		if r := recover(); r != nil {
+			b.logger.Error("event handler panicked",
+				"type", evt.Type(),
+				"handler", handlerID,
+				"panic", r,
+				"stack", string(debug.Stack()),
+			)
+			b.metrics.IncrementCounter("event_handle_panic", "type", evt.Type(), "handler", handlerID)
+			err = fmt.Errorf("event handler panicked: %v", r)
+		}
+		if err != nil {
+			b.metrics.IncrementCounter("event_handle_failure", "type", evt.Type(), "handler", handlerID)
+			return
+		}
+		b.metrics.IncrementCounter("event_handle_success", "type", evt.Type(), "handler", handlerID)
+	}()
+
+	if b.cfg.HandlerTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.cfg.HandlerTimeout)
+		defer cancel()
+	}
+
+	if err = sub.handler.Handle(ctx, evt); err != nil {
+		b.logger.Error("failed to handle event",
+			"type", evt.Type(),
+			"handler", handlerID,
+			"error", err,
+		)
+	}
+	return err
+}
+
+// close 停止接受新事件并关闭队列，工作协程处理完剩余事件后退出
+func (s *subscription) close() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+
+		s.mu.Lock()
+		s.stopped = true
+		if s.queue != nil {
+			close(s.queue)
+		}
+		s.mu.Unlock()
+	})
+}
@@ -0,0 +1,87 @@
+package event
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/domain/event"
+	"github.com/gohex/gohex/internal/infrastructure/config"
+	"github.com/gohex/gohex/internal/testutil"
+	"github.com/gohex/gohex/pkg/uow"
+)
+
+// recordingHandler 记录收到的事件以及处理时上下文中是否带有事务
+type recordingHandler struct {
+	id    string
+	err   error
+	calls chan bool
+}
+
+func newRecordingHandler(id string, err error) *recordingHandler {
+	return &recordingHandler{id: id, err: err, calls: make(chan bool, 10)}
+}
+
+func (h *recordingHandler) Handle(ctx context.Context, evt event.Event) error {
+	_, inTx := uow.FromContext(ctx)
+	h.calls <- inTx
+	return h.err
+}
+
+func (h *recordingHandler) HandlerID() string { return h.id }
+
+func TestEventBus_HandlersRunOutsideCallerTransaction(t *testing.T) {
+	tests := []struct {
+		name  string
+		async bool
+	}{
+		{"sync", false},
+		{"async", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := NewEventBus(config.EventConfig{AsyncPublishing: tt.async}, testutil.NopLogger{}, testutil.NewMetrics())
+			defer bus.Close()
+
+			handler := newRecordingHandler("recorder", nil)
+			bus.Subscribe(event.UserCreated, handler)
+
+			ctx := uow.WithTransaction(context.Background(), &sql.Tx{})
+			require.NoError(t, bus.Publish(ctx, event.NewUserCreatedEvent("user-1", "a@example.com", "A")))
+
+			assert.False(t, <-handler.calls, "handler must not inherit the publisher's transaction")
+		})
+	}
+}
+
+func TestEventBus_SyncHandlerFailureIsIsolated(t *testing.T) {
+	metrics := testutil.NewMetrics()
+	bus := NewEventBus(config.EventConfig{}, testutil.NopLogger{}, metrics)
+	defer bus.Close()
+
+	failing := newRecordingHandler("failing", stderrors.New("boom"))
+	healthy := newRecordingHandler("healthy", nil)
+	bus.Subscribe(event.UserCreated, failing)
+	bus.Subscribe(event.UserCreated, healthy)
+
+	err := bus.Publish(context.Background(), event.NewUserCreatedEvent("user-1", "a@example.com", "A"))
+
+	require.NoError(t, err, "handler failures must not propagate to the publisher")
+	assert.Len(t, failing.calls, 1)
+	assert.Len(t, healthy.calls, 1)
+	assert.Equal(t, 1, metrics.Counter("event_handle_failure"))
+	assert.Equal(t, 1, metrics.Counter("event_handle_success"))
+}
+
+func TestEventBus_PublishAfterClose(t *testing.T) {
+	bus := NewEventBus(config.EventConfig{}, testutil.NopLogger{}, testutil.NewMetrics())
+	require.NoError(t, bus.Close())
+
+	err := bus.Publish(context.Background(), event.NewUserCreatedEvent("user-1", "a@example.com", "A"))
+	assert.Error(t, err)
+}
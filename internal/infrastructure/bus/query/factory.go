@@ -1,7 +1,9 @@
 package query
 
 import (
-	"github.com/your-org/your-project/internal/application/port/input/query"
+	"github.com/gohex/gohex/internal/application/port/input/query"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/infrastructure/config"
 )
 
 type QueryBusFactory interface {
@@ -9,26 +11,26 @@ type QueryBusFactory interface {
 }
 
 type queryBusFactory struct {
-	config   QueryBusConfig
-	logger   Logger
-	metrics  MetricsReporter
-	cache    Cache
-	tracer   Tracer
+	config    config.QueryBusConfig
+	logger    output.Logger
+	metrics   output.MetricsReporter
+	cache     output.Cache
+	validator query.Validator
 }
 
 func NewQueryBusFactory(
-	config QueryBusConfig,
-	logger Logger,
-	metrics MetricsReporter,
-	cache Cache,
-	tracer Tracer,
+	cfg config.QueryBusConfig,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+	cache output.Cache,
+	validator query.Validator,
 ) QueryBusFactory {
 	return &queryBusFactory{
-		config:  config,
-		logger:  logger,
-		metrics: metrics,
-		cache:   cache,
-		tracer:  tracer,
+		config:    cfg,
+		logger:    logger,
+		metrics:   metrics,
+		cache:     cache,
+		validator: validator,
 	}
 }
 
@@ -44,12 +46,28 @@ func (f *queryBusFactory) createMiddleware() []query.Middleware {
 	var middleware []query.Middleware
 	
 	// 按配置添加中间件
-	if f.config.Validation.Enabled {
-		middleware = append(middleware, NewValidationMiddleware(f.config.Validation))
+	if f.config.Middleware.Validation.Enabled {
+		middleware = append(middleware, query.NewValidationMiddleware(f.validator, f.logger))
 	}
 	
-	if f.config.Cache.Enabled {
-		middleware = append(middleware, NewCacheMiddleware(f.cache, f.logger))
+	// 超时包含缓存读取和所有重试
+	if f.config.Middleware.Timeout.Enabled {
+		middleware = append(middleware, query.NewTimeoutMiddleware(f.config.Middleware.Timeout.Default, f.logger, f.metrics))
+	}
+
+	if f.config.Middleware.Cache.Enabled {
+		middleware = append(middleware, query.NewCacheMiddleware(f.cache, f.logger, f.metrics))
+	}
+
+	// 重试位于缓存之内，缓存命中不会计入执行次数
+	if f.config.Middleware.Retry.Enabled {
+		middleware = append(middleware, query.NewRetryMiddleware(
+			f.config.Middleware.Retry.MaxRetries,
+			f.config.Middleware.Retry.Backoff,
+			query.DefaultRetryClassifier,
+			f.logger,
+			f.metrics,
+		))
 	}
 	
 	// ... 添加其他中间件
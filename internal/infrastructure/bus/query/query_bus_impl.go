@@ -3,142 +3,86 @@ package query
 import (
 	"context"
 	"fmt"
-	"reflect"
+
+	"github.com/gohex/gohex/internal/application/port/input/query"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/infrastructure/bus/registry"
 )
 
 type queryBus struct {
-	handlers   map[reflect.Type]Handler
-	middleware []Middleware
-	logger     Logger
-	metrics    MetricsReporter
+	handlers   *registry.Registry
+	middleware []query.Middleware
+	logger     output.Logger
+	metrics    output.MetricsReporter
 }
 
-func NewQueryBus(logger Logger, metrics MetricsReporter, middleware ...Middleware) Bus {
+func NewQueryBus(logger output.Logger, metrics output.MetricsReporter, middleware ...query.Middleware) query.Bus {
 	return &queryBus{
-		handlers:   make(map[reflect.Type]Handler),
+		handlers:   registry.New("query"),
 		middleware: middleware,
 		logger:     logger,
 		metrics:    metrics,
 	}
 }
 
-func (b *queryBus) Execute(ctx context.Context, query interface{}) (interface{}, error) {
-	queryType := reflect.TypeOf(query)
-	handler, exists := b.handlers[queryType]
-	if !exists {
-		return nil, fmt.Errorf("no handler registered for query type: %v", queryType)
+func (b *queryBus) Execute(ctx context.Context, q interface{}) (interface{}, error) {
+	// 指针与值类型统一转换为处理器注册时的形式
+	h, q, err := b.handlers.Lookup(q)
+	if err != nil {
+		return nil, err
 	}
 
 	// 构建中间件链
-	var next Handler = handler
+	var next query.Handler = h.(query.Handler)
 	for i := len(b.middleware) - 1; i >= 0; i-- {
 		m := b.middleware[i]
 		current := next
 		next = middlewareHandler{
 			middleware: m,
 			next:      current,
-			query:     query,
+			query:     q,
 		}
 	}
 
-	return next.Handle(ctx, query)
-}
-
-func (b *queryBus) Register(queryType interface{}, handler Handler) {
-	t := reflect.TypeOf(queryType)
-	if _, exists := b.handlers[t]; exists {
-		panic(fmt.Sprintf("handler already registered for query type: %v", t))
-	}
-	b.handlers[t] = handler
-}
-
-type middlewareHandler struct {
-	middleware Middleware
-	next       Handler
-	query      interface{}
-}
-
-func (h middlewareHandler) Handle(ctx context.Context, q interface{}) (interface{}, error) {
-	return h.middleware.Execute(ctx, q, h.next)
+	return next.Handle(ctx, q)
 }
 
-// 中间件实现
-type CachingMiddleware struct {
-	cache  Cache
-	logger Logger
+// Register 注册处理器，重复注册不再 panic，由启动时的 Verify 统一报告
+func (b *queryBus) Register(queryType interface{}, handler query.Handler) {
+	b.handlers.Add(queryType, handler)
 }
 
-func (m *CachingMiddleware) Execute(ctx context.Context, q interface{}, next Handler) (interface{}, error) {
-	// 检查是否可缓存
-	if cacheable, ok := q.(Cacheable); ok {
-		key := cacheable.CacheKey()
-		
-		// 尝试从缓存获取
-		if cached, err := m.cache.Get(ctx, key); err == nil && cached != nil {
-			return cached, nil
-		}
-
-		// 执行查询
-		result, err := next.Handle(ctx, q)
-		if err != nil {
-			return nil, err
-		}
-
-		// 缓存结果
-		if err := m.cache.Set(ctx, key, result, cacheable.TTL()); err != nil {
-			m.logger.Error("failed to cache query result", "error", err)
-		}
-
-		return result, nil
+func (b *queryBus) Describe() query.RegistryInfo {
+	entries := b.handlers.Entries()
+	info := query.RegistryInfo{
+		Handlers:   make([]query.HandlerInfo, 0, len(entries)),
+		Middleware: make([]string, 0, len(b.middleware)),
 	}
-
-	return next.Handle(ctx, q)
-}
-
-type LoggingMiddleware struct {
-	logger Logger
+	for _, e := range entries {
+		info.Handlers = append(info.Handlers, query.HandlerInfo{
+			Type:    e.Type.String(),
+			Handler: registry.HandlerName(e.Handler),
+		})
+	}
+	for _, m := range b.middleware {
+		info.Middleware = append(info.Middleware, fmt.Sprintf("%T", m))
+	}
+	return info
 }
 
-func (m *LoggingMiddleware) Execute(ctx context.Context, q interface{}, next Handler) (interface{}, error) {
-	start := time.Now()
-	queryType := reflect.TypeOf(q).String()
-
-	m.logger.Debug("executing query", "type", queryType)
-
-	result, err := next.Handle(ctx, q)
-
-	duration := time.Since(start)
-	if err != nil {
-		m.logger.Error("query failed",
-			"type", queryType,
-			"duration", duration,
-			"error", err,
-		)
-	} else {
-		m.logger.Debug("query completed",
-			"type", queryType,
-			"duration", duration,
-		)
+func (b *queryBus) Verify(required ...interface{}) error {
+	if err := b.handlers.Verify(required...); err != nil {
+		return fmt.Errorf("query bus verification failed: %w", err)
 	}
-
-	return result, err
+	return nil
 }
 
-type MetricsMiddleware struct {
-	metrics MetricsReporter
+type middlewareHandler struct {
+	middleware query.Middleware
+	next       query.Handler
+	query      interface{}
 }
 
-func (m *MetricsMiddleware) Execute(ctx context.Context, q interface{}, next Handler) (interface{}, error) {
-	queryType := reflect.TypeOf(q).String()
-	timer := m.metrics.StartTimer("query_duration", "type", queryType)
-	defer timer.Stop()
-
-	result, err := next.Handle(ctx, q)
-	if err != nil {
-		m.metrics.IncrementCounter("query_failure", "type", queryType)
-	} else {
-		m.metrics.IncrementCounter("query_success", "type", queryType)
-	}
-
-	return result, err
-} 
\ No newline at end of file
+func (h middlewareHandler) Handle(ctx context.Context, q interface{}) (interface{}, error) {
+	return h.middleware.Execute(ctx, q, h.next)
+}
@@ -0,0 +1,85 @@
+package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/port/input/query"
+	"github.com/gohex/gohex/internal/testutil"
+)
+
+type getUserQuery struct {
+	UserID string
+}
+
+// echoHandler 返回收到的查询
+type echoHandler struct{}
+
+func (echoHandler) Handle(ctx context.Context, q interface{}) (interface{}, error) {
+	return q, nil
+}
+
+// tracingMiddleware 记录中间件执行顺序
+type tracingMiddleware struct {
+	name  string
+	trace *[]string
+}
+
+func (m tracingMiddleware) Execute(ctx context.Context, q interface{}, next query.Handler) (interface{}, error) {
+	*m.trace = append(*m.trace, m.name)
+	return next.Handle(ctx, q)
+}
+
+func TestQueryBus_ExecuteRunsMiddlewareInOrder(t *testing.T) {
+	var trace []string
+	bus := NewQueryBus(testutil.NopLogger{}, testutil.NewMetrics(),
+		tracingMiddleware{name: "logging", trace: &trace},
+		tracingMiddleware{name: "cache", trace: &trace},
+	)
+	bus.Register(&getUserQuery{}, echoHandler{})
+
+	result, err := bus.Execute(context.Background(), &getUserQuery{UserID: "alice"})
+	require.NoError(t, err)
+
+	assert.Equal(t, &getUserQuery{UserID: "alice"}, result)
+	assert.Equal(t, []string{"logging", "cache"}, trace)
+}
+
+func TestQueryBus_ExecuteNormalizesValueQuery(t *testing.T) {
+	bus := NewQueryBus(testutil.NopLogger{}, testutil.NewMetrics())
+	bus.Register(&getUserQuery{}, echoHandler{})
+
+	// 处理器以指针注册，值类型查询转换为指针
+	result, err := bus.Execute(context.Background(), getUserQuery{UserID: "alice"})
+	require.NoError(t, err)
+	assert.Equal(t, &getUserQuery{UserID: "alice"}, result)
+}
+
+func TestQueryBus_Errors(t *testing.T) {
+	type listUsersQuery struct{}
+
+	bus := NewQueryBus(testutil.NopLogger{}, testutil.NewMetrics())
+	bus.Register(&getUserQuery{}, echoHandler{})
+	bus.Register(getUserQuery{}, echoHandler{})
+
+	_, err := bus.Execute(context.Background(), &listUsersQuery{})
+	assert.ErrorContains(t, err, "no handler registered for query type")
+
+	err = bus.Verify(&getUserQuery{}, &listUsersQuery{})
+	assert.ErrorContains(t, err, "handler already registered")
+	assert.ErrorContains(t, err, "missing handler for required query type")
+}
+
+func TestQueryBus_Describe(t *testing.T) {
+	var trace []string
+	bus := NewQueryBus(testutil.NopLogger{}, testutil.NewMetrics(), tracingMiddleware{trace: &trace})
+	bus.Register(&getUserQuery{}, echoHandler{})
+
+	info := bus.Describe()
+	require.Len(t, info.Handlers, 1)
+	assert.Equal(t, "query.getUserQuery", info.Handlers[0].Type)
+	assert.Equal(t, []string{"query.tracingMiddleware"}, info.Middleware)
+}
@@ -0,0 +1,194 @@
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// Unwrapper 由适配器实现，用于在注册表中展示被包装的原始处理器
+type Unwrapper interface {
+	Unwrap() interface{}
+}
+
+// Entry 注册表中一条处理器记录
+type Entry struct {
+	// Type 归一化后的消息类型，不含指针
+	Type reflect.Type
+	// Pointer 处理器注册时使用的是否为指针类型
+	Pointer bool
+	Handler interface{}
+}
+
+// Registry 按消息类型保存处理器，指针与值类型视为同一类型
+type Registry struct {
+	mu      sync.RWMutex
+	kind    string
+	entries map[reflect.Type]Entry
+	names   map[string]reflect.Type
+	errs    []error
+}
+
+// New 创建注册表，kind 用于错误信息，如 "command" 或 "query"
+func New(kind string) *Registry {
+	return &Registry{
+		kind:    kind,
+		entries: make(map[reflect.Type]Entry),
+		names:   make(map[string]reflect.Type),
+	}
+}
+
+// Add 注册处理器，重复或无效的注册不会 panic，而是在 Verify 时返回
+func (r *Registry) Add(msgType interface{}, handler interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t := reflect.TypeOf(msgType)
+	if t == nil {
+		r.errs = append(r.errs, fmt.Errorf("%s type must not be nil interface", r.kind))
+		return
+	}
+	if handler == nil {
+		r.errs = append(r.errs, fmt.Errorf("nil handler registered for %s type: %v", r.kind, t))
+		return
+	}
+
+	base := baseType(t)
+	if existing, exists := r.entries[base]; exists {
+		r.errs = append(r.errs, fmt.Errorf("handler already registered for %s type %v: %s, duplicate %s",
+			r.kind, base, HandlerName(existing.Handler), HandlerName(handler)))
+		return
+	}
+
+	r.entries[base] = Entry{
+		Type:    base,
+		Pointer: t.Kind() == reflect.Ptr,
+		Handler: handler,
+	}
+	r.names[TypeName(base)] = base
+}
+
+// Lookup 查找处理器，并将消息转换为处理器注册时的指针或值形式
+func (r *Registry) Lookup(msg interface{}) (interface{}, interface{}, error) {
+	t := reflect.TypeOf(msg)
+	if t == nil {
+		return nil, nil, fmt.Errorf("%s must not be nil", r.kind)
+	}
+
+	r.mu.RLock()
+	entry, exists := r.entries[baseType(t)]
+	r.mu.RUnlock()
+	if !exists {
+		return nil, nil, fmt.Errorf("no handler registered for %s type: %v", r.kind, t)
+	}
+
+	normalized, err := conform(msg, entry.Pointer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid %s %v: %w", r.kind, t, err)
+	}
+	return entry.Handler, normalized, nil
+}
+
+// Decode 按 TypeName 找到注册类型，将 JSON 反序列化为处理器期望的指针或值形式
+func (r *Registry) Decode(name string, payload []byte) (interface{}, error) {
+	r.mu.RLock()
+	t, exists := r.names[name]
+	entry := r.entries[t]
+	r.mu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("no handler registered for %s type: %s", r.kind, name)
+	}
+
+	v := reflect.New(t)
+	if err := json.Unmarshal(payload, v.Interface()); err != nil {
+		return nil, fmt.Errorf("failed to decode %s %s: %w", r.kind, name, err)
+	}
+	if entry.Pointer {
+		return v.Interface(), nil
+	}
+	return v.Elem().Interface(), nil
+}
+
+// Entries 按类型名排序返回所有注册记录
+func (r *Registry) Entries() []Entry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := make([]Entry, 0, len(r.entries))
+	for _, e := range r.entries {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Type.String() < entries[j].Type.String()
+	})
+	return entries
+}
+
+// Verify 返回注册期间的错误以及 required 中缺少处理器的类型
+func (r *Registry) Verify(required ...interface{}) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	errs := append([]error(nil), r.errs...)
+	for _, msgType := range required {
+		t := reflect.TypeOf(msgType)
+		if t == nil {
+			continue
+		}
+		if _, exists := r.entries[baseType(t)]; !exists {
+			errs = append(errs, fmt.Errorf("missing handler for required %s type: %v", r.kind, baseType(t)))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// HandlerName 返回处理器的类型名，适配器返回被包装的处理器
+func HandlerName(handler interface{}) string {
+	if u, ok := handler.(Unwrapper); ok {
+		return HandlerName(u.Unwrap())
+	}
+	return fmt.Sprintf("%T", handler)
+}
+
+// TypeName 返回消息类型的完整名称（包路径加类型名），用于持久化后重新解码
+func TypeName(msg interface{}) string {
+	t, ok := msg.(reflect.Type)
+	if !ok {
+		t = reflect.TypeOf(msg)
+	}
+	if t == nil {
+		return ""
+	}
+	t = baseType(t)
+	return t.PkgPath() + "." + t.Name()
+}
+
+func baseType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// conform 按需取地址或解引用，使处理器中的类型断言始终成立
+func conform(msg interface{}, pointer bool) (interface{}, error) {
+	v := reflect.ValueOf(msg)
+	isPointer := v.Kind() == reflect.Ptr
+
+	switch {
+	case pointer == isPointer:
+		return msg, nil
+	case pointer:
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		return p.Interface(), nil
+	default:
+		if v.IsNil() {
+			return nil, errors.New("nil pointer")
+		}
+		return v.Elem().Interface(), nil
+	}
+}
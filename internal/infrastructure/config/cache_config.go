@@ -0,0 +1,29 @@
+package config
+
+import "time"
+
+// 缓存存储
+const (
+	CacheDriverRedis  = "redis"
+	CacheDriverMemory = "memory"
+)
+
+type CacheStoreConfig struct {
+	// 缓存存储，redis 或 memory；memory 仅适用于单实例部署和测试
+	Driver string `yaml:"driver"`
+	// memory 存储清理过期条目的间隔
+	CleanupInterval time.Duration `yaml:"cleanup_interval"`
+	// Local Redis 前的进程内缓存
+	Local LocalCacheConfig `yaml:"local"`
+}
+
+type LocalCacheConfig struct {
+	// 是否在 Redis 前启用进程内缓存
+	Enabled bool `yaml:"enabled"`
+	// 进程内缓存的最大条目数，超出后淘汰最久未使用的条目
+	MaxEntries int `yaml:"max_entries"`
+	// 进程内缓存的最长保留时间，短于写入时的 ttl 时以此为准
+	TTL time.Duration `yaml:"ttl"`
+	// 实例间广播失效消息的 Redis 频道
+	InvalidationChannel string `yaml:"invalidation_channel"`
+}
@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 type CommandBusConfig struct {
     Middleware CommandMiddlewareConfig `yaml:"middleware"`
     Handlers   CommandHandlerConfig   `yaml:"handlers"`
@@ -14,6 +16,15 @@ type CommandHandlerConfig struct {
     // 处理器重试配置
     RetryAttempts int           `yaml:"retry_attempts"`
     RetryDelay    time.Duration `yaml:"retry_delay"`
+    // 按命令类型名覆盖的配置，零值继承全局配置，负数表示关闭该项
+    Commands map[string]CommandPolicyConfig `yaml:"commands"`
+}
+
+type CommandPolicyConfig struct {
+    Timeout        time.Duration `yaml:"timeout"`
+    MaxConcurrency int           `yaml:"max_concurrency"`
+    RetryAttempts  int           `yaml:"retry_attempts"`
+    RetryDelay     time.Duration `yaml:"retry_delay"`
 }
 
 type CommandMetricsConfig struct {
@@ -1,11 +1,17 @@
 package config
 
+import "time"
+
 type CommandMiddlewareConfig struct {
-    Validation   ValidationConfig   `yaml:"validation"`
+    Validation   ValidationConfig  `yaml:"validation"`
     Transaction  TransactionConfig `yaml:"transaction"`
-    Logging      LoggingConfig     `yaml:"logging"`
-    Metrics      MetricsConfig     `yaml:"metrics"`
     Events       EventConfig       `yaml:"events"`
+    Audit        AuditConfig       `yaml:"audit"`
+    Idempotency  IdempotencyConfig `yaml:"idempotency"`
+}
+
+type ValidationConfig struct {
+    Enabled bool `yaml:"enabled"`
 }
 
 type TransactionConfig struct {
@@ -15,9 +21,42 @@ type TransactionConfig struct {
     Isolation   string `yaml:"isolation"`   // ReadCommitted, RepeatableRead, Serializable
 }
 
+// 事件队列满时的处理方式
+const (
+    EventBackpressureBlock = "block"
+    EventBackpressureDrop  = "drop"
+)
+
 type EventConfig struct {
-    Enabled         bool `yaml:"enabled"`
+    Enabled bool `yaml:"enabled"`
+    // 为 true 时事件进入各处理器的队列由工作协程处理，Publish 不等待处理结果
     AsyncPublishing bool `yaml:"async_publishing"`
-    BatchSize       int  `yaml:"batch_size"`
-    RetryAttempts   int  `yaml:"retry_attempts"`
-} 
\ No newline at end of file
+    // 异步模式下每个处理器队列的容量
+    BatchSize     int `yaml:"batch_size"`
+    RetryAttempts int `yaml:"retry_attempts"`
+    // 异步模式下每个处理器的工作协程数
+    Workers int `yaml:"workers"`
+    // 队列满时 block 等待空位，drop 丢弃事件并返回错误，默认 block
+    Backpressure string `yaml:"backpressure"`
+    // 单个处理器处理一个事件的超时时间，0 表示不限制
+    HandlerTimeout time.Duration `yaml:"handler_timeout"`
+    // 关闭时等待队列中事件处理完成的最长时间
+    DrainTimeout time.Duration `yaml:"drain_timeout"`
+}
+
+type AuditConfig struct {
+    Enabled bool `yaml:"enabled"`
+    // 审计记录保留时长，0 表示永久保留
+    Retention     time.Duration `yaml:"retention"`
+    PurgeInterval time.Duration `yaml:"purge_interval"`
+    // 导出接口单次最大行数
+    MaxExportRows int `yaml:"max_export_rows"`
+}
+
+type IdempotencyConfig struct {
+    Enabled bool `yaml:"enabled"`
+    // 结果保留时长，期间相同 key 的重试直接返回已保存的结果
+    TTL time.Duration `yaml:"ttl"`
+    // 执行中锁的超时时间，命令执行期间定期续期，进程崩溃后锁到期自动释放
+    LockTTL time.Duration `yaml:"lock_ttl"`
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"time"
+	"github.com/go-viper/mapstructure/v2"
 	"github.com/spf13/viper"
 	"errors"
 	"fmt"
@@ -9,13 +10,30 @@ import (
 )
 
 type Config struct {
-	App      AppConfig
-	HTTP     HTTPConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	JWT      JWTConfig
-	Log      LogConfig
-	Auth     AuthConfig
+	App          AppConfig
+	HTTP         HTTPConfig
+	Database     DatabaseConfig
+	Redis        RedisConfig
+	Cache        CacheStoreConfig
+	JWT          JWTConfig
+	Log          LogConfig
+	Metrics      MetricsConfig
+	Tracing      TracingConfig
+	SMTP         SMTPConfig
+	Auth         AuthConfig
+	GDPR         GDPRConfig
+	Lifecycle    LifecycleConfig
+	Jobs         JobConfig
+	CommandBus   CommandBusConfig
+	QueryBus     QueryBusConfig
+	Resilience   ResilienceConfig
+	EventBus     EventBusConfig
+	Kafka        KafkaConfig
+	NATS         NATSConfig
+	Webhooks     WebhookConfig
+	Sagas        SagaConfig
+	Provisioning ProvisioningConfig
+	Scheduler    SchedulerConfig
 }
 
 type AppConfig struct {
@@ -29,6 +47,8 @@ type HTTPConfig struct {
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
 	IdleTimeout  time.Duration
+	// ShutdownTimeout 优雅关闭的最长等待时间
+	ShutdownTimeout time.Duration
 }
 
 type DatabaseConfig struct {
@@ -40,10 +60,10 @@ type DatabaseConfig struct {
 	Password        string        `yaml:"password"`
 	MaxOpenConns    int           `yaml:"max_open_conns"`
 	MaxIdleConns    int           `yaml:"max_idle_conns"`
-	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
 }
 
-"`func (c *DatabaseConfig) DSN() string {
+func (c *DatabaseConfig) DSN() string {
 	return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=true&charset=utf8mb4&loc=Local",
 		c.Username,
 		c.Password,
@@ -53,11 +73,36 @@ type DatabaseConfig struct {
 	)
 }
 
+// Redis 部署模式
+const (
+	RedisModeStandalone = "standalone"
+	RedisModeSentinel   = "sentinel"
+	RedisModeCluster    = "cluster"
+)
+
 type RedisConfig struct {
+	// Mode standalone、sentinel 或 cluster，默认 standalone
+	Mode     string
 	Host     string
 	Port     int
+	// Addrs 哨兵或集群节点地址，设置后忽略 Host 和 Port
+	Addrs    []string
 	Password string
 	DB       int
+	// MasterName 哨兵模式下的主节点名
+	MasterName       string `yaml:"master_name"`
+	SentinelPassword string `yaml:"sentinel_password"`
+	PoolSize         int    `yaml:"pool_size"`
+	// Namespace 缓存 key 前缀，为空时使用 "应用名:环境"，避免多个环境共用 Redis 时互相覆盖
+	Namespace string
+}
+
+// Addresses 返回要连接的节点地址
+func (c RedisConfig) Addresses() []string {
+	if len(c.Addrs) > 0 {
+		return c.Addrs
+	}
+	return []string{fmt.Sprintf("%s:%d", c.Host, c.Port)}
 }
 
 type JWTConfig struct {
@@ -71,6 +116,24 @@ type LogConfig struct {
 	OutputPath string
 }
 
+type MetricsConfig struct {
+	Namespace string
+}
+
+type TracingConfig struct {
+	Enabled  bool
+	Endpoint string
+}
+
+type SMTPConfig struct {
+	Host       string
+	Port       int
+	Username   string
+	Password   string
+	From       string
+	WebsiteURL string
+}
+
 type AuthConfig struct {
 	JWT struct {
 		SecretKey      string        `yaml:"secret_key"`
@@ -98,6 +161,15 @@ type AuthConfig struct {
 		Secure       bool          `yaml:"secure"`
 		HttpOnly     bool          `yaml:"http_only"`
 	} `yaml:"session"`
+
+	Impersonation struct {
+		Enabled bool          `yaml:"enabled"`
+		TTL     time.Duration `yaml:"ttl"`
+	} `yaml:"impersonation"`
+
+	EmailChange struct {
+		TTL time.Duration `yaml:"ttl"`
+	} `yaml:"email_change"`
 }
 
 func Load(configPath string) (*Config, error) {
@@ -115,7 +187,7 @@ func Load(configPath string) (*Config, error) {
 	}
 
 	var config Config
-	if err := viper.Unmarshal(&config); err != nil {
+	if err := viper.Unmarshal(&config, decodeSnakeCase); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
@@ -126,6 +198,15 @@ func Load(configPath string) (*Config, error) {
 	return &config, nil
 }
 
+// decodeSnakeCase 配置文件使用 snake_case 键：有 yaml 标签的字段按标签匹配，
+// 其余字段按去掉下划线后不区分大小写匹配，如 read_timeout 对应 ReadTimeout
+func decodeSnakeCase(dc *mapstructure.DecoderConfig) {
+	dc.TagName = "yaml"
+	dc.MatchName = func(mapKey, fieldName string) bool {
+		return strings.EqualFold(strings.ReplaceAll(mapKey, "_", ""), strings.ReplaceAll(fieldName, "_", ""))
+	}
+}
+
 func (c *Config) Validate() error {
 	if c.App.Name == "" {
 		return errors.New("app name is required")
@@ -154,6 +235,48 @@ func (c *Config) Validate() error {
 	if c.Database.MaxOpenConns <= 0 {
 		return errors.New("invalid max open connections")
 	}
+	switch c.Redis.Mode {
+	case "", RedisModeStandalone, RedisModeCluster:
+	case RedisModeSentinel:
+		if c.Redis.MasterName == "" {
+			return errors.New("redis master name is required in sentinel mode")
+		}
+	default:
+		return fmt.Errorf("invalid redis mode: %s", c.Redis.Mode)
+	}
+	switch c.EventBus.Driver {
+	case "", EventBusDriverMemory:
+	case EventBusDriverKafka:
+		if len(c.Kafka.Brokers) == 0 {
+			return errors.New("kafka brokers are required")
+		}
+	case EventBusDriverNATS:
+		if c.NATS.URL == "" {
+			return errors.New("nats url is required")
+		}
+	case EventBusDriverRabbitMQ:
+		if c.EventBus.RabbitMQ.URL == "" {
+			return errors.New("rabbitmq url is required")
+		}
+	default:
+		return fmt.Errorf("invalid event bus driver: %s", c.EventBus.Driver)
+	}
+	if len(c.Kafka.Brokers) > 0 && c.Kafka.ConsumerGroup == "" {
+		return errors.New("kafka consumer group is required")
+	}
+	switch c.Kafka.TopicMode {
+	case "", KafkaTopicPerEvent, KafkaTopicPerAggregate:
+	default:
+		return fmt.Errorf("invalid kafka topic mode: %s", c.Kafka.TopicMode)
+	}
+	if c.Kafka.Producer.Async && c.Kafka.Producer.TransactionalID != "" {
+		return errors.New("kafka async producer cannot be transactional")
+	}
+	for _, d := range c.Kafka.Retry.Delays {
+		if d <= 0 {
+			return errors.New("kafka retry delays must be positive")
+		}
+	}
 	if c.JWT.SecretKey == "" {
 		return errors.New("JWT secret key is required")
 	}
@@ -161,4 +284,12 @@ func (c *Config) Validate() error {
 		return errors.New("invalid JWT token duration")
 	}
 	return nil
-} 
\ No newline at end of file
+}
+
+// CacheNamespace 返回缓存 key 的命名空间
+func (c *Config) CacheNamespace() string {
+	if c.Redis.Namespace != "" {
+		return c.Redis.Namespace
+	}
+	return c.App.Name + ":" + c.App.Environment
+}
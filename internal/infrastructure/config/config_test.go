@@ -0,0 +1,34 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_DecodesSnakeCaseKeys(t *testing.T) {
+	cfg, err := Load("../../../config/config.yaml")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{"untagged field", cfg.HTTP.ReadTimeout, 30 * time.Second},
+		{"untagged multi-word field", cfg.HTTP.ShutdownTimeout, 15 * time.Second},
+		{"yaml tagged field", cfg.Auth.Impersonation.TTL, 15 * time.Minute},
+		{"yaml tagged nested struct", cfg.Auth.EmailChange.TTL, 24 * time.Hour},
+		{"yaml tagged bool", cfg.Auth.Impersonation.Enabled, true},
+		{"nested middleware config", cfg.CommandBus.Middleware.Events.HandlerTimeout, 30 * time.Second},
+		{"lifecycle grace period", cfg.Lifecycle.DeletionGracePeriod, 720 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.got)
+		})
+	}
+}
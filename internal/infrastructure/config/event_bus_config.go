@@ -0,0 +1,53 @@
+package config
+
+import "time"
+
+// 事件总线实现
+const (
+	EventBusDriverMemory   = "memory"
+	EventBusDriverKafka    = "kafka"
+	EventBusDriverNATS     = "nats"
+	EventBusDriverRabbitMQ = "rabbitmq"
+)
+
+type EventBusConfig struct {
+	// Driver memory、kafka、nats 或 rabbitmq，默认 memory；memory 的行为由 command_bus.middleware.events 配置
+	Driver   string         `yaml:"driver"`
+	RabbitMQ RabbitMQConfig `yaml:"rabbitmq"`
+}
+
+type RabbitMQConfig struct {
+	URL string `yaml:"url"`
+	// Exchange 发布事件的 topic 交换机，事件类型即路由键，默认 gohex.events
+	Exchange string `yaml:"exchange"`
+	// DeadLetterExchange 处理失败的消息转入的交换机，默认 "Exchange.dlx"
+	DeadLetterExchange string `yaml:"dead_letter_exchange"`
+	// Group 订阅组，与处理器 ID 组合成持久队列名；同一组的多个实例共享队列，默认应用名
+	Group string `yaml:"group"`
+	// Prefetch 每个消费者未确认消息的上限
+	Prefetch int `yaml:"prefetch"`
+	// ConfirmTimeout 等待 broker 发布确认的最长时间
+	ConfirmTimeout time.Duration `yaml:"confirm_timeout"`
+	// ReconnectDelay 连接或通道断开后重试的间隔
+	ReconnectDelay time.Duration `yaml:"reconnect_delay"`
+}
+
+type NATSConfig struct {
+	URL string `yaml:"url"`
+	// Stream JetStream 流名称，默认 GOHEX
+	Stream string `yaml:"stream"`
+	// SubjectPrefix 事件主题前缀，事件 user.created 发布到 "前缀.user.created"，默认 gohex
+	SubjectPrefix string `yaml:"subject_prefix"`
+	// Durable 持久消费者名称前缀，与处理器 ID 组合成每个处理器独立的消费者
+	Durable string `yaml:"durable"`
+	// AckWait 处理器未确认时多久后重新投递
+	AckWait time.Duration `yaml:"ack_wait"`
+	// MaxDeliver 最多投递次数，超过后不再重新投递
+	MaxDeliver int `yaml:"max_deliver"`
+	// NakDelay 处理失败后首次重新投递的延迟，之后按投递次数递增
+	NakDelay time.Duration `yaml:"nak_delay"`
+	// MaxAckPending 每个消费者未确认消息的上限
+	MaxAckPending int `yaml:"max_ack_pending"`
+	// DuplicateWindow 按事件 ID 去重的时间窗口
+	DuplicateWindow time.Duration `yaml:"duplicate_window"`
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"time"
+)
+
+type GDPRConfig struct {
+	// base64 编码的 32 字节主密钥，用于包裹每个用户的数据密钥
+	MasterKey string `yaml:"master_key"`
+	// 导出归档的下载有效期
+	ExportTTL time.Duration `yaml:"export_ttl"`
+	// 后台任务轮询待处理导出请求的间隔
+	PollInterval time.Duration `yaml:"poll_interval"`
+}
+
+// MasterKeyBytes 解码主密钥
+func (c GDPRConfig) MasterKeyBytes() ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(c.MasterKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gdpr master key: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("gdpr master key must be 32 bytes, got %d", len(key))
+	}
+	return key, nil
+}
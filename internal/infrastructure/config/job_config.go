@@ -0,0 +1,24 @@
+package config
+
+import "time"
+
+// 任务队列存储
+const (
+	JobDriverMySQL = "mysql"
+	JobDriverRedis = "redis"
+)
+
+type JobConfig struct {
+	// 任务存储，mysql 或 redis
+	Driver string `yaml:"driver"`
+	// 每个实例并发执行任务的 worker 数
+	Workers           int           `yaml:"workers"`
+	PollInterval      time.Duration `yaml:"poll_interval"`
+	HeartbeatInterval time.Duration `yaml:"heartbeat_interval"`
+	// 超过该时间没有心跳的运行中任务视为所在实例已退出，重新入队
+	LeaseTimeout time.Duration `yaml:"lease_timeout"`
+	// 任务最多被领取的次数，超过后租约再次过期时转为死信，默认 3
+	MaxAttempts int `yaml:"max_attempts"`
+	// 已结束任务的保留时间
+	Retention time.Duration `yaml:"retention"`
+}
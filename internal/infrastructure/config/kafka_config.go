@@ -0,0 +1,90 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 主题划分方式
+const (
+	KafkaTopicPerEvent     = "event"
+	KafkaTopicPerAggregate = "aggregate"
+)
+
+type KafkaConfig struct {
+	Brokers []string `yaml:"brokers"`
+	// Version broker 版本，幂等和事务生产者要求 0.11.0.0 以上
+	Version       string `yaml:"version"`
+	ConsumerGroup string `yaml:"consumer_group"`
+	// TopicPrefix 事件主题前缀，默认 events
+	TopicPrefix string `yaml:"topic_prefix"`
+	// TopicMode event 为每种事件一个主题，aggregate 为每种聚合一个主题，
+	// 后者保证同一聚合的所有事件按发布顺序消费，默认 event
+	TopicMode string              `yaml:"topic_mode"`
+	Producer  KafkaProducerConfig `yaml:"producer"`
+	Retry     KafkaRetryConfig    `yaml:"retry"`
+	// DeadLetterTopic 重试耗尽或无法解析的消息转入的主题，为空时使用 "消费组.dlq"
+	DeadLetterTopic string `yaml:"dead_letter_topic"`
+}
+
+type KafkaProducerConfig struct {
+	// Idempotent 开启幂等生产者，broker 端对重试产生的重复消息去重
+	Idempotent bool `yaml:"idempotent"`
+	// TransactionalID 非空时每次 Publish 的所有事件在一个事务中提交，隐含开启幂等
+	TransactionalID string `yaml:"transactional_id"`
+	// Async 为 true 时 Publish 只入队，发送结果在后台记录；不能与事务同时使用
+	Async bool `yaml:"async"`
+	// FlushMessages 和 FlushFrequency 控制批量发送，任一条件满足即发送
+	FlushMessages  int           `yaml:"flush_messages"`
+	FlushFrequency time.Duration `yaml:"flush_frequency"`
+	// Compression none、gzip、snappy、lz4 或 zstd
+	Compression string `yaml:"compression"`
+}
+
+type KafkaRetryConfig struct {
+	// Delays 依次为第 1、2... 次重试前的等待时间，每一项对应一个重试主题；为空时失败直接进入死信主题
+	Delays []time.Duration `yaml:"delays"`
+}
+
+// EventTopic 返回事件应发布到的主题
+func (c KafkaConfig) EventTopic(eventType string) string {
+	prefix := c.TopicPrefix
+	if prefix == "" {
+		prefix = "events"
+	}
+	if c.TopicMode == KafkaTopicPerAggregate {
+		return prefix + "." + AggregateType(eventType)
+	}
+	return prefix + "." + eventType
+}
+
+// AggregateType 从 "聚合.事件" 形式的事件类型中取出聚合名
+func AggregateType(eventType string) string {
+	if i := strings.IndexByte(eventType, '.'); i > 0 {
+		return eventType[:i]
+	}
+	return eventType
+}
+
+// RetryTopic 返回第 attempt 次重试使用的主题
+func (c KafkaConfig) RetryTopic(attempt int) string {
+	return fmt.Sprintf("%s.retry.%d", c.ConsumerGroup, attempt)
+}
+
+// RetryTopics 返回所有重试主题
+func (c KafkaConfig) RetryTopics() []string {
+	topics := make([]string, 0, len(c.Retry.Delays))
+	for i := range c.Retry.Delays {
+		topics = append(topics, c.RetryTopic(i+1))
+	}
+	return topics
+}
+
+// DeadLetter 返回死信主题
+func (c KafkaConfig) DeadLetter() string {
+	if c.DeadLetterTopic != "" {
+		return c.DeadLetterTopic
+	}
+	return c.ConsumerGroup + ".dlq"
+}
@@ -0,0 +1,10 @@
+package config
+
+import "time"
+
+type LifecycleConfig struct {
+	// 软删除后的宽限期，期间管理员可以恢复用户
+	DeletionGracePeriod time.Duration `yaml:"deletion_grace_period"`
+	// 每次清理按批查询到期用户，每批最多的用户数
+	PurgeBatchSize int `yaml:"purge_batch_size"`
+}
@@ -1,11 +1,21 @@
 package config
 
+import "time"
+
+type QueryBusConfig struct {
+    Middleware QueryMiddlewareConfig `yaml:"middleware"`
+}
+
 type QueryMiddlewareConfig struct {
     Validation ValidationConfig `yaml:"validation"`
-    Cache      CacheConfig     `yaml:"cache"`
-    Logging    LoggingConfig   `yaml:"logging"`
-    Retry      RetryConfig     `yaml:"retry"`
-    Timeout    TimeoutConfig   `yaml:"timeout"`
+    Cache      QueryCacheConfig `yaml:"cache"`
+    Retry      RetryConfig      `yaml:"retry"`
+    Timeout    TimeoutConfig    `yaml:"timeout"`
+}
+
+type QueryCacheConfig struct {
+    Enabled bool          `yaml:"enabled"`
+    TTL     time.Duration `yaml:"ttl"`
 }
 
 type RetryConfig struct {
@@ -0,0 +1,44 @@
+package config
+
+import "time"
+
+type ResilienceConfig struct {
+	// CircuitBreaker 所有熔断器的默认配置
+	CircuitBreaker CircuitBreakerConfig `yaml:"circuit_breaker"`
+	// CircuitBreakers 按依赖名（redis、smtp、kafka、token）覆盖的配置，零值继承默认配置
+	CircuitBreakers map[string]CircuitBreakerConfig `yaml:"circuit_breakers"`
+}
+
+type CircuitBreakerConfig struct {
+	// 连续失败多少次后打开熔断
+	FailureThreshold int `yaml:"failure_threshold"`
+	// 半开状态下连续成功多少次后关闭熔断
+	SuccessThreshold int `yaml:"success_threshold"`
+	// 熔断打开后等待多久进入半开状态
+	OpenTimeout time.Duration `yaml:"open_timeout"`
+	// 半开状态下允许同时放行的探测请求数
+	HalfOpenMaxRequests int `yaml:"half_open_max_requests"`
+}
+
+// For 返回指定依赖的熔断配置
+func (c ResilienceConfig) For(name string) CircuitBreakerConfig {
+	cfg := c.CircuitBreaker
+	override, ok := c.CircuitBreakers[name]
+	if !ok {
+		return cfg
+	}
+
+	if override.FailureThreshold > 0 {
+		cfg.FailureThreshold = override.FailureThreshold
+	}
+	if override.SuccessThreshold > 0 {
+		cfg.SuccessThreshold = override.SuccessThreshold
+	}
+	if override.OpenTimeout > 0 {
+		cfg.OpenTimeout = override.OpenTimeout
+	}
+	if override.HalfOpenMaxRequests > 0 {
+		cfg.HalfOpenMaxRequests = override.HalfOpenMaxRequests
+	}
+	return cfg
+}
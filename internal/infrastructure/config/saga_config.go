@@ -0,0 +1,21 @@
+package config
+
+import "time"
+
+// SagaConfig 步骤超时由调度器按计划分发，无需单独轮询
+type SagaConfig struct {
+	Onboarding OnboardingConfig `yaml:"onboarding"`
+}
+
+type OnboardingConfig struct {
+	// 注册后验证邮箱的期限，同时是验证链接的有效期
+	VerificationTimeout time.Duration `yaml:"verification_timeout"`
+	// 发送验证邮件后仍未验证时提醒的时间，为 0 时不提醒
+	ReminderAfter time.Duration `yaml:"reminder_after"`
+}
+
+// ProvisioningConfig 下游开通服务，URL 为空时不调用下游系统
+type ProvisioningConfig struct {
+	URL     string        `yaml:"url"`
+	Timeout time.Duration `yaml:"timeout"`
+}
@@ -0,0 +1,13 @@
+package config
+
+import "time"
+
+type SchedulerConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// 检查到期计划的间隔，也是计划执行时间的最大误差
+	PollInterval time.Duration `yaml:"poll_interval"`
+	// 每次最多执行的到期计划数
+	BatchSize int `yaml:"batch_size"`
+	// 选主租约有效期，主实例宕机后其他实例最晚在此时间后接管
+	LeaseTTL time.Duration `yaml:"lease_ttl"`
+}
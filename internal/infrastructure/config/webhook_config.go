@@ -0,0 +1,21 @@
+package config
+
+import "time"
+
+type WebhookConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// 每个实例并发投递的 worker 数
+	Workers      int           `yaml:"workers"`
+	PollInterval time.Duration `yaml:"poll_interval"`
+	// 单次请求超时
+	Timeout time.Duration `yaml:"timeout"`
+	// 每条投递最多尝试次数，耗尽后标记为失败，只能手动重新投递
+	MaxAttempts int `yaml:"max_attempts"`
+	// 第一次重试前的等待时间，之后每次翻倍，不超过 MaxBackoff
+	InitialBackoff time.Duration `yaml:"initial_backoff"`
+	MaxBackoff     time.Duration `yaml:"max_backoff"`
+	// 订阅连续失败多少次后自动停用，0 表示不停用
+	DisableAfter int `yaml:"disable_after"`
+	// 已结束投递记录的保留时间
+	Retention time.Duration `yaml:"retention"`
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/event"
+)
+
+// userCacheEvents 会改变用户详情或用户列表内容的事件
+var userCacheEvents = []string{
+	event.UserCreated,
+	event.UserProfileUpdated,
+	event.UserStatusChanged,
+	event.RoleAssigned,
+	event.RoleRevoked,
+	event.UserLocked,
+	event.UserUnlocked,
+	event.UserDeactivated,
+	event.UserReactivated,
+	event.UserDeleted,
+	event.UserRestored,
+	event.UserPurged,
+	event.UserErased,
+	event.UserEmailChanged,
+}
+
+// CacheInvalidationHandler 用户数据变更后按标签失效相关的查询缓存
+type CacheInvalidationHandler struct {
+	cache   output.TaggedCache
+	logger  output.Logger
+	metrics output.MetricsReporter
+}
+
+func NewCacheInvalidationHandler(cache output.TaggedCache, logger output.Logger, metrics output.MetricsReporter) *CacheInvalidationHandler {
+	return &CacheInvalidationHandler{
+		cache:   cache,
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+// EventTypes 返回需要订阅的事件类型
+func (h *CacheInvalidationHandler) EventTypes() []string {
+	return userCacheEvents
+}
+
+func (h *CacheInvalidationHandler) HandlerID() string {
+	return "cache_invalidation"
+}
+
+func (h *CacheInvalidationHandler) Handle(ctx context.Context, evt event.Event) error {
+	tags := h.tagsFor(evt)
+	if len(tags) == 0 {
+		return nil
+	}
+
+	// 失效失败时返回错误：外部消息总线会重新投递；进程内总线不重试，
+	// 只记录失败，缓存条目在过期后才会更新
+	if err := h.cache.InvalidateTags(ctx, tags...); err != nil {
+		h.logger.Error("failed to invalidate cache tags",
+			"event_type", evt.Type(),
+			"aggregate_id", evt.AggregateID(),
+			"tags", tags,
+			"error", err,
+		)
+		h.metrics.IncrementCounter("cache_invalidation_failure", "event_type", evt.Type())
+		return err
+	}
+
+	h.metrics.IncrementCounter("cache_invalidation", "event_type", evt.Type())
+	return nil
+}
+
+func (h *CacheInvalidationHandler) tagsFor(evt event.Event) []string {
+	for _, t := range userCacheEvents {
+		if evt.Type() == t {
+			return []string{output.UserCacheTag(evt.AggregateID()), output.TagUsersList}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"context"
+	stderrors "errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/event"
+	"github.com/gohex/gohex/internal/domain/vo"
+	"github.com/gohex/gohex/internal/infrastructure/adapter/secondary/cache/memory"
+	"github.com/gohex/gohex/internal/testutil"
+)
+
+// brokenTaggedCache 模拟标签失效失败
+type brokenTaggedCache struct {
+	output.TaggedCache
+}
+
+func (brokenTaggedCache) InvalidateTags(ctx context.Context, tags ...string) error {
+	return stderrors.New("connection refused")
+}
+
+func TestCacheInvalidationHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		event       event.Event
+		wantEvicted []string
+	}{
+		{
+			name:        "profile update evicts user and lists",
+			event:       event.NewUserProfileUpdatedEvent("alice", "Alice", ""),
+			wantEvicted: []string{"user:id:alice", "users:list:1"},
+		},
+		{
+			name:        "role change evicts user and lists",
+			event:       event.NewUserRoleAssignedEvent("alice", vo.RoleAdmin),
+			wantEvicted: []string{"user:id:alice", "users:list:1"},
+		},
+		{
+			name:        "status change evicts user and lists",
+			event:       event.NewUserStatusChangedEvent("alice", vo.StatusActive, vo.StatusInactive),
+			wantEvicted: []string{"user:id:alice", "users:list:1"},
+		},
+		{name: "login keeps cache", event: event.NewUserLoggedInEvent("alice", "203.0.113.7", "ios/1.0")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			cache := memory.NewCache(time.Minute, testutil.NopLogger{}, testutil.NewMetrics())
+			require.NoError(t, cache.SetWithTags(ctx, "user:id:alice", "alice", time.Minute, []string{output.UserCacheTag("alice")}))
+			require.NoError(t, cache.SetWithTags(ctx, "user:id:bob", "bob", time.Minute, []string{output.UserCacheTag("bob")}))
+			require.NoError(t, cache.SetWithTags(ctx, "users:list:1", "page-1", time.Minute, []string{output.TagUsersList}))
+			h := NewCacheInvalidationHandler(cache, testutil.NopLogger{}, testutil.NewMetrics())
+
+			require.NoError(t, h.Handle(ctx, tt.event))
+
+			for _, key := range []string{"user:id:alice", "user:id:bob", "users:list:1"} {
+				value, err := cache.Get(ctx, key)
+				require.NoError(t, err)
+				if slices.Contains(tt.wantEvicted, key) {
+					assert.Nil(t, value, key)
+				} else {
+					assert.NotNil(t, value, key)
+				}
+			}
+		})
+	}
+}
+
+func TestCacheInvalidationHandler_ReturnsErrorForRetry(t *testing.T) {
+	metrics := testutil.NewMetrics()
+	h := NewCacheInvalidationHandler(brokenTaggedCache{}, testutil.NopLogger{}, metrics)
+
+	err := h.Handle(context.Background(), event.NewUserDeletedEvent("alice", "admin", time.Now()))
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, metrics.Counter("cache_invalidation_failure"))
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/event"
+)
+
+// IdempotentHandler 按 (HandlerID, 事件 ID) 去重的处理器装饰器。
+// 处理记录与处理器的写入在同一事务中提交，处理失败时一并回滚，事件可被重新处理
+type IdempotentHandler struct {
+	next    output.EventHandler
+	store   output.ProcessedEventStore
+	uow     output.UnitOfWork
+	logger  output.Logger
+	metrics output.MetricsReporter
+}
+
+func NewIdempotentHandler(
+	next output.EventHandler,
+	store output.ProcessedEventStore,
+	uow output.UnitOfWork,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *IdempotentHandler {
+	return &IdempotentHandler{
+		next:    next,
+		store:   store,
+		uow:     uow,
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+func (h *IdempotentHandler) HandlerID() string {
+	return h.next.HandlerID()
+}
+
+func (h *IdempotentHandler) Handle(ctx context.Context, evt event.Event) error {
+	// 处理过程中分发的命令和产生的事件以 evt 为直接原因
+	ctx = event.CausedBy(ctx, evt)
+
+	return h.uow.WithTransaction(ctx, func(ctx context.Context) error {
+		first, err := h.store.MarkProcessed(ctx, h.next.HandlerID(), evt.ID())
+		if err != nil {
+			return err
+		}
+		if !first {
+			h.logger.Debug("duplicate event skipped",
+				"handler", h.next.HandlerID(),
+				"event_id", evt.ID(),
+				"event_type", evt.Type(),
+			)
+			h.metrics.IncrementCounter("event_duplicate_skipped", "handler", h.next.HandlerID(), "event_type", evt.Type())
+			return nil
+		}
+		return h.next.Handle(ctx, evt)
+	})
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/domain/event"
+	"github.com/gohex/gohex/internal/testutil"
+)
+
+type pendingKey struct{}
+
+// txStore 模拟处理记录与处理器写入同事务提交：事务内的标记在回滚时丢弃
+type txStore struct {
+	processed map[string]bool
+}
+
+func (s *txStore) MarkProcessed(ctx context.Context, handlerID, eventID string) (bool, error) {
+	key := handlerID + "/" + eventID
+	pending := ctx.Value(pendingKey{}).(map[string]bool)
+	if s.processed[key] || pending[key] {
+		return false, nil
+	}
+	pending[key] = true
+	return true, nil
+}
+
+// txUnitOfWork 提交时写入 txStore，出错时丢弃
+type txUnitOfWork struct {
+	testutil.UnitOfWork
+	store *txStore
+}
+
+func (u txUnitOfWork) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	pending := make(map[string]bool)
+	if err := fn(context.WithValue(ctx, pendingKey{}, pending)); err != nil {
+		return err
+	}
+	for key := range pending {
+		u.store.processed[key] = true
+	}
+	return nil
+}
+
+// countingEventHandler 记录调用次数，前 failures 次返回错误
+type countingEventHandler struct {
+	calls    int
+	failures int
+	ctx      context.Context
+}
+
+func (h *countingEventHandler) HandlerID() string { return "user_notifications" }
+
+func (h *countingEventHandler) Handle(ctx context.Context, evt event.Event) error {
+	h.calls++
+	h.ctx = ctx
+	if h.calls <= h.failures {
+		return stderrors.New("smtp unavailable")
+	}
+	return nil
+}
+
+func newIdempotentHandler(next *countingEventHandler) (*IdempotentHandler, *txStore, *testutil.Metrics) {
+	store := &txStore{processed: make(map[string]bool)}
+	metrics := testutil.NewMetrics()
+	return NewIdempotentHandler(next, store, txUnitOfWork{store: store}, testutil.NopLogger{}, metrics), store, metrics
+}
+
+func TestIdempotentHandler_SkipsDuplicates(t *testing.T) {
+	next := &countingEventHandler{}
+	h, store, metrics := newIdempotentHandler(next)
+	evt := event.NewUserCreatedEvent("alice", "alice@example.com", "Alice")
+
+	require.NoError(t, h.Handle(context.Background(), evt))
+	require.NoError(t, h.Handle(context.Background(), evt))
+
+	assert.Equal(t, 1, next.calls)
+	assert.True(t, store.processed["user_notifications/"+evt.ID()])
+	assert.Equal(t, 1, metrics.Counter("event_duplicate_skipped"))
+	assert.Equal(t, "user_notifications", h.HandlerID())
+}
+
+func TestIdempotentHandler_DistinctEventsHandled(t *testing.T) {
+	next := &countingEventHandler{}
+	h, _, _ := newIdempotentHandler(next)
+
+	require.NoError(t, h.Handle(context.Background(), event.NewUserCreatedEvent("alice", "alice@example.com", "Alice")))
+	require.NoError(t, h.Handle(context.Background(), event.NewUserCreatedEvent("alice", "alice@example.com", "Alice")))
+
+	assert.Equal(t, 2, next.calls)
+}
+
+func TestIdempotentHandler_FailureAllowsRetry(t *testing.T) {
+	next := &countingEventHandler{failures: 1}
+	h, store, _ := newIdempotentHandler(next)
+	evt := event.NewUserCreatedEvent("alice", "alice@example.com", "Alice")
+
+	require.Error(t, h.Handle(context.Background(), evt))
+	assert.Empty(t, store.processed)
+
+	require.NoError(t, h.Handle(context.Background(), evt))
+	assert.Equal(t, 2, next.calls)
+	assert.Len(t, store.processed, 1)
+}
+
+func TestIdempotentHandler_PropagatesCause(t *testing.T) {
+	next := &countingEventHandler{}
+	h, _, _ := newIdempotentHandler(next)
+	evt := event.NewUserCreatedEvent("alice", "alice@example.com", "Alice")
+
+	require.NoError(t, h.Handle(context.Background(), evt))
+
+	correlationID, causationID := event.CauseFromContext(next.ctx)
+	assert.Equal(t, evt.ID(), correlationID)
+	assert.Equal(t, evt.ID(), causationID)
+}
@@ -2,15 +2,37 @@ package handler
 
 import (
 	"context"
-	"fmt"
-	"github.com/your-org/your-project/internal/domain/event"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/event"
 )
 
+// UserEventHandler 发送用户通知邮件，需要经 IdempotentHandler 包装以免重复投递时重复发信
 type UserEventHandler struct {
-	cache    port.Cache
-	emailSvc port.EmailService
-	logger   Logger
-	metrics  MetricsReporter
+	emailSvc output.EmailService
+	logger   output.Logger
+	metrics  output.MetricsReporter
+}
+
+func NewUserEventHandler(emailSvc output.EmailService, logger output.Logger, metrics output.MetricsReporter) *UserEventHandler {
+	return &UserEventHandler{
+		emailSvc: emailSvc,
+		logger:   logger,
+		metrics:  metrics,
+	}
+}
+
+// EventTypes 返回需要订阅的事件类型
+func (h *UserEventHandler) EventTypes() []string {
+	return []string{
+		event.UserCreated,
+		event.UserProfileUpdated,
+		event.PasswordChanged,
+	}
+}
+
+func (h *UserEventHandler) HandlerID() string {
+	return "user_notifications"
 }
 
 func (h *UserEventHandler) Handle(ctx context.Context, evt event.Event) error {
@@ -26,25 +48,14 @@ func (h *UserEventHandler) Handle(ctx context.Context, evt event.Event) error {
 	}
 }
 
+// handleUserCreated 欢迎邮件在邮箱验证和下游开通之后由注册流程 saga 发送
 func (h *UserEventHandler) handleUserCreated(ctx context.Context, evt *event.UserCreatedEvent) error {
-	// 1. 发送欢迎邮件
-	if err := h.emailSvc.SendWelcomeEmail(evt.Email, evt.Name); err != nil {
-		h.logger.Error("failed to send welcome email", "error", err)
-	}
-
-	// 2. 记录指标
 	h.metrics.IncrementCounter("user_created")
 	return nil
 }
 
+// handleProfileUpdated 缓存由 CacheInvalidationHandler 按标签失效
 func (h *UserEventHandler) handleProfileUpdated(ctx context.Context, evt *event.UserProfileUpdatedEvent) error {
-	// 1. 清除用户缓存
-	cacheKey := fmt.Sprintf("user:%s", evt.AggregateID())
-	if err := h.cache.Delete(ctx, cacheKey); err != nil {
-		h.logger.Error("failed to clear user cache", "error", err)
-	}
-
-	// 2. 记录指标
 	h.metrics.IncrementCounter("user_profile_updated")
 	return nil
 }
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/event"
+)
+
+// webhookPayload 推送给订阅方的请求体
+type webhookPayload struct {
+	ID          string      `json:"id"`
+	Type        string      `json:"type"`
+	AggregateID string      `json:"aggregate_id"`
+	OccurredAt  time.Time   `json:"occurred_at"`
+	Data        interface{} `json:"data"`
+}
+
+// WebhookHandler 为每个匹配的订阅生成待投递记录，由投递 worker 异步发送
+type WebhookHandler struct {
+	repo    output.WebhookRepository
+	logger  output.Logger
+	metrics output.MetricsReporter
+}
+
+func NewWebhookHandler(repo output.WebhookRepository, logger output.Logger, metrics output.MetricsReporter) *WebhookHandler {
+	return &WebhookHandler{
+		repo:    repo,
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+// EventTypes 返回需要订阅的事件类型
+func (h *WebhookHandler) EventTypes() []string {
+	return output.WebhookEventTypes
+}
+
+func (h *WebhookHandler) HandlerID() string {
+	return "webhook_fanout"
+}
+
+func (h *WebhookHandler) Handle(ctx context.Context, evt event.Event) error {
+	subs, err := h.repo.FindActiveSubscriptions(ctx, evt.Type())
+	if err != nil {
+		return err
+	}
+	if len(subs) == 0 {
+		return nil
+	}
+
+	// 未知类型的事件原样转发发布方的数据
+	var data interface{} = evt
+	if raw, ok := evt.(*event.RawEvent); ok {
+		data = raw.Payload
+	}
+
+	// 重复投递的事件 ID 相同，接收方可据此去重
+	eventID := evt.ID()
+	payload, err := json.Marshal(webhookPayload{
+		ID:          eventID,
+		Type:        evt.Type(),
+		AggregateID: evt.AggregateID(),
+		OccurredAt:  evt.OccurredAt(),
+		Data:        data,
+	})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	deliveries := make([]*output.WebhookDelivery, len(subs))
+	for i, sub := range subs {
+		deliveries[i] = &output.WebhookDelivery{
+			ID:             uuid.New().String(),
+			SubscriptionID: sub.ID,
+			EventID:        eventID,
+			EventType:      evt.Type(),
+			Payload:        payload,
+			Status:         output.WebhookDeliveryPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+		}
+	}
+
+	if err := h.repo.SaveDeliveries(ctx, deliveries); err != nil {
+		h.logger.Error("failed to enqueue webhook deliveries",
+			"event_type", evt.Type(),
+			"aggregate_id", evt.AggregateID(),
+			"error", err,
+		)
+		return err
+	}
+
+	h.metrics.IncrementCounter("webhook_delivery_enqueued", "event_type", evt.Type())
+	return nil
+}
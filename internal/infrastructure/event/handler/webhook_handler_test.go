@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/event"
+	"github.com/gohex/gohex/internal/testutil"
+)
+
+func TestWebhookHandler_FansOutToMatchingSubscriptions(t *testing.T) {
+	repo := testutil.NewWebhookRepository(
+		&output.WebhookSubscription{ID: "all", EventTypes: []string{output.WebhookAllEvents}, Active: true},
+		&output.WebhookSubscription{ID: "created", EventTypes: []string{event.UserCreated}, Active: true},
+		&output.WebhookSubscription{ID: "locked", EventTypes: []string{event.UserLocked}, Active: true},
+		&output.WebhookSubscription{ID: "disabled", EventTypes: []string{event.UserCreated}},
+	)
+	metrics := testutil.NewMetrics()
+	h := NewWebhookHandler(repo, testutil.NopLogger{}, metrics)
+	evt := event.NewUserCreatedEvent("alice", "alice@example.com", "Alice")
+
+	require.NoError(t, h.Handle(context.Background(), evt))
+
+	require.Len(t, repo.Deliveries, 2)
+	var subs []string
+	for _, d := range repo.Deliveries {
+		subs = append(subs, d.SubscriptionID)
+		assert.Equal(t, evt.ID(), d.EventID)
+		assert.Equal(t, output.WebhookDeliveryPending, d.Status)
+	}
+	assert.ElementsMatch(t, []string{"all", "created"}, subs)
+	assert.Equal(t, 1, metrics.Counter("webhook_delivery_enqueued"))
+
+	var payload map[string]interface{}
+	require.NoError(t, json.Unmarshal(repo.Deliveries[0].Payload, &payload))
+	assert.Equal(t, evt.ID(), payload["id"])
+	assert.Equal(t, event.UserCreated, payload["type"])
+	assert.Equal(t, "alice", payload["aggregate_id"])
+	assert.NotNil(t, payload["data"])
+}
+
+func TestWebhookHandler_NoSubscriptions(t *testing.T) {
+	repo := testutil.NewWebhookRepository(
+		&output.WebhookSubscription{ID: "locked", EventTypes: []string{event.UserLocked}, Active: true},
+	)
+	h := NewWebhookHandler(repo, testutil.NopLogger{}, testutil.NewMetrics())
+
+	require.NoError(t, h.Handle(context.Background(), event.NewUserCreatedEvent("alice", "alice@example.com", "Alice")))
+	assert.Empty(t, repo.Deliveries)
+}
+
+func TestWebhookHandler_ExcludesSecurityEvents(t *testing.T) {
+	h := NewWebhookHandler(testutil.NewWebhookRepository(), testutil.NopLogger{}, testutil.NewMetrics())
+
+	assert.Contains(t, h.EventTypes(), event.UserLocked)
+	assert.NotContains(t, h.EventTypes(), event.UserLoggedIn)
+	assert.NotContains(t, h.EventTypes(), event.PasswordChanged)
+}
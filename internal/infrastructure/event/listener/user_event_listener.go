@@ -2,16 +2,18 @@ package listener
 
 import (
 	"context"
-	"github.com/your-org/your-project/internal/domain/event"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/event"
+	"github.com/gohex/gohex/pkg/tracer"
 )
 
 type UserEventListener struct {
-	logger  Logger
-	metrics MetricsReporter
-	cache   Cache
+	logger  output.Logger
+	metrics output.MetricsReporter
+	cache   output.Cache
 }
 
-func NewUserEventListener(logger Logger, metrics MetricsReporter, cache Cache) *UserEventListener {
+func NewUserEventListener(logger output.Logger, metrics output.MetricsReporter, cache output.Cache) *UserEventListener {
 	return &UserEventListener{
 		logger:  logger,
 		metrics: metrics,
@@ -19,14 +21,14 @@ func NewUserEventListener(logger Logger, metrics MetricsReporter, cache Cache) *
 	}
 }
 
-func (l *UserEventListener) Handle(ctx context.Context, event event.Event) error {
+func (l *UserEventListener) Handle(ctx context.Context, evt event.Event) error {
 	span, ctx := tracer.StartSpan(ctx, "UserEventListener.Handle")
 	defer span.End()
 
 	timer := l.metrics.StartTimer("event_handler_duration")
 	defer timer.Stop()
 
-	switch e := event.(type) {
+	switch e := evt.(type) {
 	case *event.UserCreatedEvent:
 		return l.handleUserCreated(ctx, e)
 	case *event.UserProfileUpdatedEvent:
@@ -36,7 +38,7 @@ func (l *UserEventListener) Handle(ctx context.Context, event event.Event) error
 	case *event.UserLoggedInEvent:
 		return l.handleUserLoggedIn(ctx, e)
 	default:
-		l.logger.Debug("ignoring unknown event type", "type", event.Type())
+		l.logger.Debug("ignoring unknown event type", "type", evt.Type())
 		return nil
 	}
 }
@@ -47,12 +49,8 @@ func (l *UserEventListener) handleUserCreated(ctx context.Context, e *event.User
 	return nil
 }
 
+// handleProfileUpdated 缓存由 CacheInvalidationHandler 按标签失效
 func (l *UserEventListener) handleProfileUpdated(ctx context.Context, e *event.UserProfileUpdatedEvent) error {
-	// 清除用户缓存
-	cacheKey := "user:" + e.AggregateID()
-	if err := l.cache.Delete(ctx, cacheKey); err != nil {
-		l.logger.Error("failed to clear user cache", "error", err)
-	}
 	return nil
 }
 
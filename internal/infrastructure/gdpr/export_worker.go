@@ -0,0 +1,111 @@
+package gdpr
+
+import (
+	"context"
+	"time"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/application/service"
+	"github.com/gohex/gohex/internal/infrastructure/config"
+)
+
+// ExportWorker 处理待生成的数据导出请求并清理过期归档
+type ExportWorker struct {
+	exportRepo output.DataExportRepository
+	assembler  *service.DataExportService
+	config     config.GDPRConfig
+	logger     output.Logger
+	metrics    output.MetricsReporter
+}
+
+func NewExportWorker(
+	exportRepo output.DataExportRepository,
+	assembler *service.DataExportService,
+	cfg config.GDPRConfig,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *ExportWorker {
+	return &ExportWorker{
+		exportRepo: exportRepo,
+		assembler:  assembler,
+		config:     cfg,
+		logger:     logger,
+		metrics:    metrics,
+	}
+}
+
+// Start 启动处理循环，直到 ctx 取消
+func (w *ExportWorker) Start(ctx context.Context) {
+	interval := w.config.PollInterval
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			w.drain(ctx)
+			w.cleanup(ctx)
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// drain 依次处理所有待处理请求
+func (w *ExportWorker) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		export, err := w.exportRepo.ClaimPending(ctx)
+		if err != nil {
+			w.logger.Error("failed to claim data export", "error", err)
+			return
+		}
+		if export == nil {
+			return
+		}
+		w.process(ctx, export)
+	}
+}
+
+func (w *ExportWorker) process(ctx context.Context, export *output.DataExport) {
+	now := time.Now()
+	export.CompletedAt = &now
+
+	archive, err := w.assembler.Assemble(ctx, export.UserID)
+	if err != nil {
+		w.logger.Error("failed to assemble data export",
+			"export_id", export.ID,
+			"user_id", export.UserID,
+			"error", err,
+		)
+		w.metrics.IncrementCounter("data_export_failure")
+		export.Status = output.DataExportFailed
+		export.Error = err.Error()
+	} else {
+		expiresAt := now.Add(w.config.ExportTTL)
+		export.Status = output.DataExportCompleted
+		export.Archive = archive
+		export.ExpiresAt = &expiresAt
+		w.metrics.IncrementCounter("data_export_success")
+	}
+
+	if err := w.exportRepo.Update(ctx, export); err != nil {
+		w.logger.Error("failed to update data export", "export_id", export.ID, "error", err)
+	}
+}
+
+func (w *ExportWorker) cleanup(ctx context.Context) {
+	n, err := w.exportRepo.DeleteExpired(ctx, time.Now())
+	if err != nil {
+		w.logger.Error("failed to delete expired data exports", "error", err)
+		return
+	}
+	if n > 0 {
+		w.logger.Info("expired data exports deleted", "count", n)
+	}
+}
@@ -0,0 +1,246 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"time"
+
+	"github.com/gohex/gohex/internal/application/port/input/command"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/infrastructure/config"
+)
+
+// Worker 领取并执行异步命令任务，回收崩溃实例遗留的任务并清理过期任务
+type Worker struct {
+	queue      output.JobQueue
+	commandBus command.Bus
+	config     config.JobConfig
+	logger     output.Logger
+	metrics    output.MetricsReporter
+}
+
+func NewWorker(
+	queue output.JobQueue,
+	commandBus command.Bus,
+	cfg config.JobConfig,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *Worker {
+	return &Worker{
+		queue:      queue,
+		commandBus: commandBus,
+		config:     cfg,
+		logger:     logger,
+		metrics:    metrics,
+	}
+}
+
+// Start 启动 worker 和维护循环，直到 ctx 取消
+func (w *Worker) Start(ctx context.Context) {
+	workers := w.config.Workers
+	if workers <= 0 {
+		workers = 4
+	}
+
+	for i := 0; i < workers; i++ {
+		go w.loop(ctx)
+	}
+	go w.maintain(ctx)
+}
+
+func (w *Worker) loop(ctx context.Context) {
+	interval := w.config.PollInterval
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		w.drain(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// maintain 将心跳超时的任务重新入队，并删除超过保留期的任务
+func (w *Worker) maintain(ctx context.Context) {
+	ticker := time.NewTicker(w.leaseTimeout())
+	defer ticker.Stop()
+
+	for {
+		w.requeueStale(ctx)
+		w.cleanup(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// drain 依次执行所有待执行任务
+func (w *Worker) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		job, err := w.queue.Claim(ctx)
+		if err != nil {
+			w.logger.Error("failed to claim job", "error", err)
+			return
+		}
+		if job == nil {
+			return
+		}
+		w.run(ctx, job)
+	}
+}
+
+func (w *Worker) run(ctx context.Context, job *output.Job) {
+	timer := w.metrics.StartTimer("job_duration", "command_type", job.CommandType)
+	defer timer.Stop()
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		progress  int32
+		cancelled atomic.Bool
+	)
+	jobCtx = command.WithProgress(jobCtx, func(percent int) {
+		atomic.StoreInt32(&progress, int32(percent))
+	})
+
+	done := make(chan struct{})
+	go w.heartbeat(ctx, job.ID, &progress, &cancelled, cancel, done)
+
+	result, err := w.commandBus.RunJob(jobCtx, job)
+	close(done)
+
+	switch {
+	case cancelled.Load():
+		if err := w.queue.MarkCancelled(ctx, job.ID); err != nil {
+			w.logger.Error("failed to mark job cancelled", "job_id", job.ID, "error", err)
+		}
+		w.logger.Info("job cancelled", "job_id", job.ID, "command_type", job.CommandType)
+	case ctx.Err() != nil:
+		// 实例正在退出，任务保持运行中状态，租约过期后由其他实例重新执行
+		w.logger.Warn("job interrupted by shutdown", "job_id", job.ID, "command_type", job.CommandType)
+	case err != nil:
+		w.fail(ctx, job, err)
+	default:
+		data, err := json.Marshal(result)
+		if err != nil {
+			w.fail(ctx, job, err)
+			return
+		}
+		if err := w.queue.Complete(ctx, job.ID, data); err != nil {
+			w.logger.Error("failed to complete job", "job_id", job.ID, "error", err)
+			return
+		}
+		w.metrics.IncrementCounter("job_success", "command_type", job.CommandType)
+	}
+}
+
+// heartbeat 定期续约并上报进度，发现取消请求时中断任务
+func (w *Worker) heartbeat(
+	ctx context.Context,
+	jobID string,
+	progress *int32,
+	cancelled *atomic.Bool,
+	cancel context.CancelFunc,
+	done <-chan struct{},
+) {
+	interval := w.config.HeartbeatInterval
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-done:
+			return
+		case <-ctx.Done():
+			return
+		}
+
+		cancelRequested, err := w.queue.Heartbeat(ctx, jobID, int(atomic.LoadInt32(progress)))
+		if err != nil {
+			w.logger.Error("failed to heartbeat job", "job_id", jobID, "error", err)
+			continue
+		}
+		if cancelRequested {
+			cancelled.Store(true)
+			cancel()
+			return
+		}
+	}
+}
+
+func (w *Worker) fail(ctx context.Context, job *output.Job, cause error) {
+	w.logger.Error("job failed",
+		"job_id", job.ID,
+		"command_type", job.CommandType,
+		"error", cause,
+	)
+	w.metrics.IncrementCounter("job_failure", "command_type", job.CommandType)
+
+	if err := w.queue.Fail(ctx, job.ID, cause.Error()); err != nil {
+		w.logger.Error("failed to mark job failed", "job_id", job.ID, "error", err)
+	}
+}
+
+func (w *Worker) requeueStale(ctx context.Context) {
+	maxAttempts := w.config.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 3
+	}
+
+	requeued, deadLettered, err := w.queue.RequeueStale(ctx, time.Now().Add(-w.leaseTimeout()), maxAttempts)
+	if err != nil {
+		w.logger.Error("failed to requeue stale jobs", "error", err)
+		return
+	}
+	if requeued > 0 {
+		w.logger.Warn("stale jobs requeued", "count", requeued)
+		w.metrics.IncrementCounter("job_requeued")
+	}
+	if deadLettered > 0 {
+		w.logger.Error("stale jobs dead-lettered", "count", deadLettered, "max_attempts", maxAttempts)
+		w.metrics.IncrementCounter("job_dead_lettered")
+	}
+}
+
+func (w *Worker) cleanup(ctx context.Context) {
+	if w.config.Retention <= 0 {
+		return
+	}
+
+	n, err := w.queue.DeleteFinished(ctx, time.Now().Add(-w.config.Retention))
+	if err != nil {
+		w.logger.Error("failed to delete finished jobs", "error", err)
+		return
+	}
+	if n > 0 {
+		w.logger.Info("finished jobs deleted", "count", n)
+	}
+}
+
+// leaseTimeout 至少为三个心跳周期，避免正常运行的任务被误判为超时
+func (w *Worker) leaseTimeout() time.Duration {
+	heartbeat := w.config.HeartbeatInterval
+	if heartbeat <= 0 {
+		heartbeat = 10 * time.Second
+	}
+	if w.config.LeaseTimeout < 3*heartbeat {
+		return 3 * heartbeat
+	}
+	return w.config.LeaseTimeout
+}
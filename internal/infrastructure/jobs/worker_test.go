@@ -0,0 +1,96 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/port/input/command"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/infrastructure/config"
+	"github.com/gohex/gohex/internal/testutil"
+)
+
+// jobQueue 记录心跳进度和任务结果
+type jobQueue struct {
+	output.JobQueue
+	mu           sync.Mutex
+	progress     []int
+	completed    []string
+	maxAttempts  int
+	deadLettered int64
+}
+
+func (q *jobQueue) Heartbeat(ctx context.Context, id string, progress int) (bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.progress = append(q.progress, progress)
+	return false, nil
+}
+
+func (q *jobQueue) Complete(ctx context.Context, id string, result []byte) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.completed = append(q.completed, id)
+	return nil
+}
+
+func (q *jobQueue) RequeueStale(ctx context.Context, before time.Time, maxAttempts int) (int64, int64, error) {
+	q.maxAttempts = maxAttempts
+	return 0, q.deadLettered, nil
+}
+
+// progressBus 上报一半进度后等待若干心跳周期
+type progressBus struct {
+	command.Bus
+	wait time.Duration
+}
+
+func (b *progressBus) RunJob(ctx context.Context, job *output.Job) (interface{}, error) {
+	command.ReportProgress(ctx, 50)
+	time.Sleep(b.wait)
+	return nil, nil
+}
+
+func TestWorker_RunReportsProgress(t *testing.T) {
+	queue := &jobQueue{}
+	cfg := config.JobConfig{HeartbeatInterval: 5 * time.Millisecond}
+	w := NewWorker(queue, &progressBus{wait: 30 * time.Millisecond}, cfg, testutil.NopLogger{}, testutil.NewMetrics())
+
+	w.run(context.Background(), &output.Job{ID: "j1", CommandType: "command.BulkAssignRoleCommand"})
+
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	require.NotEmpty(t, queue.progress)
+	assert.Equal(t, 50, queue.progress[len(queue.progress)-1])
+	assert.Equal(t, []string{"j1"}, queue.completed)
+}
+
+func TestWorker_RequeueStaleDeadLetters(t *testing.T) {
+	tests := []struct {
+		name            string
+		maxAttempts     int
+		wantMaxAttempts int
+	}{
+		{name: "default cap", wantMaxAttempts: 3},
+		{name: "configured cap", maxAttempts: 5, wantMaxAttempts: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queue := &jobQueue{deadLettered: 2}
+			metrics := testutil.NewMetrics()
+			w := NewWorker(queue, nil, config.JobConfig{MaxAttempts: tt.maxAttempts}, testutil.NopLogger{}, metrics)
+
+			w.requeueStale(context.Background())
+
+			assert.Equal(t, tt.wantMaxAttempts, queue.maxAttempts)
+			assert.Equal(t, 1, metrics.Counter("job_dead_lettered"))
+			assert.Equal(t, 0, metrics.Counter("job_requeued"))
+		})
+	}
+}
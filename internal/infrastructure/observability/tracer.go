@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 	"go.opentelemetry.io/otel/exporters/jaeger"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
@@ -28,6 +29,11 @@ func NewTracer(serviceName, endpoint string) (*Tracer, error) {
 	)
 
 	otel.SetTracerProvider(provider)
+	// 跨进程传递追踪上下文，如 Kafka 消息信封
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
 
 	return &Tracer{
 		provider: provider,
@@ -36,7 +42,8 @@ func NewTracer(serviceName, endpoint string) (*Tracer, error) {
 }
 
 func (t *Tracer) StartSpan(ctx context.Context, name string) (trace.Span, context.Context) {
-	return t.tracer.Start(ctx, name)
+	ctx, span := t.tracer.Start(ctx, name)
+	return span, ctx
 }
 
 func (t *Tracer) Close() error {
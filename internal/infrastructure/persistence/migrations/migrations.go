@@ -1,24 +1,21 @@
 package migrations
 
 import (
-	"context"
 	"database/sql"
-	"embed"
 	"fmt"
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/mysql"
 	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/gohex/gohex/internal/application/port/output"
+	sqlfiles "github.com/gohex/gohex/migrations"
 )
 
-//go:embed *.sql
-var migrationFiles embed.FS
-
 type Migrator struct {
 	db     *sql.DB
-	logger Logger
+	logger output.Logger
 }
 
-func NewMigrator(db *sql.DB, logger Logger) *Migrator {
+func NewMigrator(db *sql.DB, logger output.Logger) *Migrator {
 	return &Migrator{
 		db:     db,
 		logger: logger,
@@ -31,12 +28,12 @@ func (m *Migrator) Up() error {
 		return fmt.Errorf("failed to create migration driver: %w", err)
 	}
 
-	source, err := iofs.New(migrationFiles, ".")
+	source, err := iofs.New(sqlfiles.FS, ".")
 	if err != nil {
 		return fmt.Errorf("failed to create migration source: %w", err)
 	}
 
-	migrate, err := migrate.NewWithInstance(
+	migrator, err := migrate.NewWithInstance(
 		"iofs",
 		source,
 		"mysql",
@@ -46,7 +43,7 @@ func (m *Migrator) Up() error {
 		return fmt.Errorf("failed to create migrator: %w", err)
 	}
 
-	if err := migrate.Up(); err != nil && err != migrate.ErrNoChange {
+	if err := migrator.Up(); err != nil && err != migrate.ErrNoChange {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 
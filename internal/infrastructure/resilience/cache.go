@@ -0,0 +1,179 @@
+package resilience
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+)
+
+// breakerCache 缓存故障时降级为未命中，调用方回源到仓储；
+// 删除类操作仍返回错误，避免失效请求被静默丢弃导致读到旧数据。
+// 只用于查询缓存，令牌吊销列表等不能丢失写入的数据不经过这一层
+type breakerCache struct {
+	next    output.Cache
+	breaker *CircuitBreaker
+	logger  output.Logger
+}
+
+func NewCache(next output.Cache, breaker *CircuitBreaker, logger output.Logger) output.TaggedCache {
+	return &breakerCache{
+		next:    next,
+		breaker: breaker,
+		logger:  logger,
+	}
+}
+
+func (c *breakerCache) Get(ctx context.Context, key string) (interface{}, error) {
+	var value interface{}
+	err := c.breaker.Execute(func() error {
+		var err error
+		value, err = c.next.Get(ctx, key)
+		return err
+	})
+	if err != nil {
+		c.logger.Warn("cache unavailable, falling back to source", "key", key, "error", err)
+		return nil, nil
+	}
+	return value, nil
+}
+
+func (c *breakerCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	err := c.breaker.Execute(func() error {
+		return c.next.Set(ctx, key, value, ttl)
+	})
+	if err != nil {
+		c.logger.Warn("cache unavailable, skipping set", "key", key, "error", err)
+	}
+	return nil
+}
+
+func (c *breakerCache) Delete(ctx context.Context, key string) error {
+	return c.breaker.Execute(func() error {
+		return c.next.Delete(ctx, key)
+	})
+}
+
+func (c *breakerCache) Increment(ctx context.Context, key string, value int64) (int64, error) {
+	var n int64
+	err := c.breaker.Execute(func() error {
+		var err error
+		n, err = c.next.Increment(ctx, key, value)
+		return err
+	})
+	return n, err
+}
+
+func (c *breakerCache) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return c.breaker.Execute(func() error {
+		return c.next.Expire(ctx, key, ttl)
+	})
+}
+
+func (c *breakerCache) GetMulti(ctx context.Context, keys []string) (map[string]interface{}, error) {
+	var values map[string]interface{}
+	err := c.breaker.Execute(func() error {
+		var err error
+		values, err = c.next.GetMulti(ctx, keys)
+		return err
+	})
+	if err != nil {
+		c.logger.Warn("cache unavailable, falling back to source", "keys", len(keys), "error", err)
+		return map[string]interface{}{}, nil
+	}
+	return values, nil
+}
+
+func (c *breakerCache) SetMulti(ctx context.Context, items map[string]interface{}, ttl time.Duration) error {
+	err := c.breaker.Execute(func() error {
+		return c.next.SetMulti(ctx, items, ttl)
+	})
+	if err != nil {
+		c.logger.Warn("cache unavailable, skipping set", "keys", len(items), "error", err)
+	}
+	return nil
+}
+
+func (c *breakerCache) DeleteMulti(ctx context.Context, keys []string) error {
+	return c.breaker.Execute(func() error {
+		return c.next.DeleteMulti(ctx, keys)
+	})
+}
+
+func (c *breakerCache) Clear(ctx context.Context) error {
+	return c.breaker.Execute(func() error {
+		return c.next.Clear(ctx)
+	})
+}
+
+func (c *breakerCache) Keys(ctx context.Context, pattern string) ([]string, error) {
+	var keys []string
+	err := c.breaker.Execute(func() error {
+		var err error
+		keys, err = c.next.Keys(ctx, pattern)
+		return err
+	})
+	return keys, err
+}
+
+// SetWithTags 下层缓存不支持标签时退化为普通写入
+func (c *breakerCache) SetWithTags(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) error {
+	tc, ok := c.next.(output.TaggedCache)
+	if !ok {
+		return c.Set(ctx, key, value, ttl)
+	}
+
+	err := c.breaker.Execute(func() error {
+		return tc.SetWithTags(ctx, key, value, ttl, tags)
+	})
+	if err != nil {
+		c.logger.Warn("cache unavailable, skipping set", "key", key, "error", err)
+	}
+	return nil
+}
+
+func (c *breakerCache) InvalidateTags(ctx context.Context, tags ...string) error {
+	tc, ok := c.next.(output.TaggedCache)
+	if !ok {
+		return fmt.Errorf("cache %T does not support tags", c.next)
+	}
+	return c.breaker.Execute(func() error {
+		return tc.InvalidateTags(ctx, tags...)
+	})
+}
+
+// GetRaw 缓存故障时降级为未命中
+func (c *breakerCache) GetRaw(ctx context.Context, key string) ([]byte, error) {
+	rc, ok := c.next.(output.RawCache)
+	if !ok {
+		return nil, fmt.Errorf("cache %T does not support raw access", c.next)
+	}
+
+	var data []byte
+	err := c.breaker.Execute(func() error {
+		var err error
+		data, err = rc.GetRaw(ctx, key)
+		return err
+	})
+	if err != nil {
+		c.logger.Warn("cache unavailable, falling back to source", "key", key, "error", err)
+		return nil, nil
+	}
+	return data, nil
+}
+
+func (c *breakerCache) SetRaw(ctx context.Context, key string, data []byte, ttl time.Duration, tags []string) error {
+	rc, ok := c.next.(output.RawCache)
+	if !ok {
+		return fmt.Errorf("cache %T does not support raw access", c.next)
+	}
+
+	err := c.breaker.Execute(func() error {
+		return rc.SetRaw(ctx, key, data, ttl, tags)
+	})
+	if err != nil {
+		c.logger.Warn("cache unavailable, skipping set", "key", key, "error", err)
+	}
+	return nil
+}
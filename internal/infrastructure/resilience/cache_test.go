@@ -0,0 +1,56 @@
+package resilience
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/infrastructure/config"
+	"github.com/gohex/gohex/internal/testutil"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+// failingCache 模拟 Redis 不可用
+type failingCache struct {
+	output.Cache
+	calls int
+}
+
+func (c *failingCache) Get(ctx context.Context, key string) (interface{}, error) {
+	c.calls++
+	return nil, errUnavailable
+}
+
+func (c *failingCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	c.calls++
+	return errUnavailable
+}
+
+func (c *failingCache) Delete(ctx context.Context, key string) error {
+	c.calls++
+	return errUnavailable
+}
+
+func TestCache_DegradesReadsButNotInvalidation(t *testing.T) {
+	ctx := context.Background()
+	next := &failingCache{}
+	breaker := newBreaker(config.CircuitBreakerConfig{FailureThreshold: 2, OpenTimeout: time.Hour})
+	cache := NewCache(next, breaker, testutil.NopLogger{})
+
+	value, err := cache.Get(ctx, "user:alice")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+	assert.NoError(t, cache.Set(ctx, "user:alice", "alice", time.Minute))
+	require.Equal(t, StateOpen, breaker.State())
+
+	// 熔断后不再访问依赖，失效请求仍返回错误
+	assert.ErrorIs(t, cache.Delete(ctx, "user:alice"), errors.ErrCircuitOpen)
+	value, err = cache.Get(ctx, "user:alice")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+	assert.Equal(t, 2, next.calls)
+}
@@ -0,0 +1,214 @@
+package resilience
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/infrastructure/config"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+// State 熔断器状态
+type State int
+
+const (
+	StateClosed State = iota
+	StateOpen
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half_open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitBreaker 连续失败达到阈值后打开，等待 OpenTimeout 后放行少量探测请求，探测成功后关闭
+type CircuitBreaker struct {
+	name    string
+	config  config.CircuitBreakerConfig
+	logger  output.Logger
+	metrics output.MetricsReporter
+
+	mu        sync.Mutex
+	state     State
+	failures  int
+	successes int
+	probes    int
+	openedAt  time.Time
+	// generation 每次状态切换递增，忽略切换前发出的请求结果
+	generation uint64
+}
+
+func NewCircuitBreaker(name string, cfg config.CircuitBreakerConfig, logger output.Logger, metrics output.MetricsReporter) *CircuitBreaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 5
+	}
+	if cfg.SuccessThreshold <= 0 {
+		cfg.SuccessThreshold = 1
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = 30 * time.Second
+	}
+	if cfg.HalfOpenMaxRequests <= 0 {
+		cfg.HalfOpenMaxRequests = 1
+	}
+
+	b := &CircuitBreaker{
+		name:    name,
+		config:  cfg,
+		logger:  logger,
+		metrics: metrics,
+	}
+	b.metrics.Gauge("circuit_breaker_state", float64(StateClosed), "name", name)
+	return b
+}
+
+// Name 返回熔断器保护的依赖名
+func (b *CircuitBreaker) Name() string {
+	return b.name
+}
+
+// Execute 熔断打开时直接返回 ErrCircuitOpen，否则执行 fn 并记录结果
+func (b *CircuitBreaker) Execute(fn func() error) error {
+	generation, err := b.allow()
+	if err != nil {
+		return err
+	}
+
+	err = fn()
+	b.record(generation, err)
+	return err
+}
+
+// State 返回当前状态，打开超时后视为半开
+func (b *CircuitBreaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen && time.Since(b.openedAt) >= b.config.OpenTimeout {
+		return StateHalfOpen
+	}
+	return b.state
+}
+
+// Snapshot 熔断器当前状态，用于健康检查
+type Snapshot struct {
+	State    string     `json:"state"`
+	Failures int        `json:"failures"`
+	OpenedAt *time.Time `json:"opened_at,omitempty"`
+}
+
+func (b *CircuitBreaker) Snapshot() Snapshot {
+	state := b.State()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	snapshot := Snapshot{
+		State:    state.String(),
+		Failures: b.failures,
+	}
+	if state != StateClosed {
+		openedAt := b.openedAt
+		snapshot.OpenedAt = &openedAt
+	}
+	return snapshot
+}
+
+func (b *CircuitBreaker) allow() (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if time.Since(b.openedAt) < b.config.OpenTimeout {
+			b.metrics.IncrementCounter("circuit_breaker_rejected", "name", b.name)
+			return 0, errors.ErrCircuitOpen
+		}
+		b.transition(StateHalfOpen)
+	case StateHalfOpen:
+		if b.probes >= b.config.HalfOpenMaxRequests {
+			b.metrics.IncrementCounter("circuit_breaker_rejected", "name", b.name)
+			return 0, errors.ErrCircuitOpen
+		}
+	}
+
+	if b.state == StateHalfOpen {
+		b.probes++
+	}
+	return b.generation, nil
+}
+
+func (b *CircuitBreaker) record(generation uint64, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if generation != b.generation {
+		return
+	}
+
+	failed := isFailure(err)
+	switch b.state {
+	case StateClosed:
+		if !failed {
+			b.failures = 0
+			return
+		}
+		b.failures++
+		if b.failures >= b.config.FailureThreshold {
+			b.transition(StateOpen)
+		}
+	case StateHalfOpen:
+		b.probes--
+		if failed {
+			b.transition(StateOpen)
+			return
+		}
+		b.successes++
+		if b.successes >= b.config.SuccessThreshold {
+			b.transition(StateClosed)
+		}
+	}
+}
+
+// transition 切换状态并重置计数，调用方需持有锁
+func (b *CircuitBreaker) transition(to State) {
+	from := b.state
+	b.state = to
+	b.generation++
+	b.successes = 0
+	b.probes = 0
+
+	switch to {
+	case StateOpen:
+		b.openedAt = time.Now()
+		b.logger.Warn("circuit breaker opened", "name", b.name, "from", from.String(), "failures", b.failures)
+	case StateHalfOpen:
+		b.logger.Info("circuit breaker half-open", "name", b.name)
+	case StateClosed:
+		b.failures = 0
+		b.logger.Info("circuit breaker closed", "name", b.name, "from", from.String())
+	}
+
+	b.metrics.Gauge("circuit_breaker_state", float64(to), "name", b.name)
+	b.metrics.IncrementCounter("circuit_breaker_transition", "name", b.name, "from", from.String(), "to", to.String())
+}
+
+// isFailure 业务错误和调用方取消不代表依赖故障，不计入失败
+func isFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	return !errors.IsAppError(err)
+}
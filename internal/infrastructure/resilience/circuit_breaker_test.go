@@ -0,0 +1,152 @@
+package resilience
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/infrastructure/config"
+	"github.com/gohex/gohex/internal/testutil"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+var errUnavailable = stderrors.New("connection refused")
+
+func newBreaker(cfg config.CircuitBreakerConfig) *CircuitBreaker {
+	return NewCircuitBreaker("redis", cfg, testutil.NopLogger{}, testutil.NewMetrics())
+}
+
+func fail(err error) func() error { return func() error { return err } }
+
+func TestCircuitBreaker_CountsOnlyDependencyFailures(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantState State
+	}{
+		{name: "dependency errors open the breaker", err: errUnavailable, wantState: StateOpen},
+		{name: "wrapped sentinel counts as failure", err: fmt.Errorf("%w: %v", errors.ErrTokenRevocationUnavailable, errUnavailable), wantState: StateOpen},
+		{name: "business errors are ignored", err: errors.ErrUserNotFound, wantState: StateClosed},
+		{name: "caller cancellation is ignored", err: fmt.Errorf("get: %w", context.Canceled), wantState: StateClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBreaker(config.CircuitBreakerConfig{FailureThreshold: 3, OpenTimeout: time.Hour})
+			for i := 0; i < 3; i++ {
+				assert.ErrorIs(t, b.Execute(fail(tt.err)), tt.err)
+			}
+			assert.Equal(t, tt.wantState, b.State())
+		})
+	}
+}
+
+func TestCircuitBreaker_SuccessResetsFailureCount(t *testing.T) {
+	b := newBreaker(config.CircuitBreakerConfig{FailureThreshold: 2, OpenTimeout: time.Hour})
+
+	_ = b.Execute(fail(errUnavailable))
+	require.NoError(t, b.Execute(fail(nil)))
+	_ = b.Execute(fail(errUnavailable))
+
+	assert.Equal(t, StateClosed, b.State())
+}
+
+func TestCircuitBreaker_RejectsWhileOpen(t *testing.T) {
+	b := newBreaker(config.CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: time.Hour})
+	_ = b.Execute(fail(errUnavailable))
+
+	called := false
+	err := b.Execute(func() error {
+		called = true
+		return nil
+	})
+
+	assert.ErrorIs(t, err, errors.ErrCircuitOpen)
+	assert.False(t, called)
+	snapshot := b.Snapshot()
+	assert.Equal(t, "open", snapshot.State)
+	assert.NotNil(t, snapshot.OpenedAt)
+}
+
+func TestCircuitBreaker_HalfOpenProbe(t *testing.T) {
+	tests := []struct {
+		name      string
+		probeErr  error
+		wantState State
+	}{
+		{name: "successful probe closes", wantState: StateClosed},
+		{name: "failed probe reopens", probeErr: errUnavailable, wantState: StateOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBreaker(config.CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: 10 * time.Millisecond})
+			_ = b.Execute(fail(errUnavailable))
+			time.Sleep(20 * time.Millisecond)
+			require.Equal(t, StateHalfOpen, b.State())
+
+			probing, release := make(chan struct{}), make(chan struct{})
+			done := make(chan error, 1)
+			go func() {
+				done <- b.Execute(func() error {
+					close(probing)
+					<-release
+					return tt.probeErr
+				})
+			}()
+			<-probing
+
+			// 半开期间只放行 HalfOpenMaxRequests 个探测请求
+			assert.ErrorIs(t, b.Execute(fail(nil)), errors.ErrCircuitOpen)
+
+			close(release)
+			assert.ErrorIs(t, <-done, tt.probeErr)
+			assert.Equal(t, tt.wantState, b.State())
+		})
+	}
+}
+
+func TestCircuitBreaker_IgnoresResultsFromPreviousState(t *testing.T) {
+	b := newBreaker(config.CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: time.Hour})
+
+	slow, release := make(chan struct{}), make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = b.Execute(func() error {
+			close(slow)
+			<-release
+			return nil
+		})
+	}()
+	<-slow
+
+	_ = b.Execute(fail(errUnavailable))
+	close(release)
+	<-done
+
+	// 打开前发出的请求成功不应关闭熔断器
+	assert.Equal(t, StateOpen, b.State())
+}
+
+func TestRegistry_Degraded(t *testing.T) {
+	r := NewRegistry(config.ResilienceConfig{}, testutil.NopLogger{}, testutil.NewMetrics())
+	redis := r.Get(BreakerRedis)
+	assert.Same(t, redis, r.Get(BreakerRedis))
+	r.Get(BreakerSMTP)
+	assert.False(t, r.Degraded())
+
+	for i := 0; i < 5; i++ {
+		_ = redis.Execute(fail(errUnavailable))
+	}
+
+	assert.True(t, r.Degraded())
+	snapshot := r.Snapshot()
+	assert.Equal(t, "open", snapshot[BreakerRedis].State)
+	assert.Equal(t, "closed", snapshot[BreakerSMTP].State)
+}
@@ -0,0 +1,64 @@
+package resilience
+
+import "github.com/gohex/gohex/internal/application/port/output"
+
+// breakerEmailService SMTP 故障时快速失败，不再让请求等待连接超时
+type breakerEmailService struct {
+	next    output.EmailService
+	breaker *CircuitBreaker
+}
+
+func NewEmailService(next output.EmailService, breaker *CircuitBreaker) output.EmailService {
+	return &breakerEmailService{
+		next:    next,
+		breaker: breaker,
+	}
+}
+
+func (s *breakerEmailService) SendWelcomeEmail(email string, name string) error {
+	return s.breaker.Execute(func() error {
+		return s.next.SendWelcomeEmail(email, name)
+	})
+}
+
+func (s *breakerEmailService) SendPasswordResetEmail(email string, resetToken string) error {
+	return s.breaker.Execute(func() error {
+		return s.next.SendPasswordResetEmail(email, resetToken)
+	})
+}
+
+func (s *breakerEmailService) SendPasswordChangedNotification(email string) error {
+	return s.breaker.Execute(func() error {
+		return s.next.SendPasswordChangedNotification(email)
+	})
+}
+
+func (s *breakerEmailService) SendVerificationEmail(email string, verificationCode string) error {
+	return s.breaker.Execute(func() error {
+		return s.next.SendVerificationEmail(email, verificationCode)
+	})
+}
+
+func (s *breakerEmailService) SendLoginNotification(email string, ip string, userAgent string) error {
+	return s.breaker.Execute(func() error {
+		return s.next.SendLoginNotification(email, ip, userAgent)
+	})
+}
+
+func (s *breakerEmailService) SendAccountLockedNotification(email string, reason string) error {
+	return s.breaker.Execute(func() error {
+		return s.next.SendAccountLockedNotification(email, reason)
+	})
+}
+
+func (s *breakerEmailService) SendEmailChangeConfirmation(newEmail string, confirmToken string) error {
+	return s.breaker.Execute(func() error {
+		return s.next.SendEmailChangeConfirmation(newEmail, confirmToken)
+	})
+}
+
+func (s *breakerEmailService) SendEmailChangeNotice(oldEmail string, newEmail string, cancelToken string) error {
+	return s.breaker.Execute(func() error {
+		return s.next.SendEmailChangeNotice(oldEmail, newEmail, cancelToken)
+	})
+}
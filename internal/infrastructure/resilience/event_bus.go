@@ -0,0 +1,46 @@
+package resilience
+
+import (
+	"context"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/event"
+)
+
+// breakerEventBus 消息中间件故障时发布快速失败，订阅、启动和关闭直接透传
+type breakerEventBus struct {
+	next    output.EventBus
+	breaker *CircuitBreaker
+}
+
+func NewEventBus(next output.EventBus, breaker *CircuitBreaker) output.EventBus {
+	return &breakerEventBus{
+		next:    next,
+		breaker: breaker,
+	}
+}
+
+func (b *breakerEventBus) Publish(ctx context.Context, events ...event.Event) error {
+	return b.breaker.Execute(func() error {
+		return b.next.Publish(ctx, events...)
+	})
+}
+
+func (b *breakerEventBus) Subscribe(eventType string, handler output.EventHandler) {
+	b.next.Subscribe(eventType, handler)
+}
+
+func (b *breakerEventBus) Unsubscribe(eventType string, handler output.EventHandler) {
+	b.next.Unsubscribe(eventType, handler)
+}
+
+func (b *breakerEventBus) Close() error {
+	return b.next.Close()
+}
+
+func (b *breakerEventBus) Start(ctx context.Context) error {
+	if s, ok := b.next.(interface{ Start(context.Context) error }); ok {
+		return s.Start(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package resilience
+
+import (
+	"sync"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/infrastructure/config"
+)
+
+// 受熔断器保护的依赖名，与 resilience.circuit_breakers 配置的键一致
+const (
+	BreakerRedis    = "redis"
+	BreakerSMTP     = "smtp"
+	BreakerKafka    = "kafka"
+	BreakerNATS     = "nats"
+	BreakerRabbitMQ = "rabbitmq"
+	BreakerToken    = "token"
+)
+
+// Registry 按依赖名创建并保存熔断器，供装饰器共享和健康检查读取
+type Registry struct {
+	config  config.ResilienceConfig
+	logger  output.Logger
+	metrics output.MetricsReporter
+
+	mu       sync.Mutex
+	breakers map[string]*CircuitBreaker
+}
+
+func NewRegistry(cfg config.ResilienceConfig, logger output.Logger, metrics output.MetricsReporter) *Registry {
+	return &Registry{
+		config:   cfg,
+		logger:   logger,
+		metrics:  metrics,
+		breakers: make(map[string]*CircuitBreaker),
+	}
+}
+
+// Get 返回指定依赖的熔断器，不存在时按配置创建
+func (r *Registry) Get(name string) *CircuitBreaker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, ok := r.breakers[name]
+	if !ok {
+		b = NewCircuitBreaker(name, r.config.For(name), r.logger, r.metrics)
+		r.breakers[name] = b
+	}
+	return b
+}
+
+// Snapshot 返回所有熔断器的状态
+func (r *Registry) Snapshot() map[string]Snapshot {
+	r.mu.Lock()
+	breakers := make([]*CircuitBreaker, 0, len(r.breakers))
+	for _, b := range r.breakers {
+		breakers = append(breakers, b)
+	}
+	r.mu.Unlock()
+
+	snapshot := make(map[string]Snapshot, len(breakers))
+	for _, b := range breakers {
+		snapshot[b.Name()] = b.Snapshot()
+	}
+	return snapshot
+}
+
+// Degraded 是否有依赖处于熔断状态
+func (r *Registry) Degraded() bool {
+	for _, s := range r.Snapshot() {
+		if s.State != StateClosed.String() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package resilience
+
+import (
+	"context"
+	"time"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/aggregate"
+)
+
+// breakerTokenService 保护依赖吊销列表存储的操作；签发令牌不访问外部依赖，直接透传。
+// 吊销列表不可用时校验失败而不是放行，避免已吊销的令牌继续生效
+type breakerTokenService struct {
+	next    output.TokenService
+	breaker *CircuitBreaker
+}
+
+func NewTokenService(next output.TokenService, breaker *CircuitBreaker) output.TokenService {
+	return &breakerTokenService{
+		next:    next,
+		breaker: breaker,
+	}
+}
+
+func (s *breakerTokenService) GenerateToken(user *aggregate.User) (string, time.Time, error) {
+	return s.next.GenerateToken(user)
+}
+
+func (s *breakerTokenService) GenerateImpersonationToken(actor *aggregate.User, subject *aggregate.User, ttl time.Duration) (string, time.Time, error) {
+	return s.next.GenerateImpersonationToken(actor, subject, ttl)
+}
+
+func (s *breakerTokenService) GeneratePasswordResetToken(user *aggregate.User) (string, error) {
+	return s.next.GeneratePasswordResetToken(user)
+}
+
+func (s *breakerTokenService) ValidateToken(ctx context.Context, token string) (*output.TokenClaims, error) {
+	var claims *output.TokenClaims
+	err := s.breaker.Execute(func() error {
+		var err error
+		claims, err = s.next.ValidateToken(ctx, token)
+		return err
+	})
+	return claims, err
+}
+
+func (s *breakerTokenService) RevokeToken(ctx context.Context, token string) error {
+	return s.breaker.Execute(func() error {
+		return s.next.RevokeToken(ctx, token)
+	})
+}
+
+func (s *breakerTokenService) RevokeUserTokens(ctx context.Context, userID string) error {
+	return s.breaker.Execute(func() error {
+		return s.next.RevokeUserTokens(ctx, userID)
+	})
+}
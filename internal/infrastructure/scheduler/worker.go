@@ -0,0 +1,176 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+	"github.com/gohex/gohex/internal/application/port/input/command"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/infrastructure/config"
+)
+
+// leaseName 调度器选主使用的租约名称
+const leaseName = "scheduler"
+
+// Worker 执行到期的计划命令，多实例部署时只有持有租约的实例执行
+type Worker struct {
+	store      output.ScheduleStore
+	lease      output.LeaderLease
+	commandBus command.Bus
+	config     config.SchedulerConfig
+	holder     string
+	logger     output.Logger
+	metrics    output.MetricsReporter
+}
+
+func NewWorker(
+	store output.ScheduleStore,
+	lease output.LeaderLease,
+	commandBus command.Bus,
+	cfg config.SchedulerConfig,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *Worker {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 5 * time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	// 租约必须长于检查间隔，否则主实例每轮之间都会丢失租约
+	if cfg.LeaseTTL <= 2*cfg.PollInterval {
+		cfg.LeaseTTL = 3 * cfg.PollInterval
+	}
+
+	hostname, _ := os.Hostname()
+	return &Worker{
+		store:      store,
+		lease:      lease,
+		commandBus: commandBus,
+		config:     cfg,
+		holder:     fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), uuid.New().String()[:8]),
+		logger:     logger,
+		metrics:    metrics,
+	}
+}
+
+// Start 启动调度循环，直到 ctx 取消
+func (w *Worker) Start(ctx context.Context) {
+	if !w.config.Enabled {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(w.config.PollInterval)
+		defer ticker.Stop()
+		defer w.release()
+
+		for {
+			if w.acquire(ctx) {
+				w.runDue(ctx)
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// acquire 获取或续约租约
+func (w *Worker) acquire(ctx context.Context) bool {
+	leader, err := w.lease.TryAcquire(ctx, leaseName, w.holder, w.config.LeaseTTL)
+	if err != nil {
+		w.logger.Error("failed to acquire scheduler lease", "error", err)
+		leader = false
+	}
+
+	if leader {
+		w.metrics.Gauge("scheduler_leader", 1)
+	} else {
+		w.metrics.Gauge("scheduler_leader", 0)
+	}
+	return leader
+}
+
+// release 停止时释放租约，其他实例无需等待租约过期即可接管
+func (w *Worker) release() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := w.lease.Release(ctx, leaseName, w.holder); err != nil {
+		w.logger.Error("failed to release scheduler lease", "error", err)
+	}
+	w.metrics.Gauge("scheduler_leader", 0)
+}
+
+func (w *Worker) runDue(ctx context.Context) {
+	schedules, err := w.store.FindDue(ctx, time.Now(), w.config.BatchSize)
+	if err != nil {
+		w.logger.Error("failed to load due scheduled commands", "error", err)
+		return
+	}
+
+	for i, s := range schedules {
+		// 批次执行时间可能超过租约，每条计划执行前续约，失去租约后交给新的主实例
+		if i > 0 && !w.acquire(ctx) {
+			return
+		}
+		w.execute(ctx, s)
+	}
+}
+
+func (w *Worker) execute(ctx context.Context, s *output.ScheduledCommand) {
+	now := time.Now()
+	w.metrics.Histogram("scheduled_command_lateness_seconds", now.Sub(s.NextRunAt).Seconds(),
+		"command_type", s.CommandType)
+
+	_, runErr := w.commandBus.RunJob(ctx, &output.Job{
+		ID:             s.ID,
+		CommandType:    s.CommandType,
+		Payload:        s.Payload,
+		RequestedBy:    s.RequestedBy,
+		ImpersonatorID: s.ImpersonatorID,
+	})
+
+	s.Runs++
+	s.LastRunAt = &now
+	s.LastError = ""
+	if runErr != nil {
+		s.LastError = runErr.Error()
+		w.logger.Error("scheduled command failed",
+			"schedule_id", s.ID,
+			"command_type", s.CommandType,
+			"error", runErr,
+		)
+		w.metrics.IncrementCounter("scheduled_command_failure", "command_type", s.CommandType)
+	} else {
+		w.metrics.IncrementCounter("scheduled_command_success", "command_type", s.CommandType)
+	}
+
+	switch {
+	case s.IsRecurring():
+		// 周期计划失败后仍按表达式继续执行；停机期间错过的多次执行只补一次
+		spec, err := cron.ParseStandard(s.Cron)
+		if err != nil {
+			s.Status = output.ScheduleFailed
+			s.LastError = fmt.Sprintf("invalid cron expression: %v", err)
+			break
+		}
+		s.NextRunAt = spec.Next(time.Now())
+	case runErr != nil:
+		s.Status = output.ScheduleFailed
+	default:
+		s.Status = output.ScheduleCompleted
+	}
+
+	if err := w.store.RecordRun(ctx, s); err != nil {
+		w.logger.Error("failed to record scheduled command run", "schedule_id", s.ID, "error", err)
+	}
+}
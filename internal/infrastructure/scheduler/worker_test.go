@@ -0,0 +1,211 @@
+package scheduler
+
+import (
+	"context"
+	stderrors "errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/port/input/command"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/infrastructure/config"
+	"github.com/gohex/gohex/internal/testutil"
+)
+
+// scheduleStore 返回预置的到期计划并记录执行结果
+type scheduleStore struct {
+	output.ScheduleStore
+	mu       sync.Mutex
+	due      []*output.ScheduledCommand
+	recorded []output.ScheduledCommand
+}
+
+func (s *scheduleStore) FindDue(ctx context.Context, now time.Time, limit int) ([]*output.ScheduledCommand, error) {
+	return s.due, nil
+}
+
+func (s *scheduleStore) RecordRun(ctx context.Context, schedule *output.ScheduledCommand) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recorded = append(s.recorded, *schedule)
+	return nil
+}
+
+func (s *scheduleStore) recordedSnapshot() []output.ScheduledCommand {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]output.ScheduledCommand(nil), s.recorded...)
+}
+
+// lease 前 grants 次获取成功，之后失去租约
+type lease struct {
+	mu       sync.Mutex
+	grants   int
+	acquired int
+	released bool
+}
+
+func (l *lease) TryAcquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.acquired++
+	return l.acquired <= l.grants, nil
+}
+
+func (l *lease) Release(ctx context.Context, name, holder string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.released = true
+	return nil
+}
+
+func (l *lease) isReleased() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.released
+}
+
+// jobRunner 记录执行的任务，fail 中的命令类型返回错误
+type jobRunner struct {
+	command.Bus
+	jobs []*output.Job
+	fail map[string]bool
+}
+
+func (b *jobRunner) RunJob(ctx context.Context, job *output.Job) (interface{}, error) {
+	b.jobs = append(b.jobs, job)
+	if b.fail[job.CommandType] {
+		return nil, stderrors.New("user not found")
+	}
+	return nil, nil
+}
+
+func newTestWorker(grants int, due ...*output.ScheduledCommand) (*Worker, *scheduleStore, *lease, *jobRunner) {
+	store := &scheduleStore{due: due}
+	l := &lease{grants: grants}
+	bus := &jobRunner{fail: map[string]bool{"command.UnlockUserCommand": true}}
+	w := NewWorker(store, l, bus, config.SchedulerConfig{Enabled: true}, testutil.NopLogger{}, testutil.NewMetrics())
+	return w, store, l, bus
+}
+
+func TestWorker_Execute(t *testing.T) {
+	tests := []struct {
+		name       string
+		schedule   output.ScheduledCommand
+		wantStatus string
+		wantError  string
+		wantNext   bool
+	}{
+		{
+			name:       "one-off succeeds",
+			schedule:   output.ScheduledCommand{CommandType: "command.ExpireEmailChangesCommand"},
+			wantStatus: output.ScheduleCompleted,
+		},
+		{
+			name:       "one-off fails",
+			schedule:   output.ScheduledCommand{CommandType: "command.UnlockUserCommand"},
+			wantStatus: output.ScheduleFailed,
+			wantError:  "user not found",
+		},
+		{
+			name:       "recurring stays active",
+			schedule:   output.ScheduledCommand{CommandType: "command.ExpireEmailChangesCommand", Cron: "*/15 * * * *"},
+			wantStatus: output.ScheduleActive,
+			wantNext:   true,
+		},
+		{
+			name:       "recurring keeps running after failure",
+			schedule:   output.ScheduledCommand{CommandType: "command.UnlockUserCommand", Cron: "*/15 * * * *"},
+			wantStatus: output.ScheduleActive,
+			wantError:  "user not found",
+			wantNext:   true,
+		},
+		{
+			name:       "invalid cron",
+			schedule:   output.ScheduledCommand{CommandType: "command.ExpireEmailChangesCommand", Cron: "every minute"},
+			wantStatus: output.ScheduleFailed,
+			wantError:  "invalid cron expression",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.schedule
+			s.ID = "s1"
+			s.Status = output.ScheduleActive
+			s.RequestedBy = "alice"
+			s.NextRunAt = time.Now().Add(-time.Minute)
+			w, store, _, bus := newTestWorker(1)
+
+			w.execute(context.Background(), &s)
+
+			require.Len(t, bus.jobs, 1)
+			assert.Equal(t, "s1", bus.jobs[0].ID)
+			assert.Equal(t, "alice", bus.jobs[0].RequestedBy)
+
+			require.Len(t, store.recorded, 1)
+			recorded := store.recorded[0]
+			assert.Equal(t, tt.wantStatus, recorded.Status)
+			assert.Equal(t, 1, recorded.Runs)
+			assert.NotNil(t, recorded.LastRunAt)
+			if tt.wantError == "" {
+				assert.Empty(t, recorded.LastError)
+			} else {
+				assert.Contains(t, recorded.LastError, tt.wantError)
+			}
+			if tt.wantNext {
+				assert.True(t, recorded.NextRunAt.After(time.Now()))
+				assert.True(t, recorded.NextRunAt.Before(time.Now().Add(15*time.Minute+time.Second)))
+			}
+		})
+	}
+}
+
+func TestWorker_RunDueStopsWhenLeaseLost(t *testing.T) {
+	due := func(id string) *output.ScheduledCommand {
+		return &output.ScheduledCommand{ID: id, CommandType: "command.ExpireEmailChangesCommand", Status: output.ScheduleActive}
+	}
+	// 第一轮获取租约，执行第二条前续约成功，执行第三条前失去租约
+	w, store, _, bus := newTestWorker(2, due("s1"), due("s2"), due("s3"))
+
+	require.True(t, w.acquire(context.Background()))
+	w.runDue(context.Background())
+
+	assert.Len(t, bus.jobs, 2)
+	assert.Len(t, store.recorded, 2)
+}
+
+func TestWorker_StartReleasesLease(t *testing.T) {
+	w, store, l, bus := newTestWorker(1, &output.ScheduledCommand{ID: "s1", CommandType: "command.ExpireEmailChangesCommand"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w.Start(ctx)
+	require.Eventually(t, func() bool { return len(store.recordedSnapshot()) == 1 }, time.Second, 10*time.Millisecond)
+	cancel()
+
+	assert.Eventually(t, func() bool { return l.isReleased() }, time.Second, 10*time.Millisecond)
+	assert.Len(t, bus.jobs, 1)
+}
+
+func TestWorker_Disabled(t *testing.T) {
+	store := &scheduleStore{}
+	l := &lease{grants: 1}
+	w := NewWorker(store, l, &jobRunner{}, config.SchedulerConfig{}, testutil.NopLogger{}, testutil.NewMetrics())
+
+	w.Start(context.Background())
+
+	assert.Zero(t, l.acquired)
+}
+
+func TestNewWorker_LeaseOutlivesPollInterval(t *testing.T) {
+	w := NewWorker(&scheduleStore{}, &lease{}, &jobRunner{}, config.SchedulerConfig{
+		PollInterval: 10 * time.Second,
+		LeaseTTL:     15 * time.Second,
+	}, testutil.NopLogger{}, testutil.NewMetrics())
+
+	assert.Equal(t, 30*time.Second, w.config.LeaseTTL)
+}
@@ -2,22 +2,27 @@ package validator
 
 import (
 	"github.com/go-playground/validator/v10"
-	"github.com/your-org/your-project/internal/application/port/input/query"
+	"github.com/gohex/gohex/internal/application/port/input/command"
+	"github.com/gohex/gohex/internal/application/port/input/query"
+	"github.com/gohex/gohex/pkg/errors"
 )
 
-type queryValidator struct {
+// structValidator 按结构体标签校验命令和查询
+type structValidator struct {
 	validate *validator.Validate
 }
 
-func NewQueryValidator() query.Validator {
-	return &queryValidator{
-		validate: validator.New(),
-	}
+func NewQueryValidator(validate *validator.Validate) query.Validator {
+	return &structValidator{validate: validate}
+}
+
+func NewCommandValidator(validate *validator.Validate) command.Validator {
+	return &structValidator{validate: validate}
 }
 
-func (v *queryValidator) Validate(i interface{}) error {
+func (v *structValidator) Validate(i interface{}) error {
 	if err := v.validate.Struct(i); err != nil {
 		return errors.NewValidationError(err.Error())
 	}
 	return nil
-} 
\ No newline at end of file
+}
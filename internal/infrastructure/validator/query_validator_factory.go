@@ -2,7 +2,9 @@ package validator
 
 import (
 	"github.com/go-playground/validator/v10"
-	"github.com/your-org/your-project/internal/application/port/input/query"
+	"github.com/gohex/gohex/internal/application/port/input/command"
+	"github.com/gohex/gohex/internal/application/port/input/query"
+	"github.com/gohex/gohex/internal/application/port/output"
 )
 
 type ValidatorFactory interface {
@@ -12,10 +14,10 @@ type ValidatorFactory interface {
 
 type validatorFactory struct {
 	validate *validator.Validate
-	logger   Logger
+	logger   output.Logger
 }
 
-func NewValidatorFactory(logger Logger) ValidatorFactory {
+func NewValidatorFactory(logger output.Logger) ValidatorFactory {
 	v := validator.New()
 	
 	// 注册自定义验证规则
@@ -30,9 +32,9 @@ func NewValidatorFactory(logger Logger) ValidatorFactory {
 }
 
 func (f *validatorFactory) CreateQueryValidator() query.Validator {
-	return NewQueryValidator(f.validate, f.logger)
+	return NewQueryValidator(f.validate)
 }
 
 func (f *validatorFactory) CreateCommandValidator() command.Validator {
-	return NewCommandValidator(f.validate, f.logger)
+	return NewCommandValidator(f.validate)
 } 
\ No newline at end of file
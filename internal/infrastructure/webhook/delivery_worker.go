@@ -0,0 +1,252 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/infrastructure/config"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+// maxErrorBody 记录失败响应体的最大字节数
+const maxErrorBody = 1024
+
+// DeliveryWorker 领取到期的 webhook 投递并发送，失败时按指数退避重试
+type DeliveryWorker struct {
+	repo    output.WebhookRepository
+	client  *http.Client
+	config  config.WebhookConfig
+	logger  output.Logger
+	metrics output.MetricsReporter
+}
+
+func NewDeliveryWorker(
+	repo output.WebhookRepository,
+	cfg config.WebhookConfig,
+	logger output.Logger,
+	metrics output.MetricsReporter,
+) *DeliveryWorker {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 8
+	}
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = 30 * time.Second
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 6 * time.Hour
+	}
+
+	return &DeliveryWorker{
+		repo: repo,
+		client: &http.Client{
+			Timeout: cfg.Timeout,
+			// 不跟随重定向，避免订阅地址被指向内部服务
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		config:  cfg,
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+// Start 启动投递和清理循环，直到 ctx 取消
+func (w *DeliveryWorker) Start(ctx context.Context) {
+	if !w.config.Enabled {
+		return
+	}
+
+	workers := w.config.Workers
+	if workers <= 0 {
+		workers = 4
+	}
+
+	for i := 0; i < workers; i++ {
+		go w.loop(ctx)
+	}
+	go w.maintain(ctx)
+}
+
+func (w *DeliveryWorker) loop(ctx context.Context) {
+	interval := w.config.PollInterval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		w.drain(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// drain 依次投递所有到期记录
+func (w *DeliveryWorker) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		// 租约长于请求超时，请求未结束前不会被其他实例重复领取
+		deliveries, err := w.repo.ClaimDue(ctx, time.Now(), 2*w.config.Timeout, 1)
+		if err != nil {
+			w.logger.Error("failed to claim webhook deliveries", "error", err)
+			return
+		}
+		if len(deliveries) == 0 {
+			return
+		}
+		for _, d := range deliveries {
+			w.process(ctx, d)
+		}
+	}
+}
+
+func (w *DeliveryWorker) process(ctx context.Context, d *output.WebhookDelivery) {
+	sub, err := w.repo.FindSubscription(ctx, d.SubscriptionID)
+	if err != nil {
+		var appErr *errors.AppError
+		if errors.As(err, &appErr) && appErr.Code == errors.ErrCodeNotFound {
+			w.finish(ctx, d, output.WebhookDeliveryFailed, 0, "subscription deleted")
+			return
+		}
+		w.logger.Error("failed to load webhook subscription", "subscription_id", d.SubscriptionID, "error", err)
+		return
+	}
+	if !sub.Active {
+		w.finish(ctx, d, output.WebhookDeliveryFailed, 0, "subscription disabled")
+		return
+	}
+
+	timer := w.metrics.StartTimer("webhook_delivery_duration", "event_type", d.EventType)
+	statusCode, sendErr := w.send(ctx, sub, d)
+	timer.Stop()
+
+	d.Attempts++
+	if sendErr == nil {
+		now := time.Now()
+		d.DeliveredAt = &now
+		w.finish(ctx, d, output.WebhookDeliverySucceeded, statusCode, "")
+		if err := w.repo.RecordSuccess(ctx, sub.ID); err != nil {
+			w.logger.Error("failed to reset webhook failures", "subscription_id", sub.ID, "error", err)
+		}
+		w.metrics.IncrementCounter("webhook_delivery_success", "event_type", d.EventType)
+		return
+	}
+
+	w.logger.Warn("webhook delivery failed",
+		"delivery_id", d.ID,
+		"subscription_id", sub.ID,
+		"attempt", d.Attempts,
+		"status_code", statusCode,
+		"error", sendErr,
+	)
+	w.metrics.IncrementCounter("webhook_delivery_failure", "event_type", d.EventType)
+
+	if d.Attempts >= w.config.MaxAttempts {
+		w.finish(ctx, d, output.WebhookDeliveryFailed, statusCode, sendErr.Error())
+		w.metrics.IncrementCounter("webhook_delivery_exhausted", "event_type", d.EventType)
+	} else {
+		d.NextAttemptAt = time.Now().Add(w.backoff(d.Attempts))
+		w.finish(ctx, d, output.WebhookDeliveryPending, statusCode, sendErr.Error())
+	}
+
+	disabled, err := w.repo.RecordFailure(ctx, sub.ID, w.config.DisableAfter,
+		fmt.Sprintf("disabled after %d consecutive failed deliveries, last error: %s", w.config.DisableAfter, sendErr))
+	if err != nil {
+		w.logger.Error("failed to record webhook failure", "subscription_id", sub.ID, "error", err)
+		return
+	}
+	if disabled {
+		w.logger.Warn("webhook subscription disabled", "subscription_id", sub.ID, "url", sub.URL)
+		w.metrics.IncrementCounter("webhook_subscription_disabled")
+	}
+}
+
+// send 发送签名后的请求，非 2xx 响应视为失败
+func (w *DeliveryWorker) send(ctx context.Context, sub *output.WebhookSubscription, d *output.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gohex-webhooks/1.0")
+	req.Header.Set(HeaderDeliveryID, d.ID)
+	req.Header.Set(HeaderEventID, d.EventID)
+	req.Header.Set(HeaderEventType, d.EventType)
+	req.Header.Set(HeaderTimestamp, fmt.Sprint(timestamp))
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, timestamp, d.Payload))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBody))
+		return resp.StatusCode, nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	return resp.StatusCode, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+}
+
+func (w *DeliveryWorker) finish(ctx context.Context, d *output.WebhookDelivery, status string, statusCode int, reason string) {
+	d.Status = status
+	d.LastStatusCode = statusCode
+	d.LastError = reason
+	if err := w.repo.UpdateDelivery(ctx, d); err != nil {
+		w.logger.Error("failed to update webhook delivery", "delivery_id", d.ID, "error", err)
+	}
+}
+
+// backoff 第 n 次失败后的等待时间
+func (w *DeliveryWorker) backoff(attempts int) time.Duration {
+	delay := w.config.InitialBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= w.config.MaxBackoff {
+			return w.config.MaxBackoff
+		}
+	}
+	return delay
+}
+
+// maintain 删除超过保留期的投递记录
+func (w *DeliveryWorker) maintain(ctx context.Context) {
+	if w.config.Retention <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		n, err := w.repo.DeleteFinished(ctx, time.Now().Add(-w.config.Retention))
+		if err != nil {
+			w.logger.Error("failed to delete webhook deliveries", "error", err)
+		} else if n > 0 {
+			w.logger.Info("expired webhook deliveries deleted", "count", n)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/infrastructure/config"
+	"github.com/gohex/gohex/internal/testutil"
+)
+
+// receiver 记录收到的请求并按 status 响应
+type receiver struct {
+	status   int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+	w.WriteHeader(r.status)
+	w.Write([]byte("  upstream says no  "))
+}
+
+func newWorker(t *testing.T, status int, cfg config.WebhookConfig) (*DeliveryWorker, *testutil.WebhookRepository, *receiver, *testutil.Metrics) {
+	t.Helper()
+	recv := &receiver{status: status}
+	srv := httptest.NewServer(recv)
+	t.Cleanup(srv.Close)
+
+	repo := testutil.NewWebhookRepository(&output.WebhookSubscription{
+		ID:         "sub-1",
+		URL:        srv.URL + "/hooks",
+		Secret:     "whsec",
+		EventTypes: []string{output.WebhookAllEvents},
+		Active:     true,
+	})
+	metrics := testutil.NewMetrics()
+	return NewDeliveryWorker(repo, cfg, testutil.NopLogger{}, metrics), repo, recv, metrics
+}
+
+func newDelivery(repo *testutil.WebhookRepository, attempts int) *output.WebhookDelivery {
+	d := &output.WebhookDelivery{
+		ID:             "dlv-1",
+		SubscriptionID: "sub-1",
+		EventID:        "evt-1",
+		EventType:      "user.created",
+		Payload:        []byte(`{"id":"evt-1"}`),
+		Status:         output.WebhookDeliveryPending,
+		Attempts:       attempts,
+	}
+	repo.SaveDeliveries(context.Background(), []*output.WebhookDelivery{d})
+	return d
+}
+
+func TestDeliveryWorker_DeliversSignedRequest(t *testing.T) {
+	w, repo, recv, metrics := newWorker(t, http.StatusNoContent, config.WebhookConfig{})
+	repo.Subscriptions["sub-1"].ConsecutiveFailures = 2
+	d := newDelivery(repo, 0)
+
+	w.process(context.Background(), d)
+
+	require.Len(t, recv.requests, 1)
+	req := recv.requests[0]
+	assert.Equal(t, "/hooks", req.URL.Path)
+	assert.Equal(t, "dlv-1", req.Header.Get(HeaderDeliveryID))
+	assert.Equal(t, "evt-1", req.Header.Get(HeaderEventID))
+	assert.Equal(t, "user.created", req.Header.Get(HeaderEventType))
+	timestamp, err := strconv.ParseInt(req.Header.Get(HeaderTimestamp), 10, 64)
+	require.NoError(t, err)
+	assert.True(t, Verify("whsec", timestamp, recv.bodies[0], req.Header.Get(HeaderSignature)))
+
+	assert.Equal(t, output.WebhookDeliverySucceeded, d.Status)
+	assert.Equal(t, 1, d.Attempts)
+	assert.Equal(t, http.StatusNoContent, d.LastStatusCode)
+	assert.NotNil(t, d.DeliveredAt)
+	assert.Zero(t, repo.Subscriptions["sub-1"].ConsecutiveFailures)
+	assert.Equal(t, 1, metrics.Counter("webhook_delivery_success"))
+}
+
+func TestDeliveryWorker_Failure(t *testing.T) {
+	tests := []struct {
+		name         string
+		attempts     int
+		disableAfter int
+		wantStatus   string
+		wantActive   bool
+	}{
+		{name: "schedules retry", attempts: 0, wantStatus: output.WebhookDeliveryPending, wantActive: true},
+		{name: "gives up after max attempts", attempts: 2, wantStatus: output.WebhookDeliveryFailed, wantActive: true},
+		{name: "disables subscription after consecutive failures", attempts: 0, disableAfter: 1, wantStatus: output.WebhookDeliveryPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, repo, _, _ := newWorker(t, http.StatusBadGateway, config.WebhookConfig{
+				MaxAttempts:    3,
+				InitialBackoff: time.Minute,
+				DisableAfter:   tt.disableAfter,
+			})
+			d := newDelivery(repo, tt.attempts)
+
+			before := time.Now()
+			w.process(context.Background(), d)
+
+			assert.Equal(t, tt.wantStatus, d.Status)
+			assert.Equal(t, tt.attempts+1, d.Attempts)
+			assert.Equal(t, http.StatusBadGateway, d.LastStatusCode)
+			assert.Equal(t, "unexpected status 502: upstream says no", d.LastError)
+			if tt.wantStatus == output.WebhookDeliveryPending {
+				assert.WithinDuration(t, before.Add(time.Minute), d.NextAttemptAt, time.Second)
+			}
+
+			sub := repo.Subscriptions["sub-1"]
+			assert.Equal(t, 1, sub.ConsecutiveFailures)
+			assert.Equal(t, tt.wantActive, sub.Active)
+		})
+	}
+}
+
+func TestDeliveryWorker_SkipsUnavailableSubscription(t *testing.T) {
+	tests := []struct {
+		name       string
+		prepare    func(repo *testutil.WebhookRepository)
+		wantReason string
+	}{
+		{
+			name:       "disabled",
+			prepare:    func(repo *testutil.WebhookRepository) { repo.Subscriptions["sub-1"].Active = false },
+			wantReason: "subscription disabled",
+		},
+		{
+			name:       "deleted",
+			prepare:    func(repo *testutil.WebhookRepository) { delete(repo.Subscriptions, "sub-1") },
+			wantReason: "subscription deleted",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, repo, recv, _ := newWorker(t, http.StatusOK, config.WebhookConfig{})
+			d := newDelivery(repo, 0)
+			tt.prepare(repo)
+
+			w.process(context.Background(), d)
+
+			assert.Empty(t, recv.requests)
+			assert.Equal(t, output.WebhookDeliveryFailed, d.Status)
+			assert.Equal(t, tt.wantReason, d.LastError)
+			assert.Zero(t, d.Attempts)
+		})
+	}
+}
+
+func TestDeliveryWorker_DoesNotFollowRedirects(t *testing.T) {
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("redirect followed")
+	}))
+	defer internal.Close()
+
+	w, repo, _, _ := newWorker(t, http.StatusOK, config.WebhookConfig{})
+	redirect := httptest.NewServer(http.RedirectHandler(internal.URL, http.StatusFound))
+	defer redirect.Close()
+	repo.Subscriptions["sub-1"].URL = redirect.URL
+	d := newDelivery(repo, 0)
+
+	w.process(context.Background(), d)
+
+	assert.Equal(t, output.WebhookDeliveryPending, d.Status)
+	assert.Equal(t, http.StatusFound, d.LastStatusCode)
+}
+
+func TestDeliveryWorker_Backoff(t *testing.T) {
+	w := NewDeliveryWorker(testutil.NewWebhookRepository(), config.WebhookConfig{
+		InitialBackoff: 30 * time.Second,
+		MaxBackoff:     5 * time.Minute,
+	}, testutil.NopLogger{}, testutil.NewMetrics())
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: 30 * time.Second},
+		{attempts: 2, want: time.Minute},
+		{attempts: 4, want: 4 * time.Minute},
+		{attempts: 5, want: 5 * time.Minute},
+		{attempts: 20, want: 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, w.backoff(tt.attempts), "attempts %d", tt.attempts)
+	}
+}
@@ -0,0 +1,32 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+// 投递请求头
+const (
+	HeaderDeliveryID = "X-Webhook-Delivery"
+	HeaderEventID    = "X-Webhook-Event-ID"
+	HeaderEventType  = "X-Webhook-Event"
+	HeaderTimestamp  = "X-Webhook-Timestamp"
+	// HeaderSignature 值为 "sha256=" 加十六进制 HMAC-SHA256(secret, 时间戳 + "." + 请求体)
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Sign 计算签名，时间戳参与签名，接收方可拒绝过旧的请求以防重放
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 校验签名，供接收方或测试使用
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
@@ -0,0 +1,39 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSign(t *testing.T) {
+	// echo -n '1700000000.{"id":"1"}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t,
+		"sha256=086f6aff7bd084c98679825129c5a64dbad88c760016d6d2c0fb123f27951d54",
+		Sign("secret", 1700000000, []byte(`{"id":"1"}`)),
+	)
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"id":"1"}`)
+	signature := Sign("secret", 1700000000, body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      []byte
+		want      bool
+	}{
+		{name: "valid", secret: "secret", timestamp: 1700000000, body: body, want: true},
+		{name: "wrong secret", secret: "other", timestamp: 1700000000, body: body},
+		{name: "replayed with new timestamp", secret: "secret", timestamp: 1700000300, body: body},
+		{name: "tampered body", secret: "secret", timestamp: 1700000000, body: []byte(`{"id":"2"}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Verify(tt.secret, tt.timestamp, tt.body, signature))
+		})
+	}
+}
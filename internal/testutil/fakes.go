@@ -0,0 +1,594 @@
+package testutil
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/aggregate"
+	"github.com/gohex/gohex/internal/domain/event"
+	"github.com/gohex/gohex/internal/domain/vo"
+	"github.com/gohex/gohex/pkg/crypto"
+	"github.com/gohex/gohex/pkg/errors"
+)
+
+// UserRepository 内存用户仓储，与数据库一样保存和返回聚合的副本：
+// 读取的聚合不带未保存的事件，版本为最近一次保存时的版本
+type UserRepository struct {
+	mu    sync.Mutex
+	users map[string]*aggregate.User
+}
+
+func NewUserRepository(users ...*aggregate.User) *UserRepository {
+	r := &UserRepository{users: make(map[string]*aggregate.User)}
+	for _, u := range users {
+		r.users[u.ID()] = copyUser(u)
+	}
+	return r
+}
+
+// copyUser 按持久化的字段重建聚合
+func copyUser(u *aggregate.User) *aggregate.User {
+	return aggregate.ReconstituteUser(u.ID(), u.Email(), u.Password(), u.Profile(), u.Status(),
+		append([]vo.UserRole(nil), u.Roles()...), u.CreatedAt(), u.UpdatedAt(), u.DeletedAt(), u.LockedUntil(), u.ErasedAt(), u.Version())
+}
+
+func (r *UserRepository) Save(ctx context.Context, user *aggregate.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.users[user.ID()] = copyUser(user)
+	return nil
+}
+
+func (r *UserRepository) Update(ctx context.Context, user *aggregate.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.users[user.ID()]; !ok {
+		return errors.ErrUserNotFound
+	}
+	r.users[user.ID()] = copyUser(user)
+	return nil
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.users[id]; !ok {
+		return errors.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *UserRepository) Purge(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[id]
+	if !ok || user.DeletedAt() == nil {
+		return errors.ErrUserNotDeleted
+	}
+	delete(r.users, id)
+	return nil
+}
+
+func (r *UserRepository) FindByID(ctx context.Context, id string) (*aggregate.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[id]
+	if !ok {
+		return nil, errors.ErrUserNotFound
+	}
+	return copyUser(user), nil
+}
+
+func (r *UserRepository) FindByEmail(ctx context.Context, email vo.Email) (*aggregate.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, user := range r.users {
+		if user.Email().String() == email.String() && user.DeletedAt() == nil {
+			return copyUser(user), nil
+		}
+	}
+	return nil, errors.ErrUserNotFound
+}
+
+func (r *UserRepository) FindAll(ctx context.Context, params output.FindAllParams) ([]*aggregate.User, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var users []*aggregate.User
+	for _, user := range r.users {
+		users = append(users, copyUser(user))
+	}
+	return users, int64(len(users)), nil
+}
+
+func (r *UserRepository) ExistsByEmail(ctx context.Context, email vo.Email) (bool, error) {
+	_, err := r.FindByEmail(ctx, email)
+	return err == nil, nil
+}
+
+func (r *UserRepository) FindDeletedBefore(ctx context.Context, before time.Time, afterID string, limit int) ([]*aggregate.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var users []*aggregate.User
+	for _, user := range r.users {
+		if d := user.DeletedAt(); d != nil && !d.After(before) && user.ID() > afterID {
+			users = append(users, copyUser(user))
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID() < users[j].ID() })
+	if len(users) > limit {
+		users = users[:limit]
+	}
+	return users, nil
+}
+
+// EventStore 记录保存的事件，与数据库一样检查期望版本；
+// 没有事件的聚合视为从其当前版本开始，测试可以直接使用重建的聚合
+type EventStore struct {
+	mu       sync.Mutex
+	events   map[string][]event.Event
+	versions map[string]int
+}
+
+func NewEventStore() *EventStore {
+	return &EventStore{events: make(map[string][]event.Event), versions: make(map[string]int)}
+}
+
+func (s *EventStore) SaveEvents(ctx context.Context, aggregateID string, events []event.Event, expectedVersion int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if version, ok := s.versions[aggregateID]; ok && version != expectedVersion {
+		return errors.ErrConcurrencyConflict
+	}
+	s.versions[aggregateID] = expectedVersion + len(events)
+	s.events[aggregateID] = append(s.events[aggregateID], events...)
+	return nil
+}
+
+func (s *EventStore) GetEvents(ctx context.Context, aggregateID string) ([]event.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]event.Event(nil), s.events[aggregateID]...), nil
+}
+
+// RedactEvents 将不属于 event.Anonymous 的事件替换为只保留描述信息的 RawEvent
+func (s *EventStore) RedactEvents(ctx context.Context, aggregateID string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var n int64
+	for i, e := range s.events[aggregateID] {
+		if _, ok := e.(event.Anonymous); ok {
+			continue
+		}
+		if raw, ok := e.(*event.RawEvent); ok && raw.Payload == nil {
+			continue
+		}
+		s.events[aggregateID][i] = event.NewRawEvent(event.HeaderOf(e), nil)
+		n++
+	}
+	return n, nil
+}
+
+// Types 返回聚合已保存事件的类型，按保存顺序
+func (s *EventStore) Types(aggregateID string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var types []string
+	for _, e := range s.events[aggregateID] {
+		types = append(types, e.Type())
+	}
+	return types
+}
+
+// EventBus 记录发布的事件，不调用处理器
+type EventBus struct {
+	mu        sync.Mutex
+	Published []event.Event
+}
+
+func (b *EventBus) Publish(ctx context.Context, events ...event.Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.Published = append(b.Published, events...)
+	return nil
+}
+
+func (b *EventBus) Subscribe(eventType string, handler output.EventHandler)   {}
+func (b *EventBus) Unsubscribe(eventType string, handler output.EventHandler) {}
+func (b *EventBus) Close() error                                              { return nil }
+
+// UnitOfWork 直接执行函数，不开启事务
+type UnitOfWork struct{}
+
+func (UnitOfWork) Begin(ctx context.Context) (context.Context, error) { return ctx, nil }
+func (UnitOfWork) Commit(ctx context.Context) error                   { return nil }
+func (UnitOfWork) Rollback(ctx context.Context) error                 { return nil }
+func (UnitOfWork) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// TokenService 按令牌字符串返回预设声明，记录被吊销的令牌和用户
+type TokenService struct {
+	mu           sync.Mutex
+	Claims       map[string]*output.TokenClaims
+	Revoked      []string
+	RevokedUsers []string
+}
+
+func NewTokenService() *TokenService {
+	return &TokenService{Claims: make(map[string]*output.TokenClaims)}
+}
+
+func (s *TokenService) GenerateToken(user *aggregate.User) (string, time.Time, error) {
+	return "token-" + user.ID(), time.Now().Add(time.Hour), nil
+}
+
+func (s *TokenService) GenerateImpersonationToken(actor, subject *aggregate.User, ttl time.Duration) (string, time.Time, error) {
+	token := "impersonation-" + actor.ID() + "-" + subject.ID()
+	expiresAt := time.Now().Add(ttl)
+	s.mu.Lock()
+	s.Claims[token] = &output.TokenClaims{
+		UserID:    subject.ID(),
+		ExpiresAt: expiresAt,
+		Actor:     &output.TokenActor{Subject: actor.ID()},
+	}
+	s.mu.Unlock()
+	return token, expiresAt, nil
+}
+
+func (s *TokenService) GeneratePasswordResetToken(user *aggregate.User) (string, error) {
+	return "reset-" + user.ID(), nil
+}
+
+func (s *TokenService) ValidateToken(ctx context.Context, token string) (*output.TokenClaims, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	claims, ok := s.Claims[token]
+	if !ok {
+		return nil, errors.ErrInvalidToken
+	}
+	return claims, nil
+}
+
+func (s *TokenService) RevokeToken(ctx context.Context, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Revoked = append(s.Revoked, token)
+	return nil
+}
+
+func (s *TokenService) RevokeUserTokens(ctx context.Context, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.RevokedUsers = append(s.RevokedUsers, userID)
+	return nil
+}
+
+// NewUser 重建一个已持久化的用户，用于测试
+func NewUser(id string, status vo.UserStatus, roles ...vo.UserRole) *aggregate.User {
+	email, err := vo.NewEmail(id + "@example.com")
+	if err != nil {
+		panic(err)
+	}
+	profile, err := vo.NewUserProfile("Test "+id, "")
+	if err != nil {
+		panic(err)
+	}
+	now := time.Now()
+	return aggregate.ReconstituteUser(id, email, vo.NewPasswordFromHash("hash"), profile, status, roles, now, now, nil, nil, nil, 1)
+}
+
+// KeyStore 内存密钥存储
+type KeyStore struct {
+	mu   sync.Mutex
+	Keys map[string][]byte
+}
+
+func NewKeyStore() *KeyStore {
+	return &KeyStore{Keys: make(map[string][]byte)}
+}
+
+func (s *KeyStore) GetOrCreateKey(ctx context.Context, subjectID string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if key, ok := s.Keys[subjectID]; ok {
+		return key, nil
+	}
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	s.Keys[subjectID] = key
+	return key, nil
+}
+
+func (s *KeyStore) GetKey(ctx context.Context, subjectID string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if key, ok := s.Keys[subjectID]; ok {
+		return key, nil
+	}
+	return nil, output.ErrKeyShredded
+}
+
+func (s *KeyStore) DeleteKey(ctx context.Context, subjectID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.Keys, subjectID)
+	return nil
+}
+
+// AuditLog 内存审计日志，不计算哈希链
+type AuditLog struct {
+	mu      sync.Mutex
+	Entries []*output.AuditEntry
+}
+
+func (l *AuditLog) Append(ctx context.Context, entry *output.AuditEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.Entries = append(l.Entries, entry)
+	return nil
+}
+
+func (l *AuditLog) Find(ctx context.Context, filter output.AuditFilter) ([]*output.AuditEntry, int64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var entries []*output.AuditEntry
+	for _, e := range l.Entries {
+		if filter.SubjectID == "" || e.SubjectID == filter.SubjectID {
+			entries = append(entries, e)
+		}
+	}
+	return entries, int64(len(entries)), nil
+}
+
+func (l *AuditLog) Verify(ctx context.Context, from, to time.Time) (string, error) {
+	return "", nil
+}
+
+func (l *AuditLog) RedactSubject(ctx context.Context, subjectID string) (int64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var n int64
+	for _, e := range l.Entries {
+		if e.SubjectID == subjectID && e.Changes != nil {
+			e.Changes = nil
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (l *AuditLog) Purge(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+// WebhookRepository 内存 webhook 仓储，未实现列表和清理
+type WebhookRepository struct {
+	output.WebhookRepository
+	mu            sync.Mutex
+	Subscriptions map[string]*output.WebhookSubscription
+	Deliveries    []*output.WebhookDelivery
+}
+
+func NewWebhookRepository(subs ...*output.WebhookSubscription) *WebhookRepository {
+	r := &WebhookRepository{Subscriptions: make(map[string]*output.WebhookSubscription)}
+	for _, sub := range subs {
+		r.Subscriptions[sub.ID] = sub
+	}
+	return r
+}
+
+func (r *WebhookRepository) SaveSubscription(ctx context.Context, sub *output.WebhookSubscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Subscriptions == nil {
+		r.Subscriptions = make(map[string]*output.WebhookSubscription)
+	}
+	r.Subscriptions[sub.ID] = sub
+	return nil
+}
+
+func (r *WebhookRepository) UpdateSubscription(ctx context.Context, sub *output.WebhookSubscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.Subscriptions[sub.ID]; !ok {
+		return errors.NewNotFoundError("webhook subscription")
+	}
+	r.Subscriptions[sub.ID] = sub
+	return nil
+}
+
+func (r *WebhookRepository) FindSubscription(ctx context.Context, id string) (*output.WebhookSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sub, ok := r.Subscriptions[id]
+	if !ok {
+		return nil, errors.NewNotFoundError("webhook subscription")
+	}
+	return sub, nil
+}
+
+// DeleteSubscription 同时删除订阅的投递记录
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.Subscriptions[id]; !ok {
+		return errors.NewNotFoundError("webhook subscription")
+	}
+	delete(r.Subscriptions, id)
+	kept := r.Deliveries[:0]
+	for _, d := range r.Deliveries {
+		if d.SubscriptionID != id {
+			kept = append(kept, d)
+		}
+	}
+	r.Deliveries = kept
+	return nil
+}
+
+func (r *WebhookRepository) FindActiveSubscriptions(ctx context.Context, eventType string) ([]*output.WebhookSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var subs []*output.WebhookSubscription
+	for _, sub := range r.Subscriptions {
+		if sub.Active && sub.Matches(eventType) {
+			subs = append(subs, sub)
+		}
+	}
+	sort.Slice(subs, func(i, j int) bool { return subs[i].ID < subs[j].ID })
+	return subs, nil
+}
+
+func (r *WebhookRepository) RecordSuccess(ctx context.Context, subscriptionID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if sub, ok := r.Subscriptions[subscriptionID]; ok {
+		sub.ConsecutiveFailures = 0
+	}
+	return nil
+}
+
+func (r *WebhookRepository) RecordFailure(ctx context.Context, subscriptionID string, disableAfter int, reason string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sub, ok := r.Subscriptions[subscriptionID]
+	if !ok {
+		return false, nil
+	}
+	sub.ConsecutiveFailures++
+	if !sub.Active || disableAfter <= 0 || sub.ConsecutiveFailures < disableAfter {
+		return false, nil
+	}
+	now := time.Now()
+	sub.Active = false
+	sub.DisabledReason = reason
+	sub.DisabledAt = &now
+	return true, nil
+}
+
+func (r *WebhookRepository) SaveDeliveries(ctx context.Context, deliveries []*output.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Deliveries = append(r.Deliveries, deliveries...)
+	return nil
+}
+
+func (r *WebhookRepository) UpdateDelivery(ctx context.Context, delivery *output.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, d := range r.Deliveries {
+		if d.ID == delivery.ID {
+			r.Deliveries[i] = delivery
+			return nil
+		}
+	}
+	return errors.NewNotFoundError("webhook delivery")
+}
+
+func (r *WebhookRepository) FindDelivery(ctx context.Context, id string) (*output.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, d := range r.Deliveries {
+		if d.ID == id {
+			return d, nil
+		}
+	}
+	return nil, errors.NewNotFoundError("webhook delivery")
+}
+
+// ClaimDue 返回到期待投递记录的副本，并把下次投递时间推迟 lease
+func (r *WebhookRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*output.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var claimed []*output.WebhookDelivery
+	for _, d := range r.Deliveries {
+		if len(claimed) >= limit {
+			break
+		}
+		if d.Status != output.WebhookDeliveryPending || d.NextAttemptAt.After(now) {
+			continue
+		}
+		d.NextAttemptAt = now.Add(lease)
+		c := *d
+		claimed = append(claimed, &c)
+	}
+	return claimed, nil
+}
+
+// RedactDeliveries 按载荷中的 aggregate_id 匹配，将 data 置为 null
+func (r *WebhookRepository) RedactDeliveries(ctx context.Context, aggregateID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var n int64
+	for _, d := range r.Deliveries {
+		var payload map[string]interface{}
+		if err := json.Unmarshal(d.Payload, &payload); err != nil {
+			return n, err
+		}
+		if payload["aggregate_id"] != aggregateID {
+			continue
+		}
+		payload["data"] = nil
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return n, err
+		}
+		d.Payload = data
+		n++
+	}
+	return n, nil
+}
+
+// DataExportRepository 只记录按用户删除的调用，其余方法未实现
+type DataExportRepository struct {
+	output.DataExportRepository
+	DeletedUsers []string
+}
+
+func (r *DataExportRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	r.DeletedUsers = append(r.DeletedUsers, userID)
+	return nil
+}
+
+// EmailChangeRepository 按确认令牌摘要查找保存的变更，记录删除调用；按取消令牌查找未实现
+type EmailChangeRepository struct {
+	output.EmailChangeRepository
+	Changes      []*output.PendingEmailChange
+	Deleted      []string
+	DeletedUsers []string
+}
+
+func (r *EmailChangeRepository) Save(ctx context.Context, change *output.PendingEmailChange) error {
+	r.Changes = append(r.Changes, change)
+	return nil
+}
+
+func (r *EmailChangeRepository) FindByConfirmToken(ctx context.Context, tokenHash string) (*output.PendingEmailChange, error) {
+	for _, c := range r.Changes {
+		if c.ConfirmTokenHash == tokenHash {
+			return c, nil
+		}
+	}
+	return nil, errors.ErrEmailChangeNotFound
+}
+
+func (r *EmailChangeRepository) Delete(ctx context.Context, id string) error {
+	r.Deleted = append(r.Deleted, id)
+	for i, c := range r.Changes {
+		if c.ID == id {
+			r.Changes = append(r.Changes[:i], r.Changes[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (r *EmailChangeRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	r.DeletedUsers = append(r.DeletedUsers, userID)
+	return nil
+}
@@ -0,0 +1,55 @@
+// Package testutil 提供单元测试共用的日志、指标等端口替身
+package testutil
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+)
+
+// NopLogger 丢弃所有日志
+type NopLogger struct{}
+
+func (NopLogger) Debug(msg string, args ...interface{})              {}
+func (NopLogger) Info(msg string, args ...interface{})               {}
+func (NopLogger) Warn(msg string, args ...interface{})               {}
+func (NopLogger) Error(msg string, args ...interface{})              {}
+func (l NopLogger) With(key string, value interface{}) output.Logger { return l }
+
+// Metrics 在内存中记录计数器，便于断言
+type Metrics struct {
+	mu       sync.Mutex
+	counters map[string]int
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{counters: make(map[string]int)}
+}
+
+func (m *Metrics) IncrementCounter(name string, tags ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counters[name]++
+}
+
+func (m *Metrics) Gauge(name string, value float64, tags ...string)     {}
+func (m *Metrics) Histogram(name string, value float64, tags ...string) {}
+
+func (m *Metrics) StartTimer(name string, tags ...string) output.Timer {
+	return &timer{start: time.Now()}
+}
+
+// Counter 返回计数器当前值
+func (m *Metrics) Counter(name string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.counters[name]
+}
+
+type timer struct {
+	start time.Time
+}
+
+func (t *timer) Stop()             {}
+func (t *timer) Duration() float64 { return time.Since(t.start).Seconds() }
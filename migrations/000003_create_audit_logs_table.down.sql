@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS audit_logs_no_update;
+DROP TABLE IF EXISTS audit_logs;
\ No newline at end of file
@@ -0,0 +1,25 @@
+CREATE TABLE audit_logs (
+    seq BIGINT AUTO_INCREMENT PRIMARY KEY,
+    id VARCHAR(36) NOT NULL UNIQUE,
+    actor_id VARCHAR(36) NOT NULL DEFAULT '',
+    impersonator_id VARCHAR(36) NOT NULL DEFAULT '',
+    subject_id VARCHAR(36) NOT NULL DEFAULT '',
+    action VARCHAR(100) NOT NULL,
+    result VARCHAR(20) NOT NULL,
+    error TEXT,
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent VARCHAR(512) NOT NULL DEFAULT '',
+    trace_id VARCHAR(64) NOT NULL DEFAULT '',
+    changes JSON NULL,
+    prev_hash CHAR(64) NOT NULL,
+    hash CHAR(64) NOT NULL,
+    occurred_at TIMESTAMP(6) NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE INDEX idx_audit_logs_actor ON audit_logs(actor_id, occurred_at);
+CREATE INDEX idx_audit_logs_subject ON audit_logs(subject_id, occurred_at);
+CREATE INDEX idx_audit_logs_action ON audit_logs(action, occurred_at);
+CREATE INDEX idx_audit_logs_occurred_at ON audit_logs(occurred_at);
+
+CREATE TRIGGER audit_logs_no_update BEFORE UPDATE ON audit_logs
+FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_logs is append-only';
@@ -0,0 +1,3 @@
+ALTER TABLE events DROP COLUMN encrypted;
+DROP TABLE IF EXISTS data_exports;
+DROP TABLE IF EXISTS encryption_keys;
@@ -0,0 +1,22 @@
+CREATE TABLE encryption_keys (
+    subject_id VARCHAR(36) PRIMARY KEY,
+    wrapped_key VARBINARY(128) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE data_exports (
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    requested_by VARCHAR(36) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    archive LONGBLOB NULL,
+    error TEXT,
+    requested_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP NULL,
+    expires_at TIMESTAMP NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE INDEX idx_data_exports_user ON data_exports(user_id);
+CREATE INDEX idx_data_exports_status ON data_exports(status, requested_at);
+
+ALTER TABLE events ADD COLUMN encrypted TINYINT(1) NOT NULL DEFAULT 0 AFTER data;
@@ -0,0 +1,6 @@
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMP NULL AFTER updated_at;
+
+CREATE INDEX idx_users_deleted_at ON users(deleted_at);
+
+-- 此前已是删除状态的用户以最后更新时间作为删除时间，宽限期从该时间起算
+UPDATE users SET deleted_at = updated_at WHERE status = 'deleted' AND deleted_at IS NULL;
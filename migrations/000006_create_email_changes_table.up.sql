@@ -0,0 +1,13 @@
+CREATE TABLE email_changes (
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    old_email VARCHAR(255) NOT NULL,
+    new_email VARCHAR(255) NOT NULL,
+    confirm_token_hash CHAR(64) NOT NULL,
+    cancel_token_hash CHAR(64) NOT NULL,
+    requested_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    UNIQUE KEY uk_email_changes_user (user_id),
+    UNIQUE KEY uk_email_changes_confirm (confirm_token_hash),
+    UNIQUE KEY uk_email_changes_cancel (cancel_token_hash)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
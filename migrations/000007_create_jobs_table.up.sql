@@ -0,0 +1,20 @@
+CREATE TABLE jobs (
+    id VARCHAR(36) PRIMARY KEY,
+    command_type VARCHAR(255) NOT NULL,
+    payload JSON NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    progress TINYINT UNSIGNED NOT NULL DEFAULT 0,
+    result JSON NULL,
+    error TEXT,
+    requested_by VARCHAR(36) NOT NULL,
+    impersonator_id VARCHAR(36) NULL,
+    cancel_requested TINYINT(1) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP NULL,
+    finished_at TIMESTAMP NULL,
+    heartbeat_at TIMESTAMP NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE INDEX idx_jobs_status ON jobs(status, created_at);
+CREATE INDEX idx_jobs_heartbeat ON jobs(status, heartbeat_at);
+CREATE INDEX idx_jobs_finished ON jobs(finished_at);
@@ -0,0 +1,8 @@
+CREATE TABLE processed_events (
+    handler_id VARCHAR(100) NOT NULL,
+    event_id VARCHAR(36) NOT NULL,
+    processed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (handler_id, event_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE INDEX idx_processed_events_processed_at ON processed_events(processed_at);
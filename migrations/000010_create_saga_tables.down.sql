@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS email_verifications;
+DROP TABLE IF EXISTS saga_instances;
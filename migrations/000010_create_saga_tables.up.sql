@@ -0,0 +1,27 @@
+CREATE TABLE saga_instances (
+    id VARCHAR(36) PRIMARY KEY,
+    type VARCHAR(100) NOT NULL,
+    correlation_id VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    step INT NOT NULL DEFAULT 0,
+    data JSON NOT NULL,
+    deadline_at TIMESTAMP NULL,
+    error TEXT,
+    version INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_saga_instances_correlation (type, correlation_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE INDEX idx_saga_instances_deadline ON saga_instances(status, deadline_at);
+
+CREATE TABLE email_verifications (
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    UNIQUE KEY uk_email_verifications_user (user_id),
+    UNIQUE KEY uk_email_verifications_token (token_hash)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS leader_leases;
+DROP TABLE IF EXISTS scheduled_commands;
@@ -0,0 +1,25 @@
+CREATE TABLE scheduled_commands (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(100) NULL,
+    command_type VARCHAR(255) NOT NULL,
+    payload JSON NOT NULL,
+    cron VARCHAR(100) NULL,
+    next_run_at TIMESTAMP(3) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    runs INT NOT NULL DEFAULT 0,
+    last_run_at TIMESTAMP(3) NULL,
+    last_error TEXT,
+    requested_by VARCHAR(36) NOT NULL,
+    impersonator_id VARCHAR(36) NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_scheduled_commands_name (name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE INDEX idx_scheduled_commands_due ON scheduled_commands(status, next_run_at);
+
+CREATE TABLE leader_leases (
+    name VARCHAR(100) PRIMARY KEY,
+    holder VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP(3) NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,11 @@
+ALTER TABLE users
+    DROP COLUMN version;
+
+DROP INDEX idx_events_correlation ON events;
+
+ALTER TABLE events
+    DROP COLUMN causation_id,
+    DROP COLUMN correlation_id,
+    DROP COLUMN impersonator_id,
+    DROP COLUMN actor_id,
+    DROP COLUMN schema_version;
@@ -0,0 +1,14 @@
+ALTER TABLE events
+    ADD COLUMN schema_version INT NOT NULL DEFAULT 1 AFTER version,
+    ADD COLUMN actor_id VARCHAR(36) NULL AFTER occurred_at,
+    ADD COLUMN impersonator_id VARCHAR(36) NULL AFTER actor_id,
+    ADD COLUMN correlation_id VARCHAR(64) NULL AFTER impersonator_id,
+    ADD COLUMN causation_id VARCHAR(64) NULL AFTER correlation_id;
+
+CREATE INDEX idx_events_correlation ON events(correlation_id);
+
+ALTER TABLE users
+    ADD COLUMN version INT NOT NULL DEFAULT 0;
+
+UPDATE users u
+SET version = (SELECT COALESCE(MAX(e.version), 0) FROM events e WHERE e.aggregate_id = u.id);
@@ -0,0 +1,13 @@
+CREATE TABLE user_roles (
+    user_id VARCHAR(36) NOT NULL,
+    role VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, role),
+    CONSTRAINT fk_user_roles_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE INDEX idx_user_roles_role ON user_roles(role);
+
+-- 已有用户此前只在内存中持有默认角色
+INSERT INTO user_roles (user_id, role)
+SELECT id, 'user' FROM users;
@@ -0,0 +1,11 @@
+DROP TRIGGER audit_logs_no_update;
+
+CREATE TRIGGER audit_logs_no_update BEFORE UPDATE ON audit_logs
+FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_logs is append-only';
+
+DROP INDEX idx_webhook_deliveries_aggregate ON webhook_deliveries;
+ALTER TABLE webhook_deliveries DROP COLUMN aggregate_id;
+
+ALTER TABLE audit_logs DROP COLUMN changes_hash;
+
+ALTER TABLE events DROP COLUMN redacted;
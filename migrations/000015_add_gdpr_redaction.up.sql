@@ -0,0 +1,50 @@
+ALTER TABLE events ADD COLUMN redacted TINYINT(1) NOT NULL DEFAULT 0 AFTER encrypted;
+
+ALTER TABLE audit_logs ADD COLUMN changes_hash CHAR(64) NOT NULL DEFAULT '' AFTER changes;
+
+ALTER TABLE webhook_deliveries
+    ADD COLUMN aggregate_id VARCHAR(36) GENERATED ALWAYS AS (payload->>'$.aggregate_id') STORED AFTER event_type;
+
+CREATE INDEX idx_webhook_deliveries_aggregate ON webhook_deliveries(aggregate_id);
+
+-- 审计记录只允许擦除 changes，其余列（包括 changes_hash）不可修改
+DROP TRIGGER audit_logs_no_update;
+
+CREATE TRIGGER audit_logs_no_update BEFORE UPDATE ON audit_logs
+FOR EACH ROW
+BEGIN
+    IF NEW.changes IS NOT NULL
+        OR NOT (NEW.seq <=> OLD.seq
+            AND NEW.id <=> OLD.id
+            AND NEW.actor_id <=> OLD.actor_id
+            AND NEW.impersonator_id <=> OLD.impersonator_id
+            AND NEW.subject_id <=> OLD.subject_id
+            AND NEW.action <=> OLD.action
+            AND NEW.result <=> OLD.result
+            AND NEW.error <=> OLD.error
+            AND NEW.ip <=> OLD.ip
+            AND NEW.user_agent <=> OLD.user_agent
+            AND NEW.trace_id <=> OLD.trace_id
+            AND NEW.changes_hash <=> OLD.changes_hash
+            AND NEW.prev_hash <=> OLD.prev_hash
+            AND NEW.hash <=> OLD.hash
+            AND NEW.occurred_at <=> OLD.occurred_at)
+    THEN
+        SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_logs is append-only';
+    END IF;
+END;
+
+-- 回填：已擦除或已永久删除的用户在启用加密前写入的明文事件和 webhook 数据
+UPDATE events e
+JOIN (
+    SELECT DISTINCT aggregate_id FROM events WHERE type IN ('user.erased', 'user.purged')
+) erased ON erased.aggregate_id = e.aggregate_id
+SET e.data = JSON_OBJECT(), e.redacted = 1
+WHERE e.encrypted = 0 AND e.type NOT IN ('user.erased', 'user.purged');
+
+UPDATE webhook_deliveries d
+JOIN (
+    SELECT DISTINCT aggregate_id FROM events WHERE type IN ('user.erased', 'user.purged')
+) erased ON erased.aggregate_id = d.aggregate_id
+SET d.payload = JSON_SET(d.payload, '$.data', NULL)
+WHERE d.event_type NOT IN ('user.erased', 'user.purged');
@@ -0,0 +1,16 @@
+-- 哈希链锚点：链尾为最新一条记录，清理锚点为保留期清理删除的最后一条记录
+CREATE TABLE audit_chain (
+    id TINYINT PRIMARY KEY,
+    head_seq BIGINT NOT NULL,
+    head_hash CHAR(64) NOT NULL,
+    purged_seq BIGINT NOT NULL,
+    purged_hash CHAR(64) NOT NULL,
+    updated_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+INSERT INTO audit_chain (id, head_seq, head_hash, purged_seq, purged_hash)
+SELECT 1,
+    COALESCE((SELECT seq FROM audit_logs ORDER BY seq DESC LIMIT 1), 0),
+    COALESCE((SELECT hash FROM audit_logs ORDER BY seq DESC LIMIT 1), REPEAT('0', 64)),
+    COALESCE((SELECT seq - 1 FROM audit_logs ORDER BY seq ASC LIMIT 1), 0),
+    COALESCE((SELECT prev_hash FROM audit_logs ORDER BY seq ASC LIMIT 1), REPEAT('0', 64));
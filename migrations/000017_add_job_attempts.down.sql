@@ -0,0 +1,4 @@
+ALTER TABLE jobs
+    DROP COLUMN attempts,
+    DROP COLUMN ip,
+    DROP COLUMN user_agent;
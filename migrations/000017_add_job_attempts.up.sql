@@ -0,0 +1,4 @@
+ALTER TABLE jobs
+    ADD COLUMN attempts INT UNSIGNED NOT NULL DEFAULT 0 AFTER cancel_requested,
+    ADD COLUMN ip VARCHAR(45) NOT NULL DEFAULT '' AFTER impersonator_id,
+    ADD COLUMN user_agent VARCHAR(512) NOT NULL DEFAULT '' AFTER ip;
@@ -0,0 +1,2 @@
+CREATE INDEX idx_saga_instances_deadline ON saga_instances(status, deadline_at);
+ALTER TABLE saga_instances DROP COLUMN timeout_schedule_id;
@@ -0,0 +1,2 @@
+ALTER TABLE saga_instances ADD COLUMN timeout_schedule_id VARCHAR(36) NOT NULL DEFAULT '' AFTER deadline_at;
+DROP INDEX idx_saga_instances_deadline ON saga_instances;
@@ -0,0 +1,2 @@
+ALTER TABLE users ADD COLUMN erased_at TIMESTAMP NULL AFTER locked_until;
+UPDATE users SET erased_at = updated_at WHERE email LIKE 'erased+%@erased.invalid';
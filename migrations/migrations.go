@@ -0,0 +1,9 @@
+// Package migrations 内嵌数据库迁移脚本，供服务启动和 cmd/migrate 共用
+package migrations
+
+import "embed"
+
+// FS 按 golang-migrate 命名规则组织的迁移脚本
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,54 @@
+package actor
+
+import "context"
+
+// Actor 描述当前请求的发起者
+type Actor struct {
+	// UserID 令牌所代表的用户（被模拟时为被模拟用户）
+	UserID string
+	// ImpersonatorID 实际操作人，仅在模拟会话中非空
+	ImpersonatorID string
+	IP             string
+	UserAgent      string
+	TraceID        string
+}
+
+// IsImpersonated 是否处于模拟会话
+func (a Actor) IsImpersonated() bool {
+	return a.ImpersonatorID != ""
+}
+
+// RealActorID 返回实际执行操作的用户 ID
+func (a Actor) RealActorID() string {
+	if a.IsImpersonated() {
+		return a.ImpersonatorID
+	}
+	return a.UserID
+}
+
+type actorKey struct{}
+
+// WithActor 将发起者写入上下文
+func WithActor(ctx context.Context, a Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, a)
+}
+
+// FromContext 从上下文读取发起者
+func FromContext(ctx context.Context) (Actor, bool) {
+	a, ok := ctx.Value(actorKey{}).(Actor)
+	return a, ok
+}
+
+// IsImpersonated 上下文中的请求是否来自模拟会话
+func IsImpersonated(ctx context.Context) bool {
+	a, ok := FromContext(ctx)
+	return ok && a.IsImpersonated()
+}
+
+// SystemUserID 后台任务发起操作时使用的发起者 ID
+const SystemUserID = "system"
+
+// WithSystem 将系统作为发起者写入上下文
+func WithSystem(ctx context.Context) context.Context {
+	return WithActor(ctx, Actor{UserID: SystemUserID})
+}
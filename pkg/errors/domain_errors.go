@@ -10,4 +10,239 @@ var (
 		Code:    ErrCodeValidation,
 		Message: "invalid password format",
 	}
-) 
\ No newline at end of file
+
+	ErrEmptyEmail = &AppError{
+		Code:    ErrCodeValidation,
+		Message: "email is required",
+	}
+
+	ErrInvalidProfile = &AppError{
+		Code:    ErrCodeValidation,
+		Message: "invalid user profile",
+	}
+
+	ErrInvalidRole = &AppError{
+		Code:    ErrCodeValidation,
+		Message: "invalid role",
+	}
+
+	ErrRoleAlreadyAssigned = &AppError{
+		Code:    ErrCodeConflict,
+		Message: "role is already assigned to the user",
+	}
+
+	ErrCannotRevokeLastRole = &AppError{
+		Code:    ErrCodeConflict,
+		Message: "cannot revoke the last role of a user",
+	}
+
+	ErrRoleNotFound = &AppError{
+		Code:    ErrCodeNotFound,
+		Message: "role not found",
+	}
+
+	ErrInsufficientPermissions = &AppError{
+		Code:    ErrCodeForbidden,
+		Message: "insufficient permissions",
+	}
+
+	ErrInactiveUser = &AppError{
+		Code:    ErrCodeForbidden,
+		Message: "user is not active",
+	}
+
+	ErrInvalidCredentials = &AppError{
+		Code:    ErrCodeUnauthorized,
+		Message: "invalid email or password",
+	}
+
+	ErrAccountLocked = &AppError{
+		Code:    ErrCodeForbidden,
+		Message: "account is locked",
+	}
+
+	ErrInvalidToken = &AppError{
+		Code:    ErrCodeUnauthorized,
+		Message: "invalid token",
+	}
+
+	ErrTokenRevoked = &AppError{
+		Code:    ErrCodeUnauthorized,
+		Message: "token has been revoked",
+	}
+
+	ErrTokenRevocationUnavailable = &AppError{
+		Code:    ErrCodeUnavailable,
+		Message: "token revocation status unavailable",
+	}
+
+	ErrCannotImpersonateSelf = &AppError{
+		Code:    ErrCodeValidation,
+		Message: "cannot impersonate yourself",
+	}
+
+	ErrCannotImpersonateAdmin = &AppError{
+		Code:    ErrCodeForbidden,
+		Message: "administrators cannot be impersonated",
+	}
+
+	ErrImpersonationForbidden = &AppError{
+		Code:    ErrCodeForbidden,
+		Message: "action not allowed while impersonating",
+	}
+
+	ErrNotImpersonating = &AppError{
+		Code:    ErrCodeValidation,
+		Message: "current session is not an impersonation session",
+	}
+
+	ErrImpersonationDisabled = &AppError{
+		Code:    ErrCodeForbidden,
+		Message: "impersonation is disabled",
+	}
+
+	ErrImpersonationTokenMismatch = &AppError{
+		Code:    ErrCodeForbidden,
+		Message: "impersonation token does not belong to the caller",
+	}
+
+	ErrUserAlreadyErased = &AppError{
+		Code:    ErrCodeConflict,
+		Message: "user has already been erased",
+	}
+
+	ErrPermissionDenied = &AppError{
+		Code:    ErrCodeForbidden,
+		Message: "permission denied",
+	}
+
+	ErrInvalidUserStatus = &AppError{
+		Code:    ErrCodeValidation,
+		Message: "invalid user status",
+	}
+
+	ErrInvalidStatusTransition = &AppError{
+		Code:    ErrCodeConflict,
+		Message: "invalid user status transition",
+	}
+
+	ErrUserNotDeleted = &AppError{
+		Code:    ErrCodeConflict,
+		Message: "user is not deleted",
+	}
+
+	ErrCannotRestoreErasedUser = &AppError{
+		Code:    ErrCodeConflict,
+		Message: "erased users cannot be restored",
+	}
+
+	ErrDeletionGracePeriodActive = &AppError{
+		Code:    ErrCodeConflict,
+		Message: "user is still within the deletion grace period",
+	}
+
+	ErrEmailAlreadyExists = &AppError{
+		Code:    ErrCodeConflict,
+		Message: "email already exists",
+	}
+
+	ErrEmailUnchanged = &AppError{
+		Code:    ErrCodeValidation,
+		Message: "new email is the same as the current email",
+	}
+
+	ErrEmailChangeNotFound = &AppError{
+		Code:    ErrCodeNotFound,
+		Message: "email change request not found",
+	}
+
+	ErrEmailChangeExpired = &AppError{
+		Code:    ErrCodeValidation,
+		Message: "email change request has expired",
+	}
+
+	ErrEmailChangeStale = &AppError{
+		Code:    ErrCodeConflict,
+		Message: "email address has changed since the change was requested",
+	}
+
+	ErrEmailVerificationNotFound = &AppError{
+		Code:    ErrCodeNotFound,
+		Message: "email verification not found",
+	}
+
+	ErrEmailVerificationExpired = &AppError{
+		Code:    ErrCodeValidation,
+		Message: "email verification has expired",
+	}
+
+	ErrEmailVerificationStale = &AppError{
+		Code:    ErrCodeConflict,
+		Message: "email address has changed since the verification was sent",
+	}
+
+	ErrSagaAlreadyStarted = &AppError{
+		Code:    ErrCodeConflict,
+		Message: "saga instance already exists for this correlation id",
+	}
+
+	ErrJobNotCancellable = &AppError{
+		Code:    ErrCodeConflict,
+		Message: "job has already finished and cannot be cancelled",
+	}
+
+	ErrScheduleNotCancellable = &AppError{
+		Code:    ErrCodeConflict,
+		Message: "scheduled command is no longer active and cannot be cancelled",
+	}
+
+	ErrIdempotencyKeyReused = &AppError{
+		Code:    ErrCodeUnprocessable,
+		Message: "idempotency key was already used with a different request",
+	}
+
+	ErrIdempotencyRequestInProgress = &AppError{
+		Code:    ErrCodeConflict,
+		Message: "a request with the same idempotency key is still in progress",
+	}
+
+	ErrConcurrencyConflict = &AppError{
+		Code:    ErrCodeConflict,
+		Message: "aggregate was modified concurrently",
+	}
+
+	ErrCommandTimeout = &AppError{
+		Code:    ErrCodeUnavailable,
+		Message: "command timed out",
+	}
+
+	ErrCommandConcurrencyLimit = &AppError{
+		Code:    ErrCodeUnavailable,
+		Message: "too many concurrent requests for this command",
+	}
+
+	ErrCircuitOpen = &AppError{
+		Code:    ErrCodeUnavailable,
+		Message: "dependency temporarily unavailable",
+	}
+
+	ErrEventBusClosed = &AppError{
+		Code:    ErrCodeUnavailable,
+		Message: "event bus is closed",
+	}
+
+	ErrEventQueueFull = &AppError{
+		Code:    ErrCodeUnavailable,
+		Message: "event handler queue is full",
+	}
+
+	ErrAsyncDispatchDisabled = &AppError{
+		Code:    ErrCodeInternal,
+		Message: "async command dispatch is not configured",
+	}
+
+	ErrSchedulingDisabled = &AppError{
+		Code:    ErrCodeInternal,
+		Message: "command scheduling is not configured",
+	}
+)
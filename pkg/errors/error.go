@@ -1,6 +1,7 @@
 package errors
 
 import (
+	stderrors "errors"
 	"fmt"
 	"net/http"
 )
@@ -14,6 +15,8 @@ const (
 	ErrCodeConflict     ErrorCode = "CONFLICT"
 	ErrCodeUnauthorized ErrorCode = "UNAUTHORIZED"
 	ErrCodeForbidden    ErrorCode = "FORBIDDEN"
+	ErrCodeUnprocessable ErrorCode = "UNPROCESSABLE"
+	ErrCodeUnavailable  ErrorCode = "UNAVAILABLE"
 	ErrCodeInternal     ErrorCode = "INTERNAL_ERROR"
 )
 
@@ -67,6 +70,10 @@ func (e *AppError) HTTPStatusCode() int {
 		return http.StatusUnauthorized
 	case ErrCodeForbidden:
 		return http.StatusForbidden
+	case ErrCodeUnprocessable:
+		return http.StatusUnprocessableEntity
+	case ErrCodeUnavailable:
+		return http.StatusServiceUnavailable
 	default:
 		return http.StatusInternalServerError
 	}
@@ -84,6 +91,16 @@ func (e *AppError) WithDetails(details map[string]interface{}) *AppError {
 	return e
 }
 
+// Is 同标准库 errors.Is
+func Is(err, target error) bool {
+	return stderrors.Is(err, target)
+}
+
+// As 同标准库 errors.As
+func As(err error, target interface{}) bool {
+	return stderrors.As(err, target)
+}
+
 func IsAppError(err error) bool {
 	_, ok := err.(*AppError)
 	return ok
@@ -91,7 +108,7 @@ func IsAppError(err error) bool {
 
 func GetErrorCode(err error) string {
 	if appErr, ok := err.(*AppError); ok {
-		return appErr.Code
+		return string(appErr.Code)
 	}
-	return ErrCodeInternal
+	return string(ErrCodeInternal)
 } 
\ No newline at end of file
@@ -35,9 +35,4 @@ const (
     ErrCodeMissingField     = "MISSING_FIELD"
     ErrCodeInvalidValue     = "INVALID_VALUE"
     ErrCodeDuplicateValue   = "DUPLICATE_VALUE"
-    ErrCodeValidation   ErrorCode = "GOHEX_VALIDATION_ERROR"
-    ErrCodeNotFound     ErrorCode = "GOHEX_NOT_FOUND"
-    ErrCodeConflict     ErrorCode = "GOHEX_CONFLICT"
-    ErrCodeUnauthorized ErrorCode = "GOHEX_UNAUTHORIZED"
-    ErrCodeInternal     ErrorCode = "GOHEX_INTERNAL_ERROR"
 ) 
\ No newline at end of file
@@ -0,0 +1,52 @@
+package errors
+
+import (
+	"context"
+	"database/sql/driver"
+	"net"
+	"strings"
+)
+
+// Transient 错误可实现该接口声明自身是否可以重试
+type Transient interface {
+	Transient() bool
+}
+
+// transientMessages MySQL 死锁（1213）和锁等待超时（1205）
+var transientMessages = []string{
+	"Error 1213",
+	"Error 1205",
+	"Deadlock found",
+	"Lock wait timeout exceeded",
+}
+
+// IsTransient 判断错误是否为可重试的瞬时错误：并发冲突、数据库死锁和网络错误
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	if Is(err, context.DeadlineExceeded) || Is(err, context.Canceled) {
+		return false
+	}
+	if Is(err, ErrConcurrencyConflict) || Is(err, driver.ErrBadConn) {
+		return true
+	}
+
+	var t Transient
+	if As(err, &t) {
+		return t.Transient()
+	}
+
+	var netErr net.Error
+	if As(err, &netErr) {
+		return true
+	}
+
+	msg := err.Error()
+	for _, m := range transientMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+	return false
+}
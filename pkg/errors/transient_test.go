@@ -0,0 +1,43 @@
+package errors
+
+import (
+	"context"
+	"database/sql/driver"
+	stderrors "errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type retryableError struct{ retry bool }
+
+func (e retryableError) Error() string   { return "retryable" }
+func (e retryableError) Transient() bool { return e.retry }
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "concurrency conflict", err: fmt.Errorf("save: %w", ErrConcurrencyConflict), want: true},
+		{name: "bad connection", err: driver.ErrBadConn, want: true},
+		{name: "deadlock", err: stderrors.New("Error 1213 (40001): Deadlock found when trying to get lock"), want: true},
+		{name: "lock wait timeout", err: stderrors.New("Error 1205 (HY000): Lock wait timeout exceeded"), want: true},
+		{name: "network error", err: &net.OpError{Op: "dial", Err: stderrors.New("connection refused")}, want: true},
+		{name: "declares transient", err: retryableError{retry: true}, want: true},
+		{name: "declares permanent", err: retryableError{retry: false}, want: false},
+		{name: "deadline exceeded", err: context.DeadlineExceeded, want: false},
+		{name: "canceled", err: fmt.Errorf("query: %w", context.Canceled), want: false},
+		{name: "domain error", err: ErrUserNotFound, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsTransient(tt.err))
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package tracer
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName 全局 TracerProvider 下的追踪器名称
+const instrumentationName = "github.com/gohex/gohex"
+
+// Span 追踪片段
+type Span = trace.Span
+
+// StartSpan 基于全局 TracerProvider 创建子 span，未初始化时为 no-op
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (Span, context.Context) {
+	ctx, span := otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+	return span, ctx
+}
+
+// Tag 构造字符串属性
+func Tag(key, value string) attribute.KeyValue {
+	return attribute.String(key, value)
+}
+
+// IDs 返回上下文中 span 的 trace ID 和 span ID，没有有效 span 时返回空字符串
+func IDs(ctx context.Context) (traceID, spanID string) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return "", ""
+	}
+	return sc.TraceID().String(), sc.SpanID().String()
+}
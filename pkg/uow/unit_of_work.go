@@ -3,19 +3,23 @@ package uow
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	"github.com/gohex/gohex/internal/application/port"
-	"github.com/gohex/gohex/pkg/errors"
+
+	"github.com/gohex/gohex/internal/application/port/output"
 )
 
+// ErrNoActiveTransaction 上下文中没有事务时提交或回滚返回该错误
+var ErrNoActiveTransaction = errors.New("no active transaction")
+
+// UnitOfWork 在所有请求间共享，不保存事务状态；事务只通过上下文传递
 type UnitOfWork struct {
 	db      *sql.DB
-	tx      *sql.Tx
-	logger  Logger
-	metrics MetricsReporter
+	logger  output.Logger
+	metrics output.MetricsReporter
 }
 
-func NewUnitOfWork(db *sql.DB, logger Logger, metrics MetricsReporter) *UnitOfWork {
+func NewUnitOfWork(db *sql.DB, logger output.Logger, metrics output.MetricsReporter) *UnitOfWork {
 	return &UnitOfWork{
 		db:      db,
 		logger:  logger,
@@ -35,7 +39,6 @@ func (u *UnitOfWork) Begin(ctx context.Context) (context.Context, error) {
 		return ctx, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 
-	u.tx = tx
 	ctx = context.WithValue(ctx, txKey{}, tx)
 	u.metrics.IncrementCounter("uow_begin_success")
 	return ctx, nil
@@ -45,16 +48,16 @@ func (u *UnitOfWork) Commit(ctx context.Context) error {
 	timer := u.metrics.StartTimer("uow_commit_duration")
 	defer timer.Stop()
 
-	if u.tx == nil {
-		return fmt.Errorf("no active transaction")
+	tx, ok := FromContext(ctx)
+	if !ok {
+		return ErrNoActiveTransaction
 	}
 
-	if err := u.tx.Commit(); err != nil {
+	if err := tx.Commit(); err != nil {
 		u.metrics.IncrementCounter("uow_commit_error")
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	u.tx = nil
 	u.metrics.IncrementCounter("uow_commit_success")
 	return nil
 }
@@ -63,16 +66,16 @@ func (u *UnitOfWork) Rollback(ctx context.Context) error {
 	timer := u.metrics.StartTimer("uow_rollback_duration")
 	defer timer.Stop()
 
-	if u.tx == nil {
-		return nil
+	tx, ok := FromContext(ctx)
+	if !ok {
+		return ErrNoActiveTransaction
 	}
 
-	if err := u.tx.Rollback(); err != nil {
+	if err := tx.Rollback(); err != nil {
 		u.metrics.IncrementCounter("uow_rollback_error")
 		return fmt.Errorf("failed to rollback transaction: %w", err)
 	}
 
-	u.tx = nil
 	u.metrics.IncrementCounter("uow_rollback_success")
 	return nil
 }
@@ -82,17 +85,26 @@ type txKey struct{}
 
 func FromContext(ctx context.Context) (*sql.Tx, bool) {
 	tx, ok := ctx.Value(txKey{}).(*sql.Tx)
-	return tx, ok
+	return tx, ok && tx != nil
 }
 
 func WithTransaction(ctx context.Context, tx *sql.Tx) context.Context {
 	return context.WithValue(ctx, txKey{}, tx)
 }
 
+// WithoutTransaction 返回脱离当前事务的上下文，保留其他值；
+// 事件处理器等需要独立提交或失败的逻辑使用，避免加入调用方的事务
+func WithoutTransaction(ctx context.Context) context.Context {
+	if _, ok := FromContext(ctx); !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, txKey{}, (*sql.Tx)(nil))
+}
+
 // 添加事务包装方法
 func (u *UnitOfWork) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
 	// 如果已经在事务中，直接执行
-	if tx, ok := FromContext(ctx); ok {
+	if _, ok := FromContext(ctx); ok {
 		return fn(ctx)
 	}
 
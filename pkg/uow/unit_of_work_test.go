@@ -0,0 +1,43 @@
+package uow
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gohex/gohex/internal/testutil"
+)
+
+func TestWithoutTransaction(t *testing.T) {
+	tx := &sql.Tx{}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+	}{
+		{"no transaction", context.Background()},
+		{"active transaction", WithTransaction(context.Background(), tx)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := WithoutTransaction(tt.ctx)
+
+			_, ok := FromContext(ctx)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestUnitOfWork_RequiresTransactionInContext(t *testing.T) {
+	u := NewUnitOfWork(nil, testutil.NopLogger{}, testutil.NewMetrics())
+	detached := WithoutTransaction(WithTransaction(context.Background(), &sql.Tx{}))
+
+	// 没有事务或已脱离事务的上下文不能提交或回滚其他请求的事务
+	for _, ctx := range []context.Context{context.Background(), detached} {
+		assert.ErrorIs(t, u.Commit(ctx), ErrNoActiveTransaction)
+		assert.ErrorIs(t, u.Rollback(ctx), ErrNoActiveTransaction)
+	}
+}
@@ -0,0 +1,76 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/command"
+	"github.com/gohex/gohex/internal/infrastructure/adapter/secondary/cache/memory"
+	"github.com/gohex/gohex/internal/infrastructure/adapter/secondary/persistence/mysql"
+	"github.com/gohex/gohex/internal/testutil"
+	"github.com/gohex/gohex/pkg/uow"
+)
+
+func TestEraseUser_RollsBackAsAWhole(t *testing.T) {
+	// 仓储和工作单元使用不同的模拟连接：绕过事务的语句会落到没有期望的 repoMock 上
+	repoDB, repoMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer repoDB.Close()
+	txDB, txMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer txDB.Close()
+
+	logger, metrics := testutil.NopLogger{}, testutil.NewMetrics()
+	keys := testutil.NewKeyStore()
+	_, err = keys.GetOrCreateKey(context.Background(), "alice")
+	require.NoError(t, err)
+	events := testutil.NewEventStore()
+
+	h := command.NewEraseUserHandler(
+		mysql.NewUserRepository(repoDB, logger, metrics),
+		events,
+		&testutil.EventBus{},
+		keys,
+		mysql.NewDataExportRepository(repoDB, logger, metrics),
+		mysql.NewEmailChangeRepository(repoDB, logger, metrics),
+		&testutil.AuditLog{},
+		testutil.NewWebhookRepository(),
+		memory.NewCache(time.Minute, logger, metrics),
+		uow.NewUnitOfWork(txDB, logger, metrics),
+		logger, metrics,
+	)
+
+	now := time.Now()
+	txMock.ExpectBegin()
+	txMock.ExpectQuery(regexp.QuoteMeta("FROM users WHERE id = ?")).WithArgs("alice").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "password", "name", "bio", "avatar", "status",
+			"created_at", "updated_at", "deleted_at", "locked_until", "erased_at", "version"}).
+			AddRow("alice", "alice@example.com", "hash", "Alice", "", "", "active", now, now, nil, nil, nil, 1))
+	txMock.ExpectQuery(regexp.QuoteMeta("FROM user_roles")).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "role"}).AddRow("alice", "user"))
+	txMock.ExpectExec(regexp.QuoteMeta("UPDATE users")).WillReturnResult(sqlmock.NewResult(0, 1))
+	txMock.ExpectExec(regexp.QuoteMeta("DELETE FROM user_roles")).WillReturnResult(sqlmock.NewResult(0, 0))
+	txMock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO user_roles")).WillReturnResult(sqlmock.NewResult(0, 0))
+	txMock.ExpectExec(regexp.QuoteMeta("DELETE FROM data_exports WHERE user_id = ?")).WithArgs("alice").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	txMock.ExpectExec(regexp.QuoteMeta("DELETE FROM email_changes WHERE user_id = ?")).WithArgs("alice").
+		WillReturnError(errors.New("connection reset"))
+	txMock.ExpectRollback()
+
+	_, err = h.Handle(context.Background(), &command.EraseUserCommand{UserID: "alice", RequestedBy: "admin"})
+	assert.EqualError(t, err, "connection reset")
+
+	// 匿名化的用户和已删除的导出随事务回滚，密钥未被粉碎
+	assert.NoError(t, txMock.ExpectationsWereMet())
+	assert.NoError(t, repoMock.ExpectationsWereMet())
+	key, err := keys.GetKey(context.Background(), "alice")
+	require.NoError(t, err)
+	assert.NotEmpty(t, key)
+}
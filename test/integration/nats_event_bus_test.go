@@ -0,0 +1,161 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/test"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/event"
+	natsbus "github.com/gohex/gohex/internal/infrastructure/adapter/secondary/message/nats"
+	"github.com/gohex/gohex/internal/infrastructure/config"
+	"github.com/gohex/gohex/internal/testutil"
+)
+
+// recordingHandler 记录收到的事件，前 failures 次处理返回错误
+type recordingHandler struct {
+	id       string
+	failures int
+
+	mu       sync.Mutex
+	received []event.Event
+}
+
+func (h *recordingHandler) HandlerID() string { return h.id }
+
+func (h *recordingHandler) Handle(ctx context.Context, evt event.Event) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.received = append(h.received, evt)
+	if len(h.received) <= h.failures {
+		return errors.New("handler failed")
+	}
+	return nil
+}
+
+func (h *recordingHandler) count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.received)
+}
+
+// startNATSEventBus 启动开启 JetStream 的内嵌服务器并返回已启动的事件总线
+func startNATSEventBus(t *testing.T, cfg config.NATSConfig) output.EventBus {
+	t.Helper()
+
+	opts := natsserver.DefaultTestOptions
+	opts.Port = -1
+	opts.JetStream = true
+	opts.StoreDir = t.TempDir()
+	server := natsserver.RunServer(&opts)
+	t.Cleanup(server.Shutdown)
+
+	conn, err := nats.Connect(server.ClientURL())
+	require.NoError(t, err)
+
+	bus, err := natsbus.NewEventBus(conn, cfg, testutil.NopLogger{}, testutil.NewMetrics())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = bus.Close() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	require.NoError(t, bus.(interface{ Start(context.Context) error }).Start(ctx))
+	return bus
+}
+
+func TestNATSEventBus_DeliversToEachSubscriber(t *testing.T) {
+	bus := startNATSEventBus(t, config.NATSConfig{})
+	audit := &recordingHandler{id: "audit"}
+	mailer := &recordingHandler{id: "mailer"}
+	other := &recordingHandler{id: "other"}
+	bus.Subscribe(event.UserCreated, audit)
+	bus.Subscribe(event.UserCreated, mailer)
+	bus.Subscribe(event.UserDeleted, other)
+
+	require.NoError(t, bus.Publish(context.Background(),
+		event.NewUserCreatedEvent("alice", "alice@example.com", "Alice")))
+
+	for _, h := range []*recordingHandler{audit, mailer} {
+		require.Eventually(t, func() bool { return h.count() == 1 }, 5*time.Second, 10*time.Millisecond)
+		h.mu.Lock()
+		assert.Equal(t, event.UserCreated, h.received[0].Type())
+		assert.Equal(t, "alice", h.received[0].AggregateID())
+		h.mu.Unlock()
+	}
+	assert.Zero(t, other.count())
+}
+
+func TestNATSEventBus_Redelivery(t *testing.T) {
+	tests := []struct {
+		name      string
+		failures  int
+		wantCalls int
+	}{
+		{name: "redelivers until handled", failures: 2, wantCalls: 3},
+		{name: "terminates after max deliver", failures: 10, wantCalls: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := startNATSEventBus(t, config.NATSConfig{
+				AckWait:    time.Second,
+				MaxDeliver: 3,
+				NakDelay:   10 * time.Millisecond,
+			})
+			h := &recordingHandler{id: "flaky", failures: tt.failures}
+			bus.Subscribe(event.UserCreated, h)
+
+			require.NoError(t, bus.Publish(context.Background(),
+				event.NewUserCreatedEvent("alice", "alice@example.com", "Alice")))
+
+			require.Eventually(t, func() bool { return h.count() == tt.wantCalls }, 5*time.Second, 10*time.Millisecond)
+			// 确认或终止后不再投递
+			time.Sleep(200 * time.Millisecond)
+			assert.Equal(t, tt.wantCalls, h.count())
+		})
+	}
+}
+
+func TestNATSEventBus_DeduplicatesRepublishedEvent(t *testing.T) {
+	bus := startNATSEventBus(t, config.NATSConfig{})
+	h := &recordingHandler{id: "audit"}
+	bus.Subscribe(event.UserCreated, h)
+
+	evt := event.NewUserCreatedEvent("alice", "alice@example.com", "Alice")
+	require.NoError(t, bus.Publish(context.Background(), evt))
+	require.NoError(t, bus.Publish(context.Background(), evt))
+
+	require.Eventually(t, func() bool { return h.count() == 1 }, 5*time.Second, 10*time.Millisecond)
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, 1, h.count())
+}
+
+func TestNATSEventBus_ResumesDurableConsumerAfterUnsubscribe(t *testing.T) {
+	bus := startNATSEventBus(t, config.NATSConfig{})
+	h := &recordingHandler{id: "audit"}
+	bus.Subscribe(event.UserCreated, h)
+
+	require.NoError(t, bus.Publish(context.Background(),
+		event.NewUserCreatedEvent("alice", "alice@example.com", "Alice")))
+	require.Eventually(t, func() bool { return h.count() == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	bus.Unsubscribe(event.UserCreated, h)
+	require.NoError(t, bus.Publish(context.Background(),
+		event.NewUserCreatedEvent("bob", "bob@example.com", "Bob")))
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, 1, h.count())
+
+	// 重新订阅后从上次确认的位置继续，只收到离线期间的事件
+	bus.Subscribe(event.UserCreated, h)
+	require.Eventually(t, func() bool { return h.count() == 2 }, 5*time.Second, 10*time.Millisecond)
+	h.mu.Lock()
+	assert.Equal(t, "bob", h.received[1].AggregateID())
+	h.mu.Unlock()
+}
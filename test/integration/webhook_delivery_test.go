@@ -0,0 +1,106 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohex/gohex/internal/application/command"
+	"github.com/gohex/gohex/internal/application/port/output"
+	"github.com/gohex/gohex/internal/domain/event"
+	"github.com/gohex/gohex/internal/domain/vo"
+	"github.com/gohex/gohex/internal/infrastructure/adapter/secondary/cache/memory"
+	eventbus "github.com/gohex/gohex/internal/infrastructure/bus/event"
+	"github.com/gohex/gohex/internal/infrastructure/config"
+	eventhandler "github.com/gohex/gohex/internal/infrastructure/event/handler"
+	"github.com/gohex/gohex/internal/infrastructure/webhook"
+	"github.com/gohex/gohex/internal/testutil"
+	pkgerrors "github.com/gohex/gohex/pkg/errors"
+)
+
+// webhookReceiver 记录收到的事件类型
+type webhookReceiver struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	r.events = append(r.events, req.Header.Get(webhook.HeaderEventType))
+	r.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (r *webhookReceiver) received() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.events...)
+}
+
+// nopScheduler 丢弃计划的命令，测试不关心自动解锁
+type nopScheduler struct {
+	output.CommandScheduler
+}
+
+func (nopScheduler) Schedule(ctx context.Context, command interface{}, runAt time.Time) (string, error) {
+	return "unlock", nil
+}
+
+func TestWebhookDelivery_FromCommandToReceiver(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	receiver := &webhookReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	logger, metrics := testutil.NopLogger{}, testutil.NewMetrics()
+	webhooks := testutil.NewWebhookRepository(&output.WebhookSubscription{
+		ID:         "sub-1",
+		URL:        server.URL,
+		Secret:     "secret",
+		EventTypes: []string{event.UserProfileUpdated, event.UserLocked},
+		Active:     true,
+	})
+
+	bus := eventbus.NewEventBus(config.EventConfig{Enabled: true}, logger, metrics)
+	handler := eventhandler.NewWebhookHandler(webhooks, logger, metrics)
+	for _, eventType := range handler.EventTypes() {
+		bus.Subscribe(eventType, handler)
+	}
+
+	worker := webhook.NewDeliveryWorker(webhooks, config.WebhookConfig{
+		Enabled:      true,
+		Workers:      1,
+		PollInterval: 10 * time.Millisecond,
+		Timeout:      time.Second,
+	}, logger, metrics)
+	worker.Start(ctx)
+
+	users := testutil.NewUserRepository(testutil.NewUser("alice", vo.StatusActive, vo.RoleUser))
+	events := testutil.NewEventStore()
+	cache := memory.NewCache(time.Minute, logger, metrics)
+
+	update := command.NewUpdateUserProfileHandler(users, events, bus, cache, testutil.UnitOfWork{}, logger, metrics)
+	_, err := update.Handle(ctx, &command.UpdateUserProfileCommand{UserID: "alice", Name: "Alice"})
+	require.NoError(t, err)
+
+	// 连续 5 次密码错误锁定账户
+	login := command.NewLoginHandler(users, testutil.NewTokenService(), events, bus, testutil.UnitOfWork{}, cache,
+		nopScheduler{}, logger, metrics)
+	for i := 0; i < 5; i++ {
+		_, err := login.Handle(ctx, &command.LoginCommand{Email: "alice@example.com", Password: "wrong"})
+		require.ErrorIs(t, err, pkgerrors.ErrInvalidCredentials)
+	}
+
+	assert.Eventually(t, func() bool {
+		return len(receiver.received()) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.ElementsMatch(t, []string{event.UserProfileUpdated, event.UserLocked}, receiver.received())
+}